@@ -8,6 +8,11 @@ export const MOMOSHTREM_CONFIG = {
     return process.env.MOMOSHTREM_URL || 'http://localhost:4444';
   },
 
+  /** API key sent as X-API-Key when momoshtrem has server.api_auth enabled - read at runtime */
+  get apiKey(): string | undefined {
+    return process.env.MOMOSHTREM_API_KEY || undefined;
+  },
+
   /** Request timeout in milliseconds (longer for torrent metadata fetching) */
   timeout: 30000,
 } as const;
@@ -3,10 +3,15 @@ import { APIError, ValidationError } from '@/lib/errors';
 import type {
   LibraryMovie,
   LibraryShow,
+  LibraryListResponse,
   MovieAssignmentResponse,
   ShowAssignmentResponse,
   MomoshtremError,
   RecentlyAiredResponse,
+  PlaybackInfo,
+  ShowSettings,
+  ImportRequest,
+  ImportResponse,
 } from '@/types/momoshtrem';
 import type { TorrentStatus, TorrentListResponse } from '@/types/torrent';
 import type {
@@ -61,7 +66,7 @@ class MomoshtremClient {
   private async handleError(response: Response, operation: string): Promise<never> {
     const data = (await response.json().catch(() => ({}))) as MomoshtremError;
     throw new APIError(
-      data.error || `Failed to ${operation}: ${response.statusText}`,
+      data.error?.message || `Failed to ${operation}: ${response.statusText}`,
       response.status
     );
   }
@@ -82,6 +87,7 @@ class MomoshtremClient {
         method,
         headers: {
           'Content-Type': 'application/json',
+          ...(MOMOSHTREM_CONFIG.apiKey ? { 'X-API-Key': MOMOSHTREM_CONFIG.apiKey } : {}),
         },
         body: body ? JSON.stringify(body) : undefined,
       });
@@ -130,13 +136,13 @@ class MomoshtremClient {
    * Get all movies in the library.
    */
   async getMovies(): Promise<LibraryMovie[]> {
-    const result = await this.request<LibraryMovie[]>(
+    const result = await this.request<LibraryListResponse<LibraryMovie>>(
       'GET',
       '/api/movies',
       undefined,
       'get movies'
     );
-    return result || [];
+    return result?.items || [];
   }
 
   /**
@@ -192,6 +198,18 @@ class MomoshtremClient {
     );
   }
 
+  /**
+   * Get the active assignment's codecs and Apple TV compatibility.
+   */
+  async getMoviePlaybackInfo(id: number): Promise<PlaybackInfo> {
+    return this.request<PlaybackInfo>(
+      'GET',
+      `/api/movies/${id}/playback-info`,
+      undefined,
+      'get movie playback info'
+    );
+  }
+
   // ============================================================================
   // Shows API
   // ============================================================================
@@ -213,13 +231,13 @@ class MomoshtremClient {
    * Get all shows in the library.
    */
   async getShows(): Promise<LibraryShow[]> {
-    const result = await this.request<LibraryShow[]>(
+    const result = await this.request<LibraryListResponse<LibraryShow>>(
       'GET',
       '/api/shows',
       undefined,
       'get shows'
     );
-    return result || [];
+    return result?.items || [];
   }
 
   /**
@@ -264,6 +282,43 @@ class MomoshtremClient {
     );
   }
 
+  /**
+   * Get a show's preferred subtitle languages.
+   */
+  async getShowSettings(id: number): Promise<ShowSettings> {
+    return this.request<ShowSettings>(
+      'GET',
+      `/api/shows/${id}/settings`,
+      undefined,
+      'get show settings'
+    );
+  }
+
+  /**
+   * Replace a show's preferred subtitle languages.
+   */
+  async updateShowSettings(id: number, preferredLangs: string[]): Promise<ShowSettings> {
+    return this.request<ShowSettings>(
+      'PUT',
+      `/api/shows/${id}/settings`,
+      { preferred_subtitle_langs: preferredLangs },
+      'update show settings'
+    );
+  }
+
+  /**
+   * Add many movies and shows by TMDB ID. Reports each ID separately, so
+   * one bad ID doesn't fail the rest.
+   */
+  async importLibrary(request: ImportRequest): Promise<ImportResponse> {
+    return this.request<ImportResponse>(
+      'POST',
+      '/api/import',
+      request,
+      'import library'
+    );
+  }
+
   // ============================================================================
   // Library Lookup Methods
   // ============================================================================
@@ -472,6 +527,18 @@ class MomoshtremClient {
     );
   }
 
+  /**
+   * Get an episode's active assignment codecs and Apple TV compatibility.
+   */
+  async getEpisodePlaybackInfo(episodeId: number): Promise<PlaybackInfo> {
+    return this.request<PlaybackInfo>(
+      'GET',
+      `/api/episodes/${episodeId}/playback-info`,
+      undefined,
+      'get episode playback info'
+    );
+  }
+
   // ============================================================================
   // Subtitles API
   // ============================================================================
@@ -29,6 +29,13 @@ export interface LibraryShow {
   seasons?: LibrarySeason[];
 }
 
+/** Paged list envelope returned by GET /api/movies and /api/shows */
+export interface LibraryListResponse<T> {
+  items: T[];
+  /** Total items in the library, regardless of limit/offset */
+  total: number;
+}
+
 export interface LibrarySeason {
   id: number;
   season_number: number;
@@ -39,10 +46,18 @@ export interface LibraryEpisode {
   id: number;
   episode_number: number;
   name: string;
+  air_date?: string; // YYYY-MM-DD
   has_assignment: boolean;
   assignment?: TorrentAssignment;
 }
 
+/** GET/PUT /api/shows/:id/settings */
+export interface ShowSettings {
+  show_id: number;
+  /** Most preferred first; empty uses the server's subtitles config */
+  preferred_subtitle_langs: string[];
+}
+
 export interface TorrentAssignment {
   id: number;
   info_hash: string;
@@ -50,6 +65,25 @@ export interface TorrentAssignment {
   file_size: number;
   resolution?: string;
   source?: string;
+  codec?: string;
+  audio_codec?: string;
+  hdr?: boolean;
+  /** Set for files on the server's disk; info_hash is then empty */
+  local_path?: string;
+}
+
+export interface PlaybackInfo {
+  item_type: 'movie' | 'episode';
+  item_id: number;
+  assignment_id: number;
+  video_codec?: string;
+  audio_codec?: string;
+  resolution?: string;
+  bit_depth?: string;
+  hdr: boolean;
+  /** False when a codec is unsupported; null when a codec is missing from the release name */
+  apple_tv_compatible: boolean | null;
+  issues?: string[];
 }
 
 // =============================================================================
@@ -64,6 +98,11 @@ export interface AddShowRequest {
   tmdb_id: number;
 }
 
+export interface ImportRequest {
+  movie_tmdb_ids?: number[];
+  show_tmdb_ids?: number[];
+}
+
 export interface AssignTorrentRequest {
   magnet_uri: string;
 }
@@ -110,6 +149,9 @@ export interface EpisodeMatch {
   file_size: number;
   resolution?: string;
   confidence: 'high' | 'medium' | 'low';
+  pattern_used?: string;
+  season_from_folder: boolean;
+  needs_review: boolean;
 }
 
 export interface UnmatchedFile {
@@ -135,6 +177,23 @@ export interface AddTorrentResponse {
   unmatched?: UnmatchedFile[];
 }
 
+export interface ImportItemResult {
+  tmdb_id: number;
+  status: 'created' | 'existing' | 'failed' | 'not_attempted';
+  id?: number;
+  title?: string;
+  error?: string;
+}
+
+export interface ImportResponse {
+  movies: ImportItemResult[];
+  shows: ImportItemResult[];
+  created: number;
+  existing: number;
+  failed: number;
+  not_attempted: number;
+}
+
 // =============================================================================
 // Library Status Types (for UI)
 // =============================================================================
@@ -155,8 +214,10 @@ export interface LibraryItemStatus {
 // =============================================================================
 
 export interface MomoshtremError {
-  error: string;
-  code?: string;
+  error?: {
+    code: string;
+    message: string;
+  };
 }
 
 // =============================================================================
@@ -18,6 +18,7 @@ export interface TorrentStatus {
   download_speed: number; // bytes/sec
   upload_speed: number; // bytes/sec
   is_paused: boolean;
+  resolving?: boolean; // Metadata still arriving; name comes from the magnet
 }
 
 /**
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"database/sql"
+	_ "embed"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var sqliteSchema string
+
+// tables lists every library table in FK-dependency order. Columns are read
+// from PostgreSQL, so databases that predate later migrations still migrate.
+var tables = []string{
+	"movies",
+	"shows",
+	"seasons",
+	"episodes",
+	"torrent_assignments",
+	"subtitles",
+	"sync_metadata",
+	"item_metadata",
+	"collections",
+	"collection_items",
+	"watch_status",
+	"queued_torrents",
+	"show_settings",
+}
+
+// skippedTables are PostgreSQL tables that aren't library data
+var skippedTables = map[string]bool{
+	"schema_migrations": true,
+}
+
+func main() {
+	pgURL := flag.String("pg-url", "", "PostgreSQL connection URL")
+	sqlitePath := flag.String("sqlite-path", "", "Path to SQLite database file (created if missing)")
+	flag.Parse()
+
+	if *sqlitePath == "" || *pgURL == "" {
+		fmt.Fprintf(os.Stderr, "Usage: migrate-to-sqlite --pg-url postgres://... --sqlite-path /path/to/momoshtrem.db\n")
+		os.Exit(1)
+	}
+
+	// Open PostgreSQL
+	pgDB, err := sql.Open("pgx", *pgURL)
+	if err != nil {
+		log.Fatalf("Failed to open PostgreSQL: %v", err)
+	}
+	defer pgDB.Close()
+
+	if err := pgDB.Ping(); err != nil {
+		log.Fatalf("Failed to ping PostgreSQL: %v", err)
+	}
+	log.Println("Connected to PostgreSQL")
+
+	// Open SQLite
+	sqliteDB, err := sql.Open("sqlite", *sqlitePath)
+	if err != nil {
+		log.Fatalf("Failed to open SQLite: %v", err)
+	}
+	defer sqliteDB.Close()
+
+	if err := sqliteDB.Ping(); err != nil {
+		log.Fatalf("Failed to ping SQLite: %v", err)
+	}
+	log.Println("Connected to SQLite")
+
+	// Create schema
+	if _, err := sqliteDB.Exec(sqliteSchema); err != nil {
+		log.Fatalf("Failed to create SQLite schema: %v", err)
+	}
+	log.Println("Created SQLite schema")
+
+	// Refuse to drop data the SQLite schema has no place for
+	if err := checkUnknownTables(pgDB); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	// Start transaction
+	tx, err := sqliteDB.Begin()
+	if err != nil {
+		log.Fatalf("Failed to start transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	// Clear all target tables for idempotent re-runs (reverse FK order).
+	// SQLite has no TRUNCATE; an unqualified DELETE uses its truncate optimization.
+	for i := len(tables) - 1; i >= 0; i-- {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", tables[i])); err != nil {
+			log.Fatalf("Failed to clear %s: %v", tables[i], err)
+		}
+	}
+	log.Println("Cleared all target tables")
+
+	for _, table := range tables {
+		count, err := migrateTable(pgDB, tx, table)
+		if err != nil {
+			log.Fatalf("Failed to migrate table %s: %v", table, err)
+		}
+		log.Printf("Migrated %s: %d rows", table, count)
+	}
+
+	// Verify row counts
+	log.Println("Verifying row counts...")
+	for _, table := range tables {
+		var pgCount, sqliteCount int64
+
+		if exists, err := pgTableExists(pgDB, table); err != nil {
+			log.Fatalf("Failed to check PG table %s: %v", table, err)
+		} else if exists {
+			err := pgDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&pgCount)
+			if err != nil {
+				log.Fatalf("Failed to count PG rows for %s: %v", table, err)
+			}
+		}
+
+		err = tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&sqliteCount)
+		if err != nil {
+			log.Fatalf("Failed to count SQLite rows for %s: %v", table, err)
+		}
+
+		if pgCount != sqliteCount {
+			log.Fatalf("Row count mismatch for %s: PG=%d, SQLite=%d", table, pgCount, sqliteCount)
+		}
+		log.Printf("Verified %s: %d rows match", table, pgCount)
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		log.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	log.Println("Migration completed successfully!")
+}
+
+// pgTableExists reports whether a table exists in the current PostgreSQL schema
+func pgTableExists(pgDB *sql.DB, tableName string) (bool, error) {
+	var exists bool
+	err := pgDB.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1)",
+		tableName,
+	).Scan(&exists)
+	return exists, err
+}
+
+// checkUnknownTables fails if PostgreSQL has a table the migration doesn't copy
+func checkUnknownTables(pgDB *sql.DB) error {
+	rows, err := pgDB.Query(
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list PostgreSQL tables: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		known[t] = true
+	}
+	var unknown []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("failed to scan table name: %w", err)
+		}
+		if !known[name] && !skippedTables[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("PostgreSQL has tables this tool doesn't know: %s; update schema.sql and the tables list", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// pgColumns returns a table's PostgreSQL columns in definition order
+func pgColumns(pgDB *sql.DB, tableName string) ([]string, error) {
+	rows, err := pgDB.Query(
+		"SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position",
+		tableName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNames(rows)
+}
+
+// sqliteColumns returns the columns of a table in the SQLite schema
+func sqliteColumns(tx *sql.Tx, tableName string) ([]string, error) {
+	rows, err := tx.Query("SELECT name FROM pragma_table_info(?)", tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanNames(rows)
+}
+
+func scanNames(rows *sql.Rows) ([]string, error) {
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// unknownColumns returns the source columns missing from the target
+func unknownColumns(source, target []string) []string {
+	known := make(map[string]bool, len(target))
+	for _, c := range target {
+		known[c] = true
+	}
+	var unknown []string
+	for _, c := range source {
+		if !known[c] {
+			unknown = append(unknown, c)
+		}
+	}
+	return unknown
+}
+
+func migrateTable(pgDB *sql.DB, tx *sql.Tx, tableName string) (int64, error) {
+	// Tables added by later migrations might not exist in an older PG database
+	exists, err := pgTableExists(pgDB, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check table existence: %w", err)
+	}
+	if !exists {
+		log.Printf("Table %s does not exist in PostgreSQL, skipping", tableName)
+		return 0, nil
+	}
+
+	// Copy the columns PostgreSQL has; ones it predates keep their SQLite defaults
+	pgCols, err := pgColumns(pgDB, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list PostgreSQL columns: %w", err)
+	}
+	sqliteCols, err := sqliteColumns(tx, tableName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list SQLite columns: %w", err)
+	}
+	if unknown := unknownColumns(pgCols, sqliteCols); len(unknown) > 0 {
+		return 0, fmt.Errorf("columns missing from schema.sql: %s", strings.Join(unknown, ", "))
+	}
+	columns := strings.Join(pgCols, ", ")
+
+	// Read from PostgreSQL
+	rows, err := pgDB.Query(fmt.Sprintf("SELECT %s FROM %s", columns, tableName))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query PostgreSQL: %w", err)
+	}
+	defer rows.Close()
+
+	colNames, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	// Build INSERT statement with ? placeholders; SQLite accepts explicit
+	// values for INTEGER PRIMARY KEY columns, so IDs are preserved as-is
+	placeholders := make([]string, len(colNames))
+	for i := range colNames {
+		placeholders[i] = "?"
+	}
+	insertSQL := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		tableName, columns, strings.Join(placeholders, ", "),
+	)
+
+	stmt, err := tx.Prepare(insertSQL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	var count int64
+	for rows.Next() {
+		// Create a slice of interface{} to hold the values
+		values := make([]interface{}, len(colNames))
+		valuePtrs := make([]interface{}, len(colNames))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		// Convert PostgreSQL booleans to SQLite 0/1
+		for i := range values {
+			if v, ok := values[i].(bool); ok {
+				values[i] = boolToInt(v)
+			}
+		}
+
+		if _, err := stmt.Exec(values...); err != nil {
+			return 0, fmt.Errorf("failed to insert row: %w", err)
+		}
+		count++
+	}
+
+	return count, rows.Err()
+}
+
+func boolToInt(v bool) int64 {
+	if v {
+		return 1
+	}
+	return 0
+}
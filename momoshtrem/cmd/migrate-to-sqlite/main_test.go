@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSchemaHasEveryTable(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		t.Fatalf("schema.sql: %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range tables {
+		cols, err := sqliteColumns(tx, table)
+		if err != nil || len(cols) == 0 {
+			t.Errorf("table %s missing from schema.sql: %v", table, err)
+		}
+	}
+
+	// Columns added by the latest migrations
+	cols, err := sqliteColumns(tx, "torrent_assignments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"bitrate_bps", "bit_depth", "part_paths", "codec", "hdr", "confidence", "needs_review", "local_path", "audio_codec"} {
+		if !slices.Contains(cols, want) {
+			t.Errorf("torrent_assignments lacks %s", want)
+		}
+	}
+}
+
+func TestUnknownColumns(t *testing.T) {
+	target := []string{"id", "title", "audio_codec"}
+	if got := unknownColumns([]string{"id", "title"}, target); len(got) != 0 {
+		t.Errorf("older source reported unknown columns %v", got)
+	}
+	if got := unknownColumns([]string{"id", "title", "dolby_atmos"}, target); !slices.Equal(got, []string{"dolby_atmos"}) {
+		t.Errorf("unknownColumns = %v, want [dolby_atmos]", got)
+	}
+}
@@ -0,0 +1,163 @@
+-- SQLite schema for momoshtrem, equivalent to the PostgreSQL migrations in internal/library/migrations
+-- (through 019). Keep it in step with new migrations: the tool refuses to run
+-- when the PostgreSQL database has a table or column this schema lacks.
+
+CREATE TABLE IF NOT EXISTS movies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tmdb_id INTEGER UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    year INTEGER NOT NULL,
+    overview TEXT NOT NULL DEFAULT '',
+    poster_url TEXT NOT NULL DEFAULT '',
+    backdrop_url TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_movies_tmdb ON movies(tmdb_id);
+
+CREATE TABLE IF NOT EXISTS shows (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tmdb_id INTEGER UNIQUE NOT NULL,
+    title TEXT NOT NULL,
+    year INTEGER NOT NULL,
+    overview TEXT NOT NULL DEFAULT '',
+    poster_url TEXT NOT NULL DEFAULT '',
+    backdrop_url TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_shows_tmdb ON shows(tmdb_id);
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    show_id INTEGER NOT NULL REFERENCES shows(id) ON DELETE CASCADE,
+    season_number INTEGER NOT NULL,
+    UNIQUE(show_id, season_number)
+);
+CREATE INDEX IF NOT EXISTS idx_seasons_show ON seasons(show_id);
+
+CREATE TABLE IF NOT EXISTS episodes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    season_id INTEGER NOT NULL REFERENCES seasons(id) ON DELETE CASCADE,
+    episode_number INTEGER NOT NULL,
+    name TEXT,
+    air_date TEXT,
+    tmdb_id INTEGER NOT NULL DEFAULT 0,
+    overview TEXT NOT NULL DEFAULT '',
+    UNIQUE(season_id, episode_number)
+);
+CREATE INDEX IF NOT EXISTS idx_episodes_season ON episodes(season_id);
+CREATE INDEX IF NOT EXISTS idx_episodes_air_date ON episodes(air_date);
+
+CREATE TABLE IF NOT EXISTS torrent_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id INTEGER NOT NULL,
+    info_hash TEXT NOT NULL,
+    magnet_uri TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    file_size INTEGER NOT NULL,
+    resolution TEXT,
+    source TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bitrate_bps INTEGER,
+    runtime_seconds INTEGER,
+    bit_depth TEXT,
+    part_paths TEXT,
+    codec TEXT,
+    hdr BOOLEAN NOT NULL DEFAULT FALSE,
+    confidence TEXT,
+    pattern_used TEXT,
+    season_from_folder BOOLEAN NOT NULL DEFAULT FALSE,
+    needs_review BOOLEAN NOT NULL DEFAULT FALSE,
+    local_path TEXT,
+    audio_codec TEXT,
+    UNIQUE(item_type, item_id, info_hash, file_path)
+);
+CREATE INDEX IF NOT EXISTS idx_assignments_item ON torrent_assignments(item_type, item_id);
+CREATE INDEX IF NOT EXISTS idx_assignments_hash ON torrent_assignments(info_hash);
+CREATE INDEX IF NOT EXISTS idx_assignments_needs_review ON torrent_assignments(created_at) WHERE needs_review AND is_active;
+
+CREATE TABLE IF NOT EXISTS subtitles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id INTEGER NOT NULL,
+    language_code TEXT NOT NULL,
+    language_name TEXT NOT NULL,
+    format TEXT NOT NULL DEFAULT 'srt',
+    file_path TEXT NOT NULL,
+    file_size INTEGER DEFAULT 0,
+    source TEXT NOT NULL DEFAULT 'opensubtitles',
+    info_hash TEXT,
+    content_hash TEXT,
+    offset_ms INTEGER NOT NULL DEFAULT 0,
+    forced BOOLEAN NOT NULL DEFAULT FALSE,
+    hearing_impaired BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_subtitles_item ON subtitles(item_type, item_id);
+CREATE INDEX IF NOT EXISTS idx_subtitles_source ON subtitles(source);
+CREATE INDEX IF NOT EXISTS idx_subtitles_content_hash ON subtitles(item_type, item_id, content_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subtitles_item_variant
+    ON subtitles(item_type, item_id, language_code, forced, hearing_impaired);
+
+CREATE TABLE IF NOT EXISTS sync_metadata (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS item_metadata (
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'show', 'episode')),
+    item_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(item_type, item_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_item_metadata_key ON item_metadata(key, value);
+
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'show')),
+    item_id INTEGER NOT NULL,
+    added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(collection_id, item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_collection_items_item ON collection_items(item_type, item_id);
+
+CREATE TABLE IF NOT EXISTS watch_status (
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id INTEGER NOT NULL,
+    position_seconds INTEGER NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    watched BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_watch_status_updated ON watch_status(updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS queued_torrents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id INTEGER NOT NULL,
+    info_hash TEXT NOT NULL,
+    magnet_uri TEXT NOT NULL,
+    air_date TEXT NOT NULL,
+    prewarmed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_queued_torrents_due ON queued_torrents(air_date) WHERE prewarmed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS show_settings (
+    show_id INTEGER PRIMARY KEY REFERENCES shows(id) ON DELETE CASCADE,
+    preferred_subtitle_langs TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -18,9 +18,13 @@ import (
 	"github.com/shapedtime/momoshtrem/internal/airdate"
 	"github.com/shapedtime/momoshtrem/internal/api"
 	"github.com/shapedtime/momoshtrem/internal/config"
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/jobs"
 	"github.com/shapedtime/momoshtrem/internal/library"
 	"github.com/shapedtime/momoshtrem/internal/metrics"
+	"github.com/shapedtime/momoshtrem/internal/notify"
 	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
+	"github.com/shapedtime/momoshtrem/internal/service"
 	"github.com/shapedtime/momoshtrem/internal/streaming"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
 	"github.com/shapedtime/momoshtrem/internal/tmdb"
@@ -48,6 +52,10 @@ func main() {
 		slog.Error("Failed to load config", "error", err)
 		os.Exit(1)
 	}
+	if err := cfg.Validate(); err != nil {
+		slog.Error("Invalid config", "path", *configPath, "error", err)
+		os.Exit(1)
+	}
 
 	// Ensure required directories exist
 	if err := cfg.EnsureDirectories(); err != nil {
@@ -69,19 +77,24 @@ func main() {
 	showRepo := library.NewShowRepository(db)
 	assignmentRepo := library.NewAssignmentRepository(db)
 	syncMetaRepo := library.NewSyncMetadataRepository(db)
+	metadataRepo := library.NewMetadataRepository(db)
+	collectionRepo := library.NewCollectionRepository(db)
+	watchStatusRepo := library.NewWatchStatusRepository(db)
+	showSettingsRepo := library.NewShowSettingsRepository(db)
 
 	// Initialize TMDB client
 	var tmdbClient *tmdb.Client
 	if cfg.TMDB.APIKey != "" {
-		tmdbClient = tmdb.NewClient(cfg.TMDB.APIKey)
-		slog.Info("TMDB client initialized")
+		tmdbClient = tmdb.NewClient(cfg.TMDB.APIKey,
+			tmdb.WithCache(time.Duration(cfg.TMDB.CacheTTLMinutes)*time.Minute, cfg.TMDB.CacheDir))
+		slog.Info("TMDB client initialized", "cache_ttl_minutes", cfg.TMDB.CacheTTLMinutes)
 	} else {
 		slog.Warn("TMDB API key not configured, some features will be unavailable")
 		tmdbClient = tmdb.NewClient("") // Empty client will fail on API calls
 	}
 
 	// Initialize torrent storage
-	pieceStorage, _, pieceCompletion, err := torrent.InitStorage(
+	pieceStorage, pieceCache, pieceCompletion, err := torrent.InitStorage(
 		cfg.Torrent.MetadataFolder,
 		cfg.Torrent.GlobalCacheSize,
 	)
@@ -134,11 +147,13 @@ func main() {
 	if cfg.Torrent.IdleEnabled {
 		activityManager = torrent.NewActivityManager(
 			time.Duration(cfg.Torrent.IdleTimeout)*time.Second,
+			time.Duration(cfg.Torrent.IdleCheckIntervalSeconds)*time.Second,
 			cfg.Torrent.StartPaused,
 		)
 		activityManager.Start()
 		slog.Info("Activity manager started",
 			"idle_timeout_seconds", cfg.Torrent.IdleTimeout,
+			"idle_check_interval_seconds", cfg.Torrent.IdleCheckIntervalSeconds,
 			"start_paused", cfg.Torrent.StartPaused,
 		)
 	}
@@ -149,17 +164,50 @@ func main() {
 		activityManager,
 		time.Duration(cfg.Torrent.AddTimeout)*time.Second,
 		time.Duration(cfg.Torrent.ReadTimeout)*time.Second,
+		torrent.MetadataRetry{
+			Retries:    cfg.Torrent.AddRetries,
+			Backoff:    time.Duration(cfg.Torrent.AddRetryBackoff) * time.Second,
+			Background: cfg.Torrent.ResolveInBackground,
+		},
+		cfg.Torrent.DefaultTrackers,
+		filepath.Join(cfg.Torrent.MetadataFolder, "metainfo"),
 	)
 	slog.Info("Torrent service initialized",
 		"add_timeout_seconds", cfg.Torrent.AddTimeout,
+		"add_retries", cfg.Torrent.AddRetries,
+		"resolve_in_background", cfg.Torrent.ResolveInBackground,
+		"default_trackers", len(cfg.Torrent.DefaultTrackers),
 		"read_timeout_seconds", cfg.Torrent.ReadTimeout,
 	)
 
+	// Forget stored metadata of torrents no longer assigned (inspected, previewed...)
+	if hashes, err := assignmentRepo.ListDistinctTorrents(); err != nil {
+		slog.Warn("Failed to list assigned torrents", "error", err)
+	} else if n, err := torrentService.PruneMetainfo(hashes); err != nil {
+		slog.Warn("Failed to prune stored torrent metadata", "error", err)
+	} else if n > 0 {
+		slog.Info("Pruned stored metadata of unassigned torrents", "torrents", n)
+	}
+
+	// Add assigned torrents up front so cached pieces are readable right away
+	var preloader *service.TorrentPreloader
+	if cfg.Torrent.PreloadAssignedTorrents {
+		preloader = service.NewTorrentPreloader(assignmentRepo, torrentService)
+		preloader.Start()
+	}
+
 	// Initialize VFS (event-driven updates, no periodic rebuilds)
 	libraryFS := vfs.NewLibraryFS(movieRepo, showRepo, assignmentRepo, cfg.VFS.TreeTTL)
 	if cfg.VFS.CacheDir != "" {
 		libraryFS.SetCacheDir(cfg.VFS.CacheDir)
+		libraryFS.SetSyncMetadataRepository(syncMetaRepo)
 	}
+	libraryFS.SetFlatMovies(cfg.VFS.FlatMovies)
+	libraryFS.SetForceExtension(cfg.VFS.ForceExtension)
+	libraryFS.SetTagFolders(metadataRepo, cfg.VFS.TagFolderKey)
+	libraryFS.SetCollectionRepository(collectionRepo)
+	libraryFS.SetSubtitlePreferences(showSettingsRepo, cfg.Subtitles.PreferredLanguages)
+	libraryFS.SetRebuildDelay(time.Duration(cfg.VFS.RebuildDelayMs) * time.Millisecond)
 	slog.Info("VFS initialized", "cache_dir", cfg.VFS.CacheDir)
 
 	// Wire torrent service into VFS with streaming optimization
@@ -176,14 +224,23 @@ func main() {
 		FooterPriorityBytes: cfg.Streaming.FooterPriorityBytes,
 		ReadaheadBytes:      cfg.Streaming.ReadaheadBytes,
 		UrgentBufferBytes:   cfg.Streaming.UrgentBufferBytes,
+
+		ReadaheadMinMultiplier: cfg.Streaming.ReadaheadMinMultiplier,
+		ReadaheadMaxMultiplier: cfg.Streaming.ReadaheadMaxMultiplier,
+		TargetBufferSeconds:    cfg.Streaming.TargetBufferSeconds,
 	}
 	if streamingCfg.IsZero() {
 		streamingCfg = streaming.DefaultConfig()
 	}
+	streamingCfg.WaitForIndex = cfg.Streaming.WaitForIndex
+	streamingCfg.IndexWaitTimeout = time.Duration(cfg.Streaming.IndexWaitTimeoutSeconds) * time.Second
 	slog.Info("Streaming optimization configured",
 		"header_priority_mb", streamingCfg.HeaderPriorityBytes/(1024*1024),
 		"footer_priority_mb", streamingCfg.FooterPriorityBytes/(1024*1024),
 		"readahead_mb", streamingCfg.ReadaheadBytes/(1024*1024),
+		"readahead_max_multiplier", streamingCfg.ReadaheadMaxMultiplier,
+		"target_buffer_seconds", streamingCfg.TargetBufferSeconds,
+		"wait_for_index", streamingCfg.WaitForIndex,
 	)
 
 	libraryFS.SetTorrentService(
@@ -193,11 +250,41 @@ func main() {
 		activationCallback,
 		streamingCfg,
 	)
+	if activityManager != nil {
+		libraryFS.SetReaderCallbacks(activityManager.Acquire, activityManager.Release)
+	}
+
+	// Watch the piece cache for evictions forced by its size cap
+	cacheMonitor := torrent.NewCacheMonitor(pieceCache, 30*time.Second)
+	cacheMonitor.SetStreamingPieces(libraryFS.StreamingPieceHashes)
+	cacheMonitor.Start()
+
+	// Bound long-running API operations so a flood of requests gets 429s
+	// instead of exhausting goroutines and connections
+	jobQueue := jobs.NewQueue(jobs.Config{
+		Workers:    cfg.Jobs.MaxConcurrent,
+		QueueSize:  cfg.Jobs.QueueSize,
+		RetryAfter: time.Duration(cfg.Jobs.RetryAfterSeconds) * time.Second,
+	})
+
+	// Webhook notifications (optional)
+	notifier := notify.New(notify.Config{
+		URLs:        cfg.Webhooks.URLs,
+		Secret:      cfg.Webhooks.Secret,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Timeout:     time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second,
+	})
+	if notifier != nil {
+		defer notifier.Close()
+		slog.Info("Webhook notifications enabled", "urls", len(cfg.Webhooks.URLs), "signed", cfg.Webhooks.Secret != "")
+	}
 
 	// Initialize Prometheus metrics (optional)
 	var metricsServer *metrics.Server
+	var metricsReg *prometheus.Registry
 	if cfg.Metrics.Enabled {
 		reg := prometheus.NewRegistry()
+		metricsReg = reg
 		reg.MustRegister(
 			collectors.NewGoCollector(),
 			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
@@ -209,6 +296,12 @@ func main() {
 		torrentCollector := metrics.NewTorrentCollector(torrentService, activityManager)
 		reg.MustRegister(torrentCollector)
 
+		if jobQueue != nil {
+			metrics.RegisterJobQueue(reg, jobQueue)
+		}
+		metrics.RegisterTMDBCache(reg, tmdbClient)
+		metrics.RegisterPieceCache(reg, cacheMonitor)
+
 		metricsServer = metrics.NewServer(cfg.Metrics.Port, reg)
 		go func() {
 			if err := metricsServer.Start(); err != nil {
@@ -219,12 +312,58 @@ func main() {
 	}
 
 	// Initialize servers with torrent service and tree updater
-	apiServer := api.NewServer(movieRepo, showRepo, assignmentRepo, tmdbClient, torrentService, libraryFS)
+	// Build identifier config: explicit skip patterns replace the defaults, extras extend them
+	identifyCfg := identify.DefaultConfig()
+	if cfg.Identify.SkipPatterns != nil {
+		identifyCfg.SkipPatterns = cfg.Identify.SkipPatterns
+	}
+	identifyCfg.SkipPatterns = append(identifyCfg.SkipPatterns, cfg.Identify.ExtraSkipPatterns...)
+	identifyCfg.MinFileSize = cfg.Identify.MinFileSizeMB * 1024 * 1024
+	identifyCfg.MinSeason = cfg.Identify.MinSeason
+	identifyCfg.MaxSeason = cfg.Identify.MaxSeason
+	identifyCfg.MaxEpisode = cfg.Identify.MaxEpisode
+	identifyCfg.AnimeAbsoluteEpisodes = cfg.Identify.AnimeAbsoluteEpisodes
+	identifyCfg.CacheSize = cfg.Identify.CacheSize
+
+	apiServer := api.NewServer(movieRepo, showRepo, assignmentRepo, tmdbClient, torrentService, libraryFS, identifyCfg)
+	apiServer.SetMetadataRepository(metadataRepo)
+	apiServer.SetCollectionRepository(collectionRepo)
+	apiServer.SetWatchStatusRepository(watchStatusRepo)
+	apiServer.SetShowSettingsRepository(showSettingsRepo)
+	apiServer.SetLocalMediaRoots(cfg.VFS.LocalMediaRoots)
+	apiServer.SetStreamInspector(libraryFS)
+	apiServer.SetPlaylistSource(libraryFS, cfg.Server)
+	apiServer.SetActivityManager(activityManager) // nil when idle mode is disabled
+	apiServer.SetJobQueue(jobQueue)
+	apiServer.SetNotifier(notifier)
+	apiServer.SetHealthChecks(db, cfg.Server.HealthCheckTMDB)
+	apiServer.SetAPIAuth(cfg.Server.APIAuth)
+	apiServer.SetTorrentEventsInterval(time.Duration(cfg.Server.TorrentEventsIntervalSeconds) * time.Second)
+	apiServer.SetCacheSize(cfg.Torrent.GlobalCacheSize * 1024 * 1024)
+	apiServer.SetCacheMonitor(cacheMonitor)
+	if cfg.Server.ShowZipDownload {
+		apiServer.SetShowArchive(libraryFS)
+		slog.Warn("Show zip download enabled; each download reads whole seasons through the torrent client")
+	}
+	apiServer.SetAssignSingleVideo(cfg.Identify.AssignSingleVideo)
+	apiServer.SetMinSeeders(cfg.Torrent.MinSeeders)
+	if metricsReg != nil {
+		metrics.RegisterIdentifyCache(metricsReg, apiServer.Identifier())
+	}
+	if cfg.Identify.Fallback.Enabled {
+		apiServer.SetIdentifyFallback(identify.NewLLMFallback(identify.LLMFallbackConfig{
+			URL:     cfg.Identify.Fallback.URL,
+			Model:   cfg.Identify.Fallback.Model,
+			Timeout: time.Duration(cfg.Identify.Fallback.TimeoutSeconds) * time.Second,
+		}))
+		slog.Info("LLM identification fallback enabled", "url", cfg.Identify.Fallback.URL, "model", cfg.Identify.Fallback.Model)
+	}
 
 	// Initialize air date sync service
 	var airDateSync *airdate.SyncService
 	if cfg.AirDateSync.Enabled && cfg.TMDB.APIKey != "" {
 		airDateSync = airdate.NewSyncService(cfg.AirDateSync, showRepo, syncMetaRepo, tmdbClient)
+		airDateSync.SetNotifier(notifier)
 		airDateSync.Start()
 		apiServer.SetAirDateSyncService(airDateSync)
 		slog.Info("Air date sync service initialized",
@@ -235,6 +374,27 @@ func main() {
 		slog.Warn("Air date sync service disabled (requires TMDB API key)")
 	}
 
+	// Initialize torrent pre-warming for queued episodes
+	var prewarmer *service.TorrentPrewarmer
+	if cfg.Prewarm.Enabled {
+		prewarmer = service.NewTorrentPrewarmer(
+			library.NewQueuedTorrentRepository(db),
+			showRepo,
+			torrentService,
+			apiServer.Identifier(),
+			time.Duration(cfg.Prewarm.CheckIntervalMinutes)*time.Minute,
+		)
+		if cfg.Prewarm.PrefetchHeader {
+			var onActivity func(hash string)
+			if activityManager != nil {
+				onActivity = activityManager.MarkActive
+			}
+			prewarmer.SetHeaderPrefetch(streamingCfg, onActivity)
+		}
+		prewarmer.Start()
+		apiServer.SetTorrentPrewarmer(prewarmer)
+	}
+
 	// Initialize subtitle repository (always, for VFS to show existing subtitles)
 	subtitleRepo := subtitle.NewRepository(db.DB)
 	libraryFS.SetSubtitleRepository(subtitleRepo)
@@ -246,8 +406,11 @@ func main() {
 			cfg.OpenSubtitles.Username,
 			cfg.OpenSubtitles.Password,
 		)
+		osClient.SetMaxRetries(cfg.OpenSubtitles.MaxRetries)
 		subtitleService := subtitle.NewService(osClient, subtitleRepo, cfg.Subtitles.DownloadPath)
+		subtitleService.SetDedupeIdentical(cfg.Subtitles.DedupeIdentical)
 		apiServer.SetSubtitleService(subtitleService)
+		apiServer.SetAutoSubtitleLanguages(cfg.Subtitles.AutoDownloadLanguages)
 		slog.Info("Subtitle service initialized with OpenSubtitles client")
 	} else {
 		slog.Warn("OpenSubtitles API key not configured, subtitle download unavailable")
@@ -256,12 +419,31 @@ func main() {
 	// Validate WebDAV auth config and create server
 	webdav.ValidateConfig(cfg.Server.WebDAVAuth)
 	webdavServer := webdav.NewServer(libraryFS, cfg.Server.WebDAVAuth)
+	webdavServer.SetAccessLog(cfg.Server.WebDAVAccessLog)
+	webdavServer.SetBasePath(cfg.Server.WebDAVBasePath)
+
+	// Build the VFS tree before the first PROPFIND (from the persistent cache when fresh)
+	go libraryFS.LoadTree()
+
+	// Fill in artwork URLs for items added before artwork was stored
+	if cfg.TMDB.APIKey != "" {
+		go func() {
+			updated, err := service.BackfillArtwork(context.Background(), movieRepo, showRepo, syncMetaRepo, tmdbClient)
+			if err != nil {
+				slog.Warn("Artwork backfill incomplete", "error", err)
+			}
+			if updated > 0 {
+				libraryFS.InvalidateTree()
+			}
+		}()
+	}
 
 	// Start HTTP servers
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.HTTPPort),
 		Handler: apiServer.Handler(),
 	}
+	httpServer.RegisterOnShutdown(apiServer.CloseEventStreams)
 
 	webdavHTTPServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.WebDAVPort),
@@ -296,6 +478,22 @@ func main() {
 	sig := <-sigChan
 	slog.Info("Received signal, shutting down", "signal", sig)
 
+	// Refuse new streams and give open ones a chance to finish before the
+	// torrent client goes away under them
+	libraryFS.StartDraining()
+	if active := libraryFS.ActiveStreams(); active > 0 {
+		drainTimeout := time.Duration(cfg.Server.ShutdownDrainTimeoutSeconds) * time.Second
+		slog.Info("Draining active streams", "active_streams", active, "timeout_seconds", drainTimeout.Seconds())
+
+		drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+		if remaining := libraryFS.WaitForDrain(drainCtx); remaining > 0 {
+			slog.Warn("Drain timeout reached, closing streams", "active_streams", remaining)
+		} else {
+			slog.Info("All streams drained")
+		}
+		drainCancel()
+	}
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -315,6 +513,13 @@ func main() {
 		slog.Error("WebDAV server shutdown error", "error", err)
 	}
 
+	// Persist the VFS tree (including targeted updates) for a fast next start
+	if cfg.VFS.CacheDir != "" {
+		libraryFS.SaveCache()
+	}
+
+	cacheMonitor.Stop()
+
 	// Stop activity manager
 	if activityManager != nil {
 		activityManager.Stop()
@@ -325,6 +530,14 @@ func main() {
 		airDateSync.Stop()
 	}
 
+	// Stop torrent pre-warming
+	if prewarmer != nil {
+		prewarmer.Stop()
+	}
+	if preloader != nil {
+		preloader.Stop()
+	}
+
 	// Close torrent service
 	if err := torrentService.Close(); err != nil {
 		slog.Error("Torrent service close error", "error", err)
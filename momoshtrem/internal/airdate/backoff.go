@@ -0,0 +1,64 @@
+package airdate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRateLimitBackoff is used when TMDB's 429 has no Retry-After header
+	defaultRateLimitBackoff = 10 * time.Second
+	// maxRateLimitRetries bounds how often one request is retried after a 429
+	maxRateLimitRetries = 3
+)
+
+// rateBackoff is a pause shared by all sync workers. When one worker is rate
+// limited, every worker waits until the backoff ends instead of each hitting
+// TMDB again.
+type rateBackoff struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// trigger extends the backoff by d (or the default when d is 0) from now and
+// returns the pause applied. Concurrent 429s do not stack.
+func (b *rateBackoff) trigger(d time.Duration) time.Duration {
+	if d <= 0 {
+		d = defaultRateLimitBackoff
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.until) {
+		b.until = until
+	}
+	return time.Until(b.until)
+}
+
+// wait blocks until the backoff has ended or ctx is done.
+func (b *rateBackoff) wait(ctx context.Context) error {
+	b.mu.Lock()
+	remaining := time.Until(b.until)
+	b.mu.Unlock()
+
+	return sleepContext(ctx, remaining)
+}
+
+// sleepContext sleeps for d or until ctx is done, returning ctx's error.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
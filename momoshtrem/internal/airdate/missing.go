@@ -0,0 +1,152 @@
+package airdate
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/notify"
+)
+
+// notifiedMissingKey stores the episode IDs already reported as missing, so
+// an episode is announced once rather than after every sync.
+const notifiedMissingKey = "air_date_notified_missing"
+
+// MissingEpisode is an episode that aired recently without an active assignment
+type MissingEpisode struct {
+	ShowID        int64  `json:"show_id"`
+	ShowTitle     string `json:"show_title"`
+	ShowYear      int    `json:"show_year"`
+	SeasonNumber  int    `json:"season_number"`
+	EpisodeID     int64  `json:"episode_id"`
+	EpisodeNumber int    `json:"episode_number"`
+	EpisodeName   string `json:"episode_name,omitempty"`
+	AirDate       string `json:"air_date"`
+}
+
+// MissingEpisodesWebhook is the payload of notify.EventEpisodesMissing
+type MissingEpisodesWebhook struct {
+	LookbackDays int              `json:"lookback_days"`
+	Episodes     []MissingEpisode `json:"episodes"`
+}
+
+// SetNotifier configures webhook notifications for newly missing episodes.
+func (s *SyncService) SetNotifier(n *notify.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = n
+}
+
+// notifyMissingEpisodes reports episodes that aired within the lookback window
+// and have no active assignment, skipping those reported by an earlier sync.
+// Best-effort: failures are logged and never fail the sync.
+func (s *SyncService) notifyMissingEpisodes() {
+	recent, err := s.showRepo.GetRecentlyAiredEpisodes(s.config.LookbackDays)
+	if err != nil {
+		s.log.Warn("Failed to list recently aired episodes", "error", err)
+		return
+	}
+
+	notified, err := s.loadNotifiedMissing()
+	if err != nil {
+		s.log.Warn("Failed to load notified episodes", "error", err)
+		return
+	}
+
+	missing, stillMissing := newlyMissing(recent, notified)
+
+	// Only keep IDs that are still missing and recent, so the set stays small
+	// and an episode that loses its assignment is reported again
+	if err := s.saveNotifiedMissing(stillMissing); err != nil {
+		s.log.Warn("Failed to store notified episodes", "error", err)
+		return
+	}
+
+	if len(missing) == 0 {
+		return
+	}
+
+	for _, ep := range missing {
+		s.log.Info("Aired episode has no assignment",
+			"show", ep.ShowTitle,
+			"season", ep.SeasonNumber,
+			"episode", ep.EpisodeNumber,
+			"air_date", ep.AirDate,
+		)
+	}
+
+	s.mu.RLock()
+	notifier := s.notifier
+	s.mu.RUnlock()
+
+	notifier.Notify(notify.EventEpisodesMissing, MissingEpisodesWebhook{
+		LookbackDays: s.config.LookbackDays,
+		Episodes:     missing,
+	})
+}
+
+// newlyMissing returns the recent episodes without an assignment that haven't
+// been reported yet, and the IDs of every recent episode still missing one.
+func newlyMissing(recent []library.RecentlyAiredEpisode, notified map[int64]struct{}) ([]MissingEpisode, []int64) {
+	var missing []MissingEpisode
+	var stillMissing []int64
+	for _, ep := range recent {
+		if ep.HasAssignment {
+			continue
+		}
+		stillMissing = append(stillMissing, ep.EpisodeID)
+		if _, ok := notified[ep.EpisodeID]; ok {
+			continue
+		}
+		missing = append(missing, toMissingEpisode(ep))
+	}
+	return missing, stillMissing
+}
+
+func (s *SyncService) loadNotifiedMissing() (map[int64]struct{}, error) {
+	value, err := s.syncRepo.GetValue(notifiedMissingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	notified := make(map[int64]struct{})
+	if value == "" {
+		return notified, nil
+	}
+
+	var ids []int64
+	if err := json.Unmarshal([]byte(value), &ids); err != nil {
+		// A corrupt list only means episodes may be reported again
+		s.log.Warn("Ignoring corrupt notified episode list", "error", err)
+		return notified, nil
+	}
+	for _, id := range ids {
+		notified[id] = struct{}{}
+	}
+	return notified, nil
+}
+
+func (s *SyncService) saveNotifiedMissing(ids []int64) error {
+	slices.Sort(ids)
+	if ids == nil {
+		ids = []int64{}
+	}
+	value, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return s.syncRepo.SetValue(notifiedMissingKey, string(value))
+}
+
+func toMissingEpisode(ep library.RecentlyAiredEpisode) MissingEpisode {
+	return MissingEpisode{
+		ShowID:        ep.ShowID,
+		ShowTitle:     ep.ShowTitle,
+		ShowYear:      ep.ShowYear,
+		SeasonNumber:  ep.SeasonNumber,
+		EpisodeID:     ep.EpisodeID,
+		EpisodeNumber: ep.EpisodeNumber,
+		EpisodeName:   ep.EpisodeName,
+		AirDate:       ep.AirDate,
+	}
+}
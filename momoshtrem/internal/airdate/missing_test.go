@@ -0,0 +1,48 @@
+package airdate
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestNewlyMissing(t *testing.T) {
+	recent := []library.RecentlyAiredEpisode{
+		{EpisodeID: 1, EpisodeNumber: 1},
+		{EpisodeID: 2, EpisodeNumber: 2, HasAssignment: true},
+		{EpisodeID: 3, EpisodeNumber: 3},
+	}
+	ids := func(episodes []MissingEpisode) []int64 {
+		var ids []int64
+		for _, ep := range episodes {
+			ids = append(ids, ep.EpisodeID)
+		}
+		return ids
+	}
+
+	// Episodes without an assignment are reported once
+	missing, stillMissing := newlyMissing(recent, map[int64]struct{}{3: {}})
+	if got := ids(missing); !slices.Equal(got, []int64{1}) {
+		t.Errorf("missing = %v, want [1]", got)
+	}
+	if !slices.Equal(stillMissing, []int64{1, 3}) {
+		t.Errorf("still missing = %v, want [1 3]", stillMissing)
+	}
+
+	// Once episode 2 loses its assignment it is reported, and the others are not again
+	recent[1].HasAssignment = false
+	missing, stillMissing = newlyMissing(recent, map[int64]struct{}{1: {}, 3: {}})
+	if got := ids(missing); !slices.Equal(got, []int64{2}) {
+		t.Errorf("missing after unassigning = %v, want [2]", got)
+	}
+	if !slices.Equal(stillMissing, []int64{1, 2, 3}) {
+		t.Errorf("still missing after unassigning = %v, want [1 2 3]", stillMissing)
+	}
+
+	// Nothing aired recently clears the notified set
+	missing, stillMissing = newlyMissing(nil, map[int64]struct{}{1: {}})
+	if missing != nil || stillMissing != nil {
+		t.Errorf("newlyMissing(nil) = %v, %v; want nothing", missing, stillMissing)
+	}
+}
@@ -5,13 +5,22 @@ import (
 	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shapedtime/momoshtrem/internal/config"
 	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/notify"
 	"github.com/shapedtime/momoshtrem/internal/tmdb"
 )
 
+// Sync metadata keys (stored with a "last_" prefix)
+const (
+	syncKey            = "air_date_sync"    // Any completed sync; drives the sync interval
+	fullSyncKey        = "full_sync"        // Start of the last full sync
+	incrementalSyncKey = "incremental_sync" // Start of the last incremental sync
+)
+
 // SyncService manages background synchronization of episode air dates from TMDB
 type SyncService struct {
 	mu       sync.RWMutex
@@ -24,6 +33,11 @@ type SyncService struct {
 	syncStatus string // "ok", "pending", "in_progress", "error"
 	lastError  error
 
+	notifier *notify.Notifier // Optional: reports aired episodes without an assignment
+
+	// Shared TMDB backoff: a 429 seen by any worker pauses all of them
+	backoff *rateBackoff
+
 	stopChan chan struct{}
 	stopped  bool
 	log      *slog.Logger
@@ -42,6 +56,7 @@ func NewSyncService(
 		syncRepo:   syncRepo,
 		tmdb:       tmdbClient,
 		syncStatus: "pending",
+		backoff:    &rateBackoff{},
 		stopChan:   make(chan struct{}),
 		log:        slog.With("component", "airdate-sync"),
 	}
@@ -53,6 +68,7 @@ func (s *SyncService) Start() {
 		"interval_hours", s.config.SyncIntervalHours,
 		"lookback_days", s.config.LookbackDays,
 		"batch_size", s.config.BatchSize,
+		"full_sync_interval_days", s.config.FullSyncIntervalDays,
 	)
 	go s.syncLoop()
 }
@@ -70,19 +86,31 @@ func (s *SyncService) Stop() {
 	s.log.Info("Air date sync service stopped")
 }
 
+// ErrSyncInProgress is returned by TriggerSync while another sync is running
+var ErrSyncInProgress = errors.New("sync already in progress")
+
 // TriggerSync manually triggers a sync
 func (s *SyncService) TriggerSync() error {
+	if !s.beginSync() {
+		return ErrSyncInProgress
+	}
+
+	// fullSync will set final status (ok/error) when complete.
+	// A manual sync bypasses the TMDB cache so newly announced air dates show up.
+	return s.fullSync(context.Background(), s.tmdb.Uncached())
+}
+
+// beginSync marks a sync as running. Returns false if one already is, so a
+// manual trigger and the scheduled loop never run concurrently.
+func (s *SyncService) beginSync() bool {
 	// Use write lock for atomic check-and-set to prevent race condition
 	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.syncStatus == "in_progress" {
-		s.mu.Unlock()
-		return errors.New("sync already in progress")
+		return false
 	}
 	s.syncStatus = "in_progress"
-	s.mu.Unlock()
-
-	// doSync will set final status (ok/error) when complete
-	return s.doSync(context.Background(), false)
+	return true
 }
 
 // GetStatus returns current sync status
@@ -99,7 +127,7 @@ func (s *SyncService) GetLookbackDays() int {
 
 // SyncSingleShow syncs air dates for a single show (called when a show is added)
 func (s *SyncService) SyncSingleShow(show *library.Show) error {
-	return s.syncShowAirDates(context.Background(), show)
+	return s.syncShowAirDates(context.Background(), s.tmdb, show)
 }
 
 func (s *SyncService) syncLoop() {
@@ -120,7 +148,7 @@ func (s *SyncService) syncLoop() {
 }
 
 func (s *SyncService) checkAndSync() {
-	lastSync, err := s.syncRepo.GetLastSyncTime("air_date_sync")
+	lastSync, err := s.syncRepo.GetLastSyncTime(syncKey)
 	if err != nil {
 		s.log.Error("Failed to get last sync time", "error", err)
 		return
@@ -128,75 +156,225 @@ func (s *SyncService) checkAndSync() {
 
 	interval := time.Duration(s.config.SyncIntervalHours) * time.Hour
 	if time.Since(lastSync) >= interval {
+		if !s.beginSync() {
+			s.log.Info("Skipping scheduled sync, a sync is already in progress")
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 		defer cancel()
-		if err := s.doSync(ctx, true); err != nil {
+
+		// Abort the run when the service stops
+		go func() {
+			select {
+			case <-s.stopChan:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		if err := s.scheduledSync(ctx); err != nil {
 			s.log.Error("Scheduled sync failed", "error", err)
 		}
 	}
 }
 
-func (s *SyncService) doSync(ctx context.Context, setInProgress bool) error {
-	if setInProgress {
-		s.mu.Lock()
-		s.syncStatus = "in_progress"
-		s.mu.Unlock()
+// scheduledSync runs an incremental sync when the last sync is recent enough
+// for the TMDB changes window, and a full sync otherwise or when the last full
+// sync is older than FullSyncIntervalDays. Scheduled full syncs bypass the TMDB
+// cache, since refreshing seasons the changes feed missed is their point.
+func (s *SyncService) scheduledSync(ctx context.Context) error {
+	lastFull, lastIncremental, err := s.lastSyncTimes()
+	if err != nil {
+		s.log.Warn("Failed to read last sync times, running full sync", "error", err)
+		return s.fullSync(ctx, s.tmdb.Uncached())
+	}
+
+	fullEvery := time.Duration(s.config.FullSyncIntervalDays) * 24 * time.Hour
+	if needsFullSync(lastFull, lastIncremental, time.Now(), fullEvery) {
+		return s.fullSync(ctx, s.tmdb.Uncached())
+	}
+	return s.incrementalSync(ctx, latest(lastFull, lastIncremental))
+}
+
+// needsFullSync reports whether a scheduled sync at now must be a full one:
+// no full sync has completed yet, the last one is at least fullEvery old
+// (fullEvery <= 0 disables this), or the last sync of either kind is too old
+// for the TMDB changes window.
+func needsFullSync(lastFull, lastIncremental, now time.Time, fullEvery time.Duration) bool {
+	if lastFull.IsZero() {
+		return true
+	}
+	if fullEvery > 0 && now.Sub(lastFull) >= fullEvery {
+		return true
+	}
+	return now.Sub(latest(lastFull, lastIncremental)) > tmdb.MaxChangesWindow
+}
+
+// lastSyncTimes returns the starts of the last full and incremental syncs;
+// zero for a kind that has never completed.
+func (s *SyncService) lastSyncTimes() (lastFull, lastIncremental time.Time, err error) {
+	lastFull, err = s.syncRepo.GetLastSyncTime(fullSyncKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
 	}
+	lastIncremental, err = s.syncRepo.GetLastSyncTime(incrementalSyncKey)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return lastFull, lastIncremental, nil
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
 
-	s.log.Info("Starting air date sync")
+// fullSync fetches air dates for every show in the library.
+// The caller must have marked the sync as running via beginSync.
+func (s *SyncService) fullSync(ctx context.Context, client *tmdb.Client) error {
+	started := time.Now()
+	s.log.Info("Starting full air date sync")
 
 	// Get all shows in library
-	shows, err := s.showRepo.List()
+	shows, _, err := s.showRepo.List(library.ListOptions{})
+	if err != nil {
+		s.setError(err)
+		return err
+	}
+
+	if err := s.syncShows(ctx, client, shows); err != nil {
+		s.setError(err)
+		return err
+	}
+
+	s.recordSync(fullSyncKey, started)
+	s.setSuccess()
+	s.notifyMissingEpisodes()
+	return nil
+}
+
+// incrementalSync re-fetches seasons only for library shows that TMDB reports
+// as changed since the last sync. Falls back to a full sync if the changes
+// list cannot be fetched. The caller must have marked the sync as running.
+func (s *SyncService) incrementalSync(ctx context.Context, since time.Time) error {
+	started := time.Now()
+
+	if err := s.backoff.wait(ctx); err != nil {
+		s.setError(err)
+		return err
+	}
+	// A stale change list would silently skip shows, so never serve it from the cache
+	client := s.tmdb.Uncached()
+	changed, err := client.GetTVChanges(since, started)
+	if err != nil {
+		var rateErr *tmdb.RateLimitError
+		if errors.As(err, &rateErr) {
+			s.backoff.trigger(rateErr.RetryAfter)
+		}
+		s.log.Warn("Failed to fetch TMDB changes, running full sync", "error", err)
+		return s.fullSync(ctx, client)
+	}
+
+	shows, _, err := s.showRepo.List(library.ListOptions{})
 	if err != nil {
 		s.setError(err)
 		return err
 	}
 
+	changedIDs := make(map[int]struct{}, len(changed))
+	for _, id := range changed {
+		changedIDs[id] = struct{}{}
+	}
+	var stale []*library.Show
+	for _, show := range shows {
+		if _, ok := changedIDs[show.TMDBID]; ok {
+			stale = append(stale, show)
+		}
+	}
+
+	s.log.Info("Starting incremental air date sync",
+		"since", since,
+		"tmdb_changes", len(changed),
+		"changed_shows", len(stale),
+	)
+
+	// Cached seasons of a changed show are stale by definition
+	if err := s.syncShows(ctx, client, stale); err != nil {
+		s.setError(err)
+		return err
+	}
+
+	s.recordSync(incrementalSyncKey, started)
+	s.setSuccess()
+	s.notifyMissingEpisodes()
+	return nil
+}
+
+// syncShows fetches air dates for the given shows. Shows are processed in
+// batches of BatchSize, concurrently within a batch, with BatchDelayMs between
+// batches. Per-show failures are logged; only cancellation is returned.
+func (s *SyncService) syncShows(ctx context.Context, client *tmdb.Client, shows []*library.Show) error {
 	if len(shows) == 0 {
-		s.log.Info("No shows in library, skipping sync")
-		s.setSuccess()
+		s.log.Info("No shows to sync")
 		return nil
 	}
 
-	// Process in batches
-	for i := 0; i < len(shows); i += s.config.BatchSize {
-		select {
-		case <-ctx.Done():
-			s.setError(ctx.Err())
-			return ctx.Err()
-		default:
+	// Process in batches, one worker per show of the batch
+	batchSize := max(s.config.BatchSize, 1)
+	var failed atomic.Int64
+	for i := 0; i < len(shows); i += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		end := i + s.config.BatchSize
-		if end > len(shows) {
-			end = len(shows)
-		}
-		batch := shows[i:end]
+		batch := shows[i:min(i+batchSize, len(shows))]
 
+		var wg sync.WaitGroup
 		for _, show := range batch {
-			if err := s.syncShowAirDates(ctx, show); err != nil {
-				s.log.Warn("Failed to sync show air dates",
-					"show_id", show.ID,
-					"tmdb_id", show.TMDBID,
-					"title", show.Title,
-					"error", err,
-				)
-				// Continue with other shows
-			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.syncShowAirDates(ctx, client, show); err != nil {
+					failed.Add(1)
+					s.log.Warn("Failed to sync show air dates",
+						"show_id", show.ID,
+						"tmdb_id", show.TMDBID,
+						"title", show.Title,
+						"error", err,
+					)
+					// Continue with other shows
+				}
+			}()
 		}
+		wg.Wait()
 
 		// Delay between batches to respect rate limits
-		if i+s.config.BatchSize < len(shows) {
-			time.Sleep(time.Duration(s.config.BatchDelayMs) * time.Millisecond)
+		if i+batchSize < len(shows) {
+			if err := sleepContext(ctx, time.Duration(s.config.BatchDelayMs)*time.Millisecond); err != nil {
+				return err
+			}
 		}
 	}
 
-	s.setSuccess()
-	s.log.Info("Air date sync completed", "shows_processed", len(shows))
+	s.log.Info("Air date sync completed",
+		"shows_processed", len(shows),
+		"shows_failed", failed.Load(),
+	)
 	return nil
 }
 
-func (s *SyncService) syncShowAirDates(ctx context.Context, show *library.Show) error {
+// recordSync stores when a full or incremental sync started, which bounds the
+// changes window of the next incremental sync.
+func (s *SyncService) recordSync(key string, started time.Time) {
+	if err := s.syncRepo.SetLastSyncTime(key, started); err != nil {
+		s.log.Error("Failed to update sync metadata", "key", key, "error", err)
+	}
+}
+
+func (s *SyncService) syncShowAirDates(ctx context.Context, client *tmdb.Client, show *library.Show) error {
 	// Get show with seasons
 	showWithSeasons, err := s.showRepo.GetWithSeasons(show.ID)
 	if err != nil {
@@ -211,7 +389,10 @@ func (s *SyncService) syncShowAirDates(ctx context.Context, show *library.Show)
 		default:
 		}
 
-		tmdbSeason, err := s.tmdb.GetSeason(show.TMDBID, season.SeasonNumber)
+		tmdbSeason, err := s.getSeason(ctx, client, show.TMDBID, season.SeasonNumber)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			s.log.Warn("Failed to fetch season from TMDB",
 				"show_tmdb_id", show.TMDBID,
@@ -242,6 +423,30 @@ func (s *SyncService) syncShowAirDates(ctx context.Context, show *library.Show)
 	return nil
 }
 
+// getSeason fetches a season, waiting out any shared TMDB backoff first.
+// A 429 starts a backoff for all workers and the request is retried after it.
+func (s *SyncService) getSeason(ctx context.Context, client *tmdb.Client, showTMDBID, seasonNumber int) (*tmdb.Season, error) {
+	for attempt := 0; ; attempt++ {
+		if err := s.backoff.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		season, err := client.GetSeason(showTMDBID, seasonNumber)
+
+		var rateErr *tmdb.RateLimitError
+		if !errors.As(err, &rateErr) || attempt >= maxRateLimitRetries {
+			return season, err
+		}
+
+		delay := s.backoff.trigger(rateErr.RetryAfter)
+		s.log.Warn("TMDB rate limit hit, pausing air date sync",
+			"show_tmdb_id", showTMDBID,
+			"season", seasonNumber,
+			"backoff", delay,
+		)
+	}
+}
+
 func (s *SyncService) setError(err error) {
 	s.mu.Lock()
 	s.syncStatus = "error"
@@ -251,7 +456,7 @@ func (s *SyncService) setError(err error) {
 
 func (s *SyncService) setSuccess() {
 	now := time.Now()
-	if err := s.syncRepo.SetLastSyncTime("air_date_sync", now); err != nil {
+	if err := s.syncRepo.SetLastSyncTime(syncKey, now); err != nil {
 		s.log.Error("Failed to update sync metadata", "error", err)
 	}
 
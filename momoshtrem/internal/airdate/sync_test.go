@@ -0,0 +1,35 @@
+package airdate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNeedsFullSync(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	week := 7 * day
+
+	tests := []struct {
+		name            string
+		lastFull        time.Time
+		lastIncremental time.Time
+		fullEvery       time.Duration
+		want            bool
+	}{
+		{"never synced", time.Time{}, time.Time{}, week, true},
+		{"only incremental syncs", time.Time{}, now.Add(-time.Hour), week, true},
+		{"recent full sync", now.Add(-day), time.Time{}, week, false},
+		{"recent incremental after older full", now.Add(-3 * day), now.Add(-day), week, false},
+		{"full sync due despite recent incremental", now.Add(-week), now.Add(-time.Hour), week, true},
+		{"periodic full syncs disabled", now.Add(-10 * day), now.Add(-time.Hour), 0, false},
+		{"outside the changes window", now.Add(-20 * day), now.Add(-15 * day), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsFullSync(tt.lastFull, tt.lastIncremental, now, tt.fullEvery); got != tt.want {
+				t.Errorf("needsFullSync = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// archiveCopyBufferSize keeps torrent reads large enough for sequential readahead to pay off
+const archiveCopyBufferSize = 1 << 20
+
+// archiveEntry is a file included in a show archive
+type archiveEntry struct {
+	vfsPath string    // Path in the library VFS
+	name    string    // Path inside the zip
+	size    int64     // 0 if unknown until opened (torrent subtitles)
+	modTime time.Time // When the file last changed in the library (its assignment time); zero if unknown
+}
+
+// downloadShowZip streams every file under a show's VFS folder (videos, subtitles,
+// metadata) as an uncompressed zip. Videos are read through the torrent service,
+// so this can move many gigabytes and keeps the torrents busy until it finishes.
+func (s *Server) downloadShowZip(c *gin.Context) {
+	if s.showArchiveFS == nil {
+		errorResponse(c, http.StatusForbidden, "Show zip download is disabled (server.show_zip_download)")
+		return
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return
+	}
+
+	root := vfs.TVShowsPath + show.VFSPath()
+	folder := path.Base(root)
+
+	entries, err := collectArchiveEntries(s.showArchiveFS, root, folder)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		handleError(c, err)
+		return
+	}
+	if len(entries) == 0 {
+		errorResponse(c, http.StatusNotFound, "Show has no assigned files")
+		return
+	}
+
+	var totalSize int64
+	for _, e := range entries {
+		totalSize += e.size
+	}
+	slog.Warn("Streaming show archive; this reads every assigned episode through the torrent client",
+		"show_id", id,
+		"title", show.Title,
+		"files", len(entries),
+		"total_bytes", totalSize,
+	)
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": folder + ".zip"}))
+	c.Header("X-Archive-Size", strconv.FormatInt(totalSize, 10)) // Uncompressed payload, for client-side size warnings
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+	buf := make([]byte, archiveCopyBufferSize)
+	for _, e := range entries {
+		if err := c.Request.Context().Err(); err != nil {
+			slog.Info("Show archive download canceled", "show_id", id, "at", e.name)
+			return
+		}
+		// Headers are already sent, so a failure can only truncate the archive
+		if err := writeArchiveEntry(zw, s.showArchiveFS, e, buf); err != nil {
+			slog.Error("Show archive download failed", "show_id", id, "file", e.name, "error", err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		slog.Error("Failed to finish show archive", "show_id", id, "error", err)
+		return
+	}
+
+	slog.Info("Show archive download completed", "show_id", id, "files", len(entries))
+}
+
+// collectArchiveEntries lists the files under a VFS directory in a stable order
+func collectArchiveEntries(fsys vfs.Filesystem, dirPath, zipPrefix string) ([]archiveEntry, error) {
+	children, err := fsys.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var entries []archiveEntry
+	for _, name := range names {
+		child := children[name]
+		childPath := dirPath + "/" + name
+		zipName := zipPrefix + "/" + name
+
+		if child != nil && child.IsDir() {
+			sub, err := collectArchiveEntries(fsys, childPath, zipName)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, sub...)
+			continue
+		}
+
+		entry := archiveEntry{vfsPath: childPath, name: zipName}
+		if child != nil {
+			entry.size = child.Size()
+			if info, err := child.Stat(); err == nil {
+				entry.modTime = info.ModTime()
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// writeArchiveEntry copies one VFS file into the zip without compression
+func writeArchiveEntry(zw *zip.Writer, fsys vfs.Filesystem, e archiveEntry, buf []byte) error {
+	f, err := fsys.Open(e.vfsPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     e.name,
+		Method:   zip.Store, // Video doesn't compress; storing keeps reads sequential and cheap
+		Modified: e.modTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyBuffer(w, f, buf)
+	return err
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// fakeDir is a directory entry in a dirFS listing
+type fakeDir struct{ vfs.File }
+
+func (fakeDir) IsDir() bool { return true }
+
+// dirFS serves fixed directory listings
+type dirFS map[string]map[string]vfs.File
+
+func (d dirFS) Open(string) (vfs.File, error) { return nil, os.ErrNotExist }
+
+func (d dirFS) ReadDir(path string) (map[string]vfs.File, error) {
+	children, ok := d[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return children, nil
+}
+
+func TestCollectArchiveEntriesUsesAssignmentTime(t *testing.T) {
+	assigned := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	fsys := dirFS{
+		"/Show": {"Season 01": fakeDir{}},
+		"/Show/Season 01": {
+			"Show S01E01.mkv": vfs.NewPlaceholderFile("Show S01E01.mkv", 100, &library.TorrentAssignment{CreatedAt: assigned}),
+		},
+	}
+
+	entries, err := collectArchiveEntries(fsys, "/Show", "Show")
+	if err != nil {
+		t.Fatalf("collectArchiveEntries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want the one episode", entries)
+	}
+	e := entries[0]
+	if e.name != "Show/Season 01/Show S01E01.mkv" || e.size != 100 {
+		t.Errorf("entry = %+v", e)
+	}
+	if !e.modTime.Equal(assigned) {
+		t.Errorf("modTime = %v, want the assignment time %v", e.modTime, assigned)
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// LibraryAssignmentResponse is an active assignment with the item it plays
+// and how the identifier matched it
+type LibraryAssignmentResponse struct {
+	AssignmentResponse
+	ItemType string `json:"item_type"`
+	ItemID   int64  `json:"item_id"`
+	Title    string `json:"title"` // Movie or show title
+
+	// Episodes only
+	ShowID        int64 `json:"show_id,omitempty"`
+	SeasonNumber  int   `json:"season,omitempty"`
+	EpisodeNumber int   `json:"episode,omitempty"`
+
+	Confidence       string `json:"confidence,omitempty"`
+	PatternUsed      string `json:"pattern_used,omitempty"`
+	SeasonFromFolder bool   `json:"season_from_folder"`
+	NeedsReview      bool   `json:"needs_review"`
+}
+
+// LibraryAssignmentsResponse lists active assignments, newest first
+type LibraryAssignmentsResponse struct {
+	Assignments []LibraryAssignmentResponse `json:"assignments"`
+}
+
+// listAssignments lists active assignments across the library.
+// ?needs_review=true keeps only matches the identifier flagged as uncertain.
+func (s *Server) listAssignments(c *gin.Context) {
+	needsReview := false
+	if v := c.Query("needs_review"); v != "" {
+		var err error
+		needsReview, err = strconv.ParseBool(v)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "needs_review must be 'true' or 'false'")
+			return
+		}
+	}
+
+	assignments, err := s.assignmentRepo.ListActive(needsReview)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LibraryAssignmentsResponse{Assignments: toLibraryAssignmentResponses(assignments)})
+}
+
+// DuplicateGroupResponse is a set of active assignments from different
+// torrents whose files have the same size and resolution
+type DuplicateGroupResponse struct {
+	FileSize    int64                       `json:"file_size"`
+	Resolution  string                      `json:"resolution,omitempty"`
+	InfoHashes  []string                    `json:"info_hashes"` // Distinct torrents, oldest assignment first
+	Assignments []LibraryAssignmentResponse `json:"assignments"`
+}
+
+// DuplicateAssignmentsResponse lists suspected duplicate groups, largest files first
+type DuplicateAssignmentsResponse struct {
+	Groups []DuplicateGroupResponse `json:"groups"`
+}
+
+// listDuplicateAssignments reports active assignments that likely point at
+// the same file in different torrents, so the redundant torrents can be removed
+func (s *Server) listDuplicateAssignments(c *gin.Context) {
+	groups, err := s.assignmentRepo.ListSuspectedDuplicates()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := DuplicateAssignmentsResponse{Groups: make([]DuplicateGroupResponse, 0, len(groups))}
+	for _, group := range groups {
+		g := DuplicateGroupResponse{
+			FileSize:    group[0].FileSize,
+			Resolution:  group[0].Resolution,
+			Assignments: toLibraryAssignmentResponses(group),
+		}
+		for _, a := range group {
+			if !slices.Contains(g.InfoHashes, a.InfoHash) {
+				g.InfoHashes = append(g.InfoHashes, a.InfoHash)
+			}
+		}
+		resp.Groups = append(resp.Groups, g)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toLibraryAssignmentResponses(assignments []*library.AssignmentWithItem) []LibraryAssignmentResponse {
+	resp := make([]LibraryAssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		resp = append(resp, LibraryAssignmentResponse{
+			AssignmentResponse: *toAssignmentResponse(a.TorrentAssignment),
+			ItemType:           string(a.ItemType),
+			ItemID:             a.ItemID,
+			Title:              a.Title,
+			ShowID:             a.ShowID,
+			SeasonNumber:       a.SeasonNumber,
+			EpisodeNumber:      a.EpisodeNumber,
+			Confidence:         a.Confidence,
+			PatternUsed:        a.PatternUsed,
+			SeasonFromFolder:   a.SeasonFromFolder,
+			NeedsReview:        a.NeedsReview,
+		})
+	}
+	return resp
+}
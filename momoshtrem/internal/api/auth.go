@@ -0,0 +1,106 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/config"
+)
+
+// apiKeyHeader is the header checked before falling back to Authorization: Bearer
+const apiKeyHeader = "X-API-Key"
+
+// exemptAuthPaths can be reached without a key when APIAuth.ExemptStatus is set
+var exemptAuthPaths = map[string]bool{
+	"/api/status":       true,
+	"/api/health":       true,
+	"/api/health/ready": true,
+	"/api/health/live":  true,
+}
+
+// apiKeyAuth wraps the REST router and rejects requests without a valid API key
+type apiKeyAuth struct {
+	next         http.Handler
+	keyHashes    [][sha256.Size]byte
+	exemptStatus bool
+}
+
+// newAPIKeyAuth returns next wrapped with API key checks, or next itself when auth is disabled.
+// An enabled config without keys is rejected by config.Validate.
+func newAPIKeyAuth(next http.Handler, cfg config.APIAuthConfig) http.Handler {
+	if !cfg.Enabled {
+		slog.Info("REST API authentication is disabled")
+		return next
+	}
+
+	auth := &apiKeyAuth{next: next, exemptStatus: cfg.ExemptStatus}
+	for _, key := range cfg.Keys {
+		if key = strings.TrimSpace(key); key != "" {
+			if len(key) < 16 {
+				slog.Warn("REST API key is less than 16 characters, consider using a longer key")
+			}
+			auth.keyHashes = append(auth.keyHashes, sha256.Sum256([]byte(key)))
+		}
+	}
+	return auth
+}
+
+// ServeHTTP implements http.Handler
+func (a *apiKeyAuth) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// CORS preflight requests never carry credentials
+	if r.Method == http.MethodOptions || (a.exemptStatus && exemptAuthPaths[r.URL.Path]) {
+		a.next.ServeHTTP(w, r)
+		return
+	}
+
+	key := requestAPIKey(r)
+	if key == "" {
+		a.unauthorized(w, r, "missing API key")
+		return
+	}
+	if !a.validKey(key) {
+		a.unauthorized(w, r, "invalid API key")
+		return
+	}
+
+	a.next.ServeHTTP(w, r)
+}
+
+// validKey compares the key against every configured key in constant time.
+// Keys are hashed first so the comparison doesn't leak their lengths either.
+func (a *apiKeyAuth) validKey(key string) bool {
+	hash := sha256.Sum256([]byte(key))
+	match := 0
+	for i := range a.keyHashes {
+		match |= subtle.ConstantTimeCompare(hash[:], a.keyHashes[i][:])
+	}
+	return match == 1
+}
+
+func (a *apiKeyAuth) unauthorized(w http.ResponseWriter, r *http.Request, reason string) {
+	slog.Warn("API auth failed",
+		"reason", reason,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"remote_addr", r.RemoteAddr,
+	)
+
+	w.Header().Set("WWW-Authenticate", `Bearer realm="momoshtrem API"`)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusUnauthorized)
+	_, _ = w.Write([]byte(`{"error":{"code":"unauthorized","message":"Unauthorized"}}`))
+}
+
+// requestAPIKey returns the key from X-API-Key or an Authorization: Bearer header
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
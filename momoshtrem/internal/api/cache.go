@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// SetCacheMonitor enables GET /api/cache
+func (s *Server) SetCacheMonitor(m *torrent.CacheMonitor) {
+	s.cacheMonitor = m
+}
+
+// getCacheUsage reports piece cache fill and evictions since startup
+func (s *Server) getCacheUsage(c *gin.Context) {
+	if s.cacheMonitor == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent cache not available")
+		return
+	}
+	c.JSON(http.StatusOK, s.cacheMonitor.Usage())
+}
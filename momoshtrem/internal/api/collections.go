@@ -0,0 +1,327 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// Collection limits
+const (
+	maxCollectionNameLen = 100
+)
+
+// Collection request/response types
+
+type CreateCollectionRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type AddCollectionItemRequest struct {
+	ItemType string `json:"item_type" binding:"required"`
+	ItemID   int64  `json:"item_id" binding:"required"`
+}
+
+type CollectionResponse struct {
+	ID        int64                    `json:"id"`
+	Name      string                   `json:"name"`
+	ItemCount int                      `json:"item_count"`
+	CreatedAt time.Time                `json:"created_at"`
+	Items     []CollectionItemResponse `json:"items,omitempty"`
+}
+
+type CollectionItemResponse struct {
+	ItemType string    `json:"item_type"`
+	ItemID   int64     `json:"item_id"`
+	TMDBID   int       `json:"tmdb_id,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	Year     int       `json:"year,omitempty"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+type CollectionListResponse struct {
+	Collections []CollectionResponse `json:"collections"`
+}
+
+// Collection handlers
+
+func (s *Server) listCollections(c *gin.Context) {
+	if !s.requireCollections(c) {
+		return
+	}
+
+	collections, err := s.collectionRepo.List()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := CollectionListResponse{Collections: make([]CollectionResponse, 0, len(collections))}
+	for _, col := range collections {
+		response.Collections = append(response.Collections, collectionToResponse(col))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) createCollection(c *gin.Context) {
+	if !s.requireCollections(c) {
+		return
+	}
+
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if msg := validateCollectionName(name); msg != "" {
+		errorResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	collection := &library.Collection{Name: name}
+	if err := s.collectionRepo.Create(collection); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, collectionToResponse(collection))
+}
+
+func (s *Server) getCollection(c *gin.Context) {
+	collection, ok := s.parseCollection(c)
+	if !ok {
+		return
+	}
+
+	items, err := s.collectionRepo.GetItems(collection.ID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := collectionToResponse(collection)
+	response.Items = make([]CollectionItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResp := CollectionItemResponse{
+			ItemType: string(item.ItemType),
+			ItemID:   item.ItemID,
+			AddedAt:  item.AddedAt,
+		}
+		switch item.ItemType {
+		case library.ItemTypeMovie:
+			if movie, err := s.movieRepo.GetByID(item.ItemID); err == nil && movie != nil {
+				itemResp.TMDBID, itemResp.Title, itemResp.Year = movie.TMDBID, movie.Title, movie.Year
+			}
+		case library.ItemTypeShow:
+			if show, err := s.showRepo.GetByID(item.ItemID); err == nil && show != nil {
+				itemResp.TMDBID, itemResp.Title, itemResp.Year = show.TMDBID, show.Title, show.Year
+			}
+		}
+		response.Items = append(response.Items, itemResp)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (s *Server) deleteCollection(c *gin.Context) {
+	if !s.requireCollections(c) {
+		return
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	deleted, err := s.collectionRepo.Delete(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if !deleted {
+		errorResponse(c, http.StatusNotFound, "Collection not found")
+		return
+	}
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) addCollectionItem(c *gin.Context) {
+	collection, ok := s.parseCollection(c)
+	if !ok {
+		return
+	}
+
+	var req AddCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemType, ok := parseCollectionItemType(c, req.ItemType)
+	if !ok {
+		return
+	}
+	if !s.collectionItemExists(c, itemType, req.ItemID) {
+		return
+	}
+
+	if err := s.collectionRepo.AddItem(collection.ID, itemType, req.ItemID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Collection folders in the VFS are derived from the collection table
+	if s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) removeCollectionItem(c *gin.Context) {
+	collection, ok := s.parseCollection(c)
+	if !ok {
+		return
+	}
+
+	itemType, ok := parseCollectionItemType(c, c.Param("type"))
+	if !ok {
+		return
+	}
+	itemID, err := strconv.ParseInt(c.Param("item_id"), 10, 64)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "Invalid item ID")
+		return
+	}
+
+	removed, err := s.collectionRepo.RemoveItem(collection.ID, itemType, itemID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if !removed {
+		errorResponse(c, http.StatusNotFound, "Item is not in collection")
+		return
+	}
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireCollections reports whether collections are configured, responding with 503 if not
+func (s *Server) requireCollections(c *gin.Context) bool {
+	if s.collectionRepo == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Collections not configured")
+		return false
+	}
+	return true
+}
+
+// parseCollection parses the :id path parameter and loads the collection
+func (s *Server) parseCollection(c *gin.Context) (*library.Collection, bool) {
+	if !s.requireCollections(c) {
+		return nil, false
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return nil, false
+	}
+
+	collection, err := s.collectionRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return nil, false
+	}
+	if collection == nil {
+		errorResponse(c, http.StatusNotFound, "Collection not found")
+		return nil, false
+	}
+	return collection, true
+}
+
+// collectionItemExists verifies the movie or show exists, responding with 404 if not
+func (s *Server) collectionItemExists(c *gin.Context, itemType library.ItemType, id int64) bool {
+	var exists bool
+	switch itemType {
+	case library.ItemTypeMovie:
+		movie, err := s.movieRepo.GetByID(id)
+		if err != nil {
+			handleError(c, err)
+			return false
+		}
+		exists = movie != nil
+	case library.ItemTypeShow:
+		show, err := s.showRepo.GetByID(id)
+		if err != nil {
+			handleError(c, err)
+			return false
+		}
+		exists = show != nil
+	}
+
+	if !exists {
+		errorResponse(c, http.StatusNotFound, "Item not found")
+		return false
+	}
+	return true
+}
+
+// deleteCollectionItemsFor removes a deleted item from all collections (items have no foreign key)
+func (s *Server) deleteCollectionItemsFor(itemType library.ItemType, id int64) {
+	if s.collectionRepo == nil {
+		return
+	}
+	if err := s.collectionRepo.DeleteForItem(itemType, id); err != nil {
+		slog.Error("Failed to delete collection items", "item_type", itemType, "item_id", id, "error", err)
+	}
+}
+
+// parseCollectionItemType accepts "movie" or "show"
+func parseCollectionItemType(c *gin.Context, value string) (library.ItemType, bool) {
+	switch library.ItemType(value) {
+	case library.ItemTypeMovie, library.ItemTypeShow:
+		return library.ItemType(value), true
+	}
+	errorResponse(c, http.StatusBadRequest, "item_type must be 'movie' or 'show'")
+	return "", false
+}
+
+// validateCollectionName returns an error message if the name can't be used as a VFS folder
+func validateCollectionName(name string) string {
+	if name == "" {
+		return "name is required"
+	}
+	if len(name) > maxCollectionNameLen {
+		return "name is too long"
+	}
+	if folder := library.SanitizeFilename(name); folder == "" || folder == "." || folder == ".." {
+		return "name cannot be used as a folder name"
+	}
+	return ""
+}
+
+func collectionToResponse(col *library.Collection) CollectionResponse {
+	return CollectionResponse{
+		ID:        col.ID,
+		Name:      col.Name,
+		ItemCount: col.ItemCount,
+		CreatedAt: col.CreatedAt,
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
+	"github.com/shapedtime/momoshtrem/internal/subtitle"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// Machine-readable error codes. Clients branch on these; messages may change.
+const (
+	CodeBadRequest         = "bad_request"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeBusy               = "busy"
+	CodeInternal           = "internal_error"
+	CodeNotImplemented     = "not_implemented"
+	CodeServiceUnavailable = "service_unavailable"
+
+	CodeTorrentNotFound           = "torrent_not_found"
+	CodeFileNotFound              = "file_not_found"
+	CodeInvalidMagnet             = "invalid_magnet"
+	CodeMetadataTimeout           = "metadata_timeout"
+	CodeNoFiles                   = "no_files"
+	CodeTorrentServiceUnavailable = "torrent_service_unavailable"
+	CodeTooFewSeeders             = "too_few_seeders"
+
+	CodeShowNotFound        = "show_not_found"
+	CodeMovieNotFound       = "movie_not_found"
+	CodeEpisodeNotFound     = "episode_not_found"
+	CodeNoVideoFiles        = "no_video_files"
+	CodeNoMatchingFile      = "no_matching_file"
+	CodeNotVideoFile        = "not_video_file"
+	CodeCollectionExists    = "collection_exists"
+	CodeNoActiveAssignments = "no_active_assignments"
+	CodeSameTorrent         = "same_torrent"
+
+	CodeSubtitleNotFound       = "subtitle_not_found"
+	CodeOffsetUnsupported      = "offset_unsupported"
+	CodeSubtitleQuotaExhausted = "subtitle_quota_exhausted"
+
+	CodeTMDBNotFound    = "tmdb_not_found"
+	CodeTMDBUnavailable = "tmdb_unavailable"
+	CodeTMDBRateLimited = "tmdb_rate_limited"
+)
+
+// APIError is an error with the HTTP status and code it is reported with.
+// It is serialized as {"error": {"code": ..., "message": ...}}.
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string { return e.Message }
+
+// NewAPIError creates an APIError
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// sentinelErrors maps errors returned by the services to API errors.
+// The first entry whose error matches with errors.Is wins.
+var sentinelErrors = []struct {
+	err    error
+	apiErr *APIError
+}{
+	{torrent.ErrTorrentNotFound, NewAPIError(http.StatusNotFound, CodeTorrentNotFound, "Torrent not found")},
+	{torrent.ErrFileNotFound, NewAPIError(http.StatusBadRequest, CodeFileNotFound, "File not found in torrent")},
+	{torrent.ErrInvalidMagnet, NewAPIError(http.StatusBadRequest, CodeInvalidMagnet, "Invalid magnet URI")},
+	{torrent.ErrMetadataTimeout, NewAPIError(http.StatusGatewayTimeout, CodeMetadataTimeout, "Timed out waiting for torrent metadata")},
+	{torrent.ErrNoFiles, NewAPIError(http.StatusBadRequest, CodeNoFiles, "Torrent contains no files")},
+	{torrent.ErrClientClosed, NewAPIError(http.StatusServiceUnavailable, CodeTorrentServiceUnavailable, "Torrent client is shutting down")},
+
+	{library.ErrShowNotFound, NewAPIError(http.StatusNotFound, CodeShowNotFound, "Show not found")},
+	{library.ErrMovieNotFound, NewAPIError(http.StatusNotFound, CodeMovieNotFound, "Movie not found")},
+	{library.ErrEpisodeNotFound, NewAPIError(http.StatusNotFound, CodeEpisodeNotFound, "Episode not found")},
+	{library.ErrInvalidMagnet, NewAPIError(http.StatusBadRequest, CodeInvalidMagnet, "Invalid magnet URI")},
+	{library.ErrTorrentServiceUnavailable, NewAPIError(http.StatusServiceUnavailable, CodeTorrentServiceUnavailable, "Torrent service not available - Stage 2 required")},
+	{library.ErrNoVideoFiles, NewAPIError(http.StatusBadRequest, CodeNoVideoFiles, "No video files found in torrent")},
+	{library.ErrNoMatchingFile, NewAPIError(http.StatusBadRequest, CodeNoMatchingFile, "No file in torrent matches the episode")},
+	{library.ErrNotVideoFile, NewAPIError(http.StatusBadRequest, CodeNotVideoFile, "File is not a video")},
+	{library.ErrCollectionExists, NewAPIError(http.StatusConflict, CodeCollectionExists, "Collection already exists")},
+	{library.ErrNoActiveAssignments, NewAPIError(http.StatusNotFound, CodeNoActiveAssignments, "No items are assigned to this torrent")},
+	{library.ErrSameTorrent, NewAPIError(http.StatusBadRequest, CodeSameTorrent, "Replacement magnet is the same torrent")},
+
+	{subtitle.ErrNotFound, NewAPIError(http.StatusNotFound, CodeSubtitleNotFound, "Subtitle not found")},
+	{subtitle.ErrOffsetUnsupported, NewAPIError(http.StatusBadRequest, CodeOffsetUnsupported, "Timing offset is only supported for srt and vtt subtitles")},
+
+	{tmdb.ErrNotFound, NewAPIError(http.StatusNotFound, CodeTMDBNotFound, "Not found on TMDB")},
+	{tmdb.ErrUnavailable, NewAPIError(http.StatusBadGateway, CodeTMDBUnavailable, "TMDB is unavailable")},
+}
+
+// toAPIError returns the API error err maps to, or nil for unknown errors
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var rateErr *tmdb.RateLimitError
+	if errors.As(err, &rateErr) {
+		return NewAPIError(http.StatusServiceUnavailable, CodeTMDBRateLimited, "Rate limited by TMDB, retry later")
+	}
+	var seedersErr *torrent.InsufficientSeedersError
+	if errors.As(err, &seedersErr) {
+		return NewAPIError(http.StatusUnprocessableEntity, CodeTooFewSeeders,
+			fmt.Sprintf("Torrent has %d connected seeders, at least %d required (torrent.min_seeders)", seedersErr.Seeders, seedersErr.MinSeeders))
+	}
+	var quotaErr *opensubtitles.QuotaExhaustedError
+	if errors.As(err, &quotaErr) {
+		return NewAPIError(http.StatusTooManyRequests, CodeSubtitleQuotaExhausted, quotaErr.Error())
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			return s.apiErr
+		}
+	}
+	return nil
+}
+
+// handleError responds with the API error err maps to. Unknown errors are
+// logged and reported as a generic 500 so internal details don't reach clients.
+func handleError(c *gin.Context, err error) {
+	apiErr := toAPIError(err)
+	if apiErr == nil {
+		slog.Error("API request failed",
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"error", err,
+		)
+		apiErr = NewAPIError(http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+	apiErrorResponse(c, apiErr)
+}
+
+// apiErrorResponse writes apiErr as the response
+func apiErrorResponse(c *gin.Context, apiErr *APIError) {
+	c.JSON(apiErr.Status, gin.H{"error": apiErr})
+}
+
+// errorResponse responds with a message and the generic code for status
+func errorResponse(c *gin.Context, status int, message string) {
+	apiErrorResponse(c, NewAPIError(status, statusCode(status), message))
+}
+
+// statusCode returns the generic error code for an HTTP status
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeBusy
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+func TestHandleError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{"wrapped sentinel", fmt.Errorf("failed to get torrent: %w", torrent.ErrTorrentNotFound), http.StatusNotFound, CodeTorrentNotFound, "Torrent not found"},
+		{"too few seeders", fmt.Errorf("torrent rejected: %w", &torrent.InsufficientSeedersError{Seeders: 1, MinSeeders: 3}),
+			http.StatusUnprocessableEntity, CodeTooFewSeeders, "Torrent has 1 connected seeders, at least 3 required (torrent.min_seeders)"},
+		{"api error", NewAPIError(http.StatusConflict, CodeConflict, "Already there"), http.StatusConflict, CodeConflict, "Already there"},
+		{"unknown error", errors.New("pq: connection refused to 10.0.0.5"), http.StatusInternalServerError, CodeInternal, "Internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			handleError(c, tt.err)
+
+			var body struct {
+				Error APIError `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid body %q: %v", w.Body.String(), err)
+			}
+			if w.Code != tt.wantStatus || body.Error.Code != tt.wantCode || body.Error.Message != tt.wantMsg {
+				t.Errorf("got %d %q %q, want %d %q %q",
+					w.Code, body.Error.Code, body.Error.Message, tt.wantStatus, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// Health check timing
+const (
+	healthCheckTimeout = 2 * time.Second
+	tmdbHealthCacheTTL = 30 * time.Second // Probes reuse the last TMDB result for this long
+)
+
+// Component status values
+const (
+	componentOK   = "ok"
+	componentDown = "down"
+)
+
+// HealthResponse is the readiness result with per-component status
+type HealthResponse struct {
+	Status     string                     `json:"status"` // "ok" or "unavailable"
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth is the status of one dependency
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Cached    bool   `json:"cached,omitempty"` // Result reused from an earlier probe
+}
+
+// cachedHealthCheck remembers the last result of a slow external check.
+// Concurrent probes wait for the in-flight check instead of starting their own.
+type cachedHealthCheck struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	checked time.Time
+	result  ComponentHealth
+}
+
+func (h *cachedHealthCheck) get(check func() ComponentHealth) ComponentHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.checked.IsZero() && time.Since(h.checked) < h.ttl {
+		result := h.result
+		result.Cached = true
+		return result
+	}
+	h.result = check()
+	h.checked = time.Now()
+	return h.result
+}
+
+// SetHealthChecks configures the dependencies verified by GET /api/health.
+// The TMDB check is optional since streaming keeps working while TMDB is down.
+func (s *Server) SetHealthChecks(db *library.DB, checkTMDB bool) {
+	s.healthDB = db
+	if checkTMDB && s.tmdbClient != nil {
+		s.tmdbHealth = &cachedHealthCheck{ttl: tmdbHealthCacheTTL}
+	}
+}
+
+// getLiveness reports that the process is serving requests; it checks no dependencies
+// GET /api/health/live
+func (s *Server) getLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// getReadiness checks the database, torrent client and (optionally) TMDB,
+// returning 503 if any of them is down
+// GET /api/health, GET /api/health/ready
+func (s *Server) getReadiness(c *gin.Context) {
+	checks := make(map[string]func() ComponentHealth)
+	if s.healthDB != nil {
+		checks["database"] = func() ComponentHealth {
+			return timedCheck(func() error {
+				ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+				defer cancel()
+				return s.healthDB.PingContext(ctx)
+			})
+		}
+	}
+	if s.torrentService != nil {
+		checks["torrent"] = func() ComponentHealth {
+			return timedCheck(s.torrentService.Healthy)
+		}
+	}
+	if s.tmdbHealth != nil {
+		checks["tmdb"] = func() ComponentHealth {
+			return s.tmdbHealth.get(func() ComponentHealth {
+				return timedCheck(func() error {
+					// Detached from the probe so a cancelled request doesn't cache a failure
+					ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+					defer cancel()
+					return s.tmdbClient.Ping(ctx)
+				})
+			})
+		}
+	}
+
+	response := HealthResponse{
+		Status:     "ok",
+		Components: make(map[string]ComponentHealth, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := check()
+			mu.Lock()
+			response.Components[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	for _, component := range response.Components {
+		if component.Status != componentOK {
+			response.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+
+	c.JSON(status, response)
+}
+
+// timedCheck runs a check and records its latency
+func timedCheck(check func() error) ComponentHealth {
+	start := time.Now()
+	err := check()
+	result := ComponentHealth{
+		Status:    componentOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = componentDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCachedHealthCheck(t *testing.T) {
+	h := &cachedHealthCheck{ttl: time.Hour}
+	calls := 0
+	check := func() ComponentHealth {
+		calls++
+		return timedCheck(func() error { return errors.New("unreachable") })
+	}
+
+	first := h.get(check)
+	if first.Status != componentDown || first.Error != "unreachable" || first.Cached {
+		t.Fatalf("first result = %+v", first)
+	}
+	second := h.get(check)
+	if !second.Cached || second.Status != componentDown {
+		t.Errorf("second result = %+v, want cached failure", second)
+	}
+	if calls != 1 {
+		t.Errorf("check ran %d times, want 1", calls)
+	}
+
+	h.checked = time.Now().Add(-2 * time.Hour) // Expire the cached result
+	h.get(check)
+	if calls != 2 {
+		t.Errorf("check ran %d times after expiry, want 2", calls)
+	}
+}
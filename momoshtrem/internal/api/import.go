@@ -0,0 +1,194 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/service"
+)
+
+// maxImportIDs bounds the TMDB IDs of one import request
+const maxImportIDs = 500
+
+// importConcurrency bounds the items of an import fetched from TMDB at once
+const importConcurrency = 4
+
+// Import outcomes of an item
+const (
+	importCreated  = "created"
+	importExisting = "existing"
+	importFailed   = "failed"
+
+	importNotAttempted = "not_attempted" // The client went away before the item started
+)
+
+// ImportRequest lists TMDB IDs to add to the library
+type ImportRequest struct {
+	MovieTMDBIDs []int `json:"movie_tmdb_ids"`
+	ShowTMDBIDs  []int `json:"show_tmdb_ids"` // All seasons are added
+}
+
+// ImportItemResult is the outcome for one TMDB ID
+type ImportItemResult struct {
+	TMDBID int    `json:"tmdb_id"`
+	Status string `json:"status"`       // "created", "existing", "failed" or "not_attempted"
+	ID     int64  `json:"id,omitempty"` // Library ID unless failed
+	Title  string `json:"title,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportResponse reports every requested ID, in request order without duplicates
+type ImportResponse struct {
+	Movies       []ImportItemResult `json:"movies"`
+	Shows        []ImportItemResult `json:"shows"`
+	Created      int                `json:"created"`
+	Existing     int                `json:"existing"`
+	Failed       int                `json:"failed"`
+	NotAttempted int                `json:"not_attempted"` // Left out because the request was cancelled
+}
+
+// importLibrary adds movies and shows by TMDB ID. Items are created
+// independently, so a bad ID only fails its own entry.
+func (s *Server) importLibrary(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movieIDs, showIDs := uniqueIDs(req.MovieTMDBIDs), uniqueIDs(req.ShowTMDBIDs)
+	total := len(movieIDs) + len(showIDs)
+	if total == 0 {
+		errorResponse(c, http.StatusBadRequest, "movie_tmdb_ids or show_tmdb_ids is required")
+		return
+	}
+	if total > maxImportIDs {
+		errorResponse(c, http.StatusBadRequest, fmt.Sprintf("at most %d IDs can be imported at once", maxImportIDs))
+		return
+	}
+	for _, id := range append(append([]int{}, movieIDs...), showIDs...) {
+		if id <= 0 {
+			errorResponse(c, http.StatusBadRequest, fmt.Sprintf("invalid TMDB ID: %d", id))
+			return
+		}
+	}
+
+	resp := ImportResponse{
+		Movies: make([]ImportItemResult, len(movieIDs)),
+		Shows:  make([]ImportItemResult, len(showIDs)),
+	}
+	ctx := c.Request.Context()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, importConcurrency)
+	run := func(result *ImportItemResult, add func() (int64, string, bool, error)) {
+		// Stop dispatching once the request is cancelled; items already
+		// running finish, the rest are reported as not attempted
+		if ctx.Err() != nil {
+			result.Status = importNotAttempted
+			return
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			result.Status = importNotAttempted
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			id, title, created, err := add()
+			if err != nil {
+				result.Status, result.Error = importFailed, importError(result.TMDBID, err)
+				return
+			}
+			result.ID, result.Title, result.Status = id, title, importExisting
+			if created {
+				result.Status = importCreated
+			}
+		}()
+	}
+
+	for i, tmdbID := range movieIDs {
+		resp.Movies[i].TMDBID = tmdbID
+		run(&resp.Movies[i], func() (int64, string, bool, error) {
+			movie, created, err := s.addMovie(tmdbID)
+			if err != nil {
+				return 0, "", false, err
+			}
+			return movie.ID, movie.Title, created, nil
+		})
+	}
+	for i, tmdbID := range showIDs {
+		resp.Shows[i].TMDBID = tmdbID
+		run(&resp.Shows[i], func() (int64, string, bool, error) {
+			result, err := s.showService.Create(ctx, service.CreateShowInput{TMDBID: tmdbID})
+			if err != nil {
+				return 0, "", false, err
+			}
+			for _, se := range result.SeasonErrors {
+				slog.Warn("Season creation error", "error", se.Error())
+			}
+			return result.Show.ID, result.Show.Title, !result.IsExisting, nil
+		})
+	}
+	wg.Wait()
+
+	for _, results := range [][]ImportItemResult{resp.Movies, resp.Shows} {
+		for _, r := range results {
+			switch r.Status {
+			case importCreated:
+				resp.Created++
+			case importExisting:
+				resp.Existing++
+			case importNotAttempted:
+				resp.NotAttempted++
+			default:
+				resp.Failed++
+			}
+		}
+	}
+
+	slog.Info("Library import finished",
+		"movies", len(movieIDs),
+		"shows", len(showIDs),
+		"created", resp.Created,
+		"existing", resp.Existing,
+		"failed", resp.Failed,
+		"not_attempted", resp.NotAttempted,
+	)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// importError is the message reported for an item that failed to import.
+// Unknown errors are logged and reported generically, like handleError does.
+func importError(tmdbID int, err error) string {
+	if apiErr := toAPIError(err); apiErr != nil {
+		return apiErr.Message
+	}
+	slog.Error("Import item failed", "tmdb_id", tmdbID, "error", err)
+	return "Internal server error"
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestImportLibraryValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ids := make([]string, maxImportIDs+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i + 1)
+	}
+	tooMany := "[" + strings.Join(ids, ",") + "]"
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", `{}`},
+		{"invalid id", `{"movie_tmdb_ids": [550, -1]}`},
+		{"too many", `{"show_tmdb_ids": ` + tooMany + `}`},
+		{"invalid after dedupe", `{"movie_tmdb_ids": [550, 550], "show_tmdb_ids": [0]}`},
+	}
+	// Validation happens before any repository or TMDB access
+	s := &Server{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/import", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			s.importLibrary(c)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (body %s)", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	if got := uniqueIDs([]int{3, 1, 3, 2, 1}); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("uniqueIDs = %v, want [3 1 2]", got)
+	}
+}
+
+func TestImportLibraryStopsWhenCancelled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Nothing is dispatched once the client has gone, so no repository or
+	// TMDB client is needed
+	s := &Server{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/import",
+		strings.NewReader(`{"movie_tmdb_ids": [550, 603], "show_tmdb_ids": [1399]}`)).WithContext(ctx)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	s.importLibrary(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+	}
+	var resp ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, r := range append(resp.Movies, resp.Shows...) {
+		if r.Status != importNotAttempted {
+			t.Errorf("TMDB ID %d status = %q, want %q", r.TMDBID, r.Status, importNotAttempted)
+		}
+	}
+	if len(resp.Movies) != 2 || len(resp.Shows) != 1 || resp.NotAttempted != 3 || resp.Failed != 0 {
+		t.Errorf("response = %+v, want all 3 IDs not attempted", resp)
+	}
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -9,7 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shapedtime/momoshtrem/internal/identify"
 	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/notify"
 	"github.com/shapedtime/momoshtrem/internal/service"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
 	"github.com/shapedtime/momoshtrem/internal/torrent"
 )
 
@@ -23,10 +27,17 @@ type MovieResponse struct {
 	TMDBID        int    `json:"tmdb_id"`
 	Title         string `json:"title"`
 	Year          int    `json:"year"`
+	PosterURL     string `json:"poster_url,omitempty"`
+	BackdropURL   string `json:"backdrop_url,omitempty"`
 	HasAssignment bool   `json:"has_assignment"`
 	Assignment    *AssignmentResponse `json:"assignment,omitempty"`
 }
 
+type MovieListResponse struct {
+	Items []MovieResponse `json:"items"`
+	Total int             `json:"total"` // All movies, regardless of limit/offset
+}
+
 type AssignmentResponse struct {
 	ID         int64  `json:"id"`
 	InfoHash   string `json:"info_hash"`
@@ -34,6 +45,20 @@ type AssignmentResponse struct {
 	FileSize   int64  `json:"file_size"`
 	Resolution string `json:"resolution,omitempty"`
 	Source     string `json:"source,omitempty"`
+	BitDepth   string `json:"bit_depth,omitempty"`   // 8bit, 10bit, 12bit when the release name says
+	Codec      string `json:"codec,omitempty"`       // x264, x265, HEVC, AV1 when the release name says
+	AudioCodec string `json:"audio_codec,omitempty"` // AAC, AC3, E-AC3, DTS, TrueHD when the release name says
+	HDR        bool   `json:"hdr"`
+
+	// Ordered parts of a multi-part movie (CD1, CD2, ...) served as one file
+	PartPaths []string `json:"part_paths,omitempty"`
+
+	// File on local disk read instead of a torrent; info_hash is empty
+	LocalPath string `json:"local_path,omitempty"`
+
+	// Estimated from the container header after assignment; null until probed
+	BitrateBps     *int64 `json:"bitrate_bps"`
+	RuntimeSeconds *int   `json:"runtime_seconds"`
 }
 
 // Show request/response types
@@ -47,9 +72,18 @@ type ShowResponse struct {
 	TMDBID  int              `json:"tmdb_id"`
 	Title   string           `json:"title"`
 	Year    int              `json:"year"`
+
+	PosterURL   string `json:"poster_url,omitempty"`
+	BackdropURL string `json:"backdrop_url,omitempty"`
+
 	Seasons []SeasonResponse `json:"seasons,omitempty"`
 }
 
+type ShowListResponse struct {
+	Items []ShowResponse `json:"items"`
+	Total int            `json:"total"` // All shows, regardless of limit/offset
+}
+
 type SeasonResponse struct {
 	ID           int64             `json:"id"`
 	SeasonNumber int               `json:"season_number"`
@@ -60,6 +94,7 @@ type EpisodeResponse struct {
 	ID            int64               `json:"id"`
 	EpisodeNumber int                 `json:"episode_number"`
 	Name          string              `json:"name"`
+	AirDate       string              `json:"air_date,omitempty"` // YYYY-MM-DD, empty if unknown
 	HasAssignment bool                `json:"has_assignment"`
 	Assignment    *AssignmentResponse `json:"assignment,omitempty"`
 }
@@ -67,6 +102,19 @@ type EpisodeResponse struct {
 // Assignment request types - auto-detection API
 type AssignTorrentRequest struct {
 	MagnetURI string `json:"magnet_uri" binding:"required"`
+
+	// Movie assignments only: how to choose between several video files
+	FileSelectionPolicy string `json:"file_selection_policy,omitempty"` // largest (default), highest-resolution, prefer-codec
+	PreferCodec         string `json:"prefer_codec,omitempty"`          // For prefer-codec, defaults to HEVC
+}
+
+// AssignFileRequest picks a file of an already-added torrent for an episode
+type AssignFileRequest struct {
+	InfoHash   string `json:"info_hash" binding:"required"`
+	FilePath   string `json:"file_path" binding:"required"`
+	Resolution string `json:"resolution,omitempty"` // Parsed from the file name when omitted
+	Source     string `json:"source,omitempty"`
+	MagnetURI  string `json:"magnet_uri,omitempty"` // Defaults to the torrent's existing magnet
 }
 
 // Movie assignment response
@@ -82,9 +130,17 @@ type ShowAssignmentResponse struct {
 	Summary   service.AssignmentSummary   `json:"summary"`
 	Matched   []service.MatchedAssignment `json:"matched"`
 	Unmatched []service.UnmatchedAssignment `json:"unmatched,omitempty"`
+	DryRun    bool                        `json:"dry_run,omitempty"` // Nothing was assigned
 	Error     string                      `json:"error,omitempty"`
 }
 
+// Episode assignment response
+type EpisodeAssignmentResponse struct {
+	Success    bool                       `json:"success"`
+	Assignment *service.MatchedAssignment `json:"assignment,omitempty"`
+	Error      string                     `json:"error,omitempty"`
+}
+
 // parseID parses and validates an ID parameter
 func parseID(c *gin.Context, param string) (int64, bool) {
 	id, err := strconv.ParseInt(c.Param(param), 10, 64)
@@ -99,24 +155,87 @@ func parseID(c *gin.Context, param string) (int64, bool) {
 	return id, true
 }
 
+// maxListLimit caps the page size of list endpoints
+const maxListLimit = 500
+
+// parseListOptions parses ?limit=&offset=&sort=title|year|created_at&order=asc|desc
+// and the optional &has_assignment=true|false filter. Without a limit the whole
+// list is returned.
+func parseListOptions(c *gin.Context) (library.ListOptions, bool) {
+	var opts library.ListOptions
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			errorResponse(c, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxListLimit))
+			return opts, false
+		}
+		opts.Limit = limit
+	}
+
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			errorResponse(c, http.StatusBadRequest, "offset must be a non-negative integer")
+			return opts, false
+		}
+		opts.Offset = offset
+	}
+
+	opts.Sort = c.DefaultQuery("sort", library.SortByTitle)
+	if !library.ValidSortField(opts.Sort) {
+		errorResponse(c, http.StatusBadRequest, "sort must be 'title', 'year' or 'created_at'")
+		return opts, false
+	}
+
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		opts.Desc = true
+	default:
+		errorResponse(c, http.StatusBadRequest, "order must be 'asc' or 'desc'")
+		return opts, false
+	}
+
+	if v := c.Query("has_assignment"); v != "" {
+		hasAssignment, err := strconv.ParseBool(v)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "has_assignment must be 'true' or 'false'")
+			return opts, false
+		}
+		opts.HasAssignment = &hasAssignment
+	}
+
+	return opts, true
+}
+
 // Movie handlers
 
 func (s *Server) listMovies(c *gin.Context) {
-	movies, err := s.movieRepo.List()
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	movies, total, err := s.movieRepo.List(opts)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
-	// Get assignments for each movie
-	response := make([]MovieResponse, len(movies))
+	ids := make([]int64, len(movies))
 	for i, movie := range movies {
-		assignment, err := s.assignmentRepo.GetActiveForItem(library.ItemTypeMovie, movie.ID)
-		if err != nil {
-			errorResponse(c, http.StatusInternalServerError, "Failed to get assignment for movie")
-			return
-		}
-		response[i] = toMovieResponse(movie, assignment)
+		ids[i] = movie.ID
+	}
+	assignments, err := s.assignmentRepo.GetActiveForItems(library.ItemTypeMovie, ids)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "Failed to get assignments for movies")
+		return
+	}
+
+	response := MovieListResponse{Items: make([]MovieResponse, len(movies)), Total: total}
+	for i, movie := range movies {
+		response.Items[i] = toMovieResponse(movie, assignments[movie.ID])
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -129,36 +248,51 @@ func (s *Server) createMovie(c *gin.Context) {
 		return
 	}
 
-	// Check if already exists
-	existing, err := s.movieRepo.GetByTMDBID(req.TMDBID)
+	movie, created, err := s.addMovie(req.TMDBID)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, toMovieResponse(movie, nil))
+}
+
+// addMovie adds a movie from TMDB, or returns the library's existing one
+// with created false
+func (s *Server) addMovie(tmdbID int) (movie *library.Movie, created bool, err error) {
+	// Check if already exists
+	existing, err := s.movieRepo.GetByTMDBID(tmdbID)
+	if err != nil {
+		return nil, false, err
+	}
 	if existing != nil {
-		c.JSON(http.StatusOK, toMovieResponse(existing, nil))
-		return
+		return existing, false, nil
 	}
 
 	// Fetch from TMDB
-	tmdbMovie, err := s.tmdbClient.GetMovie(req.TMDBID)
+	tmdbMovie, err := s.tmdbClient.GetMovie(tmdbID)
 	if err != nil {
-		errorResponse(c, http.StatusBadRequest, "Failed to fetch from TMDB: "+err.Error())
-		return
+		return nil, false, err
 	}
 
-	movie := &library.Movie{
-		TMDBID: tmdbMovie.ID,
-		Title:  tmdbMovie.Title,
-		Year:   tmdbMovie.Year(),
+	movie = &library.Movie{
+		TMDBID:   tmdbMovie.ID,
+		Title:    tmdbMovie.Title,
+		Year:     tmdbMovie.Year(),
+		Overview: tmdbMovie.Overview,
+
+		PosterURL:   tmdb.ImageURL(tmdbMovie.PosterPath),
+		BackdropURL: tmdb.ImageURL(tmdbMovie.BackdropPath),
 	}
 
 	if err := s.movieRepo.Create(movie); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, false, err
 	}
-
-	c.JSON(http.StatusCreated, toMovieResponse(movie, nil))
+	return movie, true, nil
 }
 
 func (s *Server) getMovie(c *gin.Context) {
@@ -169,7 +303,7 @@ func (s *Server) getMovie(c *gin.Context) {
 
 	movie, err := s.movieRepo.GetByID(id)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 	if movie == nil {
@@ -204,9 +338,16 @@ func (s *Server) deleteMovie(c *gin.Context) {
 	}
 
 	if err := s.movieRepo.Delete(id); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
+	s.deleteItemMetadataFor(library.ItemTypeMovie, id)
+	s.deleteCollectionItemsFor(library.ItemTypeMovie, id)
+	if s.watchStatusRepo != nil {
+		if _, err := s.watchStatusRepo.Delete(library.ItemTypeMovie, id); err != nil {
+			slog.Error("Failed to delete watch status", "movie_id", id, "error", err)
+		}
+	}
 
 	// Update VFS tree immediately
 	if movie != nil && s.treeUpdater != nil {
@@ -228,10 +369,16 @@ func (s *Server) assignMovieTorrent(c *gin.Context) {
 		return
 	}
 
+	policy, err := identify.ParseFileSelectionPolicy(req.FileSelectionPolicy)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Verify movie exists
 	movie, err := s.movieRepo.GetByID(id)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 	if movie == nil {
@@ -253,25 +400,43 @@ func (s *Server) assignMovieTorrent(c *gin.Context) {
 	}
 
 	// Add torrent and get file list
-	torrentInfo, err := s.torrentService.AddTorrent(req.MagnetURI)
+	torrentInfo, err := s.torrentService.AddTorrent(c.Request.Context(), req.MagnetURI)
+	if errors.Is(err, torrent.ErrMetadataPending) {
+		resolvingResponse(c, req.MagnetURI)
+		return
+	}
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, "Failed to add torrent: "+err.Error())
+		handleError(c, err)
+		return
+	}
+	if err := s.torrentService.WaitForSeeders(c.Request.Context(), torrentInfo.InfoHash, s.minSeeders); err != nil {
+		handleError(c, err)
 		return
 	}
 
-	// Find the best movie file (largest video file)
-	result := identify.FindMovieFile(torrentInfo.Files)
+	// Find the best movie file according to the selection policy
+	result := s.identifier.SelectMovieFile(torrentInfo.Files, identify.FileSelection{
+		Policy:      policy,
+		PreferCodec: req.PreferCodec,
+	})
 	if !result.Found {
 		errorResponse(c, http.StatusBadRequest, "No video files found in torrent")
 		return
 	}
 
-	// Log other files if any
-	if len(result.OtherFiles) > 0 {
-		slog.Info("Movie torrent has multiple video files, using largest",
+	// Log the ranking so the choice can be audited
+	if len(result.Candidates) > 1 {
+		ranked := make([]string, len(result.Candidates))
+		for i, cand := range result.Candidates {
+			ranked[i] = fmt.Sprintf("%s [%s %s %d bytes]",
+				cand.FilePath, cand.Quality.Resolution, cand.Quality.Codec, cand.FileSize)
+		}
+		slog.Info("Movie torrent has multiple video files",
 			"movie_id", id,
+			"policy", policy,
 			"selected", result.FilePath,
-			"others", result.OtherFiles,
+			"parts", result.Parts,
+			"ranked", ranked,
 		)
 	}
 
@@ -284,18 +449,34 @@ func (s *Server) assignMovieTorrent(c *gin.Context) {
 		FileSize:   result.FileSize,
 		Resolution: result.Quality.Resolution,
 		Source:     result.Quality.Source,
+		BitDepth:   result.Quality.BitDepth,
+		Codec:      result.Quality.Codec,
+		AudioCodec: result.Quality.AudioCodec,
+		HDR:        result.Quality.HDR,
+		PartPaths:  result.Parts,
 	}
 
 	if err := s.assignmentRepo.Create(assignment); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
+	s.mediaProber.Probe(assignment)
 
 	// Update VFS tree immediately
 	if s.treeUpdater != nil {
 		s.treeUpdater.AddMovieToTree(movie, assignment)
 	}
 
+	s.notifier.Notify(notify.EventTorrentAssigned, AssignmentWebhook{
+		ItemType: string(library.ItemTypeMovie),
+		ItemID:   id,
+		Title:    movie.Title,
+		Year:     movie.Year,
+		InfoHash: infoHash,
+		FilePath: assignment.FilePath,
+		Matched:  1,
+	})
+
 	c.JSON(http.StatusCreated, MovieAssignmentResponse{
 		Success:    true,
 		Assignment: toAssignmentResponse(assignment),
@@ -315,7 +496,7 @@ func (s *Server) unassignMovieTorrent(c *gin.Context) {
 	}
 
 	if err := s.assignmentRepo.DeactivateForItem(library.ItemTypeMovie, id); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -330,19 +511,27 @@ func (s *Server) unassignMovieTorrent(c *gin.Context) {
 // Show handlers
 
 func (s *Server) listShows(c *gin.Context) {
-	shows, err := s.showRepo.List()
+	opts, ok := parseListOptions(c)
+	if !ok {
+		return
+	}
+
+	shows, total, err := s.showRepo.List(opts)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
-	response := make([]ShowResponse, len(shows))
+	response := ShowListResponse{Items: make([]ShowResponse, len(shows)), Total: total}
 	for i, show := range shows {
-		response[i] = ShowResponse{
+		response.Items[i] = ShowResponse{
 			ID:     show.ID,
 			TMDBID: show.TMDBID,
 			Title:  show.Title,
 			Year:   show.Year,
+
+			PosterURL:   show.PosterURL,
+			BackdropURL: show.BackdropURL,
 		}
 	}
 
@@ -361,7 +550,7 @@ func (s *Server) createShow(c *gin.Context) {
 		Seasons: req.Seasons,
 	})
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -385,7 +574,7 @@ func (s *Server) getShow(c *gin.Context) {
 
 	show, err := s.showRepo.GetWithSeasonsAndEpisodes(id)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 	if show == nil {
@@ -425,12 +614,14 @@ func (s *Server) deleteShow(c *gin.Context) {
 	// Store show info for tree update before deletion
 	var showTitle string
 	var showYear int
+	var episodeIDs []int64
 	if show != nil {
 		showTitle = show.Title
 		showYear = show.Year
 
 		for _, season := range show.Seasons {
 			for _, ep := range season.Episodes {
+				episodeIDs = append(episodeIDs, ep.ID)
 				if err := s.assignmentRepo.DeactivateForItem(library.ItemTypeEpisode, ep.ID); err != nil {
 					slog.Error("Failed to deactivate assignment for episode", "episode_id", ep.ID, "error", err)
 				}
@@ -439,9 +630,12 @@ func (s *Server) deleteShow(c *gin.Context) {
 	}
 
 	if err := s.showRepo.Delete(id); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
+	s.deleteItemMetadataFor(library.ItemTypeShow, id)
+	s.deleteCollectionItemsFor(library.ItemTypeShow, id)
+	s.deleteEpisodeWatchStatus(episodeIDs)
 
 	// Update VFS tree immediately
 	if showTitle != "" && s.treeUpdater != nil {
@@ -451,6 +645,75 @@ func (s *Server) deleteShow(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// deleteEpisodeWatchStatus drops the playback progress of deleted episodes
+func (s *Server) deleteEpisodeWatchStatus(episodeIDs []int64) {
+	if s.watchStatusRepo == nil {
+		return
+	}
+	if err := s.watchStatusRepo.DeleteForItems(library.ItemTypeEpisode, episodeIDs); err != nil {
+		slog.Error("Failed to delete episode watch status", "episodes", len(episodeIDs), "error", err)
+	}
+}
+
+// deleteSeason removes one season of a show, leaving its other seasons intact
+func (s *Server) deleteSeason(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+	seasonNumber, err := strconv.Atoi(c.Param("num"))
+	if err != nil || seasonNumber < 0 {
+		errorResponse(c, http.StatusBadRequest, "Invalid season number")
+		return
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "Failed to get show")
+		return
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return
+	}
+
+	season, err := s.showRepo.GetSeason(id, seasonNumber)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "Failed to get season")
+		return
+	}
+	if season == nil {
+		errorResponse(c, http.StatusNotFound, "Season not found")
+		return
+	}
+
+	episodes, err := s.showRepo.GetEpisodes(season.ID)
+	if err != nil {
+		errorResponse(c, http.StatusInternalServerError, "Failed to get season episodes")
+		return
+	}
+	episodeIDs := make([]int64, 0, len(episodes))
+	for _, ep := range episodes {
+		episodeIDs = append(episodeIDs, ep.ID)
+		if err := s.assignmentRepo.DeactivateForItem(library.ItemTypeEpisode, ep.ID); err != nil {
+			slog.Error("Failed to deactivate assignment for episode", "episode_id", ep.ID, "error", err)
+		}
+	}
+
+	if err := s.showRepo.DeleteSeason(id, seasonNumber); err != nil {
+		handleError(c, err)
+		return
+	}
+	s.deleteEpisodeWatchStatus(episodeIDs)
+
+	// Update VFS tree immediately
+	if s.treeUpdater != nil {
+		s.treeUpdater.RemoveSeasonFromTree(show.Title, show.Year, seasonNumber)
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // Show assignment handler - auto-detects episodes from torrent
 
 func (s *Server) assignShowTorrent(c *gin.Context) {
@@ -465,21 +728,46 @@ func (s *Server) assignShowTorrent(c *gin.Context) {
 		return
 	}
 
-	result, err := s.showAssignmentService.AssignTorrent(c.Request.Context(), id, req.MagnetURI)
+	// ?dry_run=true previews the matches without assigning anything
+	dryRun := c.Query("dry_run") == "true"
+	assign := s.showAssignmentService.AssignTorrent
+	if dryRun {
+		assign = s.previewShowTorrent
+	}
+
+	result, err := assign(c.Request.Context(), id, req.MagnetURI)
+	if errors.Is(err, torrent.ErrMetadataPending) {
+		resolvingResponse(c, req.MagnetURI)
+		return
+	}
 	if err != nil {
-		switch {
-		case errors.Is(err, library.ErrShowNotFound):
-			errorResponse(c, http.StatusNotFound, "Show not found")
-		case errors.Is(err, library.ErrInvalidMagnet):
-			errorResponse(c, http.StatusBadRequest, "Invalid magnet URI")
-		case errors.Is(err, library.ErrTorrentServiceUnavailable):
-			errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available - Stage 2 required")
-		default:
-			errorResponse(c, http.StatusInternalServerError, err.Error())
-		}
+		handleError(c, err)
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, ShowAssignmentResponse{
+			Success:   true,
+			DryRun:    true,
+			Summary:   result.Summary,
+			Matched:   result.Matched,
+			Unmatched: result.Unmatched,
+		})
 		return
 	}
 
+	event := AssignmentWebhook{
+		ItemType:  string(library.ItemTypeShow),
+		ItemID:    id,
+		InfoHash:  torrent.ExtractInfoHash(req.MagnetURI),
+		Matched:   result.Summary.Matched,
+		Unmatched: result.Summary.Unmatched,
+	}
+	if show, err := s.showRepo.GetByID(id); err == nil && show != nil {
+		event.Title, event.Year = show.Title, show.Year
+	}
+	s.notifier.Notify(notify.EventTorrentAssigned, event)
+
 	c.JSON(http.StatusCreated, ShowAssignmentResponse{
 		Success:   true,
 		Summary:   result.Summary,
@@ -488,8 +776,92 @@ func (s *Server) assignShowTorrent(c *gin.Context) {
 	})
 }
 
+// previewShowTorrent previews a show assignment. A torrent loaded for the
+// preview is released like an inspected one, unless it gets assigned.
+func (s *Server) previewShowTorrent(ctx context.Context, showID int64, magnetURI string) (*service.ShowAssignmentResult, error) {
+	infoHash := torrent.ExtractInfoHash(magnetURI)
+	if s.torrentService == nil || infoHash == "" {
+		return s.showAssignmentService.PreviewTorrent(ctx, showID, magnetURI)
+	}
+	_, err := s.torrentService.GetTorrent(infoHash)
+	alreadyLoaded := err == nil
+
+	result, err := s.showAssignmentService.PreviewTorrent(ctx, showID, magnetURI)
+	if err == nil || errors.Is(err, torrent.ErrMetadataPending) {
+		s.scheduleInspectRelease(infoHash, alreadyLoaded)
+	}
+	return result, err
+}
+
 // Episode handlers
 
+func (s *Server) assignEpisodeTorrent(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req AssignTorrentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assignment, err := s.showAssignmentService.AssignEpisodeTorrent(c.Request.Context(), id, req.MagnetURI)
+	if errors.Is(err, torrent.ErrMetadataPending) {
+		resolvingResponse(c, req.MagnetURI)
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, EpisodeAssignmentResponse{
+		Success:    true,
+		Assignment: assignment,
+	})
+}
+
+// assignEpisodeFile assigns a specific file of a loaded torrent, bypassing identification
+func (s *Server) assignEpisodeFile(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req AssignFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	assignment, err := s.showAssignmentService.AssignEpisodeFile(c.Request.Context(), id, service.FileAssignment{
+		InfoHash:   req.InfoHash,
+		FilePath:   req.FilePath,
+		Resolution: req.Resolution,
+		Source:     req.Source,
+		MagnetURI:  req.MagnetURI,
+	})
+	if err != nil {
+		// Same codes as the central mapping, with messages specific to this endpoint
+		switch {
+		case errors.Is(err, library.ErrInvalidMagnet):
+			apiErrorResponse(c, NewAPIError(http.StatusBadRequest, CodeInvalidMagnet, "magnet_uri does not match info_hash"))
+		case errors.Is(err, torrent.ErrTorrentNotFound):
+			apiErrorResponse(c, NewAPIError(http.StatusNotFound, CodeTorrentNotFound, "Torrent not loaded - add it first"))
+		default:
+			handleError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, EpisodeAssignmentResponse{
+		Success:    true,
+		Assignment: assignment,
+	})
+}
+
 func (s *Server) unassignEpisodeTorrent(c *gin.Context) {
 	id, ok := parseID(c, "id")
 	if !ok {
@@ -503,7 +875,7 @@ func (s *Server) unassignEpisodeTorrent(c *gin.Context) {
 	}
 
 	if err := s.assignmentRepo.DeactivateForItem(library.ItemTypeEpisode, id); err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -531,6 +903,8 @@ func toMovieResponse(movie *library.Movie, assignment *library.TorrentAssignment
 		TMDBID:        movie.TMDBID,
 		Title:         movie.Title,
 		Year:          movie.Year,
+		PosterURL:     movie.PosterURL,
+		BackdropURL:   movie.BackdropURL,
 		HasAssignment: assignment != nil,
 	}
 	if assignment != nil {
@@ -545,6 +919,9 @@ func toShowResponse(show *library.Show) ShowResponse {
 		TMDBID: show.TMDBID,
 		Title:  show.Title,
 		Year:   show.Year,
+
+		PosterURL:   show.PosterURL,
+		BackdropURL: show.BackdropURL,
 	}
 
 	for _, season := range show.Seasons {
@@ -560,6 +937,9 @@ func toShowResponse(show *library.Show) ShowResponse {
 				Name:          ep.Name,
 				HasAssignment: ep.Assignment != nil,
 			}
+			if ep.AirDate != nil {
+				epResp.AirDate = ep.AirDate.Format("2006-01-02")
+			}
 			if ep.Assignment != nil {
 				epResp.Assignment = toAssignmentResponse(ep.Assignment)
 			}
@@ -580,6 +960,16 @@ func toAssignmentResponse(a *library.TorrentAssignment) *AssignmentResponse {
 		FileSize:   a.FileSize,
 		Resolution: a.Resolution,
 		Source:     a.Source,
+		BitDepth:   a.BitDepth,
+		Codec:      a.Codec,
+		AudioCodec: a.AudioCodec,
+		HDR:        a.HDR,
+
+		PartPaths: a.PartPaths,
+		LocalPath: a.LocalPath,
+
+		BitrateBps:     a.BitrateBps,
+		RuntimeSeconds: a.RuntimeSeconds,
 	}
 }
 
@@ -618,7 +1008,7 @@ func (s *Server) getRecentlyAiredEpisodes(c *gin.Context) {
 
 	episodes, err := s.showRepo.GetRecentlyAiredEpisodes(lookbackDays)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -667,11 +1057,18 @@ func (s *Server) triggerAirDateSync(c *gin.Context) {
 	}
 
 	// Start sync in background
-	go func() {
+	err := s.jobQueue.Submit(func() {
+		event := AirDateSyncWebhook{Trigger: "manual", Status: "ok"}
 		if err := s.airDateSync.TriggerSync(); err != nil {
 			slog.Warn("Manual air date sync failed", "error", err)
+			event.Status, event.Error = "error", err.Error()
 		}
-	}()
+		s.notifier.Notify(notify.EventAirDateSyncCompleted, event)
+	})
+	if err != nil {
+		busyResponse(c, s.jobQueue)
+		return
+	}
 
 	c.JSON(http.StatusAccepted, gin.H{
 		"message": "Air date sync started",
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// AssignLocalRequest points a library item at a video file on local disk
+type AssignLocalRequest struct {
+	Path string `json:"path" binding:"required"` // Absolute path under vfs.local_media_roots
+}
+
+// LocalAssignmentResponse is the assignment created for a local file
+type LocalAssignmentResponse struct {
+	Success    bool                `json:"success"`
+	Assignment *AssignmentResponse `json:"assignment"`
+}
+
+// assignMovieLocal assigns a local video file to a movie
+func (s *Server) assignMovieLocal(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req AssignLocalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movie, err := s.movieRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if movie == nil {
+		handleError(c, library.ErrMovieNotFound)
+		return
+	}
+
+	assignment, ok := s.createLocalAssignment(c, library.ItemTypeMovie, id, req.Path)
+	if !ok {
+		return
+	}
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.AddMovieToTree(movie, assignment)
+	}
+
+	c.JSON(http.StatusCreated, LocalAssignmentResponse{
+		Success:    true,
+		Assignment: toAssignmentResponse(assignment),
+	})
+}
+
+// assignEpisodeLocal assigns a local video file to an episode
+func (s *Server) assignEpisodeLocal(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req AssignLocalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	episode, err := s.showRepo.GetEpisodeByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	epCtx, err := s.showRepo.GetEpisodeContext(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if episode == nil || epCtx == nil {
+		handleError(c, library.ErrEpisodeNotFound)
+		return
+	}
+
+	assignment, ok := s.createLocalAssignment(c, library.ItemTypeEpisode, id, req.Path)
+	if !ok {
+		return
+	}
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.AddEpisodesToTree([]vfs.EpisodeWithContext{{
+			ShowTitle:    epCtx.ShowTitle,
+			ShowYear:     epCtx.ShowYear,
+			SeasonNumber: epCtx.SeasonNumber,
+			Episode:      episode,
+			Assignment:   assignment,
+
+			ShowTMDBID:   epCtx.ShowTMDBID,
+			ShowOverview: epCtx.ShowOverview,
+		}})
+	}
+
+	c.JSON(http.StatusCreated, LocalAssignmentResponse{
+		Success:    true,
+		Assignment: toAssignmentResponse(assignment),
+	})
+}
+
+// createLocalAssignment verifies a local file and stores it as the item's
+// active assignment, with quality parsed from the file name
+func (s *Server) createLocalAssignment(c *gin.Context, itemType library.ItemType, itemID int64, path string) (*library.TorrentAssignment, bool) {
+	if len(s.localMediaRoots) == 0 {
+		errorResponse(c, http.StatusForbidden, "Local files are disabled - set vfs.local_media_roots")
+		return nil, false
+	}
+	if !filepath.IsAbs(path) {
+		errorResponse(c, http.StatusBadRequest, "path must be absolute")
+		return nil, false
+	}
+
+	// Resolve symlinks so a link can't point outside the roots
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "File not found")
+		return nil, false
+	}
+	if !withinRoots(resolved, s.localMediaRoots) {
+		errorResponse(c, http.StatusForbidden, "path is outside vfs.local_media_roots")
+		return nil, false
+	}
+	info, err := os.Stat(resolved)
+	if err != nil || !info.Mode().IsRegular() {
+		errorResponse(c, http.StatusBadRequest, "path is not a regular file")
+		return nil, false
+	}
+	if !identify.IsVideoFile(resolved) {
+		handleError(c, library.ErrNotVideoFile)
+		return nil, false
+	}
+
+	parsed := s.identifier.FindMovieFile([]identify.TorrentFile{{Path: resolved, Size: info.Size()}})
+	assignment := &library.TorrentAssignment{
+		ItemType:   itemType,
+		ItemID:     itemID,
+		FilePath:   resolved,
+		FileSize:   info.Size(),
+		LocalPath:  resolved,
+		Resolution: parsed.Quality.Resolution,
+		Source:     parsed.Quality.Source,
+		BitDepth:   parsed.Quality.BitDepth,
+		Codec:      parsed.Quality.Codec,
+		AudioCodec: parsed.Quality.AudioCodec,
+		HDR:        parsed.Quality.HDR,
+	}
+	if err := s.assignmentRepo.Create(assignment); err != nil {
+		handleError(c, err)
+		return nil, false
+	}
+	s.mediaProber.Probe(assignment)
+
+	slog.Info("Local file assigned",
+		"item_type", itemType,
+		"item_id", itemID,
+		"local_path", resolved,
+		"size", info.Size(),
+	)
+
+	return assignment, true
+}
+
+// withinRoots reports whether path is one of roots or inside one. Roots are
+// compared with symlinks resolved where they exist.
+func withinRoots(path string, roots []string) bool {
+	for _, root := range roots {
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			root = resolved
+		}
+		rel, err := filepath.Rel(filepath.Clean(root), path)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
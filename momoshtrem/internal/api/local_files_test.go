@@ -0,0 +1,38 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithinRoots(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "movies"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := []string{filepath.Join(root, "movies")}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"inside", filepath.Join(resolvedRoot, "movies", "a.mkv"), true},
+		{"nested", filepath.Join(resolvedRoot, "movies", "x", "a.mkv"), true},
+		{"sibling", filepath.Join(resolvedRoot, "other", "a.mkv"), false},
+		{"prefix only", filepath.Join(resolvedRoot, "movies-old", "a.mkv"), false},
+		{"parent", resolvedRoot, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinRoots(tt.path, roots); got != tt.want {
+				t.Errorf("withinRoots(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// Metadata limits
+const (
+	maxMetadataValueLen = 1024
+)
+
+// metadataKeyPattern restricts keys to simple identifiers (e.g. "tags", "rating", "note:kids")
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,64}$`)
+
+// Metadata request/response types
+
+type SetMetadataRequest struct {
+	Value string `json:"value"`
+}
+
+type ItemMetadataResponse struct {
+	ItemType string            `json:"item_type"`
+	ItemID   int64             `json:"item_id"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type MetadataEntryResponse struct {
+	ItemType string `json:"item_type"`
+	ItemID   int64  `json:"item_id"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+type MetadataSearchResponse struct {
+	Items []MetadataEntryResponse `json:"items"`
+}
+
+// Metadata handlers
+
+func (s *Server) getItemMetadata(itemType library.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := s.parseMetadataItem(c, itemType)
+		if !ok {
+			return
+		}
+
+		metadata, err := s.metadataRepo.GetForItem(itemType, id)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, ItemMetadataResponse{
+			ItemType: string(itemType),
+			ItemID:   id,
+			Metadata: metadata,
+		})
+	}
+}
+
+func (s *Server) setItemMetadata(itemType library.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := s.parseMetadataItem(c, itemType)
+		if !ok {
+			return
+		}
+
+		key, ok := parseMetadataKey(c)
+		if !ok {
+			return
+		}
+
+		var req SetMetadataRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(req.Value) > maxMetadataValueLen {
+			errorResponse(c, http.StatusBadRequest, "value is too long")
+			return
+		}
+
+		if err := s.metadataRepo.Set(itemType, id, key, req.Value); err != nil {
+			handleError(c, err)
+			return
+		}
+
+		// Tag folders in the VFS are derived from metadata
+		if s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+		}
+
+		c.JSON(http.StatusOK, MetadataEntryResponse{
+			ItemType: string(itemType),
+			ItemID:   id,
+			Key:      key,
+			Value:    req.Value,
+		})
+	}
+}
+
+func (s *Server) deleteItemMetadata(itemType library.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := s.parseMetadataItem(c, itemType)
+		if !ok {
+			return
+		}
+
+		key, ok := parseMetadataKey(c)
+		if !ok {
+			return
+		}
+
+		deleted, err := s.metadataRepo.Delete(itemType, id, key)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		if !deleted {
+			errorResponse(c, http.StatusNotFound, "Metadata key not found")
+			return
+		}
+
+		if s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// searchMetadata lists items that have a metadata key, optionally with a specific value
+func (s *Server) searchMetadata(c *gin.Context) {
+	if s.metadataRepo == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Metadata not configured")
+		return
+	}
+
+	key := c.Query("key")
+	if !metadataKeyPattern.MatchString(key) {
+		errorResponse(c, http.StatusBadRequest, "key is required and must match [A-Za-z0-9_.:-]{1,64}")
+		return
+	}
+
+	entries, err := s.metadataRepo.ListByKey(key, c.Query("value"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := MetadataSearchResponse{Items: make([]MetadataEntryResponse, 0, len(entries))}
+	for _, e := range entries {
+		response.Items = append(response.Items, MetadataEntryResponse{
+			ItemType: string(e.ItemType),
+			ItemID:   e.ItemID,
+			Key:      e.Key,
+			Value:    e.Value,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseMetadataItem parses the item ID and verifies the item exists
+func (s *Server) parseMetadataItem(c *gin.Context, itemType library.ItemType) (int64, bool) {
+	if s.metadataRepo == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Metadata not configured")
+		return 0, false
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return 0, false
+	}
+
+	var exists bool
+	switch itemType {
+	case library.ItemTypeMovie:
+		movie, err := s.movieRepo.GetByID(id)
+		if err != nil {
+			handleError(c, err)
+			return 0, false
+		}
+		exists = movie != nil
+	case library.ItemTypeShow:
+		show, err := s.showRepo.GetByID(id)
+		if err != nil {
+			handleError(c, err)
+			return 0, false
+		}
+		exists = show != nil
+	}
+
+	if !exists {
+		errorResponse(c, http.StatusNotFound, "Item not found")
+		return 0, false
+	}
+	return id, true
+}
+
+// deleteItemMetadataFor removes metadata for a deleted item (metadata has no foreign key)
+func (s *Server) deleteItemMetadataFor(itemType library.ItemType, id int64) {
+	if s.metadataRepo == nil {
+		return
+	}
+	if err := s.metadataRepo.DeleteForItem(itemType, id); err != nil {
+		slog.Error("Failed to delete item metadata", "item_type", itemType, "item_id", id, "error", err)
+	}
+}
+
+// parseMetadataKey validates the :key path parameter
+func parseMetadataKey(c *gin.Context) (string, bool) {
+	key := c.Param("key")
+	if !metadataKeyPattern.MatchString(key) {
+		errorResponse(c, http.StatusBadRequest, "key must match [A-Za-z0-9_.:-]{1,64}")
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// PlaybackInfoResponse describes the active assignment's streams as parsed
+// from the release name, and whether Apple TV can play them without transcoding
+type PlaybackInfoResponse struct {
+	ItemType     string `json:"item_type"`
+	ItemID       int64  `json:"item_id"`
+	AssignmentID int64  `json:"assignment_id"`
+	VideoCodec   string `json:"video_codec,omitempty"` // H.264, HEVC, AV1
+	AudioCodec   string `json:"audio_codec,omitempty"` // AAC, AC3, E-AC3, DTS, TrueHD...
+	Resolution   string `json:"resolution,omitempty"`
+	BitDepth     string `json:"bit_depth,omitempty"`
+	HDR          bool   `json:"hdr"`
+
+	// True when both codecs are known and in the known-good matrix, false when
+	// either is known to be unsupported, null when a codec is unknown (as for
+	// assignments predating codec parsing). Issues says why it isn't true.
+	AppleTVCompatible *bool    `json:"apple_tv_compatible"`
+	Issues            []string `json:"issues,omitempty"`
+}
+
+// getPlaybackInfo returns codec details and Apple TV compatibility for an
+// item's active assignment
+func (s *Server) getPlaybackInfo(itemType library.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseID(c, "id")
+		if !ok {
+			return
+		}
+		if !s.watchableItemExists(c, itemType, id) {
+			return
+		}
+
+		assignment, err := s.assignmentRepo.GetActiveForItem(itemType, id)
+		if err != nil {
+			handleError(c, err)
+			return
+		}
+		if assignment == nil {
+			errorResponse(c, http.StatusNotFound, "Item has no active assignment")
+			return
+		}
+
+		compatible, issues := appleTVCompatibility(assignment.Codec, assignment.BitDepth, assignment.AudioCodec)
+		c.JSON(http.StatusOK, PlaybackInfoResponse{
+			ItemType:          string(itemType),
+			ItemID:            id,
+			AssignmentID:      assignment.ID,
+			VideoCodec:        assignment.Codec,
+			AudioCodec:        assignment.AudioCodec,
+			Resolution:        assignment.Resolution,
+			BitDepth:          assignment.BitDepth,
+			HDR:               assignment.HDR,
+			AppleTVCompatible: compatible,
+			Issues:            issues,
+		})
+	}
+}
+
+// appleTVCompatibility checks codecs against what Apple TV plays natively:
+// H.264 up to 8-bit or HEVC up to Main10, with AAC or (E-)AC3 audio.
+// Returns nil when nothing is known to be unsupported but a codec is missing
+// from the release name.
+func appleTVCompatibility(videoCodec, bitDepth, audioCodec string) (*bool, []string) {
+	var issues []string
+	var unknown []string
+
+	switch strings.ToUpper(videoCodec) {
+	case "":
+		unknown = append(unknown, "video codec unknown")
+	case "H.264", "H264", "X264", "AVC":
+		if bitDepth != "" && bitDepth != "8bit" {
+			issues = append(issues, "H.264 "+bitDepth+" is not supported")
+		}
+	case "HEVC", "H.265", "H265", "X265":
+		if bitDepth == "12bit" {
+			issues = append(issues, "HEVC 12bit is not supported (Main10 max)")
+		}
+	default:
+		issues = append(issues, "video codec "+videoCodec+" is not supported")
+	}
+
+	switch strings.ToUpper(audioCodec) {
+	case "":
+		unknown = append(unknown, "audio codec unknown")
+	case "AAC", "AC3", "E-AC3":
+	default:
+		issues = append(issues, "audio codec "+audioCodec+" is not supported")
+	}
+
+	compatible := len(issues) == 0
+	issues = append(issues, unknown...)
+	if compatible && len(unknown) > 0 {
+		return nil, issues
+	}
+	return &compatible, issues
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestAppleTVCompatibility(t *testing.T) {
+	tests := []struct {
+		name                   string
+		video, bitDepth, audio string
+		wantCompatible         string // "true", "false" or "null"
+		wantIssues             int
+	}{
+		{"h264 aac", "H.264", "", "AAC", "true", 0},
+		{"hevc main10 eac3", "HEVC", "10bit", "E-AC3", "true", 0},
+		{"hevc ac3", "HEVC", "", "AC3", "true", 0},
+		{"hi10p", "H.264", "10bit", "AAC", "false", 1},
+		{"hevc 12bit", "HEVC", "12bit", "AAC", "false", 1},
+		{"av1", "AV1", "", "AAC", "false", 1},
+		{"dts", "H.264", "", "DTS", "false", 1},
+		{"truehd", "HEVC", "", "TrueHD", "false", 1},
+		{"unknown audio", "HEVC", "", "", "null", 1},
+		{"unknown codecs", "", "", "", "null", 2},
+		{"unknown audio, unsupported video", "AV1", "", "", "false", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatible, issues := appleTVCompatibility(tt.video, tt.bitDepth, tt.audio)
+			got := "null"
+			if compatible != nil {
+				got = strconv.FormatBool(*compatible)
+			}
+			if got != tt.wantCompatible {
+				t.Errorf("compatible = %s, want %s (issues %v)", got, tt.wantCompatible, issues)
+			}
+			if len(issues) != tt.wantIssues {
+				t.Errorf("issues = %v, want %d", issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestAppleTVCompatibilityOfSelectedMovieFile(t *testing.T) {
+	identifier := identify.NewIdentifier(nil, identify.DefaultConfig())
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"Movie.2020.1080p.BluRay.Hi10P.x264.AAC/Movie.2020.1080p.BluRay.Hi10P.x264.AAC.mkv", false},
+		{"Movie.2020.1080p.BluRay.x264.AAC/Movie.2020.1080p.BluRay.x264.AAC.mkv", true},
+	}
+	for _, tt := range tests {
+		// The quality stored on assignment comes from movie file selection
+		q := identifier.FindMovieFile([]identify.TorrentFile{{Path: tt.path, Size: 4 << 30}}).Quality
+		compatible, issues := appleTVCompatibility(q.Codec, q.BitDepth, q.AudioCodec)
+		if compatible == nil || *compatible != tt.want {
+			t.Errorf("%s: compatible = %v (issues %v), want %v", tt.path, compatible, issues, tt.want)
+		}
+	}
+}
+
+func TestGetPlaybackInfoUnknownAudioIsNull(t *testing.T) {
+	s, db := openTestServer(t)
+	movie := createTestMovie(t, db, "Playback Info Test")
+
+	get := func(codec, audioCodec string) map[string]any {
+		t.Helper()
+		createTestAssignment(t, db, &library.TorrentAssignment{
+			ItemType:   library.ItemTypeMovie,
+			ItemID:     movie.ID,
+			FilePath:   "Movie.mkv",
+			Codec:      codec,
+			AudioCodec: audioCodec,
+		})
+
+		w := serve(s, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/movies/%d/playback-info", movie.ID), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+		}
+		var body map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return body
+	}
+
+	// An assignment without a parsed audio codec, as before audio codecs were stored
+	body := get("H.264", "")
+	if v, ok := body["apple_tv_compatible"]; !ok || v != nil {
+		t.Errorf("apple_tv_compatible = %v (present %v), want null", v, ok)
+	}
+
+	body = get("H.264", "AAC")
+	if body["apple_tv_compatible"] != true {
+		t.Errorf("apple_tv_compatible = %v, want true", body["apple_tv_compatible"])
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+	"github.com/shapedtime/momoshtrem/internal/config"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// playlistSource points show playlists at the WebDAV server
+type playlistSource struct {
+	paths     vfs.EpisodePathResolver
+	publicURL *url.URL // Configured WebDAV address; nil derives it from the request host
+	port      int      // WebDAV port for derived addresses
+	basePath  string   // WebDAV base path for derived addresses ("" for the root)
+
+	// WebDAV credentials embedded in entry URLs; nil unless both WebDAV and
+	// API auth are on, since the playlist is served to any API caller
+	user *url.Userinfo
+}
+
+// SetPlaylistSource enables GET /shows/:id/playlist.m3u8, listing episode files
+// from paths at the WebDAV address in cfg
+func (s *Server) SetPlaylistSource(paths vfs.EpisodePathResolver, cfg config.ServerConfig) {
+	src := &playlistSource{
+		paths: paths,
+		port:  cfg.WebDAVPort,
+	}
+	if cfg.WebDAVAuth.Enabled {
+		if cfg.APIAuth.Enabled {
+			src.user = url.UserPassword(cfg.WebDAVAuth.Username, cfg.WebDAVAuth.Password)
+		} else {
+			slog.Info("API auth is disabled; playlist URLs leave out the WebDAV credentials")
+		}
+	}
+	if basePath := common.CleanPath(strings.TrimSpace(cfg.WebDAVBasePath)); basePath != "/" {
+		src.basePath = basePath
+	}
+	if cfg.WebDAVPublicURL != "" {
+		u, err := url.Parse(strings.TrimRight(cfg.WebDAVPublicURL, "/"))
+		if err != nil {
+			slog.Warn("Ignoring invalid WebDAV public URL; playlists use the request host", "url", cfg.WebDAVPublicURL, "error", err)
+		} else {
+			src.publicURL = u
+		}
+	}
+	s.playlists = src
+}
+
+// baseURL returns the WebDAV root as seen by the client that sent r. Credentials
+// are embedded when configured, since players can't prompt per file.
+func (p *playlistSource) baseURL(r *http.Request) *url.URL {
+	var u url.URL
+	if p.publicURL != nil {
+		u = *p.publicURL
+	} else {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		u = url.URL{
+			Scheme: "http",
+			Host:   net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(p.port)),
+			Path:   p.basePath,
+		}
+	}
+	if p.user != nil {
+		u.User = p.user
+	}
+	return &u
+}
+
+// buildPlaylist renders an extended M3U playlist of VFS files under base,
+// titled by their file names without extension
+func buildPlaylist(base *url.URL, vfsPaths []string) string {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	for _, p := range vfsPaths {
+		name := path.Base(p)
+		u := *base
+		u.Path = base.Path + p
+		b.WriteString("#EXTINF:-1," + strings.TrimSuffix(name, path.Ext(name)) + "\n")
+		b.WriteString(u.String() + "\n")
+	}
+	return b.String()
+}
+
+// playlist renders the playlist of a show's assigned episodes, requested by r,
+// limited to one season unless seasonNumber is negative. It returns "" when
+// there are no episodes to list.
+func (p *playlistSource) playlist(r *http.Request, show *library.Show, seasons []library.Season, seasonNumber int) string {
+	var vfsPaths []string
+	for _, season := range seasons {
+		if seasonNumber >= 0 && season.SeasonNumber != seasonNumber {
+			continue
+		}
+		for i := range season.Episodes {
+			vfsPaths = append(vfsPaths, p.paths.EpisodeVideoPath(show, season.SeasonNumber, &season.Episodes[i]))
+		}
+	}
+	if len(vfsPaths) == 0 {
+		return ""
+	}
+	return buildPlaylist(p.baseURL(r), vfsPaths)
+}
+
+// getShowPlaylist returns an M3U playlist of every assigned episode of a show
+func (s *Server) getShowPlaylist(c *gin.Context) {
+	s.servePlaylist(c, -1)
+}
+
+// getSeasonPlaylist returns an M3U playlist of the assigned episodes of one season
+func (s *Server) getSeasonPlaylist(c *gin.Context) {
+	seasonNumber, err := strconv.Atoi(c.Param("num"))
+	if err != nil || seasonNumber < 0 {
+		errorResponse(c, http.StatusBadRequest, "Invalid season number")
+		return
+	}
+	s.servePlaylist(c, seasonNumber)
+}
+
+// servePlaylist writes the playlist of a show's assigned episodes in season and
+// episode order, limited to one season unless seasonNumber is negative
+func (s *Server) servePlaylist(c *gin.Context, seasonNumber int) {
+	if s.playlists == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Playlists are not available")
+		return
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return
+	}
+
+	seasons, err := s.showRepo.GetSeasonsWithAssignedEpisodes(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	playlist := s.playlists.playlist(c.Request, show, seasons, seasonNumber)
+	if playlist == "" {
+		errorResponse(c, http.StatusNotFound, "No assigned episodes")
+		return
+	}
+
+	name := strings.TrimPrefix(show.VFSPath(), "/")
+	if seasonNumber >= 0 {
+		name += " - Season " + common.PadZero(seasonNumber, 2)
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": name + ".m3u8"}))
+	c.Data(http.StatusOK, "audio/x-mpegurl; charset=utf-8", []byte(playlist))
+}
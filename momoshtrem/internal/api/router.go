@@ -1,13 +1,19 @@
 package api
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shapedtime/momoshtrem/internal/airdate"
+	"github.com/shapedtime/momoshtrem/internal/config"
 	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/jobs"
 	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/notify"
 	"github.com/shapedtime/momoshtrem/internal/service"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
 	"github.com/shapedtime/momoshtrem/internal/tmdb"
@@ -27,6 +33,33 @@ type Server struct {
 	treeUpdater     vfs.TreeUpdater       // Optional: updates VFS tree on assignment changes
 	subtitleService *subtitle.Service     // Optional: subtitle search/download service
 	airDateSync     *airdate.SyncService  // Optional: air date sync service
+	metadataRepo    *library.MetadataRepository // Optional: per-item key/value metadata
+	streamInspector vfs.StreamInspector         // Optional: buffer health of open streams
+	activity        *torrent.ActivityManager    // Optional: idle mode state (nil = idle mode disabled)
+	prewarmer       *service.TorrentPrewarmer   // Optional: adds queued torrents when episodes air
+	jobQueue        *jobs.Queue                 // Optional: bounds long-running operations (nil = unlimited)
+	showArchiveFS   vfs.Filesystem              // Optional: enables show zip downloads
+	collectionRepo  *library.CollectionRepository // Optional: user collections of movies/shows
+	watchStatusRepo *library.WatchStatusRepository // Optional: playback progress for continue watching
+	showSettingsRepo *library.ShowSettingsRepository // Optional: per-show subtitle languages
+	localMediaRoots  []string                        // Directories local files can be assigned from (empty = disabled)
+	mediaProber     *service.MediaProber            // Optional: bitrate/runtime of newly assigned files
+	inspected       *inspectedTorrents              // Torrents loaded by /api/torrents/inspect, pending release
+	cacheSizeBytes  int64                           // Configured torrent cache size, reported by /api/stats
+	cacheMonitor    *torrent.CacheMonitor           // Optional: piece cache usage and evictions
+	playlists       *playlistSource                 // Optional: WebDAV URLs for show playlists
+	minSeeders      int                             // Reject movie torrents with fewer connected seeders (0 = disabled)
+
+	// Server-Sent Event streams
+	torrentEventsInterval time.Duration // Push interval for /api/torrents/events
+	events                *eventStreams
+
+	apiAuth  config.APIAuthConfig // Optional: API key authentication
+	notifier *notify.Notifier     // Optional: webhook notifications (nil discards events)
+
+	// Readiness checks
+	healthDB   *library.DB        // Optional: pinged by /api/health
+	tmdbHealth *cachedHealthCheck // Optional: cached TMDB reachability
 
 	// Business logic services
 	showService           *service.ShowService
@@ -41,10 +74,11 @@ func NewServer(
 	tmdbClient *tmdb.Client,
 	torrentService torrent.Service, // Can be nil until Stage 2
 	treeUpdater vfs.TreeUpdater,    // Optional: updates VFS tree on assignment changes
+	identifyCfg identify.Config,
 ) *Server {
 	gin.SetMode(gin.ReleaseMode)
 
-	identifier := identify.NewIdentifier(nil)
+	identifier := identify.NewIdentifier(nil, identifyCfg)
 
 	s := &Server{
 		router:         gin.New(),
@@ -55,6 +89,10 @@ func NewServer(
 		torrentService: torrentService,
 		identifier:     identifier,
 		treeUpdater:    treeUpdater,
+
+		torrentEventsInterval: defaultTorrentEventsInterval,
+		events:                newEventStreams(),
+		inspected:             newInspectedTorrents(),
 	}
 
 	// Initialize business logic services
@@ -65,6 +103,13 @@ func NewServer(
 	if treeUpdater != nil {
 		assignmentOpts = append(assignmentOpts, service.WithTreeUpdater(treeUpdater))
 	}
+	if torrentService != nil {
+		s.mediaProber = service.NewMediaProber(torrentService, assignmentRepo)
+		assignmentOpts = append(assignmentOpts,
+			service.WithTorrentFileGetter(torrentService),
+			service.WithMediaProber(s.mediaProber),
+		)
+	}
 	s.showAssignmentService = service.NewShowAssignmentService(
 		showRepo,
 		assignmentRepo,
@@ -85,16 +130,110 @@ func (s *Server) SetSubtitleService(svc *subtitle.Service) {
 	// Configure subtitle creator on assignment service
 	if s.showAssignmentService != nil {
 		s.showAssignmentService.SetSubtitleCreator(svc)
+		s.showAssignmentService.SetSubtitleDownloader(svc)
 	}
 	slog.Info("Subtitle service configured")
 }
 
+// SetAssignSingleVideo configures whether episode assignments take a torrent's
+// only video file without requiring its name to match the episode
+func (s *Server) SetAssignSingleVideo(enabled bool) {
+	s.showAssignmentService.SetAssignSingleVideo(enabled)
+}
+
+// SetMinSeeders rejects assignments of torrents that connect to fewer than n
+// seeders shortly after their metadata arrives (0 = disabled)
+func (s *Server) SetMinSeeders(n int) {
+	s.minSeeders = n
+	s.showAssignmentService.SetMinSeeders(n)
+}
+
+// SetAutoSubtitleLanguages configures the languages downloaded from OpenSubtitles
+// for episodes matched by a show torrent assignment
+func (s *Server) SetAutoSubtitleLanguages(languages []string) {
+	s.showAssignmentService.SetAutoSubtitleLanguages(languages)
+}
+
+// Identifier returns the episode identifier shared by the assignment handlers
+func (s *Server) Identifier() *identify.Identifier {
+	return s.identifier
+}
+
+// SetIdentifyFallback configures identification of files no pattern matches
+func (s *Server) SetIdentifyFallback(f identify.FallbackHandler) {
+	s.identifier.SetFallback(f)
+}
+
 // SetAirDateSyncService configures air date sync support
 func (s *Server) SetAirDateSyncService(svc *airdate.SyncService) {
 	s.airDateSync = svc
 	slog.Info("Air date sync service configured")
 }
 
+// SetAPIAuth requires an API key on REST requests (applied by Handler)
+func (s *Server) SetAPIAuth(cfg config.APIAuthConfig) {
+	s.apiAuth = cfg
+}
+
+// SetNotifier configures webhook notifications for assignment, removal and sync events
+func (s *Server) SetNotifier(n *notify.Notifier) {
+	s.notifier = n
+}
+
+// SetJobQueue bounds concurrent long-running operations
+func (s *Server) SetJobQueue(q *jobs.Queue) {
+	s.jobQueue = q
+	s.showAssignmentService.SetJobQueue(q)
+}
+
+// SetShowArchive enables GET /shows/:id/download.zip, reading show files from the VFS
+func (s *Server) SetShowArchive(fsys vfs.Filesystem) {
+	s.showArchiveFS = fsys
+}
+
+// SetStreamInspector configures buffer diagnostics for open streams
+func (s *Server) SetStreamInspector(si vfs.StreamInspector) {
+	s.streamInspector = si
+}
+
+// SetActivityManager enables per-torrent idle mode reporting
+func (s *Server) SetActivityManager(am *torrent.ActivityManager) {
+	s.activity = am
+}
+
+// SetTorrentPrewarmer enables queueing torrents for upcoming episodes
+func (s *Server) SetTorrentPrewarmer(p *service.TorrentPrewarmer) {
+	s.prewarmer = p
+}
+
+// SetMetadataRepository configures per-item metadata support
+func (s *Server) SetMetadataRepository(repo *library.MetadataRepository) {
+	s.metadataRepo = repo
+}
+
+// SetCollectionRepository configures collection support
+func (s *Server) SetCollectionRepository(repo *library.CollectionRepository) {
+	s.collectionRepo = repo
+}
+
+// SetWatchStatusRepository configures playback progress tracking
+func (s *Server) SetWatchStatusRepository(repo *library.WatchStatusRepository) {
+	s.watchStatusRepo = repo
+}
+
+// SetShowSettingsRepository configures per-show settings, including the
+// subtitle languages auto-downloaded for the show's assignments
+func (s *Server) SetShowSettingsRepository(repo *library.ShowSettingsRepository) {
+	s.showSettingsRepo = repo
+	s.showAssignmentService.SetShowSettings(repo)
+}
+
+// SetLocalMediaRoots enables assigning video files under these directories,
+// which the VFS reads from disk instead of a torrent
+func (s *Server) SetLocalMediaRoots(roots []string) {
+	s.localMediaRoots = roots
+}
+
 func (s *Server) setupMiddleware() {
 	// Recovery middleware
 	s.router.Use(gin.Recovery())
@@ -112,8 +251,8 @@ func (s *Server) setupMiddleware() {
 	// CORS for development
 	s.router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusOK)
@@ -132,45 +271,139 @@ func (s *Server) setupRoutes() {
 	api.POST("/movies", s.createMovie)
 	api.GET("/movies/:id", s.getMovie)
 	api.DELETE("/movies/:id", s.deleteMovie)
-	api.POST("/movies/:id/assign-torrent", s.assignMovieTorrent) // Auto-detect movie file
+	api.POST("/movies/:id/assign-torrent", s.limitJobs, s.assignMovieTorrent) // Auto-detect movie file
 	api.DELETE("/movies/:id/assign", s.unassignMovieTorrent)
+	api.POST("/movies/:id/assign-local", s.assignMovieLocal) // File on local disk (vfs.local_media_roots)
 
 	// Shows
 	api.GET("/shows", s.listShows)
 	api.POST("/shows", s.createShow)
 	api.GET("/shows/:id", s.getShow)
 	api.DELETE("/shows/:id", s.deleteShow)
-	api.POST("/shows/:id/assign-torrent", s.assignShowTorrent) // Auto-detect episodes
+	api.POST("/shows/:id/assign-torrent", s.limitJobs, s.assignShowTorrent) // Auto-detect episodes (?dry_run=true previews)
+	api.GET("/shows/:id/download.zip", s.limitJobs, s.downloadShowZip)      // Whole show for offline copy (opt-in)
+	api.GET("/shows/:id/coverage", s.getShowCoverage)                       // Assigned vs missing episodes per season
+	api.GET("/shows/:id/playlist.m3u8", s.getShowPlaylist)                  // WebDAV URLs of assigned episodes, in order
+	api.GET("/shows/:id/seasons/:num/playlist.m3u8", s.getSeasonPlaylist)
+	api.POST("/shows/:id/refresh", s.limitJobs, s.refreshShow)              // Add newly announced seasons/episodes from TMDB
+	api.DELETE("/shows/:id/seasons/:num", s.deleteSeason)                   // Remove one season, keeping the rest of the show
+	api.GET("/shows/:id/settings", s.getShowSettings)                       // Per-show subtitle languages
+	api.PUT("/shows/:id/settings", s.setShowSettings)
 	api.GET("/shows/recently-aired", s.getRecentlyAiredEpisodes)
 	api.POST("/shows/sync-air-dates", s.triggerAirDateSync)
 
-	// Episodes - only unassign, assignment is done via show-level API
+	// Bulk add by TMDB ID
+	api.POST("/import", s.limitJobs, s.importLibrary) // Per-ID report; one bad ID doesn't fail the rest
+
+	// Episodes
+	api.POST("/episodes/:id/assign-torrent", s.limitJobs, s.assignEpisodeTorrent) // Single-episode torrent
+	api.POST("/episodes/:id/assign-file", s.assignEpisodeFile)                     // Specific file, no identification
+	api.POST("/episodes/:id/assign-local", s.assignEpisodeLocal)                   // File on local disk (vfs.local_media_roots)
 	api.DELETE("/episodes/:id/assign", s.unassignEpisodeTorrent)
+	api.POST("/episodes/:id/queue-torrent", s.queueEpisodeTorrent) // Add (not assign) the torrent when the episode airs
+
+	// Assignments across the library
+	api.GET("/assignments", s.listAssignments)                     // ?needs_review=true: uncertain identifier matches
+	api.GET("/assignments/duplicates", s.listDuplicateAssignments) // Same file size/resolution from different torrents
 
 	// Torrents - torrent management
 	api.GET("/torrents", s.listTorrents)
+	api.GET("/torrents/events", s.streamTorrentEvents) // SSE: snapshot, then changed torrents
+	api.POST("/torrents/inspect", s.limitJobs, s.inspectTorrent) // File list and episode guesses, no assignment
 	api.GET("/torrents/:hash", s.getTorrent)
 	api.DELETE("/torrents/:hash", s.deleteTorrent)
 	api.POST("/torrents/:hash/pause", s.pauseTorrent)
 	api.POST("/torrents/:hash/resume", s.resumeTorrent)
+	api.POST("/torrents/:hash/replace", s.limitJobs, s.replaceTorrent) // Move all items to a new torrent
+	api.POST("/torrents/:hash/reidentify", s.limitJobs, s.reidentifyTorrent) // Refresh quality of assigned files
+	api.POST("/torrents/:hash/trackers", s.addTorrentTrackers)          // Announce to extra trackers
+	api.PUT("/torrents/:hash/limits", s.setTorrentLimits)               // Override the peer connection limit
+	api.GET("/torrents/:hash/buffer", s.getTorrentBuffer) // Buffer health of open streams
+	api.GET("/torrents/:hash/files", s.getTorrentFiles)   // Per-file download progress
+	api.GET("/torrents/:hash/pieces", s.getTorrentPieces) // Piece completion bitfield
+	api.GET("/torrents/:hash/activity", s.getTorrentActivity) // Idle mode state
 
 	// Subtitles
 	api.GET("/subtitles/search", s.searchSubtitles)
-	api.POST("/subtitles/download", s.downloadSubtitle)
+	api.POST("/subtitles/download", s.limitJobs, s.downloadSubtitle)
 	api.DELETE("/subtitles/:id", s.deleteSubtitle)
+	api.PATCH("/subtitles/:id/offset", s.setSubtitleOffset) // Shift cue timing
 	api.GET("/movies/:id/subtitles", s.getMovieSubtitles)
 	api.GET("/episodes/:id/subtitles", s.getEpisodeSubtitles)
+	api.POST("/shows/:id/subtitles/download", s.limitJobs, s.downloadSeasonSubtitles) // Whole season, per-episode summary
+	api.GET("/subtitles/auth", s.getSubtitleAuth)   // OpenSubtitles auth diagnostics
+	api.GET("/subtitles/quota", s.getSubtitleQuota) // Downloads left and reset time
+
+	// Item metadata (tags, labels)
+	api.GET("/movies/:id/metadata", s.getItemMetadata(library.ItemTypeMovie))
+	api.PUT("/movies/:id/metadata/:key", s.setItemMetadata(library.ItemTypeMovie))
+	api.DELETE("/movies/:id/metadata/:key", s.deleteItemMetadata(library.ItemTypeMovie))
+	api.GET("/shows/:id/metadata", s.getItemMetadata(library.ItemTypeShow))
+	api.PUT("/shows/:id/metadata/:key", s.setItemMetadata(library.ItemTypeShow))
+	api.DELETE("/shows/:id/metadata/:key", s.deleteItemMetadata(library.ItemTypeShow))
+	api.GET("/metadata", s.searchMetadata)
+
+	// Collections (exposed as /Collections/<name>/ in the VFS)
+	api.GET("/collections", s.listCollections)
+	api.POST("/collections", s.createCollection)
+	api.GET("/collections/:id", s.getCollection)
+	api.DELETE("/collections/:id", s.deleteCollection)
+	api.POST("/collections/:id/items", s.addCollectionItem)
+	api.DELETE("/collections/:id/items/:type/:item_id", s.removeCollectionItem)
+
+	// Watch status
+	api.PUT("/movies/:id/progress", s.updateProgress(library.ItemTypeMovie))
+	api.PUT("/episodes/:id/progress", s.updateProgress(library.ItemTypeEpisode))
+	api.GET("/movies/:id/playback-info", s.getPlaybackInfo(library.ItemTypeMovie))
+	api.GET("/episodes/:id/playback-info", s.getPlaybackInfo(library.ItemTypeEpisode)) // Codecs and Apple TV compatibility
+	api.GET("/continue-watching", s.getContinueWatching)
 
 	// Status
 	api.GET("/status", s.getStatus)
+	api.GET("/stats", s.getStats) // Library counts, assigned vs downloaded bytes, cache size
+	api.GET("/cache", s.getCacheUsage) // Piece cache fill and evictions
+	api.GET("/health", s.getReadiness)
+	api.GET("/health/ready", s.getReadiness)
+	api.GET("/health/live", s.getLiveness)
 }
 
 // Handler returns the HTTP handler
 func (s *Server) Handler() http.Handler {
-	return s.router
+	return newAPIKeyAuth(s.router, s.apiAuth)
 }
 
-// Error response helper
-func errorResponse(c *gin.Context, status int, message string) {
-	c.JSON(status, gin.H{"error": message})
+// limitJobs runs a long-running handler under the job queue.
+// When the queue is full the request is rejected with 429 and Retry-After.
+func (s *Server) limitJobs(c *gin.Context) {
+	release, err := s.jobQueue.Acquire(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, jobs.ErrQueueFull) {
+			busyResponse(c, s.jobQueue)
+			return
+		}
+		// Client went away while queued
+		c.Abort()
+		return
+	}
+	defer release()
+
+	c.Next()
+}
+
+// busyResponse rejects a request because the job queue is full
+func busyResponse(c *gin.Context, q *jobs.Queue) {
+	retryAfter := max(int(q.RetryAfter().Seconds()), 1)
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": NewAPIError(http.StatusTooManyRequests, CodeBusy, "Server busy, retry later")})
+}
+
+// resolvingResponse reports that a torrent's metadata is still arriving in the
+// background; repeating the request succeeds once it has
+func resolvingResponse(c *gin.Context, magnetURI string) {
+	c.JSON(http.StatusAccepted, gin.H{
+		"status":    "resolving",
+		"info_hash": torrent.ExtractInfoHash(magnetURI),
+		"name":      torrent.MagnetDisplayName(magnetURI), // Placeholder from the magnet's dn, if any
+		"message":   "Torrent metadata is still resolving, retry the request shortly",
+	})
 }
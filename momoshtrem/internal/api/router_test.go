@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+)
+
+func TestCORSAllowsEveryRouteMethod(t *testing.T) {
+	s := NewServer(nil, nil, nil, nil, nil, nil, identify.DefaultConfig())
+
+	w := serve(s, httptest.NewRequest(http.MethodOptions, "/api/subtitles/1/offset", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("preflight status = %d, want 200", w.Code)
+	}
+	allowed := strings.Split(w.Header().Get("Access-Control-Allow-Methods"), ", ")
+	for _, route := range s.router.Routes() {
+		if !slices.Contains(allowed, route.Method) {
+			t.Errorf("%s %s is not in Access-Control-Allow-Methods %v", route.Method, route.Path, allowed)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
+	"github.com/shapedtime/momoshtrem/internal/service"
+)
+
+// DownloadSeasonSubtitlesRequest selects the season and languages to download
+type DownloadSeasonSubtitlesRequest struct {
+	Season    *int     `json:"season" binding:"required"` // 0 for specials
+	Languages []string `json:"languages" binding:"required,min=1"`
+}
+
+// DownloadSeasonSubtitlesResponse summarizes the download per episode
+type DownloadSeasonSubtitlesResponse struct {
+	ShowID     int64                           `json:"show_id"`
+	Season     int                             `json:"season"`
+	Downloaded int                             `json:"downloaded"` // Subtitles stored across all episodes
+	Episodes   []service.EpisodeSubtitleResult `json:"episodes"`
+
+	// Why the batch ended before the last episode, e.g. the download quota ran out
+	StoppedReason string `json:"stopped_reason,omitempty"`
+}
+
+// downloadSeasonSubtitles downloads the best OpenSubtitles match in each
+// language for every episode of a season that doesn't have one yet
+func (s *Server) downloadSeasonSubtitles(c *gin.Context) {
+	if s.subtitleService == nil || !s.subtitleService.IsConfigured() {
+		errorResponse(c, http.StatusServiceUnavailable, "Subtitle service not configured")
+		return
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req DownloadSeasonSubtitlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	langs, ok := parseSubtitleLangs(c, "languages", req.Languages)
+	if !ok {
+		return
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return
+	}
+	season, err := s.showRepo.GetSeason(id, *req.Season)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if season == nil {
+		errorResponse(c, http.StatusNotFound, "Season not found")
+		return
+	}
+	episodes, err := s.showRepo.GetEpisodes(season.ID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	results, err := s.showAssignmentService.DownloadSeasonSubtitles(c.Request.Context(), show, season.SeasonNumber, episodes, langs)
+	var quotaErr *opensubtitles.QuotaExhaustedError
+	if err != nil && !errors.As(err, &quotaErr) {
+		handleError(c, err)
+		return
+	}
+
+	resp := DownloadSeasonSubtitlesResponse{
+		ShowID:   id,
+		Season:   season.SeasonNumber,
+		Episodes: results,
+	}
+	for _, r := range results {
+		resp.Downloaded += len(r.Downloaded)
+	}
+	if quotaErr != nil {
+		resp.StoppedReason = quotaErr.Error()
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeasonCoverageResponse reports how much of a season is assigned
+type SeasonCoverageResponse struct {
+	SeasonNumber    int   `json:"season_number"`
+	EpisodeCount    int   `json:"episode_count"`
+	AssignedCount   int   `json:"assigned_count"`
+	MissingEpisodes []int `json:"missing_episodes"`
+	Complete        bool  `json:"complete"`
+}
+
+// ShowCoverageResponse summarizes assignment coverage across a show's seasons
+type ShowCoverageResponse struct {
+	ShowID        int64                    `json:"show_id"`
+	Title         string                   `json:"title"`
+	EpisodeCount  int                      `json:"episode_count"`
+	AssignedCount int                      `json:"assigned_count"`
+	Seasons       []SeasonCoverageResponse `json:"seasons"`
+}
+
+// getShowCoverage returns per-season counts of assigned and missing episodes
+func (s *Server) getShowCoverage(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return
+	}
+
+	coverage, err := s.showRepo.GetCoverage(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := ShowCoverageResponse{
+		ShowID:  show.ID,
+		Title:   show.Title,
+		Seasons: make([]SeasonCoverageResponse, len(coverage)),
+	}
+	for i, season := range coverage {
+		resp.EpisodeCount += season.EpisodeCount
+		resp.AssignedCount += season.AssignedCount
+		resp.Seasons[i] = SeasonCoverageResponse{
+			SeasonNumber:    season.SeasonNumber,
+			EpisodeCount:    season.EpisodeCount,
+			AssignedCount:   season.AssignedCount,
+			MissingEpisodes: season.MissingEpisodes,
+			Complete:        season.EpisodeCount > 0 && season.AssignedCount == season.EpisodeCount,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
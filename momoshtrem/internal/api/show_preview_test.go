@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// previewTorrents adds a one-episode torrent and reports its removal
+type previewTorrents struct {
+	torrent.Service
+	removed chan string
+}
+
+func (f *previewTorrents) GetTorrent(string) (*torrent.TorrentInfo, error) {
+	return nil, torrent.ErrTorrentNotFound
+}
+
+func (f *previewTorrents) AddTorrent(_ context.Context, magnetURI string) (*torrent.TorrentInfo, error) {
+	return &torrent.TorrentInfo{
+		InfoHash: torrent.ExtractInfoHash(magnetURI),
+		Name:     "Preview.Test.S01.1080p",
+		Files:    []identify.TorrentFile{{Path: "Preview.Test.S01E01.1080p.mkv", Size: 1 << 30}},
+	}, nil
+}
+
+func (f *previewTorrents) RemoveTorrent(infoHash string, _ bool) error {
+	f.removed <- infoHash
+	return nil
+}
+
+func TestAssignShowTorrentDryRunReleasesTorrent(t *testing.T) {
+	_, db := openTestServer(t)
+	torrents := &previewTorrents{removed: make(chan string, 1)}
+	shows := library.NewShowRepository(db)
+	assignments := library.NewAssignmentRepository(db)
+	s := NewServer(library.NewMovieRepository(db), shows, assignments, nil, torrents, nil, identify.DefaultConfig())
+	s.inspected.grace = 10 * time.Millisecond
+
+	show := createTestShow(t, db, "Preview Test", 1)
+	ep := show.Seasons[0].Episodes[0]
+
+	const hash = "fedcba9876543210fedcba9876543210fedcba98"
+	body := `{"magnet_uri":"magnet:?xt=urn:btih:` + hash + `"}`
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/shows/%d/assign-torrent?dry_run=true", show.ID), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := serve(s, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+	}
+	var resp ShowAssignmentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !resp.DryRun || len(resp.Matched) != 1 || resp.Matched[0].EpisodeID != ep.ID {
+		t.Errorf("preview = %+v, want a dry run matching episode %d", resp, ep.ID)
+	}
+
+	if got, err := assignments.GetActiveForItem(library.ItemTypeEpisode, ep.ID); err != nil || got != nil {
+		t.Errorf("active assignment after dry run = %+v, %v; want none", got, err)
+	}
+	select {
+	case got := <-torrents.removed:
+		if got != hash {
+			t.Errorf("removed %q, want the previewed %q", got, hash)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("previewed torrent was never released")
+	}
+}
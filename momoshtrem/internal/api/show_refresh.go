@@ -0,0 +1,124 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/service"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// RefreshShowResponse reports seasons and episodes picked up from TMDB
+type RefreshShowResponse struct {
+	SeasonsAdded    int                      `json:"seasons_added"`
+	EpisodesAdded   int                      `json:"episodes_added"`
+	EpisodesRenamed int                      `json:"episodes_renamed"`
+	Renamed         []RenamedEpisodeResponse `json:"renamed,omitempty"`
+	Errors          []string                 `json:"errors,omitempty"` // Seasons that failed to refresh
+	Show            ShowResponse             `json:"show"`
+}
+
+// RenamedEpisodeResponse is an episode whose name changed. Assigned episodes
+// get a new VFS file name, so players holding the old path must re-list.
+type RenamedEpisodeResponse struct {
+	EpisodeID     int64  `json:"episode_id"`
+	SeasonNumber  int    `json:"season"`
+	EpisodeNumber int    `json:"episode"`
+	OldName       string `json:"old_name"`
+	NewName       string `json:"new_name"`
+	HasAssignment bool   `json:"has_assignment"`
+}
+
+// refreshShow re-fetches a show from TMDB and adds newly announced seasons and episodes
+func (s *Server) refreshShow(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := s.showService.RefreshShow(c.Request.Context(), id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := RefreshShowResponse{
+		SeasonsAdded:    result.SeasonsAdded,
+		EpisodesAdded:   result.EpisodesAdded,
+		EpisodesRenamed: result.EpisodesRenamed,
+		Show:            toShowResponse(result.Show),
+	}
+	if len(result.Renamed) > 0 {
+		assigned := s.renameEpisodesInTree(result.Show, result.Renamed)
+		for _, r := range result.Renamed {
+			resp.Renamed = append(resp.Renamed, RenamedEpisodeResponse{
+				EpisodeID:     r.Episode.ID,
+				SeasonNumber:  r.SeasonNumber,
+				EpisodeNumber: r.Episode.EpisodeNumber,
+				OldName:       r.OldName,
+				NewName:       r.Episode.Name,
+				HasAssignment: assigned[r.Episode.ID] != nil,
+			})
+		}
+	}
+	for _, se := range result.SeasonErrors {
+		slog.Warn("Season refresh error", "show_id", id, "error", se.Error())
+		resp.Errors = append(resp.Errors, se.Error())
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// renameEpisodesInTree moves the VFS files of renamed assigned episodes, with
+// their subtitle sidecars, to their new names, since episode names are part of
+// VFS file names. Returns the active assignments of the renamed episodes by episode ID.
+func (s *Server) renameEpisodesInTree(show *library.Show, renamed []service.RenamedEpisode) map[int64]*library.TorrentAssignment {
+	ids := make([]int64, len(renamed))
+	for i, r := range renamed {
+		ids[i] = r.Episode.ID
+	}
+	assignments, err := s.assignmentRepo.GetActiveForItems(library.ItemTypeEpisode, ids)
+	if err != nil {
+		// The new names can't be matched to files; rebuild everything instead
+		slog.Error("Failed to load assignments of renamed episodes", "show_id", show.ID, "error", err)
+		if s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+		}
+		return nil
+	}
+	if len(assignments) == 0 || s.treeUpdater == nil {
+		return assignments
+	}
+
+	for _, r := range renamed {
+		assignment := assignments[r.Episode.ID]
+		if assignment == nil {
+			continue
+		}
+		s.treeUpdater.RenameEpisodeInTree(vfs.EpisodeWithContext{
+			ShowTitle:    show.Title,
+			ShowYear:     show.Year,
+			SeasonNumber: r.SeasonNumber,
+			Episode:      r.Episode,
+			Assignment:   assignment,
+
+			ShowPosterURL:   show.PosterURL,
+			ShowBackdropURL: show.BackdropURL,
+			ShowCreatedAt:   show.CreatedAt,
+			ShowTMDBID:      show.TMDBID,
+			ShowOverview:    show.Overview,
+		})
+		slog.Info("Assigned episode renamed",
+			"show_id", show.ID,
+			"season", r.SeasonNumber,
+			"episode", r.Episode.EpisodeNumber,
+			"old_name", r.OldName,
+			"new_name", r.Episode.Name,
+		)
+	}
+
+	return assignments
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/jobs"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/service"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// renameRecorder records the tree updates a refresh makes
+type renameRecorder struct {
+	vfs.TreeUpdater
+	renamed     []vfs.EpisodeWithContext
+	invalidated int
+}
+
+func (r *renameRecorder) RenameEpisodeInTree(ep vfs.EpisodeWithContext) {
+	r.renamed = append(r.renamed, ep)
+}
+
+func (r *renameRecorder) InvalidateTree() { r.invalidated++ }
+
+func TestRenameEpisodesInTreeIsTargeted(t *testing.T) {
+	s, db := openTestServer(t)
+	updater := &renameRecorder{}
+	s.treeUpdater = updater
+
+	// Episode 1 is assigned, episode 2 isn't
+	show := createTestShow(t, db, "Rename Test", 2)
+	var renamed []service.RenamedEpisode
+	for i := range show.Seasons[0].Episodes {
+		ep := &show.Seasons[0].Episodes[i]
+		ep.Name = "New Name"
+		renamed = append(renamed, service.RenamedEpisode{Episode: ep, SeasonNumber: 1, OldName: "TBA"})
+	}
+	assignment := createTestAssignment(t, db, &library.TorrentAssignment{
+		ItemType: library.ItemTypeEpisode,
+		ItemID:   renamed[0].Episode.ID,
+		FilePath: "Show.S01E01.mkv",
+	})
+
+	got := s.renameEpisodesInTree(show, renamed)
+	if got[renamed[0].Episode.ID] == nil || got[renamed[1].Episode.ID] != nil {
+		t.Errorf("returned assignments = %v, want only episode 1's", got)
+	}
+	if len(updater.renamed) != 1 || updater.renamed[0].Episode.ID != renamed[0].Episode.ID {
+		t.Fatalf("renamed in tree = %+v, want only the assigned episode", updater.renamed)
+	}
+	if updater.renamed[0].Assignment.ID != assignment.ID {
+		t.Errorf("rename carries assignment %d, want %d", updater.renamed[0].Assignment.ID, assignment.ID)
+	}
+	if updater.invalidated != 0 {
+		t.Errorf("InvalidateTree called %d times, want targeted updates only", updater.invalidated)
+	}
+}
+
+func TestRefreshShowRejectedWhenQueueFull(t *testing.T) {
+	s := NewServer(nil, nil, nil, nil, nil, nil, identify.DefaultConfig())
+	q := jobs.NewQueue(jobs.Config{Workers: 1})
+	s.SetJobQueue(q)
+
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	w := serve(s, httptest.NewRequest(http.MethodPost, "/api/shows/1/refresh", nil))
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("status = %d, Retry-After %q; want 429 with Retry-After", w.Code, w.Header().Get("Retry-After"))
+	}
+}
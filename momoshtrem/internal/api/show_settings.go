@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// maxPreferredSubtitleLangs bounds the language list of a show
+const maxPreferredSubtitleLangs = 10
+
+// subtitleLangPattern matches OpenSubtitles language codes (e.g. "en", "pt-br")
+var subtitleLangPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2})?$`)
+
+// ShowSettingsRequest replaces the settings of a show
+type ShowSettingsRequest struct {
+	// Most preferred first; empty falls back to the global subtitles settings
+	PreferredSubtitleLangs []string `json:"preferred_subtitle_langs"`
+}
+
+// ShowSettingsResponse is the settings of a show
+type ShowSettingsResponse struct {
+	ShowID                 int64    `json:"show_id"`
+	PreferredSubtitleLangs []string `json:"preferred_subtitle_langs"`
+}
+
+// getShowSettings returns a show's settings, empty when none were saved
+func (s *Server) getShowSettings(c *gin.Context) {
+	id, ok := s.parseShowSettingsShow(c)
+	if !ok {
+		return
+	}
+
+	settings, err := s.showSettingsRepo.Get(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := ShowSettingsResponse{ShowID: id, PreferredSubtitleLangs: []string{}}
+	if settings != nil {
+		resp.PreferredSubtitleLangs = settings.PreferredSubtitleLangs
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// setShowSettings replaces a show's settings. The preferred subtitle languages
+// are downloaded for new assignments instead of the configured ones, and pick
+// the default subtitle sidecar of the show's episodes in the VFS.
+func (s *Server) setShowSettings(c *gin.Context) {
+	id, ok := s.parseShowSettingsShow(c)
+	if !ok {
+		return
+	}
+
+	var req ShowSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	langs, ok := parseSubtitleLangs(c, "preferred_subtitle_langs", req.PreferredSubtitleLangs)
+	if !ok {
+		return
+	}
+
+	if err := s.showSettingsRepo.Save(&library.ShowSettings{ShowID: id, PreferredSubtitleLangs: langs}); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Default subtitle names in the VFS follow the preference
+	if s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	c.JSON(http.StatusOK, ShowSettingsResponse{ShowID: id, PreferredSubtitleLangs: langs})
+}
+
+// parseSubtitleLangs lowercases, validates and dedupes language codes,
+// keeping their order. field names the request field in errors.
+func parseSubtitleLangs(c *gin.Context, field string, codes []string) ([]string, bool) {
+	if len(codes) > maxPreferredSubtitleLangs {
+		errorResponse(c, http.StatusBadRequest, "too many "+field)
+		return nil, false
+	}
+
+	langs := make([]string, 0, len(codes))
+	seen := make(map[string]bool)
+	for _, lang := range codes {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if !subtitleLangPattern.MatchString(lang) {
+			errorResponse(c, http.StatusBadRequest, "invalid language code: "+lang)
+			return nil, false
+		}
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	return langs, true
+}
+
+// parseShowSettingsShow parses the show ID and verifies the show exists
+func (s *Server) parseShowSettingsShow(c *gin.Context) (int64, bool) {
+	if s.showSettingsRepo == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Show settings not configured")
+		return 0, false
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return 0, false
+	}
+
+	show, err := s.showRepo.GetByID(id)
+	if err != nil {
+		handleError(c, err)
+		return 0, false
+	}
+	if show == nil {
+		errorResponse(c, http.StatusNotFound, "Show not found")
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// invalidateRecorder counts full tree rebuilds
+type invalidateRecorder struct {
+	vfs.TreeUpdater
+	invalidated int
+}
+
+func (r *invalidateRecorder) InvalidateTree() { r.invalidated++ }
+
+func TestParseSubtitleLangs(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []string
+		want  []string // nil when rejected
+	}{
+		{"normalized and deduplicated in order", []string{"ES", " en", "es", "pt-br"}, []string{"es", "en", "pt-br"}},
+		{"empty clears", []string{}, []string{}},
+		{"invalid code", []string{"english"}, nil},
+		{"too many", []string{"aa", "ab", "ac", "ad", "ae", "af", "ag", "ah", "ai", "aj", "ak"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			got, ok := parseSubtitleLangs(c, "preferred_subtitle_langs", tt.codes)
+			if tt.want == nil {
+				if ok || w.Code != http.StatusBadRequest {
+					t.Errorf("parseSubtitleLangs = %v, %v (status %d); want rejected with 400", got, ok, w.Code)
+				}
+				return
+			}
+			if !ok || !slices.Equal(got, tt.want) {
+				t.Errorf("parseSubtitleLangs = %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowSettingsEndpoints(t *testing.T) {
+	s, db := openTestServer(t)
+	s.SetShowSettingsRepository(library.NewShowSettingsRepository(db))
+	updater := &invalidateRecorder{}
+	s.treeUpdater = updater
+
+	show := createTestShow(t, db, "Settings Test")
+	settingsURL := fmt.Sprintf("/api/shows/%d/settings", show.ID)
+
+	put := func(url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return serve(s, req)
+	}
+	decode := func(w *httptest.ResponseRecorder) ShowSettingsResponse {
+		t.Helper()
+		var resp ShowSettingsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %s: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, settingsURL, nil))
+	if w.Code != http.StatusOK || len(decode(w).PreferredSubtitleLangs) != 0 {
+		t.Fatalf("unset settings = %d %s, want 200 with no languages", w.Code, w.Body.String())
+	}
+
+	// Codes are normalized and deduplicated, keeping preference order
+	w = put(settingsURL, `{"preferred_subtitle_langs": ["ES", " en", "es", "pt-br"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d (body %s)", w.Code, w.Body.String())
+	}
+	want := []string{"es", "en", "pt-br"}
+	if got := decode(w).PreferredSubtitleLangs; !slices.Equal(got, want) {
+		t.Errorf("PUT languages = %v, want %v", got, want)
+	}
+	if updater.invalidated != 1 {
+		t.Errorf("InvalidateTree called %d times, want 1 so subtitle names follow", updater.invalidated)
+	}
+
+	w = serve(s, httptest.NewRequest(http.MethodGet, settingsURL, nil))
+	if got := decode(w).PreferredSubtitleLangs; !slices.Equal(got, want) {
+		t.Errorf("GET languages = %v, want the saved %v", got, want)
+	}
+
+	// An empty list clears the preference
+	w = put(settingsURL, `{"preferred_subtitle_langs": []}`)
+	if w.Code != http.StatusOK || len(decode(w).PreferredSubtitleLangs) != 0 {
+		t.Errorf("clearing PUT = %d %s, want 200 with no languages", w.Code, w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		name, url, body string
+		want            int
+	}{
+		{"invalid code", settingsURL, `{"preferred_subtitle_langs": ["english"]}`, http.StatusBadRequest},
+		{"too many", settingsURL, `{"preferred_subtitle_langs": ["aa","ab","ac","ad","ae","af","ag","ah","ai","aj","ak"]}`, http.StatusBadRequest},
+		{"malformed body", settingsURL, `{"preferred_subtitle_langs": "en"}`, http.StatusBadRequest},
+		{"unknown show", "/api/shows/999999999/settings", `{"preferred_subtitle_langs": ["en"]}`, http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := put(tt.url, tt.body); w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
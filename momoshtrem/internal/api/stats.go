@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// StatsResponse summarizes library size and torrent storage for capacity planning
+type StatsResponse struct {
+	Movies   ItemCounts `json:"movies"`
+	Shows    ItemCounts `json:"shows"`
+	Episodes ItemCounts `json:"episodes"`
+
+	AssignedBytes   int64 `json:"assigned_bytes"`   // Declared size of every actively assigned file
+	DownloadedBytes int64 `json:"downloaded_bytes"` // Completed bytes across loaded torrents
+	ActiveTorrents  int   `json:"active_torrents"`  // Torrents currently loaded in the client
+	CacheSizeBytes  int64 `json:"cache_size_bytes"` // Configured torrent piece cache size
+}
+
+// ItemCounts is a library item total and how many of the items have a torrent assigned
+type ItemCounts struct {
+	Total    int `json:"total"`
+	Assigned int `json:"assigned"`
+}
+
+// SetCacheSize reports the configured torrent cache size in GET /api/stats
+func (s *Server) SetCacheSize(bytes int64) {
+	s.cacheSizeBytes = bytes
+}
+
+// getStats returns library counts and storage totals in one call
+func (s *Server) getStats(c *gin.Context) {
+	movies, assignedMovies, err := s.movieRepo.Count()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	shows, err := s.showRepo.Count()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	assignedBytes, err := s.assignmentRepo.TotalActiveSize()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	resp := StatsResponse{
+		Movies:         ItemCounts{Total: movies, Assigned: assignedMovies},
+		Shows:          ItemCounts{Total: shows.Shows, Assigned: shows.AssignedShows},
+		Episodes:       ItemCounts{Total: shows.Episodes, Assigned: shows.AssignedEpisodes},
+		AssignedBytes:  assignedBytes,
+		CacheSizeBytes: s.cacheSizeBytes,
+	}
+
+	if s.torrentService != nil {
+		resp.addTorrentStats(s.torrentService.CollectStats())
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// addTorrentStats counts the loaded torrents and their completed bytes
+func (r *StatsResponse) addTorrentStats(stats []torrent.FullStats) {
+	r.ActiveTorrents = len(stats)
+	for _, st := range stats {
+		r.DownloadedBytes += st.BytesCompleted
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// statsTorrents reports fixed per-torrent download totals
+type statsTorrents struct {
+	torrent.Service
+	stats []torrent.FullStats
+}
+
+func (f *statsTorrents) CollectStats() []torrent.FullStats { return f.stats }
+
+func TestStatsAddTorrentStats(t *testing.T) {
+	var resp StatsResponse
+	resp.addTorrentStats([]torrent.FullStats{
+		{InfoHash: "a", BytesCompleted: 100},
+		{InfoHash: "b", BytesCompleted: 200},
+	})
+	if resp.ActiveTorrents != 2 || resp.DownloadedBytes != 300 {
+		t.Errorf("torrents %d, downloaded %d; want 2, 300", resp.ActiveTorrents, resp.DownloadedBytes)
+	}
+
+	resp = StatsResponse{}
+	resp.addTorrentStats(nil)
+	if resp.ActiveTorrents != 0 || resp.DownloadedBytes != 0 {
+		t.Errorf("without torrents: torrents %d, downloaded %d; want zero", resp.ActiveTorrents, resp.DownloadedBytes)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s, db := openTestServer(t)
+
+	s.SetCacheSize(64 << 20)
+	s.torrentService = &statsTorrents{stats: []torrent.FullStats{
+		{InfoHash: "a", BytesCompleted: 100},
+		{InfoHash: "b", BytesCompleted: 200},
+	}}
+
+	get := func() StatsResponse {
+		t.Helper()
+		w := serve(s, httptest.NewRequest(http.MethodGet, "/api/stats", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+		}
+		var resp StatsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+	assign := func(itemType library.ItemType, itemID, size int64) {
+		t.Helper()
+		createTestAssignment(t, db, &library.TorrentAssignment{
+			ItemType: itemType,
+			ItemID:   itemID,
+			FilePath: "File.mkv",
+			FileSize: size,
+		})
+	}
+
+	before := get()
+	if before.CacheSizeBytes != 64<<20 || before.DownloadedBytes != 300 || before.ActiveTorrents != 2 {
+		t.Errorf("cache %d, downloaded %d, torrents %d; want %d, 300, 2",
+			before.CacheSizeBytes, before.DownloadedBytes, before.ActiveTorrents, 64<<20)
+	}
+
+	// An assigned movie, and a show with one of its two episodes assigned
+	movie := createTestMovie(t, db, "Stats Test")
+	assign(library.ItemTypeMovie, movie.ID, 1000)
+	show := createTestShow(t, db, "Stats Test", 2)
+	assign(library.ItemTypeEpisode, show.Seasons[0].Episodes[0].ID, 500)
+
+	after := get()
+	diff := func(name string, got, want int) {
+		t.Helper()
+		if got != want {
+			t.Errorf("%s grew by %d, want %d", name, got, want)
+		}
+	}
+	diff("movies", after.Movies.Total-before.Movies.Total, 1)
+	diff("assigned movies", after.Movies.Assigned-before.Movies.Assigned, 1)
+	diff("shows", after.Shows.Total-before.Shows.Total, 1)
+	diff("assigned shows", after.Shows.Assigned-before.Shows.Assigned, 1)
+	diff("episodes", after.Episodes.Total-before.Episodes.Total, 2)
+	diff("assigned episodes", after.Episodes.Assigned-before.Episodes.Assigned, 1)
+	if got := after.AssignedBytes - before.AssignedBytes; got != 1500 {
+		t.Errorf("assigned bytes grew by %d, want 1500", got)
+	}
+}
@@ -1,25 +1,34 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/library"
 	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
 )
 
 // Subtitle request/response types
 
 type SubtitleSearchResult struct {
-	FileID        int     `json:"file_id"`
-	LanguageCode  string  `json:"language_code"`
-	LanguageName  string  `json:"language_name"`
-	ReleaseName   string  `json:"release_name"`
-	DownloadCount int     `json:"download_count"`
-	FileName      string  `json:"file_name"`
-	Ratings       float64 `json:"ratings"`
+	FileID         int     `json:"file_id"`
+	LanguageCode   string  `json:"language_code"`
+	LanguageName   string  `json:"language_name"`
+	ReleaseName    string  `json:"release_name"`
+	DownloadCount  int     `json:"download_count"`
+	FileName       string  `json:"file_name"`
+	Ratings        float64 `json:"ratings"`
+	MovieHashMatch bool    `json:"moviehash_match"` // Matched the exact video file; likely in sync
 }
 
 type SubtitleSearchResponse struct {
@@ -34,13 +43,27 @@ type DownloadSubtitleRequest struct {
 	LanguageName string `json:"language_name" binding:"required"`
 }
 
+// movieHashTimeout bounds how long a search waits for the torrent to load and
+// supply the pieces needed for a moviehash
+const movieHashTimeout = 20 * time.Second
+
+// maxSubtitleOffsetMs bounds timing adjustments to one hour either way
+const maxSubtitleOffsetMs = 60 * 60 * 1000
+
+type SetSubtitleOffsetRequest struct {
+	OffsetMs *int64 `json:"offset_ms" binding:"required"` // Negative shifts cues earlier
+}
+
 type SubtitleResponse struct {
-	ID           int64  `json:"id"`
-	LanguageCode string `json:"language_code"`
-	LanguageName string `json:"language_name"`
-	Format       string `json:"format"`
-	FileSize     int64  `json:"file_size"`
-	CreatedAt    string `json:"created_at"`
+	ID              int64  `json:"id"`
+	LanguageCode    string `json:"language_code"`
+	LanguageName    string `json:"language_name"`
+	Format          string `json:"format"`
+	FileSize        int64  `json:"file_size"`
+	OffsetMs        int64  `json:"offset_ms"`
+	Forced          bool   `json:"forced"`
+	HearingImpaired bool   `json:"hearing_impaired"`
+	CreatedAt       string `json:"created_at"`
 }
 
 type SubtitleListResponse struct {
@@ -99,9 +122,19 @@ func (s *Server) searchSubtitles(c *gin.Context) {
 		EpisodeNumber: episodeNum,
 	}
 
+	// With a library item, also match by the hash of its assigned video file
+	if itemIDStr := c.Query("item_id"); itemIDStr != "" {
+		itemID, err := strconv.ParseInt(itemIDStr, 10, 64)
+		if err != nil || itemID <= 0 {
+			errorResponse(c, http.StatusBadRequest, "Invalid item_id")
+			return
+		}
+		params.MovieHash = s.itemMovieHash(c.Request.Context(), library.ItemType(mediaType), itemID)
+	}
+
 	searchResp, err := s.subtitleService.Search(c.Request.Context(), params)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, "Subtitle search failed: "+err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -116,16 +149,22 @@ func (s *Server) searchSubtitles(c *gin.Context) {
 		file := sub.Attributes.Files[0]
 
 		results = append(results, SubtitleSearchResult{
-			FileID:        file.FileID,
-			LanguageCode:  sub.Attributes.Language,
-			LanguageName:  opensubtitles.GetLanguageName(sub.Attributes.Language),
-			ReleaseName:   sub.Attributes.Release,
-			DownloadCount: sub.Attributes.DownloadCount,
-			FileName:      file.FileName,
-			Ratings:       sub.Attributes.Ratings,
+			FileID:         file.FileID,
+			LanguageCode:   sub.Attributes.Language,
+			LanguageName:   opensubtitles.GetLanguageName(sub.Attributes.Language),
+			ReleaseName:    sub.Attributes.Release,
+			DownloadCount:  sub.Attributes.DownloadCount,
+			FileName:       file.FileName,
+			Ratings:        sub.Attributes.Ratings,
+			MovieHashMatch: sub.Attributes.MovieHashMatch,
 		})
 	}
 
+	// Hash-matched subtitles were made for this exact release, so rank them first
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].MovieHashMatch && !results[j].MovieHashMatch
+	})
+
 	c.JSON(http.StatusOK, SubtitleSearchResponse{Results: results})
 }
 
@@ -158,7 +197,7 @@ func (s *Server) downloadSubtitle(c *gin.Context) {
 		req.LanguageName,
 	)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, "Subtitle download failed: "+err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -185,11 +224,7 @@ func (s *Server) deleteSubtitle(c *gin.Context) {
 	}
 
 	if err := s.subtitleService.Delete(c.Request.Context(), id); err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			errorResponse(c, http.StatusNotFound, "Subtitle not found")
-			return
-		}
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -201,6 +236,42 @@ func (s *Server) deleteSubtitle(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// setSubtitleOffset stores a timing offset applied to cue timestamps when the VFS serves the subtitle
+func (s *Server) setSubtitleOffset(c *gin.Context) {
+	if s.subtitleService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Subtitle service not configured")
+		return
+	}
+
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SetSubtitleOffsetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if *req.OffsetMs > maxSubtitleOffsetMs || *req.OffsetMs < -maxSubtitleOffsetMs {
+		errorResponse(c, http.StatusBadRequest, "offset_ms must be within one hour (±3600000)")
+		return
+	}
+
+	sub, err := s.subtitleService.SetOffset(c.Request.Context(), id, *req.OffsetMs)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	// Served subtitle content depends on the offset
+	if s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	c.JSON(http.StatusOK, toSubtitleResponse(sub))
+}
+
 func (s *Server) getMovieSubtitles(c *gin.Context) {
 	if s.subtitleService == nil {
 		errorResponse(c, http.StatusServiceUnavailable, "Subtitle service not configured")
@@ -214,7 +285,7 @@ func (s *Server) getMovieSubtitles(c *gin.Context) {
 
 	subtitles, err := s.subtitleService.GetByItem(c.Request.Context(), subtitle.ItemTypeMovie, id)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -236,7 +307,7 @@ func (s *Server) getEpisodeSubtitles(c *gin.Context) {
 
 	subtitles, err := s.subtitleService.GetByItem(c.Request.Context(), subtitle.ItemTypeEpisode, id)
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -245,16 +316,116 @@ func (s *Server) getEpisodeSubtitles(c *gin.Context) {
 	})
 }
 
+// getSubtitleAuth reports OpenSubtitles authentication state (never the token itself).
+// Climbing unauthorized_relogins or login_failures points at bad credentials.
+func (s *Server) getSubtitleAuth(c *gin.Context) {
+	if s.subtitleService == nil || !s.subtitleService.IsConfigured() {
+		errorResponse(c, http.StatusServiceUnavailable, "Subtitle service not configured")
+		return
+	}
+
+	stats, ok := s.subtitleService.AuthStats()
+	if !ok {
+		errorResponse(c, http.StatusNotImplemented, "Subtitle provider does not report auth state")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// SubtitleQuotaResponse is the OpenSubtitles download quota
+type SubtitleQuotaResponse struct {
+	Quota *opensubtitles.DownloadQuota `json:"quota"` // null until the first download
+}
+
+// getSubtitleQuota reports how many OpenSubtitles downloads are left and when
+// the allowance resets, as of the last download
+func (s *Server) getSubtitleQuota(c *gin.Context) {
+	if s.subtitleService == nil || !s.subtitleService.IsConfigured() {
+		errorResponse(c, http.StatusServiceUnavailable, "Subtitle service not configured")
+		return
+	}
+
+	quota, ok := s.subtitleService.DownloadQuota()
+	if !ok {
+		errorResponse(c, http.StatusNotImplemented, "Subtitle provider does not report a download quota")
+		return
+	}
+
+	c.JSON(http.StatusOK, SubtitleQuotaResponse{Quota: quota})
+}
+
+// itemMovieHash computes the OpenSubtitles moviehash of an item's assigned video.
+// Returns "" (search without a hash) if the item has no assignment or the
+// file can't supply the first and last 64KB within movieHashTimeout.
+func (s *Server) itemMovieHash(ctx context.Context, itemType library.ItemType, itemID int64) string {
+	assignment, err := s.assignmentRepo.GetActiveForItem(itemType, itemID)
+	if err != nil || assignment == nil {
+		return ""
+	}
+
+	var hash string
+	if assignment.IsLocal() {
+		hash, err = localMovieHash(assignment.LocalPath)
+	} else {
+		if s.torrentService == nil {
+			return ""
+		}
+		// The timeout covers loading the torrent too, not just the reads
+		ctx, cancel := context.WithTimeout(ctx, movieHashTimeout)
+		defer cancel()
+		hash, err = s.torrentMovieHash(ctx, assignment)
+	}
+	if err != nil {
+		slog.Warn("Failed to compute moviehash", "item_type", itemType, "item_id", itemID, "error", err)
+		return ""
+	}
+	return hash
+}
+
+// localMovieHash hashes a video file on local disk
+func localMovieHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return opensubtitles.MovieHash(f, info.Size())
+}
+
+// torrentMovieHash hashes an assignment's file through the torrent client
+func (s *Server) torrentMovieHash(ctx context.Context, assignment *library.TorrentAssignment) (string, error) {
+	if _, err := s.torrentService.GetOrAddTorrent(ctx, assignment.MagnetURI); err != nil {
+		return "", fmt.Errorf("load torrent %s: %w", assignment.InfoHash, err)
+	}
+	handle, err := s.torrentService.GetFile(assignment.InfoHash, assignment.FilePath)
+	if err != nil {
+		return "", fmt.Errorf("get file %s: %w", assignment.FilePath, err)
+	}
+
+	// ComputeMovieHash closes the reader
+	reader := torrent.NewFileReaderAt(handle)
+	return opensubtitles.ComputeMovieHash(ctx, reader, handle.Length())
+}
+
 // Helper functions
 
 func toSubtitleResponse(s *subtitle.Subtitle) SubtitleResponse {
 	return SubtitleResponse{
-		ID:           s.ID,
-		LanguageCode: s.LanguageCode,
-		LanguageName: s.LanguageName,
-		Format:       s.Format,
-		FileSize:     s.FileSize,
-		CreatedAt:    s.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:              s.ID,
+		LanguageCode:    s.LanguageCode,
+		LanguageName:    s.LanguageName,
+		Format:          s.Format,
+		FileSize:        s.FileSize,
+		OffsetMs:        s.OffsetMs,
+		Forced:          s.Forced,
+		HearingImpaired: s.HearingImpaired,
+		CreatedAt:       s.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// openTestServer returns a server over the Postgres database in
+// MOMOSHTREM_TEST_DATABASE_URL, skipping the test when it isn't set.
+// Optional dependencies (TMDB, torrents, VFS) are left unset.
+func openTestServer(t *testing.T) (*Server, *library.DB) {
+	t.Helper()
+	connStr := os.Getenv("MOMOSHTREM_TEST_DATABASE_URL")
+	if connStr == "" {
+		t.Skip("MOMOSHTREM_TEST_DATABASE_URL not set")
+	}
+	db, err := library.NewDB(connStr)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s := NewServer(
+		library.NewMovieRepository(db),
+		library.NewShowRepository(db),
+		library.NewAssignmentRepository(db),
+		nil, nil, nil,
+		identify.DefaultConfig(),
+	)
+	return s, db
+}
+
+// testTMDBID returns a random TMDB ID above any real one, so runs don't
+// collide with each other or with the library
+func testTMDBID() int {
+	return 900_000_000 + mathrand.IntN(100_000_000)
+}
+
+// createTestShow creates a show with episodesPerSeason[i] episodes in season
+// i+1 and returns it with its seasons and episodes. The show is deleted when
+// the test ends.
+func createTestShow(t *testing.T, db *library.DB, title string, episodesPerSeason ...int) *library.Show {
+	t.Helper()
+	shows := library.NewShowRepository(db)
+
+	show := &library.Show{TMDBID: testTMDBID(), Title: title, Year: 2020}
+	if err := shows.Create(show); err != nil {
+		t.Fatalf("Create show: %v", err)
+	}
+	t.Cleanup(func() { shows.Delete(show.ID) })
+
+	for i, count := range episodesPerSeason {
+		season := &library.Season{ShowID: show.ID, SeasonNumber: i + 1}
+		if err := shows.CreateSeason(season); err != nil {
+			t.Fatalf("CreateSeason: %v", err)
+		}
+		for n := 1; n <= count; n++ {
+			if err := shows.CreateEpisode(&library.Episode{SeasonID: season.ID, EpisodeNumber: n}); err != nil {
+				t.Fatalf("CreateEpisode: %v", err)
+			}
+		}
+	}
+
+	created, err := shows.GetWithSeasonsAndEpisodes(show.ID)
+	if err != nil {
+		t.Fatalf("GetWithSeasonsAndEpisodes: %v", err)
+	}
+	return created
+}
+
+// createTestMovie creates a movie that is deleted when the test ends.
+func createTestMovie(t *testing.T, db *library.DB, title string) *library.Movie {
+	t.Helper()
+	movies := library.NewMovieRepository(db)
+
+	movie := &library.Movie{TMDBID: testTMDBID(), Title: title, Year: 2020}
+	if err := movies.Create(movie); err != nil {
+		t.Fatalf("Create movie: %v", err)
+	}
+	t.Cleanup(func() { movies.Delete(movie.ID) })
+	return movie
+}
+
+// createTestAssignment stores a, filling in a random info hash and its magnet
+// when unset, and deletes it when the test ends.
+func createTestAssignment(t *testing.T, db *library.DB, a *library.TorrentAssignment) *library.TorrentAssignment {
+	t.Helper()
+	if a.InfoHash == "" {
+		b := make([]byte, 20)
+		rand.Read(b)
+		a.InfoHash = hex.EncodeToString(b)
+	}
+	if a.MagnetURI == "" {
+		a.MagnetURI = "magnet:?xt=urn:btih:" + a.InfoHash
+	}
+	if a.FileSize == 0 {
+		a.FileSize = 1
+	}
+
+	assignments := library.NewAssignmentRepository(db)
+	if err := assignments.Create(a); err != nil {
+		t.Fatalf("Create assignment: %v", err)
+	}
+	t.Cleanup(func() { assignments.DeleteByInfoHash(a.InfoHash) })
+	return a
+}
+
+// serve sends a request through the server's router and returns the response
+func serve(s *Server, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// fixedStreams reports the same open streams for every torrent
+type fixedStreams []vfs.StreamBufferStatus
+
+func (f fixedStreams) BufferStatus(_, _ string) []vfs.StreamBufferStatus { return f }
+
+func TestBitratesByPath(t *testing.T) {
+	bitrate := func(bps int64) *int64 { return &bps }
+	got := bitratesByPath([]*library.TorrentAssignment{
+		{FilePath: "Movie.CD1.mkv", PartPaths: []string{"Movie.CD1.mkv", "Movie.CD2.mkv"}, BitrateBps: bitrate(6_000_000)},
+		{FilePath: "Show.S01E01.mkv", BitrateBps: bitrate(8_000_000)},
+		{FilePath: "Show.S01E02.mkv"},                         // Not probed yet
+		{FilePath: "Show.S01E03.mkv", BitrateBps: bitrate(0)}, // Probe failed
+	})
+	want := map[string]int64{
+		"Movie.CD1.mkv":   6_000_000,
+		"Movie.CD2.mkv":   6_000_000,
+		"Show.S01E01.mkv": 8_000_000,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("bitratesByPath = %v, want %v", got, want)
+	}
+}
+
+func TestGetTorrentBufferWithoutAssignments(t *testing.T) {
+	s := &Server{}
+	s.streamInspector = fixedStreams{
+		{FilePath: "Movie.mkv", BufferStatus: streaming.BufferStatus{BufferedBytes: 10_000_000, ThroughputBps: 5_000_000}},
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/torrents/ABC/buffer", nil)
+	c.Params = gin.Params{{Key: "hash", Value: "ABC"}}
+
+	s.getTorrentBuffer(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+	}
+	var body BufferResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.InfoHash != "abc" || len(body.Streams) != 1 || body.Streams[0].BitrateBps != 0 {
+		t.Errorf("buffer = %+v, want hash abc and one stream without a bitrate", body)
+	}
+
+	// A path that isn't streamed is not found
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/torrents/abc/buffer?path=Other.mkv", nil)
+	c.Params = gin.Params{{Key: "hash", Value: "abc"}}
+	s.streamInspector = fixedStreams{}
+	s.getTorrentBuffer(c)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unstreamed path status = %d, want 404", w.Code)
+	}
+}
+
+func TestGetTorrentBufferUsesAssignmentBitrate(t *testing.T) {
+	s, db := openTestServer(t)
+	movie := createTestMovie(t, db, "Buffer Test")
+	assignment := createTestAssignment(t, db, &library.TorrentAssignment{
+		ItemType: library.ItemTypeMovie,
+		ItemID:   movie.ID,
+		FilePath: "Movie.mkv",
+	})
+	if err := library.NewAssignmentRepository(db).UpdateMediaInfo(assignment.ID, 8_000_000, 3600); err != nil {
+		t.Fatalf("UpdateMediaInfo: %v", err)
+	}
+
+	// 10 MB buffered at 8 Mbit/s is 10 seconds, whatever the download rate
+	s.streamInspector = fixedStreams{
+		{FilePath: "Movie.mkv", BufferStatus: streaming.BufferStatus{BufferedBytes: 10_000_000, ThroughputBps: 5_000_000}},
+		{FilePath: "Extras.mkv", BufferStatus: streaming.BufferStatus{BufferedBytes: 10_000_000}},
+	}
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/api/torrents/"+assignment.InfoHash+"/buffer", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+	}
+	var body BufferResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Streams) != 2 {
+		t.Fatalf("streams = %+v, want 2", body.Streams)
+	}
+	if got := body.Streams[0]; got.BitrateBps != 8_000_000 || got.BufferSeconds == nil || *got.BufferSeconds != 10 {
+		t.Errorf("assigned stream = bitrate %d, seconds %v; want 8000000 and 10", got.BitrateBps, got.BufferSeconds)
+	}
+	if got := body.Streams[1]; got.BufferSeconds != nil {
+		t.Errorf("unassigned stream without a playback rate has buffer_seconds %v, want null", *got.BufferSeconds)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Torrent event stream defaults
+const (
+	defaultTorrentEventsInterval = 2 * time.Second
+	torrentEventsKeepalive       = 30 * time.Second // Comment sent when nothing changed, for proxies
+	minTorrentEventsWriteTimeout = 10 * time.Second // Slow clients are dropped after this
+)
+
+// TorrentRemovedEvent lists torrents that are no longer loaded
+type TorrentRemovedEvent struct {
+	InfoHashes []string `json:"info_hashes"`
+}
+
+// eventStreams lets shutdown end long-lived SSE responses
+type eventStreams struct {
+	once sync.Once
+	done chan struct{}
+}
+
+func newEventStreams() *eventStreams {
+	return &eventStreams{done: make(chan struct{})}
+}
+
+func (e *eventStreams) close() {
+	e.once.Do(func() { close(e.done) })
+}
+
+// SetTorrentEventsInterval configures how often GET /api/torrents/events pushes changes
+func (s *Server) SetTorrentEventsInterval(interval time.Duration) {
+	if interval > 0 {
+		s.torrentEventsInterval = interval
+	}
+}
+
+// CloseEventStreams ends open SSE streams so the HTTP server can shut down
+// without waiting for clients to disconnect.
+func (s *Server) CloseEventStreams() {
+	s.events.close()
+}
+
+// streamTorrentEvents pushes torrent status changes as Server-Sent Events.
+// The first "snapshot" event lists every torrent; afterwards "update" events carry
+// only torrents whose status changed and "removed" events list dropped torrents.
+// GET /api/torrents/events
+func (s *Server) streamTorrentEvents(c *gin.Context) {
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	interval := s.torrentEventsInterval
+	if interval <= 0 {
+		interval = defaultTorrentEventsInterval
+	}
+	writeTimeout := max(2*interval, minTorrentEventsWriteTimeout)
+	rc := http.NewResponseController(c.Writer)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+	// send writes one event with a deadline, so a stalled client ends its own stream
+	// instead of piling up. Statuses are copied before writing; no service lock is held.
+	send := func(event string, data any) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(writeTimeout)) // Unsupported writers just block
+		if event == "" {
+			_, err := c.Writer.WriteString(": keepalive\n\n")
+			if err == nil {
+				err = rc.Flush()
+			}
+			return err == nil
+		}
+		c.SSEvent(event, data)
+		return rc.Flush() == nil
+	}
+
+	last := make(map[string]TorrentResponse)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastSent := time.Now()
+	first := true
+	for {
+		statuses, err := s.torrentService.ListTorrents()
+		if err != nil {
+			slog.Warn("Failed to list torrents for event stream", "error", err)
+		} else {
+			current := make(map[string]TorrentResponse, len(statuses))
+			changed := make([]TorrentResponse, 0)
+			for _, st := range statuses {
+				resp := statusToResponse(st)
+				current[resp.InfoHash] = resp
+				if prev, ok := last[resp.InfoHash]; first || !ok || prev != resp {
+					changed = append(changed, resp)
+				}
+			}
+			var removed []string
+			for hash := range last {
+				if _, ok := current[hash]; !ok {
+					removed = append(removed, hash)
+				}
+			}
+			last = current
+
+			switch {
+			case first:
+				if !send("snapshot", TorrentListResponse{Torrents: changed}) {
+					return
+				}
+				first = false
+				lastSent = time.Now()
+			case len(changed) > 0 || len(removed) > 0:
+				if len(changed) > 0 && !send("update", TorrentListResponse{Torrents: changed}) {
+					return
+				}
+				if len(removed) > 0 && !send("removed", TorrentRemovedEvent{InfoHashes: removed}) {
+					return
+				}
+				lastSent = time.Now()
+			case time.Since(lastSent) >= torrentEventsKeepalive:
+				if !send("", nil) {
+					return
+				}
+				lastSent = time.Now()
+			}
+		}
+
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-s.events.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// inspectGracePeriod is how long a torrent loaded only for inspection stays in
+// the client, giving the user time to pick files and assign them
+const inspectGracePeriod = 10 * time.Minute
+
+// InspectTorrentRequest is the request body for POST /api/torrents/inspect
+type InspectTorrentRequest struct {
+	MagnetURI string `json:"magnet_uri" binding:"required"`
+}
+
+// InspectTorrentResponse lists a torrent's files with tentative episode guesses
+type InspectTorrentResponse struct {
+	InfoHash  string                  `json:"info_hash"`
+	Name      string                  `json:"name"`
+	TotalSize int64                   `json:"total_size"`
+	Files     []InspectedFileResponse `json:"files"`
+}
+
+// InspectedFileResponse is one file of an inspected torrent. Season and
+// episodes are only set for files the identifier recognized.
+type InspectedFileResponse struct {
+	Path       string                `json:"path"`
+	Size       int64                 `json:"size"`
+	FileType   identify.FileType     `json:"file_type,omitempty"` // video or subtitle, empty for other files
+	Season     *int                  `json:"season,omitempty"`
+	Episodes   []int                 `json:"episodes,omitempty"`
+	IsSpecial  bool                  `json:"is_special,omitempty"`
+	Confidence identify.Confidence   `json:"confidence,omitempty"`
+	Quality    *identify.QualityInfo `json:"quality,omitempty"`
+}
+
+// inspectedTorrents schedules removal of torrents loaded only for inspection
+type inspectedTorrents struct {
+	grace  time.Duration
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func newInspectedTorrents() *inspectedTorrents {
+	return &inspectedTorrents{grace: inspectGracePeriod, timers: make(map[string]*time.Timer)}
+}
+
+// schedule runs release after the grace period. Inspecting a tracked torrent
+// again restarts its countdown; untracked ones are only added when track is set.
+func (it *inspectedTorrents) schedule(hash string, track bool, release func()) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	if timer, ok := it.timers[hash]; ok {
+		timer.Reset(it.grace)
+		return
+	}
+	if !track {
+		return
+	}
+
+	it.timers[hash] = time.AfterFunc(it.grace, func() {
+		it.mu.Lock()
+		delete(it.timers, hash)
+		it.mu.Unlock()
+		release()
+	})
+}
+
+// inspectTorrent loads a torrent and lists its files with the identifier's
+// season/episode guesses, without creating any assignment
+// POST /api/torrents/inspect
+func (s *Server) inspectTorrent(c *gin.Context) {
+	var req InspectTorrentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	infoHash := torrent.ExtractInfoHash(req.MagnetURI)
+	if infoHash == "" {
+		errorResponse(c, http.StatusBadRequest, "Invalid magnet URI")
+		return
+	}
+
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	// Torrents that were already loaded stay loaded; only ones added here are released
+	_, err := s.torrentService.GetTorrent(infoHash)
+	alreadyLoaded := err == nil
+
+	torrentInfo, err := s.torrentService.AddTorrent(c.Request.Context(), req.MagnetURI)
+	if errors.Is(err, torrent.ErrMetadataPending) {
+		// Still resolving in the background; release it like any other inspected torrent
+		s.scheduleInspectRelease(infoHash, alreadyLoaded)
+		resolvingResponse(c, req.MagnetURI)
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	s.scheduleInspectRelease(torrentInfo.InfoHash, alreadyLoaded)
+
+	identResult := s.identifier.Identify(torrentInfo.Files, torrentInfo.Name)
+	identified := make(map[string]identify.IdentifiedFile, len(identResult.IdentifiedFiles))
+	for _, f := range identResult.IdentifiedFiles {
+		identified[f.FilePath] = f
+	}
+
+	resp := InspectTorrentResponse{
+		InfoHash:  torrentInfo.InfoHash,
+		Name:      torrentInfo.Name,
+		TotalSize: torrentInfo.TotalSize,
+		Files:     make([]InspectedFileResponse, len(torrentInfo.Files)),
+	}
+	for i, f := range torrentInfo.Files {
+		file := InspectedFileResponse{Path: f.Path, Size: f.Size}
+		if id, ok := identified[f.Path]; ok {
+			season := id.Season
+			quality := id.Quality
+			file.FileType = id.FileType
+			file.Season = &season
+			file.Episodes = id.Episodes
+			file.IsSpecial = id.IsSpecial
+			file.Confidence = id.Confidence
+			file.Quality = &quality
+		} else if identify.IsVideoFile(f.Path) {
+			file.FileType = identify.FileTypeVideo
+		}
+		resp.Files[i] = file
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// scheduleInspectRelease releases an inspected torrent after the grace period,
+// unless it was loaded before the inspection
+func (s *Server) scheduleInspectRelease(infoHash string, alreadyLoaded bool) {
+	s.inspected.schedule(infoHash, !alreadyLoaded, func() {
+		s.releaseInspectedTorrent(infoHash)
+	})
+}
+
+// releaseInspectedTorrent drops a torrent loaded for inspection unless it has
+// been assigned in the meantime
+func (s *Server) releaseInspectedTorrent(infoHash string) {
+	assignments, err := s.assignmentRepo.GetActiveByInfoHash(infoHash)
+	if err != nil {
+		slog.Warn("Failed to check inspected torrent assignments", "info_hash", infoHash, "error", err)
+		return
+	}
+	if len(assignments) > 0 {
+		return
+	}
+
+	// deleteData only forgets the stored metadata; cached pieces stay
+	if err := s.torrentService.RemoveTorrent(infoHash, true); err != nil && !errors.Is(err, torrent.ErrTorrentNotFound) {
+		slog.Warn("Failed to remove inspected torrent", "info_hash", infoHash, "error", err)
+		return
+	}
+	slog.Info("Removed unassigned inspected torrent", "info_hash", infoHash)
+}
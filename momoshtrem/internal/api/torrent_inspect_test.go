@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// inspectTorrents is a torrent service whose adds are still resolving metadata
+type inspectTorrents struct {
+	torrent.Service
+	loaded bool
+}
+
+func (f *inspectTorrents) GetTorrent(string) (*torrent.TorrentInfo, error) {
+	if f.loaded {
+		return &torrent.TorrentInfo{}, nil
+	}
+	return nil, torrent.ErrTorrentNotFound
+}
+
+func (f *inspectTorrents) AddTorrent(context.Context, string) (*torrent.TorrentInfo, error) {
+	return nil, torrent.ErrMetadataPending
+}
+
+func TestInspectTorrentSchedulesReleaseWhileResolving(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	magnet := `{"magnet_uri":"magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567"}`
+
+	for _, loaded := range []bool{false, true} {
+		s := &Server{
+			torrentService: &inspectTorrents{loaded: loaded},
+			identifier:     identify.NewIdentifier(nil, identify.DefaultConfig()),
+			inspected:      newInspectedTorrents(),
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/torrents/inspect", strings.NewReader(magnet))
+		c.Request.Header.Set("Content-Type", "application/json")
+		s.inspectTorrent(c)
+
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusAccepted, w.Body)
+		}
+		s.inspected.mu.Lock()
+		timer, tracked := s.inspected.timers[hash]
+		s.inspected.mu.Unlock()
+		if tracked {
+			timer.Stop()
+		}
+		if tracked == loaded {
+			t.Errorf("already loaded = %v: release scheduled = %v, want %v", loaded, tracked, !loaded)
+		}
+	}
+}
+
+func TestInspectedTorrentsSchedule(t *testing.T) {
+	it := newInspectedTorrents()
+	it.grace = 20 * time.Millisecond
+
+	released := make(chan string, 2)
+	it.schedule("untracked", false, func() { released <- "untracked" })
+	it.schedule("tracked", true, func() { released <- "tracked" })
+	// A second inspection restarts the countdown instead of adding a release
+	it.schedule("tracked", true, func() { released <- "duplicate" })
+
+	select {
+	case got := <-released:
+		if got != "tracked" {
+			t.Errorf("released %q, want tracked", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tracked torrent was not released")
+	}
+	select {
+	case got := <-released:
+		t.Errorf("unexpected release of %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if len(it.timers) != 0 {
+		t.Errorf("%d timers left after release", len(it.timers))
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTorrentLimitsRequest overrides the peer limits of one torrent. Half-open
+// connection attempts are capped client-wide by torrent.max_half_open only.
+type SetTorrentLimitsRequest struct {
+	MaxEstablishedConns int `json:"max_established_conns" binding:"required,min=1"`
+}
+
+// setTorrentLimits overrides torrent.max_established_conns for a live torrent
+// until it is dropped
+// PUT /api/torrents/:hash/limits
+func (s *Server) setTorrentLimits(c *gin.Context) {
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	hash := c.Param("hash")
+
+	var req SetTorrentLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	previous, err := s.torrentService.SetMaxEstablishedConns(hash, req.MaxEstablishedConns)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":               true,
+		"max_established_conns": req.MaxEstablishedConns,
+		"previous":              previous,
+	})
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// QueueTorrentRequest is the request body for POST /api/episodes/:id/queue-torrent
+type QueueTorrentRequest struct {
+	MagnetURI string `json:"magnet_uri" binding:"required"`
+	AirDate   string `json:"air_date"` // YYYY-MM-DD; defaults to the episode's air date
+}
+
+// QueuedTorrentResponse describes a torrent waiting to be pre-warmed
+type QueuedTorrentResponse struct {
+	ID          int64      `json:"id"`
+	ItemType    string     `json:"item_type"`
+	ItemID      int64      `json:"item_id"`
+	InfoHash    string     `json:"info_hash"`
+	AirDate     string     `json:"air_date"`
+	PrewarmedAt *time.Time `json:"prewarmed_at"` // Null until the torrent has been added
+}
+
+// queueEpisodeTorrent queues a torrent to be added, but not assigned, when
+// the episode airs, so the first stream doesn't start cold
+// POST /api/episodes/:id/queue-torrent
+func (s *Server) queueEpisodeTorrent(c *gin.Context) {
+	id, ok := parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if s.prewarmer == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent pre-warming not enabled")
+		return
+	}
+
+	var req QueueTorrentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	infoHash := torrent.ExtractInfoHash(req.MagnetURI)
+	if infoHash == "" {
+		errorResponse(c, http.StatusBadRequest, "Invalid magnet URI")
+		return
+	}
+
+	episode, err := s.showRepo.GetEpisodeByID(id)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	if episode == nil {
+		errorResponse(c, http.StatusNotFound, "Episode not found")
+		return
+	}
+
+	airDate := req.AirDate
+	if airDate == "" {
+		if episode.AirDate == nil {
+			errorResponse(c, http.StatusBadRequest, "Episode has no air date; set air_date")
+			return
+		}
+		airDate = episode.AirDate.Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", airDate); err != nil {
+		errorResponse(c, http.StatusBadRequest, "air_date must be YYYY-MM-DD")
+		return
+	}
+
+	queued := &library.QueuedTorrent{
+		ItemType:  library.ItemTypeEpisode,
+		ItemID:    id,
+		InfoHash:  infoHash,
+		MagnetURI: req.MagnetURI,
+		AirDate:   airDate,
+	}
+	if err := s.prewarmer.Queue(queued); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, QueuedTorrentResponse{
+		ID:          queued.ID,
+		ItemType:    string(queued.ItemType),
+		ItemID:      queued.ItemID,
+		InfoHash:    queued.InfoHash,
+		AirDate:     queued.AirDate,
+		PrewarmedAt: queued.PrewarmedAt,
+	})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/service"
+)
+
+// ReidentifyTorrentResponse reports which assignments got new quality metadata
+type ReidentifyTorrentResponse struct {
+	Success      bool                             `json:"success"`
+	InfoHash     string                           `json:"info_hash"`
+	CheckedCount int                              `json:"checked_count"`
+	UpdatedCount int                              `json:"updated_count"`
+	Updated      []service.ReidentifiedAssignment `json:"updated"`
+}
+
+// reidentifyTorrent re-parses quality for every item assigned to :hash,
+// keeping the file each item points at
+func (s *Server) reidentifyTorrent(c *gin.Context) {
+	hash := c.Param("hash")
+
+	result, err := s.showAssignmentService.ReidentifyTorrent(c.Request.Context(), hash)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ReidentifyTorrentResponse{
+		Success:      true,
+		InfoHash:     result.InfoHash,
+		CheckedCount: result.Checked,
+		UpdatedCount: len(result.Updated),
+		Updated:      result.Updated,
+	})
+}
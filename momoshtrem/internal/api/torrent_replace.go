@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/service"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// ReplaceTorrentRequest names the torrent that takes over an existing torrent's items
+type ReplaceTorrentRequest struct {
+	MagnetURI string `json:"magnet_uri" binding:"required"`
+}
+
+// ReplaceTorrentResponse reports which items moved to the new torrent
+type ReplaceTorrentResponse struct {
+	Success        bool                           `json:"success"`
+	OldInfoHash    string                         `json:"old_info_hash"`
+	NewInfoHash    string                         `json:"new_info_hash"`
+	ReplacedCount  int                            `json:"replaced_count"`
+	UnmatchedCount int                            `json:"unmatched_count"`
+	Replaced       []service.ReplacedAssignment   `json:"replaced"`
+	Unmatched      []service.UnreplacedAssignment `json:"unmatched,omitempty"` // Still assigned to the old torrent
+}
+
+// replaceTorrent re-points every item assigned to :hash at a new torrent
+func (s *Server) replaceTorrent(c *gin.Context) {
+	hash := c.Param("hash")
+
+	var req ReplaceTorrentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.showAssignmentService.ReplaceTorrent(c.Request.Context(), hash, req.MagnetURI)
+	if errors.Is(err, torrent.ErrMetadataPending) {
+		resolvingResponse(c, req.MagnetURI)
+		return
+	}
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ReplaceTorrentResponse{
+		Success:        true,
+		OldInfoHash:    result.OldInfoHash,
+		NewInfoHash:    result.NewInfoHash,
+		ReplacedCount:  len(result.Replaced),
+		UnmatchedCount: len(result.Unmatched),
+		Replaced:       result.Replaced,
+		Unmatched:      result.Unmatched,
+	})
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// AddTrackersRequest lists tracker announce URLs to add to a torrent
+type AddTrackersRequest struct {
+	Trackers []string `json:"trackers" binding:"required,min=1"`
+}
+
+// addTorrentTrackers injects trackers into a live torrent to help it find peers
+// POST /api/torrents/:hash/trackers
+func (s *Server) addTorrentTrackers(c *gin.Context) {
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	hash := c.Param("hash")
+
+	var req AddTrackersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, tracker := range req.Trackers {
+		if !common.ValidTrackerURL(tracker) {
+			errorResponse(c, http.StatusBadRequest, "Invalid tracker URL: "+tracker)
+			return
+		}
+	}
+
+	if err := s.torrentService.AddTrackers(hash, req.Trackers); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "added": len(req.Trackers)})
+}
@@ -3,10 +3,14 @@ package api
 import (
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/notify"
 	"github.com/shapedtime/momoshtrem/internal/torrent"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
 )
 
 // TorrentListResponse contains a list of torrents
@@ -26,6 +30,40 @@ type TorrentResponse struct {
 	DownloadSpeed int64   `json:"download_speed"`
 	UploadSpeed   int64   `json:"upload_speed"`
 	IsPaused      bool    `json:"is_paused"`
+	IsIdle        bool    `json:"is_idle"`   // Network disabled by idle mode; the next read waits for peers
+	Resolving     bool    `json:"resolving"` // Metadata still arriving; name is the magnet's display name
+}
+
+// TorrentActivityResponse is the idle mode state of a torrent
+type TorrentActivityResponse struct {
+	InfoHash         string               `json:"info_hash"`
+	State            torrent.TorrentState `json:"state"` // active or idle
+	LastAccess       time.Time            `json:"last_access"`
+	SecondsUntilIdle int                  `json:"seconds_until_idle"` // 0 once idle
+}
+
+// BufferResponse contains buffer health for the open streams of a torrent
+type BufferResponse struct {
+	InfoHash string                 `json:"info_hash"`
+	Streams  []StreamBufferResponse `json:"streams"`
+}
+
+// TorrentFilesResponse contains per-file download progress for a torrent
+type TorrentFilesResponse struct {
+	InfoHash string                 `json:"info_hash"`
+	Files    []torrent.FileProgress `json:"files"`
+}
+
+// TorrentPiecesResponse contains the piece completion bitfield of a torrent
+type TorrentPiecesResponse struct {
+	InfoHash string `json:"info_hash"`
+	torrent.PieceMap
+}
+
+// StreamBufferResponse is the buffer health of one open stream
+type StreamBufferResponse struct {
+	vfs.StreamBufferStatus
+	BufferSeconds *float64 `json:"buffer_seconds"` // Null while neither bitrate nor playback rate is known
 }
 
 // listTorrents returns all active torrents
@@ -38,7 +76,7 @@ func (s *Server) listTorrents(c *gin.Context) {
 
 	statuses, err := s.torrentService.ListTorrents()
 	if err != nil {
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -73,13 +111,144 @@ func (s *Server) getTorrent(c *gin.Context) {
 			errorResponse(c, http.StatusNotFound, "Torrent not found")
 			return
 		}
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, statusToResponse(*status))
 }
 
+// getTorrentActivity reports whether a torrent is idle and when it will go idle,
+// to tell a slow start after idling apart from a torrent with no peers
+// GET /api/torrents/:hash/activity
+func (s *Server) getTorrentActivity(c *gin.Context) {
+	if s.activity == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Idle mode not enabled")
+		return
+	}
+
+	hash := c.Param("hash")
+	activity, ok := s.activity.GetActivity(hash)
+	if !ok {
+		errorResponse(c, http.StatusNotFound, "Torrent not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, TorrentActivityResponse{
+		InfoHash:         hash,
+		State:            activity.State,
+		LastAccess:       activity.LastAccess,
+		SecondsUntilIdle: int(activity.UntilIdle.Round(time.Second).Seconds()),
+	})
+}
+
+// getTorrentFiles reports how much of each file in a torrent is downloaded
+// GET /api/torrents/:hash/files
+func (s *Server) getTorrentFiles(c *gin.Context) {
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	hash := c.Param("hash")
+	files, err := s.torrentService.GetFileProgress(hash)
+	if err != nil {
+		if err == torrent.ErrTorrentNotFound {
+			errorResponse(c, http.StatusNotFound, "Torrent not found")
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TorrentFilesResponse{InfoHash: hash, Files: files})
+}
+
+// getTorrentPieces returns which pieces of a torrent are complete, for
+// rendering a download heatmap
+// GET /api/torrents/:hash/pieces
+func (s *Server) getTorrentPieces(c *gin.Context) {
+	if s.torrentService == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Torrent service not available")
+		return
+	}
+
+	hash := strings.ToLower(c.Param("hash"))
+	pieces, err := s.torrentService.GetPieceMap(hash)
+	if err != nil {
+		if err == torrent.ErrTorrentNotFound {
+			errorResponse(c, http.StatusNotFound, "Torrent not found")
+			return
+		}
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TorrentPiecesResponse{InfoHash: hash, PieceMap: *pieces})
+}
+
+// getTorrentBuffer reports how much data is buffered ahead of each open stream
+// GET /api/torrents/:hash/buffer?path=...
+func (s *Server) getTorrentBuffer(c *gin.Context) {
+	if s.streamInspector == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Stream inspection not available")
+		return
+	}
+
+	hash := strings.ToLower(c.Param("hash"))
+	filePath := c.Query("path")
+
+	streams := s.streamInspector.BufferStatus(hash, filePath)
+	if filePath != "" && len(streams) == 0 {
+		errorResponse(c, http.StatusNotFound, "File is not being streamed")
+		return
+	}
+
+	bitrates := s.assignmentBitrates(hash)
+	response := BufferResponse{
+		InfoHash: hash,
+		Streams:  make([]StreamBufferResponse, len(streams)),
+	}
+	for i, st := range streams {
+		st.BitrateBps = bitrates[st.FilePath]
+		response.Streams[i] = StreamBufferResponse{StreamBufferStatus: st}
+		if seconds, ok := st.BufferSeconds(); ok {
+			response.Streams[i].BufferSeconds = &seconds
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// assignmentBitrates maps each file path of a torrent's assignments (every
+// part of a split movie) to its probed bitrate, skipping unknown ones
+func (s *Server) assignmentBitrates(hash string) map[string]int64 {
+	if s.assignmentRepo == nil {
+		return nil
+	}
+	assignments, err := s.assignmentRepo.GetByInfoHash(hash)
+	if err != nil {
+		slog.Warn("Failed to load assignment bitrates", "hash", hash, "error", err)
+		return nil
+	}
+	return bitratesByPath(assignments)
+}
+
+// bitratesByPath maps the file paths of assignments to their probed bitrates
+func bitratesByPath(assignments []*library.TorrentAssignment) map[string]int64 {
+	bitrates := make(map[string]int64)
+	for _, a := range assignments {
+		if a.BitrateBps == nil || *a.BitrateBps <= 0 {
+			continue
+		}
+		bitrates[a.FilePath] = *a.BitrateBps
+		for _, part := range a.PartPaths {
+			bitrates[part] = *a.BitrateBps
+		}
+	}
+	return bitrates
+}
+
 // deleteTorrent removes a torrent
 // DELETE /api/torrents/:hash
 func (s *Server) deleteTorrent(c *gin.Context) {
@@ -118,7 +287,7 @@ func (s *Server) deleteTorrent(c *gin.Context) {
 			errorResponse(c, http.StatusNotFound, "Torrent not found")
 			return
 		}
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -132,6 +301,12 @@ func (s *Server) deleteTorrent(c *gin.Context) {
 		}
 	}
 
+	s.notifier.Notify(notify.EventTorrentRemoved, TorrentRemovedWebhook{
+		InfoHash:           hash,
+		DeleteData:         deleteData,
+		AssignmentsRemoved: assignmentsDeleted,
+	})
+
 	c.Status(http.StatusNoContent)
 }
 
@@ -183,7 +358,7 @@ func (s *Server) pauseTorrent(c *gin.Context) {
 			errorResponse(c, http.StatusNotFound, "Torrent not found")
 			return
 		}
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -209,7 +384,7 @@ func (s *Server) resumeTorrent(c *gin.Context) {
 			errorResponse(c, http.StatusNotFound, "Torrent not found")
 			return
 		}
-		errorResponse(c, http.StatusInternalServerError, err.Error())
+		handleError(c, err)
 		return
 	}
 
@@ -229,5 +404,7 @@ func statusToResponse(status torrent.TorrentStatus) TorrentResponse {
 		DownloadSpeed: status.DownloadSpeed,
 		UploadSpeed:   status.UploadSpeed,
 		IsPaused:      status.IsPaused,
+		IsIdle:        status.IsPaused, // The service reports idle mode as paused
+		Resolving:     status.Resolving,
 	}
 }
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// Continue watching limits
+const (
+	defaultContinueWatchingLimit = 20
+	maxContinueWatchingLimit     = 100
+)
+
+// Watch status request/response types
+
+type UpdateProgressRequest struct {
+	PositionSeconds *int  `json:"position_seconds" binding:"required"`
+	DurationSeconds int   `json:"duration_seconds"`
+	Watched         *bool `json:"watched,omitempty"` // Derived from position/duration when omitted
+}
+
+type WatchStatusResponse struct {
+	ItemType        string    `json:"item_type"`
+	ItemID          int64     `json:"item_id"`
+	PositionSeconds int       `json:"position_seconds"`
+	DurationSeconds int       `json:"duration_seconds"`
+	Watched         bool      `json:"watched"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ContinueWatchingItem struct {
+	WatchStatusResponse
+	Title         string `json:"title"`
+	Year          int    `json:"year,omitempty"`
+	SeasonNumber  int    `json:"season_number,omitempty"`
+	EpisodeNumber int    `json:"episode_number,omitempty"`
+}
+
+type ContinueWatchingResponse struct {
+	Items []ContinueWatchingItem `json:"items"`
+}
+
+// Watch status handlers
+
+func (s *Server) updateProgress(itemType library.ItemType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.watchStatusRepo == nil {
+			errorResponse(c, http.StatusServiceUnavailable, "Watch status not configured")
+			return
+		}
+
+		id, ok := parseID(c, "id")
+		if !ok {
+			return
+		}
+
+		var req UpdateProgressRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if *req.PositionSeconds < 0 || req.DurationSeconds < 0 {
+			errorResponse(c, http.StatusBadRequest, "position_seconds and duration_seconds must not be negative")
+			return
+		}
+
+		if !s.watchableItemExists(c, itemType, id) {
+			return
+		}
+
+		status := &library.WatchStatus{
+			ItemType:        itemType,
+			ItemID:          id,
+			PositionSeconds: *req.PositionSeconds,
+			DurationSeconds: req.DurationSeconds,
+		}
+		if req.Watched != nil {
+			status.Watched = *req.Watched
+		} else {
+			status.Watched = status.ReachedEnd()
+		}
+
+		if err := s.watchStatusRepo.Upsert(status); err != nil {
+			handleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, watchStatusToResponse(status))
+	}
+}
+
+func (s *Server) getContinueWatching(c *gin.Context) {
+	if s.watchStatusRepo == nil {
+		errorResponse(c, http.StatusServiceUnavailable, "Watch status not configured")
+		return
+	}
+
+	limit := defaultContinueWatchingLimit
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxContinueWatchingLimit {
+			errorResponse(c, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+
+	statuses, err := s.watchStatusRepo.ListInProgress(limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := ContinueWatchingResponse{Items: make([]ContinueWatchingItem, 0, len(statuses))}
+	for _, status := range statuses {
+		item := ContinueWatchingItem{WatchStatusResponse: watchStatusToResponse(status)}
+
+		// Progress of removed items is filtered out in SQL; skip items removed since
+		switch status.ItemType {
+		case library.ItemTypeMovie:
+			movie, err := s.movieRepo.GetByID(status.ItemID)
+			if err != nil {
+				slog.Warn("Failed to load movie for continue watching", "movie_id", status.ItemID, "error", err)
+				continue
+			}
+			if movie == nil {
+				continue
+			}
+			item.Title, item.Year = movie.Title, movie.Year
+		case library.ItemTypeEpisode:
+			ep, err := s.showRepo.GetEpisodeContext(status.ItemID)
+			if err != nil {
+				slog.Warn("Failed to load episode for continue watching", "episode_id", status.ItemID, "error", err)
+				continue
+			}
+			if ep == nil {
+				continue
+			}
+			item.Title, item.Year = ep.ShowTitle, ep.ShowYear
+			item.SeasonNumber, item.EpisodeNumber = ep.SeasonNumber, ep.EpisodeNumber
+		}
+
+		response.Items = append(response.Items, item)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// watchableItemExists verifies the movie or episode exists, responding with 404 if not
+func (s *Server) watchableItemExists(c *gin.Context, itemType library.ItemType, id int64) bool {
+	var exists bool
+	switch itemType {
+	case library.ItemTypeMovie:
+		movie, err := s.movieRepo.GetByID(id)
+		if err != nil {
+			handleError(c, err)
+			return false
+		}
+		exists = movie != nil
+	case library.ItemTypeEpisode:
+		episode, err := s.showRepo.GetEpisodeByID(id)
+		if err != nil {
+			handleError(c, err)
+			return false
+		}
+		exists = episode != nil
+	}
+
+	if !exists {
+		errorResponse(c, http.StatusNotFound, "Item not found")
+		return false
+	}
+	return true
+}
+
+func watchStatusToResponse(status *library.WatchStatus) WatchStatusResponse {
+	return WatchStatusResponse{
+		ItemType:        string(status.ItemType),
+		ItemID:          status.ItemID,
+		PositionSeconds: status.PositionSeconds,
+		DurationSeconds: status.DurationSeconds,
+		Watched:         status.Watched,
+		UpdatedAt:       status.UpdatedAt,
+	}
+}
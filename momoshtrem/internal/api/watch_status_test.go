@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestDeleteShowDropsEpisodeProgress(t *testing.T) {
+	s, db := openTestServer(t)
+	watch := library.NewWatchStatusRepository(db)
+	s.SetWatchStatusRepository(watch)
+
+	show := createTestShow(t, db, "Watch Status Test", 1)
+	ep := show.Seasons[0].Episodes[0]
+	progress := &library.WatchStatus{ItemType: library.ItemTypeEpisode, ItemID: ep.ID, PositionSeconds: 60, DurationSeconds: 1200}
+	if err := watch.Upsert(progress); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	t.Cleanup(func() { watch.Delete(library.ItemTypeEpisode, ep.ID) })
+
+	w := serve(s, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/shows/%d", show.ID), nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d (body %s)", w.Code, w.Body.String())
+	}
+	if got, err := watch.Get(library.ItemTypeEpisode, ep.ID); err != nil || got != nil {
+		t.Errorf("episode progress after show delete = %+v, %v; want none", got, err)
+	}
+}
+
+func TestContinueWatchingSkipsRemovedItemsBeforeLimit(t *testing.T) {
+	s, db := openTestServer(t)
+	watch := library.NewWatchStatusRepository(db)
+	s.SetWatchStatusRepository(watch)
+
+	movie := createTestMovie(t, db, "Continue Watching Test")
+
+	// Progress of the movie, then newer progress left behind by a removed episode
+	removedEpisodeID := 9_000_000_000 + mathrand.Int64N(1_000_000_000)
+	for _, status := range []*library.WatchStatus{
+		{ItemType: library.ItemTypeMovie, ItemID: movie.ID, PositionSeconds: 60, DurationSeconds: 6000},
+		{ItemType: library.ItemTypeEpisode, ItemID: removedEpisodeID, PositionSeconds: 60, DurationSeconds: 1200},
+	} {
+		if err := watch.Upsert(status); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+		t.Cleanup(func() { watch.Delete(status.ItemType, status.ItemID) })
+	}
+
+	w := serve(s, httptest.NewRequest(http.MethodGet, "/api/continue-watching?limit=1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %s)", w.Code, w.Body.String())
+	}
+	var resp ContinueWatchingResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ItemID != movie.ID {
+		t.Errorf("continue watching = %+v, want the movie despite the newer orphaned progress", resp.Items)
+	}
+}
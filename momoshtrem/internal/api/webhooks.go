@@ -0,0 +1,29 @@
+package api
+
+// Webhook payloads, sent as the "data" field of notify.Event
+
+// AssignmentWebhook is sent when a torrent is assigned to a movie or show
+type AssignmentWebhook struct {
+	ItemType  string `json:"item_type"` // "movie" or "show"
+	ItemID    int64  `json:"item_id"`
+	Title     string `json:"title,omitempty"`
+	Year      int    `json:"year,omitempty"`
+	InfoHash  string `json:"info_hash"`
+	FilePath  string `json:"file_path,omitempty"` // Movies only
+	Matched   int    `json:"matched"`             // Files assigned (episodes for shows)
+	Unmatched int    `json:"unmatched"`
+}
+
+// TorrentRemovedWebhook is sent when a torrent is removed via the API
+type TorrentRemovedWebhook struct {
+	InfoHash           string `json:"info_hash"`
+	DeleteData         bool   `json:"delete_data"`
+	AssignmentsRemoved int64  `json:"assignments_removed"`
+}
+
+// AirDateSyncWebhook is sent when a manually triggered air date sync finishes
+type AirDateSyncWebhook struct {
+	Trigger string `json:"trigger"`
+	Status  string `json:"status"` // "ok" or "error"
+	Error   string `json:"error,omitempty"`
+}
@@ -3,7 +3,6 @@ package common
 import (
 	"path"
 	"strconv"
-	"strings"
 )
 
 // Itoa converts an int to a string using strconv.Itoa.
@@ -27,9 +26,6 @@ func PadZero(n, width int) string {
 
 // CleanPath normalizes a path by cleaning it and ensuring it starts with /.
 func CleanPath(p string) string {
-	p = path.Clean(p)
-	if !strings.HasPrefix(p, "/") {
-		p = "/" + p
-	}
-	return p
+	// Rooting before cleaning keeps "" and "." at "/" and ".." from escaping it
+	return path.Clean("/" + p)
 }
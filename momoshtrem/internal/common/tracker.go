@@ -0,0 +1,18 @@
+package common
+
+import "net/url"
+
+// trackerSchemes are the announce URL schemes the torrent client can use
+var trackerSchemes = map[string]bool{
+	"udp":   true,
+	"http":  true,
+	"https": true,
+	"ws":    true,
+	"wss":   true,
+}
+
+// ValidTrackerURL reports whether s looks like a usable tracker announce URL
+func ValidTrackerURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && trackerSchemes[u.Scheme] && u.Host != ""
+}
@@ -20,13 +20,51 @@ type Config struct {
 	OpenSubtitles OpenSubtitlesConfig `yaml:"opensubtitles"`
 	Subtitles     SubtitlesConfig     `yaml:"subtitles"`
 	AirDateSync   AirDateSyncConfig   `yaml:"airdate_sync"`
+	Prewarm       PrewarmConfig       `yaml:"prewarm"`
 	Metrics       MetricsConfig       `yaml:"metrics"`
+	Identify      IdentifyConfig      `yaml:"identify"`
+	Jobs          JobsConfig          `yaml:"jobs"`
+	Webhooks      WebhooksConfig      `yaml:"webhooks"`
 }
 
 type ServerConfig struct {
 	HTTPPort   int              `yaml:"http_port"`
 	WebDAVPort int              `yaml:"webdav_port"`
 	WebDAVAuth WebDAVAuthConfig `yaml:"webdav_auth"`
+	APIAuth    APIAuthConfig    `yaml:"api_auth"`
+
+	// ShowZipDownload enables GET /api/shows/:id/download.zip, which streams
+	// every assigned episode of a show through the torrent client (default: false)
+	ShowZipDownload bool `yaml:"show_zip_download"`
+
+	// TorrentEventsIntervalSeconds is how often GET /api/torrents/events pushes
+	// changed torrent statuses (default: 2)
+	TorrentEventsIntervalSeconds int `yaml:"torrent_events_interval_seconds"`
+
+	// HealthCheckTMDB makes GET /api/health also require TMDB to be reachable.
+	// The result is cached for 30s so probes don't hit the TMDB API (default: false)
+	HealthCheckTMDB bool `yaml:"health_check_tmdb"`
+
+	// ShutdownDrainTimeoutSeconds is how long shutdown waits for open streams
+	// to finish before closing the torrent client. New streams are refused
+	// with 503 meanwhile; 0 closes immediately (default: 30)
+	ShutdownDrainTimeoutSeconds int `yaml:"shutdown_drain_timeout_seconds"`
+
+	// WebDAVAccessLog logs every WebDAV request with its byte range, status,
+	// bytes served, duration and user agent. Requests are logged at debug level
+	// so playback doesn't flood the log; server errors at warn (default: true)
+	WebDAVAccessLog bool `yaml:"webdav_access_log"`
+
+	// WebDAVBasePath serves the WebDAV tree under a path prefix (e.g. "/media"
+	// behind a reverse proxy at location /media/). Requests outside it get 404
+	// and PROPFIND responses carry it; empty or "/" serves at the root.
+	WebDAVBasePath string `yaml:"webdav_base_path"`
+
+	// WebDAVPublicURL is the address clients reach the WebDAV server at, used
+	// in show playlists (e.g. "https://nas.example.com/media"). Empty uses the
+	// API request's host with webdav_port and webdav_base_path. Playlist URLs
+	// carry the WebDAV credentials only when API auth is also enabled.
+	WebDAVPublicURL string `yaml:"webdav_public_url"`
 }
 
 // WebDAVAuthConfig configures authentication for the WebDAV server
@@ -36,6 +74,13 @@ type WebDAVAuthConfig struct {
 	Password string `yaml:"password"` // Password for Basic Auth
 }
 
+// APIAuthConfig configures API key authentication for the REST server
+type APIAuthConfig struct {
+	Enabled      bool     `yaml:"enabled"`       // Require an API key (default: false)
+	Keys         []string `yaml:"keys"`          // Accepted keys (X-API-Key or Authorization: Bearer)
+	ExemptStatus bool     `yaml:"exempt_status"` // Allow /api/status and /api/health* without a key (default: true)
+}
+
 type DatabaseConfig struct {
 	Path string `yaml:"path"`
 	URL  string `yaml:"url"`
@@ -45,21 +90,69 @@ type TorrentConfig struct {
 	MetadataFolder       string `yaml:"metadata_folder"`
 	GlobalCacheSize      int64  `yaml:"global_cache_size"`       // MB
 	AddTimeout           int    `yaml:"add_timeout"`             // seconds
+	AddRetries           int    `yaml:"add_retries"`             // Extra metadata attempts after add_timeout (default: 0)
+	AddRetryBackoff      int    `yaml:"add_retry_backoff"`       // seconds between attempts (default: 5)
+	ResolveInBackground  bool   `yaml:"resolve_in_background"`   // Keep fetching metadata after the last attempt; API answers 202
 	ReadTimeout          int    `yaml:"read_timeout"`            // seconds
 	IdleEnabled          bool   `yaml:"idle_enabled"`
 	IdleTimeout          int    `yaml:"idle_timeout"`            // seconds
 	StartPaused          bool   `yaml:"start_paused"`
 	DropDuplicatePeerIds bool   `yaml:"drop_duplicate_peer_ids"` // Prevent duplicate peer connections
 	MaxUnverifiedMB      int64  `yaml:"max_unverified_mb"`       // Cap in-flight unverified data (MB, 0=unlimited)
+
+	// How often idle mode looks for torrents past idle_timeout (default: 30, at most idle_timeout)
+	IdleCheckIntervalSeconds int `yaml:"idle_check_interval_seconds"`
+
+	// Peer discovery beyond trackers; disable both for private-tracker-only setups
+	EnableDHT bool `yaml:"enable_dht"` // default: true
+	EnablePEX bool `yaml:"enable_pex"` // default: true
+
+	// DefaultTrackers are announced to for every added torrent, in addition to its own
+	DefaultTrackers []string `yaml:"default_trackers"`
+
+	// Peer connection limits per torrent. Raise them to fill a fast link; lower
+	// them when a small device struggles with connection churn.
+	MaxEstablishedConns int `yaml:"max_established_conns"` // Connected peers (default: 50)
+	MaxHalfOpen         int `yaml:"max_half_open"`         // Connection attempts in flight (default: 25)
+
+	// PreloadAssignedTorrents adds every torrent with an active assignment at
+	// startup, so cached pieces are readable before the first stream (default: false)
+	PreloadAssignedTorrents bool `yaml:"preload_assigned_torrents"`
+
+	// MinSeeders rejects assigning a new torrent that connects to fewer seeders
+	// within a few seconds of its metadata arriving, and drops it. Torrents
+	// already loaded or assigned aren't checked (default: 0 = disabled)
+	MinSeeders int `yaml:"min_seeders"`
 }
 
 type TMDBConfig struct {
-	APIKey string `yaml:"api_key"`
+	APIKey          string `yaml:"api_key"`
+	CacheTTLMinutes int    `yaml:"cache_ttl_minutes"` // Cache movie/show/season lookups (default: 60, 0=disabled)
+	CacheDir        string `yaml:"cache_dir"`         // Also persist cached responses here (optional)
 }
 
 type VFSConfig struct {
-	TreeTTL  int    `yaml:"tree_ttl"`  // DEPRECATED: ignored, updates are now event-driven
-	CacheDir string `yaml:"cache_dir"` // Directory for persistent VFS tree cache
+	TreeTTL    int    `yaml:"tree_ttl"`    // DEPRECATED: ignored, updates are now event-driven
+	CacheDir   string `yaml:"cache_dir"`   // Directory for persistent VFS tree cache
+	FlatMovies bool   `yaml:"flat_movies"` // Also expose every movie file directly under /All Movies
+
+	// RebuildDelayMs coalesces tree invalidations arriving within this window
+	// into one full rebuild (default: 500ms)
+	RebuildDelayMs int `yaml:"rebuild_delay_ms"`
+
+	// TagFolderKey is the item metadata key whose comma-separated value builds
+	// /Tags/<tag>/ folders (empty disables tag folders)
+	TagFolderKey string `yaml:"tag_folder_key"`
+
+	// ForceExtension lists every video file with this extension (e.g. ".mkv",
+	// or ".m4v" for Apple TV) instead of the torrent file's own. File contents
+	// are not remuxed; empty keeps the original extension.
+	ForceExtension string `yaml:"force_extension"`
+
+	// LocalMediaRoots are directories whose video files can be assigned to
+	// library items and are read from disk instead of a torrent (empty
+	// disables local files)
+	LocalMediaRoots []string `yaml:"local_media_roots"`
 }
 
 // StreamingConfig configures streaming optimization for video playback
@@ -68,6 +161,17 @@ type StreamingConfig struct {
 	FooterPriorityBytes int64 `yaml:"footer_priority_bytes"` // Bytes at end to prioritize (default: 5MB)
 	ReadaheadBytes      int64 `yaml:"readahead_bytes"`       // Bytes to read ahead (default: 64MB)
 	UrgentBufferBytes   int64 `yaml:"urgent_buffer_bytes"`   // Immediate buffer around seek (default: 8MB)
+
+	// Adaptive readahead scales with measured playback throughput
+	ReadaheadMinMultiplier float64 `yaml:"readahead_min_multiplier"` // Smallest window as a multiple of readahead_bytes (default: 1)
+	ReadaheadMaxMultiplier float64 `yaml:"readahead_max_multiplier"` // Largest window as a multiple of readahead_bytes (default: 4)
+	TargetBufferSeconds    float64 `yaml:"target_buffer_seconds"`    // Seconds of playback to keep buffered (default: 30, 0=fixed window)
+
+	// WaitForIndex holds the first read of a stream until the seek index at
+	// the file end (MP4 moov, MKV Cues) is downloaded, so the first seek
+	// doesn't stall. Costs startup time; off by default.
+	WaitForIndex            bool `yaml:"wait_for_index"`
+	IndexWaitTimeoutSeconds int  `yaml:"index_wait_timeout_seconds"` // Longest wait before playing anyway (default: 15)
 }
 
 // OpenSubtitlesConfig configures the OpenSubtitles API client
@@ -75,11 +179,66 @@ type OpenSubtitlesConfig struct {
 	APIKey   string `yaml:"api_key"`  // Required: OpenSubtitles API key
 	Username string `yaml:"username"` // Optional: for higher download limits
 	Password string `yaml:"password"` // Optional: for authenticated downloads
+
+	// MaxRetries retries a rate-limited (429) request with exponential backoff,
+	// or after the Retry-After delay when the API sends one (default: 3, 0=disabled)
+	MaxRetries int `yaml:"max_retries"`
 }
 
 // SubtitlesConfig configures subtitle storage
 type SubtitlesConfig struct {
-	DownloadPath string `yaml:"download_path"` // Local storage path for downloaded subtitles
+	DownloadPath    string `yaml:"download_path"`    // Local storage path for downloaded subtitles
+	DedupeIdentical bool   `yaml:"dedupe_identical"` // Keep one record when identical subtitles come from different sources
+
+	// AutoDownloadLanguages are fetched from OpenSubtitles for episodes matched by a
+	// show torrent assignment, unless the torrent has a subtitle in that language
+	AutoDownloadLanguages []string `yaml:"auto_download_languages"`
+
+	// PreferredLanguages order subtitle sidecars in the VFS: the first language
+	// with a subtitle is marked as the default track. Shows can override this
+	// (and their auto-download languages) via PUT /api/shows/:id/settings.
+	PreferredLanguages []string `yaml:"preferred_languages"`
+}
+
+// IdentifyConfig configures torrent file identification: skipped files and plausible episode numbering
+type IdentifyConfig struct {
+	SkipPatterns      []string `yaml:"skip_patterns"`       // Replaces the built-in skip list when set
+	ExtraSkipPatterns []string `yaml:"extra_skip_patterns"` // Appended to the skip list
+	MinFileSizeMB     int64    `yaml:"min_file_size_mb"`    // Skip smaller videos, keep larger ones regardless of name (0=disabled)
+	MinSeason         int      `yaml:"min_season"`          // Lowest plausible season number (default: 0)
+	MaxSeason         int      `yaml:"max_season"`          // Highest plausible season number (default: 50, 0=unlimited)
+	MaxEpisode        int      `yaml:"max_episode"`         // Highest plausible episode number (default: 2000, 0=unlimited)
+	AssignSingleVideo bool     `yaml:"assign_single_video"` // Episode assignment takes a torrent's only video regardless of name (default: true)
+	CacheSize         int      `yaml:"cache_size"`          // Identification results kept for repeated torrents, e.g. dry run then assign (default: 64, 0=disabled)
+
+	AnimeAbsoluteEpisodes bool `yaml:"anime_absolute_episodes"` // Bare [Group] Show 05 numbers are absolute S1 episodes at medium confidence
+
+	Fallback IdentifyFallbackConfig `yaml:"fallback"` // LLM identification of files no pattern matches
+}
+
+// IdentifyFallbackConfig configures an OpenAI-compatible LLM endpoint that guesses
+// season/episode for files the patterns can't identify. Guesses are low confidence
+// and only matched to episodes that exist in the show.
+type IdentifyFallbackConfig struct {
+	Enabled        bool   `yaml:"enabled"`         // Ask the LLM about unidentified files (default: false)
+	URL            string `yaml:"url"`             // Chat completions endpoint, e.g. http://localhost:11434/v1/chat/completions
+	Model          string `yaml:"model"`           // Model name sent with each request
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // Per-request timeout (default: 30)
+}
+
+// JobsConfig bounds long-running API operations (torrent assignment, subtitle downloads, syncs)
+type JobsConfig struct {
+	MaxConcurrent     int `yaml:"max_concurrent"`      // Operations running at once (default: 4, 0=unlimited)
+	QueueSize         int `yaml:"queue_size"`          // Operations waiting for a slot before 429 (default: 16)
+	RetryAfterSeconds int `yaml:"retry_after_seconds"` // Retry-After sent with 429 (default: 10)
+}
+
+// WebhooksConfig configures signed JSON POSTs on assignment, removal and sync events
+type WebhooksConfig struct {
+	URLs           []string `yaml:"urls"`            // Endpoints notified of every event (empty = disabled)
+	Secret         string   `yaml:"secret"`          // HMAC-SHA256 key for X-Momoshtrem-Signature (optional)
+	MaxAttempts    int      `yaml:"max_attempts"`    // Delivery attempts per URL (default: 3)
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // Per-request timeout (default: 10)
 }
 
 // MetricsConfig configures Prometheus metrics exposure
@@ -95,6 +254,18 @@ type AirDateSyncConfig struct {
 	LookbackDays      int  `yaml:"lookback_days"`       // Days to look back for recently aired (default: 30)
 	BatchSize         int  `yaml:"batch_size"`          // Shows per batch to avoid rate limits (default: 5)
 	BatchDelayMs      int  `yaml:"batch_delay_ms"`      // Delay between batches in ms (default: 500)
+
+	// FullSyncIntervalDays forces a full sync this many days after the last
+	// one, catching changes the TMDB changes feed missed (default: 7, 0 disables)
+	FullSyncIntervalDays int `yaml:"full_sync_interval_days"`
+}
+
+// PrewarmConfig configures adding torrents queued for upcoming episodes on
+// their air date, so the first stream doesn't wait for metadata and peers
+type PrewarmConfig struct {
+	Enabled              bool `yaml:"enabled"`                // Enable POST /api/episodes/:id/queue-torrent (default: false)
+	PrefetchHeader       bool `yaml:"prefetch_header"`        // Also fetch the episode file's header pieces (default: true)
+	CheckIntervalMinutes int  `yaml:"check_interval_minutes"` // How often to look for due torrents (default: 15)
 }
 
 // DefaultConfig returns configuration with sensible defaults
@@ -106,6 +277,13 @@ func DefaultConfig() *Config {
 			WebDAVAuth: WebDAVAuthConfig{
 				Enabled: false, // Disabled by default for backward compatibility
 			},
+			APIAuth: APIAuthConfig{
+				Enabled:      false,
+				ExemptStatus: true,
+			},
+			TorrentEventsIntervalSeconds: 2,
+			ShutdownDrainTimeoutSeconds:  30,
+			WebDAVAccessLog:              true,
 		},
 		Database: DatabaseConfig{
 			Path: "./data/momoshtrem.db",
@@ -115,26 +293,47 @@ func DefaultConfig() *Config {
 			MetadataFolder:       "./data/torrents",
 			GlobalCacheSize:      4096, // 4GB
 			AddTimeout:           60,
+			AddRetryBackoff:      5,
 			ReadTimeout:          120,
 			IdleEnabled:          true,
 			IdleTimeout:          300,
 			StartPaused:          true,
 			DropDuplicatePeerIds: true,
 			MaxUnverifiedMB:      16,
+			EnableDHT:            true,
+			EnablePEX:            true,
+
+			IdleCheckIntervalSeconds: 30,
+
+			MaxEstablishedConns: 50,
+			MaxHalfOpen:         25,
+		},
+		TMDB: TMDBConfig{
+			CacheTTLMinutes: 60,
 		},
 		VFS: VFSConfig{
-			TreeTTL:  0,              // DEPRECATED: ignored
-			CacheDir: "./data/cache", // Persistent VFS tree cache
+			TreeTTL:        0,              // DEPRECATED: ignored
+			CacheDir:       "./data/cache", // Persistent VFS tree cache
+			RebuildDelayMs: 500,
 		},
 		Streaming: StreamingConfig{
 			HeaderPriorityBytes: 10 * 1024 * 1024, // 10MB
 			FooterPriorityBytes: 5 * 1024 * 1024,  // 5MB
 			ReadaheadBytes:      32 * 1024 * 1024,  // 32MB
 			UrgentBufferBytes:   8 * 1024 * 1024,   // 8MB
+
+			ReadaheadMinMultiplier: 1,
+			ReadaheadMaxMultiplier: 4,
+			TargetBufferSeconds:    30,
+
+			IndexWaitTimeoutSeconds: 15,
+		},
+		OpenSubtitles: OpenSubtitlesConfig{
+			MaxRetries: 3,
 		},
-		OpenSubtitles: OpenSubtitlesConfig{},
 		Subtitles: SubtitlesConfig{
-			DownloadPath: "./data/subtitles",
+			DownloadPath:    "./data/subtitles",
+			DedupeIdentical: true,
 		},
 		AirDateSync: AirDateSyncConfig{
 			Enabled:           true,
@@ -142,11 +341,35 @@ func DefaultConfig() *Config {
 			LookbackDays:      30,
 			BatchSize:         5,
 			BatchDelayMs:      500,
+
+			FullSyncIntervalDays: 7,
+		},
+		Prewarm: PrewarmConfig{
+			PrefetchHeader:       true,
+			CheckIntervalMinutes: 15,
 		},
 		Metrics: MetricsConfig{
 			Enabled: false,
 			Port:    9090,
 		},
+		Identify: IdentifyConfig{
+			MaxSeason:         50,
+			MaxEpisode:        2000,
+			AssignSingleVideo: true,
+			CacheSize:         64,
+			Fallback: IdentifyFallbackConfig{
+				TimeoutSeconds: 30,
+			},
+		},
+		Jobs: JobsConfig{
+			MaxConcurrent:     4,
+			QueueSize:         16,
+			RetryAfterSeconds: 10,
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:    3,
+			TimeoutSeconds: 10,
+		},
 	}
 }
 
@@ -182,6 +405,14 @@ func Load(path string) (*Config, error) {
 		cfg.Server.WebDAVAuth.Password = envPass
 	}
 
+	// REST API auth environment variable overrides
+	if envEnabled := os.Getenv("API_AUTH_ENABLED"); envEnabled != "" {
+		cfg.Server.APIAuth.Enabled = strings.ToLower(envEnabled) == "true"
+	}
+	if envKeys := os.Getenv("API_KEYS"); envKeys != "" {
+		cfg.Server.APIAuth.Keys = strings.Split(envKeys, ",")
+	}
+
 	// Metrics environment variable overrides
 	if envEnabled := os.Getenv("METRICS_ENABLED"); envEnabled != "" {
 		cfg.Metrics.Enabled = strings.ToLower(envEnabled) == "true"
@@ -192,6 +423,11 @@ func Load(path string) (*Config, error) {
 		}
 	}
 
+	// Webhook secret environment variable override
+	if envSecret := os.Getenv("WEBHOOK_SECRET"); envSecret != "" {
+		cfg.Webhooks.Secret = envSecret
+	}
+
 	// Database URL environment variable override
 	if envURL := os.Getenv("DATABASE_URL"); envURL != "" {
 		cfg.Database.URL = envURL
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// maxPriorityBytes bounds header+footer prioritization; larger values would
+// have the torrent client fetch most of a file before playback starts
+const maxPriorityBytes = 1024 * 1024 * 1024
+
+// Validate checks for settings that would otherwise fail confusingly at runtime.
+// Every problem is returned in one error so they can be fixed in a single pass.
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// Server
+	check(validPort(c.Server.HTTPPort), "server.http_port must be between 1 and 65535 (got %d)", c.Server.HTTPPort)
+	check(validPort(c.Server.WebDAVPort), "server.webdav_port must be between 1 and 65535 (got %d)", c.Server.WebDAVPort)
+	check(c.Server.HTTPPort != c.Server.WebDAVPort, "server.http_port and server.webdav_port must differ (both %d)", c.Server.HTTPPort)
+	check(c.Server.TorrentEventsIntervalSeconds >= 0, "server.torrent_events_interval_seconds must not be negative")
+	check(c.Server.ShutdownDrainTimeoutSeconds >= 0, "server.shutdown_drain_timeout_seconds must not be negative")
+	if c.Server.WebDAVAuth.Enabled {
+		check(c.Server.WebDAVAuth.Username != "", "server.webdav_auth.username is required when WebDAV auth is enabled")
+		check(c.Server.WebDAVAuth.Password != "", "server.webdav_auth.password is required when WebDAV auth is enabled")
+	}
+	if c.Server.WebDAVPublicURL != "" {
+		check(validHTTPURL(c.Server.WebDAVPublicURL), "server.webdav_public_url must be an http(s) URL (got %q)", c.Server.WebDAVPublicURL)
+	}
+	if c.Server.APIAuth.Enabled {
+		check(hasNonEmpty(c.Server.APIAuth.Keys), "server.api_auth.keys needs at least one key when API auth is enabled")
+	}
+
+	// Database
+	check(c.Database.URL != "", "database.url must not be empty")
+
+	// Torrent
+	check(c.Torrent.MetadataFolder != "", "torrent.metadata_folder must not be empty")
+	check(c.Torrent.GlobalCacheSize > 0, "torrent.global_cache_size must be positive (MB, got %d)", c.Torrent.GlobalCacheSize)
+	check(c.Torrent.AddTimeout > 0, "torrent.add_timeout must be positive (seconds, got %d)", c.Torrent.AddTimeout)
+	check(c.Torrent.AddRetries >= 0, "torrent.add_retries must not be negative")
+	check(c.Torrent.AddRetryBackoff >= 0, "torrent.add_retry_backoff must not be negative")
+	check(c.Torrent.ReadTimeout > 0, "torrent.read_timeout must be positive (seconds, got %d)", c.Torrent.ReadTimeout)
+	if c.Torrent.IdleEnabled {
+		check(c.Torrent.IdleTimeout > 0, "torrent.idle_timeout must be positive when idle_enabled is set (seconds, got %d)", c.Torrent.IdleTimeout)
+		check(c.Torrent.IdleCheckIntervalSeconds > 0 && c.Torrent.IdleCheckIntervalSeconds <= c.Torrent.IdleTimeout,
+			"torrent.idle_check_interval_seconds must be positive and at most idle_timeout (got %d, idle_timeout %d)",
+			c.Torrent.IdleCheckIntervalSeconds, c.Torrent.IdleTimeout)
+	}
+	check(c.Torrent.MaxUnverifiedMB >= 0, "torrent.max_unverified_mb must not be negative")
+	check(c.Torrent.MinSeeders >= 0, "torrent.min_seeders must not be negative")
+	check(c.Torrent.MaxEstablishedConns > 0, "torrent.max_established_conns must be positive (got %d)", c.Torrent.MaxEstablishedConns)
+	check(c.Torrent.MaxHalfOpen > 0, "torrent.max_half_open must be positive (got %d)", c.Torrent.MaxHalfOpen)
+	for _, tracker := range c.Torrent.DefaultTrackers {
+		check(common.ValidTrackerURL(tracker), "torrent.default_trackers has an invalid tracker URL %q", tracker)
+	}
+
+	// TMDB and VFS
+	check(c.TMDB.CacheTTLMinutes >= 0, "tmdb.cache_ttl_minutes must not be negative")
+	check(c.VFS.RebuildDelayMs >= 0, "vfs.rebuild_delay_ms must not be negative")
+	if ext := c.VFS.ForceExtension; ext != "" {
+		check(validExtension(ext), "vfs.force_extension %q must be a file extension such as .mkv", ext)
+	}
+	for _, root := range c.VFS.LocalMediaRoots {
+		check(filepath.IsAbs(root), "vfs.local_media_roots entry %q must be an absolute path", root)
+	}
+
+	// Streaming (all zero means built-in defaults)
+	s := c.Streaming
+	check(s.HeaderPriorityBytes >= 0, "streaming.header_priority_bytes must not be negative")
+	check(s.FooterPriorityBytes >= 0, "streaming.footer_priority_bytes must not be negative")
+	check(s.ReadaheadBytes >= 0, "streaming.readahead_bytes must not be negative")
+	check(s.UrgentBufferBytes >= 0, "streaming.urgent_buffer_bytes must not be negative")
+	check(s.HeaderPriorityBytes+s.FooterPriorityBytes <= maxPriorityBytes,
+		"streaming.header_priority_bytes + footer_priority_bytes must not exceed %d (got %d)",
+		maxPriorityBytes, s.HeaderPriorityBytes+s.FooterPriorityBytes)
+	if s.ReadaheadMinMultiplier > 0 && s.ReadaheadMaxMultiplier > 0 {
+		check(s.ReadaheadMinMultiplier <= s.ReadaheadMaxMultiplier,
+			"streaming.readahead_min_multiplier (%g) must not exceed readahead_max_multiplier (%g)",
+			s.ReadaheadMinMultiplier, s.ReadaheadMaxMultiplier)
+	}
+	if s.WaitForIndex {
+		check(s.IndexWaitTimeoutSeconds > 0 && s.IndexWaitTimeoutSeconds < c.Torrent.ReadTimeout,
+			"streaming.index_wait_timeout_seconds must be positive and below torrent.read_timeout (got %d, read_timeout %d)",
+			s.IndexWaitTimeoutSeconds, c.Torrent.ReadTimeout)
+	}
+
+	// Subtitles
+	check(c.Subtitles.DownloadPath != "", "subtitles.download_path must not be empty")
+	check(c.OpenSubtitles.MaxRetries >= 0, "opensubtitles.max_retries must not be negative")
+
+	// Identification
+	check(c.Identify.CacheSize >= 0, "identify.cache_size must not be negative")
+	if c.Identify.Fallback.Enabled {
+		check(validHTTPURL(c.Identify.Fallback.URL), "identify.fallback.url must be an http(s) URL when the fallback is enabled (got %q)", c.Identify.Fallback.URL)
+		check(c.Identify.Fallback.Model != "", "identify.fallback.model is required when the fallback is enabled")
+		check(c.Identify.Fallback.TimeoutSeconds > 0, "identify.fallback.timeout_seconds must be positive when the fallback is enabled")
+	}
+
+	// Background work
+	if c.AirDateSync.Enabled {
+		check(c.AirDateSync.SyncIntervalHours > 0, "airdate_sync.sync_interval_hours must be positive when enabled")
+		check(c.AirDateSync.BatchSize > 0, "airdate_sync.batch_size must be positive when enabled")
+		check(c.AirDateSync.FullSyncIntervalDays >= 0, "airdate_sync.full_sync_interval_days must not be negative")
+	}
+	if c.Prewarm.Enabled {
+		check(c.Prewarm.CheckIntervalMinutes > 0, "prewarm.check_interval_minutes must be positive when enabled")
+	}
+	if c.Metrics.Enabled {
+		check(validPort(c.Metrics.Port), "metrics.port must be between 1 and 65535 (got %d)", c.Metrics.Port)
+		check(c.Metrics.Port != c.Server.HTTPPort && c.Metrics.Port != c.Server.WebDAVPort,
+			"metrics.port must differ from the HTTP and WebDAV ports (got %d)", c.Metrics.Port)
+	}
+	check(c.Jobs.MaxConcurrent >= 0, "jobs.max_concurrent must not be negative")
+	check(c.Jobs.QueueSize >= 0, "jobs.queue_size must not be negative")
+	if len(c.Webhooks.URLs) > 0 {
+		check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+		check(c.Webhooks.TimeoutSeconds > 0, "webhooks.timeout_seconds must be positive")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration (%d problems): %s", len(problems), strings.Join(problems, "; "))
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func validHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func hasNonEmpty(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validExtension accepts a file extension with or without its leading dot
+func validExtension(ext string) bool {
+	name := strings.TrimPrefix(ext, ".")
+	return name != "" && !strings.ContainsAny(name, `./\ `)
+}
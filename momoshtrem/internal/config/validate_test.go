@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("DefaultConfig().Validate() = %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Server.HTTPPort = 70000
+	cfg.Torrent.ReadTimeout = -1
+	cfg.Torrent.IdleCheckIntervalSeconds = cfg.Torrent.IdleTimeout + 1
+	cfg.Torrent.GlobalCacheSize = 0
+	cfg.Torrent.MetadataFolder = ""
+	cfg.Server.WebDAVAuth.Enabled = true
+	cfg.Server.APIAuth = APIAuthConfig{Enabled: true, Keys: []string{" "}}
+	cfg.Server.WebDAVPublicURL = "nas.local/media"
+	cfg.Streaming.HeaderPriorityBytes = 2 * maxPriorityBytes
+	cfg.Torrent.DefaultTrackers = []string{"udp://tracker.example.org:1337/announce", "tracker.example.org"}
+	cfg.Identify.Fallback.Enabled = true
+	cfg.Identify.Fallback.URL = "localhost:11434"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want error")
+	}
+	for _, want := range []string{
+		"server.http_port",
+		"torrent.read_timeout",
+		"torrent.idle_check_interval_seconds",
+		"torrent.global_cache_size",
+		"torrent.metadata_folder",
+		"server.webdav_auth.username",
+		"server.webdav_auth.password",
+		"server.api_auth.keys",
+		"server.webdav_public_url",
+		"footer_priority_bytes",
+		`invalid tracker URL "tracker.example.org"`,
+		"identify.fallback.url",
+		"identify.fallback.model",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %s: %v", want, err)
+		}
+	}
+}
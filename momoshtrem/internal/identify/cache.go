@@ -0,0 +1,108 @@
+package identify
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// resultCache is an LRU of identification results keyed by torrent name and
+// file list, so a pack previewed with dry_run isn't parsed again on assignment.
+// Results are cloned on the way in and out; callers never share slices.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is most recently used
+	entries map[[sha256.Size]byte]*list.Element
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type resultCacheEntry struct {
+	key    [sha256.Size]byte
+	result *IdentificationResult
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[[sha256.Size]byte]*list.Element),
+	}
+}
+
+// resultCacheKey hashes everything Identify reads from its input
+func resultCacheKey(files []TorrentFile, torrentName string) [sha256.Size]byte {
+	h := sha256.New()
+	var buf [8]byte
+	writeString := func(s string) {
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(s)))
+		h.Write(buf[:])
+		h.Write([]byte(s))
+	}
+
+	writeString(torrentName)
+	for _, f := range files {
+		writeString(f.Path)
+		binary.LittleEndian.PutUint64(buf[:], uint64(f.Size))
+		h.Write(buf[:])
+	}
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+	return key
+}
+
+func (c *resultCache) get(key [sha256.Size]byte) (*IdentificationResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.hits.Add(1)
+	c.order.MoveToFront(elem)
+	return cloneResult(elem.Value.(*resultCacheEntry).result), true
+}
+
+func (c *resultCache) put(key [sha256.Size]byte, result *IdentificationResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*resultCacheEntry).result = cloneResult(result)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&resultCacheEntry{key: key, result: cloneResult(result)})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).key)
+	}
+}
+
+// clear drops every entry, e.g. when the fallback handler changes
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+func cloneResult(r *IdentificationResult) *IdentificationResult {
+	clone := *r
+	clone.IdentifiedFiles = slices.Clone(r.IdentifiedFiles)
+	for i := range clone.IdentifiedFiles {
+		clone.IdentifiedFiles[i].Episodes = slices.Clone(r.IdentifiedFiles[i].Episodes)
+	}
+	clone.UnidentifiedFiles = slices.Clone(r.UnidentifiedFiles)
+	return &clone
+}
@@ -0,0 +1,45 @@
+package identify
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIdentifyCachesResults(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheSize = 2
+	identifier := NewIdentifier(nil, cfg)
+
+	pack := func(name string) []TorrentFile {
+		files := make([]TorrentFile, 3)
+		for i := range files {
+			files[i] = TorrentFile{Path: fmt.Sprintf("%s/%s.S01E%02d.mkv", name, name, i+1), Size: 500 << 20}
+		}
+		return files
+	}
+
+	first := identifier.Identify(pack("Show"), "Show.S01")
+	first.IdentifiedFiles[0].Episodes[0] = 99 // Callers must not corrupt the cache
+
+	second := identifier.Identify(pack("Show"), "Show.S01")
+	if hits, misses := identifier.CacheStats(); hits != 1 || misses != 1 {
+		t.Fatalf("hits/misses = %d/%d, want 1/1", hits, misses)
+	}
+	if second.IdentifiedCount != 3 || second.IdentifiedFiles[0].Episodes[0] != 1 {
+		t.Errorf("cached result = %d files, first episode %v; want 3 files starting at E01",
+			second.IdentifiedCount, second.IdentifiedFiles[0].Episodes)
+	}
+
+	// A different torrent name is a different key
+	identifier.Identify(pack("Show"), "Show.S01.REPACK")
+	if _, misses := identifier.CacheStats(); misses != 2 {
+		t.Errorf("misses = %d after new torrent name, want 2", misses)
+	}
+
+	// Least recently used entry is evicted
+	identifier.Identify(pack("Other"), "Other.S01")
+	identifier.Identify(pack("Show"), "Show.S01")
+	if hits, misses := identifier.CacheStats(); hits != 1 || misses != 4 {
+		t.Errorf("hits/misses = %d/%d after eviction, want 1/4", hits, misses)
+	}
+}
@@ -0,0 +1,89 @@
+package identify
+
+import "strings"
+
+// DefaultSkipPatterns are path substrings identifying samples, trailers and extras
+var DefaultSkipPatterns = []string{
+	"sample",
+	"trailer",
+	"preview",
+	"extras/",
+	"extras\\",
+	"featurette",
+	"deleted.scene",
+	"deleted_scene",
+	"deleted-scene",
+	"behind.the.scene",
+	"behind_the_scene",
+	"behind-the-scene",
+	"bonus/",
+	"bonus\\",
+	"/extra/",
+	"\\extra\\",
+}
+
+// Config controls which torrent files the identifier skips
+type Config struct {
+	// SkipPatterns are case-insensitive path substrings; matching files are skipped.
+	// Nil uses DefaultSkipPatterns, an empty slice disables name-based skipping.
+	SkipPatterns []string
+
+	// MinFileSize in bytes (0 disables). Video files smaller than this are
+	// skipped regardless of name, and video files at or above it are kept
+	// even if their path matches a skip pattern.
+	MinFileSize int64
+
+	// Plausible season/episode bounds. Parses outside them are flagged as
+	// implausible and left unmatched. A zero maximum disables that bound.
+	MinSeason  int
+	MaxSeason  int
+	MaxEpisode int
+
+	// AnimeAbsoluteEpisodes treats group-tagged anime releases without season
+	// context as absolute numbering within season 1, at medium instead of low confidence.
+	AnimeAbsoluteEpisodes bool
+
+	// CacheSize is how many identification results are kept, keyed by torrent
+	// name and file list (0 disables caching)
+	CacheSize int
+}
+
+// DefaultConfig returns the identifier config matching the built-in skip list
+func DefaultConfig() Config {
+	return Config{
+		SkipPatterns: append([]string(nil), DefaultSkipPatterns...),
+		MaxSeason:    50,
+		MaxEpisode:   2000,
+		CacheSize:    64,
+	}
+}
+
+// plausible reports whether a parsed season and episodes fall within the configured bounds
+func (c Config) plausible(season int, episodes []int) bool {
+	if season < c.MinSeason || (c.MaxSeason > 0 && season > c.MaxSeason) {
+		return false
+	}
+	for _, ep := range episodes {
+		if c.MaxEpisode > 0 && ep > c.MaxEpisode {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize fills defaults and lowercases patterns for matching
+func (c Config) normalize() Config {
+	patterns := c.SkipPatterns
+	if patterns == nil {
+		patterns = DefaultSkipPatterns
+	}
+
+	lowered := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if p = strings.ToLower(strings.TrimSpace(p)); p != "" {
+			lowered = append(lowered, p)
+		}
+	}
+	c.SkipPatterns = lowered
+	return c
+}
@@ -0,0 +1,182 @@
+package identify
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FileSelectionPolicy decides which video file represents a movie when a
+// torrent contains several candidates (e.g. a 1080p and a 720p encode)
+type FileSelectionPolicy string
+
+const (
+	SelectLargest           FileSelectionPolicy = "largest"            // Biggest file wins (default)
+	SelectHighestResolution FileSelectionPolicy = "highest-resolution" // Best resolution, then size
+	SelectPreferCodec       FileSelectionPolicy = "prefer-codec"       // Preferred codec, then resolution, then size
+)
+
+// DefaultPreferredCodec is used by SelectPreferCodec when no codec is given
+const DefaultPreferredCodec = "HEVC"
+
+// ParseFileSelectionPolicy validates a policy name; empty means SelectLargest
+func ParseFileSelectionPolicy(name string) (FileSelectionPolicy, error) {
+	switch policy := FileSelectionPolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return SelectLargest, nil
+	case SelectLargest, SelectHighestResolution, SelectPreferCodec:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown file selection policy %q (want %s, %s or %s)",
+			name, SelectLargest, SelectHighestResolution, SelectPreferCodec)
+	}
+}
+
+// FileSelection configures SelectMovieFile
+type FileSelection struct {
+	Policy      FileSelectionPolicy
+	PreferCodec string // Codec favored by SelectPreferCodec (x265 and HEVC are equivalent)
+}
+
+// MovieCandidate is an eligible video file with its parsed quality
+type MovieCandidate struct {
+	FilePath string      `json:"file_path"`
+	FileSize int64       `json:"file_size"`
+	Quality  QualityInfo `json:"quality"`
+}
+
+// resolutionRank orders normalized resolutions; unknown resolutions rank lowest
+var resolutionRank = map[string]int{
+	"480p":  1,
+	"720p":  2,
+	"1080p": 3,
+	"2160p": 4,
+}
+
+// SelectMovieFile ranks the video files that aren't samples/trailers according
+// to the selection policy and picks the best one. Ties fall back to file size.
+func (i *Identifier) SelectMovieFile(files []TorrentFile, sel FileSelection) *MovieMatchResult {
+	result := &MovieMatchResult{
+		Found:      false,
+		OtherFiles: make([]string, 0),
+	}
+
+	for _, file := range files {
+		// Skip non-video files and samples, trailers, extras
+		if !isVideoFile(file.Path) || i.shouldSkip(file) {
+			continue
+		}
+		result.Candidates = append(result.Candidates, MovieCandidate{
+			FilePath: file.Path,
+			FileSize: file.Size,
+			Quality:  extractQualityFromPath(file.Path, i.patterns),
+		})
+	}
+	if len(result.Candidates) == 0 {
+		return result
+	}
+
+	codec := sel.PreferCodec
+	if codec == "" {
+		codec = DefaultPreferredCodec
+	}
+	codec = normalizeCodec(codec)
+
+	// Stable so equally ranked files keep torrent order
+	sort.SliceStable(result.Candidates, func(a, b int) bool {
+		ca, cb := result.Candidates[a], result.Candidates[b]
+		switch sel.Policy {
+		case SelectPreferCodec:
+			ma, mb := strings.EqualFold(ca.Quality.Codec, codec), strings.EqualFold(cb.Quality.Codec, codec)
+			if ma != mb {
+				return ma
+			}
+			fallthrough
+		case SelectHighestResolution:
+			ra, rb := resolutionRank[ca.Quality.Resolution], resolutionRank[cb.Quality.Resolution]
+			if ra != rb {
+				return ra > rb
+			}
+		}
+		return ca.FileSize > cb.FileSize
+	})
+
+	best := result.Candidates[0]
+	result.Found = true
+	result.FilePath = best.FilePath
+	result.FileSize = best.FileSize
+	result.Quality = best.Quality
+
+	// A movie split into CD1/CD2 is served as one file made of all its parts
+	if parts := i.movieParts(best, result.Candidates); parts != nil {
+		result.FileSize = 0
+		for _, part := range parts {
+			result.Parts = append(result.Parts, part.FilePath)
+			result.FileSize += part.FileSize
+		}
+		result.FilePath = result.Parts[0]
+	}
+
+	for _, other := range result.Candidates {
+		if other.FilePath != result.FilePath && !slices.Contains(result.Parts, other.FilePath) {
+			result.OtherFiles = append(result.OtherFiles, other.FilePath)
+		}
+	}
+
+	return result
+}
+
+// movieParts returns the parts of the multi-part movie that selected belongs
+// to, in order, or nil if it is a single file. Parts live in the same folder,
+// have the same name apart from a CD1/Part1 marker, and are numbered
+// consecutively from 1; an incomplete set is treated as separate files.
+func (i *Identifier) movieParts(selected MovieCandidate, candidates []MovieCandidate) []MovieCandidate {
+	key, _, ok := i.moviePartKey(selected.FilePath)
+	if !ok {
+		return nil
+	}
+
+	byNumber := make(map[int]MovieCandidate)
+	for _, c := range candidates {
+		k, n, ok := i.moviePartKey(c.FilePath)
+		if !ok || k != key {
+			continue
+		}
+		if _, dup := byNumber[n]; dup {
+			return nil
+		}
+		byNumber[n] = c
+	}
+	if len(byNumber) < 2 {
+		return nil
+	}
+
+	parts := make([]MovieCandidate, 0, len(byNumber))
+	for n := 1; n <= len(byNumber); n++ {
+		part, ok := byNumber[n]
+		if !ok {
+			return nil
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// moviePartKey splits a path into the part number and a key shared by all
+// parts of the same movie: the folder and the name with the marker removed
+func (i *Identifier) moviePartKey(path string) (key string, number int, ok bool) {
+	ext := filepath.Ext(path)
+	name := strings.TrimSuffix(filepath.Base(path), ext)
+
+	// The last marker counts, so a title like "Part 2" before "CD1" is kept in the key
+	all := i.patterns.MoviePart.FindAllStringSubmatchIndex(name, -1)
+	if len(all) == 0 {
+		return "", 0, false
+	}
+	m := all[len(all)-1]
+
+	key = filepath.Dir(path) + "/" + strings.ToLower(name[:m[0]]+"\x00"+name[m[1]:]+ext)
+	return key, parseInt(name[m[2]:m[3]]), true
+}
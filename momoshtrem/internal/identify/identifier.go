@@ -1,7 +1,9 @@
 package identify
 
 import (
+	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -19,7 +21,7 @@ var subtitleExtensions = map[string]bool{
 }
 
 // FallbackHandler is an interface for handling unidentified files
-// This allows future integration with local LLMs for complex identification
+// This allows integration with local LLMs for complex identification (see LLMFallback)
 type FallbackHandler interface {
 	IdentifyBatch(files []UnidentifiedFile, context *Context) (map[string]*IdentifiedFile, error)
 }
@@ -51,22 +53,69 @@ func (n *NoOpFallback) IdentifyBatch(files []UnidentifiedFile, context *Context)
 type Identifier struct {
 	patterns *CompiledPatterns
 	fallback FallbackHandler
+	config   Config
+	cache    *resultCache // nil when Config.CacheSize is 0
 }
 
-// NewIdentifier creates a new Identifier with the given fallback handler
+// NewIdentifier creates a new Identifier with the given fallback handler and config
 // If fallback is nil, NoOpFallback is used
-func NewIdentifier(fallback FallbackHandler) *Identifier {
+func NewIdentifier(fallback FallbackHandler, cfg Config) *Identifier {
 	if fallback == nil {
 		fallback = &NoOpFallback{}
 	}
-	return &Identifier{
+	i := &Identifier{
 		patterns: NewCompiledPatterns(),
 		fallback: fallback,
+		config:   cfg.normalize(),
 	}
+	if cfg.CacheSize > 0 {
+		i.cache = newResultCache(cfg.CacheSize)
+	}
+	return i
+}
+
+// SetFallback replaces the fallback handler. Not safe to call concurrently with Identify.
+func (i *Identifier) SetFallback(fallback FallbackHandler) {
+	if fallback == nil {
+		fallback = &NoOpFallback{}
+	}
+	i.fallback = fallback
+	if i.cache != nil {
+		i.cache.clear()
+	}
+}
+
+// CacheStats returns the number of result cache hits and misses (zero if caching is disabled)
+func (i *Identifier) CacheStats() (hits, misses uint64) {
+	if i.cache == nil {
+		return 0, 0
+	}
+	return i.cache.hits.Load(), i.cache.misses.Load()
 }
 
-// Identify processes torrent files and returns identification results
+// Identify processes torrent files and returns identification results.
+// Results for the same torrent name and file list are served from the cache.
 func (i *Identifier) Identify(files []TorrentFile, torrentName string) *IdentificationResult {
+	if i.cache == nil {
+		result, _ := i.identify(files, torrentName)
+		return result
+	}
+
+	key := resultCacheKey(files, torrentName)
+	if result, ok := i.cache.get(key); ok {
+		return result
+	}
+	result, cacheable := i.identify(files, torrentName)
+	if cacheable {
+		i.cache.put(key, result)
+	}
+	return result
+}
+
+// identify runs the patterns and fallback over files. The result is not
+// cacheable if the fallback failed, so a later call can retry it.
+func (i *Identifier) identify(files []TorrentFile, torrentName string) (*IdentificationResult, bool) {
+	cacheable := true
 	result := &IdentificationResult{
 		TorrentName:       torrentName,
 		IdentifiedFiles:   make([]IdentifiedFile, 0),
@@ -86,7 +135,7 @@ func (i *Identifier) Identify(files []TorrentFile, torrentName string) *Identifi
 		}
 
 		// Skip samples, trailers, extras
-		if shouldSkip(file.Path) {
+		if i.shouldSkip(file) {
 			continue
 		}
 
@@ -102,6 +151,8 @@ func (i *Identifier) Identify(files []TorrentFile, torrentName string) *Identifi
 		}
 	}
 
+	numberKeywordSpecials(result.IdentifiedFiles)
+
 	// If we have unidentified files and a fallback handler, try to identify them
 	if len(result.UnidentifiedFiles) > 0 && i.fallback != nil {
 		unidentified := make([]UnidentifiedFile, len(result.UnidentifiedFiles))
@@ -122,11 +173,22 @@ func (i *Identifier) Identify(files []TorrentFile, torrentName string) *Identifi
 		}
 
 		fallbackResults, err := i.fallback.IdentifyBatch(unidentified, ctx)
+		if err != nil {
+			slog.Warn("Fallback identification failed", "torrent", torrentName, "error", err)
+			cacheable = false
+		}
 		if err == nil && fallbackResults != nil {
 			// Update results with fallback identifications
 			newUnidentified := make([]string, 0)
-			for _, path := range result.UnidentifiedFiles {
-				if identified, ok := fallbackResults[path]; ok {
+			for idx, path := range result.UnidentifiedFiles {
+				if identified, ok := fallbackResults[path]; ok && identified != nil {
+					// Fallback guesses are never trusted like a pattern match
+					identified.FilePath = path
+					identified.FileSize = unidentified[idx].Size
+					identified.Confidence = ConfidenceLow
+					identified.NeedsReview = true
+					identified.FromFallback = true
+					identified.Implausible = !i.config.plausible(identified.Season, identified.Episodes)
 					result.IdentifiedFiles = append(result.IdentifiedFiles, *identified)
 					result.IdentifiedCount++
 				} else {
@@ -137,7 +199,7 @@ func (i *Identifier) Identify(files []TorrentFile, torrentName string) *Identifi
 		}
 	}
 
-	return result
+	return result, cacheable
 }
 
 // extractContext extracts hints from the torrent name
@@ -174,14 +236,21 @@ func (i *Identifier) extractSeasonFromPath(filePath string) (int, bool) {
 	dir := filepath.Dir(filePath)
 	parts := strings.Split(dir, string(filepath.Separator))
 
-	// Check each directory component for season patterns
-	for _, part := range parts {
+	// Check the innermost component first: in nested packs
+	// (Show.S01-S03/1080p/S02/...) the pack root can also look like a season
+	for _, part := range slices.Backward(parts) {
 		if match := i.patterns.SeasonFolder.FindStringSubmatch(part); match != nil {
 			season := parseInt(match[1])
 			if season > 0 {
 				return season, true
 			}
 		}
+		// Spelled-out seasons only when the folder has no numeric one
+		if match := i.patterns.SeasonWordFolder.FindStringSubmatch(part); match != nil {
+			if season := parseNumberWord(match[1]); season > 0 {
+				return season, true
+			}
+		}
 	}
 
 	return 0, false
@@ -214,8 +283,15 @@ func (i *Identifier) identifyFile(file TorrentFile, ctx *Context) (*IdentifiedFi
 	// Extract quality info
 	quality := i.extractQuality(filename, ctx)
 
+	// Flag parses outside plausible bounds (e.g. S99E99 from a random number)
+	implausible := !i.config.plausible(season, episodes)
+
+	// A filename season that disagrees with its folder (S01E01 inside Season 02)
+	// is usually a mislabeled release; the filename wins but needs a look
+	seasonConflict := hasFolderSeason && !isSpecial && season != folderSeason
+
 	// Determine if review is needed
-	needsReview := confidence == ConfidenceLow
+	needsReview := confidence == ConfidenceLow || implausible || seasonConflict
 
 	return &IdentifiedFile{
 		FilePath:         file.Path,
@@ -229,9 +305,51 @@ func (i *Identifier) identifyFile(file TorrentFile, ctx *Context) (*IdentifiedFi
 		PatternUsed:      pattern,
 		NeedsReview:      needsReview,
 		SeasonFromFolder: hasFolderSeason && season == folderSeason,
+		ReleaseVersion:   i.patterns.ReleaseVersionRank(filename),
+		Implausible:      implausible,
+		SeasonConflict:   seasonConflict,
 	}, true
 }
 
+// patternSpecialKeyword is the pattern name of specials found only by a
+// Special/OVA/OAD keyword, without an episode number
+const patternSpecialKeyword = "Special keyword"
+
+// numberKeywordSpecials numbers keyword specials (Show.OVA.mkv) S00E01, S00E02, ...
+// in file name order, so several in one torrent don't all become S00E01.
+// Numbers already used by S00Exx files of the same type are skipped.
+func numberKeywordSpecials(files []IdentifiedFile) {
+	for _, fileType := range []FileType{FileTypeVideo, FileTypeSubtitle} {
+		taken := make(map[int]bool)
+		var keyword []*IdentifiedFile
+		for idx := range files {
+			f := &files[idx]
+			if !f.IsSpecial || f.FileType != fileType {
+				continue
+			}
+			if f.PatternUsed == patternSpecialKeyword {
+				keyword = append(keyword, f)
+				continue
+			}
+			for _, ep := range f.Episodes {
+				taken[ep] = true
+			}
+		}
+
+		slices.SortFunc(keyword, func(a, b *IdentifiedFile) int {
+			return strings.Compare(a.FilePath, b.FilePath)
+		})
+		next := 1
+		for _, f := range keyword {
+			for taken[next] {
+				next++
+			}
+			f.Episodes = []int{next}
+			next++
+		}
+	}
+}
+
 // tryPatterns tries all patterns in order of confidence and returns the first match
 func (i *Identifier) tryPatterns(filename string, folderSeason int, hasFolderSeason bool) (season int, episodes []int, confidence Confidence, pattern string, isSpecial bool, ok bool) {
 	// Check for special episodes first
@@ -241,9 +359,9 @@ func (i *Identifier) tryPatterns(filename string, folderSeason int, hasFolderSea
 			ep := parseInt(match[1])
 			return 0, []int{ep}, ConfidenceHigh, "S00Exx", true, true
 		}
-		// Special/OVA/OAD keyword
+		// Special/OVA/OAD keyword; numbered by numberKeywordSpecials
 		if match[2] != "" {
-			return 0, []int{1}, ConfidenceMedium, "Special keyword", true, true
+			return 0, []int{1}, ConfidenceMedium, patternSpecialKeyword, true, true
 		}
 	}
 
@@ -289,6 +407,15 @@ func (i *Identifier) tryPatterns(filename string, folderSeason int, hasFolderSea
 		return s, []int{ep}, ConfidenceMedium, "Season X Episode Y", false, true
 	}
 
+	// Try Season Two Episode 3 / Part III Episode 5
+	if match := i.patterns.SeasonWordEp.FindStringSubmatch(filename); match != nil {
+		s := parseNumberWord(match[1])
+		ep := parseNumberWord(match[2])
+		if s > 0 && ep > 0 {
+			return s, []int{ep}, ConfidenceMedium, "Season word Episode Y", false, true
+		}
+	}
+
 	// Try Episode/Ep number (needs folder season context)
 	if match := i.patterns.EpNumber.FindStringSubmatch(filename); match != nil {
 		ep := parseInt(match[1])
@@ -304,11 +431,29 @@ func (i *Identifier) tryPatterns(filename string, folderSeason int, hasFolderSea
 			if hasFolderSeason {
 				return folderSeason, []int{ep}, ConfidenceMedium, "Anime + folder", false, true
 			}
+			if i.config.AnimeAbsoluteEpisodes {
+				return 1, []int{ep}, ConfidenceMedium, "Anime (absolute)", false, true
+			}
 			// Without folder context, assume season 1
 			return 1, []int{ep}, ConfidenceLow, "Anime (assumed S1)", false, true
 		}
 	}
 
+	// Try Anime format with the number after the title ([Group] Show Name 05 (1080p))
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if match := i.patterns.AnimeTitleEp.FindStringSubmatch(name); match != nil {
+		ep := parseInt(match[1])
+		if ep > 0 {
+			if hasFolderSeason {
+				return folderSeason, []int{ep}, ConfidenceMedium, "Anime title + folder", false, true
+			}
+			if i.config.AnimeAbsoluteEpisodes {
+				return 1, []int{ep}, ConfidenceMedium, "Anime title (absolute)", false, true
+			}
+			return 1, []int{ep}, ConfidenceLow, "Anime title (assumed S1)", false, true
+		}
+	}
+
 	// LOW CONFIDENCE PATTERNS (only use with folder context)
 	if hasFolderSeason {
 		// Try 4-digit concatenated format (0101 = S01E01)
@@ -334,32 +479,13 @@ func (i *Identifier) tryPatterns(filename string, folderSeason int, hasFolderSea
 	return 0, nil, ConfidenceNone, "", false, false
 }
 
-// extractQuality extracts quality information from filename
+// extractQuality extracts quality information from filename, falling back to
+// the folder's resolution hint
 func (i *Identifier) extractQuality(filename string, ctx *Context) QualityInfo {
-	quality := QualityInfo{}
-
-	// Resolution
-	if match := i.patterns.Resolution.FindString(filename); match != "" {
-		quality.Resolution = normalizeResolution(match)
-	} else if ctx.QualityHint != "" {
+	quality := extractQualityFromPath(filename, i.patterns)
+	if quality.Resolution == "" {
 		quality.Resolution = ctx.QualityHint
 	}
-
-	// Source
-	if match := i.patterns.Source.FindString(filename); match != "" {
-		quality.Source = normalizeSource(match)
-	}
-
-	// Codec
-	if match := i.patterns.Codec.FindString(filename); match != "" {
-		quality.Codec = normalizeCodec(match)
-	}
-
-	// HDR
-	if i.patterns.HDR.MatchString(filename) {
-		quality.HDR = true
-	}
-
 	return quality
 }
 
@@ -399,6 +525,25 @@ func normalizeSource(match string) string {
 	}
 }
 
+// normalizeAudioCodec converts an audio codec to standard format
+func normalizeAudioCodec(match string) string {
+	upper := strings.NewReplacer(" ", "", ".", "", "_", "", "-", "").Replace(strings.ToUpper(match))
+	switch upper {
+	case "TRUEHD":
+		return "TrueHD"
+	case "DTSHD":
+		return "DTS-HD"
+	case "EAC3", "DDP", "DD+":
+		return "E-AC3"
+	case "AC3", "DD":
+		return "AC3"
+	case "OPUS":
+		return "Opus"
+	default:
+		return upper // DTS, AAC, FLAC, MP3
+	}
+}
+
 // normalizeCodec converts codec to standard format
 func normalizeCodec(match string) string {
 	upper := strings.ToUpper(match)
@@ -431,35 +576,15 @@ func isSubtitleFile(path string) bool {
 	return subtitleExtensions[ext]
 }
 
-// ShouldSkip returns true if the file should be skipped (samples, trailers, extras)
-func ShouldSkip(path string) bool {
-	return shouldSkip(path)
-}
+// shouldSkip returns true if the file should be skipped (samples, trailers, extras).
+// Size thresholds only apply to video files; subtitles are always small.
+func (i *Identifier) shouldSkip(file TorrentFile) bool {
+	if i.config.MinFileSize > 0 && isVideoFile(file.Path) {
+		return file.Size < i.config.MinFileSize
+	}
 
-// shouldSkip returns true if the file should be skipped (samples, trailers, extras)
-func shouldSkip(path string) bool {
-	lower := strings.ToLower(path)
-
-	skipPatterns := []string{
-		"sample",
-		"trailer",
-		"preview",
-		"extras/",
-		"extras\\",
-		"featurette",
-		"deleted.scene",
-		"deleted_scene",
-		"deleted-scene",
-		"behind.the.scene",
-		"behind_the_scene",
-		"behind-the-scene",
-		"bonus/",
-		"bonus\\",
-		"/extra/",
-		"\\extra\\",
-	}
-
-	for _, pattern := range skipPatterns {
+	lower := strings.ToLower(file.Path)
+	for _, pattern := range i.config.SkipPatterns {
 		if strings.Contains(lower, pattern) {
 			return true
 		}
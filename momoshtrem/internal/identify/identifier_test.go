@@ -0,0 +1,442 @@
+package identify
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestIdentifyAnimeTitleEpisode(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		absolute       bool
+		wantSeason     int
+		wantEpisode    int
+		wantConfidence Confidence
+	}{
+		{"number before paren", "[SubsPlease] Show Name 05 (1080p) [ABCD1234].mkv", false, 1, 5, ConfidenceLow},
+		{"number before bracket", "[Group] Show Name 12 [720p].mkv", false, 1, 12, ConfidenceLow},
+		{"number before resolution", "[Group] Show_Name_07_1080p.mkv", false, 1, 7, ConfidenceLow},
+		{"dash with title", "[Group] Show Name - 05 (1080p).mkv", false, 1, 5, ConfidenceLow},
+		{"version suffix", "[Group] Show Name 05v2 (1080p).mkv", false, 1, 5, ConfidenceLow},
+		{"three digit absolute", "[Group] Long Runner 104 (1080p).mkv", false, 1, 104, ConfidenceLow},
+		{"folder season", "Season 2/[Group] Show Name 05 (1080p).mkv", false, 2, 5, ConfidenceMedium},
+		{"absolute numbering", "[Group] Show Name 05 (1080p).mkv", true, 1, 5, ConfidenceMedium},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.AnimeAbsoluteEpisodes = tt.absolute
+			identifier := NewIdentifier(nil, cfg)
+
+			result := identifier.Identify([]TorrentFile{{Path: tt.path, Size: 500 << 20}}, "")
+			if len(result.IdentifiedFiles) != 1 {
+				t.Fatalf("Identify(%q) identified %d files, want 1", tt.path, len(result.IdentifiedFiles))
+			}
+			got := result.IdentifiedFiles[0]
+			if got.Season != tt.wantSeason || !slices.Equal(got.Episodes, []int{tt.wantEpisode}) || got.Confidence != tt.wantConfidence {
+				t.Errorf("Identify(%q) = S%d E%v %s (%s), want S%d E%d %s",
+					tt.path, got.Season, got.Episodes, got.Confidence, got.PatternUsed,
+					tt.wantSeason, tt.wantEpisode, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestIdentifyAnimeTitleEpisodeNoMatch(t *testing.T) {
+	identifier := NewIdentifier(nil, DefaultConfig())
+
+	paths := []string{
+		"[Group] Show Name (1080p).mkv",    // No episode number
+		"Show Name 05 (1080p).mkv",         // No group tag
+		"[Group] Show Name 2024 Movie.mkv", // Year, not an episode
+	}
+	for _, path := range paths {
+		result := identifier.Identify([]TorrentFile{{Path: path, Size: 500 << 20}}, "")
+		if len(result.IdentifiedFiles) != 0 {
+			got := result.IdentifiedFiles[0]
+			t.Errorf("Identify(%q) = S%d E%v (%s), want unidentified", path, got.Season, got.Episodes, got.PatternUsed)
+		}
+	}
+}
+
+func TestIdentifyNestedSeasonFolders(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		wantSeason   int
+		wantEpisode  int
+		wantConflict bool
+	}{
+		{"season folder not parent", "Show/1080p/S01/Show.E03.mkv", 1, 3, false},
+		{"pack root looks like season", "Show.S01-S03/1080p/S02/Show.Episode.04.mkv", 2, 4, false},
+		{"pattern agrees with folder", "Show/Season 02/Show.S02E01.mkv", 2, 1, false},
+		{"mislabeled release", "Show/Season 02/Show.S01E01.mkv", 1, 1, true},
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := identifier.Identify([]TorrentFile{{Path: tt.path, Size: 500 << 20}}, "")
+			if len(result.IdentifiedFiles) != 1 {
+				t.Fatalf("Identify(%q) identified %d files, want 1", tt.path, len(result.IdentifiedFiles))
+			}
+			got := result.IdentifiedFiles[0]
+			if got.Season != tt.wantSeason || !slices.Equal(got.Episodes, []int{tt.wantEpisode}) {
+				t.Errorf("Identify(%q) = S%d E%v (%s), want S%d E%d",
+					tt.path, got.Season, got.Episodes, got.PatternUsed, tt.wantSeason, tt.wantEpisode)
+			}
+			if got.SeasonConflict != tt.wantConflict || got.NeedsReview != tt.wantConflict {
+				t.Errorf("Identify(%q) conflict = %v, needs review = %v; want %v",
+					tt.path, got.SeasonConflict, got.NeedsReview, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestIdentifyKeywordSpecialsNumbered(t *testing.T) {
+	files := []TorrentFile{
+		{Path: "Show OVAs/Show.OVA.Christmas.mkv", Size: 300 << 20},
+		{Path: "Show OVAs/Show.OVA.Alpha.mkv", Size: 300 << 20},
+		{Path: "Show OVAs/Show.OVA.Beach.mkv", Size: 300 << 20},
+		{Path: "Show OVAs/Show.S00E02.Recap.mkv", Size: 300 << 20},
+	}
+	want := map[string]int{
+		"Show OVAs/Show.OVA.Alpha.mkv":     1,
+		"Show OVAs/Show.S00E02.Recap.mkv":  2, // Explicit number kept
+		"Show OVAs/Show.OVA.Beach.mkv":     3,
+		"Show OVAs/Show.OVA.Christmas.mkv": 4,
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	result := identifier.Identify(files, "Show OVAs")
+	if len(result.IdentifiedFiles) != len(files) {
+		t.Fatalf("identified %d files, want %d", len(result.IdentifiedFiles), len(files))
+	}
+	for _, got := range result.IdentifiedFiles {
+		if got.Season != 0 || !slices.Equal(got.Episodes, []int{want[got.FilePath]}) {
+			t.Errorf("%s = S%02d E%v, want S00E%02d", got.FilePath, got.Season, got.Episodes, want[got.FilePath])
+		}
+	}
+}
+
+func TestIdentifySpelledOutSeasons(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		wantSeason     int
+		wantEpisode    int
+		wantConfidence Confidence
+	}{
+		{"season word folder", "Show Season Two/Show.Episode.05.mkv", 2, 5, ConfidenceMedium},
+		{"roman numeral folder", "Show.Part.III/Show.E07.mkv", 3, 7, ConfidenceMedium},
+		{"season word episode", "Show Season Two Episode 3.mkv", 2, 3, ConfidenceMedium},
+		{"roman season word episode", "Show.Part.XII.Ep.Four.mkv", 12, 4, ConfidenceMedium},
+		{"numeric season word episode", "Show Season 4 Episode Two.mkv", 4, 2, ConfidenceMedium},
+		{"SxxExx wins over word", "Show Season Two S03E01.mkv", 3, 1, ConfidenceHigh},
+		{"numeric folder wins over word", "Show Season Two/Season 05/Show.Episode.01.mkv", 5, 1, ConfidenceMedium},
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := identifier.Identify([]TorrentFile{{Path: tt.path, Size: 500 << 20}}, "")
+			if len(result.IdentifiedFiles) != 1 {
+				t.Fatalf("Identify(%q) identified %d files, want 1", tt.path, len(result.IdentifiedFiles))
+			}
+			got := result.IdentifiedFiles[0]
+			if got.Season != tt.wantSeason || !slices.Equal(got.Episodes, []int{tt.wantEpisode}) || got.Confidence != tt.wantConfidence {
+				t.Errorf("Identify(%q) = S%d E%v %s (%s), want S%d E%d %s",
+					tt.path, got.Season, got.Episodes, got.Confidence, got.PatternUsed,
+					tt.wantSeason, tt.wantEpisode, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestExtractQualityBitDepth(t *testing.T) {
+	tests := map[string]string{
+		"Show.S01E01.1080p.WEB-DL.10bit.x265.mkv":     "10bit",
+		"Show.S01E01.2160p.BluRay.10-bit.HEVC.mkv":    "10bit",
+		"Show S01E01 [1080p 12bit].mkv":               "12bit",
+		"Show.S01E01.720p.HDTV.8bit.x264.mkv":         "8bit",
+		"[Group] Show - 01 [BD 1080p Hi10P FLAC].mkv": "10bit",
+		"Show.S01E01.1080p.WEB-DL.HDR.x265.mkv":       "", // HDR alone doesn't imply a depth
+		"Show.S01E01.110bits.mkv":                     "",
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	for name, want := range tests {
+		if got := identifier.extractQuality(name, &Context{}).BitDepth; got != want {
+			t.Errorf("extractQuality(%q).BitDepth = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFindMovieFileBitDepth(t *testing.T) {
+	identifier := NewIdentifier(nil, DefaultConfig())
+	result := identifier.FindMovieFile([]TorrentFile{
+		{Path: "Movie.2020.1080p.Hi10P.x264/Movie.2020.1080p.Hi10P.x264.mkv", Size: 8 << 30},
+		{Path: "Movie.2020.1080p.Hi10P.x264/Movie.nfo", Size: 1 << 10},
+	})
+	if !result.Found {
+		t.Fatal("FindMovieFile found no video")
+	}
+	if got := result.Quality; got.BitDepth != "10bit" || got.Codec != "H.264" || got.Resolution != "1080p" {
+		t.Errorf("Quality = %+v, want 10bit H.264 1080p", got)
+	}
+}
+
+func TestExtractQualityAudioCodec(t *testing.T) {
+	tests := map[string]string{
+		"Show.S01E01.1080p.WEB-DL.DDP5.1.H.264.mkv":       "E-AC3",
+		"Show.S01E01.1080p.WEB-DL.DD+5.1.x264.mkv":        "E-AC3",
+		"Show.S01E01.2160p.BluRay.TrueHD.Atmos.7.1.mkv":   "TrueHD",
+		"Show.S01E01.1080p.BluRay.DTS-HD.MA.5.1.x264.mkv": "DTS-HD",
+		"Show.S01E01.1080p.BluRay.DTS.x264.mkv":           "DTS",
+		"Show.S01E01.720p.HDTV.AAC2.0.x264.mkv":           "AAC",
+		"Show.S01E01.720p.HDTV.AC3.x264.mkv":              "AC3",
+		"Show.S01E01.720p.WEB.DD5.1.x264.mkv":             "AC3",
+		"[Group] Show - 01 [BD 1080p Hi10P FLAC].mkv":     "FLAC",
+		"Show.S01E01.1080p.WEB-DL.x265.mkv":               "",
+		"Show.S01E01.Addams.Family.1080p.WEB-DL.x265.mkv": "", // Not inside words
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	for name, want := range tests {
+		if got := identifier.extractQuality(name, &Context{}).AudioCodec; got != want {
+			t.Errorf("extractQuality(%q).AudioCodec = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseNumberWord(t *testing.T) {
+	tests := map[string]int{
+		"Two": 2, "twenty": 20, "III": 3, "xiv": 14, "XX": 20, "07": 7,
+		"IIII": 0, "XXI": 0, "Twentyone": 0, "": 0,
+	}
+	for in, want := range tests {
+		if got := parseNumberWord(in); got != want {
+			t.Errorf("parseNumberWord(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestMatchToShowMislabeledSeasonPack(t *testing.T) {
+	show := &library.Show{
+		Seasons: []library.Season{
+			{SeasonNumber: 1, Episodes: []library.Episode{{ID: 101, EpisodeNumber: 1}}},
+			{SeasonNumber: 2, Episodes: []library.Episode{{ID: 201, EpisodeNumber: 1}, {ID: 202, EpisodeNumber: 2}}},
+		},
+	}
+
+	// A season 2 pack where one file is really (or at least labeled) S01E01
+	identifier := NewIdentifier(nil, DefaultConfig())
+	result := identifier.Identify([]TorrentFile{
+		{Path: "Show.S02.1080p/Season 02/Show.S01E01.mkv", Size: 500 << 20},
+		{Path: "Show.S02.1080p/Season 02/Show.S02E02.mkv", Size: 500 << 20},
+	}, "Show.S02.1080p")
+
+	matched := MatchToShow(show, result)
+	if len(matched.Matched) != 2 {
+		t.Fatalf("matched %d files, want 2", len(matched.Matched))
+	}
+	for _, m := range matched.Matched {
+		switch m.Episode.ID {
+		case 101:
+			if !m.NeedsReview {
+				t.Errorf("%s matched S01E01 without needing review", m.FilePath)
+			}
+		case 202:
+			if m.NeedsReview {
+				t.Errorf("%s needs review, want clean match", m.FilePath)
+			}
+		default:
+			t.Errorf("%s matched unexpected episode %d", m.FilePath, m.Episode.ID)
+		}
+	}
+}
+
+func TestSelectMovieFilePolicies(t *testing.T) {
+	identifier := NewIdentifier(nil, DefaultConfig())
+	files := []TorrentFile{
+		{Path: "Movie.2020.1080p.BluRay.x264/Movie.2020.1080p.BluRay.x264.mkv", Size: 12 << 30},
+		{Path: "Movie.2020.2160p.WEB-DL.x265/Movie.2020.2160p.WEB-DL.x265.mkv", Size: 9 << 30},
+		{Path: "Movie.2020.720p.WEB-DL.HEVC/Movie.2020.720p.WEB-DL.HEVC.mkv", Size: 2 << 30},
+		{Path: "Movie.2020.1080p.BluRay.x264/Sample/sample.mkv", Size: 50 << 20},
+		{Path: "Movie.2020.1080p.BluRay.x264/Movie.nfo", Size: 1 << 10},
+	}
+
+	tests := []struct {
+		name  string
+		sel   FileSelection
+		want  string
+		codec string
+	}{
+		{"largest", FileSelection{Policy: SelectLargest}, files[0].Path, "H.264"},
+		{"highest resolution", FileSelection{Policy: SelectHighestResolution}, files[1].Path, "HEVC"},
+		{"prefer default codec", FileSelection{Policy: SelectPreferCodec}, files[1].Path, "HEVC"},
+		{"prefer x264", FileSelection{Policy: SelectPreferCodec, PreferCodec: "x264"}, files[0].Path, "H.264"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := identifier.SelectMovieFile(files, tt.sel)
+			if !result.Found || result.FilePath != tt.want || result.Quality.Codec != tt.codec {
+				t.Fatalf("SelectMovieFile() = %q (%s), want %q (%s)", result.FilePath, result.Quality.Codec, tt.want, tt.codec)
+			}
+			if len(result.Candidates) != 3 || len(result.OtherFiles) != 2 {
+				t.Errorf("got %d candidates and %d other files, want 3 and 2", len(result.Candidates), len(result.OtherFiles))
+			}
+		})
+	}
+
+	if _, err := ParseFileSelectionPolicy("smallest"); err == nil {
+		t.Errorf("ParseFileSelectionPolicy(smallest) succeeded, want error")
+	}
+	if policy, err := ParseFileSelectionPolicy(""); err != nil || policy != SelectLargest {
+		t.Errorf("ParseFileSelectionPolicy(\"\") = %q, %v; want %q", policy, err, SelectLargest)
+	}
+}
+
+func TestSelectMovieFileMultiPart(t *testing.T) {
+	identifier := NewIdentifier(nil, DefaultConfig())
+
+	tests := []struct {
+		name      string
+		files     []TorrentFile
+		wantParts []string
+		wantSize  int64
+	}{
+		{
+			name: "cd parts in order",
+			files: []TorrentFile{
+				{Path: "Movie.1999.DVDRip/Movie.1999.DVDRip.CD2.avi", Size: 750 << 20},
+				{Path: "Movie.1999.DVDRip/Movie.1999.DVDRip.CD1.avi", Size: 700 << 20},
+				{Path: "Movie.1999.DVDRip/Sample/sample.avi", Size: 10 << 20},
+			},
+			wantParts: []string{"Movie.1999.DVDRip/Movie.1999.DVDRip.CD1.avi", "Movie.1999.DVDRip/Movie.1999.DVDRip.CD2.avi"},
+			wantSize:  1450 << 20,
+		},
+		{
+			name: "part suffix with title number",
+			files: []TorrentFile{
+				{Path: "Movie 2 (2004)/Movie 2 (2004) part1.mkv", Size: 4 << 30},
+				{Path: "Movie 2 (2004)/Movie 2 (2004) part2.mkv", Size: 3 << 30},
+			},
+			wantParts: []string{"Movie 2 (2004)/Movie 2 (2004) part1.mkv", "Movie 2 (2004)/Movie 2 (2004) part2.mkv"},
+			wantSize:  7 << 30,
+		},
+		{
+			name: "incomplete set stays separate",
+			files: []TorrentFile{
+				{Path: "Movie/Movie.CD1.avi", Size: 700 << 20},
+				{Path: "Movie/Movie.CD3.avi", Size: 600 << 20},
+			},
+			wantSize: 700 << 20,
+		},
+		{
+			name: "different encodes stay separate",
+			files: []TorrentFile{
+				{Path: "Movie/Movie.720p.CD1.mkv", Size: 2 << 30},
+				{Path: "Movie/Movie.1080p.CD2.mkv", Size: 3 << 30},
+			},
+			wantSize: 3 << 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := identifier.FindMovieFile(tt.files)
+			if !result.Found || !slices.Equal(result.Parts, tt.wantParts) || result.FileSize != tt.wantSize {
+				t.Fatalf("FindMovieFile() parts = %v, size %d; want %v, size %d", result.Parts, result.FileSize, tt.wantParts, tt.wantSize)
+			}
+			if len(tt.wantParts) > 0 && (result.FilePath != tt.wantParts[0] || len(result.OtherFiles) != 0) {
+				t.Errorf("FilePath = %q, OtherFiles = %v; want first part and no others", result.FilePath, result.OtherFiles)
+			}
+		})
+	}
+}
+
+func TestReleaseVersionRank(t *testing.T) {
+	patterns := NewCompiledPatterns()
+	tests := []struct {
+		filename string
+		want     int
+	}{
+		{"Show.S01E01.1080p.WEB-DL.mkv", 0},
+		{"Show.S01E01.PROPER.1080p.mkv", 1},
+		{"Show.S01E01.REPACK.1080p.mkv", 1},
+		{"Show.S01E01.RERIP.1080p.mkv", 1},
+		{"Show.S01E01.REAL.1080p.mkv", 1},
+		{"Show.S01E01.REPACK2.1080p.mkv", 2},
+		{"Show.S01E01.REAL.PROPER.1080p.mkv", 2},
+		{"Show S01E01 [proper] (1080p).mkv", 1},
+		{"The.Real.Housewives.S01E01.mkv", 1}, // Title words match too, equally for every file of the show
+		{"Show.S01E01.Unreal.Properties.mkv", 0},
+	}
+	for _, tt := range tests {
+		if got := patterns.ReleaseVersionRank(tt.filename); got != tt.want {
+			t.Errorf("ReleaseVersionRank(%q) = %d, want %d", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestMatchToShowPrefersNewerReleaseVersion(t *testing.T) {
+	show := &library.Show{
+		Seasons: []library.Season{
+			{SeasonNumber: 1, Episodes: []library.Episode{{ID: 101, EpisodeNumber: 1}, {ID: 102, EpisodeNumber: 2}}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  map[int64]string // Episode ID -> matched file
+	}{
+		{
+			name:  "repack after original",
+			files: []string{"Show.S01E01.1080p.mkv", "Show.S01E01.REPACK.1080p.mkv", "Show.S01E02.1080p.mkv"},
+			want:  map[int64]string{101: "Show.S01E01.REPACK.1080p.mkv", 102: "Show.S01E02.1080p.mkv"},
+		},
+		{
+			name:  "original after proper",
+			files: []string{"Show.S01E01.PROPER.1080p.mkv", "Show.S01E01.1080p.mkv"},
+			want:  map[int64]string{101: "Show.S01E01.PROPER.1080p.mkv"},
+		},
+		{
+			name:  "lone real beats original",
+			files: []string{"Show.S01E01.1080p.mkv", "Show.S01E01.REAL.1080p.mkv"},
+			want:  map[int64]string{101: "Show.S01E01.REAL.1080p.mkv"},
+		},
+		{
+			name:  "real proper beats proper",
+			files: []string{"Show.S01E01.REAL.PROPER.1080p.mkv", "Show.S01E01.PROPER.1080p.mkv"},
+			want:  map[int64]string{101: "Show.S01E01.REAL.PROPER.1080p.mkv"},
+		},
+	}
+
+	identifier := NewIdentifier(nil, DefaultConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []TorrentFile
+			for _, f := range tt.files {
+				files = append(files, TorrentFile{Path: f, Size: 500 << 20})
+			}
+			matched := MatchToShow(show, identifier.Identify(files, ""))
+
+			got := make(map[int64]string)
+			for _, m := range matched.Matched {
+				if prev, dup := got[m.Episode.ID]; dup {
+					t.Errorf("episode %d matched both %s and %s", m.Episode.ID, prev, m.FilePath)
+				}
+				got[m.Episode.ID] = m.FilePath
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("matched = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
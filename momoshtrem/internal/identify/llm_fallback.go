@@ -0,0 +1,220 @@
+package identify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLLMTimeout = 30 * time.Second
+
+	// maxLLMResponseSize bounds the body read from the LLM endpoint
+	maxLLMResponseSize = 1024 * 1024
+)
+
+// llmSystemPrompt asks for one guess per file in a fixed JSON shape
+const llmSystemPrompt = `You identify TV episodes from torrent file paths.
+For each path, give the season and episode numbers it most likely contains.
+Use season 0 for specials. Leave out files you cannot identify; do not guess wildly.
+Reply with JSON only, in this form:
+{"files": [{"path": "<path exactly as given>", "season": 1, "episodes": [1]}]}`
+
+// LLMFallbackConfig configures the LLM fallback handler
+type LLMFallbackConfig struct {
+	URL     string        // OpenAI-compatible chat completions endpoint
+	Model   string        // Model name sent with each request
+	Timeout time.Duration // Per-request timeout (0 = 30s)
+}
+
+// LLMFallback asks an OpenAI-compatible chat endpoint (e.g. a local LLM) to
+// identify files the regex patterns could not. Its guesses are untrusted:
+// Identify downgrades them to low confidence and MatchToShow only accepts
+// episodes that exist in the show.
+type LLMFallback struct {
+	url    string
+	model  string
+	client *http.Client
+	log    *slog.Logger
+}
+
+// Compile-time verification
+var _ FallbackHandler = (*LLMFallback)(nil)
+
+// NewLLMFallback creates a fallback handler for the given endpoint
+func NewLLMFallback(cfg LLMFallbackConfig) *LLMFallback {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultLLMTimeout
+	}
+	return &LLMFallback{
+		url:    cfg.URL,
+		model:  cfg.Model,
+		client: &http.Client{Timeout: cfg.Timeout},
+		log:    slog.With("component", "llm-fallback"),
+	}
+}
+
+type llmChatRequest struct {
+	Model          string           `json:"model"`
+	Messages       []llmChatMessage `json:"messages"`
+	Temperature    float64          `json:"temperature"`
+	ResponseFormat map[string]any   `json:"response_format,omitempty"`
+}
+
+type llmChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type llmChatResponse struct {
+	Choices []struct {
+		Message llmChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// llmPrompt is the user message: the files plus hints from the torrent name
+type llmPrompt struct {
+	TorrentName string   `json:"torrent_name"`
+	SeasonHint  *int     `json:"season_hint,omitempty"`
+	IsComplete  bool     `json:"is_complete_series,omitempty"`
+	Files       []string `json:"files"`
+}
+
+// llmGuesses is the JSON the model is asked to reply with
+type llmGuesses struct {
+	Files []struct {
+		Path     string `json:"path"`
+		Season   int    `json:"season"`
+		Episodes []int  `json:"episodes"`
+	} `json:"files"`
+}
+
+// IdentifyBatch sends the unidentified paths to the LLM and returns its
+// guesses keyed by path. Paths the model didn't return, or invented, are left out.
+func (f *LLMFallback) IdentifyBatch(files []UnidentifiedFile, hints *Context) (map[string]*IdentifiedFile, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	prompt := llmPrompt{Files: make([]string, len(files))}
+	if hints != nil {
+		prompt.TorrentName = hints.TorrentName
+		prompt.SeasonHint = hints.SeasonHint
+		prompt.IsComplete = hints.IsComplete
+	}
+	requested := make(map[string]UnidentifiedFile, len(files))
+	for i, file := range files {
+		prompt.Files[i] = file.Path
+		requested[file.Path] = file
+	}
+
+	content, err := f.complete(prompt)
+	if err != nil {
+		f.log.Warn("LLM identification failed", "files", len(files), "error", err)
+		return nil, err
+	}
+
+	var guesses llmGuesses
+	if err := json.Unmarshal([]byte(extractJSONObject(content)), &guesses); err != nil {
+		f.log.Warn("LLM returned invalid JSON", "error", err)
+		return nil, fmt.Errorf("failed to parse LLM response: %w", err)
+	}
+
+	results := make(map[string]*IdentifiedFile)
+	for _, g := range guesses.Files {
+		file, ok := requested[g.Path]
+		if !ok || g.Season < 0 || !validEpisodes(g.Episodes) {
+			continue
+		}
+
+		fileType := FileTypeVideo
+		if isSubtitleFile(file.Path) {
+			fileType = FileTypeSubtitle
+		}
+		results[file.Path] = &IdentifiedFile{
+			FilePath:    file.Path,
+			FileSize:    file.Size,
+			FileType:    fileType,
+			Season:      g.Season,
+			Episodes:    g.Episodes,
+			IsSpecial:   g.Season == 0,
+			Confidence:  ConfidenceLow,
+			PatternUsed: "LLM fallback",
+			NeedsReview: true,
+		}
+	}
+
+	f.log.Info("LLM identified files", "requested", len(files), "identified", len(results))
+	return results, nil
+}
+
+// complete posts the prompt and returns the model's reply text
+func (f *LLMFallback) complete(prompt llmPrompt) (string, error) {
+	userContent, err := json.Marshal(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(llmChatRequest{
+		Model: f.model,
+		Messages: []llmChatMessage{
+			{Role: "system", Content: llmSystemPrompt},
+			{Role: "user", Content: string(userContent)},
+		},
+		ResponseFormat: map[string]any{"type": "json_object"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := f.client.Post(f.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to call LLM endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLLMResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read LLM response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("LLM endpoint returned status %d", resp.StatusCode)
+	}
+
+	var chat llmChatResponse
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return "", fmt.Errorf("failed to decode LLM response: %w", err)
+	}
+	if len(chat.Choices) == 0 {
+		return "", fmt.Errorf("LLM response has no choices")
+	}
+	return chat.Choices[0].Message.Content, nil
+}
+
+// extractJSONObject strips Markdown fences or chatter some models add around
+// the requested JSON object
+func extractJSONObject(content string) string {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return content
+	}
+	return content[start : end+1]
+}
+
+func validEpisodes(episodes []int) bool {
+	if len(episodes) == 0 {
+		return false
+	}
+	for _, ep := range episodes {
+		if ep <= 0 {
+			return false
+		}
+	}
+	return true
+}
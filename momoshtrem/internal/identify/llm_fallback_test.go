@@ -0,0 +1,89 @@
+package identify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// llmServer replies to chat completions with the given assistant message
+func llmServer(t *testing.T, reply string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req llmChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Model != "test-model" || len(req.Messages) != 2 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLLMFallbackGuessesAreUntrusted(t *testing.T) {
+	reply := "```json\n" + `{"files": [
+		{"path": "Show/Pilot.mkv", "season": 1, "episodes": [1]},
+		{"path": "Show/Finale.mkv", "season": 1, "episodes": [9]},
+		{"path": "Show/Invented.mkv", "season": 1, "episodes": [2]},
+		{"path": "Show/Bonus.mkv", "season": 1, "episodes": [0]}
+	]}` + "\n```"
+	srv := llmServer(t, reply)
+
+	identifier := NewIdentifier(NewLLMFallback(LLMFallbackConfig{URL: srv.URL, Model: "test-model"}), DefaultConfig())
+	result := identifier.Identify([]TorrentFile{
+		{Path: "Show/Pilot.mkv", Size: 500 << 20},
+		{Path: "Show/Finale.mkv", Size: 500 << 20},
+		{Path: "Show/Bonus.mkv", Size: 500 << 20},
+	}, "Show")
+
+	if len(result.IdentifiedFiles) != 2 || len(result.UnidentifiedFiles) != 1 {
+		t.Fatalf("identified %d, unidentified %v; want 2 and [Show/Bonus.mkv]",
+			len(result.IdentifiedFiles), result.UnidentifiedFiles)
+	}
+	for _, f := range result.IdentifiedFiles {
+		if f.Confidence != ConfidenceLow || !f.NeedsReview || !f.FromFallback || f.FileSize != 500<<20 {
+			t.Errorf("%s = %s review=%v fallback=%v size=%d; want low confidence fallback needing review",
+				f.FilePath, f.Confidence, f.NeedsReview, f.FromFallback, f.FileSize)
+		}
+	}
+
+	// Only the guess naming an episode the show has is matched
+	show := &library.Show{
+		Seasons: []library.Season{
+			{SeasonNumber: 1, Episodes: []library.Episode{{ID: 1, EpisodeNumber: 1}, {ID: 2, EpisodeNumber: 2}}},
+		},
+	}
+	matched := MatchToShow(show, result)
+	if len(matched.Matched) != 1 || matched.Matched[0].FilePath != "Show/Pilot.mkv" || !matched.Matched[0].NeedsReview {
+		t.Fatalf("matched = %+v, want only Show/Pilot.mkv needing review", matched.Matched)
+	}
+
+	reasons := make(map[string]UnmatchedReason)
+	for _, u := range matched.Unmatched {
+		reasons[u.FilePath] = u.Reason
+	}
+	if reasons["Show/Finale.mkv"] != ReasonUnverifiedGuess {
+		t.Errorf("Show/Finale.mkv reason = %q, want %q", reasons["Show/Finale.mkv"], ReasonUnverifiedGuess)
+	}
+}
+
+func TestLLMFallbackEndpointError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	identifier := NewIdentifier(NewLLMFallback(LLMFallbackConfig{URL: srv.URL, Model: "test-model"}), DefaultConfig())
+	result := identifier.Identify([]TorrentFile{{Path: "Show/Pilot.mkv", Size: 500 << 20}}, "Show")
+
+	// A failing endpoint leaves files unidentified rather than failing identification
+	if len(result.IdentifiedFiles) != 0 || len(result.UnidentifiedFiles) != 1 {
+		t.Errorf("identified %d, unidentified %d; want 0 and 1", len(result.IdentifiedFiles), len(result.UnidentifiedFiles))
+	}
+}
@@ -1,6 +1,8 @@
 package identify
 
 import (
+	"log/slog"
+
 	"github.com/shapedtime/momoshtrem/internal/library"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
 )
@@ -14,6 +16,8 @@ const (
 	ReasonSample            UnmatchedReason = "sample"
 	ReasonCouldNotIdentify  UnmatchedReason = "could_not_identify"
 	ReasonSpecialNotSupport UnmatchedReason = "special_not_supported"
+	ReasonImplausible       UnmatchedReason = "implausible_number"
+	ReasonUnverifiedGuess   UnmatchedReason = "unverified_guess"
 )
 
 // MatchResult contains the results of matching identified files to library episodes
@@ -32,18 +36,23 @@ type MatchedSubtitle struct {
 	LanguageCode string
 	LanguageName string
 	Format       string
+
+	Forced          bool
+	HearingImpaired bool
 }
 
 // MatchedEpisode represents a successful match between a torrent file and a library episode
 type MatchedEpisode struct {
-	Episode      *library.Episode
-	Season       *library.Season
-	FilePath     string
-	FileSize     int64
-	Quality      QualityInfo
-	Confidence   Confidence
-	NeedsReview  bool
-	PatternUsed  string
+	Episode          *library.Episode
+	Season           *library.Season
+	FilePath         string
+	FileSize         int64
+	Quality          QualityInfo
+	Confidence       Confidence
+	NeedsReview      bool
+	PatternUsed      string
+	SeasonFromFolder bool
+	ReleaseVersion   int
 }
 
 // UnmatchedFile represents a file that couldn't be matched to a library episode
@@ -88,6 +97,22 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 		}
 	}
 
+	// Highest release version seen per episode, for PROPER/REPACK preference
+	bestVersion := make(map[episodeKey]IdentifiedFile)
+
+	// Fallback guesses are only trusted when every episode exists in the show
+	unverified := func(identified IdentifiedFile) bool {
+		if !identified.FromFallback {
+			return false
+		}
+		for _, epNum := range identified.Episodes {
+			if _, ok := episodeLookup[episodeKey{season: identified.Season, episode: epNum}]; !ok {
+				return true
+			}
+		}
+		return false
+	}
+
 	// First pass: Process video files
 	for _, identified := range result.IdentifiedFiles {
 		// Skip subtitle files in first pass (processed separately below)
@@ -95,6 +120,27 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 			continue
 		}
 
+		// Reject season/episode numbers outside plausible bounds
+		if identified.Implausible {
+			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
+				FilePath: identified.FilePath,
+				Reason:   ReasonImplausible,
+				Season:   identified.Season,
+				Episode:  firstEpisode(identified.Episodes),
+			})
+			continue
+		}
+
+		if unverified(identified) {
+			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
+				FilePath: identified.FilePath,
+				Reason:   ReasonUnverifiedGuess,
+				Season:   identified.Season,
+				Episode:  firstEpisode(identified.Episodes),
+			})
+			continue
+		}
+
 		// Skip special episodes for now
 		if identified.IsSpecial {
 			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
@@ -110,15 +156,28 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 		for _, epNum := range identified.Episodes {
 			key := episodeKey{season: identified.Season, episode: epNum}
 			if entry, ok := episodeLookup[key]; ok {
+				if best, seen := bestVersion[key]; seen {
+					if identified.ReleaseVersion < best.ReleaseVersion {
+						logDiscardedVersion(key.season, key.episode, best, identified)
+						continue
+					}
+					if identified.ReleaseVersion > best.ReleaseVersion {
+						matchResult.Matched = dropEpisodeMatches(matchResult.Matched, entry.episode.ID, identified)
+					}
+				}
+				bestVersion[key] = identified
+
 				matchResult.Matched = append(matchResult.Matched, MatchedEpisode{
-					Episode:     entry.episode,
-					Season:      entry.season,
-					FilePath:    identified.FilePath,
-					FileSize:    identified.FileSize,
-					Quality:     identified.Quality,
-					Confidence:  identified.Confidence,
-					NeedsReview: identified.NeedsReview,
-					PatternUsed: identified.PatternUsed,
+					Episode:          entry.episode,
+					Season:           entry.season,
+					FilePath:         identified.FilePath,
+					FileSize:         identified.FileSize,
+					Quality:          identified.Quality,
+					Confidence:       identified.Confidence,
+					NeedsReview:      identified.NeedsReview,
+					PatternUsed:      identified.PatternUsed,
+					SeasonFromFolder: identified.SeasonFromFolder,
+					ReleaseVersion:   identified.ReleaseVersion,
 				})
 			} else {
 				matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
@@ -137,6 +196,27 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 			continue
 		}
 
+		// Reject season/episode numbers outside plausible bounds
+		if identified.Implausible {
+			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
+				FilePath: identified.FilePath,
+				Reason:   ReasonImplausible,
+				Season:   identified.Season,
+				Episode:  firstEpisode(identified.Episodes),
+			})
+			continue
+		}
+
+		if unverified(identified) {
+			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
+				FilePath: identified.FilePath,
+				Reason:   ReasonUnverifiedGuess,
+				Season:   identified.Season,
+				Episode:  firstEpisode(identified.Episodes),
+			})
+			continue
+		}
+
 		// Skip special episodes for now
 		if identified.IsSpecial {
 			matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
@@ -150,6 +230,7 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 
 		// Detect language from filename
 		langCode, langName, _ := subtitle.DetectLanguage(identified.FilePath)
+		forced, hearingImpaired := subtitle.DetectSubtitleFlags(identified.FilePath)
 
 		// Determine format from extension
 		format := subtitle.ParseFormat(identified.FilePath)
@@ -166,6 +247,9 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 					LanguageCode: langCode,
 					LanguageName: langName,
 					Format:       format,
+
+					Forced:          forced,
+					HearingImpaired: hearingImpaired,
 				})
 			} else {
 				matchResult.Unmatched = append(matchResult.Unmatched, UnmatchedFile{
@@ -191,65 +275,62 @@ func MatchToShow(show *library.Show, result *IdentificationResult) *MatchResult
 	return matchResult
 }
 
+// dropEpisodeMatches removes earlier matches for an episode that are superseded
+// by a higher release version (e.g. a PROPER replacing the original)
+func dropEpisodeMatches(matched []MatchedEpisode, episodeID int64, preferred IdentifiedFile) []MatchedEpisode {
+	kept := matched[:0]
+	for _, m := range matched {
+		if m.Episode.ID == episodeID {
+			slog.Info("Preferred newer release version",
+				"season", m.Season.SeasonNumber,
+				"episode", m.Episode.EpisodeNumber,
+				"preferred", preferred.FilePath,
+				"preferred_version", preferred.ReleaseVersion,
+				"discarded", m.FilePath,
+				"discarded_version", m.ReleaseVersion,
+			)
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// logDiscardedVersion notes a file skipped because a higher release version exists
+func logDiscardedVersion(season, episode int, preferred, discarded IdentifiedFile) {
+	slog.Info("Discarded older release version",
+		"season", season,
+		"episode", episode,
+		"preferred", preferred.FilePath,
+		"preferred_version", preferred.ReleaseVersion,
+		"discarded", discarded.FilePath,
+		"discarded_version", discarded.ReleaseVersion,
+	)
+}
+
 // MovieMatchResult contains the result of finding a movie file in a torrent
 type MovieMatchResult struct {
-	Found       bool
-	FilePath    string
-	FileSize    int64
-	Quality     QualityInfo
-	OtherFiles  []string // Other video files that were not selected
+	Found      bool
+	FilePath   string
+	FileSize   int64
+	Quality    QualityInfo
+	OtherFiles []string         // Other video files that were not selected
+	Candidates []MovieCandidate // All eligible video files, best first
+
+	// Ordered paths when the selected file is one part of a multi-part movie
+	// (CD1, CD2, ...); nil otherwise. FilePath is then the first part and
+	// FileSize the combined size of all parts.
+	Parts []string
 }
 
 // FindMovieFile finds the best movie file in a list of torrent files
 // It selects the largest video file that isn't a sample/trailer
-func FindMovieFile(files []TorrentFile) *MovieMatchResult {
-	result := &MovieMatchResult{
-		Found:      false,
-		OtherFiles: make([]string, 0),
-	}
-
-	var bestFile *TorrentFile
-	var bestSize int64
-
-	patterns := NewCompiledPatterns()
-
-	for i := range files {
-		file := &files[i]
-
-		// Skip non-video files
-		if !isVideoFile(file.Path) {
-			continue
-		}
-
-		// Skip samples, trailers, extras
-		if shouldSkip(file.Path) {
-			continue
-		}
-
-		// Track as potential file
-		if file.Size > bestSize {
-			// If we had a previous best, add it to other files
-			if bestFile != nil {
-				result.OtherFiles = append(result.OtherFiles, bestFile.Path)
-			}
-			bestFile = file
-			bestSize = file.Size
-		} else {
-			result.OtherFiles = append(result.OtherFiles, file.Path)
-		}
-	}
-
-	if bestFile != nil {
-		result.Found = true
-		result.FilePath = bestFile.Path
-		result.FileSize = bestFile.Size
-		result.Quality = extractQualityFromPath(bestFile.Path, patterns)
-	}
-
-	return result
+func (i *Identifier) FindMovieFile(files []TorrentFile) *MovieMatchResult {
+	return i.SelectMovieFile(files, FileSelection{Policy: SelectLargest})
 }
 
-// extractQualityFromPath extracts quality info from a file path
+// extractQualityFromPath extracts quality info from a file path. Shared by
+// episode identification and movie file selection.
 func extractQualityFromPath(path string, patterns *CompiledPatterns) QualityInfo {
 	quality := QualityInfo{}
 
@@ -273,6 +354,16 @@ func extractQualityFromPath(path string, patterns *CompiledPatterns) QualityInfo
 		quality.HDR = true
 	}
 
+	// Bit depth (left empty rather than inferred from codec or HDR)
+	if match := patterns.BitDepth.FindStringSubmatch(path); match != nil {
+		quality.BitDepth = match[1] + match[2] + "bit"
+	}
+
+	// Audio codec
+	if match := patterns.AudioCodec.FindStringSubmatch(path); match != nil {
+		quality.AudioCodec = normalizeAudioCodec(match[1])
+	}
+
 	return quality
 }
 
@@ -1,6 +1,9 @@
 package identify
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 // CompiledPatterns contains all precompiled regex patterns for episode identification
 type CompiledPatterns struct {
@@ -12,8 +15,10 @@ type CompiledPatterns struct {
 
 	// Secondary patterns (Medium confidence)
 	SeasonEpisode *regexp.Regexp // Season 1 Episode 1
+	SeasonWordEp  *regexp.Regexp // Season Two Episode 3, Part III Episode 5
 	EpNumber      *regexp.Regexp // Ep 1, Episode 1, E01
 	AnimeEpisode  *regexp.Regexp // [Group] Show - 01 [quality]
+	AnimeTitleEp  *regexp.Regexp // [Group] Show Name 05 (1080p)
 	DateYMD       *regexp.Regexp // 2024.01.15 (daily shows)
 
 	// Low confidence patterns
@@ -21,16 +26,25 @@ type CompiledPatterns struct {
 	Concatenated3 *regexp.Regexp // 101 (SEE format)
 
 	// Folder/context patterns
-	SeasonFolder *regexp.Regexp // Season 01, S01 (in folder path)
+	SeasonFolder     *regexp.Regexp // Season 01, S01 (in folder path)
+	SeasonWordFolder *regexp.Regexp // Season Two, Part III (in folder path)
 
 	// Quality extraction patterns
 	Resolution *regexp.Regexp // 2160p, 4K, 1080p, 720p, 480p
 	Source     *regexp.Regexp // BluRay, WEB-DL, HDTV, DVDRip
 	Codec      *regexp.Regexp // x264, x265, H.264, H.265, HEVC, AV1
 	HDR        *regexp.Regexp // HDR, HDR10, HDR10+, Dolby Vision, DV
+	BitDepth   *regexp.Regexp // 10bit, 10-bit, 12bit, 8bit, Hi10P
+	AudioCodec *regexp.Regexp // AAC, AC3, DD+, EAC3, DTS-HD, TrueHD, FLAC
+
+	// Multi-part movie patterns
+	MoviePart *regexp.Regexp // CD1, CD2, Part1, Part 2, Disc1
 
 	// Special episode patterns
 	Special *regexp.Regexp // S00E01, Special, OVA, OAD
+
+	// Release version patterns
+	ReleaseVersion *regexp.Regexp // PROPER, REPACK, REAL, RERIP
 }
 
 // NewCompiledPatterns creates and returns all compiled regex patterns
@@ -53,6 +67,9 @@ func NewCompiledPatterns() *CompiledPatterns {
 		// Season 1 Episode 1, Season.1.Episode.1
 		SeasonEpisode: regexp.MustCompile(`(?i)Season[.\s]*(\d+)[.\s]*Episode[.\s]*(\d+)`),
 
+		// Season Two Episode 3, Part.III.Ep.05, Season 4 Episode Two
+		SeasonWordEp: regexp.MustCompile(`(?i)(?:^|[.\s_\-])(?:Season|Part)[.\s_\-]*(\d{1,2}|` + numberWordPattern + `)[.\s_\-]+Ep(?:isode)?[.\s_\-]*(\d{1,3}|` + numberWordPattern + `)(?:[.\s_\-\[\(]|$)`),
+
 		// Ep 1, Episode 1, Ep01, Episode.01, E01 (standalone)
 		EpNumber: regexp.MustCompile(`(?i)(?:Ep(?:isode)?[.\s]*|(?:^|[.\s_-])E)(\d{1,3})(?:[.\s_\-\[]|$)`),
 
@@ -60,6 +77,11 @@ func NewCompiledPatterns() *CompiledPatterns {
 		// Matches: ] - 01 [ or ] - 01v2 or - 01 [
 		AnimeEpisode: regexp.MustCompile(`(?:\]|^)\s*-?\s*(\d{2,3})(?:v\d)?(?:\s*[\[\(]|$)`),
 
+		// Anime format with the number after the title: [Group] Show Name 05 (1080p)
+		// Matched against the name without extension. The number must be followed
+		// by a paren/bracket, a resolution token or the end of the name.
+		AnimeTitleEp: regexp.MustCompile(`(?i)^\[[^\]]+\][\s_.]*[^\[\(]+?[\s_.]+(?:-[\s_.]*)?(\d{2,3})(?:v\d)?[\s_.]*(?:[\[\(]|(?:2160|1080|720|480)p|$)`),
+
 		// Daily shows: 2024.01.15, 2024-01-15
 		DateYMD: regexp.MustCompile(`(\d{4})[.\-](\d{2})[.\-](\d{2})`),
 
@@ -74,6 +96,9 @@ func NewCompiledPatterns() *CompiledPatterns {
 		// Season 01, Season.01, Season 1, S01, S1
 		SeasonFolder: regexp.MustCompile(`(?i)(?:Season[.\s]*|S)(\d{1,2})(?:[/\\]|$)`),
 
+		// Show Season Two, Show.Part.III (word or Roman numeral ending the folder name)
+		SeasonWordFolder: regexp.MustCompile(`(?i)(?:^|[.\s_\-])(?:Season|Part)[.\s_\-]*(` + numberWordPattern + `)$`),
+
 		// Quality extraction patterns
 		// 2160p, 1080p, 720p, 480p, 4K, UHD
 		Resolution: regexp.MustCompile(`(?i)(2160|1080|720|480)p|4K|UHD`),
@@ -87,10 +112,55 @@ func NewCompiledPatterns() *CompiledPatterns {
 		// HDR, HDR10, HDR10+, Dolby Vision, DV, DoVi
 		HDR: regexp.MustCompile(`(?i)(HDR10\+?|HDR|Dolby[\s.]?Vision|DV|DoVi)`),
 
+		// 10bit, 10-bit, 10.bit, 12bit, 8bit, Hi10P, Hi10 (anime 10-bit H.264)
+		BitDepth: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(?:(8|10|12)[\s._\-]?bits?|Hi(10)P?)(?:[^a-z0-9]|$)`),
+
+		// AAC2.0, AC3, DD5.1, DD+5.1, DDP5.1, EAC3, E-AC-3, DTS, DTS-HD.MA, TrueHD, FLAC, Opus, MP3
+		// (channel counts may follow directly; DTS:X is left as DTS since "DTS.x264" is common)
+		AudioCodec: regexp.MustCompile(`(?i)(?:^|[^a-z0-9])(TrueHD|DTS(?:[\s._\-]?HD)?|E[\s._\-]?AC[\s._\-]?3|DDP|DD\+|AC[\s._\-]?3|DD|AAC|FLAC|Opus|MP3)(?:[^a-z]|$)`),
+
+		// Multi-part movie patterns
+		// CD1, cd.2, Part1, Part 2, Disc1 - matched against the file name without extension
+		MoviePart: regexp.MustCompile(`(?i)(?:^|[.\s_\-\[\(])(?:CD|Disc|Disk|Part)[.\s_\-]?(\d{1,2})(?:[.\s_\-\]\)]|$)`),
+
 		// Special episode patterns
 		// S00E01, Special, Specials, OVA, OAD, ONA
 		Special: regexp.MustCompile(`(?i)S00E(\d+)|(?:^|[.\s_\-])(Special|OVA|OAD|ONA)(?:[.\s_\-]|$)`),
+
+		// Release version patterns
+		// PROPER, REPACK, REPACK2, RERIP, REAL - matched against a single name token
+		ReleaseVersion: regexp.MustCompile(`(?i)^(PROPER|REPACK|RERIP|REAL)(\d)?$`),
+	}
+}
+
+// ReleaseVersionRank ranks a filename by its PROPER/REPACK/RERIP/REAL markers.
+// An original release ranks 0 and each marker adds its numeric suffix (default 1),
+// so a REAL.PROPER outranks a PROPER and a lone REAL still outranks no marker.
+func (p *CompiledPatterns) ReleaseVersionRank(filename string) int {
+	rank := 0
+	for _, token := range splitReleaseTokens(filename) {
+		m := p.ReleaseVersion.FindStringSubmatch(token)
+		if m == nil {
+			continue
+		}
+		if m[2] != "" {
+			rank += parseInt(m[2])
+		} else {
+			rank++
+		}
 	}
+	return rank
+}
+
+// splitReleaseTokens splits a filename on common release-name delimiters
+func splitReleaseTokens(filename string) []string {
+	return strings.FieldsFunc(filename, func(r rune) bool {
+		switch r {
+		case '.', ' ', '_', '-', '[', ']', '(', ')':
+			return true
+		}
+		return false
+	})
 }
 
 // ExtractMultiEpisodes parses the episode portion of multi-episode patterns like "E01E02E03"
@@ -124,6 +194,33 @@ func ExpandEpisodeRange(start, end int) []int {
 	return episodes
 }
 
+// numberWords maps English number words and Roman numerals (1-20) to their values
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+	"eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15,
+	"sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19, "twenty": 20,
+
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5,
+	"vi": 6, "vii": 7, "viii": 8, "ix": 9, "x": 10,
+	"xi": 11, "xii": 12, "xiii": 13, "xiv": 14, "xv": 15,
+	"xvi": 16, "xvii": 17, "xviii": 18, "xix": 19, "xx": 20,
+}
+
+// numberWordPattern matches candidates for parseNumberWord; the map lookup
+// rejects invalid numerals like IIII
+const numberWordPattern = `one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve|` +
+	`thirteen|fourteen|fifteen|sixteen|seventeen|eighteen|nineteen|twenty|[ivx]{1,5}`
+
+// parseNumberWord converts a number word ("Two"), Roman numeral ("III") or
+// digits to int, returns 0 if s is none of these
+func parseNumberWord(s string) int {
+	if n := parseInt(s); n > 0 {
+		return n
+	}
+	return numberWords[strings.ToLower(s)]
+}
+
 // parseInt safely converts a string to int, returns 0 on error
 func parseInt(s string) int {
 	var result int
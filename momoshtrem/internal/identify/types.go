@@ -24,6 +24,8 @@ type QualityInfo struct {
 	Source     string `json:"source"`     // BluRay, WEB-DL, HDTV
 	Codec      string `json:"codec"`      // x264, x265, HEVC
 	HDR        bool   `json:"hdr"`
+	BitDepth   string `json:"bit_depth,omitempty"` // 8bit, 10bit, 12bit; empty when not in the name
+	AudioCodec string `json:"audio_codec,omitempty"` // AAC, AC3, E-AC3, DTS, DTS-HD, TrueHD, FLAC, Opus, MP3; empty when not in the name
 }
 
 // IdentifiedFile represents a file with identified episode information
@@ -39,6 +41,10 @@ type IdentifiedFile struct {
 	PatternUsed      string      `json:"pattern_used"`
 	NeedsReview      bool        `json:"needs_review"`
 	SeasonFromFolder bool        `json:"season_from_folder"` // true if season extracted from folder path
+	ReleaseVersion   int         `json:"release_version"`    // 0 for original, higher for PROPER/REPACK
+	Implausible      bool        `json:"implausible"`        // season/episode outside configured bounds
+	SeasonConflict   bool        `json:"season_conflict"`    // filename season differs from folder season
+	FromFallback     bool        `json:"from_fallback"`      // guessed by the fallback handler, not a pattern
 }
 
 // IdentificationResult is the result of identifying episodes in a torrent
@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrQueueFull is returned when all workers are busy and the wait queue is at capacity.
+var ErrQueueFull = errors.New("job queue is full")
+
+// Config bounds long-running operations (torrent assignment, subtitle downloads, syncs).
+type Config struct {
+	Workers    int           // Operations allowed to run at once
+	QueueSize  int           // Operations allowed to wait for a worker; more are rejected
+	RetryAfter time.Duration // Suggested client backoff when rejected
+}
+
+// Queue limits concurrent heavy operations with a bounded wait queue.
+// Callers beyond Workers+QueueSize are rejected instead of piling up goroutines.
+// A nil *Queue imposes no limit.
+type Queue struct {
+	slots      chan struct{} // One token per running operation
+	maxWaiting int64
+	retryAfter time.Duration
+
+	waiting  atomic.Int64
+	rejected atomic.Int64
+}
+
+// NewQueue creates a queue. Returns nil (no limit) if cfg.Workers <= 0.
+func NewQueue(cfg Config) *Queue {
+	if cfg.Workers <= 0 {
+		return nil
+	}
+	return &Queue{
+		slots:      make(chan struct{}, cfg.Workers),
+		maxWaiting: int64(max(cfg.QueueSize, 0)),
+		retryAfter: cfg.RetryAfter,
+	}
+}
+
+// Acquire blocks until a worker slot is free and returns its release function.
+// Returns ErrQueueFull without blocking if the wait queue is full, or the
+// context error if ctx ends while waiting.
+func (q *Queue) Acquire(ctx context.Context) (release func(), err error) {
+	if q == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+		return q.release, nil
+	default:
+	}
+
+	if !q.enqueue() {
+		return nil, ErrQueueFull
+	}
+	defer q.waiting.Add(-1)
+
+	select {
+	case q.slots <- struct{}{}:
+		return q.release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Submit runs fn in the background once a worker slot is free.
+// Returns ErrQueueFull if the wait queue is full.
+func (q *Queue) Submit(fn func()) error {
+	if q == nil {
+		go fn()
+		return nil
+	}
+
+	select {
+	case q.slots <- struct{}{}:
+		go q.run(fn)
+		return nil
+	default:
+	}
+
+	if !q.enqueue() {
+		return ErrQueueFull
+	}
+	go func() {
+		q.slots <- struct{}{}
+		q.waiting.Add(-1)
+		q.run(fn)
+	}()
+	return nil
+}
+
+// enqueue reserves a wait queue position, counting a rejection if none is left
+func (q *Queue) enqueue() bool {
+	if q.waiting.Add(1) > q.maxWaiting {
+		q.waiting.Add(-1)
+		q.rejected.Add(1)
+		return false
+	}
+	return true
+}
+
+func (q *Queue) run(fn func()) {
+	defer q.release()
+	fn()
+}
+
+func (q *Queue) release() {
+	<-q.slots
+}
+
+// Depth returns the number of operations waiting for a worker.
+func (q *Queue) Depth() int {
+	if q == nil {
+		return 0
+	}
+	return int(q.waiting.Load())
+}
+
+// Running returns the number of operations currently holding a worker.
+func (q *Queue) Running() int {
+	if q == nil {
+		return 0
+	}
+	return len(q.slots)
+}
+
+// Rejected returns the number of operations turned away because the queue was full.
+func (q *Queue) Rejected() int64 {
+	if q == nil {
+		return 0
+	}
+	return q.rejected.Load()
+}
+
+// RetryAfter returns the backoff suggested to rejected clients.
+func (q *Queue) RetryAfter() time.Duration {
+	if q == nil {
+		return 0
+	}
+	return q.retryAfter
+}
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestQueueRejectsWhenFull(t *testing.T) {
+	q := NewQueue(Config{Workers: 1, QueueSize: 1})
+
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("first Acquire: %v", err)
+	}
+
+	// Second caller waits for the single worker
+	started := make(chan struct{})
+	done := make(chan struct{})
+	if err := q.Submit(func() { close(started); <-done }); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if got := q.Depth(); got != 1 {
+		t.Errorf("Depth() = %d, want 1", got)
+	}
+
+	// Third is rejected: worker busy and wait queue full
+	if _, err := q.Acquire(context.Background()); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Acquire with full queue = %v, want ErrQueueFull", err)
+	}
+	if got := q.Rejected(); got != 1 {
+		t.Errorf("Rejected() = %d, want 1", got)
+	}
+
+	release()
+	<-started
+	if got := q.Depth(); got != 0 {
+		t.Errorf("Depth() after release = %d, want 0", got)
+	}
+	if got := q.Running(); got != 1 {
+		t.Errorf("Running() = %d, want 1", got)
+	}
+	close(done)
+}
+
+func TestQueueAcquireCanceled(t *testing.T) {
+	q := NewQueue(Config{Workers: 1, QueueSize: 1})
+	release, _ := q.Acquire(context.Background())
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire with canceled ctx = %v, want context.Canceled", err)
+	}
+	if got := q.Depth(); got != 0 {
+		t.Errorf("Depth() = %d, want 0", got)
+	}
+}
+
+func TestNilQueueIsUnlimited(t *testing.T) {
+	q := NewQueue(Config{})
+	if q != nil {
+		t.Fatalf("NewQueue with no workers = %v, want nil", q)
+	}
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("nil Acquire: %v", err)
+	}
+	release()
+}
@@ -2,7 +2,9 @@ package library
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"log/slog"
 )
 
 // AssignmentRepository handles torrent assignment database operations
@@ -20,22 +22,40 @@ type scanner interface {
 	Scan(dest ...interface{}) error
 }
 
-// scanAssignment scans a row into a TorrentAssignment
-func scanAssignment(s scanner) (*TorrentAssignment, error) {
+// scanAssignment scans a row into a TorrentAssignment. Columns selected after
+// the assignment's own are scanned into extra.
+func scanAssignment(s scanner, extra ...interface{}) (*TorrentAssignment, error) {
 	assignment := &TorrentAssignment{}
-	var resolution, source sql.NullString
+	var resolution, source, bitDepth, partPaths, codec, confidence, pattern, localPath, audioCodec sql.NullString
+	var bitrate, runtime sql.NullInt64
 
-	err := s.Scan(
+	dest := []interface{}{
 		&assignment.ID, &assignment.ItemType, &assignment.ItemID,
 		&assignment.InfoHash, &assignment.MagnetURI, &assignment.FilePath, &assignment.FileSize,
-		&resolution, &source, &assignment.IsActive, &assignment.CreatedAt,
-	)
-	if err != nil {
+		&resolution, &source, &bitDepth, &partPaths, &assignment.IsActive, &assignment.CreatedAt,
+		&bitrate, &runtime, &codec, &assignment.HDR,
+		&confidence, &pattern, &assignment.SeasonFromFolder, &assignment.NeedsReview, &localPath, &audioCodec,
+	}
+	if err := s.Scan(append(dest, extra...)...); err != nil {
 		return nil, err
 	}
 
 	assignment.Resolution = resolution.String
 	assignment.Source = source.String
+	assignment.BitDepth = bitDepth.String
+	assignment.Codec = codec.String
+	assignment.AudioCodec = audioCodec.String
+	assignment.Confidence = confidence.String
+	assignment.PatternUsed = pattern.String
+	assignment.LocalPath = localPath.String
+	assignment.PartPaths = decodePartPaths(partPaths)
+	if bitrate.Valid {
+		assignment.BitrateBps = &bitrate.Int64
+	}
+	if runtime.Valid {
+		seconds := int(runtime.Int64)
+		assignment.RuntimeSeconds = &seconds
+	}
 
 	return assignment, nil
 }
@@ -48,6 +68,10 @@ func (r *AssignmentRepository) Create(assignment *TorrentAssignment) error {
 	}
 	defer tx.Rollback()
 
+	if assignment.LocalPath == "" {
+		r.warnDuplicates(tx, assignment)
+	}
+
 	// Deactivate any existing active assignments for this item
 	_, err = tx.Exec(
 		`UPDATE torrent_assignments SET is_active = FALSE WHERE item_type = $1 AND item_id = $2 AND is_active = TRUE`,
@@ -59,10 +83,15 @@ func (r *AssignmentRepository) Create(assignment *TorrentAssignment) error {
 
 	// Create new assignment
 	err = tx.QueryRow(
-		`INSERT INTO torrent_assignments (item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, is_active)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, TRUE) RETURNING id, created_at`,
+		`INSERT INTO torrent_assignments (item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, codec, hdr,
+		                                  confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec, is_active)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, TRUE) RETURNING id, created_at`,
 		assignment.ItemType, assignment.ItemID, assignment.InfoHash, assignment.MagnetURI,
 		assignment.FilePath, assignment.FileSize, nullString(assignment.Resolution), nullString(assignment.Source),
+		nullString(assignment.BitDepth), encodePartPaths(assignment.PartPaths),
+		nullString(assignment.Codec), assignment.HDR,
+		nullString(assignment.Confidence), nullString(assignment.PatternUsed), assignment.SeasonFromFolder, assignment.NeedsReview,
+		nullString(assignment.LocalPath), nullString(assignment.AudioCodec),
 	).Scan(&assignment.ID, &assignment.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create assignment: %w", err)
@@ -72,10 +101,50 @@ func (r *AssignmentRepository) Create(assignment *TorrentAssignment) error {
 	return tx.Commit()
 }
 
+// warnDuplicates logs active assignments from other torrents whose file has
+// the same size and resolution as the new one's: most likely the same file,
+// leaving one of the torrents redundant. The item's own assignment is about to
+// be replaced, so it never counts. Best-effort; errors are only logged.
+func (r *AssignmentRepository) warnDuplicates(tx *sql.Tx, assignment *TorrentAssignment) {
+	rows, err := tx.Query(
+		`SELECT id, item_type, item_id, info_hash FROM torrent_assignments
+		 WHERE is_active = TRUE AND local_path IS NULL AND file_size = $1
+		   AND COALESCE(resolution, '') = $2 AND info_hash <> $3
+		   AND NOT (item_type = $4 AND item_id = $5)`,
+		assignment.FileSize, assignment.Resolution, assignment.InfoHash,
+		assignment.ItemType, assignment.ItemID,
+	)
+	if err != nil {
+		slog.Warn("Failed to check for duplicate assignments", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, itemID int64
+		var itemType, infoHash string
+		if err := rows.Scan(&id, &itemType, &itemID, &infoHash); err != nil {
+			slog.Warn("Failed to check for duplicate assignments", "error", err)
+			return
+		}
+		slog.Warn("Assignment looks like a duplicate of another torrent's file",
+			"item_type", assignment.ItemType,
+			"item_id", assignment.ItemID,
+			"info_hash", assignment.InfoHash,
+			"file_size", assignment.FileSize,
+			"existing_assignment_id", id,
+			"existing_item_type", itemType,
+			"existing_item_id", itemID,
+			"existing_info_hash", infoHash,
+		)
+	}
+}
+
 // GetByID retrieves an assignment by its ID
 func (r *AssignmentRepository) GetByID(id int64) (*TorrentAssignment, error) {
 	row := r.db.QueryRow(
-		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, is_active, created_at
+		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, is_active, created_at, bitrate_bps, runtime_seconds, codec, hdr,
+		        confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec
 		 FROM torrent_assignments WHERE id = $1`,
 		id,
 	)
@@ -94,7 +163,8 @@ func (r *AssignmentRepository) GetByID(id int64) (*TorrentAssignment, error) {
 // GetActiveForItem retrieves the active assignment for a library item
 func (r *AssignmentRepository) GetActiveForItem(itemType ItemType, itemID int64) (*TorrentAssignment, error) {
 	row := r.db.QueryRow(
-		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, is_active, created_at
+		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, is_active, created_at, bitrate_bps, runtime_seconds, codec, hdr,
+		        confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec
 		 FROM torrent_assignments WHERE item_type = $1 AND item_id = $2 AND is_active = TRUE`,
 		itemType, itemID,
 	)
@@ -110,10 +180,41 @@ func (r *AssignmentRepository) GetActiveForItem(itemType ItemType, itemID int64)
 	return assignment, nil
 }
 
+// GetActiveForItems retrieves the active assignments for several items of one type
+// in a single query, keyed by item ID. Items without an assignment are absent.
+func (r *AssignmentRepository) GetActiveForItems(itemType ItemType, itemIDs []int64) (map[int64]*TorrentAssignment, error) {
+	assignments := make(map[int64]*TorrentAssignment, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return assignments, nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, is_active, created_at, bitrate_bps, runtime_seconds, codec, hdr,
+		        confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec
+		 FROM torrent_assignments WHERE item_type = $1 AND item_id = ANY($2) AND is_active = TRUE`,
+		itemType, itemIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active assignments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments[assignment.ItemID] = assignment
+	}
+
+	return assignments, rows.Err()
+}
+
 // GetByInfoHash retrieves all assignments using a specific torrent
 func (r *AssignmentRepository) GetByInfoHash(infoHash string) ([]*TorrentAssignment, error) {
 	rows, err := r.db.Query(
-		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, is_active, created_at
+		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, is_active, created_at, bitrate_bps, runtime_seconds, codec, hdr,
+		        confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec
 		 FROM torrent_assignments WHERE info_hash = $1`,
 		infoHash,
 	)
@@ -137,7 +238,8 @@ func (r *AssignmentRepository) GetByInfoHash(infoHash string) ([]*TorrentAssignm
 // GetActiveByInfoHash retrieves all active assignments using a specific torrent
 func (r *AssignmentRepository) GetActiveByInfoHash(infoHash string) ([]*TorrentAssignment, error) {
 	rows, err := r.db.Query(
-		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, is_active, created_at
+		`SELECT id, item_type, item_id, info_hash, magnet_uri, file_path, file_size, resolution, source, bit_depth, part_paths, is_active, created_at, bitrate_bps, runtime_seconds, codec, hdr,
+		        confidence, pattern_used, season_from_folder, needs_review, local_path, audio_codec
 		 FROM torrent_assignments WHERE info_hash = $1 AND is_active = TRUE`,
 		infoHash,
 	)
@@ -158,6 +260,31 @@ func (r *AssignmentRepository) GetActiveByInfoHash(infoHash string) ([]*TorrentA
 	return assignments, rows.Err()
 }
 
+// UpdateMediaInfo stores the probed bitrate and runtime of an assignment's file
+func (r *AssignmentRepository) UpdateMediaInfo(id int64, bitrateBps int64, runtimeSeconds int) error {
+	_, err := r.db.Exec(
+		`UPDATE torrent_assignments SET bitrate_bps = $1, runtime_seconds = $2 WHERE id = $3`,
+		bitrateBps, runtimeSeconds, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update assignment media info: %w", err)
+	}
+	return nil
+}
+
+// UpdateQuality stores an assignment's quality fields parsed from its file name
+// (resolution, source, codec, HDR, bit depth and audio codec)
+func (r *AssignmentRepository) UpdateQuality(a *TorrentAssignment) error {
+	_, err := r.db.Exec(
+		`UPDATE torrent_assignments SET resolution = $1, source = $2, codec = $3, hdr = $4, bit_depth = $5, audio_codec = $6 WHERE id = $7`,
+		nullString(a.Resolution), nullString(a.Source), nullString(a.Codec), a.HDR, nullString(a.BitDepth), nullString(a.AudioCodec), a.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update assignment quality: %w", err)
+	}
+	return nil
+}
+
 // Deactivate deactivates an assignment
 func (r *AssignmentRepository) Deactivate(id int64) error {
 	result, err := r.db.Exec(
@@ -210,10 +337,23 @@ func (r *AssignmentRepository) Delete(id int64) error {
 	return nil
 }
 
-// ListDistinctTorrents returns all unique torrents that have active assignments
+// TotalActiveSize returns the summed declared file size of all active assignments
+func (r *AssignmentRepository) TotalActiveSize() (int64, error) {
+	var total int64
+	err := r.db.QueryRow(
+		`SELECT COALESCE(SUM(file_size), 0) FROM torrent_assignments WHERE is_active = TRUE`,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum assignment sizes: %w", err)
+	}
+	return total, nil
+}
+
+// ListDistinctTorrents returns all unique torrents that have active assignments.
+// Local files have no torrent and are left out.
 func (r *AssignmentRepository) ListDistinctTorrents() ([]string, error) {
 	rows, err := r.db.Query(
-		`SELECT DISTINCT info_hash FROM torrent_assignments WHERE is_active = TRUE`,
+		`SELECT DISTINCT info_hash FROM torrent_assignments WHERE is_active = TRUE AND local_path IS NULL`,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list torrents: %w", err)
@@ -232,6 +372,95 @@ func (r *AssignmentRepository) ListDistinctTorrents() ([]string, error) {
 	return hashes, rows.Err()
 }
 
+// AssignmentWithItem is an active assignment with the library item it plays
+type AssignmentWithItem struct {
+	*TorrentAssignment
+	Title         string // Movie or show title
+	ShowID        int64  // Episodes only
+	SeasonNumber  int    // Episodes only
+	EpisodeNumber int    // Episodes only
+}
+
+// selectAssignmentsWithItem selects assignments with their item's title and
+// episode position, for scanAssignmentsWithItem
+const selectAssignmentsWithItem = `SELECT ta.id, ta.item_type, ta.item_id, ta.info_hash, ta.magnet_uri, ta.file_path, ta.file_size, ta.resolution, ta.source, ta.bit_depth, ta.part_paths, ta.is_active, ta.created_at, ta.bitrate_bps, ta.runtime_seconds, ta.codec, ta.hdr,
+		        ta.confidence, ta.pattern_used, ta.season_from_folder, ta.needs_review, ta.local_path, ta.audio_codec,
+		        COALESCE(m.title, sh.title, ''), COALESCE(sh.id, 0), COALESCE(se.season_number, 0), COALESCE(e.episode_number, 0)
+		 FROM torrent_assignments ta
+		 LEFT JOIN movies m ON ta.item_type = 'movie' AND m.id = ta.item_id
+		 LEFT JOIN episodes e ON ta.item_type = 'episode' AND e.id = ta.item_id
+		 LEFT JOIN seasons se ON se.id = e.season_id
+		 LEFT JOIN shows sh ON sh.id = se.show_id`
+
+// ListActive returns active assignments across the library, newest first.
+// With needsReviewOnly, only matches the identifier flagged as uncertain.
+func (r *AssignmentRepository) ListActive(needsReviewOnly bool) ([]*AssignmentWithItem, error) {
+	rows, err := r.db.Query(
+		selectAssignmentsWithItem+`
+		 WHERE ta.is_active = TRUE AND (ta.needs_review OR NOT $1)
+		 ORDER BY ta.created_at DESC, ta.id DESC`,
+		needsReviewOnly,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAssignmentsWithItem(rows)
+}
+
+// ListSuspectedDuplicates returns active torrent assignments whose file size
+// and resolution match an active assignment from a different torrent, grouped
+// by size and resolution, oldest first within a group. The newer torrents of
+// a group most likely hold the same file again.
+func (r *AssignmentRepository) ListSuspectedDuplicates() ([][]*AssignmentWithItem, error) {
+	rows, err := r.db.Query(
+		selectAssignmentsWithItem + `
+		 WHERE ta.is_active = TRUE AND ta.local_path IS NULL
+		   AND (ta.file_size, COALESCE(ta.resolution, '')) IN (
+		       SELECT file_size, COALESCE(resolution, '') FROM torrent_assignments
+		       WHERE is_active = TRUE AND local_path IS NULL
+		       GROUP BY file_size, COALESCE(resolution, '')
+		       HAVING COUNT(DISTINCT info_hash) > 1)
+		 ORDER BY ta.file_size DESC, COALESCE(ta.resolution, ''), ta.created_at, ta.id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments, err := scanAssignmentsWithItem(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows arrive sorted by group
+	var groups [][]*AssignmentWithItem
+	for i, a := range assignments {
+		if i == 0 || a.FileSize != assignments[i-1].FileSize || a.Resolution != assignments[i-1].Resolution {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], a)
+	}
+	return groups, nil
+}
+
+// scanAssignmentsWithItem scans rows selected with selectAssignmentsWithItem
+func scanAssignmentsWithItem(rows *sql.Rows) ([]*AssignmentWithItem, error) {
+	var assignments []*AssignmentWithItem
+	for rows.Next() {
+		item := &AssignmentWithItem{}
+		var err error
+		item.TorrentAssignment, err = scanAssignment(rows, &item.Title, &item.ShowID, &item.SeasonNumber, &item.EpisodeNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, item)
+	}
+
+	return assignments, rows.Err()
+}
+
 // DeleteByInfoHash removes all assignments using a specific torrent
 func (r *AssignmentRepository) DeleteByInfoHash(infoHash string) (int64, error) {
 	result, err := r.db.Exec(`DELETE FROM torrent_assignments WHERE info_hash = $1`, infoHash)
@@ -252,3 +481,29 @@ func nullString(s string) sql.NullString {
 	}
 	return sql.NullString{String: s, Valid: true}
 }
+
+// encodePartPaths stores multi-part paths as a JSON array; NULL for a single file
+func encodePartPaths(paths []string) sql.NullString {
+	if len(paths) < 2 {
+		return sql.NullString{}
+	}
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
+
+// decodePartPaths reverses encodePartPaths. A corrupt value degrades to the
+// first part only, which is still playable.
+func decodePartPaths(ns sql.NullString) []string {
+	if !ns.Valid {
+		return nil
+	}
+	var paths []string
+	if err := json.Unmarshal([]byte(ns.String), &paths); err != nil {
+		slog.Warn("Ignoring invalid assignment part paths", "value", ns.String, "error", err)
+		return nil
+	}
+	return paths
+}
@@ -0,0 +1,84 @@
+package library
+
+import (
+	"bytes"
+	"log/slog"
+	mathrand "math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestListSuspectedDuplicates(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewAssignmentRepository(db)
+
+	// Item IDs far outside the library and a random, unusual size keep the rows
+	// apart from real data; assignments have no foreign keys
+	size := 1_000_000_000 + mathrand.Int64N(1_000_000_000)
+	create := func(fileSize int64) *TorrentAssignment {
+		t.Helper()
+		return createTestAssignment(t, db, &TorrentAssignment{
+			ItemType:   ItemTypeEpisode,
+			ItemID:     testItemID(),
+			FilePath:   "Show.S01E01.1080p.mkv",
+			FileSize:   fileSize,
+			Resolution: "1080p",
+		})
+	}
+	first := create(size)
+	second := create(size) // Same file in another torrent
+	create(size + 1)       // Different file
+
+	groups, err := repo.ListSuspectedDuplicates()
+	if err != nil {
+		t.Fatalf("ListSuspectedDuplicates: %v", err)
+	}
+
+	var found []*AssignmentWithItem
+	for _, g := range groups {
+		if g[0].FileSize == size {
+			found = g
+		}
+		if g[0].FileSize == size+1 {
+			t.Errorf("group for unique file size reported: %+v", g)
+		}
+	}
+	if len(found) != 2 || found[0].InfoHash != first.InfoHash || found[1].InfoHash != second.InfoHash {
+		t.Fatalf("duplicate group = %+v, want assignments from %s and %s", found, first.InfoHash, second.InfoHash)
+	}
+}
+
+func TestCreateWarnsAboutDuplicatesOfOtherItemsOnly(t *testing.T) {
+	db := openTestDB(t)
+
+	size := 1_000_000_000 + mathrand.Int64N(1_000_000_000)
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	create := func(itemID int64) *TorrentAssignment {
+		t.Helper()
+		return createTestAssignment(t, db, &TorrentAssignment{
+			ItemType:   ItemTypeEpisode,
+			ItemID:     itemID,
+			FilePath:   "Show.S01E01.1080p.mkv",
+			FileSize:   size,
+			Resolution: "1080p",
+		})
+	}
+	const duplicateMsg = "duplicate of another torrent"
+
+	// Replacing an episode's torrent with another holding the same file
+	episodeID := testItemID()
+	create(episodeID)
+	replacement := create(episodeID)
+	if strings.Contains(logs.String(), duplicateMsg) {
+		t.Errorf("replacing the item's own assignment logged a duplicate:\n%s", logs.String())
+	}
+
+	// The same file assigned to another episode still warns
+	create(testItemID())
+	if !strings.Contains(logs.String(), duplicateMsg) || !strings.Contains(logs.String(), "existing_info_hash="+replacement.InfoHash) {
+		t.Errorf("duplicate of another item's file not logged:\n%s", logs.String())
+	}
+}
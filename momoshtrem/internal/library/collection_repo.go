@@ -0,0 +1,206 @@
+package library
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Collection is a user-defined group of movies and shows (e.g. "Marvel")
+type Collection struct {
+	ID        int64
+	Name      string
+	ItemCount int
+	CreatedAt time.Time
+}
+
+// CollectionItem is a movie or show that belongs to a collection
+type CollectionItem struct {
+	CollectionID int64
+	ItemType     ItemType
+	ItemID       int64
+	AddedAt      time.Time
+}
+
+// CollectionMembership pairs a collection name with one of its items (used to build the VFS)
+type CollectionMembership struct {
+	CollectionName string
+	ItemType       ItemType
+	ItemID         int64
+}
+
+// CollectionRepository handles collection database operations
+type CollectionRepository struct {
+	db *DB
+}
+
+// NewCollectionRepository creates a new collection repository
+func NewCollectionRepository(db *DB) *CollectionRepository {
+	return &CollectionRepository{db: db}
+}
+
+// Create inserts a new collection. Returns ErrCollectionExists if the name is taken.
+func (r *CollectionRepository) Create(collection *Collection) error {
+	err := r.db.QueryRow(
+		`INSERT INTO collections (name) VALUES ($1) ON CONFLICT (name) DO NOTHING RETURNING id, created_at`,
+		collection.Name,
+	).Scan(&collection.ID, &collection.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrCollectionExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a collection by its ID
+func (r *CollectionRepository) GetByID(id int64) (*Collection, error) {
+	collection := &Collection{}
+	err := r.db.QueryRow(`
+		SELECT c.id, c.name, c.created_at, COUNT(ci.item_id)
+		FROM collections c
+		LEFT JOIN collection_items ci ON ci.collection_id = c.id
+		WHERE c.id = $1
+		GROUP BY c.id
+	`, id).Scan(&collection.ID, &collection.Name, &collection.CreatedAt, &collection.ItemCount)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return collection, nil
+}
+
+// List returns all collections ordered by name, with their item counts
+func (r *CollectionRepository) List() ([]*Collection, error) {
+	rows, err := r.db.Query(`
+		SELECT c.id, c.name, c.created_at, COUNT(ci.item_id)
+		FROM collections c
+		LEFT JOIN collection_items ci ON ci.collection_id = c.id
+		GROUP BY c.id
+		ORDER BY c.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []*Collection
+	for rows.Next() {
+		c := &Collection{}
+		if err := rows.Scan(&c.ID, &c.Name, &c.CreatedAt, &c.ItemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan collection: %w", err)
+		}
+		collections = append(collections, c)
+	}
+
+	return collections, rows.Err()
+}
+
+// Delete removes a collection and its item links. Returns false if it did not exist.
+func (r *CollectionRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM collections WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// AddItem adds a movie or show to a collection. Adding an existing member is a no-op.
+func (r *CollectionRepository) AddItem(collectionID int64, itemType ItemType, itemID int64) error {
+	_, err := r.db.Exec(`
+		INSERT INTO collection_items (collection_id, item_type, item_id) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, collectionID, itemType, itemID)
+	if err != nil {
+		return fmt.Errorf("failed to add collection item: %w", err)
+	}
+	return nil
+}
+
+// RemoveItem removes a movie or show from a collection. Returns false if it wasn't a member.
+func (r *CollectionRepository) RemoveItem(collectionID int64, itemType ItemType, itemID int64) (bool, error) {
+	result, err := r.db.Exec(
+		`DELETE FROM collection_items WHERE collection_id = $1 AND item_type = $2 AND item_id = $3`,
+		collectionID, itemType, itemID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove collection item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetItems returns the members of a collection in the order they were added
+func (r *CollectionRepository) GetItems(collectionID int64) ([]CollectionItem, error) {
+	rows, err := r.db.Query(`
+		SELECT collection_id, item_type, item_id, added_at
+		FROM collection_items
+		WHERE collection_id = $1
+		ORDER BY added_at, item_type, item_id
+	`, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []CollectionItem
+	for rows.Next() {
+		var item CollectionItem
+		if err := rows.Scan(&item.CollectionID, &item.ItemType, &item.ItemID, &item.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan collection item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ListMemberships returns every collection/item pair across all collections
+func (r *CollectionRepository) ListMemberships() ([]CollectionMembership, error) {
+	rows, err := r.db.Query(`
+		SELECT c.name, ci.item_type, ci.item_id
+		FROM collection_items ci
+		JOIN collections c ON c.id = ci.collection_id
+		ORDER BY c.name, ci.item_type, ci.item_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collection memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []CollectionMembership
+	for rows.Next() {
+		var m CollectionMembership
+		if err := rows.Scan(&m.CollectionName, &m.ItemType, &m.ItemID); err != nil {
+			return nil, fmt.Errorf("failed to scan collection membership: %w", err)
+		}
+		memberships = append(memberships, m)
+	}
+
+	return memberships, rows.Err()
+}
+
+// DeleteForItem removes an item from every collection (used when the item is deleted)
+func (r *CollectionRepository) DeleteForItem(itemType ItemType, itemID int64) error {
+	_, err := r.db.Exec(
+		`DELETE FROM collection_items WHERE item_type = $1 AND item_id = $2`,
+		itemType, itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection items: %w", err)
+	}
+	return nil
+}
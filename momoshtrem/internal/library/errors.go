@@ -6,7 +6,13 @@ import "errors"
 var (
 	ErrShowNotFound              = errors.New("show not found")
 	ErrMovieNotFound             = errors.New("movie not found")
+	ErrEpisodeNotFound           = errors.New("episode not found")
 	ErrInvalidMagnet             = errors.New("invalid magnet URI")
 	ErrTorrentServiceUnavailable = errors.New("torrent service not available")
 	ErrNoVideoFiles              = errors.New("no video files found in torrent")
+	ErrNoMatchingFile            = errors.New("no file in torrent matches the episode")
+	ErrCollectionExists          = errors.New("collection already exists")
+	ErrNoActiveAssignments       = errors.New("torrent has no active assignments")
+	ErrSameTorrent               = errors.New("replacement is the same torrent")
+	ErrNotVideoFile              = errors.New("file is not a video")
 )
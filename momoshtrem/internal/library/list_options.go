@@ -0,0 +1,72 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Sort fields accepted by list queries
+const (
+	SortByTitle     = "title"
+	SortByYear      = "year"
+	SortByCreatedAt = "created_at"
+)
+
+// listSortColumns maps sort fields to columns; only these are interpolated into SQL
+var listSortColumns = map[string]string{
+	SortByTitle:     "title",
+	SortByYear:      "year",
+	SortByCreatedAt: "created_at",
+}
+
+// ListOptions controls paging and ordering of movie and show lists
+type ListOptions struct {
+	Limit  int    // Maximum rows (0 = all)
+	Offset int    // Rows to skip
+	Sort   string // SortByTitle (default), SortByYear or SortByCreatedAt
+	Desc   bool   // Descending order
+
+	// HasAssignment filters by assignment status (nil = no filter)
+	HasAssignment *bool
+}
+
+// ValidSortField reports whether a sort field is supported
+func ValidSortField(field string) bool {
+	_, ok := listSortColumns[field]
+	return ok
+}
+
+// where returns the WHERE clause for the assignment filter, given the SQL
+// conditions matching assigned and unassigned rows
+func (o ListOptions) where(assigned, unassigned string) string {
+	switch {
+	case o.HasAssignment == nil:
+		return ""
+	case *o.HasAssignment:
+		return " WHERE " + assigned
+	default:
+		return " WHERE " + unassigned
+	}
+}
+
+// clause returns the ORDER BY/LIMIT/OFFSET suffix for a list query.
+// Ties are broken by title and id so pages are stable.
+func (o ListOptions) clause() string {
+	column, ok := listSortColumns[o.Sort]
+	if !ok {
+		column = listSortColumns[SortByTitle]
+	}
+	direction := "ASC"
+	if o.Desc {
+		direction = "DESC"
+	}
+
+	clause := fmt.Sprintf(" ORDER BY %s %s, title, id", column, direction)
+	if o.Limit > 0 {
+		clause += " LIMIT " + strconv.Itoa(o.Limit)
+	}
+	if o.Offset > 0 {
+		clause += " OFFSET " + strconv.Itoa(o.Offset)
+	}
+	return clause
+}
@@ -0,0 +1,131 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemMetadata is a single key/value entry attached to a library item
+type ItemMetadata struct {
+	ItemType ItemType
+	ItemID   int64
+	Key      string
+	Value    string
+}
+
+// MetadataRepository handles per-item key/value metadata database operations
+type MetadataRepository struct {
+	db *DB
+}
+
+// NewMetadataRepository creates a new metadata repository
+func NewMetadataRepository(db *DB) *MetadataRepository {
+	return &MetadataRepository{db: db}
+}
+
+// GetForItem returns all metadata for an item as a key/value map
+func (r *MetadataRepository) GetForItem(itemType ItemType, itemID int64) (map[string]string, error) {
+	rows, err := r.db.Query(
+		`SELECT key, value FROM item_metadata WHERE item_type = $1 AND item_id = $2 ORDER BY key`,
+		itemType, itemID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item metadata: %w", err)
+	}
+	defer rows.Close()
+
+	metadata := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan item metadata: %w", err)
+		}
+		metadata[key] = value
+	}
+
+	return metadata, rows.Err()
+}
+
+// Set creates or updates a metadata key for an item
+func (r *MetadataRepository) Set(itemType ItemType, itemID int64, key, value string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO item_metadata (item_type, item_id, key, value, updated_at) VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT(item_type, item_id, key) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+	`, itemType, itemID, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to set item metadata: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a metadata key from an item. Returns false if the key did not exist.
+func (r *MetadataRepository) Delete(itemType ItemType, itemID int64, key string) (bool, error) {
+	result, err := r.db.Exec(
+		`DELETE FROM item_metadata WHERE item_type = $1 AND item_id = $2 AND key = $3`,
+		itemType, itemID, key,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete item metadata: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteForItem removes all metadata for an item (used when the item is deleted)
+func (r *MetadataRepository) DeleteForItem(itemType ItemType, itemID int64) error {
+	_, err := r.db.Exec(
+		`DELETE FROM item_metadata WHERE item_type = $1 AND item_id = $2`,
+		itemType, itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete item metadata: %w", err)
+	}
+	return nil
+}
+
+// ListByKey returns all metadata entries with the given key, optionally filtered by value
+func (r *MetadataRepository) ListByKey(key, value string) ([]ItemMetadata, error) {
+	query := `SELECT item_type, item_id, key, value FROM item_metadata WHERE key = $1`
+	args := []interface{}{key}
+	if value != "" {
+		query += ` AND value = $2`
+		args = append(args, value)
+	}
+	query += ` ORDER BY item_type, item_id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list item metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []ItemMetadata
+	for rows.Next() {
+		var m ItemMetadata
+		if err := rows.Scan(&m.ItemType, &m.ItemID, &m.Key, &m.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan item metadata: %w", err)
+		}
+		entries = append(entries, m)
+	}
+
+	return entries, rows.Err()
+}
+
+// SplitTags splits a comma-separated metadata value into trimmed, non-empty labels
+func SplitTags(value string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,4 @@
+-- Content hash for deduplicating identical subtitles across sources
+
+ALTER TABLE subtitles ADD COLUMN IF NOT EXISTS content_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_subtitles_content_hash ON subtitles(item_type, item_id, content_hash);
@@ -0,0 +1,4 @@
+-- TMDB overview (plot) for movies and shows, used to render .nfo files
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS overview TEXT NOT NULL DEFAULT '';
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS overview TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Free-form key/value metadata attached to library items (tags, labels, notes)
+
+CREATE TABLE IF NOT EXISTS item_metadata (
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'show', 'episode')),
+    item_id BIGINT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY(item_type, item_id, key)
+);
+CREATE INDEX IF NOT EXISTS idx_item_metadata_key ON item_metadata(key, value);
@@ -0,0 +1,3 @@
+-- Timing offset applied to subtitle cues when served through the VFS
+
+ALTER TABLE subtitles ADD COLUMN IF NOT EXISTS offset_ms INTEGER NOT NULL DEFAULT 0;
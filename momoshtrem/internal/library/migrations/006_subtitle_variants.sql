@@ -0,0 +1,8 @@
+-- Forced and SDH subtitle variants, stored alongside the regular track for a language
+
+ALTER TABLE subtitles ADD COLUMN IF NOT EXISTS forced BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE subtitles ADD COLUMN IF NOT EXISTS hearing_impaired BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE subtitles DROP CONSTRAINT IF EXISTS subtitles_item_type_item_id_language_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subtitles_item_variant
+    ON subtitles(item_type, item_id, language_code, forced, hearing_impaired);
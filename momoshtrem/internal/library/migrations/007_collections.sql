@@ -0,0 +1,16 @@
+-- User-defined collections (playlists) of movies and shows, exposed as /Collections in the VFS
+
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS collection_items (
+    collection_id BIGINT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'show')),
+    item_id BIGINT NOT NULL,
+    added_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY(collection_id, item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_collection_items_item ON collection_items(item_type, item_id);
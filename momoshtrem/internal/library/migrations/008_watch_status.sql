@@ -0,0 +1,12 @@
+-- Playback progress per movie/episode for "continue watching"
+
+CREATE TABLE IF NOT EXISTS watch_status (
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id BIGINT NOT NULL,
+    position_seconds INTEGER NOT NULL DEFAULT 0,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    watched BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY(item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_watch_status_updated ON watch_status(updated_at DESC);
@@ -0,0 +1,6 @@
+-- TMDB artwork URLs for movies and shows, served as poster.jpg/fanart.jpg in the VFS
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS poster_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS backdrop_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS poster_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE shows ADD COLUMN IF NOT EXISTS backdrop_url TEXT NOT NULL DEFAULT '';
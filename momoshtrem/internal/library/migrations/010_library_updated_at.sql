@@ -0,0 +1,29 @@
+-- Tracks when anything shown in the VFS last changed, so a persisted VFS tree
+-- cache can tell whether it is stale. Stored as sync_metadata 'library_updated_at'.
+
+CREATE OR REPLACE FUNCTION touch_library_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_metadata (key, value, updated_at) VALUES ('library_updated_at', '', NOW())
+    ON CONFLICT(key) DO UPDATE SET updated_at = NOW();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['movies', 'shows', 'seasons', 'episodes', 'torrent_assignments',
+                             'subtitles', 'item_metadata', 'collections', 'collection_items']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', t || '_library_updated_at', t);
+        EXECUTE format('CREATE TRIGGER %I AFTER INSERT OR UPDATE OR DELETE ON %I
+                        FOR EACH STATEMENT EXECUTE FUNCTION touch_library_updated_at()',
+                       t || '_library_updated_at', t);
+    END LOOP;
+END;
+$$;
+
+-- Existing libraries count as changed now
+INSERT INTO sync_metadata (key, value, updated_at) VALUES ('library_updated_at', '', NOW())
+ON CONFLICT(key) DO UPDATE SET updated_at = NOW();
@@ -0,0 +1,5 @@
+-- Estimated bitrate and runtime of an assigned file, probed from its container
+-- header after assignment. NULL until (or if) the probe succeeds.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS bitrate_bps BIGINT;
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS runtime_seconds INTEGER;
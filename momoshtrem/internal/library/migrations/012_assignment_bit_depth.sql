@@ -0,0 +1,4 @@
+-- Video bit depth parsed from the release name (8bit, 10bit, 12bit).
+-- NULL when the name doesn't say; it is never guessed.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS bit_depth TEXT;
@@ -0,0 +1,5 @@
+-- Ordered torrent paths of a movie split into parts (CD1, CD2, ...), as a
+-- JSON array. file_path is the first part and file_size the combined size.
+-- NULL for single-file assignments.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS part_paths TEXT;
@@ -0,0 +1,16 @@
+-- Torrents queued for episodes that haven't aired yet. On the air date the
+-- torrent is added (metadata resolved, header pieces fetched) but not assigned,
+-- so the first stream starts without a cold start.
+
+CREATE TABLE IF NOT EXISTS queued_torrents (
+    id BIGSERIAL PRIMARY KEY,
+    item_type TEXT NOT NULL CHECK(item_type IN ('movie', 'episode')),
+    item_id BIGINT NOT NULL,
+    info_hash TEXT NOT NULL,
+    magnet_uri TEXT NOT NULL,
+    air_date TEXT NOT NULL,
+    prewarmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(item_type, item_id)
+);
+CREATE INDEX IF NOT EXISTS idx_queued_torrents_due ON queued_torrents(air_date) WHERE prewarmed_at IS NULL;
@@ -0,0 +1,5 @@
+-- Video codec and HDR flag parsed from the release name. codec is NULL when
+-- the name doesn't say; hdr is only set when the name mentions HDR or Dolby Vision.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS codec TEXT;
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS hdr BOOLEAN NOT NULL DEFAULT FALSE;
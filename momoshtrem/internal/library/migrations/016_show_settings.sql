@@ -0,0 +1,14 @@
+-- Per-show preferences. preferred_subtitle_langs is a comma-separated list of
+-- ISO 639-1 codes, most preferred first; empty falls back to the global
+-- subtitles settings.
+
+CREATE TABLE IF NOT EXISTS show_settings (
+    show_id BIGINT PRIMARY KEY REFERENCES shows(id) ON DELETE CASCADE,
+    preferred_subtitle_langs TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- Preferred languages decide which subtitle the VFS marks as default
+DROP TRIGGER IF EXISTS show_settings_library_updated_at ON show_settings;
+CREATE TRIGGER show_settings_library_updated_at AFTER INSERT OR UPDATE OR DELETE ON show_settings
+    FOR EACH STATEMENT EXECUTE FUNCTION touch_library_updated_at();
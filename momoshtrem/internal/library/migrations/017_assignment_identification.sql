@@ -0,0 +1,9 @@
+-- How the identifier matched an episode assignment, for auditing mis-assignments.
+-- NULL/FALSE for movies and for assignments made before this was recorded.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS confidence TEXT;
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS pattern_used TEXT;
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS season_from_folder BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_assignments_needs_review ON torrent_assignments(created_at) WHERE needs_review AND is_active;
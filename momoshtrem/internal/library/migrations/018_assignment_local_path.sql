@@ -0,0 +1,4 @@
+-- Files on local disk assigned like torrent files. local_path is the absolute
+-- path the VFS reads from; info_hash and magnet_uri are empty for these rows.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS local_path TEXT;
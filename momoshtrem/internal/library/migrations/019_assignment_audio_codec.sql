@@ -0,0 +1,4 @@
+-- Audio codec parsed from the release name (AAC, AC3, E-AC3, DTS, TrueHD...).
+-- NULL when the name doesn't say.
+
+ALTER TABLE torrent_assignments ADD COLUMN IF NOT EXISTS audio_codec TEXT;
@@ -0,0 +1,7 @@
+-- When a subtitle's served content last changed (re-download or offset change).
+-- Existing rows start from their creation time.
+
+ALTER TABLE subtitles ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE subtitles SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE subtitles ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE subtitles ALTER COLUMN updated_at SET NOT NULL;
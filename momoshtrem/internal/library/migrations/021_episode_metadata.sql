@@ -0,0 +1,5 @@
+-- TMDB episode ID and overview (plot), used to render episode .nfo files.
+-- Existing episodes are filled in by the next show refresh.
+
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS tmdb_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE episodes ADD COLUMN IF NOT EXISTS overview TEXT NOT NULL DEFAULT '';
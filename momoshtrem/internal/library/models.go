@@ -12,6 +12,7 @@ type ItemType string
 const (
 	ItemTypeMovie   ItemType = "movie"
 	ItemTypeEpisode ItemType = "episode"
+	ItemTypeShow    ItemType = "show" // Only used for item metadata; shows are assigned per episode
 )
 
 // Movie represents a movie in the library
@@ -20,8 +21,12 @@ type Movie struct {
 	TMDBID    int
 	Title     string
 	Year      int
+	Overview  string // Plot summary from TMDB
 	CreatedAt time.Time
 
+	PosterURL   string // TMDB image CDN URL, empty if TMDB has no poster
+	BackdropURL string // TMDB image CDN URL, empty if TMDB has no backdrop
+
 	// Loaded on demand
 	Assignment *TorrentAssignment
 }
@@ -31,9 +36,13 @@ type Show struct {
 	ID        int64
 	TMDBID    int
 	Title     string
-	Year      int // First air year
+	Year      int    // First air year
+	Overview  string // Plot summary from TMDB
 	CreatedAt time.Time
 
+	PosterURL   string // TMDB image CDN URL, empty if TMDB has no poster
+	BackdropURL string // TMDB image CDN URL, empty if TMDB has no backdrop
+
 	// Loaded on demand
 	Seasons []Season
 }
@@ -55,6 +64,8 @@ type Episode struct {
 	EpisodeNumber int
 	Name          string
 	AirDate       *time.Time // When the episode aired (from TMDB)
+	TMDBID        int        // TMDB episode ID, 0 until the show is refreshed
+	Overview      string     // Plot summary from TMDB
 
 	// Loaded on demand
 	Assignment *TorrentAssignment
@@ -71,8 +82,35 @@ type TorrentAssignment struct {
 	FileSize   int64
 	Resolution string // Optional: 1080p, 4K, etc.
 	Source     string // Optional: BluRay, WEB-DL, etc.
+	BitDepth   string // Optional: 8bit, 10bit, 12bit
+	Codec      string // Optional: x264, x265, HEVC, AV1
+	HDR        bool   // Release name mentions HDR or Dolby Vision
+	AudioCodec string // Optional: AAC, AC3, E-AC3, DTS, TrueHD
 	IsActive   bool
 	CreatedAt  time.Time
+
+	// Ordered torrent paths of a movie split into parts (CD1, CD2, ...); nil
+	// for a single file. FilePath is then the first part and FileSize the total.
+	PartPaths []string
+
+	// Probed from the container header after assignment; nil if unknown
+	BitrateBps     *int64
+	RuntimeSeconds *int
+
+	// Absolute path of a file on local disk, read directly instead of through
+	// the torrent client. InfoHash and MagnetURI are empty when set.
+	LocalPath string
+
+	// How the identifier matched an episode; empty/false for movies
+	Confidence       string // high, medium, low
+	PatternUsed      string // Name of the filename pattern that matched
+	SeasonFromFolder bool   // Season came from the folder path, not the file name
+	NeedsReview      bool   // Identifier flagged the match as uncertain
+}
+
+// IsLocal reports whether the assignment plays a file on local disk
+func (a *TorrentAssignment) IsLocal() bool {
+	return a.LocalPath != ""
 }
 
 // VFSPath returns the virtual filesystem path for a movie
@@ -113,6 +151,14 @@ type RecentlyAiredEpisode struct {
 	HasAssignment bool
 }
 
+// SeasonCoverage summarizes how many of a season's episodes have active assignments
+type SeasonCoverage struct {
+	SeasonNumber    int
+	EpisodeCount    int
+	AssignedCount   int
+	MissingEpisodes []int // Episode numbers without an active assignment, ascending
+}
+
 // SanitizeFilename removes or replaces characters invalid in file paths
 func SanitizeFilename(name string) string {
 	// Replace problematic characters with safe alternatives
@@ -18,8 +18,8 @@ func NewMovieRepository(db *DB) *MovieRepository {
 // Create adds a new movie to the library
 func (r *MovieRepository) Create(movie *Movie) error {
 	err := r.db.QueryRow(
-		`INSERT INTO movies (tmdb_id, title, year) VALUES ($1, $2, $3) RETURNING id, created_at`,
-		movie.TMDBID, movie.Title, movie.Year,
+		`INSERT INTO movies (tmdb_id, title, year, overview, poster_url, backdrop_url) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		movie.TMDBID, movie.Title, movie.Year, movie.Overview, movie.PosterURL, movie.BackdropURL,
 	).Scan(&movie.ID, &movie.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create movie: %w", err)
@@ -31,9 +31,9 @@ func (r *MovieRepository) Create(movie *Movie) error {
 func (r *MovieRepository) GetByID(id int64) (*Movie, error) {
 	movie := &Movie{}
 	err := r.db.QueryRow(
-		`SELECT id, tmdb_id, title, year, created_at FROM movies WHERE id = $1`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM movies WHERE id = $1`,
 		id,
-	).Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.CreatedAt)
+	).Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.Overview, &movie.PosterURL, &movie.BackdropURL, &movie.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -49,9 +49,9 @@ func (r *MovieRepository) GetByID(id int64) (*Movie, error) {
 func (r *MovieRepository) GetByTMDBID(tmdbID int) (*Movie, error) {
 	movie := &Movie{}
 	err := r.db.QueryRow(
-		`SELECT id, tmdb_id, title, year, created_at FROM movies WHERE tmdb_id = $1`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM movies WHERE tmdb_id = $1`,
 		tmdbID,
-	).Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.CreatedAt)
+	).Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.Overview, &movie.PosterURL, &movie.BackdropURL, &movie.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -63,34 +63,61 @@ func (r *MovieRepository) GetByTMDBID(tmdbID int) (*Movie, error) {
 	return movie, nil
 }
 
-// List returns all movies in the library
-func (r *MovieRepository) List() ([]*Movie, error) {
+// Count returns the number of movies and how many of them have an active assignment
+func (r *MovieRepository) Count() (total, assigned int, err error) {
+	err = r.db.QueryRow(`
+		SELECT COUNT(*), COUNT(ta.item_id)
+		FROM movies m
+		LEFT JOIN (
+			SELECT DISTINCT item_id FROM torrent_assignments WHERE item_type = 'movie' AND is_active = TRUE
+		) ta ON ta.item_id = m.id
+	`).Scan(&total, &assigned)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count movies: %w", err)
+	}
+	return total, assigned, nil
+}
+
+// movieAssignedQuery selects the active assignment of the movie in the outer query
+const movieAssignedQuery = `(SELECT 1 FROM torrent_assignments ta
+	WHERE ta.item_type = 'movie' AND ta.item_id = movies.id AND ta.is_active = TRUE)`
+
+// List returns a page of movies in the library along with the total count of
+// movies matching the filter
+func (r *MovieRepository) List(opts ListOptions) ([]*Movie, int, error) {
+	where := opts.where("EXISTS "+movieAssignedQuery, "NOT EXISTS "+movieAssignedQuery)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM movies` + where).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count movies: %w", err)
+	}
+
 	rows, err := r.db.Query(
-		`SELECT id, tmdb_id, title, year, created_at FROM movies ORDER BY title`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM movies` + where + opts.clause(),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list movies: %w", err)
+		return nil, 0, fmt.Errorf("failed to list movies: %w", err)
 	}
 	defer rows.Close()
 
 	var movies []*Movie
 	for rows.Next() {
 		movie := &Movie{}
-		if err := rows.Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan movie: %w", err)
+		if err := rows.Scan(&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.Overview, &movie.PosterURL, &movie.BackdropURL, &movie.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan movie: %w", err)
 		}
 		movies = append(movies, movie)
 	}
 
-	return movies, rows.Err()
+	return movies, total, rows.Err()
 }
 
 // ListWithAssignments returns all movies that have active torrent assignments
 func (r *MovieRepository) ListWithAssignments() ([]*Movie, error) {
 	rows, err := r.db.Query(`
-		SELECT m.id, m.tmdb_id, m.title, m.year, m.created_at,
+		SELECT m.id, m.tmdb_id, m.title, m.year, m.overview, m.poster_url, m.backdrop_url, m.created_at,
 		       ta.id, ta.info_hash, ta.magnet_uri, ta.file_path, ta.file_size,
-		       ta.resolution, ta.source, ta.created_at
+		       ta.resolution, ta.source, ta.bit_depth, ta.part_paths, ta.codec, ta.hdr, ta.created_at, ta.local_path
 		FROM movies m
 		INNER JOIN torrent_assignments ta ON ta.item_type = 'movie' AND ta.item_id = m.id AND ta.is_active = TRUE
 		ORDER BY m.title
@@ -105,13 +132,13 @@ func (r *MovieRepository) ListWithAssignments() ([]*Movie, error) {
 		movie := &Movie{}
 		assignment := &TorrentAssignment{ItemType: ItemTypeMovie}
 
-		var resolution, source sql.NullString
+		var resolution, source, bitDepth, partPaths, codec, localPath sql.NullString
 
 		if err := rows.Scan(
-			&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.CreatedAt,
+			&movie.ID, &movie.TMDBID, &movie.Title, &movie.Year, &movie.Overview, &movie.PosterURL, &movie.BackdropURL, &movie.CreatedAt,
 			&assignment.ID, &assignment.InfoHash, &assignment.MagnetURI,
 			&assignment.FilePath, &assignment.FileSize,
-			&resolution, &source, &assignment.CreatedAt,
+			&resolution, &source, &bitDepth, &partPaths, &codec, &assignment.HDR, &assignment.CreatedAt, &localPath,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan movie: %w", err)
 		}
@@ -119,6 +146,10 @@ func (r *MovieRepository) ListWithAssignments() ([]*Movie, error) {
 		assignment.ItemID = movie.ID
 		assignment.Resolution = resolution.String
 		assignment.Source = source.String
+		assignment.BitDepth = bitDepth.String
+		assignment.Codec = codec.String
+		assignment.PartPaths = decodePartPaths(partPaths)
+		assignment.LocalPath = localPath.String
 		assignment.IsActive = true
 		movie.Assignment = assignment
 
@@ -149,11 +180,43 @@ func (r *MovieRepository) Delete(id int64) error {
 // Update updates a movie's metadata
 func (r *MovieRepository) Update(movie *Movie) error {
 	_, err := r.db.Exec(
-		`UPDATE movies SET title = $1, year = $2 WHERE id = $3`,
-		movie.Title, movie.Year, movie.ID,
+		`UPDATE movies SET title = $1, year = $2, overview = $3, poster_url = $4, backdrop_url = $5 WHERE id = $6`,
+		movie.Title, movie.Year, movie.Overview, movie.PosterURL, movie.BackdropURL, movie.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update movie: %w", err)
 	}
 	return nil
 }
+
+// ListMissingArtwork returns movies with neither a poster nor a backdrop URL,
+// such as those added before artwork was stored. Only ID, TMDBID and Title are set.
+func (r *MovieRepository) ListMissingArtwork() ([]*Movie, error) {
+	rows, err := r.db.Query(`SELECT id, tmdb_id, title FROM movies WHERE poster_url = '' AND backdrop_url = '' ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list movies without artwork: %w", err)
+	}
+	defer rows.Close()
+
+	var movies []*Movie
+	for rows.Next() {
+		movie := &Movie{}
+		if err := rows.Scan(&movie.ID, &movie.TMDBID, &movie.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan movie: %w", err)
+		}
+		movies = append(movies, movie)
+	}
+	return movies, rows.Err()
+}
+
+// UpdateArtwork sets a movie's poster and backdrop URLs
+func (r *MovieRepository) UpdateArtwork(id int64, posterURL, backdropURL string) error {
+	_, err := r.db.Exec(
+		`UPDATE movies SET poster_url = $1, backdrop_url = $2 WHERE id = $3`,
+		posterURL, backdropURL, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update movie artwork: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package library
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueuedTorrent is a torrent to pre-warm when its item airs
+type QueuedTorrent struct {
+	ID          int64
+	ItemType    ItemType
+	ItemID      int64
+	InfoHash    string
+	MagnetURI   string
+	AirDate     string     // YYYY-MM-DD
+	PrewarmedAt *time.Time // Nil until the torrent has been added
+	CreatedAt   time.Time
+}
+
+// QueuedTorrentRepository handles queued torrent database operations
+type QueuedTorrentRepository struct {
+	db *DB
+}
+
+// NewQueuedTorrentRepository creates a new queued torrent repository
+func NewQueuedTorrentRepository(db *DB) *QueuedTorrentRepository {
+	return &QueuedTorrentRepository{db: db}
+}
+
+// Upsert queues a torrent for an item, replacing any earlier one.
+// Replacing resets the pre-warm so the new torrent is added too.
+func (r *QueuedTorrentRepository) Upsert(q *QueuedTorrent) error {
+	err := r.db.QueryRow(`
+		INSERT INTO queued_torrents (item_type, item_id, info_hash, magnet_uri, air_date)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(item_type, item_id) DO UPDATE SET
+			info_hash = EXCLUDED.info_hash,
+			magnet_uri = EXCLUDED.magnet_uri,
+			air_date = EXCLUDED.air_date,
+			prewarmed_at = NULL,
+			created_at = NOW()
+		RETURNING id, created_at
+	`, q.ItemType, q.ItemID, q.InfoHash, q.MagnetURI, q.AirDate).Scan(&q.ID, &q.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to queue torrent: %w", err)
+	}
+	q.PrewarmedAt = nil
+	return nil
+}
+
+// ListDue returns the torrents not yet pre-warmed whose air date falls
+// between from and to (YYYY-MM-DD, inclusive), earliest first
+func (r *QueuedTorrentRepository) ListDue(from, to string) ([]*QueuedTorrent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, item_type, item_id, info_hash, magnet_uri, air_date, created_at
+		FROM queued_torrents
+		WHERE prewarmed_at IS NULL AND air_date >= $1 AND air_date <= $2
+		ORDER BY air_date, id
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due torrents: %w", err)
+	}
+	defer rows.Close()
+
+	var queued []*QueuedTorrent
+	for rows.Next() {
+		q := &QueuedTorrent{}
+		if err := rows.Scan(&q.ID, &q.ItemType, &q.ItemID, &q.InfoHash, &q.MagnetURI, &q.AirDate, &q.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan queued torrent: %w", err)
+		}
+		queued = append(queued, q)
+	}
+
+	return queued, rows.Err()
+}
+
+// MarkPrewarmed records that a queued torrent has been added
+func (r *QueuedTorrentRepository) MarkPrewarmed(id int64) error {
+	if _, err := r.db.Exec(`UPDATE queued_torrents SET prewarmed_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark torrent pre-warmed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package library
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQueuedTorrentListDue(t *testing.T) {
+	db := openTestDB(t)
+	queue := NewQueuedTorrentRepository(db)
+
+	// queued_torrents has no foreign keys, so IDs far outside the library are safe to use
+	day := func(offset int) string { return time.Now().AddDate(0, 0, offset).Format("2006-01-02") }
+	entries := []*QueuedTorrent{
+		{ItemID: testItemID(), AirDate: day(0)},
+		{ItemID: testItemID(), AirDate: day(-1)},
+		{ItemID: testItemID(), AirDate: day(2)},
+		{ItemID: testItemID(), AirDate: day(-4)},
+	}
+	var ours []int64
+	for _, q := range entries {
+		q.ItemType = ItemTypeEpisode
+		q.InfoHash = testInfoHash()
+		q.MagnetURI = "magnet:?xt=urn:btih:" + q.InfoHash
+		if err := queue.Upsert(q); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+		ours = append(ours, q.ID)
+	}
+	t.Cleanup(func() {
+		for _, id := range ours {
+			db.Exec(`DELETE FROM queued_torrents WHERE id = $1`, id)
+		}
+	})
+
+	due := func() []int64 {
+		t.Helper()
+		list, err := queue.ListDue(day(-3), day(0))
+		if err != nil {
+			t.Fatalf("ListDue: %v", err)
+		}
+		var ids []int64
+		for _, q := range list {
+			if slices.Contains(ours, q.ID) {
+				ids = append(ids, q.ID)
+			}
+		}
+		return ids
+	}
+
+	// Earliest air date first; future and older entries are left out
+	if got, want := due(), []int64{entries[1].ID, entries[0].ID}; !slices.Equal(got, want) {
+		t.Errorf("due = %v, want %v", got, want)
+	}
+
+	if err := queue.MarkPrewarmed(entries[1].ID); err != nil {
+		t.Fatalf("MarkPrewarmed: %v", err)
+	}
+	if got, want := due(), []int64{entries[0].ID}; !slices.Equal(got, want) {
+		t.Errorf("due after pre-warming = %v, want %v", got, want)
+	}
+
+	// Queuing another torrent for the item resets the pre-warm
+	entries[1].InfoHash = testInfoHash()
+	if err := queue.Upsert(entries[1]); err != nil {
+		t.Fatalf("Upsert replacement: %v", err)
+	}
+	if got, want := due(), []int64{entries[1].ID, entries[0].ID}; !slices.Equal(got, want) {
+		t.Errorf("due after replacing = %v, want %v", got, want)
+	}
+}
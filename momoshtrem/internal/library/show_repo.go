@@ -19,8 +19,8 @@ func NewShowRepository(db *DB) *ShowRepository {
 // Create adds a new show to the library
 func (r *ShowRepository) Create(show *Show) error {
 	err := r.db.QueryRow(
-		`INSERT INTO shows (tmdb_id, title, year) VALUES ($1, $2, $3) RETURNING id, created_at`,
-		show.TMDBID, show.Title, show.Year,
+		`INSERT INTO shows (tmdb_id, title, year, overview, poster_url, backdrop_url) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		show.TMDBID, show.Title, show.Year, show.Overview, show.PosterURL, show.BackdropURL,
 	).Scan(&show.ID, &show.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create show: %w", err)
@@ -32,9 +32,9 @@ func (r *ShowRepository) Create(show *Show) error {
 func (r *ShowRepository) GetByID(id int64) (*Show, error) {
 	show := &Show{}
 	err := r.db.QueryRow(
-		`SELECT id, tmdb_id, title, year, created_at FROM shows WHERE id = $1`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM shows WHERE id = $1`,
 		id,
-	).Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.CreatedAt)
+	).Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.Overview, &show.PosterURL, &show.BackdropURL, &show.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -50,9 +50,9 @@ func (r *ShowRepository) GetByID(id int64) (*Show, error) {
 func (r *ShowRepository) GetByTMDBID(tmdbID int) (*Show, error) {
 	show := &Show{}
 	err := r.db.QueryRow(
-		`SELECT id, tmdb_id, title, year, created_at FROM shows WHERE tmdb_id = $1`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM shows WHERE tmdb_id = $1`,
 		tmdbID,
-	).Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.CreatedAt)
+	).Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.Overview, &show.PosterURL, &show.BackdropURL, &show.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -64,28 +64,112 @@ func (r *ShowRepository) GetByTMDBID(tmdbID int) (*Show, error) {
 	return show, nil
 }
 
-// List returns all shows in the library
-func (r *ShowRepository) List() ([]*Show, error) {
+// ShowCounts are library-wide show and episode totals
+type ShowCounts struct {
+	Shows            int
+	AssignedShows    int // Shows with at least one assigned episode
+	Episodes         int
+	AssignedEpisodes int
+}
+
+// Count returns show and episode totals, including how many have active assignments
+func (r *ShowRepository) Count() (*ShowCounts, error) {
+	counts := &ShowCounts{}
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM shows),
+			(SELECT COUNT(DISTINCT sn.show_id)
+			 FROM seasons sn
+			 INNER JOIN episodes e ON e.season_id = sn.id
+			 INNER JOIN torrent_assignments ta ON ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE),
+			(SELECT COUNT(*) FROM episodes),
+			(SELECT COUNT(DISTINCT e.id)
+			 FROM episodes e
+			 INNER JOIN torrent_assignments ta ON ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE)
+	`).Scan(&counts.Shows, &counts.AssignedShows, &counts.Episodes, &counts.AssignedEpisodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count shows: %w", err)
+	}
+	return counts, nil
+}
+
+// Assignment filter conditions for shows: a show counts as assigned when any
+// episode has an active assignment and as unassigned when any episode lacks one,
+// so partially assigned shows match both.
+const (
+	showAssignedCondition = `EXISTS (SELECT 1 FROM seasons sn
+		INNER JOIN episodes e ON e.season_id = sn.id
+		INNER JOIN torrent_assignments ta ON ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE
+		WHERE sn.show_id = shows.id)`
+	showUnassignedCondition = `EXISTS (SELECT 1 FROM seasons sn
+		INNER JOIN episodes e ON e.season_id = sn.id
+		WHERE sn.show_id = shows.id AND NOT EXISTS (
+			SELECT 1 FROM torrent_assignments ta
+			WHERE ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE))`
+)
+
+// List returns a page of shows in the library along with the total count of
+// shows matching the filter
+func (r *ShowRepository) List(opts ListOptions) ([]*Show, int, error) {
+	where := opts.where(showAssignedCondition, showUnassignedCondition)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM shows` + where).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count shows: %w", err)
+	}
+
 	rows, err := r.db.Query(
-		`SELECT id, tmdb_id, title, year, created_at FROM shows ORDER BY title`,
+		`SELECT id, tmdb_id, title, year, overview, poster_url, backdrop_url, created_at FROM shows` + where + opts.clause(),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list shows: %w", err)
+		return nil, 0, fmt.Errorf("failed to list shows: %w", err)
 	}
 	defer rows.Close()
 
 	var shows []*Show
 	for rows.Next() {
 		show := &Show{}
-		if err := rows.Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan show: %w", err)
+		if err := rows.Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.Overview, &show.PosterURL, &show.BackdropURL, &show.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan show: %w", err)
 		}
 		shows = append(shows, show)
 	}
 
+	return shows, total, rows.Err()
+}
+
+// ListMissingArtwork returns shows with neither a poster nor a backdrop URL,
+// such as those added before artwork was stored. Only ID, TMDBID and Title are set.
+func (r *ShowRepository) ListMissingArtwork() ([]*Show, error) {
+	rows, err := r.db.Query(`SELECT id, tmdb_id, title FROM shows WHERE poster_url = '' AND backdrop_url = '' ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shows without artwork: %w", err)
+	}
+	defer rows.Close()
+
+	var shows []*Show
+	for rows.Next() {
+		show := &Show{}
+		if err := rows.Scan(&show.ID, &show.TMDBID, &show.Title); err != nil {
+			return nil, fmt.Errorf("failed to scan show: %w", err)
+		}
+		shows = append(shows, show)
+	}
 	return shows, rows.Err()
 }
 
+// UpdateArtwork sets a show's poster and backdrop URLs
+func (r *ShowRepository) UpdateArtwork(id int64, posterURL, backdropURL string) error {
+	_, err := r.db.Exec(
+		`UPDATE shows SET poster_url = $1, backdrop_url = $2 WHERE id = $3`,
+		posterURL, backdropURL, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update show artwork: %w", err)
+	}
+	return nil
+}
+
 // GetWithSeasons retrieves a show with all its seasons
 func (r *ShowRepository) GetWithSeasons(id int64) (*Show, error) {
 	show, err := r.GetByID(id)
@@ -172,6 +256,27 @@ func (r *ShowRepository) GetSeason(showID int64, seasonNumber int) (*Season, err
 	return season, nil
 }
 
+// DeleteSeason removes a season from a show (cascades to episodes)
+func (r *ShowRepository) DeleteSeason(showID int64, seasonNumber int) error {
+	result, err := r.db.Exec(
+		`DELETE FROM seasons WHERE show_id = $1 AND season_number = $2`,
+		showID, seasonNumber,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete season: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check delete result: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("season not found")
+	}
+
+	return nil
+}
+
 // GetSeasonByID retrieves a season by its ID
 func (r *ShowRepository) GetSeasonByID(id int64) (*Season, error) {
 	season := &Season{}
@@ -218,10 +323,11 @@ func (r *ShowRepository) GetSeasons(showID int64) ([]Season, error) {
 // CreateEpisode adds a new episode to a season
 func (r *ShowRepository) CreateEpisode(episode *Episode) error {
 	err := r.db.QueryRow(
-		`INSERT INTO episodes (season_id, episode_number, name) VALUES ($1, $2, $3)
-		 ON CONFLICT(season_id, episode_number) DO UPDATE SET name = EXCLUDED.name
+		`INSERT INTO episodes (season_id, episode_number, name, tmdb_id, overview) VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT(season_id, episode_number) DO UPDATE
+		 SET name = EXCLUDED.name, tmdb_id = EXCLUDED.tmdb_id, overview = EXCLUDED.overview
 		 RETURNING id`,
-		episode.SeasonID, episode.EpisodeNumber, episode.Name,
+		episode.SeasonID, episode.EpisodeNumber, episode.Name, episode.TMDBID, episode.Overview,
 	).Scan(&episode.ID)
 	if err != nil {
 		return fmt.Errorf("failed to create episode: %w", err)
@@ -234,9 +340,9 @@ func (r *ShowRepository) GetEpisode(seasonID int64, episodeNumber int) (*Episode
 	episode := &Episode{}
 	var name sql.NullString
 	err := r.db.QueryRow(
-		`SELECT id, season_id, episode_number, name FROM episodes WHERE season_id = $1 AND episode_number = $2`,
+		`SELECT id, season_id, episode_number, name, tmdb_id, overview FROM episodes WHERE season_id = $1 AND episode_number = $2`,
 		seasonID, episodeNumber,
-	).Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name)
+	).Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name, &episode.TMDBID, &episode.Overview)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -252,11 +358,11 @@ func (r *ShowRepository) GetEpisode(seasonID int64, episodeNumber int) (*Episode
 // GetEpisodeByID retrieves an episode by its ID
 func (r *ShowRepository) GetEpisodeByID(id int64) (*Episode, error) {
 	episode := &Episode{}
-	var name sql.NullString
+	var name, airDate sql.NullString
 	err := r.db.QueryRow(
-		`SELECT id, season_id, episode_number, name FROM episodes WHERE id = $1`,
+		`SELECT id, season_id, episode_number, name, air_date, tmdb_id, overview FROM episodes WHERE id = $1`,
 		id,
-	).Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name)
+	).Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name, &airDate, &episode.TMDBID, &episode.Overview)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -265,6 +371,11 @@ func (r *ShowRepository) GetEpisodeByID(id int64) (*Episode, error) {
 		return nil, fmt.Errorf("failed to get episode: %w", err)
 	}
 	episode.Name = name.String
+	if airDate.Valid {
+		if t, err := time.Parse("2006-01-02", airDate.String); err == nil {
+			episode.AirDate = &t
+		}
+	}
 
 	return episode, nil
 }
@@ -272,7 +383,7 @@ func (r *ShowRepository) GetEpisodeByID(id int64) (*Episode, error) {
 // GetEpisodes retrieves all episodes for a season
 func (r *ShowRepository) GetEpisodes(seasonID int64) ([]Episode, error) {
 	rows, err := r.db.Query(
-		`SELECT id, season_id, episode_number, name FROM episodes WHERE season_id = $1 ORDER BY episode_number`,
+		`SELECT id, season_id, episode_number, name, air_date, tmdb_id, overview FROM episodes WHERE season_id = $1 ORDER BY episode_number`,
 		seasonID,
 	)
 	if err != nil {
@@ -283,11 +394,16 @@ func (r *ShowRepository) GetEpisodes(seasonID int64) ([]Episode, error) {
 	var episodes []Episode
 	for rows.Next() {
 		var episode Episode
-		var name sql.NullString
-		if err := rows.Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name); err != nil {
+		var name, airDate sql.NullString
+		if err := rows.Scan(&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name, &airDate, &episode.TMDBID, &episode.Overview); err != nil {
 			return nil, fmt.Errorf("failed to scan episode: %w", err)
 		}
 		episode.Name = name.String
+		if airDate.Valid {
+			if t, err := time.Parse("2006-01-02", airDate.String); err == nil {
+				episode.AirDate = &t
+			}
+		}
 		episodes = append(episodes, episode)
 	}
 
@@ -298,7 +414,7 @@ func (r *ShowRepository) GetEpisodes(seasonID int64) ([]Episode, error) {
 func (r *ShowRepository) GetShowsWithAssignedEpisodes() ([]*Show, error) {
 	// Get shows that have at least one episode with an assignment
 	rows, err := r.db.Query(`
-		SELECT DISTINCT s.id, s.tmdb_id, s.title, s.year, s.created_at
+		SELECT DISTINCT s.id, s.tmdb_id, s.title, s.year, s.overview, s.poster_url, s.backdrop_url, s.created_at
 		FROM shows s
 		INNER JOIN seasons sn ON sn.show_id = s.id
 		INNER JOIN episodes e ON e.season_id = sn.id
@@ -313,7 +429,7 @@ func (r *ShowRepository) GetShowsWithAssignedEpisodes() ([]*Show, error) {
 	var shows []*Show
 	for rows.Next() {
 		show := &Show{}
-		if err := rows.Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.CreatedAt); err != nil {
+		if err := rows.Scan(&show.ID, &show.TMDBID, &show.Title, &show.Year, &show.Overview, &show.PosterURL, &show.BackdropURL, &show.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan show: %w", err)
 		}
 
@@ -368,9 +484,9 @@ func (r *ShowRepository) GetSeasonsWithAssignedEpisodes(showID int64) ([]Season,
 // GetEpisodesWithAssignments returns all episodes for a season that have active torrent assignments
 func (r *ShowRepository) GetEpisodesWithAssignments(seasonID int64) ([]Episode, error) {
 	rows, err := r.db.Query(`
-		SELECT e.id, e.season_id, e.episode_number, e.name,
+		SELECT e.id, e.season_id, e.episode_number, e.name, e.air_date, e.tmdb_id, e.overview,
 		       ta.id, ta.info_hash, ta.magnet_uri, ta.file_path, ta.file_size,
-		       ta.resolution, ta.source, ta.created_at
+		       ta.resolution, ta.source, ta.bit_depth, ta.codec, ta.hdr, ta.created_at, ta.local_path
 		FROM episodes e
 		INNER JOIN torrent_assignments ta ON ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE
 		WHERE e.season_id = $1
@@ -384,22 +500,30 @@ func (r *ShowRepository) GetEpisodesWithAssignments(seasonID int64) ([]Episode,
 	var episodes []Episode
 	for rows.Next() {
 		var episode Episode
-		var name, resolution, source sql.NullString
+		var name, airDate, resolution, source, bitDepth, codec, localPath sql.NullString
 		assignment := &TorrentAssignment{ItemType: ItemTypeEpisode}
 
 		if err := rows.Scan(
-			&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name,
+			&episode.ID, &episode.SeasonID, &episode.EpisodeNumber, &name, &airDate, &episode.TMDBID, &episode.Overview,
 			&assignment.ID, &assignment.InfoHash, &assignment.MagnetURI,
 			&assignment.FilePath, &assignment.FileSize,
-			&resolution, &source, &assignment.CreatedAt,
+			&resolution, &source, &bitDepth, &codec, &assignment.HDR, &assignment.CreatedAt, &localPath,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan episode: %w", err)
 		}
 
 		episode.Name = name.String
+		if airDate.Valid {
+			if t, err := time.Parse("2006-01-02", airDate.String); err == nil {
+				episode.AirDate = &t
+			}
+		}
 		assignment.ItemID = episode.ID
 		assignment.Resolution = resolution.String
 		assignment.Source = source.String
+		assignment.BitDepth = bitDepth.String
+		assignment.Codec = codec.String
+		assignment.LocalPath = localPath.String
 		assignment.IsActive = true
 		episode.Assignment = assignment
 
@@ -470,10 +594,56 @@ func (r *ShowRepository) GetRecentlyAiredEpisodes(lookbackDays int) ([]RecentlyA
 	return episodes, rows.Err()
 }
 
+// GetCoverage returns per-season assignment coverage for a show, ordered by season number.
+// Seasons without episodes are included with zero counts.
+func (r *ShowRepository) GetCoverage(showID int64) ([]SeasonCoverage, error) {
+	rows, err := r.db.Query(`
+		SELECT sn.season_number, e.episode_number, COUNT(ta.id)
+		FROM seasons sn
+		LEFT JOIN episodes e ON e.season_id = sn.id
+		LEFT JOIN torrent_assignments ta ON ta.item_type = 'episode' AND ta.item_id = e.id AND ta.is_active = TRUE
+		WHERE sn.show_id = $1
+		GROUP BY sn.season_number, e.id, e.episode_number
+		ORDER BY sn.season_number, e.episode_number
+	`, showID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get show coverage: %w", err)
+	}
+	defer rows.Close()
+
+	var coverage []SeasonCoverage
+	for rows.Next() {
+		var seasonNumber, assignments int
+		var episodeNumber sql.NullInt64
+		if err := rows.Scan(&seasonNumber, &episodeNumber, &assignments); err != nil {
+			return nil, fmt.Errorf("failed to scan coverage row: %w", err)
+		}
+
+		if len(coverage) == 0 || coverage[len(coverage)-1].SeasonNumber != seasonNumber {
+			coverage = append(coverage, SeasonCoverage{SeasonNumber: seasonNumber, MissingEpisodes: []int{}})
+		}
+		if !episodeNumber.Valid {
+			continue // Season has no episodes
+		}
+
+		season := &coverage[len(coverage)-1]
+		season.EpisodeCount++
+		if assignments > 0 {
+			season.AssignedCount++
+		} else {
+			season.MissingEpisodes = append(season.MissingEpisodes, int(episodeNumber.Int64))
+		}
+	}
+
+	return coverage, rows.Err()
+}
+
 // EpisodeContext contains the full context needed for VFS tree operations
 type EpisodeContext struct {
 	ShowTitle     string
 	ShowYear      int
+	ShowTMDBID    int
+	ShowOverview  string
 	SeasonNumber  int
 	EpisodeNumber int
 }
@@ -482,12 +652,12 @@ type EpisodeContext struct {
 func (r *ShowRepository) GetEpisodeContext(episodeID int64) (*EpisodeContext, error) {
 	ctx := &EpisodeContext{}
 	err := r.db.QueryRow(`
-		SELECT s.title, s.year, sn.season_number, e.episode_number
+		SELECT s.title, s.year, s.tmdb_id, s.overview, sn.season_number, e.episode_number
 		FROM episodes e
 		INNER JOIN seasons sn ON sn.id = e.season_id
 		INNER JOIN shows s ON s.id = sn.show_id
 		WHERE e.id = $1
-	`, episodeID).Scan(&ctx.ShowTitle, &ctx.ShowYear, &ctx.SeasonNumber, &ctx.EpisodeNumber)
+	`, episodeID).Scan(&ctx.ShowTitle, &ctx.ShowYear, &ctx.ShowTMDBID, &ctx.ShowOverview, &ctx.SeasonNumber, &ctx.EpisodeNumber)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
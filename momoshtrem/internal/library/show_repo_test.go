@@ -0,0 +1,101 @@
+package library
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestShowRepositoryGetCoverage(t *testing.T) {
+	db := openTestDB(t)
+	shows := NewShowRepository(db)
+
+	// Season 1 has episodes 1-3 with episode 2 assigned; season 2 has no episodes yet
+	show := createTestShow(t, db, "Coverage Test", 3, 0)
+	createTestAssignment(t, db, &TorrentAssignment{
+		ItemType: ItemTypeEpisode,
+		ItemID:   show.Seasons[0].Episodes[1].ID,
+		FilePath: "Show.S01E02.mkv",
+	})
+
+	coverage, err := shows.GetCoverage(show.ID)
+	if err != nil {
+		t.Fatalf("GetCoverage: %v", err)
+	}
+	if len(coverage) != 2 {
+		t.Fatalf("got %d seasons, want 2", len(coverage))
+	}
+	if got := coverage[0]; got.SeasonNumber != 1 || got.EpisodeCount != 3 || got.AssignedCount != 1 || !slices.Equal(got.MissingEpisodes, []int{1, 3}) {
+		t.Errorf("season 1 coverage = %+v, want 1/3 assigned, missing [1 3]", got)
+	}
+	if got := coverage[1]; got.SeasonNumber != 2 || got.EpisodeCount != 0 || got.AssignedCount != 0 || len(got.MissingEpisodes) != 0 {
+		t.Errorf("season 2 coverage = %+v, want empty", got)
+	}
+}
+
+func TestShowRepositoryArtworkBackfill(t *testing.T) {
+	db := openTestDB(t)
+	shows := NewShowRepository(db)
+
+	show := createTestShow(t, db, "Artwork Test")
+
+	missing, err := shows.ListMissingArtwork()
+	if err != nil {
+		t.Fatalf("ListMissingArtwork: %v", err)
+	}
+	if !slices.ContainsFunc(missing, func(s *Show) bool { return s.ID == show.ID }) {
+		t.Fatal("show without artwork not listed")
+	}
+
+	if err := shows.UpdateArtwork(show.ID, "https://image.tmdb.org/p.jpg", ""); err != nil {
+		t.Fatalf("UpdateArtwork: %v", err)
+	}
+	got, err := shows.GetByID(show.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.PosterURL != "https://image.tmdb.org/p.jpg" {
+		t.Errorf("PosterURL = %q", got.PosterURL)
+	}
+	missing, err = shows.ListMissingArtwork()
+	if err != nil {
+		t.Fatalf("ListMissingArtwork: %v", err)
+	}
+	if slices.ContainsFunc(missing, func(s *Show) bool { return s.ID == show.ID }) {
+		t.Error("show with a poster still listed")
+	}
+}
+
+func TestGetRecentlyAiredEpisodesHasAssignment(t *testing.T) {
+	db := openTestDB(t)
+	shows := NewShowRepository(db)
+
+	// Two episodes aired yesterday; only the second is assigned
+	show := createTestShow(t, db, "Recently Aired Test", 2)
+	season := show.Seasons[0]
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	for _, ep := range season.Episodes {
+		if err := shows.UpdateEpisodeAirDate(season.ID, ep.EpisodeNumber, yesterday); err != nil {
+			t.Fatalf("UpdateEpisodeAirDate: %v", err)
+		}
+	}
+	createTestAssignment(t, db, &TorrentAssignment{
+		ItemType: ItemTypeEpisode,
+		ItemID:   season.Episodes[1].ID,
+		FilePath: "Show.S01E02.mkv",
+	})
+
+	recent, err := shows.GetRecentlyAiredEpisodes(7)
+	if err != nil {
+		t.Fatalf("GetRecentlyAiredEpisodes: %v", err)
+	}
+	assigned := make(map[int]bool)
+	for _, ep := range recent {
+		if ep.ShowID == show.ID {
+			assigned[ep.EpisodeNumber] = ep.HasAssignment
+		}
+	}
+	if len(assigned) != 2 || assigned[1] || !assigned[2] {
+		t.Errorf("episodes by assignment = %v, want only episode 2 assigned", assigned)
+	}
+}
@@ -0,0 +1,78 @@
+package library
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ShowSettings holds per-show preferences
+type ShowSettings struct {
+	ShowID int64
+
+	// PreferredSubtitleLangs are ISO 639-1 codes, most preferred first.
+	// Empty means the global subtitles settings apply.
+	PreferredSubtitleLangs []string
+}
+
+// ShowSettingsRepository handles per-show settings database operations
+type ShowSettingsRepository struct {
+	db *DB
+}
+
+// NewShowSettingsRepository creates a new show settings repository
+func NewShowSettingsRepository(db *DB) *ShowSettingsRepository {
+	return &ShowSettingsRepository{db: db}
+}
+
+// Get returns the settings of a show, or nil if none were saved
+func (r *ShowSettingsRepository) Get(showID int64) (*ShowSettings, error) {
+	var langs string
+	err := r.db.QueryRow(
+		`SELECT preferred_subtitle_langs FROM show_settings WHERE show_id = $1`,
+		showID,
+	).Scan(&langs)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get show settings: %w", err)
+	}
+	return &ShowSettings{ShowID: showID, PreferredSubtitleLangs: SplitTags(langs)}, nil
+}
+
+// Save creates or replaces the settings of a show
+func (r *ShowSettingsRepository) Save(settings *ShowSettings) error {
+	_, err := r.db.Exec(`
+		INSERT INTO show_settings (show_id, preferred_subtitle_langs, updated_at) VALUES ($1, $2, NOW())
+		ON CONFLICT(show_id) DO UPDATE SET preferred_subtitle_langs = EXCLUDED.preferred_subtitle_langs, updated_at = NOW()
+	`, settings.ShowID, strings.Join(settings.PreferredSubtitleLangs, ","))
+	if err != nil {
+		return fmt.Errorf("failed to save show settings: %w", err)
+	}
+	return nil
+}
+
+// PreferredSubtitleLangs returns the preferred subtitle languages of every
+// show that has them, by show ID
+func (r *ShowSettingsRepository) PreferredSubtitleLangs() (map[int64][]string, error) {
+	rows, err := r.db.Query(
+		`SELECT show_id, preferred_subtitle_langs FROM show_settings WHERE preferred_subtitle_langs <> ''`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list show settings: %w", err)
+	}
+	defer rows.Close()
+
+	langs := make(map[int64][]string)
+	for rows.Next() {
+		var showID int64
+		var value string
+		if err := rows.Scan(&showID, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan show settings: %w", err)
+		}
+		langs[showID] = SplitTags(value)
+	}
+
+	return langs, rows.Err()
+}
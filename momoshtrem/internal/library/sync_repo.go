@@ -7,6 +7,9 @@ import (
 	"time"
 )
 
+// libraryUpdatedAtKey is maintained by triggers from migration 010
+const libraryUpdatedAtKey = "library_updated_at"
+
 // SyncMetadataRepository handles sync metadata database operations
 type SyncMetadataRepository struct {
 	db *DB
@@ -87,3 +90,21 @@ func (r *SyncMetadataRepository) SetValue(key, value string) error {
 	}
 	return nil
 }
+
+// GetLibraryUpdatedAt returns when library content shown in the VFS last changed.
+// Database triggers keep it current; zero means it has never been recorded.
+func (r *SyncMetadataRepository) GetLibraryUpdatedAt() (time.Time, error) {
+	var updatedAt sql.NullTime
+	err := r.db.QueryRow(
+		`SELECT updated_at FROM sync_metadata WHERE key = $1`,
+		libraryUpdatedAtKey,
+	).Scan(&updatedAt)
+
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get library updated_at: %w", err)
+	}
+	return updatedAt.Time, nil
+}
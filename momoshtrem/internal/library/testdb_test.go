@@ -0,0 +1,93 @@
+package library
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	mathrand "math/rand/v2"
+	"os"
+	"testing"
+)
+
+// openTestDB connects to the Postgres database in MOMOSHTREM_TEST_DATABASE_URL,
+// skipping the test when it isn't set.
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	connStr := os.Getenv("MOMOSHTREM_TEST_DATABASE_URL")
+	if connStr == "" {
+		t.Skip("MOMOSHTREM_TEST_DATABASE_URL not set")
+	}
+	db, err := NewDB(connStr)
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// testItemID returns a random ID far outside the library, for rows in tables
+// without foreign keys
+func testItemID() int64 {
+	return 9_000_000_000 + mathrand.Int64N(1_000_000_000)
+}
+
+// testInfoHash returns a random info hash
+func testInfoHash() string {
+	b := make([]byte, 20)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// createTestShow creates a show with episodesPerSeason[i] episodes in season
+// i+1 and returns it with its seasons and episodes. The show is deleted when
+// the test ends. Its TMDB ID is random and above any real one, so runs don't
+// collide with each other or with the library.
+func createTestShow(t *testing.T, db *DB, title string, episodesPerSeason ...int) *Show {
+	t.Helper()
+	shows := NewShowRepository(db)
+
+	show := &Show{TMDBID: 900_000_000 + mathrand.IntN(100_000_000), Title: title, Year: 2020}
+	if err := shows.Create(show); err != nil {
+		t.Fatalf("Create show: %v", err)
+	}
+	t.Cleanup(func() { shows.Delete(show.ID) })
+
+	for i, count := range episodesPerSeason {
+		season := &Season{ShowID: show.ID, SeasonNumber: i + 1}
+		if err := shows.CreateSeason(season); err != nil {
+			t.Fatalf("CreateSeason: %v", err)
+		}
+		for n := 1; n <= count; n++ {
+			if err := shows.CreateEpisode(&Episode{SeasonID: season.ID, EpisodeNumber: n}); err != nil {
+				t.Fatalf("CreateEpisode: %v", err)
+			}
+		}
+	}
+
+	created, err := shows.GetWithSeasonsAndEpisodes(show.ID)
+	if err != nil {
+		t.Fatalf("GetWithSeasonsAndEpisodes: %v", err)
+	}
+	return created
+}
+
+// createTestAssignment stores a, filling in a random info hash and its magnet
+// when unset, and deletes it when the test ends.
+func createTestAssignment(t *testing.T, db *DB, a *TorrentAssignment) *TorrentAssignment {
+	t.Helper()
+	if a.InfoHash == "" {
+		a.InfoHash = testInfoHash()
+	}
+	if a.MagnetURI == "" {
+		a.MagnetURI = "magnet:?xt=urn:btih:" + a.InfoHash
+	}
+	if a.FileSize == 0 {
+		a.FileSize = 1
+	}
+
+	assignments := NewAssignmentRepository(db)
+	if err := assignments.Create(a); err != nil {
+		t.Fatalf("Create assignment: %v", err)
+	}
+	t.Cleanup(func() { assignments.DeleteByInfoHash(a.InfoHash) })
+	return a
+}
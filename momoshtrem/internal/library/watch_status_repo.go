@@ -0,0 +1,132 @@
+package library
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// WatchedThreshold is the fraction of the runtime after which an item counts as watched
+const WatchedThreshold = 0.9
+
+// WatchStatus is the playback state of a movie or episode
+type WatchStatus struct {
+	ItemType        ItemType
+	ItemID          int64
+	PositionSeconds int
+	DurationSeconds int
+	Watched         bool
+	UpdatedAt       time.Time
+}
+
+// ReachedEnd reports whether the position is far enough into the runtime to count as watched
+func (w *WatchStatus) ReachedEnd() bool {
+	return w.DurationSeconds > 0 && float64(w.PositionSeconds) >= float64(w.DurationSeconds)*WatchedThreshold
+}
+
+// WatchStatusRepository handles playback progress database operations
+type WatchStatusRepository struct {
+	db *DB
+}
+
+// NewWatchStatusRepository creates a new watch status repository
+func NewWatchStatusRepository(db *DB) *WatchStatusRepository {
+	return &WatchStatusRepository{db: db}
+}
+
+// Get returns the watch status for an item, or nil if it was never played
+func (r *WatchStatusRepository) Get(itemType ItemType, itemID int64) (*WatchStatus, error) {
+	status := &WatchStatus{}
+	err := r.db.QueryRow(`
+		SELECT item_type, item_id, position_seconds, duration_seconds, watched, updated_at
+		FROM watch_status WHERE item_type = $1 AND item_id = $2
+	`, itemType, itemID).Scan(&status.ItemType, &status.ItemID, &status.PositionSeconds,
+		&status.DurationSeconds, &status.Watched, &status.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watch status: %w", err)
+	}
+	return status, nil
+}
+
+// Upsert creates or updates the watch status for an item and sets UpdatedAt
+func (r *WatchStatusRepository) Upsert(status *WatchStatus) error {
+	err := r.db.QueryRow(`
+		INSERT INTO watch_status (item_type, item_id, position_seconds, duration_seconds, watched, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT(item_type, item_id) DO UPDATE SET
+			position_seconds = EXCLUDED.position_seconds,
+			duration_seconds = EXCLUDED.duration_seconds,
+			watched = EXCLUDED.watched,
+			updated_at = NOW()
+		RETURNING updated_at
+	`, status.ItemType, status.ItemID, status.PositionSeconds, status.DurationSeconds, status.Watched).Scan(&status.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save watch status: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the watch status for an item. Returns false if there was none.
+func (r *WatchStatusRepository) Delete(itemType ItemType, itemID int64) (bool, error) {
+	result, err := r.db.Exec(
+		`DELETE FROM watch_status WHERE item_type = $1 AND item_id = $2`,
+		itemType, itemID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete watch status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteForItems removes the watch status of several items of one type
+func (r *WatchStatusRepository) DeleteForItems(itemType ItemType, itemIDs []int64) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(
+		`DELETE FROM watch_status WHERE item_type = $1 AND item_id = ANY($2)`,
+		itemType, itemIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete watch status: %w", err)
+	}
+	return nil
+}
+
+// ListInProgress returns partially watched items still in the library, most
+// recently played first
+func (r *WatchStatusRepository) ListInProgress(limit int) ([]*WatchStatus, error) {
+	rows, err := r.db.Query(`
+		SELECT w.item_type, w.item_id, w.position_seconds, w.duration_seconds, w.watched, w.updated_at
+		FROM watch_status w
+		WHERE w.watched = FALSE AND w.position_seconds > 0
+		  AND (w.item_type = 'movie' AND EXISTS (SELECT 1 FROM movies m WHERE m.id = w.item_id)
+		    OR w.item_type = 'episode' AND EXISTS (SELECT 1 FROM episodes e WHERE e.id = w.item_id))
+		ORDER BY w.updated_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watch status: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []*WatchStatus
+	for rows.Next() {
+		s := &WatchStatus{}
+		if err := rows.Scan(&s.ItemType, &s.ItemID, &s.PositionSeconds, &s.DurationSeconds, &s.Watched, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watch status: %w", err)
+		}
+		statuses = append(statuses, s)
+	}
+
+	return statuses, rows.Err()
+}
@@ -0,0 +1,91 @@
+package library
+
+import "testing"
+
+func TestWatchStatusReachedEnd(t *testing.T) {
+	tests := []struct {
+		position, duration int
+		want               bool
+	}{
+		{0, 0, false},
+		{600, 0, false}, // Unknown runtime
+		{600, 3600, false},
+		{3239, 3600, false},
+		{3240, 3600, true},
+		{3600, 3600, true},
+	}
+	for _, tt := range tests {
+		w := &WatchStatus{PositionSeconds: tt.position, DurationSeconds: tt.duration}
+		if got := w.ReachedEnd(); got != tt.want {
+			t.Errorf("ReachedEnd(%d/%d) = %v, want %v", tt.position, tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestWatchStatusRepository(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewWatchStatusRepository(db)
+
+	// watch_status has no foreign keys, so IDs far outside the library are safe to use
+	movieID, episodeID, watchedID := testItemID(), testItemID(), testItemID()
+	t.Cleanup(func() {
+		repo.Delete(ItemTypeMovie, movieID)
+		repo.Delete(ItemTypeEpisode, episodeID)
+		repo.Delete(ItemTypeEpisode, watchedID)
+	})
+
+	got, err := repo.Get(ItemTypeMovie, movieID)
+	if err != nil || got != nil {
+		t.Fatalf("Get before upsert = %v, %v; want nil, nil", got, err)
+	}
+
+	movie := &WatchStatus{ItemType: ItemTypeMovie, ItemID: movieID, PositionSeconds: 120, DurationSeconds: 7200}
+	if err := repo.Upsert(movie); err != nil {
+		t.Fatalf("Upsert movie: %v", err)
+	}
+	if movie.UpdatedAt.IsZero() {
+		t.Errorf("Upsert did not set UpdatedAt")
+	}
+	episode := &WatchStatus{ItemType: ItemTypeEpisode, ItemID: episodeID, PositionSeconds: 300, DurationSeconds: 1500}
+	if err := repo.Upsert(episode); err != nil {
+		t.Fatalf("Upsert episode: %v", err)
+	}
+	watched := &WatchStatus{ItemType: ItemTypeEpisode, ItemID: watchedID, PositionSeconds: 1500, DurationSeconds: 1500, Watched: true}
+	if err := repo.Upsert(watched); err != nil {
+		t.Fatalf("Upsert watched: %v", err)
+	}
+
+	// Updating moves the movie to the front of continue watching
+	movie.PositionSeconds = 240
+	if err := repo.Upsert(movie); err != nil {
+		t.Fatalf("Upsert movie again: %v", err)
+	}
+	got, err = repo.Get(ItemTypeMovie, movieID)
+	if err != nil || got == nil || got.PositionSeconds != 240 || got.DurationSeconds != 7200 {
+		t.Fatalf("Get after update = %+v, %v", got, err)
+	}
+
+	inProgress, err := repo.ListInProgress(100)
+	if err != nil {
+		t.Fatalf("ListInProgress: %v", err)
+	}
+	var order []int64
+	for _, s := range inProgress {
+		switch s.ItemID {
+		case movieID, episodeID, watchedID:
+			order = append(order, s.ItemID)
+		}
+	}
+	if len(order) != 2 || order[0] != movieID || order[1] != episodeID {
+		t.Errorf("in-progress order = %v, want [%d %d] (watched items excluded)", order, movieID, episodeID)
+	}
+
+	deleted, err := repo.Delete(ItemTypeMovie, movieID)
+	if err != nil || !deleted {
+		t.Fatalf("Delete = %v, %v; want true, nil", deleted, err)
+	}
+	deleted, err = repo.Delete(ItemTypeMovie, movieID)
+	if err != nil || deleted {
+		t.Errorf("second Delete = %v, %v; want false, nil", deleted, err)
+	}
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shapedtime/momoshtrem/internal/identify"
+)
+
+// RegisterIdentifyCache exposes identification result cache hits and misses.
+func RegisterIdentifyCache(reg prometheus.Registerer, i *identify.Identifier) {
+	reg.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "identify",
+			Name:      "cache_hits_total",
+			Help:      "Torrent identifications served from the result cache.",
+		}, func() float64 { hits, _ := i.CacheStats(); return float64(hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "identify",
+			Name:      "cache_misses_total",
+			Help:      "Torrent identifications that parsed the file list.",
+		}, func() float64 { _, misses := i.CacheStats(); return float64(misses) }),
+	)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shapedtime/momoshtrem/internal/jobs"
+)
+
+// RegisterJobQueue exposes the depth and load of the heavy-operation queue.
+func RegisterJobQueue(reg prometheus.Registerer, q *jobs.Queue) {
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "jobs",
+			Name:      "queue_depth",
+			Help:      "Long-running operations waiting for a worker.",
+		}, func() float64 { return float64(q.Depth()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "jobs",
+			Name:      "running",
+			Help:      "Long-running operations currently executing.",
+		}, func() float64 { return float64(q.Running()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "jobs",
+			Name:      "rejected_total",
+			Help:      "Long-running operations rejected with 429 because the queue was full.",
+		}, func() float64 { return float64(q.Rejected()) }),
+	)
+}
@@ -14,6 +14,12 @@ type Metrics struct {
 	StreamingSeeks              *prometheus.CounterVec // labels: direction=forward|backward
 	StreamingPiecesDowngraded   prometheus.Counter
 	StreamingSlowReads          prometheus.Counter
+	StreamingIndexWait          *prometheus.HistogramVec // labels: result=ready|timeout|no_index
+
+	// VFS tree rebuilds (full rebuilds from the database)
+	VFSTreeRebuilds        prometheus.Counter
+	VFSTreeRebuildDuration prometheus.Histogram
+	VFSTreeEntries         prometheus.Gauge
 }
 
 // New creates and registers streaming metrics with the given registry.
@@ -68,6 +74,32 @@ func New(reg prometheus.Registerer) *Metrics {
 			Name:      "slow_reads_total",
 			Help:      "Reads that blocked over 500ms waiting for piece data.",
 		}),
+		StreamingIndexWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "streaming",
+			Name:      "index_wait_seconds",
+			Help:      "Time the first read of a stream waited for the seek index (streaming.wait_for_index).",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15, 30},
+		}, []string{"result"}),
+		VFSTreeRebuilds: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "vfs",
+			Name:      "tree_rebuilds_total",
+			Help:      "Full VFS tree rebuilds from the database.",
+		}),
+		VFSTreeRebuildDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "vfs",
+			Name:      "tree_rebuild_duration_seconds",
+			Help:      "Duration of full VFS tree rebuilds.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}),
+		VFSTreeEntries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "vfs",
+			Name:      "tree_entries",
+			Help:      "Paths in the VFS tree after the last full rebuild.",
+		}),
 	}
 
 	reg.MustRegister(
@@ -79,6 +111,10 @@ func New(reg prometheus.Registerer) *Metrics {
 		m.StreamingSeeks,
 		m.StreamingPiecesDowngraded,
 		m.StreamingSlowReads,
+		m.StreamingIndexWait,
+		m.VFSTreeRebuilds,
+		m.VFSTreeRebuildDuration,
+		m.VFSTreeEntries,
 	)
 
 	return m
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// RegisterPieceCache exposes piece cache fill and evictions caused by its size cap.
+func RegisterPieceCache(reg prometheus.Registerer, m *torrent.CacheMonitor) {
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "piece_cache",
+			Name:      "used_bytes",
+			Help:      "Bytes of piece data in the torrent cache.",
+		}, func() float64 { return float64(m.Usage().UsedBytes) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "piece_cache",
+			Name:      "capacity_bytes",
+			Help:      "Configured torrent cache size.",
+		}, func() float64 { return float64(m.Usage().CapacityBytes) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "piece_cache",
+			Name:      "evicted_pieces_total",
+			Help:      "Verified pieces evicted to keep the cache under its size.",
+		}, func() float64 { return float64(m.Usage().EvictedPieces) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "piece_cache",
+			Name:      "evicted_bytes_total",
+			Help:      "Bytes of verified pieces evicted from the cache.",
+		}, func() float64 { return float64(m.Usage().EvictedBytes) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "piece_cache",
+			Name:      "evicted_streaming_pieces_total",
+			Help:      "Evicted pieces belonging to files open for streaming. A rising rate means the cache is too small for the concurrent streams.",
+		}, func() float64 { return float64(m.Usage().EvictedStreamingPieces) }),
+	)
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
+)
+
+// RegisterTMDBCache exposes TMDB response cache hits and misses.
+func RegisterTMDBCache(reg prometheus.Registerer, c *tmdb.Client) {
+	reg.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "tmdb",
+			Name:      "cache_hits_total",
+			Help:      "TMDB movie/show/season lookups served from cache.",
+		}, func() float64 { hits, _ := c.CacheStats(); return float64(hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: "momoshtrem",
+			Subsystem: "tmdb",
+			Name:      "cache_misses_total",
+			Help:      "TMDB movie/show/season lookups that queried the API.",
+		}, func() float64 { _, misses := c.CacheStats(); return float64(misses) }),
+	)
+}
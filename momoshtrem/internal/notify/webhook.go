@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event types sent in the "event" field and the X-Momoshtrem-Event header
+const (
+	EventTorrentAssigned      = "torrent.assigned"
+	EventTorrentRemoved       = "torrent.removed"
+	EventAirDateSyncCompleted = "airdate_sync.completed"
+	EventEpisodesMissing      = "episodes.missing" // Aired episodes without an assignment
+)
+
+// Webhook request headers
+const (
+	SignatureHeader = "X-Momoshtrem-Signature" // "sha256=" + hex HMAC-SHA256 of the body
+	EventHeader     = "X-Momoshtrem-Event"
+)
+
+// Delivery defaults
+const (
+	defaultMaxAttempts = 3
+	defaultTimeout     = 10 * time.Second
+	defaultBackoff     = time.Second // Doubled after each failed attempt
+	queueSize          = 64          // Events waiting for delivery; more are dropped
+)
+
+// Config configures webhook delivery
+type Config struct {
+	URLs        []string      // Endpoints that receive every event
+	Secret      string        // HMAC-SHA256 key for the signature header (optional)
+	MaxAttempts int           // Attempts per URL before giving up
+	Timeout     time.Duration // Per-request timeout
+}
+
+// Event is the JSON body posted to webhook URLs
+type Event struct {
+	Type      string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data"`
+}
+
+// Notifier posts events to webhook URLs in the background.
+// Notify never blocks; events are dropped with a warning if delivery falls behind.
+// A nil *Notifier discards all events.
+type Notifier struct {
+	client      *http.Client
+	urls        []string
+	secret      []byte
+	maxAttempts int
+	backoff     time.Duration
+
+	events chan Event
+	stop   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+	log    *slog.Logger
+}
+
+// New creates a notifier and starts its delivery worker. Returns nil if no URLs are configured.
+func New(cfg Config) *Notifier {
+	if len(cfg.URLs) == 0 {
+		return nil
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	n := &Notifier{
+		client:      &http.Client{Timeout: cfg.Timeout},
+		urls:        cfg.URLs,
+		secret:      []byte(cfg.Secret),
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     defaultBackoff,
+		events:      make(chan Event, queueSize),
+		stop:        make(chan struct{}),
+		log:         slog.With("component", "webhooks"),
+	}
+
+	n.wg.Add(1)
+	go n.run()
+	return n
+}
+
+// Notify queues an event for delivery without blocking
+func (n *Notifier) Notify(eventType string, data any) {
+	if n == nil {
+		return
+	}
+
+	event := Event{Type: eventType, Timestamp: time.Now().UTC(), Data: data}
+	select {
+	case n.events <- event:
+	default:
+		n.log.Warn("Webhook queue full, dropping event", "event", eventType)
+	}
+}
+
+// Close stops the delivery worker. Queued events that haven't been sent are dropped.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.once.Do(func() { close(n.stop) })
+	n.wg.Wait()
+}
+
+func (n *Notifier) run() {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case event := <-n.events:
+			n.deliver(event)
+		}
+	}
+}
+
+// deliver posts an event to every URL, retrying each with exponential backoff
+func (n *Notifier) deliver(event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.log.Error("Failed to encode webhook event", "event", event.Type, "error", err)
+		return
+	}
+	signature := Sign(n.secret, body)
+
+	for _, url := range n.urls {
+		backoff := n.backoff
+		for attempt := 1; ; attempt++ {
+			retry, err := n.post(url, event.Type, body, signature)
+			if err == nil {
+				break
+			}
+			if !retry || attempt >= n.maxAttempts {
+				n.log.Warn("Webhook delivery failed", "url", url, "event", event.Type, "attempts", attempt, "error", err)
+				break
+			}
+
+			select {
+			case <-n.stop:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// post sends one request. retry reports whether a failure may succeed on another attempt.
+func (n *Notifier) post(url, eventType string, body []byte, signature string) (retry bool, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-n.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, eventType)
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	// Client errors won't change on retry, except rate limiting
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// Sign returns the signature header value for a body, or "" if there is no secret.
+// Receivers recompute it with the shared secret and compare using hmac.Equal.
+func Sign(secret, body []byte) string {
+	if len(secret) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
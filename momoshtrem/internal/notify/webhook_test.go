@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNotifierSignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	received := make(chan Event, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), Sign([]byte("s3cret"), body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if r.Header.Get(EventHeader) != EventTorrentRemoved {
+			t.Errorf("event header = %q", r.Header.Get(EventHeader))
+		}
+
+		// Fail the first attempt to exercise the retry
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		received <- event
+	}))
+	defer srv.Close()
+
+	n := New(Config{URLs: []string{srv.URL}, Secret: "s3cret"})
+	n.backoff = time.Millisecond
+	defer n.Close()
+
+	n.Notify(EventTorrentRemoved, map[string]string{"info_hash": "abc"})
+
+	select {
+	case event := <-received:
+		if event.Type != EventTorrentRemoved {
+			t.Errorf("event type = %q", event.Type)
+		}
+		if data, _ := event.Data.(map[string]any); data["info_hash"] != "abc" {
+			t.Errorf("event data = %v", event.Data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestNotifierDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	done := make(chan struct{}, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		done <- struct{}{}
+	}))
+	defer srv.Close()
+
+	n := New(Config{URLs: []string{srv.URL}, MaxAttempts: 3})
+	n.backoff = time.Millisecond
+
+	n.Notify(EventAirDateSyncCompleted, nil)
+	<-done
+	n.Close()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestNilNotifier(t *testing.T) {
+	var n *Notifier
+	n.Notify(EventTorrentAssigned, nil) // Must not panic
+	n.Close()
+
+	if New(Config{}) != nil {
+		t.Error("New without URLs should return nil")
+	}
+	if Sign(nil, []byte("body")) != "" {
+		t.Error("Sign without secret should be empty")
+	}
+}
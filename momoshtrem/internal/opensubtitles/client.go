@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -24,8 +26,55 @@ const (
 	// Token management
 	tokenValidDuration   = 24 * time.Hour // Token validity period
 	tokenRefreshDuration = 23 * time.Hour // Refresh before expiry
+
+	// Rate limit retries
+	defaultMaxRetries = 3
+	retryBaseDelay    = time.Second      // Doubled for each further retry
+	maxRetryDelay     = 30 * time.Second // Cap, including Retry-After
+)
+
+// Login reasons, used in logs to tell routine refreshes from forced re-logins
+const (
+	loginReasonInitial      = "initial"
+	loginReasonExpired      = "expired"
+	loginReasonUnauthorized = "unauthorized"
 )
 
+// Compile-time verification
+var (
+	_ AuthReporter  = (*Client)(nil)
+	_ QuotaReporter = (*Client)(nil)
+)
+
+// QuotaExhaustedError is returned by Download when no downloads are left
+type QuotaExhaustedError struct {
+	ResetAt time.Time // When the quota resets; zero if unknown
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	if !e.ResetAt.IsZero() {
+		return fmt.Sprintf("OpenSubtitles download quota exhausted, resets at %s", e.ResetAt.Format(time.RFC3339))
+	}
+	return "OpenSubtitles download quota exhausted"
+}
+
+// RateLimitError is returned when OpenSubtitles responds with 429 Too Many
+// Requests and the retries are used up
+type RateLimitError struct {
+	RetryAfter time.Duration // From the Retry-After header; 0 if absent
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited - too many requests, retry after %s", e.RetryAfter)
+	}
+	return "rate limited - too many requests"
+}
+
+// errNotAcceptable is returned by handleResponse for 406 responses, which the
+// download endpoint sends with the quota fields once the quota is used up
+var errNotAcceptable = errors.New("not acceptable")
+
 // Client is an OpenSubtitles API client
 type Client struct {
 	apiKey     string
@@ -33,10 +82,26 @@ type Client struct {
 	password   string
 	httpClient *http.Client
 
+	// Retries of rate-limited requests; retryDelay is the first backoff
+	maxRetries int
+	retryDelay time.Duration
+
 	// Token management
 	mu       sync.RWMutex
+	loginMu  sync.Mutex // Serializes logins
 	token    string
 	tokenExp time.Time
+
+	// Auth diagnostics (guarded by mu)
+	unauthorized bool // Token was cleared by a 401; the next login is a re-login
+	lastLoginAt  time.Time
+	lastError    string
+	stats        AuthStats
+
+	// Download quota from the last download response (guarded by mu; nil until then)
+	quota *DownloadQuota
+
+	log *slog.Logger
 }
 
 // NewClient creates a new OpenSubtitles client
@@ -48,7 +113,85 @@ func NewClient(apiKey, username, password string) *Client {
 		httpClient: &http.Client{
 			Timeout: defaultHTTPTimeout,
 		},
+		maxRetries: defaultMaxRetries,
+		retryDelay: retryBaseDelay,
+		log:        slog.With("component", "opensubtitles"),
+	}
+}
+
+// SetMaxRetries sets how often a rate-limited request is retried (0 disables retries)
+func (c *Client) SetMaxRetries(n int) {
+	c.maxRetries = n
+}
+
+// AuthStats returns a snapshot of the authentication state and counters
+func (c *Client) AuthStats() AuthStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := c.stats
+	stats.Mode = "user"
+	if !c.hasCredentials() {
+		stats.Mode = "anonymous"
+	}
+	stats.TokenValid = c.token != "" && time.Now().Before(c.tokenExp)
+	if !c.tokenExp.IsZero() {
+		exp := c.tokenExp
+		stats.TokenExpiresAt = &exp
+	}
+	if !c.lastLoginAt.IsZero() {
+		at := c.lastLoginAt
+		stats.LastLoginAt = &at
+	}
+	stats.LastError = c.lastError
+	return stats
+}
+
+// DownloadQuota returns the quota reported by the last download, or nil
+// before the first one
+func (c *Client) DownloadQuota() *DownloadQuota {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.quota == nil {
+		return nil
+	}
+	quota := *c.quota
+	return &quota
+}
+
+// recordQuota stores the quota fields of a download response
+func (c *Client) recordQuota(resp *DownloadResponse) *DownloadQuota {
+	quota := &DownloadQuota{
+		Requests:  resp.Requests,
+		Remaining: resp.Remaining,
+		UpdatedAt: time.Now(),
 	}
+	if reset, err := time.Parse(time.RFC3339, resp.ResetTimeUTC); err == nil {
+		quota.ResetAt = &reset
+	}
+
+	c.mu.Lock()
+	c.quota = quota
+	c.mu.Unlock()
+	return quota
+}
+
+// quotaExhausted returns an error while the last known quota is used up and
+// hasn't reset yet, so callers stop before making a request that would fail
+func (c *Client) quotaExhausted() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.quota == nil || c.quota.Remaining > 0 || c.quota.ResetAt == nil || !time.Now().Before(*c.quota.ResetAt) {
+		return nil
+	}
+	return &QuotaExhaustedError{ResetAt: *c.quota.ResetAt}
+}
+
+// hasCredentials reports whether user login is configured (otherwise API key only)
+func (c *Client) hasCredentials() bool {
+	return c.username != "" && c.password != ""
 }
 
 // IsConfigured returns true if the client has an API key configured
@@ -85,6 +228,10 @@ func (c *Client) Search(ctx context.Context, params SearchParams) (*SearchRespon
 		query.Set("languages", strings.Join(params.Languages, ","))
 	}
 
+	if params.MovieHash != "" {
+		query.Set("moviehash", params.MovieHash)
+	}
+
 	endpoint := fmt.Sprintf("%s/subtitles?%s", baseURL, query.Encode())
 
 	var result SearchResponse
@@ -102,6 +249,10 @@ func (c *Client) Download(ctx context.Context, fileID int) ([]byte, string, erro
 		return nil, "", fmt.Errorf("OpenSubtitles API key not configured")
 	}
 
+	if err := c.quotaExhausted(); err != nil {
+		return nil, "", err
+	}
+
 	// Ensure we have a valid token for downloads
 	if err := c.ensureToken(ctx); err != nil {
 		return nil, "", fmt.Errorf("failed to authenticate: %w", err)
@@ -113,8 +264,18 @@ func (c *Client) Download(ctx context.Context, fileID int) ([]byte, string, erro
 
 	var downloadResp DownloadResponse
 	if err := c.post(ctx, endpoint, reqBody, &downloadResp, true); err != nil {
+		if errors.Is(err, errNotAcceptable) {
+			quota := c.recordQuota(&downloadResp)
+			quotaErr := &QuotaExhaustedError{}
+			if quota.ResetAt != nil {
+				quotaErr.ResetAt = *quota.ResetAt
+			}
+			c.log.Warn("OpenSubtitles download quota exhausted", "requests", quota.Requests, "reset_at", quota.ResetAt)
+			return nil, "", quotaErr
+		}
 		return nil, "", fmt.Errorf("download request failed: %w", err)
 	}
+	c.recordQuota(&downloadResp)
 
 	if downloadResp.Link == "" {
 		return nil, "", fmt.Errorf("no download link in response")
@@ -159,23 +320,37 @@ func (c *Client) ensureToken(ctx context.Context) error {
 
 // login authenticates and obtains a token
 func (c *Client) login(ctx context.Context) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	// Serialize logins; the request itself runs without holding mu so a 401
+	// from the login endpoint can be recorded by handleResponse.
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
 
-	// Double-check after acquiring write lock
+	c.mu.Lock()
+	// Double-check after acquiring the login lock
 	if c.token != "" && time.Now().Before(c.tokenExp) {
+		c.mu.Unlock()
 		return nil
 	}
 
 	// If no credentials, use API key only (limited downloads)
-	if c.username == "" || c.password == "" {
+	if !c.hasCredentials() {
 		// For anonymous use, we don't need a token
 		// The API key alone allows limited downloads
 		c.token = "anonymous"
 		c.tokenExp = time.Now().Add(tokenValidDuration)
+		c.mu.Unlock()
 		return nil
 	}
 
+	reason := loginReasonInitial
+	switch {
+	case c.unauthorized:
+		reason = loginReasonUnauthorized
+	case !c.tokenExp.IsZero():
+		reason = loginReasonExpired
+	}
+	c.mu.Unlock()
+
 	endpoint := fmt.Sprintf("%s/login", baseURL)
 	reqBody := LoginRequest{
 		Username: c.username,
@@ -183,36 +358,57 @@ func (c *Client) login(ctx context.Context) error {
 	}
 
 	var loginResp LoginResponse
-	if err := c.post(ctx, endpoint, reqBody, &loginResp, false); err != nil {
-		return fmt.Errorf("login failed: %w", err)
+	err := c.post(ctx, endpoint, reqBody, &loginResp, false)
+	if err == nil && loginResp.Token == "" {
+		err = fmt.Errorf("no token in login response")
 	}
 
-	if loginResp.Token == "" {
-		return fmt.Errorf("no token in login response")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.stats.LoginFailures++
+		c.lastError = err.Error()
+		c.log.Warn("OpenSubtitles login failed",
+			"reason", reason,
+			"login_failures", c.stats.LoginFailures,
+			"error", err,
+		)
+		return fmt.Errorf("login failed: %w", err)
 	}
 
 	c.token = loginResp.Token
 	c.tokenExp = time.Now().Add(tokenRefreshDuration)
+	c.unauthorized = false
+	c.lastLoginAt = time.Now()
+	c.lastError = ""
+	c.stats.Logins++
+	switch reason {
+	case loginReasonExpired:
+		c.stats.Refreshes++
+	case loginReasonUnauthorized:
+		c.stats.UnauthorizedRelogins++
+	}
+
+	c.log.Info("OpenSubtitles login succeeded",
+		"reason", reason,
+		"logins", c.stats.Logins,
+		"unauthorized_relogins", c.stats.UnauthorizedRelogins,
+	)
 
 	return nil
 }
 
 // get performs a GET request
 func (c *Client) get(ctx context.Context, endpoint string, result interface{}) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	c.setHeaders(req, false)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	return c.handleResponse(resp, result)
+	return c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(req, false)
+		return req, nil
+	}, result)
 }
 
 // post performs a POST request
@@ -222,21 +418,57 @@ func (c *Client) post(ctx context.Context, endpoint string, body interface{}, re
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.do(ctx, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		c.setHeaders(req, useToken)
+		return req, nil
+	}, result)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	c.setHeaders(req, useToken)
+// do sends the request built by newRequest, retrying 429 responses up to
+// maxRetries times. Each retry waits twice as long as the one before, or
+// the Retry-After delay when the response has one. Other errors, including
+// a 401 that cleared the token, are returned as they are.
+func (c *Client) do(ctx context.Context, newRequest func() (*http.Request, error), result interface{}) error {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		err = c.handleResponse(resp, result)
+		resp.Body.Close()
 
-	return c.handleResponse(resp, result)
+		var rateErr *RateLimitError
+		if !errors.As(err, &rateErr) || attempt >= c.maxRetries {
+			return err
+		}
+
+		delay := rateErr.RetryAfter
+		if delay <= 0 {
+			delay = c.retryDelay << attempt
+		}
+		delay = min(delay, maxRetryDelay)
+		c.log.Warn("OpenSubtitles rate limited, retrying",
+			"path", req.URL.Path,
+			"attempt", attempt+1,
+			"delay", delay,
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
 }
 
 // setHeaders sets common headers
@@ -261,14 +493,30 @@ func (c *Client) handleResponse(resp *http.Response, result interface{}) error {
 	if resp.StatusCode == http.StatusUnauthorized {
 		// Clear token to force re-login
 		c.mu.Lock()
+		hadToken := c.token != ""
 		c.token = ""
 		c.tokenExp = time.Time{}
+		c.unauthorized = true
+		c.stats.UnauthorizedResponses++
+		count := c.stats.UnauthorizedResponses
 		c.mu.Unlock()
+
+		c.log.Warn("OpenSubtitles request unauthorized",
+			"path", resp.Request.URL.Path,
+			"had_token", hadToken,
+			"unauthorized_responses", count,
+		)
 		return fmt.Errorf("unauthorized - invalid API key or token")
 	}
 
 	if resp.StatusCode == http.StatusTooManyRequests {
-		return fmt.Errorf("rate limited - too many requests")
+		return &RateLimitError{RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+
+	if resp.StatusCode == http.StatusNotAcceptable {
+		// Best effort: the body carries the quota fields for downloads
+		json.NewDecoder(resp.Body).Decode(result)
+		return errNotAcceptable
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -282,3 +530,18 @@ func (c *Client) handleResponse(resp *http.Response, result interface{}) error {
 
 	return nil
 }
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date.
+// Returns 0 if it is absent or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
@@ -0,0 +1,113 @@
+package opensubtitles
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadStopsWhileQuotaExhausted(t *testing.T) {
+	c := NewClient("key", "", "")
+	reset := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	c.recordQuota(&DownloadResponse{Requests: 20, Remaining: 0, ResetTimeUTC: reset.Format(time.RFC3339)})
+
+	quota := c.DownloadQuota()
+	if quota == nil || quota.Remaining != 0 || quota.ResetAt == nil || !quota.ResetAt.Equal(reset) {
+		t.Fatalf("DownloadQuota() = %+v, want 0 remaining until %s", quota, reset)
+	}
+
+	// No request is made: the known quota already says it would fail
+	_, _, err := c.Download(context.Background(), 1)
+	var quotaErr *QuotaExhaustedError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Download() error = %v, want QuotaExhaustedError", err)
+	}
+	if !quotaErr.ResetAt.Equal(reset) {
+		t.Errorf("ResetAt = %s, want %s", quotaErr.ResetAt, reset)
+	}
+
+	// Once the reset time has passed, downloads are attempted again
+	c.recordQuota(&DownloadResponse{Remaining: 0, ResetTimeUTC: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)})
+	if err := c.quotaExhausted(); err != nil {
+		t.Errorf("quotaExhausted() after reset = %v, want nil", err)
+	}
+}
+
+func TestRetryOnRateLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int // Response per attempt; the last one repeats
+		retryAfter string
+		maxRetries int
+		wantCalls  int32
+		wantErr    bool
+		minElapsed time.Duration // Lower bound on the time the retries took
+	}{
+		{"succeeds after retries", []int{429, 429, 200}, "", 3, 3, false, 0},
+		{"honors retry-after", []int{429, 200}, "1", 3, 2, false, time.Second}, // Backoff alone would wait 1ms
+		{"gives up", []int{429}, "", 2, 3, true, 0},
+		{"retries disabled", []int{429}, "", 0, 1, true, 0},
+		{"unauthorized not retried", []int{401}, "", 3, 1, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				status := tt.statuses[min(n, len(tt.statuses))-1]
+				if status == http.StatusTooManyRequests && tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+				w.Write([]byte(`{"total_count": 1}`))
+			}))
+			defer srv.Close()
+
+			c := NewClient("key", "", "")
+			c.SetMaxRetries(tt.maxRetries)
+			c.retryDelay = time.Millisecond
+			c.token = "token"
+			c.tokenExp = time.Now().Add(time.Hour)
+
+			var resp SearchResponse
+			start := time.Now()
+			err := c.get(context.Background(), srv.URL, &resp)
+			elapsed := time.Since(start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", got, tt.wantCalls)
+			}
+			if elapsed < tt.minElapsed {
+				t.Errorf("retries took %s, want at least %s", elapsed, tt.minElapsed)
+			}
+
+			// Only a 401 drops the token, forcing a re-login
+			unauthorized := tt.statuses[0] == http.StatusUnauthorized
+			if cleared := c.token == ""; cleared != unauthorized {
+				t.Errorf("token cleared = %v, want %v", cleared, unauthorized)
+			}
+			if unauthorized && (!c.tokenExp.IsZero() || !c.unauthorized) {
+				t.Errorf("after 401: tokenExp = %v, unauthorized = %v, want zero and true", c.tokenExp, c.unauthorized)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("5"); got != 5*time.Second {
+		t.Errorf("parseRetryAfter(5) = %s, want 5s", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("parseRetryAfter(\"\") = %s, want 0", got)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > 10*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %s, want (0, 10s]", date, got)
+	}
+}
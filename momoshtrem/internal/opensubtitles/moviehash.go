@@ -0,0 +1,59 @@
+package opensubtitles
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// movieHashChunkSize is how much of the start and end of a file the hash covers
+const movieHashChunkSize = 64 * 1024
+
+// MovieHash computes the OpenSubtitles hash of a file: its size plus the sum of
+// the first and last 64KB read as little-endian uint64 words, as 16 hex digits.
+func MovieHash(r io.ReaderAt, size int64) (string, error) {
+	if size < movieHashChunkSize*2 {
+		return "", fmt.Errorf("file too small for moviehash: %d bytes", size)
+	}
+
+	hash := uint64(size)
+	buf := make([]byte, movieHashChunkSize)
+	for _, off := range []int64{0, size - movieHashChunkSize} {
+		if _, err := r.ReadAt(buf, off); err != nil && err != io.EOF {
+			return "", fmt.Errorf("failed to read at %d: %w", off, err)
+		}
+		for i := 0; i < len(buf); i += 8 {
+			hash += binary.LittleEndian.Uint64(buf[i:])
+		}
+	}
+
+	return fmt.Sprintf("%016x", hash), nil
+}
+
+// ComputeMovieHash computes the OpenSubtitles hash of a file that may block on
+// reads, such as a torrent file still downloading. Only the first and last 64KB
+// are read. r is closed before returning when it is an io.Closer, which also
+// abandons a pending read if ctx ends first.
+func ComputeMovieHash(ctx context.Context, r io.ReaderAt, size int64) (string, error) {
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	type result struct {
+		hash string
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		hash, err := MovieHash(r, size)
+		done <- result{hash, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.hash, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,63 @@
+package opensubtitles
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMovieHash(t *testing.T) {
+	const size = 3 * movieHashChunkSize
+	data := make([]byte, size)
+	// One word at the start, one in the middle (ignored) and one at the end
+	binary.LittleEndian.PutUint64(data[0:], 1)
+	binary.LittleEndian.PutUint64(data[movieHashChunkSize:], 1000)
+	binary.LittleEndian.PutUint64(data[size-8:], 0x10)
+
+	got, err := MovieHash(bytes.NewReader(data), size)
+	if err != nil {
+		t.Fatalf("MovieHash: %v", err)
+	}
+	// size (0x30000) + 1 + 0x10
+	if want := "0000000000030011"; got != want {
+		t.Errorf("MovieHash() = %s, want %s", got, want)
+	}
+
+	if _, err := MovieHash(bytes.NewReader(data[:100]), 100); err == nil {
+		t.Error("MovieHash() on a tiny file should fail")
+	}
+}
+
+// blockingReader blocks every read until it is closed and counts closes
+type blockingReader struct {
+	closed chan struct{}
+	closes int
+}
+
+func (r *blockingReader) ReadAt([]byte, int64) (int, error) {
+	<-r.closed
+	return 0, errors.New("closed")
+}
+
+func (r *blockingReader) Close() error {
+	if r.closes++; r.closes == 1 {
+		close(r.closed)
+	}
+	return nil
+}
+
+func TestComputeMovieHashClosesReaderOnce(t *testing.T) {
+	r := &blockingReader{closed: make(chan struct{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := ComputeMovieHash(ctx, r, 3*movieHashChunkSize); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ComputeMovieHash error = %v, want deadline exceeded", err)
+	}
+	if r.closes != 1 {
+		t.Errorf("reader closed %d times, want once", r.closes)
+	}
+}
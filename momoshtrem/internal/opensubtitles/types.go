@@ -1,6 +1,9 @@
 package opensubtitles
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // SubtitleFetcher defines the interface for fetching subtitles from external sources.
 type SubtitleFetcher interface {
@@ -12,6 +15,41 @@ type SubtitleFetcher interface {
 	Download(ctx context.Context, fileID int) ([]byte, string, error)
 }
 
+// AuthReporter is implemented by fetchers that can report their authentication state.
+type AuthReporter interface {
+	AuthStats() AuthStats
+}
+
+// AuthStats describes the client's authentication state for diagnostics.
+// It never includes the token itself.
+type AuthStats struct {
+	Mode           string     `json:"mode"` // "user" (username/password) or "anonymous" (API key only)
+	TokenValid     bool       `json:"token_valid"`
+	TokenExpiresAt *time.Time `json:"token_expires_at,omitempty"`
+	LastLoginAt    *time.Time `json:"last_login_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"` // Most recent login failure, cleared on success
+
+	Logins                int64 `json:"logins"`                 // Successful logins
+	Refreshes             int64 `json:"refreshes"`              // Logins replacing an expired token
+	UnauthorizedRelogins  int64 `json:"unauthorized_relogins"`  // Logins after a 401 invalidated the token
+	LoginFailures         int64 `json:"login_failures"`         // Failed login attempts
+	UnauthorizedResponses int64 `json:"unauthorized_responses"` // 401 responses from the API
+}
+
+// QuotaReporter is implemented by fetchers that track their download quota.
+type QuotaReporter interface {
+	DownloadQuota() *DownloadQuota
+}
+
+// DownloadQuota is the download allowance reported by the last download
+// response. OpenSubtitles limits downloads per day, more tightly on the free tier.
+type DownloadQuota struct {
+	Requests  int        `json:"requests"`  // Downloads used in the current period
+	Remaining int        `json:"remaining"` // Downloads left until ResetAt
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
 // SearchParams contains parameters for subtitle search
 type SearchParams struct {
 	TMDBID        int      // TMDB ID of the movie or TV show
@@ -19,6 +57,7 @@ type SearchParams struct {
 	SeasonNumber  int      // Season number (for episodes)
 	EpisodeNumber int      // Episode number (for episodes)
 	Languages     []string // ISO 639-1 language codes (en, ru, tr, az)
+	MovieHash     string   // Optional: OpenSubtitles hash of the video file (see MovieHash)
 }
 
 // SearchResponse is the API response for subtitle search
@@ -54,6 +93,7 @@ type SubtitleAttributes struct {
 	Release         string  `json:"release"`
 	Comments        string  `json:"comments"`
 	LegacySubtitleID int   `json:"legacy_subtitle_id"`
+	MovieHashMatch  bool    `json:"moviehash_match"` // Uploaded for a file with the searched moviehash
 	UploadDate      string  `json:"upload_date"`
 	Files           []SubtitleFile `json:"files"`
 	FeatureDetails  FeatureDetails `json:"feature_details"`
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
+)
+
+// artworkBackfillKey marks in sync_metadata that every movie and show has been
+// checked for artwork, so later startups skip the TMDB lookups
+const artworkBackfillKey = "artwork_backfill"
+
+// ArtworkSource defines the TMDB lookups used to backfill artwork URLs.
+type ArtworkSource interface {
+	GetMovie(id int) (*tmdb.Movie, error)
+	GetShow(id int) (*tmdb.Show, error)
+}
+
+// Compile-time verification
+var _ ArtworkSource = (*tmdb.Client)(nil)
+
+// BackfillArtwork fills in poster and backdrop URLs for movies and shows added
+// before artwork was stored. It runs once per database: when every lookup
+// succeeds the run is recorded and later calls return immediately. It returns
+// the number of items updated.
+func BackfillArtwork(
+	ctx context.Context,
+	movies *library.MovieRepository,
+	shows *library.ShowRepository,
+	syncMeta *library.SyncMetadataRepository,
+	source ArtworkSource,
+) (int, error) {
+	if done, err := syncMeta.GetValue(artworkBackfillKey); err != nil {
+		return 0, err
+	} else if done != "" {
+		return 0, nil
+	}
+
+	log := slog.With("component", "artwork-backfill")
+	updated, failed := 0, 0
+
+	missingMovies, err := movies.ListMissingArtwork()
+	if err != nil {
+		return 0, err
+	}
+	for _, movie := range missingMovies {
+		if ctx.Err() != nil {
+			return updated, ctx.Err()
+		}
+		details, err := source.GetMovie(movie.TMDBID)
+		if err != nil {
+			log.Warn("Failed to fetch movie artwork", "movie_id", movie.ID, "title", movie.Title, "error", err)
+			failed++
+			continue
+		}
+		poster, backdrop := tmdb.ImageURL(details.PosterPath), tmdb.ImageURL(details.BackdropPath)
+		if poster == "" && backdrop == "" {
+			continue
+		}
+		if err := movies.UpdateArtwork(movie.ID, poster, backdrop); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	missingShows, err := shows.ListMissingArtwork()
+	if err != nil {
+		return updated, err
+	}
+	for _, show := range missingShows {
+		if ctx.Err() != nil {
+			return updated, ctx.Err()
+		}
+		details, err := source.GetShow(show.TMDBID)
+		if err != nil {
+			log.Warn("Failed to fetch show artwork", "show_id", show.ID, "title", show.Title, "error", err)
+			failed++
+			continue
+		}
+		poster, backdrop := tmdb.ImageURL(details.PosterPath), tmdb.ImageURL(details.BackdropPath)
+		if poster == "" && backdrop == "" {
+			continue
+		}
+		if err := shows.UpdateArtwork(show.ID, poster, backdrop); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	log.Info("Artwork backfill finished",
+		"movies_checked", len(missingMovies),
+		"shows_checked", len(missingShows),
+		"updated", updated,
+		"failed", failed,
+	)
+
+	if failed > 0 {
+		return updated, fmt.Errorf("%d artwork lookups failed, will retry on next start", failed)
+	}
+	return updated, syncMeta.SetValue(artworkBackfillKey, "done")
+}
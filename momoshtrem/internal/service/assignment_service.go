@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/jobs"
 	"github.com/shapedtime/momoshtrem/internal/library"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
 	"github.com/shapedtime/momoshtrem/internal/torrent"
@@ -14,7 +19,10 @@ import (
 
 // TorrentAdder defines the torrent operations needed by ShowAssignmentService.
 type TorrentAdder interface {
-	AddTorrent(magnetURI string) (*torrent.TorrentInfo, error)
+	AddTorrent(ctx context.Context, magnetURI string) (*torrent.TorrentInfo, error)
+	GetTorrent(infoHash string) (*torrent.TorrentInfo, error)
+	WaitForSeeders(ctx context.Context, infoHash string, minSeeders int) error
+	RemoveTorrent(infoHash string, deleteData bool) error
 }
 
 // Compile-time verification
@@ -23,6 +31,7 @@ var _ TorrentAdder = (torrent.Service)(nil)
 // EpisodeIdentifier defines the identification operations.
 type EpisodeIdentifier interface {
 	Identify(files []identify.TorrentFile, torrentName string) *identify.IdentificationResult
+	FindMovieFile(files []identify.TorrentFile) *identify.MovieMatchResult
 }
 
 // Compile-time verification
@@ -31,20 +40,53 @@ var _ EpisodeIdentifier = (*identify.Identifier)(nil)
 // SubtitleCreator defines subtitle operations needed for torrent subtitles.
 type SubtitleCreator interface {
 	CreateTorrentSubtitle(ctx context.Context, sub *subtitle.Subtitle) error
+	SetContentHash(ctx context.Context, sub *subtitle.Subtitle, contentHash string) (bool, error)
 }
 
 // Compile-time verification
 var _ SubtitleCreator = (*subtitle.Service)(nil)
 
+// TorrentFileGetter defines file access used to hash torrent subtitles.
+type TorrentFileGetter interface {
+	GetFile(infoHash, filePath string) (torrent.TorrentFileHandle, error)
+}
+
+// Compile-time verification
+var _ TorrentFileGetter = (torrent.Service)(nil)
+
+const (
+	// subtitleHashTimeout bounds how long we wait for subtitle pieces when hashing.
+	subtitleHashTimeout = 15 * time.Second
+	// maxSubtitleHashSize skips hashing for files too large to be plain subtitles.
+	maxSubtitleHashSize = 10 * 1024 * 1024
+)
+
 // ShowAssignmentService handles torrent-to-show assignment operations.
 type ShowAssignmentService struct {
 	showRepo        *library.ShowRepository
 	assignmentRepo  *library.AssignmentRepository
 	torrentAdder    TorrentAdder
 	identifier      EpisodeIdentifier
-	treeUpdater     vfs.TreeUpdater // Optional
-	subtitleCreator SubtitleCreator // Optional
+	treeUpdater     vfs.TreeUpdater   // Optional
+	subtitleCreator SubtitleCreator   // Optional
+	fileGetter      TorrentFileGetter // Optional: enables subtitle content hashing
 	log             *slog.Logger
+
+	// Optional: OpenSubtitles downloads for newly assigned episodes
+	subtitleDownloader    SubtitleDownloader
+	autoSubtitleLanguages []string
+	showSettings          *library.ShowSettingsRepository // Per-show languages override autoSubtitleLanguages
+	jobQueue              *jobs.Queue                     // Bounds background downloads (nil = unlimited)
+
+	// assignSingleVideo assigns the only video file of an episode torrent
+	// without requiring its name to parse as the target episode.
+	assignSingleVideo bool
+
+	// minSeeders rejects new torrents with fewer connected seeders (0 = disabled)
+	minSeeders int
+
+	// Optional: bitrate/runtime probing of newly assigned files
+	mediaProber *MediaProber
 }
 
 // AssignmentServiceOption configures optional dependencies.
@@ -64,6 +106,13 @@ func WithSubtitleCreator(sc SubtitleCreator) AssignmentServiceOption {
 	}
 }
 
+// WithTorrentFileGetter configures content hashing of torrent subtitles.
+func WithTorrentFileGetter(fg TorrentFileGetter) AssignmentServiceOption {
+	return func(s *ShowAssignmentService) {
+		s.fileGetter = fg
+	}
+}
+
 // NewShowAssignmentService creates a new ShowAssignmentService.
 func NewShowAssignmentService(
 	showRepo *library.ShowRepository,
@@ -91,6 +140,64 @@ func (s *ShowAssignmentService) SetSubtitleCreator(sc SubtitleCreator) {
 	s.subtitleCreator = sc
 }
 
+// SetAssignSingleVideo enables the single-video fast path for episode assignments.
+func (s *ShowAssignmentService) SetAssignSingleVideo(enabled bool) {
+	s.assignSingleVideo = enabled
+}
+
+// SetMinSeeders rejects torrents that connect to fewer than n seeders shortly
+// after their metadata arrives. 0 disables the check.
+func (s *ShowAssignmentService) SetMinSeeders(n int) {
+	s.minSeeders = n
+}
+
+// addTorrent adds a torrent to assign and checks it has enough seeders
+func (s *ShowAssignmentService) addTorrent(ctx context.Context, magnetURI string) (*torrent.TorrentInfo, error) {
+	return admitTorrent(ctx, s.torrentAdder, magnetURI, s.minSeeders, s.hasActiveAssignments)
+}
+
+// hasActiveAssignments reports whether any item is assigned a file of infoHash
+func (s *ShowAssignmentService) hasActiveAssignments(infoHash string) (bool, error) {
+	assignments, err := s.assignmentRepo.GetActiveByInfoHash(infoHash)
+	return len(assignments) > 0, err
+}
+
+// admitTorrent adds a torrent and, when minSeeders is set, checks that a
+// torrent new to the library has enough seeders. Torrents that were already
+// loaded or assigned passed the check before and aren't checked again. A
+// rejected torrent is dropped along with its stored metadata.
+func admitTorrent(
+	ctx context.Context,
+	torrents TorrentAdder,
+	magnetURI string,
+	minSeeders int,
+	assigned func(infoHash string) (bool, error),
+) (*torrent.TorrentInfo, error) {
+	_, err := torrents.GetTorrent(torrent.ExtractInfoHash(magnetURI))
+	wasLoaded := err == nil
+
+	torrentInfo, err := torrents.AddTorrent(ctx, magnetURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add torrent: %w", err)
+	}
+	if minSeeders <= 0 || wasLoaded {
+		return torrentInfo, nil
+	}
+	if ok, err := assigned(torrentInfo.InfoHash); err != nil {
+		return nil, fmt.Errorf("failed to check assignments: %w", err)
+	} else if ok {
+		return torrentInfo, nil
+	}
+
+	if err := torrents.WaitForSeeders(ctx, torrentInfo.InfoHash, minSeeders); err != nil {
+		if rmErr := torrents.RemoveTorrent(torrentInfo.InfoHash, true); rmErr != nil && !errors.Is(rmErr, torrent.ErrTorrentNotFound) {
+			slog.Warn("Failed to drop rejected torrent", "info_hash", torrentInfo.InfoHash, "error", rmErr)
+		}
+		return nil, fmt.Errorf("torrent rejected: %w", err)
+	}
+	return torrentInfo, nil
+}
+
 // AssignmentSummary contains counts of the assignment operation.
 type AssignmentSummary struct {
 	TotalFiles     int `json:"total_files"`
@@ -109,6 +216,11 @@ type MatchedAssignment struct {
 	FileSize   int64  `json:"file_size"`
 	Resolution string `json:"resolution"`
 	Confidence string `json:"confidence"`
+
+	// Identifier details, for debugging mis-assignments
+	PatternUsed      string `json:"pattern_used,omitempty"`
+	SeasonFromFolder bool   `json:"season_from_folder"`
+	NeedsReview      bool   `json:"needs_review"`
 }
 
 // UnmatchedAssignment represents a file that couldn't be matched.
@@ -126,12 +238,17 @@ type ShowAssignmentResult struct {
 	Summary   AssignmentSummary
 }
 
-// AssignTorrent assigns a torrent to a show, auto-detecting episodes.
-func (s *ShowAssignmentService) AssignTorrent(
-	ctx context.Context,
-	showID int64,
-	magnetURI string,
-) (*ShowAssignmentResult, error) {
+// showTorrentMatch is a torrent identified and matched against a show's episodes.
+type showTorrentMatch struct {
+	show        *library.Show
+	infoHash    string
+	identResult *identify.IdentificationResult
+	matchResult *identify.MatchResult
+}
+
+// matchShowTorrent adds the torrent (to read its file list) and matches its files
+// to the show's episodes without changing the library.
+func (s *ShowAssignmentService) matchShowTorrent(ctx context.Context, showID int64, magnetURI string) (*showTorrentMatch, error) {
 	// 1. Validate magnet URI and extract info hash
 	infoHash := torrent.ExtractInfoHash(magnetURI)
 	if infoHash == "" {
@@ -153,16 +270,63 @@ func (s *ShowAssignmentService) AssignTorrent(
 	}
 
 	// 4. Add torrent and get file list
-	torrentInfo, err := s.torrentAdder.AddTorrent(magnetURI)
+	torrentInfo, err := s.addTorrent(ctx, magnetURI)
 	if err != nil {
-		return nil, fmt.Errorf("failed to add torrent: %w", err)
+		return nil, err
 	}
 
 	// 5. Identify episodes in the torrent
 	identResult := s.identifier.Identify(torrentInfo.Files, torrentInfo.Name)
 
 	// 6. Match identified files to library episodes
-	matchResult := identify.MatchToShow(show, identResult)
+	return &showTorrentMatch{
+		show:        show,
+		infoHash:    infoHash,
+		identResult: identResult,
+		matchResult: identify.MatchToShow(show, identResult),
+	}, nil
+}
+
+// PreviewTorrent reports what AssignTorrent would match without creating
+// assignments, subtitles or VFS entries. The torrent itself is still added
+// to the client since its file list is needed; callers release it.
+func (s *ShowAssignmentService) PreviewTorrent(
+	ctx context.Context,
+	showID int64,
+	magnetURI string,
+) (*ShowAssignmentResult, error) {
+	m, err := s.matchShowTorrent(ctx, showID, magnetURI)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ShowAssignmentResult{
+		Matched:   make([]MatchedAssignment, 0, len(m.matchResult.Matched)),
+		Unmatched: make([]UnmatchedAssignment, 0, len(m.matchResult.Unmatched)),
+	}
+	for _, match := range m.matchResult.Matched {
+		result.Matched = append(result.Matched, toMatchedAssignment(match))
+	}
+	for _, u := range m.matchResult.Unmatched {
+		result.Unmatched = append(result.Unmatched, toUnmatchedAssignment(u))
+	}
+	result.Summary = summarize(m.identResult, len(result.Matched), len(result.Unmatched), len(m.matchResult.MatchedSubtitles))
+
+	return result, nil
+}
+
+// AssignTorrent assigns a torrent to a show, auto-detecting episodes.
+func (s *ShowAssignmentService) AssignTorrent(
+	ctx context.Context,
+	showID int64,
+	magnetURI string,
+) (*ShowAssignmentResult, error) {
+	// 1-6. Identify the torrent's files and match them to episodes
+	match, err := s.matchShowTorrent(ctx, showID, magnetURI)
+	if err != nil {
+		return nil, err
+	}
+	show, infoHash, identResult, matchResult := match.show, match.infoHash, match.identResult, match.matchResult
 
 	// 7. Create assignments for matched episodes
 	result := &ShowAssignmentResult{
@@ -171,6 +335,8 @@ func (s *ShowAssignmentService) AssignTorrent(
 	}
 
 	episodesForTree := make([]vfs.EpisodeWithContext, 0, len(matchResult.Matched))
+	subtitleTargets := make([]autoSubtitleTarget, 0, len(matchResult.Matched))
+	created := make([]*library.TorrentAssignment, 0, len(matchResult.Matched))
 
 	for _, m := range matchResult.Matched {
 		assignment := &library.TorrentAssignment{
@@ -182,6 +348,15 @@ func (s *ShowAssignmentService) AssignTorrent(
 			FileSize:   m.FileSize,
 			Resolution: m.Quality.Resolution,
 			Source:     m.Quality.Source,
+			BitDepth:   m.Quality.BitDepth,
+			Codec:      m.Quality.Codec,
+			AudioCodec: m.Quality.AudioCodec,
+			HDR:        m.Quality.HDR,
+
+			Confidence:       string(m.Confidence),
+			PatternUsed:      m.PatternUsed,
+			SeasonFromFolder: m.SeasonFromFolder,
+			NeedsReview:      m.NeedsReview,
 		}
 
 		if err := s.assignmentRepo.Create(assignment); err != nil {
@@ -192,15 +367,8 @@ func (s *ShowAssignmentService) AssignTorrent(
 			continue
 		}
 
-		result.Matched = append(result.Matched, MatchedAssignment{
-			EpisodeID:  m.Episode.ID,
-			Season:     m.Season.SeasonNumber,
-			Episode:    m.Episode.EpisodeNumber,
-			FilePath:   m.FilePath,
-			FileSize:   m.FileSize,
-			Resolution: m.Quality.Resolution,
-			Confidence: string(m.Confidence),
-		})
+		result.Matched = append(result.Matched, toMatchedAssignment(m))
+		created = append(created, assignment)
 
 		episodesForTree = append(episodesForTree, vfs.EpisodeWithContext{
 			ShowTitle:    show.Title,
@@ -208,6 +376,18 @@ func (s *ShowAssignmentService) AssignTorrent(
 			SeasonNumber: m.Season.SeasonNumber,
 			Episode:      m.Episode,
 			Assignment:   assignment,
+
+			ShowPosterURL:   show.PosterURL,
+			ShowBackdropURL: show.BackdropURL,
+			ShowCreatedAt:   show.CreatedAt,
+			ShowTMDBID:      show.TMDBID,
+			ShowOverview:    show.Overview,
+		})
+
+		subtitleTargets = append(subtitleTargets, autoSubtitleTarget{
+			EpisodeID: m.Episode.ID,
+			Season:    m.Season.SeasonNumber,
+			Episode:   m.Episode.EpisodeNumber,
 		})
 	}
 
@@ -226,14 +406,14 @@ func (s *ShowAssignmentService) AssignTorrent(
 		}
 	}
 
-	// 10. Build unmatched response
+	// 10. Download subtitles in languages the torrent didn't provide and
+	// probe bitrate/runtime of the assigned files (background)
+	s.startAutoSubtitles(ctx, show, subtitleTargets)
+	s.mediaProber.Probe(created...)
+
+	// 11. Build unmatched response
 	for _, u := range matchResult.Unmatched {
-		result.Unmatched = append(result.Unmatched, UnmatchedAssignment{
-			FilePath: u.FilePath,
-			Reason:   string(u.Reason),
-			Season:   u.Season,
-			Episode:  u.Episode,
-		})
+		result.Unmatched = append(result.Unmatched, toUnmatchedAssignment(u))
 
 		s.log.Warn("Unmatched file in torrent",
 			"show_id", showID,
@@ -246,30 +426,218 @@ func (s *ShowAssignmentService) AssignTorrent(
 		)
 	}
 
-	// 11. Calculate summary
+	// 12. Calculate summary
+	result.Summary = summarize(identResult, len(result.Matched), len(result.Unmatched), subtitlesCreated)
+
+	return result, nil
+}
+
+// summarize builds the assignment summary counts
+func summarize(identResult *identify.IdentificationResult, matched, unmatched, subtitles int) AssignmentSummary {
 	skipped := identResult.TotalFiles - len(identResult.IdentifiedFiles) - len(identResult.UnidentifiedFiles)
 	if skipped < 0 {
 		skipped = 0
 	}
 
-	result.Summary = AssignmentSummary{
+	return AssignmentSummary{
 		TotalFiles:     identResult.TotalFiles,
-		Matched:        len(result.Matched),
-		Unmatched:      len(result.Unmatched),
+		Matched:        matched,
+		Unmatched:      unmatched,
 		Skipped:        skipped,
-		SubtitlesFound: subtitlesCreated,
+		SubtitlesFound: subtitles,
 	}
+}
 
-	return result, nil
+func toMatchedAssignment(m identify.MatchedEpisode) MatchedAssignment {
+	return MatchedAssignment{
+		EpisodeID:  m.Episode.ID,
+		Season:     m.Season.SeasonNumber,
+		Episode:    m.Episode.EpisodeNumber,
+		FilePath:   m.FilePath,
+		FileSize:   m.FileSize,
+		Resolution: m.Quality.Resolution,
+		Confidence: string(m.Confidence),
+
+		PatternUsed:      m.PatternUsed,
+		SeasonFromFolder: m.SeasonFromFolder,
+		NeedsReview:      m.NeedsReview,
+	}
+}
+
+func toUnmatchedAssignment(u identify.UnmatchedFile) UnmatchedAssignment {
+	return UnmatchedAssignment{
+		FilePath: u.FilePath,
+		Reason:   string(u.Reason),
+		Season:   u.Season,
+		Episode:  u.Episode,
+	}
+}
+
+// confidenceSingleVideo marks episode assignments made by the single-video fast path.
+const confidenceSingleVideo = "single_video"
+
+// AssignEpisodeTorrent assigns a torrent file to a specific episode.
+// When the fast path is enabled and the torrent has exactly one video file, that
+// file is assigned directly since the caller already chose the episode. Otherwise
+// the largest video file identified as the episode's SxxExx is used.
+func (s *ShowAssignmentService) AssignEpisodeTorrent(
+	ctx context.Context,
+	episodeID int64,
+	magnetURI string,
+) (*MatchedAssignment, error) {
+	infoHash := torrent.ExtractInfoHash(magnetURI)
+	if infoHash == "" {
+		return nil, library.ErrInvalidMagnet
+	}
+
+	episode, err := s.showRepo.GetEpisodeByID(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode: %w", err)
+	}
+	if episode == nil {
+		return nil, library.ErrEpisodeNotFound
+	}
+	epCtx, err := s.showRepo.GetEpisodeContext(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode context: %w", err)
+	}
+	if epCtx == nil {
+		return nil, library.ErrEpisodeNotFound
+	}
+
+	if s.torrentAdder == nil {
+		return nil, library.ErrTorrentServiceUnavailable
+	}
+
+	torrentInfo, err := s.addTorrent(ctx, magnetURI)
+	if err != nil {
+		return nil, err
+	}
+
+	video := s.identifier.FindMovieFile(torrentInfo.Files)
+	if !video.Found {
+		return nil, library.ErrNoVideoFiles
+	}
+
+	// Fast path: a lone video file is the episode the caller asked for, whatever its name.
+	// Multi-part sets are movie-only, so their files are identified individually.
+	filePath, fileSize, quality, confidence := video.FilePath, video.FileSize, video.Quality, confidenceSingleVideo
+	var identified *identify.IdentifiedFile
+	var identResult *identify.IdentificationResult
+	if !s.assignSingleVideo || len(video.OtherFiles) > 0 || len(video.Parts) > 0 {
+		identResult = s.identifier.Identify(torrentInfo.Files, torrentInfo.Name)
+		identified = findEpisodeFile(identResult, epCtx.SeasonNumber, epCtx.EpisodeNumber)
+		if identified == nil {
+			return nil, library.ErrNoMatchingFile
+		}
+		filePath, fileSize, quality, confidence = identified.FilePath, identified.FileSize, identified.Quality, string(identified.Confidence)
+	}
+
+	assignment := &library.TorrentAssignment{
+		ItemType:   library.ItemTypeEpisode,
+		ItemID:     episode.ID,
+		InfoHash:   infoHash,
+		MagnetURI:  magnetURI,
+		FilePath:   filePath,
+		FileSize:   fileSize,
+		Resolution: quality.Resolution,
+		Source:     quality.Source,
+		BitDepth:   quality.BitDepth,
+		Codec:      quality.Codec,
+		AudioCodec: quality.AudioCodec,
+		HDR:        quality.HDR,
+		Confidence: confidence,
+	}
+	if identified != nil {
+		assignment.PatternUsed = identified.PatternUsed
+		assignment.SeasonFromFolder = identified.SeasonFromFolder
+		assignment.NeedsReview = identified.NeedsReview
+	}
+	if err := s.assignmentRepo.Create(assignment); err != nil {
+		return nil, err
+	}
+	s.mediaProber.Probe(assignment)
+
+	s.log.Info("Episode torrent assigned",
+		"episode_id", episode.ID,
+		"season", epCtx.SeasonNumber,
+		"episode", epCtx.EpisodeNumber,
+		"file_path", filePath,
+		"confidence", confidence,
+	)
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.AddEpisodesToTree([]vfs.EpisodeWithContext{{
+			ShowTitle:    epCtx.ShowTitle,
+			ShowYear:     epCtx.ShowYear,
+			SeasonNumber: epCtx.SeasonNumber,
+			Episode:      episode,
+			Assignment:   assignment,
+
+			ShowTMDBID:   epCtx.ShowTMDBID,
+			ShowOverview: epCtx.ShowOverview,
+		}})
+	}
+
+	// Subtitles shipped in the torrent for this episode, as for whole-show assignments
+	if s.subtitleCreator != nil {
+		if identResult == nil {
+			identResult = s.identifier.Identify(torrentInfo.Files, torrentInfo.Name)
+		}
+		subs := episodeSubtitles(identResult, episode, epCtx.SeasonNumber)
+		if len(subs) > 0 && s.createSubtitles(ctx, subs, infoHash) > 0 && s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+		}
+	}
+
+	return &MatchedAssignment{
+		EpisodeID:  episode.ID,
+		Season:     epCtx.SeasonNumber,
+		Episode:    epCtx.EpisodeNumber,
+		FilePath:   filePath,
+		FileSize:   fileSize,
+		Resolution: quality.Resolution,
+		Confidence: confidence,
+	}, nil
+}
+
+// episodeSubtitles returns the identified subtitle files of a single episode
+func episodeSubtitles(result *identify.IdentificationResult, episode *library.Episode, seasonNumber int) []identify.MatchedSubtitle {
+	show := &library.Show{Seasons: []library.Season{{
+		ID:           episode.SeasonID,
+		SeasonNumber: seasonNumber,
+		Episodes:     []library.Episode{*episode},
+	}}}
+	return identify.MatchToShow(show, result).MatchedSubtitles
 }
 
-// createSubtitles creates subtitle records for matched subtitles.
+// findEpisodeFile returns the largest plausible video file identified as the given episode.
+func findEpisodeFile(result *identify.IdentificationResult, season, episode int) *identify.IdentifiedFile {
+	var best *identify.IdentifiedFile
+	for idx := range result.IdentifiedFiles {
+		f := &result.IdentifiedFiles[idx]
+		if f.FileType != identify.FileTypeVideo || f.Implausible || f.Season != season {
+			continue
+		}
+		if !slices.Contains(f.Episodes, episode) {
+			continue
+		}
+		if best == nil || f.FileSize > best.FileSize {
+			best = f
+		}
+	}
+	return best
+}
+
+// createSubtitles creates subtitle records for matched subtitles. Their
+// content is hashed in the background, since the torrent may take a while to
+// deliver it.
 func (s *ShowAssignmentService) createSubtitles(
 	ctx context.Context,
 	matched []identify.MatchedSubtitle,
 	infoHash string,
 ) int {
-	created := 0
+	created := make([]*subtitle.Subtitle, 0, len(matched))
 
 	for _, ms := range matched {
 		sub := &subtitle.Subtitle{
@@ -282,6 +650,9 @@ func (s *ShowAssignmentService) createSubtitles(
 			FileSize:     ms.FileSize,
 			Source:       subtitle.SourceTorrent,
 			InfoHash:     infoHash,
+
+			Forced:          ms.Forced,
+			HearingImpaired: ms.HearingImpaired,
 		}
 
 		if err := s.subtitleCreator.CreateTorrentSubtitle(ctx, sub); err != nil {
@@ -293,7 +664,7 @@ func (s *ShowAssignmentService) createSubtitles(
 			continue
 		}
 
-		created++
+		created = append(created, sub)
 		s.log.Info("Torrent subtitle assigned",
 			"episode_id", ms.Episode.ID,
 			"season", ms.Season.SeasonNumber,
@@ -303,5 +674,74 @@ func (s *ShowAssignmentService) createSubtitles(
 		)
 	}
 
-	return created
+	if s.fileGetter != nil && len(created) > 0 {
+		go s.hashSubtitles(created)
+	}
+
+	return len(created)
+}
+
+// hashSubtitles records the content hash of newly created torrent subtitles,
+// refreshing the tree if that merged any of them with an identical subtitle
+func (s *ShowAssignmentService) hashSubtitles(subs []*subtitle.Subtitle) {
+	ctx := context.Background()
+	removed := false
+	for _, sub := range subs {
+		hash := s.hashTorrentFile(sub.InfoHash, sub.FilePath, sub.FileSize)
+		if hash == "" {
+			continue
+		}
+		merged, err := s.subtitleCreator.SetContentHash(ctx, sub, hash)
+		if err != nil {
+			s.log.Error("Failed to record torrent subtitle hash",
+				"subtitle_id", sub.ID,
+				"file_path", sub.FilePath,
+				"error", err,
+			)
+			continue
+		}
+		removed = removed || merged
+	}
+
+	if removed && s.treeUpdater != nil {
+		s.treeUpdater.InvalidateTree()
+	}
+}
+
+// hashTorrentFile reads a subtitle from the torrent and returns its content hash.
+// Returns an empty string if hashing is unavailable or the read does not finish in time.
+func (s *ShowAssignmentService) hashTorrentFile(infoHash, filePath string, size int64) string {
+	if s.fileGetter == nil || size <= 0 || size > maxSubtitleHashSize {
+		return ""
+	}
+
+	handle, err := s.fileGetter.GetFile(infoHash, filePath)
+	if err != nil {
+		s.log.Debug("Failed to open torrent subtitle for hashing", "file_path", filePath, "error", err)
+		return ""
+	}
+
+	reader := handle.NewReader()
+	done := make(chan []byte, 1)
+	go func() {
+		content, err := io.ReadAll(io.LimitReader(reader, size))
+		if err != nil {
+			content = nil
+		}
+		done <- content
+	}()
+
+	select {
+	case content := <-done:
+		reader.Close()
+		if content == nil {
+			return ""
+		}
+		return subtitle.ContentHash(content)
+	case <-time.After(subtitleHashTimeout):
+		// Closing the reader unblocks the pending read
+		reader.Close()
+		s.log.Debug("Timed out hashing torrent subtitle", "file_path", filePath)
+		return ""
+	}
 }
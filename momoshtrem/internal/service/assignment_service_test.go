@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// seederTorrents is a torrent client whose torrents have too few seeders
+type seederTorrents struct {
+	loaded  map[string]bool
+	checked bool
+	removed []string
+}
+
+func (f *seederTorrents) AddTorrent(_ context.Context, magnetURI string) (*torrent.TorrentInfo, error) {
+	hash := torrent.ExtractInfoHash(magnetURI)
+	f.loaded[hash] = true
+	return &torrent.TorrentInfo{InfoHash: hash}, nil
+}
+
+func (f *seederTorrents) GetTorrent(infoHash string) (*torrent.TorrentInfo, error) {
+	if !f.loaded[infoHash] {
+		return nil, torrent.ErrTorrentNotFound
+	}
+	return &torrent.TorrentInfo{InfoHash: infoHash}, nil
+}
+
+func (f *seederTorrents) WaitForSeeders(_ context.Context, _ string, minSeeders int) error {
+	f.checked = true
+	return &torrent.InsufficientSeedersError{Seeders: 0, MinSeeders: minSeeders}
+}
+
+func (f *seederTorrents) RemoveTorrent(infoHash string, _ bool) error {
+	delete(f.loaded, infoHash)
+	f.removed = append(f.removed, infoHash)
+	return nil
+}
+
+func TestAdmitTorrent(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	const magnet = "magnet:?xt=urn:btih:" + hash
+	notAssigned := func(string) (bool, error) { return false, nil }
+
+	t.Run("new torrent rejected and dropped", func(t *testing.T) {
+		torrents := &seederTorrents{loaded: map[string]bool{}}
+		_, err := admitTorrent(context.Background(), torrents, magnet, 2, notAssigned)
+
+		var seedersErr *torrent.InsufficientSeedersError
+		if !errors.As(err, &seedersErr) {
+			t.Fatalf("admitTorrent() error = %v, want InsufficientSeedersError", err)
+		}
+		if torrents.loaded[hash] || len(torrents.removed) != 1 {
+			t.Errorf("rejected torrent not dropped: loaded %v, removed %v", torrents.loaded, torrents.removed)
+		}
+	})
+
+	t.Run("already loaded torrent not checked", func(t *testing.T) {
+		torrents := &seederTorrents{loaded: map[string]bool{hash: true}}
+		if _, err := admitTorrent(context.Background(), torrents, magnet, 2, notAssigned); err != nil {
+			t.Fatalf("admitTorrent() error = %v", err)
+		}
+		if torrents.checked || len(torrents.removed) != 0 {
+			t.Errorf("loaded torrent checked = %v, removed %v", torrents.checked, torrents.removed)
+		}
+	})
+
+	t.Run("assigned torrent not checked", func(t *testing.T) {
+		torrents := &seederTorrents{loaded: map[string]bool{}}
+		assigned := func(string) (bool, error) { return true, nil }
+		if _, err := admitTorrent(context.Background(), torrents, magnet, 2, assigned); err != nil {
+			t.Fatalf("admitTorrent() error = %v", err)
+		}
+		if torrents.checked || !torrents.loaded[hash] {
+			t.Errorf("assigned torrent checked = %v, loaded = %v", torrents.checked, torrents.loaded[hash])
+		}
+	})
+
+	t.Run("check disabled", func(t *testing.T) {
+		torrents := &seederTorrents{loaded: map[string]bool{}}
+		if _, err := admitTorrent(context.Background(), torrents, magnet, 0, notAssigned); err != nil || torrents.checked {
+			t.Errorf("admitTorrent() = %v, checked = %v; want no check", err, torrents.checked)
+		}
+	})
+}
+
+func TestEpisodeSubtitles(t *testing.T) {
+	identifier := identify.NewIdentifier(nil, identify.DefaultConfig())
+	result := identifier.Identify([]identify.TorrentFile{
+		{Path: "Show.S01/Show.S01E02.mkv", Size: 500 << 20},
+		{Path: "Show.S01/Show.S01E02.en.srt", Size: 50 << 10},
+		{Path: "Show.S01/Show.S01E02.es.forced.srt", Size: 10 << 10},
+		{Path: "Show.S01/Show.S01E03.en.srt", Size: 50 << 10},
+	}, "Show.S01")
+
+	episode := &library.Episode{ID: 12, SeasonID: 1, EpisodeNumber: 2}
+	subs := episodeSubtitles(result, episode, 1)
+
+	got := make(map[string]bool)
+	for _, s := range subs {
+		if s.Episode.ID != episode.ID {
+			t.Errorf("%s matched episode %d, want %d", s.FilePath, s.Episode.ID, episode.ID)
+		}
+		got[s.LanguageCode] = s.Forced
+	}
+	if len(subs) != 2 || got["en"] || !got["es"] {
+		t.Errorf("subtitles = %+v, want E02's en and forced es only", subs)
+	}
+}
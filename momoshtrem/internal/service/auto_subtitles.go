@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/jobs"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
+	"github.com/shapedtime/momoshtrem/internal/subtitle"
+)
+
+// SubtitleDownloader defines the OpenSubtitles operations used to fetch
+// subtitles for newly assigned episodes.
+type SubtitleDownloader interface {
+	Search(ctx context.Context, params opensubtitles.SearchParams) (*opensubtitles.SearchResponse, error)
+	DownloadAndStore(ctx context.Context, itemType subtitle.ItemType, itemID int64, fileID int, languageCode, languageName string) (*subtitle.Subtitle, error)
+	GetByItem(ctx context.Context, itemType subtitle.ItemType, itemID int64) ([]*subtitle.Subtitle, error)
+}
+
+// Compile-time verification
+var _ SubtitleDownloader = (*subtitle.Service)(nil)
+
+// autoSubtitleTimeout bounds one background download pass for an assignment
+const autoSubtitleTimeout = 10 * time.Minute
+
+// WithSubtitleDownloader configures automatic subtitle downloads for new assignments.
+func WithSubtitleDownloader(sd SubtitleDownloader) AssignmentServiceOption {
+	return func(s *ShowAssignmentService) {
+		s.subtitleDownloader = sd
+	}
+}
+
+// WithAutoSubtitleLanguages sets the languages downloaded for newly assigned episodes.
+func WithAutoSubtitleLanguages(languages []string) AssignmentServiceOption {
+	return func(s *ShowAssignmentService) {
+		s.autoSubtitleLanguages = languages
+	}
+}
+
+// SetSubtitleDownloader configures the subtitle downloader after construction.
+func (s *ShowAssignmentService) SetSubtitleDownloader(sd SubtitleDownloader) {
+	s.subtitleDownloader = sd
+}
+
+// SetAutoSubtitleLanguages sets the languages downloaded for newly assigned episodes.
+func (s *ShowAssignmentService) SetAutoSubtitleLanguages(languages []string) {
+	s.autoSubtitleLanguages = languages
+}
+
+// SetShowSettings configures per-show subtitle languages after construction.
+func (s *ShowAssignmentService) SetShowSettings(repo *library.ShowSettingsRepository) {
+	s.showSettings = repo
+}
+
+// SetJobQueue runs background subtitle downloads as jobs of the shared queue.
+func (s *ShowAssignmentService) SetJobQueue(q *jobs.Queue) {
+	s.jobQueue = q
+}
+
+// autoSubtitleTarget is a newly assigned episode to fetch subtitles for.
+type autoSubtitleTarget struct {
+	EpisodeID int64
+	Season    int
+	Episode   int
+}
+
+// startAutoSubtitles queues a background job downloading subtitles for newly
+// assigned episodes, once their assignments are committed. Each of the show's
+// languages is skipped when the episode already has a subtitle in it (such as
+// one just found in the torrent). The VFS tree is invalidated once at the end.
+// Downloads are skipped when the job queue is full.
+func (s *ShowAssignmentService) startAutoSubtitles(ctx context.Context, show *library.Show, targets []autoSubtitleTarget) {
+	if s.subtitleDownloader == nil || len(targets) == 0 {
+		return
+	}
+	languages := s.subtitleLanguagesForShow(show.ID)
+	if len(languages) == 0 {
+		return
+	}
+
+	// Outlive the request that made the assignment
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), autoSubtitleTimeout)
+	err := s.jobQueue.Submit(func() {
+		defer cancel()
+
+		downloaded := s.downloadAutoSubtitles(ctx, show.TMDBID, languages, targets)
+		if downloaded > 0 && s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+		}
+	})
+	if err != nil {
+		cancel()
+		s.log.Warn("Subtitle auto-download skipped", "show_id", show.ID, "episodes", len(targets), "error", err)
+	}
+}
+
+// downloadAutoSubtitles fetches missing subtitles for each target, best-effort.
+// Returns the number of subtitles stored.
+func (s *ShowAssignmentService) downloadAutoSubtitles(ctx context.Context, showTMDBID int, languages []string, targets []autoSubtitleTarget) int {
+	downloaded := 0
+	results, _ := s.downloadEpisodeSubtitles(ctx, showTMDBID, languages, targets)
+	for _, result := range results {
+		downloaded += len(result.Downloaded)
+	}
+	return downloaded
+}
+
+// EpisodeSubtitleResult is what a subtitle download pass did for one episode.
+type EpisodeSubtitleResult struct {
+	EpisodeID  int64    `json:"episode_id"`
+	Season     int      `json:"season"`
+	Episode    int      `json:"episode"`
+	Downloaded []string `json:"downloaded,omitempty"` // Languages stored
+	Existing   []string `json:"existing,omitempty"`   // Languages the episode already had
+	NotFound   []string `json:"not_found,omitempty"`  // Languages with no OpenSubtitles result
+	Failed     []string `json:"failed,omitempty"`     // Languages whose search or download failed
+}
+
+// subtitleRequestDelay spaces out OpenSubtitles searches for consecutive
+// episodes to stay under the API's rate limit. A var so tests can shorten it.
+var subtitleRequestDelay = time.Second
+
+// downloadEpisodeSubtitles searches once per target for its missing languages
+// and stores the best result in each. Stops early when ctx is done, or with
+// the quota error once the OpenSubtitles download quota is used up.
+func (s *ShowAssignmentService) downloadEpisodeSubtitles(ctx context.Context, showTMDBID int, languages []string, targets []autoSubtitleTarget) ([]EpisodeSubtitleResult, error) {
+	results := make([]EpisodeSubtitleResult, 0, len(targets))
+	searched := false
+
+	for _, t := range targets {
+		if ctx.Err() != nil {
+			s.log.Warn("Subtitle download stopped", "error", ctx.Err())
+			break
+		}
+
+		result := EpisodeSubtitleResult{EpisodeID: t.EpisodeID, Season: t.Season, Episode: t.Episode}
+		missing := s.missingSubtitleLanguages(ctx, t.EpisodeID, languages)
+		for _, lang := range languages {
+			if !slices.Contains(missing, lang) {
+				result.Existing = append(result.Existing, lang)
+			}
+		}
+		if len(missing) == 0 {
+			results = append(results, result)
+			continue
+		}
+
+		if searched {
+			select {
+			case <-ctx.Done():
+				s.log.Warn("Subtitle download stopped", "error", ctx.Err())
+				return results, nil
+			case <-time.After(subtitleRequestDelay):
+			}
+		}
+		searched = true
+
+		resp, err := s.subtitleDownloader.Search(ctx, opensubtitles.SearchParams{
+			TMDBID:        showTMDBID,
+			Type:          "episode",
+			SeasonNumber:  t.Season,
+			EpisodeNumber: t.Episode,
+			Languages:     missing,
+		})
+		if err != nil {
+			s.log.Warn("Subtitle search failed",
+				"episode_id", t.EpisodeID,
+				"season", t.Season,
+				"episode", t.Episode,
+				"error", err,
+			)
+			result.Failed = missing
+			results = append(results, result)
+			continue
+		}
+
+		for _, lang := range missing {
+			fileID := bestSubtitleFile(resp.Data, lang)
+			if fileID == 0 {
+				s.log.Debug("No subtitle found to download",
+					"episode_id", t.EpisodeID,
+					"language", lang,
+				)
+				result.NotFound = append(result.NotFound, lang)
+				continue
+			}
+
+			_, err := s.subtitleDownloader.DownloadAndStore(ctx, subtitle.ItemTypeEpisode, t.EpisodeID, fileID, lang, opensubtitles.GetLanguageName(lang))
+			var quotaErr *opensubtitles.QuotaExhaustedError
+			if errors.As(err, &quotaErr) {
+				// Every further download would fail the same way
+				s.log.Warn("Subtitle download stopped", "error", quotaErr)
+				result.Failed = append(result.Failed, lang)
+				return append(results, result), quotaErr
+			}
+			if err != nil {
+				s.log.Warn("Subtitle download failed",
+					"episode_id", t.EpisodeID,
+					"language", lang,
+					"file_id", fileID,
+					"error", err,
+				)
+				result.Failed = append(result.Failed, lang)
+				continue
+			}
+
+			result.Downloaded = append(result.Downloaded, lang)
+			s.log.Info("Subtitle downloaded",
+				"episode_id", t.EpisodeID,
+				"season", t.Season,
+				"episode", t.Episode,
+				"language", lang,
+			)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// DownloadSeasonSubtitles downloads subtitles in languages for every episode
+// of a season that lacks them, best-effort and one episode at a time. The
+// VFS tree is invalidated once at the end if anything was stored. A
+// *opensubtitles.QuotaExhaustedError is returned with the episodes done so
+// far when the download quota runs out.
+func (s *ShowAssignmentService) DownloadSeasonSubtitles(ctx context.Context, show *library.Show, seasonNumber int, episodes []library.Episode, languages []string) ([]EpisodeSubtitleResult, error) {
+	if s.subtitleDownloader == nil {
+		return nil, nil
+	}
+	targets := make([]autoSubtitleTarget, 0, len(episodes))
+	for _, ep := range episodes {
+		targets = append(targets, autoSubtitleTarget{EpisodeID: ep.ID, Season: seasonNumber, Episode: ep.EpisodeNumber})
+	}
+
+	results, err := s.downloadEpisodeSubtitles(ctx, show.TMDBID, languages, targets)
+	for _, result := range results {
+		if len(result.Downloaded) > 0 && s.treeUpdater != nil {
+			s.treeUpdater.InvalidateTree()
+			break
+		}
+	}
+	return results, err
+}
+
+// subtitleLanguagesForShow returns the languages to download for a show: its
+// own preference when set, otherwise the configured auto-download languages.
+func (s *ShowAssignmentService) subtitleLanguagesForShow(showID int64) []string {
+	if s.showSettings == nil {
+		return s.autoSubtitleLanguages
+	}
+
+	settings, err := s.showSettings.Get(showID)
+	if err != nil {
+		s.log.Warn("Failed to load show settings for auto download", "show_id", showID, "error", err)
+		return s.autoSubtitleLanguages
+	}
+	if settings == nil || len(settings.PreferredSubtitleLangs) == 0 {
+		return s.autoSubtitleLanguages
+	}
+	return settings.PreferredSubtitleLangs
+}
+
+// missingSubtitleLanguages returns the languages the episode has no full subtitle in.
+// A forced track only covers foreign-language parts, so it doesn't count.
+func (s *ShowAssignmentService) missingSubtitleLanguages(ctx context.Context, episodeID int64, languages []string) []string {
+	existing, err := s.subtitleDownloader.GetByItem(ctx, subtitle.ItemTypeEpisode, episodeID)
+	if err != nil {
+		s.log.Warn("Failed to list subtitles for auto download", "episode_id", episodeID, "error", err)
+		return nil
+	}
+
+	var missing []string
+	for _, lang := range languages {
+		found := false
+		for _, sub := range existing {
+			if !sub.Forced && strings.EqualFold(sub.LanguageCode, lang) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, lang)
+		}
+	}
+	return missing
+}
+
+// bestSubtitleFile picks the most downloaded result in a language, preferring
+// full subtitles over foreign-parts-only ones, then human translations, then
+// plain tracks over hearing-impaired ones. Returns 0 if there is none.
+func bestSubtitleFile(results []opensubtitles.SubtitleResult, lang string) int {
+	var best *opensubtitles.SubtitleAttributes
+	for i := range results {
+		attrs := &results[i].Attributes
+		if !strings.EqualFold(attrs.Language, lang) || len(attrs.Files) == 0 {
+			continue
+		}
+		if best == nil || betterSubtitle(attrs, best) {
+			best = attrs
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return best.Files[0].FileID
+}
+
+func betterSubtitle(a, b *opensubtitles.SubtitleAttributes) bool {
+	// A foreign-parts-only track doesn't cover the episode's dialogue
+	if a.ForeignPartsOnly != b.ForeignPartsOnly {
+		return !a.ForeignPartsOnly
+	}
+	aMachine := a.MachineTranslated || a.AITranslated
+	bMachine := b.MachineTranslated || b.AITranslated
+	if aMachine != bMachine {
+		return !aMachine
+	}
+	if a.HearingImpaired != b.HearingImpaired {
+		return !a.HearingImpaired
+	}
+	return a.DownloadCount > b.DownloadCount
+}
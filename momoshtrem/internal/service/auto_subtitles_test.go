@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/jobs"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/opensubtitles"
+	"github.com/shapedtime/momoshtrem/internal/subtitle"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// fakeDownloader serves canned subtitles per episode and offers one result
+// for every language searched
+type fakeDownloader struct {
+	mu       sync.Mutex
+	existing map[int64][]*subtitle.Subtitle // Stored subtitles by episode ID
+	searched map[int64][]string             // Languages searched by episode number
+	stored   map[int64][]string             // Languages downloaded by episode ID
+}
+
+func newFakeDownloader() *fakeDownloader {
+	return &fakeDownloader{
+		existing: make(map[int64][]*subtitle.Subtitle),
+		searched: make(map[int64][]string),
+		stored:   make(map[int64][]string),
+	}
+}
+
+func (f *fakeDownloader) Search(_ context.Context, params opensubtitles.SearchParams) (*opensubtitles.SearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.searched[int64(params.EpisodeNumber)] = append(f.searched[int64(params.EpisodeNumber)], params.Languages...)
+
+	resp := &opensubtitles.SearchResponse{}
+	for i, lang := range params.Languages {
+		resp.Data = append(resp.Data, opensubtitles.SubtitleResult{Attributes: opensubtitles.SubtitleAttributes{
+			Language: lang,
+			Files:    []opensubtitles.SubtitleFile{{FileID: i + 1}},
+		}})
+	}
+	return resp, nil
+}
+
+func (f *fakeDownloader) DownloadAndStore(_ context.Context, _ subtitle.ItemType, itemID int64, _ int, languageCode, _ string) (*subtitle.Subtitle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stored[itemID] = append(f.stored[itemID], languageCode)
+	return &subtitle.Subtitle{ItemID: itemID, LanguageCode: languageCode}, nil
+}
+
+func (f *fakeDownloader) GetByItem(_ context.Context, _ subtitle.ItemType, itemID int64) ([]*subtitle.Subtitle, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.existing[itemID], nil
+}
+
+// invalidations signals each full tree rebuild
+type invalidations struct {
+	vfs.TreeUpdater
+	ch chan struct{}
+}
+
+func (i *invalidations) InvalidateTree() { i.ch <- struct{}{} }
+
+func TestStartAutoSubtitlesChecksEachLanguage(t *testing.T) {
+	downloader := newFakeDownloader()
+	// A full English track and a forced Spanish one: only Spanish is missing
+	downloader.existing[10] = []*subtitle.Subtitle{
+		{LanguageCode: "en"},
+		{LanguageCode: "es", Forced: true},
+	}
+	tree := &invalidations{ch: make(chan struct{}, 1)}
+	s := NewShowAssignmentService(nil, nil, nil, nil,
+		WithSubtitleDownloader(downloader),
+		WithAutoSubtitleLanguages([]string{"en", "es"}),
+		WithTreeUpdater(tree),
+	)
+	s.SetJobQueue(jobs.NewQueue(jobs.Config{Workers: 1}))
+
+	s.startAutoSubtitles(context.Background(), &library.Show{ID: 1, TMDBID: 100},
+		[]autoSubtitleTarget{{EpisodeID: 10, Season: 1, Episode: 1}})
+
+	select {
+	case <-tree.ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto-download job never finished")
+	}
+	downloader.mu.Lock()
+	defer downloader.mu.Unlock()
+	if got := downloader.searched[1]; !slices.Equal(got, []string{"es"}) {
+		t.Errorf("searched languages = %v, want only the missing es", got)
+	}
+	if got := downloader.stored[10]; !slices.Equal(got, []string{"es"}) {
+		t.Errorf("stored languages = %v, want es", got)
+	}
+}
+
+func TestStartAutoSubtitlesSkippedWhenQueueFull(t *testing.T) {
+	downloader := newFakeDownloader()
+	s := NewShowAssignmentService(nil, nil, nil, nil,
+		WithSubtitleDownloader(downloader),
+		WithAutoSubtitleLanguages([]string{"en"}),
+	)
+	q := jobs.NewQueue(jobs.Config{Workers: 1})
+	s.SetJobQueue(q)
+
+	release, err := q.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	s.startAutoSubtitles(context.Background(), &library.Show{ID: 1, TMDBID: 100},
+		[]autoSubtitleTarget{{EpisodeID: 10, Season: 1, Episode: 1}})
+
+	if got := q.Rejected(); got != 1 {
+		t.Errorf("Rejected() = %d, want the download job turned away", got)
+	}
+	if got := q.Running(); got != 1 {
+		t.Errorf("Running() = %d, want only the held slot", got)
+	}
+	downloader.mu.Lock()
+	defer downloader.mu.Unlock()
+	if len(downloader.searched) != 0 {
+		t.Errorf("searched %v although the job was rejected", downloader.searched)
+	}
+}
+
+func TestDownloadSeasonSubtitlesSummary(t *testing.T) {
+	defer func(d time.Duration) { subtitleRequestDelay = d }(subtitleRequestDelay)
+	subtitleRequestDelay = time.Millisecond
+
+	downloader := newFakeDownloader()
+	// Episode 1 lacks only Spanish, episode 2 has both, episode 3 has neither
+	downloader.existing[10] = []*subtitle.Subtitle{{LanguageCode: "en"}}
+	downloader.existing[20] = []*subtitle.Subtitle{{LanguageCode: "en"}, {LanguageCode: "es"}}
+	tree := &invalidations{ch: make(chan struct{}, 2)}
+	s := NewShowAssignmentService(nil, nil, nil, nil,
+		WithSubtitleDownloader(downloader),
+		WithTreeUpdater(tree),
+	)
+
+	episodes := []library.Episode{
+		{ID: 10, EpisodeNumber: 1},
+		{ID: 20, EpisodeNumber: 2},
+		{ID: 30, EpisodeNumber: 3},
+	}
+	results, err := s.DownloadSeasonSubtitles(context.Background(), &library.Show{ID: 1, TMDBID: 100}, 1, episodes, []string{"en", "es"})
+	if err != nil {
+		t.Fatalf("DownloadSeasonSubtitles: %v", err)
+	}
+
+	want := []EpisodeSubtitleResult{
+		{EpisodeID: 10, Season: 1, Episode: 1, Downloaded: []string{"es"}, Existing: []string{"en"}},
+		{EpisodeID: 20, Season: 1, Episode: 2, Existing: []string{"en", "es"}},
+		{EpisodeID: 30, Season: 1, Episode: 3, Downloaded: []string{"en", "es"}},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i, got := range results {
+		w := want[i]
+		if got.EpisodeID != w.EpisodeID || got.Season != w.Season || got.Episode != w.Episode ||
+			!slices.Equal(got.Downloaded, w.Downloaded) || !slices.Equal(got.Existing, w.Existing) ||
+			len(got.NotFound) != 0 || len(got.Failed) != 0 {
+			t.Errorf("result %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	downloader.mu.Lock()
+	if _, ok := downloader.searched[2]; ok {
+		t.Errorf("searched episode 2 although it has every language")
+	}
+	if got := downloader.searched[1]; !slices.Equal(got, []string{"es"}) {
+		t.Errorf("episode 1 searched %v, want only the missing es", got)
+	}
+	if got := downloader.stored[30]; !slices.Equal(got, []string{"en", "es"}) {
+		t.Errorf("episode 3 stored %v, want en and es", got)
+	}
+	downloader.mu.Unlock()
+
+	if got := len(tree.ch); got != 1 {
+		t.Errorf("InvalidateTree called %d times, want once", got)
+	}
+}
+
+func TestBestSubtitleFile(t *testing.T) {
+	result := func(fileID, downloads int, mod func(*opensubtitles.SubtitleAttributes)) opensubtitles.SubtitleResult {
+		attrs := opensubtitles.SubtitleAttributes{
+			Language:      "en",
+			DownloadCount: downloads,
+			Files:         []opensubtitles.SubtitleFile{{FileID: fileID}},
+		}
+		if mod != nil {
+			mod(&attrs)
+		}
+		return opensubtitles.SubtitleResult{Attributes: attrs}
+	}
+	foreignOnly := func(a *opensubtitles.SubtitleAttributes) { a.ForeignPartsOnly = true }
+	machine := func(a *opensubtitles.SubtitleAttributes) { a.MachineTranslated = true }
+	sdh := func(a *opensubtitles.SubtitleAttributes) { a.HearingImpaired = true }
+
+	tests := []struct {
+		name    string
+		results []opensubtitles.SubtitleResult
+		want    int
+	}{
+		{"most downloaded", []opensubtitles.SubtitleResult{result(1, 10, nil), result(2, 50, nil)}, 2},
+		{"full over foreign parts only", []opensubtitles.SubtitleResult{result(1, 500, foreignOnly), result(2, 5, machine)}, 2},
+		{"human over machine", []opensubtitles.SubtitleResult{result(1, 500, machine), result(2, 5, sdh)}, 2},
+		{"plain over hearing impaired", []opensubtitles.SubtitleResult{result(1, 500, sdh), result(2, 5, nil)}, 2},
+		{"hearing impaired when nothing else", []opensubtitles.SubtitleResult{result(1, 5, sdh)}, 1},
+		{"other language ignored", []opensubtitles.SubtitleResult{result(1, 5, func(a *opensubtitles.SubtitleAttributes) { a.Language = "de" })}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bestSubtitleFile(tt.results, "en"); got != tt.want {
+				t.Errorf("bestSubtitleFile() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
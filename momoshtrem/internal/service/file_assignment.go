@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// confidenceManual marks episode assignments whose file was chosen by the user.
+const confidenceManual = "manual"
+
+// FileAssignment points an episode at a specific file of an already-added torrent.
+type FileAssignment struct {
+	InfoHash   string
+	FilePath   string
+	Resolution string // Optional: parsed from the file name when empty
+	Source     string // Optional: parsed from the file name when empty
+	MagnetURI  string // Optional: reused from existing assignments or built from the hash
+}
+
+// AssignEpisodeFile assigns a torrent file to an episode without running
+// identification, for releases whose names the patterns can't parse.
+// The torrent must already be loaded so the file can be verified.
+func (s *ShowAssignmentService) AssignEpisodeFile(
+	ctx context.Context,
+	episodeID int64,
+	req FileAssignment,
+) (*MatchedAssignment, error) {
+	infoHash := strings.ToLower(strings.TrimSpace(req.InfoHash))
+	if infoHash == "" {
+		return nil, library.ErrInvalidMagnet
+	}
+	if !identify.IsVideoFile(req.FilePath) {
+		return nil, library.ErrNotVideoFile
+	}
+
+	episode, err := s.showRepo.GetEpisodeByID(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode: %w", err)
+	}
+	if episode == nil {
+		return nil, library.ErrEpisodeNotFound
+	}
+	epCtx, err := s.showRepo.GetEpisodeContext(episodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load episode context: %w", err)
+	}
+	if epCtx == nil {
+		return nil, library.ErrEpisodeNotFound
+	}
+
+	if s.fileGetter == nil {
+		return nil, library.ErrTorrentServiceUnavailable
+	}
+
+	// Verify the file exists (returns torrent.ErrTorrentNotFound / ErrFileNotFound)
+	handle, err := s.fileGetter.GetFile(infoHash, req.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	if t := handle.Torrent(); t != nil {
+		name = t.Name()
+	}
+	magnetURI, err := s.magnetForHash(infoHash, req.MagnetURI, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill what the request leaves out from the file name
+	parsed := s.identifier.FindMovieFile([]identify.TorrentFile{{Path: req.FilePath, Size: handle.Length()}})
+	resolution, source := req.Resolution, req.Source
+	if resolution == "" {
+		resolution = parsed.Quality.Resolution
+	}
+	if source == "" {
+		source = parsed.Quality.Source
+	}
+
+	assignment := &library.TorrentAssignment{
+		ItemType:   library.ItemTypeEpisode,
+		ItemID:     episode.ID,
+		InfoHash:   infoHash,
+		MagnetURI:  magnetURI,
+		FilePath:   req.FilePath,
+		FileSize:   handle.Length(),
+		Resolution: resolution,
+		Source:     source,
+		BitDepth:   parsed.Quality.BitDepth,
+		Codec:      parsed.Quality.Codec,
+		AudioCodec: parsed.Quality.AudioCodec,
+		HDR:        parsed.Quality.HDR,
+		Confidence: confidenceManual,
+	}
+	if err := s.assignmentRepo.Create(assignment); err != nil {
+		return nil, err
+	}
+	s.mediaProber.Probe(assignment)
+
+	s.log.Info("Episode file assigned manually",
+		"episode_id", episode.ID,
+		"season", epCtx.SeasonNumber,
+		"episode", epCtx.EpisodeNumber,
+		"info_hash", infoHash,
+		"file_path", req.FilePath,
+	)
+
+	if s.treeUpdater != nil {
+		s.treeUpdater.AddEpisodesToTree([]vfs.EpisodeWithContext{{
+			ShowTitle:    epCtx.ShowTitle,
+			ShowYear:     epCtx.ShowYear,
+			SeasonNumber: epCtx.SeasonNumber,
+			Episode:      episode,
+			Assignment:   assignment,
+
+			ShowTMDBID:   epCtx.ShowTMDBID,
+			ShowOverview: epCtx.ShowOverview,
+		}})
+	}
+
+	return &MatchedAssignment{
+		EpisodeID:  episode.ID,
+		Season:     epCtx.SeasonNumber,
+		Episode:    epCtx.EpisodeNumber,
+		FilePath:   assignment.FilePath,
+		FileSize:   assignment.FileSize,
+		Resolution: resolution,
+		Confidence: confidenceManual,
+	}, nil
+}
+
+// magnetForHash picks the magnet URI stored with the assignment, which is used
+// to re-add the torrent after a restart. An explicit magnet must match the hash;
+// otherwise one from an existing assignment (with its trackers) is reused.
+func (s *ShowAssignmentService) magnetForHash(infoHash, explicit, name string) (string, error) {
+	if explicit != "" {
+		if torrent.ExtractInfoHash(explicit) != infoHash {
+			return "", library.ErrInvalidMagnet
+		}
+		return explicit, nil
+	}
+
+	existing, err := s.assignmentRepo.GetByInfoHash(infoHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to load assignments: %w", err)
+	}
+	for _, a := range existing {
+		if a.MagnetURI != "" {
+			return a.MagnetURI, nil
+		}
+	}
+
+	magnet := "magnet:?xt=urn:btih:" + infoHash
+	if name != "" {
+		magnet += "&dn=" + url.QueryEscape(name)
+	}
+	return magnet, nil
+}
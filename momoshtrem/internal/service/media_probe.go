@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// mediaProbeTimeout bounds the header reads for one assigned file. The probe
+// waits on torrent pieces, so a slow swarm just leaves the values unknown.
+const mediaProbeTimeout = time.Minute
+
+// MediaInfoStore persists probed media info for assignments.
+type MediaInfoStore interface {
+	UpdateMediaInfo(id int64, bitrateBps int64, runtimeSeconds int) error
+}
+
+// Compile-time verification
+var _ MediaInfoStore = (*library.AssignmentRepository)(nil)
+
+// MediaProber estimates the runtime and bitrate of newly assigned files by
+// reading their container header (MP4 mvhd, MKV Info) from the torrent, or from
+// disk for local files. Probing is best-effort and never blocks or fails an assignment.
+type MediaProber struct {
+	fileGetter TorrentFileGetter
+	store      MediaInfoStore
+	timeout    time.Duration
+	log        *slog.Logger
+}
+
+// NewMediaProber creates a prober that reads files through fileGetter.
+func NewMediaProber(fileGetter TorrentFileGetter, store MediaInfoStore) *MediaProber {
+	return &MediaProber{
+		fileGetter: fileGetter,
+		store:      store,
+		timeout:    mediaProbeTimeout,
+		log:        slog.With("component", "media-prober"),
+	}
+}
+
+// WithMediaProber configures bitrate and runtime probing of newly assigned files.
+func WithMediaProber(p *MediaProber) AssignmentServiceOption {
+	return func(s *ShowAssignmentService) {
+		s.mediaProber = p
+	}
+}
+
+// Probe starts probing the given assignments in the background, one at a time
+// so a season pack doesn't fetch every episode's header at once.
+// Safe to call on a nil prober.
+func (p *MediaProber) Probe(assignments ...*library.TorrentAssignment) {
+	if p == nil || len(assignments) == 0 {
+		return
+	}
+
+	go func() {
+		for _, a := range assignments {
+			p.probe(a)
+		}
+	}()
+}
+
+// probe reads one assignment's duration and stores runtime and bitrate.
+func (p *MediaProber) probe(a *library.TorrentAssignment) {
+	reader, size, err := p.open(a)
+	if err != nil {
+		p.log.Debug("Media probe skipped, file not available",
+			"assignment_id", a.ID,
+			"file_path", a.FilePath,
+			"local_path", a.LocalPath,
+			"error", err,
+		)
+		return
+	}
+
+	type result struct {
+		duration time.Duration
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		d, err := streaming.ProbeDuration(reader, size, a.FilePath)
+		done <- result{d, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+		reader.Close()
+	case <-time.After(p.timeout):
+		// Closing the reader unblocks the pending read
+		reader.Close()
+		p.log.Debug("Timed out probing media info", "assignment_id", a.ID, "file_path", a.FilePath)
+		return
+	}
+
+	if res.err != nil {
+		p.log.Debug("Media duration not found",
+			"assignment_id", a.ID,
+			"file_path", a.FilePath,
+			"error", res.err,
+		)
+		return
+	}
+
+	bitrate := streaming.EstimateBitrate(size, res.duration)
+	runtime := int(res.duration.Round(time.Second) / time.Second)
+	if err := p.store.UpdateMediaInfo(a.ID, bitrate, runtime); err != nil {
+		p.log.Warn("Failed to store media info", "assignment_id", a.ID, "error", err)
+		return
+	}
+
+	p.log.Debug("Media info probed",
+		"assignment_id", a.ID,
+		"file_path", a.FilePath,
+		"runtime_seconds", runtime,
+		"bitrate_bps", bitrate,
+	)
+}
+
+// probeReader is an assignment's file opened for probing
+type probeReader interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// open returns a reader over an assignment's file and its size: from disk
+// for local assignments, through the torrent client otherwise.
+func (p *MediaProber) open(a *library.TorrentAssignment) (probeReader, int64, error) {
+	if a.IsLocal() {
+		f, err := os.Open(a.LocalPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, info.Size(), nil
+	}
+
+	handle, err := p.fileGetter.GetFile(a.InfoHash, a.FilePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &seekingReaderAt{reader: handle.NewReader()}, handle.Length(), nil
+}
+
+// seekingReaderAt adapts a sequential torrent reader to io.ReaderAt.
+// Not safe for concurrent use; the probe reads one range at a time.
+type seekingReaderAt struct {
+	reader torrent.TorrentReader
+}
+
+func (r *seekingReaderAt) Close() error { return r.reader.Close() }
+
+func (r *seekingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := r.reader.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.reader, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
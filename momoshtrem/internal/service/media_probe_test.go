@@ -0,0 +1,57 @@
+package service
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+)
+
+// mediaInfoRecorder records stored media info
+type mediaInfoRecorder struct {
+	id      int64
+	bitrate int64
+	runtime int
+}
+
+func (r *mediaInfoRecorder) UpdateMediaInfo(id int64, bitrateBps int64, runtimeSeconds int) error {
+	r.id, r.bitrate, r.runtime = id, bitrateBps, runtimeSeconds
+	return nil
+}
+
+// mp4Atom builds an MP4 box around data
+func mp4Atom(kind string, data []byte) []byte {
+	atom := binary.BigEndian.AppendUint32(nil, uint32(8+len(data)))
+	atom = append(atom, kind...)
+	return append(atom, data...)
+}
+
+func TestMediaProberReadsLocalFiles(t *testing.T) {
+	// A 90 minute MP4: mvhd with timescale 1000 and duration 5,400,000
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 5_400_000)
+	data := append(mp4Atom("ftyp", nil), mp4Atom("mdat", make([]byte, 4096))...)
+	data = append(data, mp4Atom("moov", mp4Atom("mvhd", mvhd))...)
+
+	path := filepath.Join(t.TempDir(), "Movie.mp4")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// No torrent client: a local file must not go through it
+	store := &mediaInfoRecorder{}
+	prober := NewMediaProber(nil, store)
+	prober.probe(&library.TorrentAssignment{ID: 3, FilePath: path, LocalPath: path})
+
+	if store.id != 3 || store.runtime != 90*60 {
+		t.Fatalf("stored id %d runtime %d, want assignment 3 at 5400s", store.id, store.runtime)
+	}
+	if want := streaming.EstimateBitrate(int64(len(data)), 90*time.Minute); store.bitrate != want {
+		t.Errorf("bitrate = %d, want %d", store.bitrate, want)
+	}
+}
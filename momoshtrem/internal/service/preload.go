@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// PreloadTorrents defines the torrent operations used to preload assigned torrents.
+type PreloadTorrents interface {
+	GetOrAddTorrent(ctx context.Context, magnetURI string) (*torrent.TorrentInfo, error)
+}
+
+// Compile-time verification
+var _ PreloadTorrents = (torrent.Service)(nil)
+
+// PreloadAssignments defines the assignment lookups used to find torrents to preload.
+type PreloadAssignments interface {
+	ListDistinctTorrents() ([]string, error)
+	GetActiveByInfoHash(infoHash string) ([]*library.TorrentAssignment, error)
+}
+
+// Compile-time verification
+var _ PreloadAssignments = (*library.AssignmentRepository)(nil)
+
+// TorrentPreloader adds every torrent with an active assignment once at
+// startup instead of on first access. Pieces already in the cache are then
+// readable as soon as playback starts.
+type TorrentPreloader struct {
+	assignments PreloadAssignments
+	torrents    PreloadTorrents
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+	log      *slog.Logger
+}
+
+// NewTorrentPreloader creates a preloader.
+func NewTorrentPreloader(assignments PreloadAssignments, torrents PreloadTorrents) *TorrentPreloader {
+	return &TorrentPreloader{
+		assignments: assignments,
+		torrents:    torrents,
+		stopChan:    make(chan struct{}),
+		log:         slog.With("component", "torrent-preloader"),
+	}
+}
+
+// Start preloads in the background; torrents are added one at a time.
+func (p *TorrentPreloader) Start() {
+	go p.preload()
+}
+
+// Stop ends preloading after the torrent being added.
+func (p *TorrentPreloader) Stop() {
+	p.stopOnce.Do(func() { close(p.stopChan) })
+}
+
+func (p *TorrentPreloader) preload() {
+	hashes, err := p.assignments.ListDistinctTorrents()
+	if err != nil {
+		p.log.Error("Failed to list assigned torrents", "error", err)
+		return
+	}
+
+	p.log.Info("Preloading assigned torrents", "torrents", len(hashes))
+	start := time.Now()
+	loaded := 0
+
+	for _, hash := range hashes {
+		select {
+		case <-p.stopChan:
+			return
+		default:
+		}
+
+		assignments, err := p.assignments.GetActiveByInfoHash(hash)
+		if err != nil || len(assignments) == 0 {
+			p.log.Warn("Failed to look up assigned torrent", "info_hash", hash, "error", err)
+			continue
+		}
+
+		// All assignments of a torrent share its magnet URI
+		if _, err := p.torrents.GetOrAddTorrent(context.Background(), assignments[0].MagnetURI); err != nil {
+			p.log.Warn("Failed to preload torrent", "info_hash", hash, "error", err)
+			continue
+		}
+		loaded++
+	}
+
+	p.log.Info("Assigned torrents preloaded",
+		"loaded", loaded,
+		"failed", len(hashes)-loaded,
+		"duration", time.Since(start).Round(time.Millisecond),
+	)
+}
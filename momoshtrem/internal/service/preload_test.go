@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// preloadAssignments maps info hashes to their active assignments
+type preloadAssignments map[string][]*library.TorrentAssignment
+
+func (a preloadAssignments) ListDistinctTorrents() ([]string, error) {
+	var hashes []string
+	for hash := range a {
+		hashes = append(hashes, hash)
+	}
+	slices.Sort(hashes)
+	return hashes, nil
+}
+
+func (a preloadAssignments) GetActiveByInfoHash(infoHash string) ([]*library.TorrentAssignment, error) {
+	return a[infoHash], nil
+}
+
+// preloadTorrents records added magnets, failing the ones listed in fail
+type preloadTorrents struct {
+	mu    sync.Mutex
+	added []string
+	fail  map[string]bool
+	done  chan struct{} // Closed after want adds
+	want  int
+}
+
+func (p *preloadTorrents) GetOrAddTorrent(_ context.Context, magnetURI string) (*torrent.TorrentInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.added = append(p.added, magnetURI)
+	if len(p.added) == p.want {
+		close(p.done)
+	}
+	if p.fail[magnetURI] {
+		return nil, errors.New("no peers")
+	}
+	return &torrent.TorrentInfo{}, nil
+}
+
+func TestTorrentPreloaderAddsEachAssignedTorrent(t *testing.T) {
+	assignments := preloadAssignments{
+		"aaaa": {{MagnetURI: "magnet:a"}, {MagnetURI: "magnet:a"}},
+		"bbbb": {{MagnetURI: "magnet:b"}},
+		"cccc": nil, // Deactivated since listing: skipped
+		"dddd": {{MagnetURI: "magnet:d"}},
+	}
+	torrents := &preloadTorrents{fail: map[string]bool{"magnet:b": true}, done: make(chan struct{}), want: 3}
+
+	p := NewTorrentPreloader(assignments, torrents)
+	p.Start()
+	defer p.Stop()
+
+	select {
+	case <-torrents.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("preloader did not add every torrent")
+	}
+
+	torrents.mu.Lock()
+	defer torrents.mu.Unlock()
+	// One add per torrent, and a failure doesn't stop the rest
+	if want := []string{"magnet:a", "magnet:b", "magnet:d"}; !slices.Equal(torrents.added, want) {
+		t.Errorf("added %v, want %v", torrents.added, want)
+	}
+}
+
+func TestTorrentPreloaderStop(t *testing.T) {
+	torrents := &preloadTorrents{done: make(chan struct{}), want: -1}
+	p := NewTorrentPreloader(preloadAssignments{"aaaa": {{MagnetURI: "magnet:a"}}}, torrents)
+	p.Stop()
+	p.preload()
+
+	if len(torrents.added) != 0 {
+		t.Errorf("stopped preloader added %v", torrents.added)
+	}
+}
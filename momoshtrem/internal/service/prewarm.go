@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// prewarmLookbackDays keeps retrying torrents that failed to resolve for a few
+// days after their air date, then gives up on them
+const prewarmLookbackDays = 3
+
+// PrewarmTorrents defines the torrent operations used to pre-warm queued torrents.
+type PrewarmTorrents interface {
+	GetOrAddTorrent(ctx context.Context, magnetURI string) (*torrent.TorrentInfo, error)
+	GetFile(infoHash, filePath string) (torrent.TorrentFileHandle, error)
+}
+
+// PrewarmQueue defines the queued torrent storage used by the prewarmer.
+type PrewarmQueue interface {
+	Upsert(q *library.QueuedTorrent) error
+	ListDue(from, to string) ([]*library.QueuedTorrent, error)
+	MarkPrewarmed(id int64) error
+}
+
+// Compile-time verification
+var (
+	_ PrewarmTorrents = (torrent.Service)(nil)
+	_ PrewarmQueue    = (*library.QueuedTorrentRepository)(nil)
+)
+
+// TorrentPrewarmer adds torrents queued for upcoming episodes once their air
+// date arrives, without assigning them. Resolving metadata and, optionally,
+// fetching the episode file's header ahead of time lets the first stream start
+// without waiting for the swarm.
+type TorrentPrewarmer struct {
+	queue      PrewarmQueue
+	showRepo   *library.ShowRepository
+	torrents   PrewarmTorrents
+	identifier EpisodeIdentifier
+	interval   time.Duration
+
+	// Optional: header prefetch through the streaming prioritizer
+	prefetch     bool
+	streamingCfg streaming.Config
+	onActivity   func(hash string) // Wakes idle torrents so the header downloads
+
+	kick     chan struct{} // Requests a check before the next tick
+	stopChan chan struct{}
+	stopOnce sync.Once
+	log      *slog.Logger
+}
+
+// NewTorrentPrewarmer creates a prewarmer that checks for due torrents every interval.
+func NewTorrentPrewarmer(
+	queue PrewarmQueue,
+	showRepo *library.ShowRepository,
+	torrents PrewarmTorrents,
+	identifier EpisodeIdentifier,
+	interval time.Duration,
+) *TorrentPrewarmer {
+	return &TorrentPrewarmer{
+		queue:      queue,
+		showRepo:   showRepo,
+		torrents:   torrents,
+		identifier: identifier,
+		interval:   interval,
+		kick:       make(chan struct{}, 1),
+		stopChan:   make(chan struct{}),
+		log:        slog.With("component", "torrent-prewarmer"),
+	}
+}
+
+// SetHeaderPrefetch enables fetching the header and footer pieces of the
+// queued episode's file. onActivity (optional) wakes the torrent from idle
+// mode first; it goes idle again after the usual idle timeout.
+func (p *TorrentPrewarmer) SetHeaderPrefetch(cfg streaming.Config, onActivity func(hash string)) {
+	p.prefetch = true
+	p.streamingCfg = cfg
+	p.onActivity = onActivity
+}
+
+// Start begins the background check loop.
+func (p *TorrentPrewarmer) Start() {
+	p.log.Info("Torrent prewarmer started",
+		"interval_minutes", p.interval.Minutes(),
+		"prefetch_header", p.prefetch,
+	)
+	go p.loop()
+}
+
+// Stop halts the background check loop.
+func (p *TorrentPrewarmer) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopChan)
+		p.log.Info("Torrent prewarmer stopped")
+	})
+}
+
+// Queue stores a torrent to pre-warm for an item, replacing any earlier one.
+// A torrent whose air date has already arrived is pre-warmed right away.
+func (p *TorrentPrewarmer) Queue(q *library.QueuedTorrent) error {
+	if err := p.queue.Upsert(q); err != nil {
+		return err
+	}
+
+	p.log.Info("Torrent queued for pre-warming",
+		"item_type", q.ItemType,
+		"item_id", q.ItemID,
+		"info_hash", q.InfoHash,
+		"air_date", q.AirDate,
+	)
+
+	if q.AirDate <= time.Now().Format("2006-01-02") {
+		select {
+		case p.kick <- struct{}{}:
+		default: // A check is already pending
+		}
+	}
+	return nil
+}
+
+func (p *TorrentPrewarmer) loop() {
+	p.prewarmDue()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			p.prewarmDue()
+		case <-p.kick:
+			p.prewarmDue()
+		}
+	}
+}
+
+// prewarmDue pre-warms every queued torrent whose air date has arrived.
+// Failures are retried on the next check.
+func (p *TorrentPrewarmer) prewarmDue() {
+	now := time.Now()
+	due, err := p.queue.ListDue(
+		now.AddDate(0, 0, -prewarmLookbackDays).Format("2006-01-02"),
+		now.Format("2006-01-02"),
+	)
+	if err != nil {
+		p.log.Error("Failed to list due torrents", "error", err)
+		return
+	}
+
+	for _, q := range due {
+		select {
+		case <-p.stopChan:
+			return
+		default:
+		}
+
+		if err := p.prewarm(q); err != nil {
+			p.log.Warn("Failed to pre-warm torrent",
+				"item_type", q.ItemType,
+				"item_id", q.ItemID,
+				"info_hash", q.InfoHash,
+				"error", err,
+			)
+			continue
+		}
+		if err := p.queue.MarkPrewarmed(q.ID); err != nil {
+			p.log.Warn("Failed to record pre-warmed torrent", "id", q.ID, "error", err)
+		}
+	}
+}
+
+// prewarm adds one queued torrent and, if enabled, prioritizes its header pieces.
+func (p *TorrentPrewarmer) prewarm(q *library.QueuedTorrent) error {
+	info, err := p.torrents.GetOrAddTorrent(context.Background(), q.MagnetURI)
+	if err != nil {
+		return fmt.Errorf("failed to add torrent: %w", err)
+	}
+
+	p.log.Info("Torrent pre-warmed",
+		"item_type", q.ItemType,
+		"item_id", q.ItemID,
+		"info_hash", info.InfoHash,
+		"name", info.Name,
+	)
+
+	if !p.prefetch {
+		return nil
+	}
+
+	filePath, err := p.episodeFile(q, info)
+	if err != nil {
+		// Metadata is resolved, which is most of the cold start; skip the header
+		p.log.Debug("Header prefetch skipped", "item_id", q.ItemID, "reason", err)
+		return nil
+	}
+
+	handle, err := p.torrents.GetFile(info.InfoHash, filePath)
+	if err != nil {
+		p.log.Debug("Header prefetch skipped", "item_id", q.ItemID, "file_path", filePath, "error", err)
+		return nil
+	}
+
+	if p.onActivity != nil {
+		p.onActivity(info.InfoHash)
+	}
+
+	// The prioritizer is never released: its header and footer pieces keep
+	// high priority until they're downloaded or a stream takes over
+	streaming.NewPrioritizer(handle.Torrent(), handle.File(), p.streamingCfg).InitialPrioritize()
+	p.log.Debug("Prefetching header pieces", "item_id", q.ItemID, "file_path", filePath)
+	return nil
+}
+
+// episodeFile picks the torrent file the queued episode will most likely be
+// assigned: the only video file, or the one identified as the episode.
+func (p *TorrentPrewarmer) episodeFile(q *library.QueuedTorrent, info *torrent.TorrentInfo) (string, error) {
+	if q.ItemType != library.ItemTypeEpisode {
+		return "", library.ErrNoMatchingFile
+	}
+
+	video := p.identifier.FindMovieFile(info.Files)
+	if !video.Found {
+		return "", library.ErrNoVideoFiles
+	}
+	if len(video.OtherFiles) == 0 && len(video.Parts) == 0 {
+		return video.FilePath, nil
+	}
+
+	epCtx, err := p.showRepo.GetEpisodeContext(q.ItemID)
+	if err != nil {
+		return "", err
+	}
+	if epCtx == nil {
+		return "", library.ErrEpisodeNotFound
+	}
+
+	file := findEpisodeFile(p.identifier.Identify(info.Files, info.Name), epCtx.SeasonNumber, epCtx.EpisodeNumber)
+	if file == nil {
+		return "", library.ErrNoMatchingFile
+	}
+	return file.FilePath, nil
+}
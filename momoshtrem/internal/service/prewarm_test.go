@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// addRecorder records the magnets added and fails the ones in fail
+type addRecorder struct {
+	PrewarmTorrents
+	fail  map[string]bool
+	added []string
+}
+
+func (a *addRecorder) GetOrAddTorrent(_ context.Context, magnetURI string) (*torrent.TorrentInfo, error) {
+	a.added = append(a.added, magnetURI)
+	if a.fail[magnetURI] {
+		return nil, errors.New("no peers")
+	}
+	return &torrent.TorrentInfo{InfoHash: "hash", Name: "Show"}, nil
+}
+
+func TestPrewarmEpisodeFile(t *testing.T) {
+	p := NewTorrentPrewarmer(nil, nil, nil, identify.NewIdentifier(nil, identify.DefaultConfig()), time.Hour)
+
+	// A single video file is the episode's without consulting the library
+	info := &torrent.TorrentInfo{Name: "Show.S01E02", Files: []identify.TorrentFile{
+		{Path: "Show.S01E02/Show.S01E02.mkv", Size: 500 << 20},
+		{Path: "Show.S01E02/Show.S01E02.nfo", Size: 1 << 10},
+	}}
+	got, err := p.episodeFile(&library.QueuedTorrent{ItemType: library.ItemTypeEpisode, ItemID: 1}, info)
+	if err != nil || got != "Show.S01E02/Show.S01E02.mkv" {
+		t.Errorf("episodeFile() = %q, %v; want the only video file", got, err)
+	}
+
+	if _, err := p.episodeFile(&library.QueuedTorrent{ItemType: library.ItemTypeMovie, ItemID: 1}, info); !errors.Is(err, library.ErrNoMatchingFile) {
+		t.Errorf("episodeFile() for a movie = %v, want ErrNoMatchingFile", err)
+	}
+
+	noVideo := &torrent.TorrentInfo{Files: []identify.TorrentFile{{Path: "readme.txt", Size: 10}}}
+	if _, err := p.episodeFile(&library.QueuedTorrent{ItemType: library.ItemTypeEpisode, ItemID: 1}, noVideo); !errors.Is(err, library.ErrNoVideoFiles) {
+		t.Errorf("episodeFile() without video = %v, want ErrNoVideoFiles", err)
+	}
+}
+
+func TestPrewarmDue(t *testing.T) {
+	day := func(offset int) string { return time.Now().AddDate(0, 0, offset).Format("2006-01-02") }
+	queue := &memoryQueue{}
+	for i, q := range []*library.QueuedTorrent{
+		{MagnetURI: "magnet:?xt=urn:btih:today", AirDate: day(0)},
+		{MagnetURI: "magnet:?xt=urn:btih:failing", AirDate: day(-1)},
+		{MagnetURI: "magnet:?xt=urn:btih:future", AirDate: day(2)},
+		{MagnetURI: "magnet:?xt=urn:btih:stale", AirDate: day(-prewarmLookbackDays - 1)},
+	} {
+		q.ID = int64(i + 1)
+		queue.Upsert(q)
+	}
+
+	torrents := &addRecorder{fail: map[string]bool{"magnet:?xt=urn:btih:failing": true}}
+	p := NewTorrentPrewarmer(queue, nil, torrents, nil, time.Hour)
+
+	p.prewarmDue()
+	if queue.from != day(-prewarmLookbackDays) || queue.to != day(0) {
+		t.Errorf("listed due between %s and %s, want %s and %s", queue.from, queue.to, day(-prewarmLookbackDays), day(0))
+	}
+	// Earliest air date first; future and stale entries are left alone
+	if want := []string{"magnet:?xt=urn:btih:failing", "magnet:?xt=urn:btih:today"}; !slices.Equal(torrents.added, want) {
+		t.Errorf("added %v, want %v", torrents.added, want)
+	}
+	// The failed torrent isn't marked, so the next check retries it
+	if !slices.Equal(queue.prewarmed, []int64{1}) {
+		t.Errorf("marked prewarmed %v, want only the added torrent", queue.prewarmed)
+	}
+
+	torrents.added = nil
+	p.prewarmDue()
+	if want := []string{"magnet:?xt=urn:btih:failing"}; !slices.Equal(torrents.added, want) {
+		t.Errorf("second check added %v, want %v", torrents.added, want)
+	}
+}
+
+// memoryQueue is an in-memory PrewarmQueue that records the due window
+type memoryQueue struct {
+	entries   []*library.QueuedTorrent
+	prewarmed []int64
+	from, to  string
+}
+
+func (m *memoryQueue) Upsert(q *library.QueuedTorrent) error {
+	m.entries = append(m.entries, q)
+	return nil
+}
+
+func (m *memoryQueue) ListDue(from, to string) ([]*library.QueuedTorrent, error) {
+	m.from, m.to = from, to
+	var due []*library.QueuedTorrent
+	for _, q := range m.entries {
+		if q.AirDate >= from && q.AirDate <= to && !slices.Contains(m.prewarmed, q.ID) {
+			due = append(due, q)
+		}
+	}
+	slices.SortFunc(due, func(a, b *library.QueuedTorrent) int { return strings.Compare(a.AirDate, b.AirDate) })
+	return due, nil
+}
+
+func (m *memoryQueue) MarkPrewarmed(id int64) error {
+	m.prewarmed = append(m.prewarmed, id)
+	return nil
+}
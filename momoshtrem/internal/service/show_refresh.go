@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
+)
+
+// uncachedClient is implemented by TMDB clients that can bypass their response cache.
+type uncachedClient interface {
+	Uncached() *tmdb.Client
+}
+
+// RefreshShowResult reports what a show refresh changed.
+type RefreshShowResult struct {
+	Show            *library.Show
+	SeasonsAdded    int
+	EpisodesAdded   int
+	EpisodesRenamed int              // Renames change VFS file names
+	Renamed         []RenamedEpisode // The episodes counted in EpisodesRenamed
+	SeasonErrors    []SeasonError    // Non-fatal errors during season refresh
+}
+
+// RenamedEpisode is an existing episode whose name changed on TMDB.
+type RenamedEpisode struct {
+	Episode      *library.Episode // With the new name
+	SeasonNumber int
+	OldName      string
+}
+
+// RefreshShow re-fetches a show from TMDB and brings its seasons and episodes
+// up to date: new episodes of existing seasons and seasons announced after the
+// show's latest one are created, and episode names and air dates are updated.
+// Earlier seasons that were left out when the show was added are not added.
+// Idempotent: the season and episode upserts handle existing rows.
+func (s *ShowService) RefreshShow(ctx context.Context, showID int64) (*RefreshShowResult, error) {
+	show, err := s.showRepo.GetWithSeasonsAndEpisodes(showID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load show: %w", err)
+	}
+	if show == nil {
+		return nil, library.ErrShowNotFound
+	}
+
+	// Announcements must not be hidden by cached responses
+	var client TMDBClient = s.tmdbClient
+	if u, ok := client.(uncachedClient); ok {
+		client = u.Uncached()
+	}
+
+	tmdbShow, err := client.GetShowDetails(show.TMDBID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch show from TMDB: %w", err)
+	}
+
+	existing := make(map[int]map[int]string, len(show.Seasons)) // season -> episode -> name
+	latestSeason := 0
+	for _, season := range show.Seasons {
+		names := make(map[int]string, len(season.Episodes))
+		for _, ep := range season.Episodes {
+			names[ep.EpisodeNumber] = ep.Name
+		}
+		existing[season.SeasonNumber] = names
+		latestSeason = max(latestSeason, season.SeasonNumber)
+	}
+
+	result := &RefreshShowResult{}
+	for _, ts := range tmdbShow.Seasons {
+		seasonNum := ts.SeasonNumber
+		episodes, known := existing[seasonNum]
+		if seasonNum <= 0 || (!known && seasonNum < latestSeason) {
+			continue // Specials, or a season deliberately left out
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		added, renamed, err := s.refreshSeason(client, show.ID, show.TMDBID, seasonNum, episodes)
+		if err != nil {
+			result.SeasonErrors = append(result.SeasonErrors, SeasonError{
+				SeasonNumber: seasonNum,
+				Err:          err,
+			})
+			s.log.Warn("Failed to refresh season",
+				"show_id", show.ID,
+				"season", seasonNum,
+				"error", err,
+			)
+			continue
+		}
+
+		if !known {
+			result.SeasonsAdded++
+		}
+		result.EpisodesAdded += added
+		result.EpisodesRenamed += len(renamed)
+		result.Renamed = append(result.Renamed, renamed...)
+	}
+
+	s.log.Info("Show refreshed",
+		"show_id", show.ID,
+		"title", show.Title,
+		"seasons_added", result.SeasonsAdded,
+		"episodes_added", result.EpisodesAdded,
+		"episodes_renamed", result.EpisodesRenamed,
+	)
+
+	result.Show, err = s.showRepo.GetWithSeasonsAndEpisodes(show.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload show: %w", err)
+	}
+	return result, nil
+}
+
+// refreshSeason upserts a season and its episodes from TMDB. existing maps the
+// season's known episode numbers to names (nil for a new season).
+// Returns the number of episodes added and the episodes renamed.
+func (s *ShowService) refreshSeason(client TMDBClient, showID int64, tmdbID, seasonNum int, existing map[int]string) (added int, renamed []RenamedEpisode, err error) {
+	tmdbSeason, err := client.GetSeason(tmdbID, seasonNum)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to fetch season from TMDB: %w", err)
+	}
+
+	season := &library.Season{ShowID: showID, SeasonNumber: seasonNum}
+	if err := s.showRepo.CreateSeason(season); err != nil {
+		return 0, nil, fmt.Errorf("failed to create season record: %w", err)
+	}
+
+	for _, ep := range tmdbSeason.Episodes {
+		name, known := existing[ep.EpisodeNumber]
+
+		episode := &library.Episode{
+			SeasonID:      season.ID,
+			EpisodeNumber: ep.EpisodeNumber,
+			Name:          ep.Name,
+			TMDBID:        ep.ID,
+			Overview:      ep.Overview,
+		}
+		if err := s.showRepo.CreateEpisode(episode); err != nil {
+			s.log.Warn("Failed to create episode",
+				"season_id", season.ID,
+				"episode", ep.EpisodeNumber,
+				"error", err,
+			)
+			continue
+		}
+
+		switch {
+		case !known:
+			added++
+		case name != ep.Name:
+			renamed = append(renamed, RenamedEpisode{
+				Episode:      episode,
+				SeasonNumber: seasonNum,
+				OldName:      name,
+			})
+		}
+
+		if ep.AirDate != "" {
+			if err := s.showRepo.UpdateEpisodeAirDate(season.ID, ep.EpisodeNumber, ep.AirDate); err != nil {
+				s.log.Warn("Failed to update episode air date",
+					"season_id", season.ID,
+					"episode", ep.EpisodeNumber,
+					"error", err,
+				)
+			}
+		}
+	}
+
+	return added, renamed, nil
+}
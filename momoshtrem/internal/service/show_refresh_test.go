@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/tmdb"
+)
+
+// memoryShows is an in-memory ShowStore holding one show, with the same
+// upsert semantics as the repository
+type memoryShows struct {
+	ShowStore
+	show    library.Show
+	seasons []*library.Season
+	nextID  int64
+}
+
+func (m *memoryShows) id() int64 { m.nextID++; return m.nextID }
+
+func (m *memoryShows) GetWithSeasonsAndEpisodes(id int64) (*library.Show, error) {
+	if id != m.show.ID {
+		return nil, nil
+	}
+	show := m.show
+	show.Seasons = nil
+	for _, season := range m.seasons {
+		s := *season
+		s.Episodes = slices.Clone(season.Episodes)
+		show.Seasons = append(show.Seasons, s)
+	}
+	slices.SortFunc(show.Seasons, func(a, b library.Season) int { return a.SeasonNumber - b.SeasonNumber })
+	return &show, nil
+}
+
+func (m *memoryShows) CreateSeason(season *library.Season) error {
+	for _, s := range m.seasons {
+		if s.SeasonNumber == season.SeasonNumber {
+			season.ID = s.ID
+			return nil
+		}
+	}
+	season.ID = m.id()
+	m.seasons = append(m.seasons, &library.Season{ID: season.ID, ShowID: season.ShowID, SeasonNumber: season.SeasonNumber})
+	return nil
+}
+
+func (m *memoryShows) season(id int64) *library.Season {
+	for _, s := range m.seasons {
+		if s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (m *memoryShows) CreateEpisode(episode *library.Episode) error {
+	season := m.season(episode.SeasonID)
+	if season == nil {
+		return fmt.Errorf("no season %d", episode.SeasonID)
+	}
+	for i := range season.Episodes {
+		if ep := &season.Episodes[i]; ep.EpisodeNumber == episode.EpisodeNumber {
+			ep.Name, ep.TMDBID, ep.Overview = episode.Name, episode.TMDBID, episode.Overview
+			episode.ID = ep.ID
+			return nil
+		}
+	}
+	episode.ID = m.id()
+	season.Episodes = append(season.Episodes, *episode)
+	slices.SortFunc(season.Episodes, func(a, b library.Episode) int { return a.EpisodeNumber - b.EpisodeNumber })
+	return nil
+}
+
+func (m *memoryShows) UpdateEpisodeAirDate(seasonID int64, episodeNumber int, airDate string) error {
+	date, err := time.Parse("2006-01-02", airDate)
+	if err != nil {
+		return err
+	}
+	for i := range m.season(seasonID).Episodes {
+		if ep := &m.season(seasonID).Episodes[i]; ep.EpisodeNumber == episodeNumber {
+			ep.AirDate = &date
+		}
+	}
+	return nil
+}
+
+// fakeTMDB serves canned seasons and records which ones were fetched
+type fakeTMDB struct {
+	seasons map[int][]tmdb.Episode
+	fetched []int
+}
+
+func (f *fakeTMDB) GetShowDetails(id int) (*tmdb.ShowDetails, error) {
+	details := &tmdb.ShowDetails{Show: tmdb.Show{ID: id}}
+	for _, n := range []int{0, 1, 2, 3} {
+		if _, ok := f.seasons[n]; !ok {
+			continue
+		}
+		details.Seasons = append(details.Seasons, struct {
+			ID           int `json:"id"`
+			SeasonNumber int `json:"season_number"`
+			EpisodeCount int `json:"episode_count"`
+		}{SeasonNumber: n, EpisodeCount: len(f.seasons[n])})
+	}
+	return details, nil
+}
+
+func (f *fakeTMDB) GetSeason(_ int, seasonNumber int) (*tmdb.Season, error) {
+	f.fetched = append(f.fetched, seasonNumber)
+	episodes, ok := f.seasons[seasonNumber]
+	if !ok {
+		return nil, fmt.Errorf("season %d: %w", seasonNumber, tmdb.ErrNotFound)
+	}
+	return &tmdb.Season{SeasonNumber: seasonNumber, Episodes: episodes}, nil
+}
+
+func TestRefreshShow(t *testing.T) {
+	// The show was added with only season 2, whose one episode had no name yet
+	shows := &memoryShows{show: library.Show{ID: 1, TMDBID: 100, Title: "Refresh Test"}, nextID: 1}
+	season := &library.Season{ShowID: 1, SeasonNumber: 2}
+	shows.CreateSeason(season)
+	shows.CreateEpisode(&library.Episode{SeasonID: season.ID, EpisodeNumber: 1, Name: "TBA"})
+
+	client := &fakeTMDB{seasons: map[int][]tmdb.Episode{
+		0: {{EpisodeNumber: 1, Name: "Special"}},
+		1: {{EpisodeNumber: 1, Name: "Old Pilot"}},
+		2: {{EpisodeNumber: 1, Name: "Premiere", AirDate: "2024-03-01"}, {EpisodeNumber: 2, Name: "Second"}},
+		3: {{EpisodeNumber: 1, Name: "New Season"}},
+	}}
+	s := NewShowService(shows, client)
+
+	result, err := s.RefreshShow(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("RefreshShow: %v", err)
+	}
+	if result.SeasonsAdded != 1 || result.EpisodesAdded != 2 || result.EpisodesRenamed != 1 {
+		t.Errorf("added %d seasons, %d episodes, renamed %d; want 1, 2, 1",
+			result.SeasonsAdded, result.EpisodesAdded, result.EpisodesRenamed)
+	}
+	if len(result.Renamed) == 1 && (result.Renamed[0].OldName != "TBA" || result.Renamed[0].Episode.Name != "Premiere") {
+		t.Errorf("renamed = %+v, want TBA -> Premiere", result.Renamed[0])
+	}
+	// Specials and the season left out when the show was added are skipped
+	if !slices.Equal(client.fetched, []int{2, 3}) {
+		t.Errorf("fetched seasons %v, want [2 3]", client.fetched)
+	}
+
+	var numbers []int
+	for _, season := range result.Show.Seasons {
+		numbers = append(numbers, season.SeasonNumber)
+		if season.SeasonNumber == 2 {
+			if len(season.Episodes) != 2 {
+				t.Fatalf("season 2 has %d episodes, want 2", len(season.Episodes))
+			}
+			if season.Episodes[0].AirDate == nil || season.Episodes[0].AirDate.Format("2006-01-02") != "2024-03-01" {
+				t.Errorf("premiere air date = %v, want 2024-03-01", season.Episodes[0].AirDate)
+			}
+		}
+	}
+	if !slices.Equal(numbers, []int{2, 3}) {
+		t.Errorf("seasons = %v, want [2 3]", numbers)
+	}
+
+	// Refreshing again changes nothing
+	result, err = s.RefreshShow(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("second RefreshShow: %v", err)
+	}
+	if result.SeasonsAdded != 0 || result.EpisodesAdded != 0 || result.EpisodesRenamed != 0 {
+		t.Errorf("second refresh added %d seasons, %d episodes, renamed %d; want none",
+			result.SeasonsAdded, result.EpisodesAdded, result.EpisodesRenamed)
+	}
+
+	if _, err := s.RefreshShow(context.Background(), 2); err != library.ErrShowNotFound {
+		t.Errorf("RefreshShow(unknown) = %v, want ErrShowNotFound", err)
+	}
+}
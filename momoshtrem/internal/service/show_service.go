@@ -19,15 +19,28 @@ type TMDBClient interface {
 // Compile-time verification that tmdb.Client implements TMDBClient
 var _ TMDBClient = (*tmdb.Client)(nil)
 
+// ShowStore defines the show persistence used by ShowService.
+type ShowStore interface {
+	Create(show *library.Show) error
+	GetByTMDBID(tmdbID int) (*library.Show, error)
+	GetWithSeasonsAndEpisodes(id int64) (*library.Show, error)
+	CreateSeason(season *library.Season) error
+	CreateEpisode(episode *library.Episode) error
+	UpdateEpisodeAirDate(seasonID int64, episodeNumber int, airDate string) error
+}
+
+// Compile-time verification
+var _ ShowStore = (*library.ShowRepository)(nil)
+
 // ShowService manages show lifecycle operations.
 type ShowService struct {
-	showRepo   *library.ShowRepository
+	showRepo   ShowStore
 	tmdbClient TMDBClient
 	log        *slog.Logger
 }
 
 // NewShowService creates a new ShowService.
-func NewShowService(showRepo *library.ShowRepository, tmdbClient TMDBClient) *ShowService {
+func NewShowService(showRepo ShowStore, tmdbClient TMDBClient) *ShowService {
 	return &ShowService{
 		showRepo:   showRepo,
 		tmdbClient: tmdbClient,
@@ -88,9 +101,13 @@ func (s *ShowService) Create(ctx context.Context, input CreateShowInput) (*Creat
 
 	// 3. Create show record
 	show := &library.Show{
-		TMDBID: tmdbShow.ID,
-		Title:  tmdbShow.Name,
-		Year:   tmdbShow.Year(),
+		TMDBID:   tmdbShow.ID,
+		Title:    tmdbShow.Name,
+		Year:     tmdbShow.Year(),
+		Overview: tmdbShow.Overview,
+
+		PosterURL:   tmdb.ImageURL(tmdbShow.PosterPath),
+		BackdropURL: tmdb.ImageURL(tmdbShow.BackdropPath),
 	}
 	if err := s.showRepo.Create(show); err != nil {
 		return nil, fmt.Errorf("failed to create show: %w", err)
@@ -154,6 +171,8 @@ func (s *ShowService) createSeason(ctx context.Context, showID int64, tmdbID int
 			SeasonID:      season.ID,
 			EpisodeNumber: ep.EpisodeNumber,
 			Name:          ep.Name,
+			TMDBID:        ep.ID,
+			Overview:      ep.Overview,
 		}
 		if err := s.showRepo.CreateEpisode(episode); err != nil {
 			s.log.Warn("Failed to create episode",
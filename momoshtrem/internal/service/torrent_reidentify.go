@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// ReidentifiedAssignment is an assignment whose quality fields changed.
+type ReidentifiedAssignment struct {
+	ID         int64  `json:"id"`
+	ItemType   string `json:"item_type"`
+	ItemID     int64  `json:"item_id"`
+	FilePath   string `json:"file_path"`
+	Resolution string `json:"resolution,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+	HDR        bool   `json:"hdr"`
+	BitDepth   string `json:"bit_depth,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+}
+
+// ReidentifyTorrentResult contains the result of re-identifying a torrent.
+type ReidentifyTorrentResult struct {
+	InfoHash string
+	Checked  int // Active assignments of the torrent
+	Updated  []ReidentifiedAssignment
+}
+
+// ReidentifyTorrent parses the quality of every file actively assigned from
+// infoHash again and stores what changed. The file each item points at is
+// kept; only resolution, source, codec, HDR, bit depth and audio codec are
+// refreshed, and only where the parse found a value. Files assigned by hand
+// keep what the user set. A torrent that isn't loaded is not added: its
+// files are parsed from the stored paths alone.
+func (s *ShowAssignmentService) ReidentifyTorrent(ctx context.Context, infoHash string) (*ReidentifyTorrentResult, error) {
+	infoHash = strings.ToLower(infoHash)
+
+	assignments, err := s.assignmentRepo.GetActiveByInfoHash(infoHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assignments: %w", err)
+	}
+	if len(assignments) == 0 {
+		return nil, library.ErrNoActiveAssignments
+	}
+
+	// Episode identification sees the whole torrent (folder names, torrent name)
+	identified := make(map[string]identify.QualityInfo)
+	if s.torrentAdder != nil {
+		if torrentInfo, err := s.torrentAdder.GetTorrent(infoHash); err == nil {
+			for _, f := range s.identifier.Identify(torrentInfo.Files, torrentInfo.Name).IdentifiedFiles {
+				identified[f.FilePath] = f.Quality
+			}
+		}
+	}
+
+	result := &ReidentifyTorrentResult{
+		InfoHash: infoHash,
+		Checked:  len(assignments),
+		Updated:  []ReidentifiedAssignment{},
+	}
+
+	for _, a := range assignments {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if a.Confidence == confidenceManual {
+			continue
+		}
+
+		// Episodes take the quality from identification; movies and files
+		// identification skipped are parsed from the path as on assignment
+		quality, ok := identified[a.FilePath]
+		if !ok || a.ItemType == library.ItemTypeMovie {
+			parsed := s.identifier.FindMovieFile([]identify.TorrentFile{{Path: a.FilePath, Size: a.FileSize}})
+			if !parsed.Found {
+				continue
+			}
+			quality = parsed.Quality
+		}
+
+		if !mergeQuality(a, quality) {
+			continue
+		}
+		if err := s.assignmentRepo.UpdateQuality(a); err != nil {
+			s.log.Error("Failed to update assignment quality",
+				"assignment_id", a.ID,
+				"error", err,
+			)
+			continue
+		}
+
+		result.Updated = append(result.Updated, ReidentifiedAssignment{
+			ID:         a.ID,
+			ItemType:   string(a.ItemType),
+			ItemID:     a.ItemID,
+			FilePath:   a.FilePath,
+			Resolution: a.Resolution,
+			Source:     a.Source,
+			Codec:      a.Codec,
+			HDR:        a.HDR,
+			BitDepth:   a.BitDepth,
+			AudioCodec: a.AudioCodec,
+		})
+	}
+
+	s.log.Info("Torrent re-identified",
+		"info_hash", infoHash,
+		"checked", result.Checked,
+		"updated", len(result.Updated),
+	)
+
+	return result, nil
+}
+
+// mergeQuality copies the fields the parse found into a, keeping stored
+// values the name doesn't mention. HDR is only ever turned on, since names
+// without an HDR tag say nothing either way. Reports whether a changed.
+func mergeQuality(a *library.TorrentAssignment, q identify.QualityInfo) bool {
+	changed := false
+	set := func(field *string, parsed string) {
+		if parsed != "" && parsed != *field {
+			*field = parsed
+			changed = true
+		}
+	}
+	set(&a.Resolution, q.Resolution)
+	set(&a.Source, q.Source)
+	set(&a.Codec, q.Codec)
+	set(&a.BitDepth, q.BitDepth)
+	set(&a.AudioCodec, q.AudioCodec)
+	if q.HDR && !a.HDR {
+		a.HDR = true
+		changed = true
+	}
+	return changed
+}
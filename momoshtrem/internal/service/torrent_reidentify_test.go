@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestMergeQuality(t *testing.T) {
+	stored := func() *library.TorrentAssignment {
+		return &library.TorrentAssignment{Resolution: "1080p", Source: "WEB-DL", Codec: "x264"}
+	}
+
+	tests := []struct {
+		name        string
+		parsed      identify.QualityInfo
+		wantChanged bool
+		want        library.TorrentAssignment
+	}{
+		{
+			name:   "nothing parsed keeps stored values",
+			parsed: identify.QualityInfo{},
+			want:   library.TorrentAssignment{Resolution: "1080p", Source: "WEB-DL", Codec: "x264"},
+		},
+		{
+			name:   "same values",
+			parsed: identify.QualityInfo{Resolution: "1080p", Source: "WEB-DL"},
+			want:   library.TorrentAssignment{Resolution: "1080p", Source: "WEB-DL", Codec: "x264"},
+		},
+		{
+			name:        "new values replace stored ones",
+			parsed:      identify.QualityInfo{Resolution: "2160p", Codec: "x265", HDR: true},
+			wantChanged: true,
+			want:        library.TorrentAssignment{Resolution: "2160p", Source: "WEB-DL", Codec: "x265", HDR: true},
+		},
+		{
+			name:        "bit depth and audio codec filled in",
+			parsed:      identify.QualityInfo{Resolution: "1080p", BitDepth: "10bit", AudioCodec: "E-AC3"},
+			wantChanged: true,
+			want:        library.TorrentAssignment{Resolution: "1080p", Source: "WEB-DL", Codec: "x264", BitDepth: "10bit", AudioCodec: "E-AC3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := stored()
+			if got := mergeQuality(a, tt.parsed); got != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", got, tt.wantChanged)
+			}
+			if a.Resolution != tt.want.Resolution || a.Source != tt.want.Source || a.Codec != tt.want.Codec ||
+				a.HDR != tt.want.HDR || a.BitDepth != tt.want.BitDepth || a.AudioCodec != tt.want.AudioCodec {
+				t.Errorf("merged = %+v, want %+v", *a, tt.want)
+			}
+		})
+	}
+
+	hdr := &library.TorrentAssignment{HDR: true}
+	if mergeQuality(hdr, identify.QualityInfo{}) || !hdr.HDR {
+		t.Error("a name without an HDR tag cleared HDR")
+	}
+}
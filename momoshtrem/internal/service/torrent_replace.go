@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/identify"
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+// Reasons an item couldn't be moved to the replacement torrent.
+const (
+	replaceReasonNoVideo      = "no_video_files"
+	replaceReasonNoMatch      = "episode_not_found"
+	replaceReasonMissingItem  = "episode_missing"
+	replaceReasonCreateFailed = "assignment_failed"
+)
+
+// ReplacedAssignment is an item re-pointed from the old torrent to the new one.
+type ReplacedAssignment struct {
+	ItemType    string `json:"item_type"`
+	ItemID      int64  `json:"item_id"`
+	Season      int    `json:"season,omitempty"`
+	Episode     int    `json:"episode,omitempty"`
+	OldFilePath string `json:"old_file_path"`
+	FilePath    string `json:"file_path"`
+	FileSize    int64  `json:"file_size"`
+	Resolution  string `json:"resolution,omitempty"`
+}
+
+// UnreplacedAssignment is an item that still points at the old torrent.
+type UnreplacedAssignment struct {
+	ItemType string `json:"item_type"`
+	ItemID   int64  `json:"item_id"`
+	Season   int    `json:"season,omitempty"`
+	Episode  int    `json:"episode,omitempty"`
+	FilePath string `json:"file_path"` // File in the old torrent
+	Reason   string `json:"reason"`
+}
+
+// ReplaceTorrentResult contains the result of replacing a torrent.
+type ReplaceTorrentResult struct {
+	OldInfoHash string
+	NewInfoHash string
+	Replaced    []ReplacedAssignment
+	Unmatched   []UnreplacedAssignment
+}
+
+// ReplaceTorrent moves every item actively assigned to oldInfoHash onto the
+// torrent in magnetURI. Movies take the new torrent's main video file; episodes
+// take the file identified as the same season/episode. Items with no match keep
+// their old assignment, so the old torrent can be kept until they are resolved.
+func (s *ShowAssignmentService) ReplaceTorrent(
+	ctx context.Context,
+	oldInfoHash string,
+	magnetURI string,
+) (*ReplaceTorrentResult, error) {
+	newInfoHash := torrent.ExtractInfoHash(magnetURI)
+	if newInfoHash == "" {
+		return nil, library.ErrInvalidMagnet
+	}
+	oldInfoHash = strings.ToLower(oldInfoHash)
+	if oldInfoHash == newInfoHash {
+		return nil, library.ErrSameTorrent
+	}
+
+	old, err := s.assignmentRepo.GetActiveByInfoHash(oldInfoHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assignments: %w", err)
+	}
+	if len(old) == 0 {
+		return nil, library.ErrNoActiveAssignments
+	}
+
+	if s.torrentAdder == nil {
+		return nil, library.ErrTorrentServiceUnavailable
+	}
+
+	torrentInfo, err := s.addTorrent(ctx, magnetURI)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplaceTorrentResult{
+		OldInfoHash: oldInfoHash,
+		NewInfoHash: newInfoHash,
+		Replaced:    make([]ReplacedAssignment, 0, len(old)),
+	}
+
+	// Identification is only needed for episodes, and only once
+	var identResult *identify.IdentificationResult
+
+	for _, a := range old {
+		unmatched := UnreplacedAssignment{
+			ItemType: string(a.ItemType),
+			ItemID:   a.ItemID,
+			FilePath: a.FilePath,
+		}
+		replacement := &library.TorrentAssignment{
+			ItemType:  a.ItemType,
+			ItemID:    a.ItemID,
+			InfoHash:  newInfoHash,
+			MagnetURI: magnetURI,
+		}
+
+		switch a.ItemType {
+		case library.ItemTypeMovie:
+			video := s.identifier.FindMovieFile(torrentInfo.Files)
+			if !video.Found {
+				unmatched.Reason = replaceReasonNoVideo
+				result.Unmatched = append(result.Unmatched, unmatched)
+				continue
+			}
+			replacement.FilePath, replacement.FileSize = video.FilePath, video.FileSize
+			replacement.Resolution, replacement.Source = video.Quality.Resolution, video.Quality.Source
+			replacement.BitDepth = video.Quality.BitDepth
+			replacement.Codec, replacement.HDR = video.Quality.Codec, video.Quality.HDR
+			replacement.AudioCodec = video.Quality.AudioCodec
+			replacement.PartPaths = video.Parts
+
+		case library.ItemTypeEpisode:
+			epCtx, err := s.showRepo.GetEpisodeContext(a.ItemID)
+			if err != nil || epCtx == nil {
+				unmatched.Reason = replaceReasonMissingItem
+				result.Unmatched = append(result.Unmatched, unmatched)
+				continue
+			}
+			unmatched.Season, unmatched.Episode = epCtx.SeasonNumber, epCtx.EpisodeNumber
+
+			if identResult == nil {
+				identResult = s.identifier.Identify(torrentInfo.Files, torrentInfo.Name)
+			}
+			file := findEpisodeFile(identResult, epCtx.SeasonNumber, epCtx.EpisodeNumber)
+			if file == nil {
+				unmatched.Reason = replaceReasonNoMatch
+				result.Unmatched = append(result.Unmatched, unmatched)
+				continue
+			}
+			replacement.FilePath, replacement.FileSize = file.FilePath, file.FileSize
+			replacement.Resolution, replacement.Source = file.Quality.Resolution, file.Quality.Source
+			replacement.BitDepth = file.Quality.BitDepth
+			replacement.Codec, replacement.HDR = file.Quality.Codec, file.Quality.HDR
+			replacement.AudioCodec = file.Quality.AudioCodec
+			replacement.Confidence, replacement.PatternUsed = string(file.Confidence), file.PatternUsed
+			replacement.SeasonFromFolder, replacement.NeedsReview = file.SeasonFromFolder, file.NeedsReview
+
+		default:
+			continue
+		}
+
+		// Create deactivates the old assignment in the same transaction
+		if err := s.assignmentRepo.Create(replacement); err != nil {
+			s.log.Error("Failed to create replacement assignment",
+				"item_type", a.ItemType,
+				"item_id", a.ItemID,
+				"error", err,
+			)
+			unmatched.Reason = replaceReasonCreateFailed
+			result.Unmatched = append(result.Unmatched, unmatched)
+			continue
+		}
+
+		s.mediaProber.Probe(replacement)
+
+		result.Replaced = append(result.Replaced, ReplacedAssignment{
+			ItemType:    string(a.ItemType),
+			ItemID:      a.ItemID,
+			Season:      unmatched.Season,
+			Episode:     unmatched.Episode,
+			OldFilePath: a.FilePath,
+			FilePath:    replacement.FilePath,
+			FileSize:    replacement.FileSize,
+			Resolution:  replacement.Resolution,
+		})
+	}
+
+	s.log.Info("Torrent replaced",
+		"old_info_hash", oldInfoHash,
+		"new_info_hash", newInfoHash,
+		"replaced", len(result.Replaced),
+		"unmatched", len(result.Unmatched),
+	)
+
+	// File names and extensions may change, so rebuild the tree in one pass
+	if s.treeUpdater != nil && len(result.Replaced) > 0 {
+		s.treeUpdater.InvalidateTree()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,189 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrDurationUnknown = errors.New("container duration not found")
+
+// Matroska element IDs used for the duration probe
+const (
+	mkvIDInfo           = 0x1549A966
+	mkvIDTimestampScale = 0x2AD7B1
+	mkvIDDuration       = 0x4489
+
+	mkvDefaultTimestampScale = 1_000_000 // nanoseconds per tick
+	mkvMaxInfoSize           = 64 * 1024 // Largest Info element we are willing to read
+)
+
+// ProbeDuration reads a container header to find the media duration:
+// MP4 mvhd timescale/duration or MKV Info Duration scaled by TimestampScale.
+// Only a few small reads are made, so it is cheap on a torrent-backed reader
+// once the header (or MP4 moov-at-end) pieces are available.
+func ProbeDuration(reader io.ReaderAt, fileSize int64, filename string) (time.Duration, error) {
+	mp4First := true
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mkv", ".webm", ".mka":
+		mp4First = false
+	}
+
+	probes := []func() (time.Duration, error){
+		func() (time.Duration, error) { return NewMP4Analyzer(reader, fileSize).Duration() },
+		func() (time.Duration, error) { return NewMKVAnalyzer(reader, fileSize).Duration() },
+	}
+	if !mp4First {
+		probes[0], probes[1] = probes[1], probes[0]
+	}
+
+	for _, probe := range probes {
+		if d, err := probe(); err == nil {
+			return d, nil
+		}
+	}
+	return 0, ErrDurationUnknown
+}
+
+// EstimateBitrate returns the average bitrate in bits per second of a file
+// with the given size and duration, or 0 when either is unknown.
+func EstimateBitrate(fileSize int64, duration time.Duration) int64 {
+	if fileSize <= 0 || duration <= 0 {
+		return 0
+	}
+	return int64(float64(fileSize) * 8 / duration.Seconds())
+}
+
+// Duration returns the movie duration from the mvhd atom inside moov.
+func (a *MP4Analyzer) Duration() (time.Duration, error) {
+	buf := make([]byte, mp4AtomHeaderSize)
+	if _, err := a.reader.ReadAt(buf, 0); err != nil {
+		return 0, err
+	}
+	if !isValidMP4Atom(string(buf[4:8])) {
+		return 0, ErrNotMP4
+	}
+
+	// Top-level atoms are skipped by size, so walking to a moov at the end is cheap
+	moovOffset, moovSize, err := a.findAtom("moov", 0, a.fileSize)
+	if err != nil {
+		return 0, err
+	}
+
+	mvhdOffset, _, err := a.findAtom("mvhd", moovOffset+mp4AtomHeaderSize, moovOffset+moovSize)
+	if err != nil {
+		return 0, ErrDurationUnknown
+	}
+
+	// Full box: version(1) flags(3), then times, timescale and duration
+	body := make([]byte, 32)
+	n, err := a.reader.ReadAt(body, mvhdOffset+mp4AtomHeaderSize)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	body = body[:n]
+
+	var timescale, duration uint64
+	switch {
+	case len(body) >= 20 && body[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(body[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(body[16:20]))
+		if duration == math.MaxUint32 {
+			return 0, ErrDurationUnknown
+		}
+	case len(body) >= 32 && body[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(body[20:24]))
+		duration = binary.BigEndian.Uint64(body[24:32])
+		if duration == math.MaxUint64 {
+			return 0, ErrDurationUnknown
+		}
+	default:
+		return 0, ErrDurationUnknown
+	}
+
+	if timescale == 0 || duration == 0 {
+		return 0, ErrDurationUnknown
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), nil
+}
+
+// Duration returns the segment duration from the Info element.
+func (a *MKVAnalyzer) Duration() (time.Duration, error) {
+	id, dataSize, headerLen, err := a.readElementHeader(0)
+	if err != nil || id != mkvIDEBML || dataSize == mkvUnknownSize {
+		return 0, ErrNotMKV
+	}
+	pos := int64(headerLen) + dataSize
+
+	id, _, headerLen, err = a.readElementHeader(pos)
+	if err != nil || id != mkvIDSegment {
+		return 0, ErrNotMKV
+	}
+	pos += int64(headerLen)
+
+	for i := 0; i < mkvMaxTopLevelElements && pos < a.fileSize; i++ {
+		id, dataSize, headerLen, err = a.readElementHeader(pos)
+		if err != nil || dataSize == mkvUnknownSize {
+			return 0, ErrDurationUnknown
+		}
+
+		switch id {
+		case mkvIDInfo:
+			if dataSize > mkvMaxInfoSize {
+				return 0, ErrDurationUnknown
+			}
+			return a.infoDuration(pos+int64(headerLen), dataSize)
+
+		case mkvIDCluster:
+			// Media data starts; Info always precedes it in practice
+			return 0, ErrDurationUnknown
+		}
+
+		pos += int64(headerLen) + dataSize
+	}
+
+	return 0, ErrDurationUnknown
+}
+
+// infoDuration parses an Info element body for Duration and TimestampScale
+func (a *MKVAnalyzer) infoDuration(start, size int64) (time.Duration, error) {
+	data := make([]byte, size)
+	n, err := a.reader.ReadAt(data, start)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	data = data[:n]
+
+	scale := uint64(mkvDefaultTimestampScale)
+	var ticks float64
+	for len(data) > 0 {
+		id, body, rest, ok := nextEBMLElement(data)
+		if !ok {
+			break
+		}
+		data = rest
+
+		switch id {
+		case mkvIDTimestampScale:
+			if v := readEBMLUint(body); v > 0 {
+				scale = v
+			}
+		case mkvIDDuration:
+			switch len(body) {
+			case 4:
+				ticks = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+			case 8:
+				ticks = math.Float64frombits(binary.BigEndian.Uint64(body))
+			}
+		}
+	}
+
+	if ticks <= 0 || math.IsNaN(ticks) || math.IsInf(ticks, 0) {
+		return 0, ErrDurationUnknown
+	}
+	return time.Duration(ticks * float64(scale)), nil
+}
@@ -0,0 +1,99 @@
+package streaming
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// mvhdV0 builds a version 0 mvhd atom
+func mvhdV0(timescale, duration uint32) []byte {
+	body := make([]byte, 100)
+	binary.BigEndian.PutUint32(body[12:16], timescale)
+	binary.BigEndian.PutUint32(body[16:20], duration)
+	return makeAtomWithData("mvhd", body)
+}
+
+func TestMP4Duration(t *testing.T) {
+	mdat := makeAtomWithData("mdat", make([]byte, 4096))
+	moov := makeAtomWithData("moov", mvhdV0(1000, 5_400_000)) // 90 minutes
+
+	// moov at end, after the media data
+	var data []byte
+	data = append(data, makeAtom("ftyp", 8)...)
+	data = append(data, mdat...)
+	data = append(data, moov...)
+
+	d, err := ProbeDuration(&bytesReaderAt{data}, int64(len(data)), "movie.mp4")
+	if err != nil {
+		t.Fatalf("ProbeDuration() error = %v", err)
+	}
+	if d != 90*time.Minute {
+		t.Errorf("duration = %v, want 90m", d)
+	}
+}
+
+func TestMP4DurationVersion1(t *testing.T) {
+	body := make([]byte, 112)
+	body[0] = 1
+	binary.BigEndian.PutUint32(body[20:24], 90000)
+	binary.BigEndian.PutUint64(body[24:32], 90000*42)
+	moov := makeAtomWithData("moov", makeAtomWithData("mvhd", body))
+
+	data := append(makeAtom("ftyp", 8), moov...)
+
+	d, err := NewMP4Analyzer(&bytesReaderAt{data}, int64(len(data))).Duration()
+	if err != nil {
+		t.Fatalf("Duration() error = %v", err)
+	}
+	if d != 42*time.Second {
+		t.Errorf("duration = %v, want 42s", d)
+	}
+}
+
+func TestMKVDuration(t *testing.T) {
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(2_700_000)) // ms ticks: 45 minutes
+
+	info := append(
+		mkvElement(mkvIDTimestampScale, []byte{0x0F, 0x42, 0x40}), // 1,000,000 ns
+		mkvElement(mkvIDDuration, duration)...,
+	)
+
+	header := mkvElement(mkvIDEBML, []byte{0x42, 0x82, 0x88, 'm', 'a', 't', 'r', 'o', 's', 'k', 'a'})
+	body := append(mkvElement(mkvIDInfo, info), mkvElement(mkvIDCluster, make([]byte, 1024))...)
+	data := append(header, mkvElement(mkvIDSegment, body)...)
+
+	d, err := ProbeDuration(&bytesReaderAt{data}, int64(len(data)), "episode.mkv")
+	if err != nil {
+		t.Fatalf("ProbeDuration() error = %v", err)
+	}
+	if d != 45*time.Minute {
+		t.Errorf("duration = %v, want 45m", d)
+	}
+}
+
+func TestProbeDurationUnknown(t *testing.T) {
+	// MKV whose Info has no Duration (e.g. a live recording)
+	header := mkvElement(mkvIDEBML, []byte{0x42, 0x82, 0x84, 'w', 'e', 'b', 'm'})
+	data := append(header, mkvElement(mkvIDSegment, mkvElement(mkvIDInfo, nil))...)
+
+	if _, err := ProbeDuration(&bytesReaderAt{data}, int64(len(data)), "live.webm"); err != ErrDurationUnknown {
+		t.Errorf("ProbeDuration() error = %v, want ErrDurationUnknown", err)
+	}
+
+	if _, err := ProbeDuration(&bytesReaderAt{[]byte("not a video")}, 11, "file.avi"); err != ErrDurationUnknown {
+		t.Errorf("ProbeDuration() error = %v, want ErrDurationUnknown", err)
+	}
+}
+
+func TestEstimateBitrate(t *testing.T) {
+	// 1.5 GB over 90 minutes
+	if got := EstimateBitrate(1_500_000_000, 90*time.Minute); got != 2_222_222 {
+		t.Errorf("EstimateBitrate() = %d, want 2222222", got)
+	}
+	if got := EstimateBitrate(1_000, 0); got != 0 {
+		t.Errorf("EstimateBitrate() with unknown duration = %d, want 0", got)
+	}
+}
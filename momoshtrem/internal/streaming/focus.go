@@ -0,0 +1,218 @@
+package streaming
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/types"
+)
+
+// Files of a multi-file torrent share the piece queue, so pieces left at normal
+// priority by earlier streams (e.g. episodes 1-2 of a season pack) compete with
+// the episode being watched. The focus registry tracks which files of each
+// torrent have open readers: while any file is active, incomplete pieces that
+// belong only to inactive files are demoted to none, and once the last reader
+// of the torrent closes the demoted pieces are restored to normal.
+
+// pieceRange is a file's piece index range (inclusive begin, exclusive end).
+type pieceRange struct {
+	begin int
+	end   int
+}
+
+func (r pieceRange) contains(piece int) bool {
+	return piece >= r.begin && piece < r.end
+}
+
+// focusPieces is the piece state the registry reads and changes.
+// Replaceable in tests, where no real torrent is available.
+type focusPieces interface {
+	complete(piece int) bool
+	priority(piece int) types.PiecePriority
+	setPriority(piece int, prio types.PiecePriority)
+}
+
+// torrentPieces adapts a torrent to focusPieces.
+type torrentPieces struct {
+	t *torrent.Torrent
+}
+
+func (p torrentPieces) complete(piece int) bool {
+	return p.t.Piece(piece).State().Complete
+}
+
+func (p torrentPieces) priority(piece int) types.PiecePriority {
+	return p.t.Piece(piece).State().Priority
+}
+
+func (p torrentPieces) setPriority(piece int, prio types.PiecePriority) {
+	p.t.Piece(piece).SetPriority(prio)
+}
+
+// torrentFocus is the focus state of a single torrent.
+type torrentFocus struct {
+	pieces  focusPieces
+	files   []pieceRange     // piece range of every file in the torrent
+	active  map[int]int      // open readers per file index
+	demoted map[int]struct{} // pieces lowered to none, restored when nothing is active
+}
+
+// focusRegistry coordinates piece priorities between files of the same torrent.
+type focusRegistry struct {
+	mu       sync.Mutex
+	torrents map[any]*torrentFocus
+}
+
+func newFocusRegistry() *focusRegistry {
+	return &focusRegistry{torrents: make(map[any]*torrentFocus)}
+}
+
+// focus is shared by all priority readers in the process.
+var focus = newFocusRegistry()
+
+// acquireFile marks a torrent file as streamed and demotes leftover priorities
+// of the torrent's other files.
+func (r *focusRegistry) acquireFile(t *torrent.Torrent, file *torrent.File) {
+	if t == nil || file == nil || t.Info() == nil {
+		return
+	}
+	r.acquire(t, torrentPieces{t}, filePieceRanges(t), fileIndex(t, file))
+}
+
+// releaseFile undoes acquireFile when the file's reader closes.
+func (r *focusRegistry) releaseFile(t *torrent.Torrent, file *torrent.File) {
+	if t == nil || file == nil || t.Info() == nil {
+		return
+	}
+	r.release(t, fileIndex(t, file))
+}
+
+// acquire registers an open reader for file index idx of the torrent identified by key.
+func (r *focusRegistry) acquire(key any, pieces focusPieces, files []pieceRange, idx int) {
+	if idx < 0 || idx >= len(files) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tf, ok := r.torrents[key]
+	if !ok {
+		tf = &torrentFocus{
+			pieces:  pieces,
+			files:   files,
+			active:  make(map[int]int),
+			demoted: make(map[int]struct{}),
+		}
+		r.torrents[key] = tf
+	}
+
+	tf.active[idx]++
+	if tf.active[idx] > 1 {
+		return
+	}
+
+	demoted := 0
+	for i, fr := range tf.files {
+		if tf.active[i] == 0 {
+			demoted += tf.demote(fr)
+		}
+	}
+	if demoted > 0 {
+		slog.Debug("demoted pieces of inactive files",
+			"component", "prioritizer",
+			"file_index", idx,
+			"pieces", demoted,
+		)
+	}
+}
+
+// release drops an open reader for file index idx. The file's pieces are demoted
+// if other files of the torrent are still streamed; when the last reader closes,
+// every demoted piece is restored to normal priority.
+func (r *focusRegistry) release(key any, idx int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tf, ok := r.torrents[key]
+	if !ok || tf.active[idx] == 0 {
+		return
+	}
+
+	tf.active[idx]--
+	if tf.active[idx] > 0 {
+		return
+	}
+	delete(tf.active, idx)
+
+	if len(tf.active) > 0 {
+		tf.demote(tf.files[idx])
+		return
+	}
+
+	restored := 0
+	for piece := range tf.demoted {
+		if tf.pieces.complete(piece) {
+			continue
+		}
+		tf.pieces.setPriority(piece, types.PiecePriorityNormal)
+		restored++
+	}
+	delete(r.torrents, key)
+
+	if restored > 0 {
+		slog.Debug("restored demoted pieces",
+			"component", "prioritizer",
+			"pieces", restored,
+		)
+	}
+}
+
+// demote lowers incomplete, prioritized pieces of an inactive file to none,
+// skipping pieces shared with an active file. Returns the number of pieces changed.
+func (tf *torrentFocus) demote(fr pieceRange) int {
+	count := 0
+	for piece := fr.begin; piece < fr.end; piece++ {
+		if tf.sharedWithActive(piece) {
+			continue
+		}
+		if tf.pieces.complete(piece) || tf.pieces.priority(piece) == types.PiecePriorityNone {
+			continue
+		}
+		tf.pieces.setPriority(piece, types.PiecePriorityNone)
+		tf.demoted[piece] = struct{}{}
+		count++
+	}
+	return count
+}
+
+// sharedWithActive reports whether a piece holds data of any active file.
+func (tf *torrentFocus) sharedWithActive(piece int) bool {
+	for idx := range tf.active {
+		if tf.files[idx].contains(piece) {
+			return true
+		}
+	}
+	return false
+}
+
+// filePieceRanges returns the piece range of every file in the torrent.
+func filePieceRanges(t *torrent.Torrent) []pieceRange {
+	files := t.Files()
+	ranges := make([]pieceRange, len(files))
+	for i, f := range files {
+		ranges[i] = pieceRange{begin: f.BeginPieceIndex(), end: f.EndPieceIndex()}
+	}
+	return ranges
+}
+
+// fileIndex returns the index of file within the torrent, or -1.
+func fileIndex(t *torrent.Torrent, file *torrent.File) int {
+	for i, f := range t.Files() {
+		if f == file || f.Path() == file.Path() {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,113 @@
+package streaming
+
+import (
+	"testing"
+
+	"github.com/anacrolix/torrent/types"
+)
+
+// fakePieces is an in-memory focusPieces.
+type fakePieces struct {
+	prio map[int]types.PiecePriority
+	done map[int]bool
+}
+
+func newFakePieces() *fakePieces {
+	return &fakePieces{prio: make(map[int]types.PiecePriority), done: make(map[int]bool)}
+}
+
+func (p *fakePieces) complete(piece int) bool                         { return p.done[piece] }
+func (p *fakePieces) priority(piece int) types.PiecePriority          { return p.prio[piece] }
+func (p *fakePieces) setPriority(piece int, prio types.PiecePriority) { p.prio[piece] = prio }
+
+func TestFocusDemotesInactiveFiles(t *testing.T) {
+	r := newFocusRegistry()
+	pieces := newFakePieces()
+	key := new(int)
+
+	// Three episodes; piece 4 is shared by episodes 1 and 2
+	files := []pieceRange{{0, 5}, {4, 8}, {8, 12}}
+
+	// Leftovers of an earlier stream of episode 1, one already downloaded
+	for i := 0; i < 5; i++ {
+		pieces.prio[i] = types.PiecePriorityNormal
+	}
+	pieces.done[1] = true
+
+	r.acquire(key, pieces, files, 1)
+
+	for _, i := range []int{0, 2, 3} {
+		if pieces.prio[i] != types.PiecePriorityNone {
+			t.Errorf("piece %d priority = %v, want none", i, pieces.prio[i])
+		}
+	}
+	if pieces.prio[1] != types.PiecePriorityNormal {
+		t.Errorf("complete piece 1 was changed to %v", pieces.prio[1])
+	}
+	if pieces.prio[4] != types.PiecePriorityNormal {
+		t.Errorf("piece 4 shared with the active file was demoted to %v", pieces.prio[4])
+	}
+
+	r.release(key, 1)
+
+	for _, i := range []int{0, 2, 3, 4} {
+		if pieces.prio[i] != types.PiecePriorityNormal {
+			t.Errorf("after release piece %d priority = %v, want normal", i, pieces.prio[i])
+		}
+	}
+	if len(r.torrents) != 0 {
+		t.Errorf("torrent still tracked after last release")
+	}
+}
+
+func TestFocusReleaseWhileOtherFileActive(t *testing.T) {
+	r := newFocusRegistry()
+	pieces := newFakePieces()
+	key := new(int)
+	files := []pieceRange{{0, 4}, {4, 8}}
+
+	r.acquire(key, pieces, files, 0)
+	r.acquire(key, pieces, files, 1)
+
+	// Episode 1's reader closes and leaves its window at normal priority
+	for i := 0; i < 4; i++ {
+		pieces.prio[i] = types.PiecePriorityNormal
+	}
+	r.release(key, 0)
+
+	for i := 0; i < 4; i++ {
+		if pieces.prio[i] != types.PiecePriorityNone {
+			t.Errorf("piece %d priority = %v, want none while episode 2 streams", i, pieces.prio[i])
+		}
+	}
+
+	r.release(key, 1)
+
+	for i := 0; i < 4; i++ {
+		if pieces.prio[i] != types.PiecePriorityNormal {
+			t.Errorf("after last release piece %d priority = %v, want normal", i, pieces.prio[i])
+		}
+	}
+}
+
+func TestFocusCountsReadersPerFile(t *testing.T) {
+	r := newFocusRegistry()
+	pieces := newFakePieces()
+	key := new(int)
+	files := []pieceRange{{0, 4}, {4, 8}}
+
+	r.acquire(key, pieces, files, 1)
+	r.acquire(key, pieces, files, 1)
+	r.release(key, 1)
+
+	if _, ok := r.torrents[key]; !ok {
+		t.Fatal("torrent released while a reader is still open")
+	}
+
+	r.release(key, 1)
+	r.release(key, 1) // extra release is ignored
+
+	if len(r.torrents) != 0 {
+		t.Errorf("torrent still tracked after last release")
+	}
+}
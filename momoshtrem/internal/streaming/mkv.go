@@ -14,8 +14,27 @@ const (
 	// Conservative header size for MKV files.
 	// MKV SeekHead and Cues elements are typically within first 10-20MB.
 	mkvDefaultHeaderSize = 20 * 1024 * 1024 // 20MB
+
+	// Limits for the EBML walk so detection stays cheap on a torrent-backed reader
+	mkvMaxTopLevelElements = 16        // Segment children inspected before giving up
+	mkvMaxSeekHeadSize     = 64 * 1024 // Largest SeekHead we are willing to read
 )
 
+// Matroska element IDs (with length marker bits, as they appear on disk)
+const (
+	mkvIDEBML         = 0x1A45DFA3
+	mkvIDSegment      = 0x18538067
+	mkvIDSeekHead     = 0x114D9B74
+	mkvIDSeek         = 0x4DBB
+	mkvIDSeekID       = 0x53AB
+	mkvIDSeekPosition = 0x53AC
+	mkvIDCues         = 0x1C53BB6B
+	mkvIDCluster      = 0x1F43B675
+)
+
+// mkvUnknownSize marks an element whose size field is all ones (live/streamed muxing)
+const mkvUnknownSize = -1
+
 // MKVAnalyzer detects MKV/WebM structure for streaming prioritization.
 // It verifies the EBML signature and returns conservative header size estimates.
 type MKVAnalyzer struct {
@@ -33,6 +52,8 @@ func NewMKVAnalyzer(reader io.ReaderAt, fileSize int64) *MKVAnalyzer {
 
 // Analyze checks if file is MKV/WebM and returns format info.
 // MKV files start with the EBML signature (0x1A 0x45 0xDF 0xA3).
+// When the Cues (seek index) location can be read from the SeekHead, it is
+// reported so the prioritizer can fetch a tail index like an MP4 moov-at-end.
 func (a *MKVAnalyzer) Analyze() (*FormatInfo, error) {
 	// Read first 4 bytes to check EBML signature
 	buf := make([]byte, 4)
@@ -52,15 +73,234 @@ func (a *MKVAnalyzer) Analyze() (*FormatInfo, error) {
 	}
 
 	// MKV detected - use conservative header estimate
-	// Full EBML parsing is complex; 20MB covers most SeekHead/Cues
 	headerSize := int64(mkvDefaultHeaderSize)
 	if a.fileSize < headerSize {
 		headerSize = a.fileSize
 	}
 
-	return &FormatInfo{
+	info := &FormatInfo{
 		Format:      FormatMKV,
 		HeaderSize:  headerSize,
 		NeedsFooter: true, // Cues element may be at end in some MKVs
-	}, nil
+	}
+
+	// Locate Cues; on any parse failure keep the conservative defaults
+	if cuesOffset, cuesSize, ok := a.findCues(); ok {
+		info.CuesOffset = cuesOffset
+		info.CuesSize = cuesSize
+		// Cues inside the prioritized header need no footer fetch
+		info.NeedsFooter = cuesOffset+cuesSize > headerSize
+	}
+
+	return info, nil
+}
+
+// findCues walks the EBML header and the Segment's top-level elements to find
+// the Cues element, either directly or through a SeekHead entry.
+// Returns the absolute offset and total size (header included) of Cues.
+func (a *MKVAnalyzer) findCues() (offset, size int64, ok bool) {
+	// EBML header element
+	id, dataSize, headerLen, err := a.readElementHeader(0)
+	if err != nil || id != mkvIDEBML || dataSize == mkvUnknownSize {
+		return 0, 0, false
+	}
+	pos := int64(headerLen) + dataSize
+
+	// Segment element; SeekPosition values are relative to its data start
+	id, _, headerLen, err = a.readElementHeader(pos)
+	if err != nil || id != mkvIDSegment {
+		return 0, 0, false
+	}
+	segmentStart := pos + int64(headerLen)
+
+	pos = segmentStart
+	for i := 0; i < mkvMaxTopLevelElements && pos < a.fileSize; i++ {
+		id, dataSize, headerLen, err = a.readElementHeader(pos)
+		if err != nil {
+			return 0, 0, false
+		}
+
+		switch id {
+		case mkvIDCues:
+			if dataSize == mkvUnknownSize {
+				return 0, 0, false
+			}
+			return pos, int64(headerLen) + dataSize, true
+
+		case mkvIDSeekHead:
+			if dataSize == mkvUnknownSize || dataSize > mkvMaxSeekHeadSize {
+				return 0, 0, false
+			}
+			if cuesPos, found := a.seekHeadCuesPosition(pos+int64(headerLen), dataSize); found {
+				return a.cuesAt(segmentStart + cuesPos)
+			}
+
+		case mkvIDCluster:
+			// Media data starts; Cues (if any) were not announced up front
+			return 0, 0, false
+		}
+
+		if dataSize == mkvUnknownSize {
+			return 0, 0, false
+		}
+		pos += int64(headerLen) + dataSize
+	}
+
+	return 0, 0, false
+}
+
+// cuesAt verifies a Cues element at the given absolute offset and returns its extent
+func (a *MKVAnalyzer) cuesAt(offset int64) (int64, int64, bool) {
+	if offset <= 0 || offset >= a.fileSize {
+		return 0, 0, false
+	}
+	id, dataSize, headerLen, err := a.readElementHeader(offset)
+	if err != nil || id != mkvIDCues || dataSize == mkvUnknownSize {
+		return 0, 0, false
+	}
+	size := int64(headerLen) + dataSize
+	if offset+size > a.fileSize {
+		size = a.fileSize - offset
+	}
+	return offset, size, true
+}
+
+// seekHeadCuesPosition reads a SeekHead body and returns the Cues SeekPosition
+func (a *MKVAnalyzer) seekHeadCuesPosition(start, size int64) (int64, bool) {
+	data := make([]byte, size)
+	n, err := a.reader.ReadAt(data, start)
+	if err != nil && err != io.EOF {
+		return 0, false
+	}
+	data = data[:n]
+
+	for len(data) > 0 {
+		id, body, rest, ok := nextEBMLElement(data)
+		if !ok {
+			return 0, false
+		}
+		data = rest
+		if id != mkvIDSeek {
+			continue
+		}
+
+		var seekID, seekPos uint64
+		var hasID, hasPos bool
+		for len(body) > 0 {
+			childID, childBody, childRest, ok := nextEBMLElement(body)
+			if !ok {
+				break
+			}
+			body = childRest
+			switch childID {
+			case mkvIDSeekID:
+				seekID, hasID = readEBMLUint(childBody), true
+			case mkvIDSeekPosition:
+				seekPos, hasPos = readEBMLUint(childBody), true
+			}
+		}
+		if hasID && hasPos && seekID == mkvIDCues {
+			return int64(seekPos), true
+		}
+	}
+	return 0, false
+}
+
+// readElementHeader reads an element ID and data size at the given offset.
+// dataSize is mkvUnknownSize when the size field is all ones.
+func (a *MKVAnalyzer) readElementHeader(offset int64) (id uint32, dataSize int64, headerLen int, err error) {
+	buf := make([]byte, 12) // 4-byte ID + 8-byte size at most
+	n, err := a.reader.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return 0, 0, 0, err
+	}
+	buf = buf[:n]
+
+	id, idLen, ok := readEBMLID(buf)
+	if !ok {
+		return 0, 0, 0, ErrNotMKV
+	}
+	size, sizeLen, ok := readEBMLSize(buf[idLen:])
+	if !ok {
+		return 0, 0, 0, ErrNotMKV
+	}
+	return id, size, idLen + sizeLen, nil
+}
+
+// nextEBMLElement splits the first element off an in-memory buffer
+func nextEBMLElement(data []byte) (id uint32, body, rest []byte, ok bool) {
+	id, idLen, ok := readEBMLID(data)
+	if !ok {
+		return 0, nil, nil, false
+	}
+	size, sizeLen, ok := readEBMLSize(data[idLen:])
+	if !ok || size == mkvUnknownSize {
+		return 0, nil, nil, false
+	}
+	start := idLen + sizeLen
+	if int64(len(data)-start) < size {
+		return 0, nil, nil, false
+	}
+	end := start + int(size)
+	return id, data[start:end], data[end:], true
+}
+
+// vintLength returns the total length of a variable-size integer from its first byte
+func vintLength(first byte, maxLen int) int {
+	for length := 1; length <= maxLen; length++ {
+		if first&(0x80>>(length-1)) != 0 {
+			return length
+		}
+	}
+	return 0
+}
+
+// readEBMLID reads an element ID (1-4 bytes), keeping the length marker bits
+func readEBMLID(buf []byte) (uint32, int, bool) {
+	if len(buf) == 0 {
+		return 0, 0, false
+	}
+	length := vintLength(buf[0], 4)
+	if length == 0 || len(buf) < length {
+		return 0, 0, false
+	}
+	var id uint32
+	for i := 0; i < length; i++ {
+		id = id<<8 | uint32(buf[i])
+	}
+	return id, length, true
+}
+
+// readEBMLSize reads an element data size (1-8 bytes) with the length marker removed
+func readEBMLSize(buf []byte) (int64, int, bool) {
+	if len(buf) == 0 {
+		return 0, 0, false
+	}
+	length := vintLength(buf[0], 8)
+	if length == 0 || len(buf) < length {
+		return 0, 0, false
+	}
+
+	value := uint64(buf[0] & (0xFF >> length))
+	allOnes := value == uint64(0xFF>>length)
+	for i := 1; i < length; i++ {
+		value = value<<8 | uint64(buf[i])
+		allOnes = allOnes && buf[i] == 0xFF
+	}
+	if allOnes {
+		return mkvUnknownSize, length, true
+	}
+	if value > 1<<62 {
+		return 0, 0, false
+	}
+	return int64(value), length, true
+}
+
+// readEBMLUint decodes a big-endian unsigned integer element body (up to 8 bytes)
+func readEBMLUint(body []byte) uint64 {
+	var v uint64
+	for i := 0; i < len(body) && i < 8; i++ {
+		v = v<<8 | uint64(body[i])
+	}
+	return v
 }
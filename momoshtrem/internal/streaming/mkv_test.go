@@ -108,3 +108,137 @@ func TestMKVAnalyzerPartialSignature(t *testing.T) {
 		t.Errorf("Analyze() error = %v, want ErrNotMKV", err)
 	}
 }
+
+// mkvElement encodes an EBML element with an 8-byte size field
+func mkvElement(id uint32, payload []byte) []byte {
+	var out []byte
+	for shift := 24; shift >= 0; shift -= 8 {
+		if b := byte(id >> shift); b != 0 || len(out) > 0 {
+			out = append(out, b)
+		}
+	}
+	size := uint64(len(payload))
+	out = append(out, 0x01)
+	for shift := 48; shift >= 0; shift -= 8 {
+		out = append(out, byte(size>>shift))
+	}
+	return append(out, payload...)
+}
+
+// mkvFile builds a minimal Matroska file whose SeekHead points at a Cues
+// element placed after padding bytes of cluster data.
+func mkvFile(padding int) (data []byte, cuesOffset int64) {
+	header := mkvElement(mkvIDEBML, []byte{0x42, 0x82, 0x88, 'm', 'a', 't', 'r', 'o', 's', 'k', 'a'})
+	cues := mkvElement(mkvIDCues, make([]byte, 32))
+	cluster := mkvElement(mkvIDCluster, make([]byte, padding))
+
+	// SeekHead size doesn't depend on the position value (fixed 8-byte field)
+	seekHead := func(pos uint64) []byte {
+		posBytes := make([]byte, 8)
+		for i := 0; i < 8; i++ {
+			posBytes[i] = byte(pos >> (56 - 8*i))
+		}
+		seek := append(mkvElement(mkvIDSeekID, []byte{0x1C, 0x53, 0xBB, 0x6B}), mkvElement(mkvIDSeekPosition, posBytes)...)
+		return mkvElement(mkvIDSeekHead, mkvElement(mkvIDSeek, seek))
+	}
+
+	cuesPos := uint64(len(seekHead(0)) + len(cluster))
+	body := append(append(seekHead(cuesPos), cluster...), cues...)
+	segment := mkvElement(mkvIDSegment, body)
+
+	segmentDataStart := len(header) + len(segment) - len(body)
+	data = append(header, segment...)
+	return data, int64(segmentDataStart) + int64(cuesPos)
+}
+
+func TestMKVAnalyzerCuesAtTail(t *testing.T) {
+	data, wantOffset := mkvFile(4096)
+
+	reader := &bytesReaderAt{data: data}
+	analyzer := NewMKVAnalyzer(reader, int64(len(data)))
+
+	info, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if info.CuesOffset != wantOffset {
+		t.Errorf("CuesOffset = %d, want %d", info.CuesOffset, wantOffset)
+	}
+	if info.CuesSize != int64(len(mkvElement(mkvIDCues, make([]byte, 32)))) {
+		t.Errorf("CuesSize = %d, want %d", info.CuesSize, len(mkvElement(mkvIDCues, make([]byte, 32))))
+	}
+	if info.CuesOffset+info.CuesSize != int64(len(data)) {
+		t.Errorf("Cues should end at file end: offset %d + size %d != %d", info.CuesOffset, info.CuesSize, len(data))
+	}
+
+	start, end, ok := info.TailIndexRange()
+	if !ok || start != info.CuesOffset || end != info.CuesOffset+info.CuesSize {
+		t.Errorf("TailIndexRange() = %d, %d, %v", start, end, ok)
+	}
+}
+
+func TestMKVAnalyzerCuesWithinHeader(t *testing.T) {
+	data, _ := mkvFile(16)
+
+	reader := &bytesReaderAt{data: data}
+	analyzer := NewMKVAnalyzer(reader, 1024*1024*1024)
+
+	info, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if info.CuesOffset == 0 {
+		t.Fatal("CuesOffset should be detected")
+	}
+	if info.NeedsFooter {
+		t.Error("NeedsFooter should be false when Cues lie within the header window")
+	}
+}
+
+func TestMKVAnalyzerNoSeekHead(t *testing.T) {
+	header := mkvElement(mkvIDEBML, nil)
+	cluster := mkvElement(mkvIDCluster, make([]byte, 64))
+	data := append(header, mkvElement(mkvIDSegment, cluster)...)
+
+	reader := &bytesReaderAt{data: data}
+	analyzer := NewMKVAnalyzer(reader, int64(len(data)))
+
+	info, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if info.CuesOffset != 0 || info.CuesSize != 0 {
+		t.Errorf("Cues = %d/%d, want unknown", info.CuesOffset, info.CuesSize)
+	}
+	if !info.NeedsFooter {
+		t.Error("NeedsFooter should stay true when Cues location is unknown")
+	}
+}
+
+func TestReadEBMLSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		buf     []byte
+		want    int64
+		wantLen int
+		wantOK  bool
+	}{
+		{"one byte", []byte{0x81}, 1, 1, true},
+		{"two bytes", []byte{0x40, 0x02}, 2, 2, true},
+		{"eight bytes", []byte{0x01, 0, 0, 0, 0, 0, 0x01, 0x00}, 256, 8, true},
+		{"unknown size", []byte{0xFF}, mkvUnknownSize, 1, true},
+		{"invalid marker", []byte{0x00}, 0, 0, false},
+		{"truncated", []byte{0x40}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotLen, ok := readEBMLSize(tt.buf)
+			if got != tt.want || gotLen != tt.wantLen || ok != tt.wantOK {
+				t.Errorf("readEBMLSize(%x) = %d, %d, %v; want %d, %d, %v",
+					tt.buf, got, gotLen, ok, tt.want, tt.wantLen, tt.wantOK)
+			}
+		})
+	}
+}
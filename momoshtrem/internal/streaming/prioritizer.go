@@ -3,6 +3,7 @@ package streaming
 import (
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/anacrolix/torrent"
 	"github.com/anacrolix/torrent/types"
@@ -28,7 +29,9 @@ type Prioritizer struct {
 	fileLength int64
 
 	// Tracking
-	initialized bool
+	initialized   bool
+	released      bool // set on Release; further priority updates are ignored
+	footerRelaxed bool // Footer returned to normal priority after format detection
 
 	// Debouncing: track last seek position to avoid redundant priority updates
 	lastSeekOffset int64
@@ -38,6 +41,23 @@ type Prioritizer struct {
 	lastUrgentEnd    int64
 	lastReadaheadEnd int64
 
+	// Throughput estimation for adaptive readahead. The anchor is the position
+	// and time of the last sample; throughput is an EWMA in bytes/sec (0 = unknown).
+	sampleOffset int64
+	sampleTime   time.Time
+	throughput   float64
+	now          func() time.Time // Clock, replaceable in tests (nil = time.Now)
+
+	// Piece completion check, replaceable in tests (nil = torrent piece state)
+	pieceComplete func(piece int) bool
+
+	// Piece priority setter, replaceable in tests (nil = torrent piece)
+	setPiecePriority func(piece int, priority types.PiecePriority)
+
+	// Key of the file in the shared release registry; nil when this
+	// prioritizer is the file's only reader (e.g. in tests)
+	holdKey any
+
 	// Metrics callbacks (nil-safe)
 	onSeek      func(forward bool) // called on each non-debounced seek
 	onDowngrade func(count int)    // called with number of pieces downgraded
@@ -45,6 +65,13 @@ type Prioritizer struct {
 	log *slog.Logger
 }
 
+// Throughput sampling limits
+const (
+	minThroughputSample = time.Second      // Shorter intervals accumulate into the next sample
+	maxThroughputSample = 30 * time.Second // Longer gaps are pauses, not playback
+	throughputAlpha     = 0.3              // EWMA weight of the newest sample
+)
+
 // NewPrioritizer creates a prioritizer for a file within a torrent.
 func NewPrioritizer(t *torrent.Torrent, file *torrent.File, cfg Config) *Prioritizer {
 	info := t.Info()
@@ -52,6 +79,7 @@ func NewPrioritizer(t *torrent.Torrent, file *torrent.File, cfg Config) *Priorit
 		return nil
 	}
 
+	held.acquire(file)
 	return &Prioritizer{
 		t:              t,
 		file:           file,
@@ -62,10 +90,68 @@ func NewPrioritizer(t *torrent.Torrent, file *torrent.File, cfg Config) *Priorit
 		fileOffset:     file.Offset(),
 		fileLength:     file.Length(),
 		lastSeekOffset: -1, // sentinel: never seeked
+		holdKey:        file,
 		log:            slog.With("component", "prioritizer", "file", file.Path()),
 	}
 }
 
+// byteRange is a file-relative byte range (inclusive start, exclusive end).
+type byteRange struct {
+	start int64
+	end   int64
+}
+
+// heldFile is the release state of one file's open readers.
+type heldFile struct {
+	readers int
+	ranges  []byteRange // raised by readers that have already closed
+}
+
+// releaseRegistry defers downgrading a file's pieces until its last reader
+// closes. Readers of the same file raise overlapping pieces, so one closing
+// must not lower pieces another still plays from.
+type releaseRegistry struct {
+	mu    sync.Mutex
+	files map[any]*heldFile
+}
+
+// held is shared by all prioritizers in the process.
+var held = &releaseRegistry{files: make(map[any]*heldFile)}
+
+// acquire registers an open reader of the file identified by key.
+func (r *releaseRegistry) acquire(key any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hf, ok := r.files[key]
+	if !ok {
+		hf = &heldFile{}
+		r.files[key] = hf
+	}
+	hf.readers++
+}
+
+// release drops an open reader that raised ranges. It returns every range
+// raised by the file's readers once the last one closes, or false while
+// others remain open.
+func (r *releaseRegistry) release(key any, ranges []byteRange) ([]byteRange, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hf, ok := r.files[key]
+	if !ok {
+		return ranges, true
+	}
+
+	hf.readers--
+	hf.ranges = append(hf.ranges, ranges...)
+	if hf.readers > 0 {
+		return nil, false
+	}
+	delete(r.files, key)
+	return hf.ranges, true
+}
+
 // InitialPrioritize sets header and footer pieces to HIGH priority.
 // Should be called when file is first opened for streaming.
 func (p *Prioritizer) InitialPrioritize() {
@@ -76,7 +162,7 @@ func (p *Prioritizer) InitialPrioritize() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.initialized {
+	if p.initialized || p.released {
 		return
 	}
 
@@ -85,13 +171,8 @@ func (p *Prioritizer) InitialPrioritize() {
 	p.setPieceRangePriority(0, headerEnd, types.PiecePriorityHigh)
 
 	// Prioritize footer (for MP4 moov-at-end cases)
-	if p.fileLength > p.cfg.FooterPriorityBytes {
-		footerStart := p.fileLength - p.cfg.FooterPriorityBytes
-		p.setPieceRangePriority(footerStart, p.fileLength, types.PiecePriorityHigh)
-	} else {
-		// File is smaller than footer size, entire file is high priority
-		p.setPieceRangePriority(0, p.fileLength, types.PiecePriorityHigh)
-	}
+	footerStart, footerEnd := p.footerRange()
+	p.setPieceRangePriority(footerStart, footerEnd, types.PiecePriorityHigh)
 
 	p.initialized = true
 	p.log.Debug("initial prioritization complete",
@@ -102,7 +183,7 @@ func (p *Prioritizer) InitialPrioritize() {
 }
 
 // SetFormatInfo updates prioritization based on detected format.
-// For example, if MP4 moov atom is at end of file, those pieces get HIGH priority.
+// For example, if MP4 moov atom or MKV Cues sit past the header, those pieces get HIGH priority.
 func (p *Prioritizer) SetFormatInfo(info *FormatInfo) {
 	if p == nil || info == nil {
 		return
@@ -111,16 +192,60 @@ func (p *Prioritizer) SetFormatInfo(info *FormatInfo) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.released {
+		return
+	}
+
 	p.formatInfo = info
 
-	// For MP4 with moov at end, ensure those pieces are HIGH priority
-	if info.Format == FormatMP4 && info.MoovOffset > 0 && info.MoovSize > 0 {
-		p.setPieceRangePriority(info.MoovOffset, info.MoovOffset+info.MoovSize, types.PiecePriorityHigh)
-		p.log.Debug("prioritized moov atom",
-			"offset", info.MoovOffset,
-			"size", info.MoovSize,
+	// For MP4 moov-at-end or MKV Cues, ensure the index pieces are HIGH priority
+	if start, end, ok := info.TailIndexRange(); ok {
+		p.setPieceRangePriority(start, end, types.PiecePriorityHigh)
+		p.log.Debug("prioritized seek index",
+			"format", info.Format.String(),
+			"offset", start,
+			"size", end-start,
 		)
 	}
+
+	// The index sits at the front (e.g. WEB-DL MP4 with moov first), so the
+	// footer raised up front won't be read early
+	if !info.NeedsFooter && p.initialized && !p.footerRelaxed {
+		downgraded := p.relaxFooter()
+		p.footerRelaxed = true
+		if downgraded > 0 && p.onDowngrade != nil {
+			p.onDowngrade(downgraded)
+		}
+		p.log.Debug("relaxed footer priority", "format", info.Format.String(), "downgraded", downgraded)
+	}
+}
+
+// footerRange returns the byte range InitialPrioritize raises for the footer.
+// A file smaller than the footer budget is prioritized whole.
+func (p *Prioritizer) footerRange() (start, end int64) {
+	if p.fileLength > p.cfg.FooterPriorityBytes {
+		return p.fileLength - p.cfg.FooterPriorityBytes, p.fileLength
+	}
+	return 0, p.fileLength
+}
+
+// relaxFooter sets the incomplete footer pieces back to normal priority,
+// except pieces shared with the header or the current playback window.
+// Returns the number of pieces changed. Caller must hold p.mu.
+func (p *Prioritizer) relaxFooter() int {
+	start, end := p.pieceRange(p.footerRange())
+	headerStart, headerEnd := p.pieceRange(0, min(p.cfg.HeaderPriorityBytes, p.fileLength))
+	windowStart, windowEnd := p.pieceRange(p.lastUrgentStart, p.lastReadaheadEnd)
+
+	count := 0
+	for i := start; i < end; i++ {
+		if (i >= headerStart && i < headerEnd) || (i >= windowStart && i < windowEnd) || p.isPieceComplete(i) {
+			continue
+		}
+		p.setPriority(i, types.PiecePriorityNormal)
+		count++
+	}
+	return count
 }
 
 // UpdateForSeek updates priorities based on seek position.
@@ -135,6 +260,10 @@ func (p *Prioritizer) UpdateForSeek(offset int64) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.released {
+		return
+	}
+
 	// Clamp offset to valid range
 	if offset < 0 {
 		offset = 0
@@ -157,6 +286,8 @@ func (p *Prioritizer) UpdateForSeek(offset int64) {
 		}
 	}
 
+	p.observeThroughput(offset)
+
 	forward := offset >= p.lastSeekOffset
 	p.lastSeekOffset = offset
 
@@ -165,8 +296,9 @@ func (p *Prioritizer) UpdateForSeek(offset int64) {
 	}
 
 	// Calculate new ranges
+	readahead := p.readaheadWindow()
 	urgentEnd := min(offset+p.cfg.UrgentBufferBytes, p.fileLength)
-	readaheadEnd := min(urgentEnd+p.cfg.ReadaheadBytes, p.fileLength)
+	readaheadEnd := min(urgentEnd+readahead, p.fileLength)
 
 	// Downgrade pieces from previous ranges that are now behind the cursor.
 	// Only downgrade pieces strictly before the new urgent start — the player
@@ -183,7 +315,8 @@ func (p *Prioritizer) UpdateForSeek(offset int64) {
 	}
 
 	// Downgrade pieces from old range that are now beyond new readahead window.
-	// This handles backward seeks and forward seeks near end of file that shrink the window.
+	// This handles backward seeks, forward seeks near end of file, and the adaptive
+	// window shrinking when measured throughput drops.
 	if p.lastReadaheadEnd > readaheadEnd {
 		downgraded := p.setPieceRangePriorityCount(readaheadEnd, p.lastReadaheadEnd, types.PiecePriorityNormal)
 		if downgraded > 0 && p.onDowngrade != nil {
@@ -208,9 +341,193 @@ func (p *Prioritizer) UpdateForSeek(offset int64) {
 		"offset", offset,
 		"urgent_end", urgentEnd,
 		"readahead_end", readaheadEnd,
+		"readahead_bytes", readahead,
+		"throughput_bps", int64(p.throughput),
 	)
 }
 
+// observeThroughput updates the playback throughput estimate from a new position.
+// Only forward moves that stay within the previously prioritized window count as
+// playback; backward seeks and jumps past the window restart sampling.
+// Caller must hold p.mu.
+func (p *Prioritizer) observeThroughput(offset int64) {
+	now := p.clock()
+
+	delta := offset - p.sampleOffset
+	if p.sampleTime.IsZero() || delta <= 0 || offset > p.lastReadaheadEnd {
+		p.sampleOffset, p.sampleTime = offset, now
+		return
+	}
+
+	elapsed := now.Sub(p.sampleTime)
+	if elapsed < minThroughputSample {
+		return // Accumulate until the interval is long enough to be meaningful
+	}
+	if elapsed > maxThroughputSample {
+		p.sampleOffset, p.sampleTime = offset, now
+		return
+	}
+
+	rate := float64(delta) / elapsed.Seconds()
+	if p.throughput == 0 {
+		p.throughput = rate
+	} else {
+		p.throughput = throughputAlpha*rate + (1-throughputAlpha)*p.throughput
+	}
+	p.sampleOffset, p.sampleTime = offset, now
+}
+
+// readaheadWindow returns the readahead size for the current throughput estimate,
+// aiming for TargetBufferSeconds of buffer within the configured bounds.
+func (p *Prioritizer) readaheadWindow() int64 {
+	lo, hi := p.cfg.readaheadBounds()
+	if p.throughput <= 0 || p.cfg.TargetBufferSeconds <= 0 {
+		return lo
+	}
+	window := int64(p.throughput * p.cfg.TargetBufferSeconds)
+	return max(lo, min(window, hi))
+}
+
+// BufferStatus reports the contiguous complete data from offset onward and the
+// current readahead window.
+func (p *Prioritizer) BufferStatus(offset int64) BufferStatus {
+	if p == nil {
+		return BufferStatus{Offset: offset}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	offset = max(0, min(offset, p.fileLength))
+	status := BufferStatus{
+		Offset:         offset,
+		FileLength:     p.fileLength,
+		PieceLength:    p.pieceLength,
+		ReadaheadBytes: p.readaheadWindow(),
+		ThroughputBps:  p.throughput,
+	}
+	if offset >= p.fileLength || p.pieceLength == 0 {
+		return status
+	}
+
+	start := p.fileOffset + offset
+	bufferedEnd := start
+	for i := p.byteToPiece(start); i < p.endPiece; i++ {
+		if !p.isPieceComplete(i) {
+			break
+		}
+		status.CompletePiecesAhead++
+		bufferedEnd = int64(i+1) * p.pieceLength
+	}
+	status.BufferedBytes = min(bufferedEnd, p.fileOffset+p.fileLength) - start
+
+	return status
+}
+
+// isPieceComplete reports whether a piece has been downloaded and verified.
+func (p *Prioritizer) isPieceComplete(piece int) bool {
+	if p.pieceComplete != nil {
+		return p.pieceComplete(piece)
+	}
+	return p.t.PieceState(piece).Complete
+}
+
+// RangeComplete reports whether every piece holding the file-relative byte
+// range [startByte, endByte) is complete. A nil prioritizer has nothing to wait for.
+func (p *Prioritizer) RangeComplete(startByte, endByte int64) bool {
+	if p == nil {
+		return true
+	}
+	begin, end := p.pieceRange(startByte, endByte)
+	for i := begin; i < end; i++ {
+		if !p.isPieceComplete(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// clock returns the current time from the configured clock.
+func (p *Prioritizer) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// Release downgrades the incomplete pieces this prioritizer raised (header,
+// footer, seek index and the current urgent/readahead window) back to normal priority.
+// Should be called when the file is closed so the client stops fetching content
+// nobody is reading. While other readers of the same file are open, the
+// downgrade is deferred until the last of them is released. Completed pieces
+// are left untouched.
+func (p *Prioritizer) Release() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.released {
+		return
+	}
+	p.released = true
+
+	var raised []byteRange
+	if p.initialized {
+		raised = append(raised, byteRange{0, min(p.cfg.HeaderPriorityBytes, p.fileLength)})
+		if !p.footerRelaxed {
+			start, end := p.footerRange()
+			raised = append(raised, byteRange{start, end})
+		}
+		if start, end, ok := p.formatInfo.TailIndexRange(); ok {
+			raised = append(raised, byteRange{start, end})
+		}
+		if p.lastReadaheadEnd > 0 {
+			raised = append(raised, byteRange{p.lastUrgentStart, p.lastReadaheadEnd})
+		}
+	}
+
+	if p.holdKey != nil {
+		var last bool
+		if raised, last = held.release(p.holdKey, raised); !last {
+			p.log.Debug("kept piece priorities for other readers of the file")
+			return
+		}
+	}
+	if len(raised) == 0 {
+		return
+	}
+
+	downgraded := 0
+	for _, r := range raised {
+		downgraded += p.downgradeIncomplete(r.start, r.end)
+	}
+
+	if downgraded > 0 && p.onDowngrade != nil {
+		p.onDowngrade(downgraded)
+	}
+
+	p.log.Debug("released piece priorities", "downgraded", downgraded)
+}
+
+// downgradeIncomplete sets incomplete pieces covering a byte range to normal
+// priority and returns the number of pieces changed.
+func (p *Prioritizer) downgradeIncomplete(startByte, endByte int64) int {
+	startPiece, endPiece := p.pieceRange(startByte, endByte)
+
+	count := 0
+	for i := startPiece; i < endPiece; i++ {
+		if p.isPieceComplete(i) {
+			continue
+		}
+		p.setPriority(i, types.PiecePriorityNormal)
+		count++
+	}
+	return count
+}
+
 // setPieceRangePriority sets priority for pieces covering a byte range within the file.
 // startByte and endByte are relative to file start (not torrent start).
 func (p *Prioritizer) setPieceRangePriority(startByte, endByte int64, priority types.PiecePriority) {
@@ -220,8 +537,32 @@ func (p *Prioritizer) setPieceRangePriority(startByte, endByte int64, priority t
 // setPieceRangePriorityCount sets priority for pieces covering a byte range
 // and returns the number of pieces updated.
 func (p *Prioritizer) setPieceRangePriorityCount(startByte, endByte int64, priority types.PiecePriority) int {
+	startPiece, endPiece := p.pieceRange(startByte, endByte)
+
+	// Set priority for each piece
+	count := 0
+	for i := startPiece; i < endPiece; i++ {
+		p.setPriority(i, priority)
+		count++
+	}
+	return count
+}
+
+// setPriority sets the download priority of one piece.
+func (p *Prioritizer) setPriority(piece int, priority types.PiecePriority) {
+	if p.setPiecePriority != nil {
+		p.setPiecePriority(piece, priority)
+		return
+	}
+	p.t.Piece(piece).SetPriority(priority)
+}
+
+// pieceRange converts a file-relative byte range to a piece index range
+// (inclusive begin, exclusive end) clamped to the file's pieces.
+// Returns an empty range if startByte >= endByte.
+func (p *Prioritizer) pieceRange(startByte, endByte int64) (int, int) {
 	if startByte >= endByte {
-		return 0
+		return 0, 0
 	}
 
 	// Convert file-relative offsets to torrent-absolute offsets
@@ -239,15 +580,7 @@ func (p *Prioritizer) setPieceRangePriorityCount(startByte, endByte int64, prior
 	if endPiece > p.endPiece {
 		endPiece = p.endPiece
 	}
-
-	// Set priority for each piece
-	count := 0
-	for i := startPiece; i < endPiece; i++ {
-		piece := p.t.Piece(i)
-		piece.SetPriority(priority)
-		count++
-	}
-	return count
+	return startPiece, endPiece
 }
 
 // byteToPiece converts absolute byte offset (torrent-relative) to piece index.
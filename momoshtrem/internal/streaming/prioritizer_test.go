@@ -1,7 +1,12 @@
 package streaming
 
 import (
+	"log/slog"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent/types"
 )
 
 func TestByteToPiece(t *testing.T) {
@@ -95,6 +100,7 @@ func TestNilPrioritizerSafety(t *testing.T) {
 	p.InitialPrioritize()
 	p.SetFormatInfo(&FormatInfo{Format: FormatMP4})
 	p.UpdateForSeek(1000)
+	p.Release()
 
 	if p.PieceLength() != 0 {
 		t.Error("PieceLength() on nil should return 0")
@@ -146,6 +152,34 @@ func TestUpdateForSeekTracksRanges(t *testing.T) {
 	}
 }
 
+func TestReleaseStopsFurtherUpdates(t *testing.T) {
+	// An uninitialized prioritizer has nothing to downgrade, so Release
+	// must not touch the (nil) torrent and must make later updates no-ops.
+	var seekCount int
+	p := &Prioritizer{
+		cfg:            DefaultConfig(),
+		pieceLength:    1024 * 1024,
+		fileLength:     500 * 1024 * 1024,
+		lastSeekOffset: -1,
+		onSeek: func(_ bool) {
+			seekCount++
+		},
+	}
+
+	p.Release()
+	p.Release() // idempotent
+
+	p.UpdateForSeek(100 * 1024 * 1024)
+	p.SetFormatInfo(&FormatInfo{Format: FormatMP4, MoovOffset: 1000, MoovSize: 1000})
+
+	if seekCount != 0 {
+		t.Errorf("expected no seek callbacks after Release, got %d", seekCount)
+	}
+	if p.formatInfo != nil {
+		t.Error("SetFormatInfo should be ignored after Release")
+	}
+}
+
 func TestPriorityCallbacksStruct(t *testing.T) {
 	// Verify PriorityCallbacks can be constructed with both callbacks
 	var seekCalled bool
@@ -170,3 +204,324 @@ func TestPriorityCallbacksStruct(t *testing.T) {
 		t.Error("OnDowngrade callback not called")
 	}
 }
+
+func TestReadaheadWindow(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		throughput float64
+		want       int64
+	}{
+		{"no estimate uses minimum", DefaultConfig(), 0, 32 * mb},
+		{"slow playback clamps to minimum", DefaultConfig(), 100 * 1024, 32 * mb},
+		{"scales with throughput", DefaultConfig(), 2 * mb, 60 * mb},
+		{"fast playback clamps to maximum", DefaultConfig(), 20 * mb, 128 * mb},
+		{"adaptive disabled", Config{ReadaheadBytes: 32 * mb, ReadaheadMaxMultiplier: 4}, 20 * mb, 32 * mb},
+		{"inverted bounds stay fixed", Config{ReadaheadBytes: 32 * mb, ReadaheadMinMultiplier: 2, ReadaheadMaxMultiplier: 1, TargetBufferSeconds: 30}, 20 * mb, 64 * mb},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Prioritizer{cfg: tt.cfg, throughput: tt.throughput}
+			if got := p.readaheadWindow(); got != tt.want {
+				t.Errorf("readaheadWindow() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserveThroughput(t *testing.T) {
+	const mb = 1024 * 1024
+	start := time.Unix(1000, 0)
+
+	tests := []struct {
+		name   string
+		steps  []throughputStep
+		wantBp float64
+	}{
+		{
+			name: "steady playback",
+			steps: []throughputStep{
+				{0, 0},
+				{2 * time.Second, 4 * mb},
+			},
+			wantBp: 2 * mb,
+		},
+		{
+			name: "short intervals accumulate",
+			steps: []throughputStep{
+				{0, 0},
+				{500 * time.Millisecond, 1 * mb},
+				{2 * time.Second, 4 * mb},
+			},
+			wantBp: 2 * mb,
+		},
+		{
+			name: "backward seek restarts sampling",
+			steps: []throughputStep{
+				{0, 10 * mb},
+				{2 * time.Second, 0},
+				{4 * time.Second, 2 * mb},
+			},
+			wantBp: 1 * mb,
+		},
+		{
+			name: "jump past window is not playback",
+			steps: []throughputStep{
+				{0, 0},
+				{2 * time.Second, 500 * mb},
+			},
+			wantBp: 0,
+		},
+		{
+			name: "long pause is not a sample",
+			steps: []throughputStep{
+				{0, 0},
+				{time.Minute, 4 * mb},
+			},
+			wantBp: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var now time.Time
+			p := &Prioritizer{
+				cfg:              DefaultConfig(),
+				lastReadaheadEnd: 100 * mb,
+				now:              func() time.Time { return now },
+			}
+			for _, step := range tt.steps {
+				now = start.Add(step.at)
+				p.observeThroughput(step.offset)
+			}
+			if p.throughput != tt.wantBp {
+				t.Errorf("throughput = %.0f, want %.0f", p.throughput, tt.wantBp)
+			}
+		})
+	}
+}
+
+// throughputStep is a playback position observed at a time offset
+type throughputStep struct {
+	at     time.Duration
+	offset int64
+}
+
+func TestBufferStatus(t *testing.T) {
+	const pieceLen = 100
+
+	// File spans bytes 50..1050 of the torrent (pieces 0-10); pieces 2-5 and 8 are complete
+	complete := map[int]bool{2: true, 3: true, 4: true, 5: true, 8: true}
+	newPrioritizer := func() *Prioritizer {
+		return &Prioritizer{
+			cfg:           DefaultConfig(),
+			pieceLength:   pieceLen,
+			beginPiece:    0,
+			endPiece:      11,
+			fileOffset:    50,
+			fileLength:    1000,
+			pieceComplete: func(piece int) bool { return complete[piece] },
+		}
+	}
+
+	tests := []struct {
+		name       string
+		offset     int64
+		wantPieces int
+		wantBytes  int64
+	}{
+		{"cursor on missing piece", 0, 0, 0},
+		{"cursor mid complete run", 200, 4, 350},
+		{"cursor at run start", 150, 4, 400},
+		{"run ending at file end", 990, 0, 0},
+		{"past end of file", 2000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPrioritizer().BufferStatus(tt.offset)
+			if got.CompletePiecesAhead != tt.wantPieces || got.BufferedBytes != tt.wantBytes {
+				t.Errorf("BufferStatus(%d) = %d pieces, %d bytes; want %d pieces, %d bytes",
+					tt.offset, got.CompletePiecesAhead, got.BufferedBytes, tt.wantPieces, tt.wantBytes)
+			}
+		})
+	}
+
+	t.Run("clamped to file end", func(t *testing.T) {
+		complete[9], complete[10] = true, true
+		defer delete(complete, 9)
+		defer delete(complete, 10)
+		got := newPrioritizer().BufferStatus(900)
+		// Pieces 9-10 cover torrent bytes 900..1100 but the file ends at 1050
+		if got.CompletePiecesAhead != 2 || got.BufferedBytes != 100 {
+			t.Errorf("BufferStatus(900) = %d pieces, %d bytes; want 2 pieces, 100 bytes",
+				got.CompletePiecesAhead, got.BufferedBytes)
+		}
+	})
+}
+
+func TestBufferSeconds(t *testing.T) {
+	if _, ok := (BufferStatus{BufferedBytes: 1000}).BufferSeconds(); ok {
+		t.Errorf("BufferSeconds without throughput should be unknown")
+	}
+	if got, ok := (BufferStatus{BufferedBytes: 1000, ThroughputBps: 250}).BufferSeconds(); !ok || got != 4 {
+		t.Errorf("BufferSeconds() = %v, %v; want 4, true", got, ok)
+	}
+	// A known bitrate wins over the measured rate
+	if got, ok := (BufferStatus{BufferedBytes: 1000, ThroughputBps: 250, BitrateBps: 4000}).BufferSeconds(); !ok || got != 2 {
+		t.Errorf("BufferSeconds() = %v, %v; want 2 from the bitrate", got, ok)
+	}
+}
+
+func TestAwaitIndex(t *testing.T) {
+	cues := &FormatInfo{Format: FormatMKV, CuesOffset: 90 * 1024 * 1024, CuesSize: 2 * 1024 * 1024}
+
+	newReader := func(info *FormatInfo, complete *atomic.Bool) *PriorityReader {
+		r := &PriorityReader{
+			prioritizer: &Prioritizer{
+				pieceLength:   1024 * 1024,
+				endPiece:      100,
+				fileLength:    100 * 1024 * 1024,
+				pieceComplete: func(int) bool { return complete.Load() },
+			},
+			formatInfo: info,
+			formatDone: make(chan struct{}),
+		}
+		close(r.formatDone)
+		return r
+	}
+
+	t.Run("ready", func(t *testing.T) {
+		var complete atomic.Bool
+		r := newReader(cues, &complete)
+		time.AfterFunc(20*time.Millisecond, func() { complete.Store(true) })
+		if got := r.awaitIndex(time.Now().Add(5 * time.Second)); got != IndexWaitReady {
+			t.Errorf("awaitIndex() = %q, want %q", got, IndexWaitReady)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var complete atomic.Bool
+		r := newReader(cues, &complete)
+		if got := r.awaitIndex(time.Now().Add(20 * time.Millisecond)); got != IndexWaitTimedOut {
+			t.Errorf("awaitIndex() = %q, want %q", got, IndexWaitTimedOut)
+		}
+	})
+
+	t.Run("index at start", func(t *testing.T) {
+		var complete atomic.Bool
+		r := newReader(&FormatInfo{Format: FormatMP4}, &complete)
+		if got := r.awaitIndex(time.Now().Add(time.Second)); got != IndexWaitNone {
+			t.Errorf("awaitIndex() = %q, want %q", got, IndexWaitNone)
+		}
+	})
+
+	t.Run("detection pending", func(t *testing.T) {
+		r := &PriorityReader{formatDone: make(chan struct{})}
+		if got := r.awaitIndex(time.Now().Add(20 * time.Millisecond)); got != IndexWaitTimedOut {
+			t.Errorf("awaitIndex() = %q, want %q", got, IndexWaitTimedOut)
+		}
+	})
+}
+
+func TestSetFormatInfoRelaxesFooterForFrontIndex(t *testing.T) {
+	const mb = 1024 * 1024
+
+	newPrioritizer := func(priorities map[int]types.PiecePriority) *Prioritizer {
+		return &Prioritizer{
+			cfg:              DefaultConfig(), // 10MB header, 5MB footer
+			pieceLength:      mb,
+			endPiece:         100,
+			fileLength:       100 * mb,
+			lastSeekOffset:   -1,
+			pieceComplete:    func(int) bool { return false },
+			setPiecePriority: func(piece int, prio types.PiecePriority) { priorities[piece] = prio },
+			log:              slog.Default(),
+		}
+	}
+
+	t.Run("moov at start", func(t *testing.T) {
+		priorities := make(map[int]types.PiecePriority)
+		p := newPrioritizer(priorities)
+		p.InitialPrioritize()
+		if priorities[99] != types.PiecePriorityHigh {
+			t.Fatalf("footer piece priority after InitialPrioritize = %v, want high", priorities[99])
+		}
+
+		p.SetFormatInfo(&FormatInfo{Format: FormatMP4, MoovSize: 2 * mb, HeaderSize: 2 * mb, NeedsFooter: false})
+
+		for piece := 95; piece < 100; piece++ {
+			if priorities[piece] != types.PiecePriorityNormal {
+				t.Errorf("footer piece %d priority = %v, want normal", piece, priorities[piece])
+			}
+		}
+		for piece := 0; piece < 10; piece++ {
+			if priorities[piece] != types.PiecePriorityHigh {
+				t.Errorf("header piece %d priority = %v, want high", piece, priorities[piece])
+			}
+		}
+	})
+
+	t.Run("moov at end", func(t *testing.T) {
+		priorities := make(map[int]types.PiecePriority)
+		p := newPrioritizer(priorities)
+		p.InitialPrioritize()
+
+		p.SetFormatInfo(&FormatInfo{Format: FormatMP4, MoovOffset: 98 * mb, MoovSize: mb, NeedsFooter: true})
+
+		for piece := 95; piece < 100; piece++ {
+			if priorities[piece] != types.PiecePriorityHigh {
+				t.Errorf("footer piece %d priority = %v, want high", piece, priorities[piece])
+			}
+		}
+	})
+}
+
+func TestReleaseWaitsForLastReaderOfFile(t *testing.T) {
+	const pieceLen = 100
+
+	priorities := map[int]types.PiecePriority{}
+	newReader := func(key any, urgentStart, readaheadEnd int64) *Prioritizer {
+		held.acquire(key)
+		return &Prioritizer{
+			cfg:              Config{HeaderPriorityBytes: 100},
+			pieceLength:      pieceLen,
+			endPiece:         10,
+			fileLength:       1000,
+			initialized:      true,
+			footerRelaxed:    true,
+			lastUrgentStart:  urgentStart,
+			lastReadaheadEnd: readaheadEnd,
+			holdKey:          key,
+			pieceComplete:    func(int) bool { return false },
+			setPiecePriority: func(piece int, prio types.PiecePriority) { priorities[piece] = prio },
+			log:              slog.Default(),
+		}
+	}
+
+	key := new(int)
+	first := newReader(key, 200, 400)
+	second := newReader(key, 600, 800)
+
+	first.Release()
+	if len(priorities) != 0 {
+		t.Fatalf("first Release changed priorities %v while another reader is open", priorities)
+	}
+
+	second.Release()
+	// Header (piece 0) and both readers' windows (pieces 2-3 and 6-7)
+	for _, piece := range []int{0, 2, 3, 6, 7} {
+		if prio, ok := priorities[piece]; !ok || prio != types.PiecePriorityNormal {
+			t.Errorf("piece %d priority = %v (set %v), want normal", piece, prio, ok)
+		}
+	}
+	if len(priorities) != 5 {
+		t.Errorf("downgraded %d pieces, want 5: %v", len(priorities), priorities)
+	}
+	if _, ok := held.files[key]; ok {
+		t.Error("file still registered after its last reader closed")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"io"
 	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/anacrolix/torrent"
 )
@@ -18,15 +20,22 @@ type PriorityReader struct {
 	prioritizer *Prioritizer
 	cfg         Config
 
-	// Position tracking
-	pos int64
+	// Position tracking. cursor mirrors pos for lock-free reads by BufferStatus,
+	// which must not block behind a read waiting for pieces.
+	pos    int64
+	cursor atomic.Int64
 
 	// Format detection state (async)
 	formatDetecting sync.Once
 	formatInfo      *FormatInfo
+	formatDone      chan struct{} // Closed once formatInfo is set
+
+	// Seek index wait before the first read (Config.WaitForIndex)
+	indexWait sync.Once
 
 	// Callbacks
-	onActivity func()
+	onActivity  func()
+	onIndexWait func(d time.Duration, result string)
 
 	log *slog.Logger
 }
@@ -36,8 +45,22 @@ type PriorityReader struct {
 type PriorityCallbacks struct {
 	OnSeek      func(forward bool) // Called on each non-debounced seek
 	OnDowngrade func(count int)    // Called with number of pieces downgraded
+
+	// Called once per reader after waiting for the seek index, with the wait
+	// time and one of the IndexWait results (Config.WaitForIndex only)
+	OnIndexWait func(d time.Duration, result string)
 }
 
+// Outcomes of waiting for the seek index before the first read
+const (
+	IndexWaitReady    = "ready"    // Index pieces complete
+	IndexWaitTimedOut = "timeout"  // Gave up after Config.IndexWaitTimeout
+	IndexWaitNone     = "no_index" // Format has no index away from the file start
+)
+
+// indexPollInterval is how often piece completion is checked while waiting for the index
+const indexPollInterval = 50 * time.Millisecond
+
 // NewPriorityReader creates a priority-aware reader for a torrent file.
 // It immediately sets up initial prioritization and configures the underlying reader.
 func NewPriorityReader(
@@ -51,6 +74,9 @@ func NewPriorityReader(
 	reader.SetReadahead(cfg.UrgentBufferBytes)
 	reader.SetResponsive()
 
+	// Demote leftovers of other files in the torrent before raising this one
+	focus.acquireFile(t, file)
+
 	prioritizer := NewPrioritizer(t, file, cfg)
 	if callbacks != nil && prioritizer != nil {
 		prioritizer.onSeek = callbacks.OnSeek
@@ -64,9 +90,13 @@ func NewPriorityReader(
 		reader:      reader,
 		prioritizer: prioritizer,
 		cfg:         cfg,
+		formatDone:  make(chan struct{}),
 		onActivity:  onActivity,
 		log:         slog.With("component", "priority-reader", "file", file.Path()),
 	}
+	if callbacks != nil {
+		pr.onIndexWait = callbacks.OnIndexWait
+	}
 
 	pr.log.Debug("priority reader created",
 		"file_size", file.Length(),
@@ -79,6 +109,8 @@ func NewPriorityReader(
 
 // Read implements io.Reader.
 func (r *PriorityReader) Read(p []byte) (n int, err error) {
+	r.waitForIndex()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -87,6 +119,7 @@ func (r *PriorityReader) Read(p []byte) (n int, err error) {
 
 	n, err = r.reader.Read(p)
 	r.pos += int64(n)
+	r.cursor.Store(r.pos)
 
 	return n, err
 }
@@ -94,6 +127,8 @@ func (r *PriorityReader) Read(p []byte) (n int, err error) {
 // ReadAt implements io.ReaderAt for seeking reads.
 // It updates piece priorities around the seek position.
 func (r *PriorityReader) ReadAt(p []byte, off int64) (n int, err error) {
+	r.waitForIndex()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -111,15 +146,24 @@ func (r *PriorityReader) ReadAt(p []byte, off int64) (n int, err error) {
 	// Read fully (ReadAt semantics require reading exactly len(p) bytes)
 	n, err = io.ReadFull(r.reader, p)
 	r.pos = off + int64(n)
+	r.cursor.Store(r.pos)
 
 	return n, err
 }
 
 // Seek implements io.Seeker.
+// Piece priorities are always updated for the target position; the underlying
+// reader is only repositioned when the position actually changes, so sequential
+// range reads keep its readahead state.
 func (r *PriorityReader) Seek(offset int64, whence int) (int64, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if whence == io.SeekStart && offset == r.pos {
+		r.prioritizer.UpdateForSeek(offset)
+		return offset, nil
+	}
+
 	newPos, err := r.reader.Seek(offset, whence)
 	if err != nil {
 		return 0, err
@@ -127,6 +171,7 @@ func (r *PriorityReader) Seek(offset int64, whence int) (int64, error) {
 
 	r.prioritizer.UpdateForSeek(newPos)
 	r.pos = newPos
+	r.cursor.Store(newPos)
 
 	// Note: Seek logging removed to reduce verbosity.
 	// Priority updates are logged in Prioritizer.UpdateForSeek when significant.
@@ -141,9 +186,19 @@ func (r *PriorityReader) Close() error {
 
 	r.log.Debug("reader closed", "final_position", r.pos)
 
+	// Stop prioritizing pieces for a stream that is no longer being read
+	r.prioritizer.Release()
+	focus.releaseFile(r.t, r.file)
+
 	return r.reader.Close()
 }
 
+// BufferStatus reports buffered data ahead of the read position.
+// Safe to call while a read is blocked waiting for pieces.
+func (r *PriorityReader) BufferStatus() BufferStatus {
+	return r.prioritizer.BufferStatus(r.cursor.Load())
+}
+
 // SetResponsive delegates to underlying reader.
 // This is called by the VFS layer to ensure responsive mode is set.
 func (r *PriorityReader) SetResponsive() {
@@ -176,16 +231,74 @@ func (r *PriorityReader) detectFormat() {
 	r.formatInfo = info
 	r.prioritizer.SetFormatInfo(info)
 	r.mu.Unlock()
+	close(r.formatDone)
 
 	r.log.Debug("format detected",
 		"format", info.Format.String(),
 		"moov_offset", info.MoovOffset,
 		"moov_size", info.MoovSize,
+		"cues_offset", info.CuesOffset,
+		"cues_size", info.CuesSize,
 		"header_size", info.HeaderSize,
 		"needs_footer", info.NeedsFooter,
 	)
 }
 
+// waitForIndex blocks the first read, and reads racing it, until the seek
+// index pieces are complete or the index wait timeout passes. It runs before
+// taking r.mu because format detection needs the lock to publish its result.
+func (r *PriorityReader) waitForIndex() {
+	if !r.cfg.WaitForIndex {
+		return
+	}
+	r.indexWait.Do(func() {
+		r.startFormatDetection()
+
+		start := time.Now()
+		result := r.awaitIndex(start.Add(r.cfg.indexWaitTimeout()))
+		elapsed := time.Since(start)
+
+		if r.onIndexWait != nil {
+			r.onIndexWait(elapsed, result)
+		}
+		r.log.Debug("waited for seek index", "result", result, "duration_ms", elapsed.Milliseconds())
+	})
+}
+
+// awaitIndex waits for format detection and then for the index pieces,
+// returning one of the IndexWait results.
+func (r *PriorityReader) awaitIndex(deadline time.Time) string {
+	timeout := time.NewTimer(time.Until(deadline))
+	defer timeout.Stop()
+
+	select {
+	case <-r.formatDone:
+	case <-timeout.C:
+		return IndexWaitTimedOut
+	}
+
+	r.mu.Lock()
+	info := r.formatInfo
+	r.mu.Unlock()
+
+	// An index at the file start is read in order with the header
+	start, end, ok := info.TailIndexRange()
+	if !ok {
+		return IndexWaitNone
+	}
+
+	poll := time.NewTicker(indexPollInterval)
+	defer poll.Stop()
+	for !r.prioritizer.RangeComplete(start, end) {
+		select {
+		case <-poll.C:
+		case <-timeout.C:
+			return IndexWaitTimedOut
+		}
+	}
+	return IndexWaitReady
+}
+
 // markActivity signals file access for idle tracking.
 func (r *PriorityReader) markActivity() {
 	if r.onActivity != nil {
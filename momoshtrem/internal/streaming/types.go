@@ -1,5 +1,7 @@
 package streaming
 
+import "time"
+
 // Format represents detected video container format
 type Format int
 
@@ -29,16 +31,85 @@ type FormatInfo struct {
 	Format      Format
 	MoovOffset  int64 // MP4: offset of moov atom (0 if at start, >0 if at end)
 	MoovSize    int64 // MP4: size of moov atom
+	CuesOffset  int64 // MKV: offset of Cues element (0 if unknown)
+	CuesSize    int64 // MKV: size of Cues element, header included
 	HeaderSize  int64 // Recommended header bytes to prioritize
 	NeedsFooter bool  // Whether footer contains important metadata
 }
 
+// TailIndexRange returns the byte range of a seek index stored away from the
+// file start (MP4 moov-at-end, MKV Cues). Players need it before they can
+// start or seek, so it is prioritized like the header.
+func (i *FormatInfo) TailIndexRange() (start, end int64, ok bool) {
+	if i == nil {
+		return 0, 0, false
+	}
+	switch {
+	case i.Format == FormatMP4 && i.MoovOffset > 0 && i.MoovSize > 0:
+		return i.MoovOffset, i.MoovOffset + i.MoovSize, true
+	case i.Format == FormatMKV && i.CuesOffset > 0 && i.CuesSize > 0:
+		return i.CuesOffset, i.CuesOffset + i.CuesSize, true
+	}
+	return 0, 0, false
+}
+
+// BufferStatus describes how much contiguous data is available ahead of a stream's read position
+type BufferStatus struct {
+	Offset              int64   `json:"offset"`                // Read position within the file
+	FileLength          int64   `json:"file_length"`
+	PieceLength         int64   `json:"piece_length"`
+	CompletePiecesAhead int     `json:"complete_pieces_ahead"` // Complete pieces from the cursor up to the first missing one
+	BufferedBytes       int64   `json:"buffered_bytes"`        // Contiguous complete bytes from the cursor
+	ReadaheadBytes      int64   `json:"readahead_bytes"`       // Current readahead window
+	ThroughputBps       float64 `json:"throughput_bps"`        // Measured playback rate in bytes/sec (0 = unknown)
+	BitrateBps          int64   `json:"bitrate_bps,omitempty"` // Probed average bitrate in bits/sec (0 = unknown)
+}
+
+// BufferSeconds estimates playback time covered by the buffer from the
+// file's bitrate, falling back to the measured playback rate when the
+// bitrate is unknown. Returns false while neither is known.
+func (s BufferStatus) BufferSeconds() (float64, bool) {
+	if s.BitrateBps > 0 {
+		return float64(s.BufferedBytes) * 8 / float64(s.BitrateBps), true
+	}
+	if s.ThroughputBps <= 0 {
+		return 0, false
+	}
+	return float64(s.BufferedBytes) / s.ThroughputBps, true
+}
+
 // Config holds streaming optimization settings
 type Config struct {
 	HeaderPriorityBytes int64
 	FooterPriorityBytes int64
 	ReadaheadBytes      int64
 	UrgentBufferBytes   int64
+
+	// Adaptive readahead: the window scales with measured playback throughput
+	// to keep about TargetBufferSeconds buffered, bounded to
+	// [ReadaheadMinMultiplier, ReadaheadMaxMultiplier] * ReadaheadBytes.
+	// TargetBufferSeconds <= 0 keeps the window fixed at ReadaheadBytes.
+	ReadaheadMinMultiplier float64
+	ReadaheadMaxMultiplier float64
+	TargetBufferSeconds    float64
+
+	// WaitForIndex holds the first read until the seek index stored away from
+	// the file start (MP4 moov-at-end, MKV Cues) is downloaded, for at most
+	// IndexWaitTimeout (<= 0 uses defaultIndexWaitTimeout). Trades startup
+	// latency for a stream the player can seek in right away.
+	WaitForIndex     bool
+	IndexWaitTimeout time.Duration
+}
+
+// defaultIndexWaitTimeout bounds the index wait when no timeout is configured
+const defaultIndexWaitTimeout = 15 * time.Second
+
+// indexWaitTimeout returns the configured index wait bound or the default
+func (c Config) indexWaitTimeout() time.Duration {
+	if c.IndexWaitTimeout > 0 {
+		return c.IndexWaitTimeout
+	}
+	return defaultIndexWaitTimeout
 }
 
 // DefaultConfig returns sensible defaults for streaming optimization
@@ -48,7 +119,25 @@ func DefaultConfig() Config {
 		FooterPriorityBytes: 5 * 1024 * 1024,  // 5MB
 		ReadaheadBytes:      32 * 1024 * 1024,  // 32MB
 		UrgentBufferBytes:   8 * 1024 * 1024,   // 8MB
+
+		ReadaheadMinMultiplier: 1,
+		ReadaheadMaxMultiplier: 4,
+		TargetBufferSeconds:    30,
+	}
+}
+
+// readaheadBounds returns the adaptive readahead window limits in bytes.
+// Missing or inverted multipliers fall back to a fixed ReadaheadBytes window.
+func (c Config) readaheadBounds() (lo, hi int64) {
+	minMul := c.ReadaheadMinMultiplier
+	if minMul <= 0 {
+		minMul = 1
+	}
+	maxMul := c.ReadaheadMaxMultiplier
+	if maxMul < minMul {
+		maxMul = minMul
 	}
+	return int64(float64(c.ReadaheadBytes) * minMul), int64(float64(c.ReadaheadBytes) * maxMul)
 }
 
 // IsZero returns true if config has no values set
@@ -52,6 +52,23 @@ var languagePatterns = []languagePattern{
 	{regexp.MustCompile(`(?i)\.(?:sr|srp)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sr", "Serbian"},
 	{regexp.MustCompile(`(?i)\.(?:sk|slk|slo)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sk", "Slovak"},
 	{regexp.MustCompile(`(?i)\.(?:sl|slv)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sl", "Slovenian"},
+	{regexp.MustCompile(`(?i)\.(?:fa|per|fas)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "fa", "Persian"},
+	{regexp.MustCompile(`(?i)\.(?:ta|tam)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ta", "Tamil"},
+	{regexp.MustCompile(`(?i)\.(?:te|tel)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "te", "Telugu"},
+	{regexp.MustCompile(`(?i)\.(?:bn|ben)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "bn", "Bengali"},
+	{regexp.MustCompile(`(?i)\.(?:ml|mal)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ml", "Malayalam"},
+	{regexp.MustCompile(`(?i)\.(?:ca|cat)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ca", "Catalan"},
+	{regexp.MustCompile(`(?i)\.(?:et|est)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "et", "Estonian"},
+	{regexp.MustCompile(`(?i)\.(?:lv|lav)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "lv", "Latvian"},
+	{regexp.MustCompile(`(?i)\.(?:lt|lit)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "lt", "Lithuanian"},
+	{regexp.MustCompile(`(?i)\.(?:is|ice|isl)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "is", "Icelandic"},
+	{regexp.MustCompile(`(?i)\.(?:mk|mac|mkd)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "mk", "Macedonian"},
+	{regexp.MustCompile(`(?i)\.(?:sq|alb|sqi)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sq", "Albanian"},
+	{regexp.MustCompile(`(?i)\.(?:eu|baq|eus)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "eu", "Basque"},
+	{regexp.MustCompile(`(?i)\.(?:gl|glg)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "gl", "Galician"},
+	{regexp.MustCompile(`(?i)\.(?:bs|bos)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "bs", "Bosnian"},
+	{regexp.MustCompile(`(?i)\.(?:ur|urd)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ur", "Urdu"},
+	{regexp.MustCompile(`(?i)\.(?:tl|tgl|fil)\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "tl", "Tagalog"},
 
 	// Full language names
 	{regexp.MustCompile(`(?i)\.english\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "en", "English"},
@@ -85,6 +102,25 @@ var languagePatterns = []languagePattern{
 	{regexp.MustCompile(`(?i)\.bulgarian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "bg", "Bulgarian"},
 	{regexp.MustCompile(`(?i)\.croatian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "hr", "Croatian"},
 	{regexp.MustCompile(`(?i)\.serbian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sr", "Serbian"},
+	{regexp.MustCompile(`(?i)\.persian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "fa", "Persian"},
+	{regexp.MustCompile(`(?i)\.farsi\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "fa", "Persian"},
+	{regexp.MustCompile(`(?i)\.tamil\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ta", "Tamil"},
+	{regexp.MustCompile(`(?i)\.telugu\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "te", "Telugu"},
+	{regexp.MustCompile(`(?i)\.bengali\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "bn", "Bengali"},
+	{regexp.MustCompile(`(?i)\.malayalam\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ml", "Malayalam"},
+	{regexp.MustCompile(`(?i)\.catalan\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ca", "Catalan"},
+	{regexp.MustCompile(`(?i)\.estonian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "et", "Estonian"},
+	{regexp.MustCompile(`(?i)\.latvian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "lv", "Latvian"},
+	{regexp.MustCompile(`(?i)\.lithuanian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "lt", "Lithuanian"},
+	{regexp.MustCompile(`(?i)\.icelandic\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "is", "Icelandic"},
+	{regexp.MustCompile(`(?i)\.macedonian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "mk", "Macedonian"},
+	{regexp.MustCompile(`(?i)\.albanian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "sq", "Albanian"},
+	{regexp.MustCompile(`(?i)\.basque\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "eu", "Basque"},
+	{regexp.MustCompile(`(?i)\.galician\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "gl", "Galician"},
+	{regexp.MustCompile(`(?i)\.bosnian\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "bs", "Bosnian"},
+	{regexp.MustCompile(`(?i)\.urdu\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "ur", "Urdu"},
+	{regexp.MustCompile(`(?i)\.tagalog\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "tl", "Tagalog"},
+	{regexp.MustCompile(`(?i)\.filipino\.(?:srt|sub|ass|ssa|vtt|idx|smi)$`), "tl", "Tagalog"},
 }
 
 // directoryLanguages maps directory names to language info
@@ -124,16 +160,88 @@ var directoryLanguages = map[string]struct{ code, name string }{
 	"bulgarian":  {"bg", "Bulgarian"},
 	"croatian":   {"hr", "Croatian"},
 	"serbian":    {"sr", "Serbian"},
+	"persian":    {"fa", "Persian"},
+	"farsi":      {"fa", "Persian"},
+	"tamil":      {"ta", "Tamil"},
+	"telugu":     {"te", "Telugu"},
+	"bengali":    {"bn", "Bengali"},
+	"malayalam":  {"ml", "Malayalam"},
+	"catalan":    {"ca", "Catalan"},
+	"estonian":   {"et", "Estonian"},
+	"latvian":    {"lv", "Latvian"},
+	"lithuanian": {"lt", "Lithuanian"},
+	"icelandic":  {"is", "Icelandic"},
+	"macedonian": {"mk", "Macedonian"},
+	"albanian":   {"sq", "Albanian"},
+	"basque":     {"eu", "Basque"},
+	"galician":   {"gl", "Galician"},
+	"bosnian":    {"bs", "Bosnian"},
+	"urdu":       {"ur", "Urdu"},
+	"tagalog":    {"tl", "Tagalog"},
+	"filipino":   {"tl", "Tagalog"},
 	"subs":       {"", ""},  // Common folder name, but no specific language
 }
 
+// subtitleFlagPattern matches a variant marker between dots, e.g. ".forced." or ".SDH."
+var subtitleFlagPattern = regexp.MustCompile(`(?i)\.(?:forced|sdh|cc)\.`)
+
+// DetectSubtitleFlags detects forced and SDH/CC markers in a subtitle filename.
+// Markers may come before or after the language (.forced.en.srt, .en.forced.srt).
+func DetectSubtitleFlags(filename string) (forced bool, hearingImpaired bool) {
+	parts := strings.Split(strings.ToLower(filepath.Base(filename)), ".")
+	if len(parts) < 2 {
+		return false, false
+	}
+	// The first part is the title and the last the extension
+	for _, part := range parts[1 : len(parts)-1] {
+		switch part {
+		case "forced":
+			forced = true
+		case "sdh", "cc":
+			hearingImpaired = true
+		}
+	}
+	return forced, hearingImpaired
+}
+
+// VariantTag returns the filename marker for a subtitle variant:
+// "forced", "sdh", "forced.sdh", or "" for a regular track.
+func VariantTag(forced, hearingImpaired bool) string {
+	switch {
+	case forced && hearingImpaired:
+		return "forced.sdh"
+	case forced:
+		return "forced"
+	case hearingImpaired:
+		return "sdh"
+	default:
+		return ""
+	}
+}
+
+// stripSubtitleFlags removes variant markers so the language is the last part before the extension
+func stripSubtitleFlags(filename string) string {
+	for {
+		stripped := subtitleFlagPattern.ReplaceAllString(filename, ".")
+		if stripped == filename {
+			return filename
+		}
+		filename = stripped
+	}
+}
+
+// UnknownLanguageCode is assigned to subtitles whose language could not be detected.
+const UnknownLanguageCode = "unknown"
+
 // DetectLanguage attempts to detect the language from a subtitle filename.
 // Returns (languageCode, languageName, detected).
 // If no language is detected, returns ("unknown", "Unknown", false).
 func DetectLanguage(filename string) (code string, name string, detected bool) {
-	// First, try to match filename patterns (e.g., .en.srt, .english.srt)
+	// First, try to match filename patterns (e.g., .en.srt, .english.srt),
+	// ignoring variant markers such as .en.forced.srt
+	base := stripSubtitleFlags(filename)
 	for _, lp := range languagePatterns {
-		if lp.pattern.MatchString(filename) {
+		if lp.pattern.MatchString(base) {
 			return lp.code, lp.name, true
 		}
 	}
@@ -150,5 +258,5 @@ func DetectLanguage(filename string) (code string, name string, detected bool) {
 		}
 	}
 
-	return "unknown", "Unknown", false
+	return UnknownLanguageCode, "Unknown", false
 }
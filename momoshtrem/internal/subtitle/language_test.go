@@ -0,0 +1,79 @@
+package subtitle
+
+import "testing"
+
+func TestDetectSubtitleFlags(t *testing.T) {
+	tests := []struct {
+		filename   string
+		wantLang   string
+		wantForced bool
+		wantSDH    bool
+	}{
+		{"Show.S01E01.en.srt", "en", false, false},
+		{"Show.S01E01.en.forced.srt", "en", true, false},
+		{"Show.S01E01.forced.en.srt", "en", true, false},
+		{"Show.S01E01.ENG.FORCED.srt", "en", true, false},
+		{"Subs/Show.S01E01.en.sdh.srt", "en", false, true},
+		{"Show.S01E01.CC.eng.srt", "en", false, true},
+		{"Show.S01E01.en.forced.sdh.srt", "en", true, true},
+		{"Forced.Entry.2020.en.srt", "en", false, false}, // Title word, not a marker
+		{"Show.S01E01.hi.srt", "hi", false, false},       // Hindi, not hearing impaired
+	}
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			forced, hi := DetectSubtitleFlags(tt.filename)
+			if forced != tt.wantForced || hi != tt.wantSDH {
+				t.Errorf("DetectSubtitleFlags() = %v, %v; want %v, %v", forced, hi, tt.wantForced, tt.wantSDH)
+			}
+			if code, _, _ := DetectLanguage(tt.filename); code != tt.wantLang {
+				t.Errorf("DetectLanguage() = %q, want %q", code, tt.wantLang)
+			}
+		})
+	}
+}
+
+func TestDetectLanguageAdded(t *testing.T) {
+	tests := []struct {
+		code     string
+		fullName string
+	}{
+		{"fa", "farsi"},
+		{"fa", "persian"},
+		{"ta", "tamil"},
+		{"te", "telugu"},
+		{"bn", "bengali"},
+		{"ml", "malayalam"},
+		{"ca", "catalan"},
+		{"et", "estonian"},
+		{"lv", "latvian"},
+		{"lt", "lithuanian"},
+		{"is", "icelandic"},
+		{"mk", "macedonian"},
+		{"sq", "albanian"},
+		{"eu", "basque"},
+		{"gl", "galician"},
+		{"bs", "bosnian"},
+		{"ur", "urdu"},
+		{"tl", "tagalog"},
+	}
+	for _, tt := range tests {
+		for _, filename := range []string{
+			"Movie.2020." + tt.code + ".srt",
+			"Movie.2020." + tt.fullName + ".srt",
+		} {
+			t.Run(filename, func(t *testing.T) {
+				code, _, detected := DetectLanguage(filename)
+				if !detected || code != tt.code {
+					t.Errorf("DetectLanguage(%q) = %q, %v; want %q", filename, code, detected, tt.code)
+				}
+			})
+		}
+	}
+
+	t.Run("directory fallback", func(t *testing.T) {
+		code, name, detected := DetectLanguage("Subs/Farsi/x.srt")
+		if !detected || code != "fa" || name != "Persian" {
+			t.Errorf("DetectLanguage(Subs/Farsi/x.srt) = %q, %q, %v; want fa, Persian", code, name, detected)
+		}
+	})
+}
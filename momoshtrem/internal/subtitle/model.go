@@ -2,6 +2,7 @@ package subtitle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -37,17 +38,27 @@ func ParseItemType(s string) (ItemType, error) {
 
 // Subtitle represents a subtitle file for a library item
 type Subtitle struct {
-	ID           int64
-	ItemType     ItemType
-	ItemID       int64
-	LanguageCode string // ISO 639-1 (en, ru, tr, az)
-	LanguageName string // Display name (English, Russian, etc.)
-	Format       string // srt, vtt, ass, ssa, sub
-	FilePath     string // Local storage path OR torrent file path (for Source=torrent)
-	FileSize     int64
-	Source       Source // "opensubtitles" or "torrent"
-	InfoHash     string // For torrent subtitles: the torrent info hash
-	CreatedAt    time.Time
+	ID              int64
+	ItemType        ItemType
+	ItemID          int64
+	LanguageCode    string // ISO 639-1 (en, ru, tr, az)
+	LanguageName    string // Display name (English, Russian, etc.)
+	Format          string // srt, vtt, ass, ssa, sub
+	FilePath        string // Local storage path OR torrent file path (for Source=torrent)
+	FileSize        int64
+	Source          Source // "opensubtitles" or "torrent"
+	InfoHash        string // For torrent subtitles: the torrent info hash
+	ContentHash     string // SHA-256 of the subtitle content, empty if unknown
+	OffsetMs        int64  // Shift applied to cue timestamps when served (negative = earlier)
+	Forced          bool   // Only translates foreign-language parts
+	HearingImpaired bool   // SDH/CC: includes sound descriptions
+	CreatedAt       time.Time
+	UpdatedAt       time.Time // When the served content last changed (file or offset)
+}
+
+// Variant returns the filename marker for the subtitle's variant ("" for a regular track)
+func (s *Subtitle) Variant() string {
+	return VariantTag(s.Forced, s.HearingImpaired)
 }
 
 // Supported subtitle formats
@@ -59,6 +70,22 @@ var SupportedFormats = map[string]bool{
 	"sub": true,
 }
 
+// ErrNotFound is returned when no subtitle has the requested ID
+var ErrNotFound = errors.New("subtitle not found")
+
+// ErrOffsetUnsupported is returned when shifting timestamps of a format that isn't cue-based
+var ErrOffsetUnsupported = errors.New("timing offset is only supported for srt and vtt subtitles")
+
+// SupportsOffset reports whether cue timestamps in a format can be shifted
+func SupportsOffset(format string) bool {
+	switch strings.ToLower(format) {
+	case "srt", "vtt":
+		return true
+	default:
+		return false
+	}
+}
+
 // ParseFormat extracts subtitle format from filename extension.
 // Returns "srt" as default if format is not recognized.
 func ParseFormat(filename string) string {
@@ -77,6 +104,9 @@ type SubtitleRepository interface {
 	GetByID(ctx context.Context, id int64) (*Subtitle, error)
 	GetByItem(ctx context.Context, itemType ItemType, itemID int64) ([]*Subtitle, error)
 	GetByItemAndLanguage(ctx context.Context, itemType ItemType, itemID int64, languageCode string) (*Subtitle, error)
+	GetByItemAndHash(ctx context.Context, itemType ItemType, itemID int64, contentHash string) (*Subtitle, error)
+	SetOffset(ctx context.Context, id int64, offsetMs int64) error
+	SetContentHash(ctx context.Context, id int64, contentHash string) error
 	Delete(ctx context.Context, id int64) error
 	DeleteByItem(ctx context.Context, itemType ItemType, itemID int64) error
 }
@@ -24,18 +24,19 @@ type scanner interface {
 // scanSubtitle scans a row into a Subtitle
 func scanSubtitle(s scanner) (*Subtitle, error) {
 	sub := &Subtitle{}
-	var infoHash sql.NullString
+	var infoHash, contentHash sql.NullString
 	err := s.Scan(
 		&sub.ID, &sub.ItemType, &sub.ItemID,
 		&sub.LanguageCode, &sub.LanguageName,
 		&sub.Format, &sub.FilePath, &sub.FileSize,
-		&sub.Source, &infoHash,
-		&sub.CreatedAt,
+		&sub.Source, &infoHash, &contentHash,
+		&sub.OffsetMs, &sub.Forced, &sub.HearingImpaired, &sub.CreatedAt, &sub.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	sub.InfoHash = infoHash.String
+	sub.ContentHash = contentHash.String
 	return sub, nil
 }
 
@@ -55,19 +56,23 @@ func (r *Repository) Create(ctx context.Context, sub *Subtitle) error {
 	}
 
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO subtitles (item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		 ON CONFLICT(item_type, item_id, language_code) DO UPDATE SET
+		`INSERT INTO subtitles (item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, content_hash, offset_ms, forced, hearing_impaired)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		 ON CONFLICT(item_type, item_id, language_code, forced, hearing_impaired) DO UPDATE SET
 		 language_name = EXCLUDED.language_name,
 		 format = EXCLUDED.format,
 		 file_path = EXCLUDED.file_path,
 		 file_size = EXCLUDED.file_size,
 		 source = EXCLUDED.source,
-		 info_hash = EXCLUDED.info_hash
-		 RETURNING id, source, created_at`,
+		 info_hash = EXCLUDED.info_hash,
+		 content_hash = EXCLUDED.content_hash,
+		 offset_ms = EXCLUDED.offset_ms,
+		 updated_at = NOW()
+		 RETURNING id, source, created_at, updated_at`,
 		sub.ItemType, sub.ItemID, sub.LanguageCode, sub.LanguageName,
-		sub.Format, sub.FilePath, sub.FileSize, source, nullString(sub.InfoHash),
-	).Scan(&sub.ID, &sub.Source, &sub.CreatedAt)
+		sub.Format, sub.FilePath, sub.FileSize, source, nullString(sub.InfoHash), nullString(sub.ContentHash), sub.OffsetMs,
+		sub.Forced, sub.HearingImpaired,
+	).Scan(&sub.ID, &sub.Source, &sub.CreatedAt, &sub.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create subtitle: %w", err)
 	}
@@ -77,7 +82,7 @@ func (r *Repository) Create(ctx context.Context, sub *Subtitle) error {
 // GetByID retrieves a subtitle by its ID
 func (r *Repository) GetByID(ctx context.Context, id int64) (*Subtitle, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, created_at
+		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, content_hash, offset_ms, forced, hearing_impaired, created_at, updated_at
 		 FROM subtitles WHERE id = $1`,
 		id,
 	)
@@ -96,9 +101,9 @@ func (r *Repository) GetByID(ctx context.Context, id int64) (*Subtitle, error) {
 // GetByItem retrieves all subtitles for a library item
 func (r *Repository) GetByItem(ctx context.Context, itemType ItemType, itemID int64) ([]*Subtitle, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, created_at
+		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, content_hash, offset_ms, forced, hearing_impaired, created_at, updated_at
 		 FROM subtitles WHERE item_type = $1 AND item_id = $2
-		 ORDER BY language_code`,
+		 ORDER BY language_code, forced, hearing_impaired`,
 		itemType, itemID,
 	)
 	if err != nil {
@@ -118,11 +123,13 @@ func (r *Repository) GetByItem(ctx context.Context, itemType ItemType, itemID in
 	return subtitles, rows.Err()
 }
 
-// GetByItemAndLanguage retrieves a specific subtitle by item and language
+// GetByItemAndLanguage retrieves a subtitle by item and language,
+// preferring the regular track over forced and SDH variants
 func (r *Repository) GetByItemAndLanguage(ctx context.Context, itemType ItemType, itemID int64, languageCode string) (*Subtitle, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, created_at
-		 FROM subtitles WHERE item_type = $1 AND item_id = $2 AND language_code = $3`,
+		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, content_hash, offset_ms, forced, hearing_impaired, created_at, updated_at
+		 FROM subtitles WHERE item_type = $1 AND item_id = $2 AND language_code = $3
+		 ORDER BY forced, hearing_impaired LIMIT 1`,
 		itemType, itemID, languageCode,
 	)
 
@@ -137,6 +144,63 @@ func (r *Repository) GetByItemAndLanguage(ctx context.Context, itemType ItemType
 	return sub, nil
 }
 
+// GetByItemAndHash retrieves a subtitle for an item with the given content hash.
+// Returns nil if no subtitle with identical content is stored for the item.
+func (r *Repository) GetByItemAndHash(ctx context.Context, itemType ItemType, itemID int64, contentHash string) (*Subtitle, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, item_type, item_id, language_code, language_name, format, file_path, file_size, source, info_hash, content_hash, offset_ms, forced, hearing_impaired, created_at, updated_at
+		 FROM subtitles WHERE item_type = $1 AND item_id = $2 AND content_hash = $3
+		 ORDER BY id LIMIT 1`,
+		itemType, itemID, contentHash,
+	)
+
+	sub, err := scanSubtitle(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtitle by content hash: %w", err)
+	}
+
+	return sub, nil
+}
+
+// SetOffset updates the timing offset of a subtitle
+func (r *Repository) SetOffset(ctx context.Context, id int64, offsetMs int64) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE subtitles SET offset_ms = $1, updated_at = NOW() WHERE id = $2`, offsetMs, id)
+	if err != nil {
+		return fmt.Errorf("failed to set subtitle offset: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetContentHash records the content hash of a subtitle
+func (r *Repository) SetContentHash(ctx context.Context, id int64, contentHash string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE subtitles SET content_hash = $1 WHERE id = $2`, nullString(contentHash), id)
+	if err != nil {
+		return fmt.Errorf("failed to set subtitle content hash: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // Delete removes a subtitle by ID
 func (r *Repository) Delete(ctx context.Context, id int64) error {
 	result, err := r.db.ExecContext(ctx, `DELETE FROM subtitles WHERE id = $1`, id)
@@ -149,7 +213,7 @@ func (r *Repository) Delete(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to check delete result: %w", err)
 	}
 	if affected == 0 {
-		return fmt.Errorf("subtitle not found")
+		return ErrNotFound
 	}
 
 	return nil
@@ -2,6 +2,9 @@ package subtitle
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -16,6 +19,10 @@ type Service struct {
 	fetcher       opensubtitles.SubtitleFetcher
 	repo          SubtitleRepository
 	subtitlesPath string
+
+	// dedupeIdentical keeps a single record when the same subtitle content
+	// arrives from multiple sources under different languages.
+	dedupeIdentical bool
 }
 
 // NewService creates a new subtitle service.
@@ -27,11 +34,42 @@ func NewService(fetcher opensubtitles.SubtitleFetcher, repo SubtitleRepository,
 	}
 }
 
+// SetDedupeIdentical enables merging of subtitles with identical content.
+func (s *Service) SetDedupeIdentical(enabled bool) {
+	s.dedupeIdentical = enabled
+}
+
+// ContentHash returns the hex-encoded SHA-256 of subtitle content.
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
 // IsConfigured returns true if the service has a configured fetcher.
 func (s *Service) IsConfigured() bool {
 	return s.fetcher != nil && s.fetcher.IsConfigured()
 }
 
+// AuthStats returns the fetcher's authentication state.
+// Returns false if the fetcher doesn't report one.
+func (s *Service) AuthStats() (opensubtitles.AuthStats, bool) {
+	reporter, ok := s.fetcher.(opensubtitles.AuthReporter)
+	if !ok {
+		return opensubtitles.AuthStats{}, false
+	}
+	return reporter.AuthStats(), true
+}
+
+// DownloadQuota returns the fetcher's download quota, nil until it has
+// downloaded something. Returns false if the fetcher doesn't track one.
+func (s *Service) DownloadQuota() (*opensubtitles.DownloadQuota, bool) {
+	reporter, ok := s.fetcher.(opensubtitles.QuotaReporter)
+	if !ok {
+		return nil, false
+	}
+	return reporter.DownloadQuota(), true
+}
+
 // Search searches for subtitles using the configured fetcher.
 func (s *Service) Search(ctx context.Context, params opensubtitles.SearchParams) (*opensubtitles.SearchResponse, error) {
 	if !s.IsConfigured() {
@@ -53,8 +91,18 @@ func (s *Service) DownloadAndStore(ctx context.Context, itemType ItemType, itemI
 		return nil, fmt.Errorf("failed to download subtitle: %w", err)
 	}
 
-	// Determine format from filename
+	// Determine format and variant from filename
 	format := ParseFormat(fileName)
+	forced, hearingImpaired := DetectSubtitleFlags(fileName)
+	contentHash := ContentHash(content)
+
+	// Skip storing if identical content is already present under another language
+	candidate := &Subtitle{ItemType: itemType, ItemID: itemID, LanguageCode: languageCode, ContentHash: contentHash}
+	if existing, _, err := s.resolveDuplicate(ctx, candidate); err != nil {
+		return nil, err
+	} else if existing != nil {
+		return existing, nil
+	}
 
 	// Create storage directory: {download_path}/{item_type}/{item_id}/
 	storageDir := filepath.Join(s.subtitlesPath, string(itemType), strconv.FormatInt(itemID, 10))
@@ -62,8 +110,11 @@ func (s *Service) DownloadAndStore(ctx context.Context, itemType ItemType, itemI
 		return nil, fmt.Errorf("failed to create subtitle directory: %w", err)
 	}
 
-	// Final file path: {language_code}.{format}
-	storedFileName := fmt.Sprintf("%s.%s", languageCode, format)
+	// Final file path: {language_code}[.{variant}].{format}
+	storedFileName := languageCode + "." + format
+	if variant := VariantTag(forced, hearingImpaired); variant != "" {
+		storedFileName = languageCode + "." + variant + "." + format
+	}
 	storedFilePath := filepath.Join(storageDir, storedFileName)
 
 	// Write atomically: write to temp file, then rename
@@ -80,6 +131,10 @@ func (s *Service) DownloadAndStore(ctx context.Context, itemType ItemType, itemI
 		Format:       format,
 		FilePath:     storedFilePath,
 		FileSize:     int64(len(content)),
+		ContentHash:  contentHash,
+
+		Forced:          forced,
+		HearingImpaired: hearingImpaired,
 	}
 
 	if err := s.repo.Create(ctx, sub); err != nil {
@@ -107,7 +162,7 @@ func (s *Service) Delete(ctx context.Context, id int64) error {
 		return fmt.Errorf("failed to get subtitle: %w", err)
 	}
 	if sub == nil {
-		return fmt.Errorf("subtitle not found")
+		return ErrNotFound
 	}
 
 	// Delete from database first
@@ -130,6 +185,36 @@ func (s *Service) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// SetOffset sets the timing offset applied when a subtitle is served.
+// A replacement download for the same language starts again at zero.
+func (s *Service) SetOffset(ctx context.Context, id int64, offsetMs int64) (*Subtitle, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtitle: %w", err)
+	}
+	if sub == nil {
+		return nil, ErrNotFound
+	}
+	if !SupportsOffset(sub.Format) {
+		return nil, fmt.Errorf("%w: %s", ErrOffsetUnsupported, sub.Format)
+	}
+
+	if err := s.repo.SetOffset(ctx, id, offsetMs); err != nil {
+		return nil, err
+	}
+	sub.OffsetMs = offsetMs
+
+	slog.Info("Subtitle offset updated",
+		"id", id,
+		"item_type", sub.ItemType,
+		"item_id", sub.ItemID,
+		"language", sub.LanguageCode,
+		"offset_ms", offsetMs,
+	)
+
+	return sub, nil
+}
+
 // GetByItem retrieves all subtitles for a library item.
 func (s *Service) GetByItem(ctx context.Context, itemType ItemType, itemID int64) ([]*Subtitle, error) {
 	return s.repo.GetByItem(ctx, itemType, itemID)
@@ -141,6 +226,15 @@ func (s *Service) CreateTorrentSubtitle(ctx context.Context, sub *Subtitle) erro
 	// Ensure source is set correctly
 	sub.Source = SourceTorrent
 
+	existing, _, err := s.resolveDuplicate(ctx, sub)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		*sub = *existing
+		return nil
+	}
+
 	// Create/update the database record
 	if err := s.repo.Create(ctx, sub); err != nil {
 		return fmt.Errorf("failed to save torrent subtitle record: %w", err)
@@ -158,6 +252,81 @@ func (s *Service) CreateTorrentSubtitle(ctx context.Context, sub *Subtitle) erro
 	return nil
 }
 
+// SetContentHash records the content hash of a stored torrent subtitle, which
+// is read from the torrent after the record is created. When dedupe is enabled
+// and identical content is already stored under another language, one of the
+// two records is removed as in CreateTorrentSubtitle. Returns true if a record
+// was removed.
+func (s *Service) SetContentHash(ctx context.Context, sub *Subtitle, contentHash string) (bool, error) {
+	sub.ContentHash = contentHash
+
+	existing, replaced, err := s.resolveDuplicate(ctx, sub)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		if err := s.repo.Delete(ctx, sub.ID); err != nil && !errors.Is(err, ErrNotFound) {
+			return false, fmt.Errorf("failed to remove duplicate subtitle: %w", err)
+		}
+		return true, nil
+	}
+
+	if err := s.repo.SetContentHash(ctx, sub.ID, contentHash); err != nil {
+		return replaced, err
+	}
+	return replaced, nil
+}
+
+// resolveDuplicate checks whether a subtitle with the same content is already
+// stored for the item under a different language. It returns the record to keep
+// when the incoming subtitle should be dropped, or nil when the incoming one
+// should be stored. An existing duplicate with an undetected language is
+// removed in favour of an incoming subtitle whose language is known, which is
+// reported by the second result.
+func (s *Service) resolveDuplicate(ctx context.Context, sub *Subtitle) (*Subtitle, bool, error) {
+	if !s.dedupeIdentical || sub.ContentHash == "" {
+		return nil, false, nil
+	}
+
+	existing, err := s.repo.GetByItemAndHash(ctx, sub.ItemType, sub.ItemID, sub.ContentHash)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check for duplicate subtitle: %w", err)
+	}
+	// Same language is handled by the repository upsert
+	if existing == nil || existing.ID == sub.ID || existing.LanguageCode == sub.LanguageCode {
+		return nil, false, nil
+	}
+
+	if existing.LanguageCode == UnknownLanguageCode && sub.LanguageCode != UnknownLanguageCode {
+		if err := s.repo.Delete(ctx, existing.ID); err != nil {
+			return nil, false, fmt.Errorf("failed to replace duplicate subtitle: %w", err)
+		}
+		if existing.Source != SourceTorrent {
+			if err := os.Remove(existing.FilePath); err != nil && !os.IsNotExist(err) {
+				slog.Error("Failed to delete subtitle file", "path", existing.FilePath, "error", err)
+			}
+		}
+		slog.Info("Replaced duplicate subtitle",
+			"item_type", sub.ItemType,
+			"item_id", sub.ItemID,
+			"replaced_id", existing.ID,
+			"replaced_source", existing.Source,
+			"language", sub.LanguageCode,
+		)
+		return nil, true, nil
+	}
+
+	slog.Info("Skipped duplicate subtitle",
+		"item_type", sub.ItemType,
+		"item_id", sub.ItemID,
+		"language", sub.LanguageCode,
+		"kept_id", existing.ID,
+		"kept_source", existing.Source,
+		"kept_language", existing.LanguageCode,
+	)
+	return existing, false, nil
+}
+
 // writeFileAtomic writes content to a file atomically using a temp file and rename.
 func (s *Service) writeFileAtomic(path string, content []byte) error {
 	dir := filepath.Dir(path)
@@ -0,0 +1,204 @@
+package subtitle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// memRepo is an in-memory SubtitleRepository
+type memRepo struct {
+	subs   map[int64]*Subtitle
+	nextID int64
+}
+
+func newMemRepo(subs ...*Subtitle) *memRepo {
+	r := &memRepo{subs: make(map[int64]*Subtitle)}
+	for _, sub := range subs {
+		r.Create(context.Background(), sub)
+	}
+	return r
+}
+
+func (r *memRepo) Create(_ context.Context, sub *Subtitle) error {
+	r.nextID++
+	sub.ID = r.nextID
+	stored := *sub
+	r.subs[sub.ID] = &stored
+	return nil
+}
+
+func (r *memRepo) GetByID(_ context.Context, id int64) (*Subtitle, error) {
+	return r.subs[id], nil
+}
+
+func (r *memRepo) GetByItem(_ context.Context, itemType ItemType, itemID int64) ([]*Subtitle, error) {
+	var out []*Subtitle
+	for _, sub := range r.subs {
+		if sub.ItemType == itemType && sub.ItemID == itemID {
+			out = append(out, sub)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepo) GetByItemAndLanguage(_ context.Context, itemType ItemType, itemID int64, languageCode string) (*Subtitle, error) {
+	for _, sub := range r.subs {
+		if sub.ItemType == itemType && sub.ItemID == itemID && sub.LanguageCode == languageCode {
+			return sub, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRepo) GetByItemAndHash(_ context.Context, itemType ItemType, itemID int64, contentHash string) (*Subtitle, error) {
+	for _, sub := range r.subs {
+		if sub.ItemType == itemType && sub.ItemID == itemID && sub.ContentHash == contentHash {
+			return sub, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRepo) SetOffset(_ context.Context, id int64, offsetMs int64) error {
+	if r.subs[id] == nil {
+		return ErrNotFound
+	}
+	r.subs[id].OffsetMs = offsetMs
+	return nil
+}
+
+func (r *memRepo) SetContentHash(_ context.Context, id int64, contentHash string) error {
+	if r.subs[id] == nil {
+		return ErrNotFound
+	}
+	r.subs[id].ContentHash = contentHash
+	return nil
+}
+
+func (r *memRepo) Delete(_ context.Context, id int64) error {
+	if r.subs[id] == nil {
+		return ErrNotFound
+	}
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memRepo) DeleteByItem(_ context.Context, itemType ItemType, itemID int64) error {
+	for id, sub := range r.subs {
+		if sub.ItemType == itemType && sub.ItemID == itemID {
+			delete(r.subs, id)
+		}
+	}
+	return nil
+}
+
+func TestResolveDuplicate(t *testing.T) {
+	ctx := context.Background()
+	stored := func(lang string) *Subtitle {
+		return &Subtitle{ItemType: ItemTypeEpisode, ItemID: 1, LanguageCode: lang, ContentHash: "abc", Source: SourceTorrent}
+	}
+	incoming := func(lang, hash string) *Subtitle {
+		return &Subtitle{ItemType: ItemTypeEpisode, ItemID: 1, LanguageCode: lang, ContentHash: hash}
+	}
+
+	tests := []struct {
+		name         string
+		dedupe       bool
+		existing     *Subtitle
+		incoming     *Subtitle
+		wantKept     bool
+		wantReplaced bool
+	}{
+		{"disabled", false, stored("en"), incoming("ru", "abc"), false, false},
+		{"no hash", true, stored("en"), incoming("ru", ""), false, false},
+		{"different content", true, stored("en"), incoming("ru", "def"), false, false},
+		{"same language", true, stored("en"), incoming("en", "abc"), false, false},
+		{"other language", true, stored("en"), incoming("ru", "abc"), true, false},
+		{"unknown incoming", true, stored("en"), incoming(UnknownLanguageCode, "abc"), true, false},
+		{"unknown existing", true, stored(UnknownLanguageCode), incoming("en", "abc"), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo(tt.existing)
+			s := NewService(nil, repo, t.TempDir())
+			s.SetDedupeIdentical(tt.dedupe)
+
+			kept, replaced, err := s.resolveDuplicate(ctx, tt.incoming)
+			if err != nil {
+				t.Fatalf("resolveDuplicate: %v", err)
+			}
+			if (kept != nil) != tt.wantKept {
+				t.Errorf("kept = %v, want kept %v", kept, tt.wantKept)
+			}
+			if kept != nil && kept.ID != tt.existing.ID {
+				t.Errorf("kept ID = %d, want %d", kept.ID, tt.existing.ID)
+			}
+			if replaced != tt.wantReplaced {
+				t.Errorf("replaced = %v, want %v", replaced, tt.wantReplaced)
+			}
+			if _, ok := repo.subs[tt.existing.ID]; ok == tt.wantReplaced {
+				t.Errorf("existing record present = %v, want %v", ok, !tt.wantReplaced)
+			}
+		})
+	}
+}
+
+func TestResolveDuplicateRemovesDownloadedFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "unknown.srt")
+	if err := os.WriteFile(file, []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	existing := &Subtitle{ItemType: ItemTypeMovie, ItemID: 1, LanguageCode: UnknownLanguageCode, ContentHash: "abc", Source: SourceOpenSubtitles, FilePath: file}
+	s := NewService(nil, newMemRepo(existing), t.TempDir())
+	s.SetDedupeIdentical(true)
+
+	incoming := &Subtitle{ItemType: ItemTypeMovie, ItemID: 1, LanguageCode: "en", ContentHash: "abc"}
+	if _, replaced, err := s.resolveDuplicate(context.Background(), incoming); err != nil || !replaced {
+		t.Fatalf("resolveDuplicate = %v, %v; want replaced", replaced, err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("replaced subtitle file still exists: %v", err)
+	}
+}
+
+func TestSetContentHash(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unique", func(t *testing.T) {
+		repo := newMemRepo()
+		s := NewService(nil, repo, t.TempDir())
+		s.SetDedupeIdentical(true)
+		sub := &Subtitle{ItemType: ItemTypeEpisode, ItemID: 1, LanguageCode: "en", Source: SourceTorrent}
+		repo.Create(ctx, sub)
+
+		removed, err := s.SetContentHash(ctx, sub, "abc")
+		if err != nil || removed {
+			t.Fatalf("SetContentHash = %v, %v; want false, nil", removed, err)
+		}
+		if got := repo.subs[sub.ID].ContentHash; got != "abc" {
+			t.Errorf("stored hash = %q, want abc", got)
+		}
+	})
+
+	t.Run("duplicate of other language", func(t *testing.T) {
+		existing := &Subtitle{ItemType: ItemTypeEpisode, ItemID: 1, LanguageCode: "en", ContentHash: "abc", Source: SourceTorrent}
+		repo := newMemRepo(existing)
+		s := NewService(nil, repo, t.TempDir())
+		s.SetDedupeIdentical(true)
+		sub := &Subtitle{ItemType: ItemTypeEpisode, ItemID: 1, LanguageCode: "ru", Source: SourceTorrent}
+		repo.Create(ctx, sub)
+
+		removed, err := s.SetContentHash(ctx, sub, "abc")
+		if err != nil || !removed {
+			t.Fatalf("SetContentHash = %v, %v; want true, nil", removed, err)
+		}
+		if _, ok := repo.subs[sub.ID]; ok {
+			t.Error("duplicate subtitle was kept")
+		}
+		if _, ok := repo.subs[existing.ID]; !ok {
+			t.Error("original subtitle was removed")
+		}
+	})
+}
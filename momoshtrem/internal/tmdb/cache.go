@@ -0,0 +1,171 @@
+package tmdb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxCacheEntries bounds the in-memory cache. When full, expired entries are
+// dropped first, then the entry closest to expiry (the oldest stored).
+const maxCacheEntries = 2000
+
+// responseCache stores raw TMDB response bodies keyed by endpoint.
+// Bodies are decoded on every hit so callers never share mutable values.
+// When dir is set, entries are also written to disk and survive restarts;
+// a file's modification time is used as its store time. Stale files are
+// removed when the cache is created.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	dir     string
+	entries map[string]cacheEntry
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration, dir string) *responseCache {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			slog.Warn("TMDB disk cache disabled", "dir", dir, "error", err)
+			dir = ""
+		}
+	}
+	if dir != "" {
+		pruneDisk(dir, ttl, time.Now())
+	}
+	return &responseCache{
+		ttl:     ttl,
+		dir:     dir,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached body for a key if it hasn't expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && now.After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok && c.dir != "" {
+		entry, ok = c.readDisk(key, now)
+		if ok {
+			c.mu.Lock()
+			c.store(key, entry)
+			c.mu.Unlock()
+		}
+	}
+
+	if ok {
+		c.hits.Add(1)
+		return entry.body, true
+	}
+	c.misses.Add(1)
+	return nil, false
+}
+
+// put stores a response body
+func (c *responseCache) put(key string, body []byte) {
+	c.mu.Lock()
+	c.store(key, cacheEntry{body: body, expires: time.Now().Add(c.ttl)})
+	c.mu.Unlock()
+
+	if c.dir != "" {
+		path := c.diskPath(key)
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, body, 0644); err != nil {
+			slog.Debug("Failed to write TMDB cache file", "path", path, "error", err)
+			return
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			slog.Debug("Failed to write TMDB cache file", "path", path, "error", err)
+			os.Remove(tmp)
+		}
+	}
+}
+
+// store adds an entry, evicting to stay within maxCacheEntries.
+// Must be called with c.mu held.
+func (c *responseCache) store(key string, entry cacheEntry) {
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		c.evict(time.Now())
+	}
+	c.entries[key] = entry
+}
+
+// evict drops expired entries, or the one expiring soonest if none have
+func (c *responseCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	expired := false
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			expired = true
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if !expired && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// pruneDisk removes cache files older than ttl
+func pruneDisk(dir string, ttl time.Duration, now time.Time) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || f.IsDir() {
+			continue
+		}
+		if now.After(info.ModTime().Add(ttl)) {
+			os.Remove(filepath.Join(dir, f.Name()))
+		}
+	}
+}
+
+func (c *responseCache) readDisk(key string, now time.Time) (cacheEntry, bool) {
+	path := c.diskPath(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	expires := info.ModTime().Add(c.ttl)
+	if now.After(expires) {
+		os.Remove(path)
+		return cacheEntry{}, false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	return cacheEntry{body: body, expires: expires}, true
+}
+
+func (c *responseCache) diskPath(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,151 @@
+package tmdb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	c := newResponseCache(time.Hour, "")
+
+	if _, ok := c.get("a"); ok {
+		t.Fatal("empty cache returned a hit")
+	}
+	c.put("a", []byte(`{"id":1}`))
+	if body, ok := c.get("a"); !ok || string(body) != `{"id":1}` {
+		t.Fatalf("get = %q, %v", body, ok)
+	}
+
+	// Expired entries are dropped
+	c.mu.Lock()
+	c.entries["a"] = cacheEntry{body: []byte("old"), expires: time.Now().Add(-time.Second)}
+	c.mu.Unlock()
+	if _, ok := c.get("a"); ok {
+		t.Error("expired entry returned a hit")
+	}
+
+	if hits, misses := c.hits.Load(), c.misses.Load(); hits != 1 || misses != 2 {
+		t.Errorf("hits/misses = %d/%d, want 1/2", hits, misses)
+	}
+}
+
+func TestResponseCacheDisk(t *testing.T) {
+	dir := t.TempDir()
+	newResponseCache(time.Hour, dir).put("tv/1", []byte(`{"id":1}`))
+
+	// A new cache (e.g. after restart) reads the entry back from disk
+	c := newResponseCache(time.Hour, dir)
+	if body, ok := c.get("tv/1"); !ok || string(body) != `{"id":1}` {
+		t.Fatalf("disk get = %q, %v", body, ok)
+	}
+
+	// Entries older than the TTL are ignored and removed
+	path := c.diskPath("tv/1")
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	c = newResponseCache(time.Hour, dir)
+	if _, ok := c.get("tv/1"); ok {
+		t.Error("stale disk entry returned a hit")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("stale disk entry not removed: %v", err)
+	}
+}
+
+// countingTransport answers every request with body and counts the requests
+type countingTransport struct {
+	body     string
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestTVChangesNotCached(t *testing.T) {
+	transport := &countingTransport{body: `{"results":[{"id":7}],"total_pages":1}`}
+	c := NewClient("key", WithCache(time.Hour, ""))
+	c.httpClient.Transport = transport
+
+	end := time.Now()
+	for i := 0; i < 2; i++ {
+		ids, err := c.GetTVChanges(end.Add(-24*time.Hour), end)
+		if err != nil {
+			t.Fatalf("GetTVChanges: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != 7 {
+			t.Fatalf("ids = %v, want [7]", ids)
+		}
+	}
+	if transport.requests != 2 {
+		t.Errorf("requests = %d, want every change list fetched from TMDB", transport.requests)
+	}
+	if hits, misses := c.CacheStats(); hits != 0 || misses != 0 {
+		t.Errorf("cache hits/misses = %d/%d, want the cache untouched", hits, misses)
+	}
+}
+
+func TestResponseCacheBounded(t *testing.T) {
+	c := newResponseCache(time.Hour, "")
+	for i := 0; i < maxCacheEntries; i++ {
+		c.put(fmt.Sprintf("tv/%d", i), []byte("{}"))
+	}
+
+	// Full with live entries: the oldest makes room
+	c.mu.Lock()
+	c.entries["tv/0"] = cacheEntry{body: []byte("{}"), expires: time.Now().Add(time.Minute)}
+	c.mu.Unlock()
+	c.put("new", []byte("{}"))
+	if len(c.entries) != maxCacheEntries {
+		t.Errorf("len = %d, want capped at %d", len(c.entries), maxCacheEntries)
+	}
+	if _, ok := c.entries["tv/0"]; ok {
+		t.Error("oldest entry not evicted")
+	}
+
+	// Expired entries are all dropped before any live one
+	c.mu.Lock()
+	for i := 1; i <= 10; i++ {
+		c.entries[fmt.Sprintf("tv/%d", i)] = cacheEntry{expires: time.Now().Add(-time.Second)}
+	}
+	c.mu.Unlock()
+	c.put("newer", []byte("{}"))
+	if want := maxCacheEntries - 9; len(c.entries) != want {
+		t.Errorf("len = %d, want %d after dropping expired entries", len(c.entries), want)
+	}
+	if _, ok := c.entries["new"]; !ok {
+		t.Error("live entry evicted while expired ones remained")
+	}
+}
+
+func TestResponseCachePrunesDiskOnStart(t *testing.T) {
+	dir := t.TempDir()
+	c := newResponseCache(time.Hour, dir)
+	c.put("fresh", []byte("{}"))
+	c.put("stale", []byte("{}"))
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(c.diskPath("stale"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	newResponseCache(time.Hour, dir)
+
+	if _, err := os.Stat(c.diskPath("stale")); !os.IsNotExist(err) {
+		t.Errorf("stale file not pruned: %v", err)
+	}
+	if _, err := os.Stat(c.diskPath("fresh")); err != nil {
+		t.Errorf("fresh file removed: %v", err)
+	}
+}
@@ -1,38 +1,105 @@
 package tmdb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
 const baseURL = "https://api.themoviedb.org/3"
 
+// ImageBaseURL is the TMDB image CDN prefix for full-size images
+const ImageBaseURL = "https://image.tmdb.org/t/p/original"
+
+// maxResponseSize bounds TMDB response bodies (season details are the largest)
+const maxResponseSize = 16 << 20
+
 // Client is a TMDB API client
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
+
+	cache       *responseCache // Optional: caches movie, show and season lookups
+	bypassCache bool           // Set on clients returned by Uncached
+}
+
+var (
+	// ErrNotFound is returned when TMDB has no item with the requested ID
+	ErrNotFound = errors.New("not found on TMDB")
+	// ErrUnavailable is returned when TMDB can't be reached or fails with a server error
+	ErrUnavailable = errors.New("TMDB unavailable")
+)
+
+// RateLimitError is returned when TMDB responds with 429 Too Many Requests
+type RateLimitError struct {
+	RetryAfter time.Duration // From the Retry-After header; 0 if absent
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited by TMDB, retry after %s", e.RetryAfter)
+	}
+	return "rate limited by TMDB"
+}
+
+// Option configures optional Client behavior
+type Option func(*Client)
+
+// WithCache caches GetMovie, GetShowDetails and GetSeason responses for ttl.
+// If dir is non-empty, responses are also stored on disk and reused after restarts.
+// A ttl <= 0 disables caching.
+func WithCache(ttl time.Duration, dir string) Option {
+	return func(c *Client) {
+		if ttl > 0 {
+			c.cache = newResponseCache(ttl, dir)
+		}
+	}
 }
 
 // NewClient creates a new TMDB client
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey: apiKey,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Uncached returns a client that always queries TMDB (for forced refreshes).
+// Fresh responses still replace cached entries, so later cached reads see them.
+func (c *Client) Uncached() *Client {
+	clone := *c
+	clone.bypassCache = true
+	return &clone
+}
+
+// CacheStats returns the number of cache hits and misses (zero if caching is disabled)
+func (c *Client) CacheStats() (hits, misses uint64) {
+	if c.cache == nil {
+		return 0, 0
+	}
+	return c.cache.hits.Load(), c.cache.misses.Load()
 }
 
 // Movie represents a movie from TMDB
 type Movie struct {
-	ID          int    `json:"id"`
-	Title       string `json:"title"`
-	ReleaseDate string `json:"release_date"`
-	Overview    string `json:"overview"`
-	PosterPath  string `json:"poster_path"`
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+	ReleaseDate  string `json:"release_date"`
+	Overview     string `json:"overview"`
+	PosterPath   string `json:"poster_path"`
+	BackdropPath string `json:"backdrop_path"`
 }
 
 // Year extracts the year from the release date
@@ -45,6 +112,14 @@ func (m *Movie) Year() int {
 	return year
 }
 
+// ImageURL returns the CDN URL for a TMDB image path (e.g. "/abc.jpg"), or "" if there is no image
+func ImageURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	return ImageBaseURL + path
+}
+
 // Show represents a TV show from TMDB
 type Show struct {
 	ID           int    `json:"id"`
@@ -52,6 +127,7 @@ type Show struct {
 	FirstAirDate string `json:"first_air_date"`
 	Overview     string `json:"overview"`
 	PosterPath   string `json:"poster_path"`
+	BackdropPath string `json:"backdrop_path"`
 }
 
 // Year extracts the year from the first air date
@@ -96,7 +172,7 @@ func (c *Client) GetMovie(id int) (*Movie, error) {
 	endpoint := fmt.Sprintf("%s/movie/%d", baseURL, id)
 
 	movie := &Movie{}
-	if err := c.get(endpoint, movie); err != nil {
+	if err := c.getCached(endpoint, movie); err != nil {
 		return nil, err
 	}
 
@@ -120,7 +196,7 @@ func (c *Client) GetShowDetails(id int) (*ShowDetails, error) {
 	endpoint := fmt.Sprintf("%s/tv/%d", baseURL, id)
 
 	details := &ShowDetails{}
-	if err := c.get(endpoint, details); err != nil {
+	if err := c.getCached(endpoint, details); err != nil {
 		return nil, err
 	}
 
@@ -132,7 +208,7 @@ func (c *Client) GetSeason(showID int, seasonNumber int) (*Season, error) {
 	endpoint := fmt.Sprintf("%s/tv/%d/season/%d", baseURL, showID, seasonNumber)
 
 	season := &Season{}
-	if err := c.get(endpoint, season); err != nil {
+	if err := c.getCached(endpoint, season); err != nil {
 		return nil, err
 	}
 
@@ -167,34 +243,144 @@ func (c *Client) SearchShows(query string) ([]Show, error) {
 	return result.Results, nil
 }
 
+// MaxChangesWindow is the longest period the TMDB changes endpoints accept
+const MaxChangesWindow = 14 * 24 * time.Hour
+
+// maxChangesPages bounds pagination of the changes endpoint (TMDB's own page limit)
+const maxChangesPages = 500
+
+// GetTVChanges returns the IDs of TV shows changed between start and end.
+// TMDB only accepts windows up to MaxChangesWindow and has day granularity.
+// Change lists are never cached, even on a client with a cache.
+func (c *Client) GetTVChanges(start, end time.Time) ([]int, error) {
+	startDate := start.UTC().Format("2006-01-02")
+	endDate := end.UTC().Format("2006-01-02")
+
+	var ids []int
+	for page := 1; page <= maxChangesPages; page++ {
+		endpoint := fmt.Sprintf("%s/tv/changes?start_date=%s&end_date=%s&page=%d", baseURL, startDate, endDate, page)
+
+		var result struct {
+			Results []struct {
+				ID int `json:"id"`
+			} `json:"results"`
+			TotalPages int `json:"total_pages"`
+		}
+		if err := c.get(endpoint, &result); err != nil {
+			return nil, err
+		}
+
+		for _, r := range result.Results {
+			ids = append(ids, r.ID)
+		}
+		if page >= result.TotalPages {
+			break
+		}
+	}
+
+	return ids, nil
+}
+
+// Ping checks that the TMDB API is reachable and accepts the API key,
+// giving up when ctx is done
+func (c *Client) Ping(ctx context.Context) error {
+	var config struct {
+		Images struct {
+			BaseURL string `json:"base_url"`
+		} `json:"images"`
+	}
+	body, err := c.getBody(ctx, baseURL+"/configuration")
+	if err != nil {
+		return fmt.Errorf("failed to reach TMDB: %w", err)
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// getCached is get with response caching when the client has a cache.
+// Only successful responses are cached.
+func (c *Client) getCached(endpoint string, v interface{}) error {
+	if c.cache == nil {
+		return c.get(endpoint, v)
+	}
+
+	if !c.bypassCache {
+		if body, ok := c.cache.get(endpoint); ok {
+			if err := json.Unmarshal(body, v); err == nil {
+				return nil
+			}
+			// Corrupt entry: fall through and refetch
+		}
+	}
+
+	body, err := c.getBody(context.Background(), endpoint)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	c.cache.put(endpoint, body)
+	return nil
+}
+
 // get performs a GET request and decodes the response
 func (c *Client) get(endpoint string, v interface{}) error {
+	body, err := c.getBody(context.Background(), endpoint)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// getBody performs a GET request and returns the response body
+func (c *Client) getBody(ctx context.Context, endpoint string) ([]byte, error) {
 	// Add API key
 	u, err := url.Parse(endpoint)
 	if err != nil {
-		return fmt.Errorf("invalid endpoint: %w", err)
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
 	}
 
 	q := u.Query()
 	q.Set("api_key", c.apiKey)
 	u.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Get(u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: request failed: %v", ErrUnavailable, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("not found")
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rateErr := &RateLimitError{}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			rateErr.RetryAfter = time.Duration(secs) * time.Second
+		}
+		return nil, rateErr
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return nil
+	return body, nil
 }
@@ -0,0 +1,34 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// hangingTransport never answers, returning only once the request is cancelled
+type hangingTransport struct{}
+
+func (hangingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestPingGivesUpWithContext(t *testing.T) {
+	c := NewClient("key")
+	c.httpClient.Transport = hangingTransport{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Ping(ctx)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Ping() = %v, want ErrUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Ping took %s, want it bounded by the context", elapsed)
+	}
+}
@@ -24,6 +24,7 @@ type ActivityManager struct {
 	torrents   map[string]*torrent.Torrent // hash -> torrent
 	lastAccess map[string]time.Time        // hash -> last access time
 	state      map[string]TorrentState     // hash -> current state
+	readers    map[string]int              // hash -> open file readers
 
 	idleTimeout   time.Duration
 	checkInterval time.Duration
@@ -36,14 +37,16 @@ type ActivityManager struct {
 
 // NewActivityManager creates a new activity manager.
 // idleTimeout: duration of inactivity before pausing a torrent
+// checkInterval: how often to look for torrents past idleTimeout
 // startPaused: whether new torrents should start with network disabled
-func NewActivityManager(idleTimeout time.Duration, startPaused bool) *ActivityManager {
+func NewActivityManager(idleTimeout, checkInterval time.Duration, startPaused bool) *ActivityManager {
 	return &ActivityManager{
 		torrents:      make(map[string]*torrent.Torrent),
 		lastAccess:    make(map[string]time.Time),
 		state:         make(map[string]TorrentState),
+		readers:       make(map[string]int),
 		idleTimeout:   idleTimeout,
-		checkInterval: 30 * time.Second,
+		checkInterval: checkInterval,
 		startPaused:   startPaused,
 		stopChan:      make(chan struct{}),
 		log:           slog.With("component", "activity-manager"),
@@ -80,6 +83,7 @@ func (am *ActivityManager) Unregister(hash string) {
 	delete(am.torrents, hash)
 	delete(am.lastAccess, hash)
 	delete(am.state, hash)
+	delete(am.readers, hash)
 
 	am.log.Info("unregistered torrent", "hash", hash)
 }
@@ -100,6 +104,40 @@ func (am *ActivityManager) MarkActive(hash string) {
 	}
 }
 
+// Acquire signals that a reader was opened on one of the torrent's files.
+// It marks the torrent active like MarkActive and is paired with Release.
+func (am *ActivityManager) Acquire(hash string) {
+	am.MarkActive(hash)
+
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	if _, ok := am.torrents[hash]; ok {
+		am.readers[hash]++
+	}
+}
+
+// Release signals that a reader opened with Acquire was closed. Once the
+// torrent's last reader closes, the idle countdown restarts from the close,
+// so a torrent goes idle idleTimeout after playback stops even if reads
+// ended earlier. Closing one of several readers leaves the countdown to the
+// reads of the others.
+func (am *ActivityManager) Release(hash string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	if _, ok := am.torrents[hash]; !ok {
+		return
+	}
+	if am.readers[hash] > 1 {
+		am.readers[hash]--
+		return
+	}
+	delete(am.readers, hash)
+	am.lastAccess[hash] = time.Now()
+	am.log.Debug("last file released, idle countdown restarted", "hash", hash)
+}
+
 // WaitForActivation blocks until the torrent has connected peers or timeout expires.
 // Returns immediately if: torrent not registered, already has peers, or start_paused is false.
 // This fixes the race condition where MarkActive enables network but the torrent needs
@@ -245,6 +283,36 @@ func (am *ActivityManager) IsPaused(hash string) bool {
 	return am.GetState(hash) == StateIdle
 }
 
+// TorrentActivity is the idle-mode state of one torrent.
+type TorrentActivity struct {
+	State      TorrentState
+	LastAccess time.Time
+
+	// Remaining time before an active torrent goes idle; zero once idle.
+	// The idle check runs periodically, so the switch can lag by up to its interval.
+	UntilIdle time.Duration
+}
+
+// GetActivity returns the idle-mode state of a torrent.
+// Returns false if the torrent isn't registered.
+func (am *ActivityManager) GetActivity(hash string) (TorrentActivity, bool) {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	if _, ok := am.torrents[hash]; !ok {
+		return TorrentActivity{}, false
+	}
+
+	activity := TorrentActivity{
+		State:      am.state[hash],
+		LastAccess: am.lastAccess[hash],
+	}
+	if activity.State == StateActive {
+		activity.UntilIdle = max(am.idleTimeout-time.Since(activity.LastAccess), 0)
+	}
+	return activity, true
+}
+
 // GetStats returns activity statistics for monitoring/debugging.
 func (am *ActivityManager) GetStats() map[string]interface{} {
 	am.mu.RLock()
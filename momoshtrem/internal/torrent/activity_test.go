@@ -0,0 +1,56 @@
+package torrent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityManagerGetActivity(t *testing.T) {
+	am := NewActivityManager(10*time.Minute, 30*time.Second, false)
+
+	if _, ok := am.GetActivity("unknown"); ok {
+		t.Fatal("GetActivity(unknown) found a torrent")
+	}
+
+	// Registered directly: Register needs a live torrent to toggle its network
+	lastAccess := time.Now().Add(-4 * time.Minute)
+	am.torrents["active"], am.lastAccess["active"], am.state["active"] = nil, lastAccess, StateActive
+	am.torrents["idle"], am.lastAccess["idle"], am.state["idle"] = nil, lastAccess.Add(-time.Hour), StateIdle
+
+	activity, ok := am.GetActivity("active")
+	if !ok || activity.State != StateActive || !activity.LastAccess.Equal(lastAccess) {
+		t.Fatalf("GetActivity(active) = %+v, %v", activity, ok)
+	}
+	if activity.UntilIdle <= 5*time.Minute || activity.UntilIdle > 6*time.Minute {
+		t.Errorf("UntilIdle = %v, want about 6m", activity.UntilIdle)
+	}
+
+	activity, ok = am.GetActivity("idle")
+	if !ok || activity.State != StateIdle || activity.UntilIdle != 0 {
+		t.Errorf("GetActivity(idle) = %+v, %v; want idle with no time left", activity, ok)
+	}
+}
+
+func TestActivityManagerReleaseLastReader(t *testing.T) {
+	am := NewActivityManager(10*time.Minute, 30*time.Second, false)
+	am.torrents["hash"], am.state["hash"] = nil, StateActive
+
+	am.Acquire("hash")
+	am.Acquire("hash")
+
+	stale := time.Now().Add(-time.Hour)
+	am.lastAccess["hash"] = stale
+
+	am.Release("hash")
+	if !am.lastAccess["hash"].Equal(stale) {
+		t.Error("closing one of two readers restarted the idle countdown")
+	}
+
+	am.Release("hash")
+	if !am.lastAccess["hash"].After(stale) {
+		t.Error("closing the last reader did not restart the idle countdown")
+	}
+	if _, ok := am.readers["hash"]; ok {
+		t.Error("reader count kept after the last reader closed")
+	}
+}
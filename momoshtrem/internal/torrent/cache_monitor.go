@@ -0,0 +1,161 @@
+package torrent
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anacrolix/missinggo/v2/filecache"
+)
+
+// completedPiecePrefix is where resource piece storage keeps verified pieces,
+// one cache item per piece named by its hex hash
+const completedPiecePrefix = "completed/"
+
+// CacheUsage reports the piece cache fill and evictions since startup
+type CacheUsage struct {
+	CapacityBytes int64 `json:"capacity_bytes"`
+	UsedBytes     int64 `json:"used_bytes"` // Bytes of piece data on disk
+	Items         int   `json:"items"`
+
+	EvictedPieces          uint64 `json:"evicted_pieces"`
+	EvictedBytes           uint64 `json:"evicted_bytes"`
+	EvictedStreamingPieces uint64 `json:"evicted_streaming_pieces"` // Pieces of files open for streaming, downloaded again if played
+}
+
+// CacheMonitor reports piece cache usage and detects evictions. filecache has
+// no eviction hook, so completed pieces are snapshotted every interval and the
+// ones that disappeared are counted as evicted. Pieces dropped because they
+// failed verification are rare and counted too.
+type CacheMonitor struct {
+	cache    *filecache.Cache
+	interval time.Duration
+
+	// Optional: hex hashes of pieces in files currently being streamed
+	streamingPieces func() map[string]struct{}
+
+	mu        sync.Mutex
+	completed map[string]int64 // Piece hash -> size at the last check
+
+	evictedPieces          atomic.Uint64
+	evictedBytes           atomic.Uint64
+	evictedStreamingPieces atomic.Uint64
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+	log      *slog.Logger
+}
+
+// NewCacheMonitor creates a monitor that checks the cache every interval.
+func NewCacheMonitor(cache *filecache.Cache, interval time.Duration) *CacheMonitor {
+	return &CacheMonitor{
+		cache:    cache,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		log:      slog.With("component", "cache-monitor"),
+	}
+}
+
+// SetStreamingPieces configures how evicted pieces are matched to open streams.
+func (m *CacheMonitor) SetStreamingPieces(fn func() map[string]struct{}) {
+	m.streamingPieces = fn
+}
+
+// Start takes the first snapshot and begins checking for evictions.
+func (m *CacheMonitor) Start() {
+	m.check()
+	go m.loop()
+}
+
+// Stop halts the background checks.
+func (m *CacheMonitor) Stop() {
+	m.stopOnce.Do(func() { close(m.stopChan) })
+}
+
+// Usage returns the current cache fill and eviction counts.
+func (m *CacheMonitor) Usage() CacheUsage {
+	info := m.cache.Info()
+	return CacheUsage{
+		CapacityBytes:          info.Capacity,
+		UsedBytes:              info.Filled,
+		Items:                  info.NumItems,
+		EvictedPieces:          m.evictedPieces.Load(),
+		EvictedBytes:           m.evictedBytes.Load(),
+		EvictedStreamingPieces: m.evictedStreamingPieces.Load(),
+	}
+}
+
+func (m *CacheMonitor) loop() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stopChan:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check compares the completed pieces with the previous snapshot
+func (m *CacheMonitor) check() {
+	completed := make(map[string]int64)
+	m.cache.WalkItems(func(item filecache.ItemInfo) {
+		if hash, ok := strings.CutPrefix(string(item.Path), completedPiecePrefix); ok {
+			completed[hash] = item.Size
+		}
+	})
+
+	m.mu.Lock()
+	previous := m.completed
+	m.completed = completed
+	m.mu.Unlock()
+
+	var evicted []string
+	var evictedBytes int64
+	for hash, size := range previous {
+		if _, ok := completed[hash]; !ok {
+			evicted = append(evicted, hash)
+			evictedBytes += size
+		}
+	}
+	if len(evicted) == 0 {
+		return
+	}
+
+	streaming := 0
+	if m.streamingPieces != nil {
+		open := m.streamingPieces()
+		for _, hash := range evicted {
+			if _, ok := open[hash]; ok {
+				streaming++
+			}
+		}
+	}
+
+	m.evictedPieces.Add(uint64(len(evicted)))
+	m.evictedBytes.Add(uint64(evictedBytes))
+	m.evictedStreamingPieces.Add(uint64(streaming))
+
+	usage := m.cache.Info()
+	if streaming > 0 {
+		m.log.Warn("Piece cache evicted pieces of open streams; the cache may be too small for concurrent streams",
+			"evicted_pieces", len(evicted),
+			"evicted_bytes", evictedBytes,
+			"streaming_pieces", streaming,
+			"used_bytes", usage.Filled,
+			"capacity_bytes", usage.Capacity,
+		)
+		return
+	}
+	m.log.Info("Piece cache evicted pieces",
+		"evicted_pieces", len(evicted),
+		"evicted_bytes", evictedBytes,
+		"used_bytes", usage.Filled,
+		"capacity_bytes", usage.Capacity,
+	)
+}
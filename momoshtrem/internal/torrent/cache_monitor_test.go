@@ -0,0 +1,55 @@
+package torrent
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/missinggo/v2/filecache"
+)
+
+func writeCacheItem(t *testing.T, c *filecache.Cache, path string, size int) {
+	t.Helper()
+	f, err := c.OpenFile(path, os.O_CREATE|os.O_WRONLY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write(make([]byte, size)); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
+
+func TestCacheMonitorCountsEvictions(t *testing.T) {
+	cache, err := filecache.NewCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewCacheMonitor(cache, time.Hour)
+	m.SetStreamingPieces(func() map[string]struct{} { return map[string]struct{}{"aa": {}} })
+
+	writeCacheItem(t, cache, "completed/aa", 100)
+	writeCacheItem(t, cache, "completed/bb", 100)
+	writeCacheItem(t, cache, "incompleted/cc/0", 100) // Partial pieces aren't tracked
+	m.check()
+
+	// Shrinking the cap evicts every item
+	cache.SetCapacity(0)
+	cache.TrimToCapacity()
+	m.check()
+
+	usage := m.Usage()
+	if usage.EvictedPieces != 2 || usage.EvictedBytes != 200 || usage.EvictedStreamingPieces != 1 {
+		t.Errorf("usage = %+v, want 2 pieces, 200 bytes, 1 streaming", usage)
+	}
+	if usage.UsedBytes != 0 || usage.CapacityBytes != 0 {
+		t.Errorf("used %d of %d, want 0 of 0", usage.UsedBytes, usage.CapacityBytes)
+	}
+
+	// Evictions are counted once
+	m.check()
+	if got := m.Usage().EvictedPieces; got != 2 {
+		t.Errorf("EvictedPieces after another check = %d, want 2", got)
+	}
+}
@@ -46,6 +46,9 @@ func (h *torrentLogHandler) Handle(r tlog.Record) {
 
 // InitStorage creates the storage layer for torrents.
 // Returns the storage implementation, file cache, piece completion database, and any error.
+// Resource piece storage files each verified piece in the cache under its hash
+// and derives completion from that, so completed pieces survive restarts as
+// long as the cache directory does; the torrent only needs its metadata back.
 func InitStorage(metadataFolder string, cacheSizeMB int64) (storage.ClientImpl, *filecache.Cache, storage.PieceCompletion, error) {
 	// Create cache directory
 	cacheDir := filepath.Join(metadataFolder, "cache")
@@ -106,6 +109,16 @@ func NewClient(cfg *config.TorrentConfig, cc *ClientConfig) (*torrent.Client, er
 		torrentCfg.MaxUnverifiedBytes = cfg.MaxUnverifiedMB * 1024 * 1024
 	}
 
+	// Peer connection limits. The client-wide half-open cap is raised with the
+	// per-torrent one so it doesn't silently win on a single busy torrent.
+	torrentCfg.EstablishedConnsPerTorrent = cfg.MaxEstablishedConns
+	torrentCfg.HalfOpenConnsPerTorrent = cfg.MaxHalfOpen
+	torrentCfg.TotalHalfOpenConns = max(torrentCfg.TotalHalfOpenConns, cfg.MaxHalfOpen)
+
+	// Peer discovery beyond trackers (private trackers ban clients that leak peers)
+	torrentCfg.NoDHT = !cfg.EnableDHT
+	torrentCfg.DisablePEX = !cfg.EnablePEX
+
 	// Configure logging
 	tl := tlog.NewLogger()
 	tl.SetHandlers(&torrentLogHandler{log: log})
@@ -128,7 +141,22 @@ func NewClient(cfg *config.TorrentConfig, cc *ClientConfig) (*torrent.Client, er
 		"ipv6_disabled", true,
 		"drop_duplicate_peers", cfg.DropDuplicatePeerIds,
 		"max_unverified_mb", cfg.MaxUnverifiedMB,
+		"max_established_conns", cfg.MaxEstablishedConns,
+		"max_half_open", cfg.MaxHalfOpen,
+		"total_half_open", torrentCfg.TotalHalfOpenConns,
+		"networking", networkingMode(cfg),
+		"dht", cfg.EnableDHT,
+		"pex", cfg.EnablePEX,
 	)
 
 	return client, nil
 }
+
+// networkingMode summarizes how peers are found: "trackers-only" when DHT and
+// PEX are both off (safe for private trackers), otherwise "public"
+func networkingMode(cfg *config.TorrentConfig) string {
+	if !cfg.EnableDHT && !cfg.EnablePEX {
+		return "trackers-only"
+	}
+	return "public"
+}
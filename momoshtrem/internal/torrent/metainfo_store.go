@@ -0,0 +1,88 @@
+package torrent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+// metainfoStore keeps the metadata (.torrent file) of every loaded torrent on
+// disk. Pieces in the cache survive restarts, but a torrent added by magnet
+// can't see them until peers send its metadata again; adding it from the stored
+// metainfo makes them readable immediately.
+type metainfoStore struct {
+	dir string
+}
+
+// newMetainfoStore returns nil when dir is empty, disabling persistence
+func newMetainfoStore(dir string) (*metainfoStore, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &metainfoStore{dir: dir}, nil
+}
+
+func (s *metainfoStore) path(hash string) string {
+	return filepath.Join(s.dir, hash+".torrent")
+}
+
+// load returns the stored metainfo of a torrent, or nil if there is none
+func (s *metainfoStore) load(hash string) (*metainfo.MetaInfo, error) {
+	mi, err := metainfo.LoadFromFile(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return mi, err
+}
+
+// has reports whether a torrent's metainfo is stored
+func (s *metainfoStore) has(hash string) bool {
+	_, err := os.Stat(s.path(hash))
+	return err == nil
+}
+
+// save writes a torrent's metainfo, replacing any earlier copy atomically
+func (s *metainfoStore) save(hash string, mi metainfo.MetaInfo) error {
+	tmp, err := os.CreateTemp(s.dir, hash+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if err := mi.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(hash))
+}
+
+// hashes lists the info hashes with stored metainfo
+func (s *metainfoStore) hashes() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.torrent"))
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(matches))
+	for i, m := range matches {
+		hashes[i] = strings.TrimSuffix(filepath.Base(m), ".torrent")
+	}
+	return hashes, nil
+}
+
+// remove deletes a torrent's stored metainfo, if any
+func (s *metainfoStore) remove(hash string) error {
+	err := os.Remove(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package torrent
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+)
+
+func TestMetainfoStoreRoundTrip(t *testing.T) {
+	store, err := newMetainfoStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := metainfo.Info{
+		Name:        "Movie.2020.1080p.mkv",
+		Length:      1 << 20,
+		PieceLength: 1 << 18,
+		Pieces:      make([]byte, 20*4),
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := metainfo.MetaInfo{InfoBytes: infoBytes}
+	hash := mi.HashInfoBytes().HexString()
+
+	if store.has(hash) {
+		t.Fatal("has() = true before save")
+	}
+	if got, err := store.load(hash); err != nil || got != nil {
+		t.Fatalf("load() before save = %v, %v; want nil, nil", got, err)
+	}
+
+	if err := store.save(hash, mi); err != nil {
+		t.Fatal(err)
+	}
+	if !store.has(hash) {
+		t.Fatal("has() = false after save")
+	}
+	got, err := store.load(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.HashInfoBytes().HexString() != hash {
+		t.Errorf("loaded info hash = %s, want %s", got.HashInfoBytes().HexString(), hash)
+	}
+
+	if err := store.remove(hash); err != nil {
+		t.Fatal(err)
+	}
+	if store.has(hash) {
+		t.Error("has() = true after remove")
+	}
+	if err := store.remove(hash); err != nil {
+		t.Errorf("second remove() = %v, want nil", err)
+	}
+}
+
+func TestNewMetainfoStoreDisabled(t *testing.T) {
+	store, err := newMetainfoStore("")
+	if err != nil || store != nil {
+		t.Fatalf("newMetainfoStore(\"\") = %v, %v; want nil, nil", store, err)
+	}
+}
+
+func TestPruneMetainfo(t *testing.T) {
+	store, err := newMetainfoStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newOfflineClient(t)
+
+	var hashes []string
+	var loaded *torrent.Torrent
+	for i := range 3 {
+		info := metainfo.Info{
+			Name:        fmt.Sprintf("Movie.%d.mkv", i),
+			Length:      1 << 20,
+			PieceLength: 1 << 18,
+			Pieces:      make([]byte, 20*4),
+		}
+		infoBytes, err := bencode.Marshal(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mi := metainfo.MetaInfo{InfoBytes: infoBytes}
+		hash := mi.HashInfoBytes().HexString()
+		if err := store.save(hash, mi); err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+		if i == 1 {
+			if loaded, err = client.AddTorrent(&mi); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// hashes[0] is assigned, hashes[1] loaded, hashes[2] neither
+	s := &service{
+		torrents: map[string]*torrent.Torrent{hashes[1]: loaded},
+		metainfo: store,
+		log:      slog.Default(),
+	}
+	removed, err := s.PruneMetainfo([]string{hashes[0]})
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneMetainfo = %d, %v; want 1, nil", removed, err)
+	}
+	for i, want := range []bool{true, true, false} {
+		if got := store.has(hashes[i]); got != want {
+			t.Errorf("torrent %d stored = %v, want %v", i, got, want)
+		}
+	}
+}
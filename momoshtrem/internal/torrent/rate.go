@@ -0,0 +1,59 @@
+package torrent
+
+import (
+	"sync"
+	"time"
+)
+
+// minRateInterval is the shortest window used to compute a transfer rate.
+// Calls within this window reuse the previous rate instead of dividing by a tiny duration.
+const minRateInterval = time.Second
+
+// rateSample is the last observation of a torrent's cumulative byte counters
+type rateSample struct {
+	read, written int64
+	at            time.Time
+	down, up      int64 // bytes per second over the last window
+}
+
+// rateTracker turns cumulative byte counters into per-second transfer rates.
+// It has its own lock so statuses can be computed while holding the service read lock.
+type rateTracker struct {
+	mu      sync.Mutex
+	samples map[string]*rateSample
+}
+
+func newRateTracker() *rateTracker {
+	return &rateTracker{samples: make(map[string]*rateSample)}
+}
+
+// observe records the cumulative counters for a torrent and returns the
+// download and upload rates in bytes per second.
+func (r *rateTracker) observe(hash string, read, written int64, now time.Time) (down, up int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.samples[hash]
+	if !ok {
+		r.samples[hash] = &rateSample{read: read, written: written, at: now}
+		return 0, 0
+	}
+
+	elapsed := now.Sub(prev.at)
+	if elapsed < minRateInterval {
+		return prev.down, prev.up
+	}
+
+	seconds := elapsed.Seconds()
+	prev.down = max(0, int64(float64(read-prev.read)/seconds))
+	prev.up = max(0, int64(float64(written-prev.written)/seconds))
+	prev.read, prev.written, prev.at = read, written, now
+	return prev.down, prev.up
+}
+
+// forget drops the samples for a removed torrent
+func (r *rateTracker) forget(hash string) {
+	r.mu.Lock()
+	delete(r.samples, hash)
+	r.mu.Unlock()
+}
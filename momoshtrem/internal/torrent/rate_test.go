@@ -0,0 +1,37 @@
+package torrent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateTracker(t *testing.T) {
+	r := newRateTracker()
+	start := time.Unix(1_700_000_000, 0)
+
+	if down, up := r.observe("abc", 1000, 500, start); down != 0 || up != 0 {
+		t.Fatalf("first observation = %d, %d; want 0, 0", down, up)
+	}
+
+	down, up := r.observe("abc", 3000, 1500, start.Add(2*time.Second))
+	if down != 1000 || up != 500 {
+		t.Errorf("rate after 2s = %d, %d; want 1000, 500", down, up)
+	}
+
+	// Too soon to recompute: the previous rate is reused
+	down, up = r.observe("abc", 9000, 1500, start.Add(2500*time.Millisecond))
+	if down != 1000 || up != 500 {
+		t.Errorf("rate within min interval = %d, %d; want 1000, 500", down, up)
+	}
+
+	// Counters going backwards (e.g. torrent re-added) never yield negative rates
+	down, up = r.observe("abc", 0, 0, start.Add(4*time.Second))
+	if down != 0 || up != 0 {
+		t.Errorf("rate after counter reset = %d, %d; want 0, 0", down, up)
+	}
+
+	r.forget("abc")
+	if down, _ := r.observe("abc", 5000, 0, start.Add(5*time.Second)); down != 0 {
+		t.Errorf("rate after forget = %d; want 0", down)
+	}
+}
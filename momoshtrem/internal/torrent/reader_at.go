@@ -0,0 +1,41 @@
+package torrent
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// FileReaderAt provides random access to a torrent file for small reads such
+// as hashing, prioritizing the pieces at each read position.
+type FileReaderAt struct {
+	mu sync.Mutex
+	r  TorrentReader
+}
+
+// NewFileReaderAt opens a responsive reader on a torrent file. Close it when done.
+func NewFileReaderAt(f TorrentFileHandle) *FileReaderAt {
+	r := f.NewReader()
+	r.SetResponsive()
+	return &FileReaderAt{r: r}
+}
+
+// ReadAt seeks and reads, blocking until the pieces are downloaded
+func (f *FileReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(f.r, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Close releases the reader, unblocking any pending read
+func (f *FileReaderAt) Close() error {
+	return f.r.Close()
+}
@@ -1,10 +1,13 @@
 package torrent
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/metainfo"
 
 	"github.com/shapedtime/momoshtrem/internal/identify"
 )
@@ -13,11 +16,37 @@ import (
 var (
 	ErrTorrentNotFound = errors.New("torrent not found")
 	ErrMetadataTimeout = errors.New("timeout waiting for torrent metadata")
+	ErrMetadataPending = errors.New("torrent metadata is still resolving in the background")
 	ErrInvalidMagnet   = errors.New("invalid magnet URI")
 	ErrNoFiles         = errors.New("torrent contains no files")
 	ErrFileNotFound    = errors.New("file not found in torrent")
+	ErrClientClosed    = errors.New("torrent client is closed")
 )
 
+// InsufficientSeedersError is returned by WaitForSeeders when too few seeders
+// connected within the wait window
+type InsufficientSeedersError struct {
+	Seeders    int // Connected seeders when the wait ended
+	MinSeeders int
+}
+
+func (e *InsufficientSeedersError) Error() string {
+	return fmt.Sprintf("torrent has %d connected seeders, need at least %d", e.Seeders, e.MinSeeders)
+}
+
+// MetadataRetry configures how AddTorrent copes with metadata that is slow to arrive
+type MetadataRetry struct {
+	Retries int           // Further AddMagnet+GotInfo attempts after the first timeout
+	Backoff time.Duration // Pause between attempts
+
+	// Background keeps the torrent resolving after the last attempt times out,
+	// so a later add of the same hash succeeds as soon as metadata arrives
+	Background bool
+}
+
+// backgroundResolveLimit bounds how long a torrent keeps resolving in the background
+const backgroundResolveLimit = 30 * time.Minute
+
 // TorrentInfo contains information about an added torrent
 type TorrentInfo struct {
 	InfoHash  string
@@ -39,6 +68,33 @@ type TorrentStatus struct {
 	DownloadSpeed int64   // bytes per second
 	UploadSpeed   int64   // bytes per second
 	IsPaused      bool
+
+	// Resolving is set until metadata arrives; Name is then the magnet's display name
+	Resolving bool
+}
+
+// FileProgress reports how much of one file within a torrent is downloaded
+type FileProgress struct {
+	Path           string  `json:"path"`
+	Length         int64   `json:"length"`
+	BytesCompleted int64   `json:"bytes_completed"`
+	Progress       float64 `json:"progress"` // 0.0 to 1.0
+	Complete       bool    `json:"complete"`
+	FirstPiece     int     `json:"first_piece"`
+	EndPiece       int     `json:"end_piece"` // Exclusive
+}
+
+// PieceMap is a snapshot of which pieces of a torrent are complete
+type PieceMap struct {
+	PieceLength int64 `json:"piece_length"`
+	NumPieces   int   `json:"num_pieces"`
+	Completed   int   `json:"completed"`
+
+	// Bitfield has one bit per piece, set once the piece is downloaded and
+	// verified. Piece i is bit 7-i%8 of byte i/8 (high bit first, as in the
+	// BitTorrent bitfield message) and spare bits of the last byte are zero.
+	// Base64 (standard alphabet) in JSON.
+	Bitfield []byte `json:"bitfield"`
 }
 
 // FullStats contains complete torrent statistics for Prometheus metrics collection.
@@ -66,16 +122,25 @@ type Service interface {
 	// and returns information about the torrent including its files.
 	// This operation may take some time as it needs to connect to peers
 	// and download the torrent metadata.
-	// Returns ErrMetadataTimeout if metadata cannot be retrieved in time.
+	// Returns ErrMetadataTimeout if metadata cannot be retrieved in time,
+	// or ErrMetadataPending if it is still being resolved in the background.
 	// Returns ErrInvalidMagnet if the magnet URI is invalid.
-	AddTorrent(magnetURI string) (*TorrentInfo, error)
+	// Canceling ctx returns its error; the torrent is dropped unless
+	// another caller is still waiting for the same one.
+	AddTorrent(ctx context.Context, magnetURI string) (*TorrentInfo, error)
+
+	// WaitForSeeders waits briefly for a loaded torrent to connect to at least
+	// minSeeders seeders, returning as soon as it has. Returns
+	// *InsufficientSeedersError if it still has fewer when the wait ends.
+	// Torrents already fully downloaded and minSeeders <= 0 always pass.
+	WaitForSeeders(ctx context.Context, infoHash string, minSeeders int) error
 
 	// GetTorrent returns information about an already-added torrent.
 	// Returns ErrTorrentNotFound if the torrent is not loaded.
 	GetTorrent(infoHash string) (*TorrentInfo, error)
 
 	// GetOrAddTorrent returns an existing torrent or adds it if not present.
-	GetOrAddTorrent(magnetURI string) (*TorrentInfo, error)
+	GetOrAddTorrent(ctx context.Context, magnetURI string) (*TorrentInfo, error)
 
 	// GetFile returns a file handle for streaming a specific file from a torrent.
 	// Returns ErrTorrentNotFound if the torrent is not loaded.
@@ -86,22 +151,47 @@ type Service interface {
 	// If deleteData is true, also deletes downloaded data.
 	RemoveTorrent(infoHash string, deleteData bool) error
 
+	// PruneMetainfo deletes the stored metadata of torrents that are neither
+	// in keep nor loaded, returning how many were deleted.
+	PruneMetainfo(keep []string) (int, error)
+
 	// ListTorrents returns status of all active torrents.
 	ListTorrents() ([]TorrentStatus, error)
 
 	// GetStatus returns detailed status of a specific torrent.
 	GetStatus(infoHash string) (*TorrentStatus, error)
 
+	// GetFileProgress returns download progress for each file in a torrent.
+	// Returns ErrTorrentNotFound if the torrent is not loaded.
+	GetFileProgress(infoHash string) ([]FileProgress, error)
+
+	// GetPieceMap returns which pieces of a torrent are complete.
+	// Returns ErrTorrentNotFound if the torrent is not loaded.
+	GetPieceMap(infoHash string) (*PieceMap, error)
+
 	// Pause pauses downloading/uploading for a torrent.
 	Pause(infoHash string) error
 
 	// Resume resumes a paused torrent.
 	Resume(infoHash string) error
 
+	// AddTrackers announces a loaded or still-resolving torrent to extra trackers.
+	// Returns ErrTorrentNotFound if the torrent is neither.
+	AddTrackers(infoHash string, trackers []string) error
+
+	// SetMaxEstablishedConns overrides torrent.max_established_conns for a
+	// loaded or still-resolving torrent until it is dropped. Returns the
+	// previous limit, or ErrTorrentNotFound if the torrent is neither.
+	SetMaxEstablishedConns(infoHash string, maxConns int) (int, error)
+
 	// CollectStats returns complete statistics for all active torrents.
 	// Used by the Prometheus metrics collector.
 	CollectStats() []FullStats
 
+	// Healthy reports whether the underlying torrent client is still running.
+	// Returns ErrClientClosed once the client has shut down.
+	Healthy() error
+
 	// Close shuts down the torrent service.
 	Close() error
 }
@@ -164,3 +254,13 @@ func ExtractInfoHash(magnetURI string) string {
 
 	return string(result)
 }
+
+// MagnetDisplayName returns the display name (dn) of a magnet URI, or "" when
+// it has none or the URI doesn't parse
+func MagnetDisplayName(magnetURI string) string {
+	spec, err := metainfo.ParseMagnetUri(magnetURI)
+	if err != nil {
+		return ""
+	}
+	return spec.DisplayName
+}
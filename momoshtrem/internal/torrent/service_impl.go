@@ -1,16 +1,25 @@
 package torrent
 
 import (
+	"context"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/anacrolix/torrent"
 	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/shapedtime/momoshtrem/internal/identify"
 )
 
+// defaultSeederWait bounds WaitForSeeders; healthy swarms connect seeders well within it
+const defaultSeederWait = 10 * time.Second
+
+// seederPollInterval is how often WaitForSeeders checks the connected seeders
+const seederPollInterval = 250 * time.Millisecond
+
 // Ensure service implements Service interface
 var _ Service = (*service)(nil)
 
@@ -23,31 +32,73 @@ type service struct {
 	// Loaded torrents by info hash (lowercase hex)
 	torrents map[string]*torrent.Torrent
 
+	// Torrents still waiting for metadata in the background, by info hash
+	resolving map[string]*torrent.Torrent
+
+	// Concurrent adds of the same info hash share one add and metadata wait,
+	// which is canceled once every caller waiting on it has given up
+	adds     singleflight.Group
+	addsMu   sync.Mutex
+	addWaits map[string]*addWait
+
+	// client.AddMagnet; replaced in tests
+	addMagnet func(uri string) (*torrent.Torrent, error)
+
+	// Download/upload rates derived from cumulative peer byte counters
+	rates *rateTracker
+
 	// Configuration
 	addTimeout  time.Duration
 	readTimeout time.Duration
+	retry       MetadataRetry
+	seederWait  time.Duration // How long WaitForSeeders waits
+
+	// Extra trackers announced to for every added torrent
+	defaultTrackers []string
+
+	// Metadata of loaded torrents, for adding them again after a restart (nil = disabled)
+	metainfo *metainfoStore
 
 	log *slog.Logger
 }
 
-// NewService creates a new torrent service.
+// NewService creates a new torrent service. metainfoDir (optional) is where
+// the metadata of loaded torrents is kept across restarts.
 func NewService(
 	client *torrent.Client,
 	am *ActivityManager,
 	addTimeout, readTimeout time.Duration,
+	retry MetadataRetry,
+	defaultTrackers []string,
+	metainfoDir string,
 ) Service {
+	log := slog.With("component", "torrent-service")
+	store, err := newMetainfoStore(metainfoDir)
+	if err != nil {
+		log.Warn("torrent metadata will not be kept across restarts", "dir", metainfoDir, "error", err)
+	}
+
 	return &service{
 		client:      client,
+		addMagnet:   client.AddMagnet,
 		am:          am,
 		torrents:    make(map[string]*torrent.Torrent),
+		resolving:   make(map[string]*torrent.Torrent),
+		addWaits:    make(map[string]*addWait),
+		rates:       newRateTracker(),
 		addTimeout:  addTimeout,
 		readTimeout: readTimeout,
-		log:         slog.With("component", "torrent-service"),
+		retry:       retry,
+		seederWait:  defaultSeederWait,
+		metainfo:    store,
+		log:         log,
+
+		defaultTrackers: cleanTrackers(defaultTrackers),
 	}
 }
 
 // AddTorrent adds a torrent by magnet URI and waits for metadata.
-func (s *service) AddTorrent(magnetURI string) (*TorrentInfo, error) {
+func (s *service) AddTorrent(ctx context.Context, magnetURI string) (*TorrentInfo, error) {
 	// Parse magnet URI
 	spec, err := metainfo.ParseMagnetUri(magnetURI)
 	if err != nil {
@@ -64,43 +115,261 @@ func (s *service) AddTorrent(magnetURI string) (*TorrentInfo, error) {
 
 	if exists {
 		s.log.Debug("torrent already loaded", "hash", hash)
+		s.addMagnetTrackers(existing, spec.Trackers)
 		return s.torrentToInfo(existing), nil
 	}
 
-	// Add to client
-	t, err := s.client.AddMagnet(magnetURI)
-	if err != nil {
-		s.log.Error("failed to add magnet", "hash", hash, "error", err)
-		return nil, err
+	wait, results := s.joinAdd(hash, magnetURI, spec)
+	var res singleflight.Result
+	select {
+	case res = <-results:
+	case <-ctx.Done():
+		s.leaveAdd(wait)
+		return nil, ctx.Err()
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	if res.Shared {
+		// Another caller's magnet did the add; keep this one's trackers too
+		s.mu.RLock()
+		t, ok := s.torrents[hash]
+		s.mu.RUnlock()
+		if ok {
+			s.addMagnetTrackers(t, spec.Trackers)
+		}
 	}
+	return res.Val.(*TorrentInfo), nil
+}
+
+// addWait is an add in flight and the number of callers waiting on it
+type addWait struct {
+	hash    string
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
 
-	s.log.Info("waiting for torrent metadata", "hash", hash)
+// joinAdd starts adding a torrent, or joins the add already in flight for its
+// info hash. The caller must receive from the channel or call leaveAdd.
+func (s *service) joinAdd(hash, magnetURI string, spec metainfo.Magnet) (*addWait, <-chan singleflight.Result) {
+	s.addsMu.Lock()
+	defer s.addsMu.Unlock()
+
+	wait, ok := s.addWaits[hash]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		wait = &addWait{hash: hash, ctx: ctx, cancel: cancel}
+		s.addWaits[hash] = wait
+	}
+	wait.waiters++
+
+	return wait, s.adds.DoChan(hash, func() (interface{}, error) {
+		defer func() {
+			// Forget under addsMu so a later joinAdd starts a new add with a fresh context
+			s.addsMu.Lock()
+			s.forgetAdd(wait)
+			s.addsMu.Unlock()
+			wait.cancel()
+		}()
+		return s.addMagnetAndWait(wait.ctx, hash, magnetURI, spec)
+	})
+}
+
+// leaveAdd gives up waiting on an add, canceling it if no one else waits
+func (s *service) leaveAdd(wait *addWait) {
+	s.addsMu.Lock()
+	defer s.addsMu.Unlock()
+
+	wait.waiters--
+	if wait.waiters == 0 {
+		// Callers arriving before the canceled add returns start a new one
+		// instead of joining it and getting its context.Canceled
+		s.forgetAdd(wait)
+		wait.cancel()
+	}
+}
+
+// forgetAdd removes an add from addWaits and the flight group, unless a newer
+// add for the same hash has replaced it. Caller must hold addsMu.
+func (s *service) forgetAdd(wait *addWait) {
+	if s.addWaits[wait.hash] != wait {
+		return
+	}
+	delete(s.addWaits, wait.hash)
+	s.adds.Forget(wait.hash)
+}
+
+// addMagnetAndWait adds a torrent that isn't loaded yet and waits for its
+// metadata. AddTorrent runs at most one of these per info hash at a time.
+// Canceling ctx drops the torrent unless it is resolving in the background.
+func (s *service) addMagnetAndWait(ctx context.Context, hash, magnetURI string, spec metainfo.Magnet) (*TorrentInfo, error) {
+	// A background resolve already has peers; give it one more wait
+	s.mu.RLock()
+	pending, isResolving := s.resolving[hash]
+	s.mu.RUnlock()
+	if isResolving {
+		// This magnet may list trackers the first one didn't
+		s.addMagnetTrackers(pending, spec.Trackers)
+		select {
+		case <-pending.GotInfo():
+			return s.loaded(hash, pending), nil
+		case <-time.After(s.addTimeout):
+			return nil, ErrMetadataPending
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Metadata saved before a restart: no need to wait for peers
+	if t := s.addFromMetainfo(hash, spec.Trackers); t != nil {
+		return s.loaded(hash, t), nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Add to client; the magnet's tr trackers and dn display name come along
+		t, err := s.addMagnet(magnetURI)
+		if err != nil {
+			s.log.Error("failed to add magnet", "hash", hash, "error", err)
+			return nil, err
+		}
+		if len(s.defaultTrackers) > 0 {
+			t.AddTrackers([][]string{s.defaultTrackers})
+		}
+
+		s.log.Info("waiting for torrent metadata",
+			"hash", hash,
+			"name", spec.DisplayName,
+			"trackers", len(spec.Trackers),
+			"attempt", attempt+1,
+		)
+
+		// Wait for metadata with strict timeout
+		select {
+		case <-t.GotInfo():
+			s.log.Info("obtained torrent metadata",
+				"hash", hash,
+				"name", t.Info().Name,
+				"files", len(t.Files()),
+			)
+			return s.loaded(hash, t), nil
+		case <-time.After(s.addTimeout):
+		case <-ctx.Done():
+			s.log.Info("torrent add canceled while waiting for metadata", "hash", hash)
+			t.Drop()
+			return nil, ctx.Err()
+		}
+
+		if attempt < s.retry.Retries {
+			// Drop and re-add so the next attempt re-announces to trackers and the DHT
+			s.log.Warn("timeout waiting for torrent metadata, retrying",
+				"hash", hash,
+				"attempt", attempt+1,
+				"backoff", s.retry.Backoff,
+			)
+			t.Drop()
+			select {
+			case <-time.After(s.retry.Backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		if s.retry.Background {
+			s.log.Warn("timeout waiting for torrent metadata, resolving in background", "hash", hash)
+			s.resolveInBackground(hash, t)
+			return nil, ErrMetadataPending
+		}
 
-	// Wait for metadata with strict timeout
-	select {
-	case <-time.After(s.addTimeout):
 		s.log.Warn("timeout waiting for torrent metadata", "hash", hash)
 		t.Drop() // Clean up failed torrent
 		return nil, ErrMetadataTimeout
-	case <-t.GotInfo():
-		s.log.Info("obtained torrent metadata",
-			"hash", hash,
-			"name", t.Info().Name,
-			"files", len(t.Files()),
-		)
 	}
+}
+
+// loaded registers a torrent whose metadata has arrived
+func (s *service) loaded(hash string, t *torrent.Torrent) *TorrentInfo {
+	s.mu.Lock()
+	delete(s.resolving, hash)
+	if existing, ok := s.torrents[hash]; ok {
+		s.mu.Unlock()
+		return s.torrentToInfo(existing) // Registered by a concurrent add
+	}
+	s.torrents[hash] = t
+	s.mu.Unlock()
 
 	// Register with activity manager for idle tracking
 	if s.am != nil {
 		s.am.Register(hash, t)
 	}
 
-	// Store in map
+	if s.metainfo != nil && !s.metainfo.has(hash) {
+		if err := s.metainfo.save(hash, t.Metainfo()); err != nil {
+			s.log.Warn("failed to save torrent metadata", "hash", hash, "error", err)
+		}
+	}
+
+	return s.torrentToInfo(t)
+}
+
+// addFromMetainfo adds a torrent from its stored metadata, returning nil when
+// none is stored or it can't be used
+func (s *service) addFromMetainfo(hash string, magnetTrackers []string) *torrent.Torrent {
+	if s.metainfo == nil {
+		return nil
+	}
+	mi, err := s.metainfo.load(hash)
+	if err != nil {
+		s.log.Warn("failed to load stored torrent metadata", "hash", hash, "error", err)
+		return nil
+	}
+	if mi == nil {
+		return nil
+	}
+
+	t, err := s.client.AddTorrent(mi)
+	if err != nil {
+		s.log.Warn("failed to add torrent from stored metadata", "hash", hash, "error", err)
+		return nil
+	}
+	s.addMagnetTrackers(t, magnetTrackers)
+	if len(s.defaultTrackers) > 0 {
+		t.AddTrackers([][]string{s.defaultTrackers})
+	}
+
+	s.log.Info("added torrent from stored metadata", "hash", hash, "name", t.Info().Name)
+	return t
+}
+
+// resolveInBackground keeps waiting for metadata after AddTorrent gave up,
+// dropping the torrent if none arrives within backgroundResolveLimit.
+func (s *service) resolveInBackground(hash string, t *torrent.Torrent) {
 	s.mu.Lock()
-	s.torrents[hash] = t
+	if _, ok := s.resolving[hash]; ok {
+		s.mu.Unlock()
+		return
+	}
+	s.resolving[hash] = t
 	s.mu.Unlock()
 
-	return s.torrentToInfo(t), nil
+	go func() {
+		select {
+		case <-t.GotInfo():
+			s.log.Info("obtained torrent metadata in background", "hash", hash, "name", t.Info().Name)
+			s.loaded(hash, t)
+		case <-time.After(backgroundResolveLimit):
+			s.log.Warn("giving up on background metadata resolve", "hash", hash, "after", backgroundResolveLimit)
+			s.mu.Lock()
+			delete(s.resolving, hash)
+			s.mu.Unlock()
+			t.Drop()
+		case <-t.Closed():
+			s.mu.Lock()
+			delete(s.resolving, hash)
+			s.mu.Unlock()
+		}
+	}()
 }
 
 // GetTorrent returns information about an already-added torrent.
@@ -117,7 +386,7 @@ func (s *service) GetTorrent(infoHash string) (*TorrentInfo, error) {
 }
 
 // GetOrAddTorrent returns an existing torrent or adds it if not present.
-func (s *service) GetOrAddTorrent(magnetURI string) (*TorrentInfo, error) {
+func (s *service) GetOrAddTorrent(ctx context.Context, magnetURI string) (*TorrentInfo, error) {
 	hash := ExtractInfoHash(magnetURI)
 	if hash == "" {
 		return nil, ErrInvalidMagnet
@@ -133,7 +402,7 @@ func (s *service) GetOrAddTorrent(magnetURI string) (*TorrentInfo, error) {
 	}
 
 	// Add it
-	return s.AddTorrent(magnetURI)
+	return s.AddTorrent(ctx, magnetURI)
 }
 
 // GetFile returns a file handle for streaming a specific file from a torrent.
@@ -166,6 +435,7 @@ func (s *service) RemoveTorrent(infoHash string, deleteData bool) error {
 	}
 	delete(s.torrents, infoHash)
 	s.mu.Unlock()
+	s.rates.forget(infoHash)
 
 	// Unregister from activity manager
 	if s.am != nil {
@@ -177,19 +447,65 @@ func (s *service) RemoveTorrent(infoHash string, deleteData bool) error {
 
 	s.log.Info("removed torrent", "hash", infoHash, "delete_data", deleteData)
 
-	// Note: deleteData is currently ignored as we use a shared cache.
-	// The filecache handles cleanup based on capacity limits.
+	// Piece data stays in the shared cache, which handles cleanup based on
+	// capacity limits; deleteData only forgets the stored metadata
+	if deleteData && s.metainfo != nil {
+		if err := s.metainfo.remove(infoHash); err != nil {
+			s.log.Warn("failed to remove stored torrent metadata", "hash", infoHash, "error", err)
+		}
+	}
 
 	return nil
 }
 
+// PruneMetainfo deletes stored metadata of torrents that are neither kept nor loaded.
+func (s *service) PruneMetainfo(keep []string) (int, error) {
+	if s.metainfo == nil {
+		return 0, nil
+	}
+	stored, err := s.metainfo.hashes()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, hash := range keep {
+		kept[hash] = true
+	}
+	s.mu.RLock()
+	for hash := range s.torrents {
+		kept[hash] = true
+	}
+	s.mu.RUnlock()
+
+	removed := 0
+	for _, hash := range stored {
+		if kept[hash] {
+			continue
+		}
+		if err := s.metainfo.remove(hash); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
 // ListTorrents returns status of all active torrents.
 func (s *service) ListTorrents() ([]TorrentStatus, error) {
+	// Copy the torrents so stats are gathered without holding the lock
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	result := make([]TorrentStatus, 0, len(s.torrents))
+	torrents := make([]*torrent.Torrent, 0, len(s.torrents)+len(s.resolving))
 	for _, t := range s.torrents {
+		torrents = append(torrents, t)
+	}
+	for _, t := range s.resolving {
+		torrents = append(torrents, t)
+	}
+	s.mu.RUnlock()
+
+	result := make([]TorrentStatus, 0, len(torrents))
+	for _, t := range torrents {
 		result = append(result, s.torrentToStatus(t))
 	}
 
@@ -200,6 +516,9 @@ func (s *service) ListTorrents() ([]TorrentStatus, error) {
 func (s *service) GetStatus(infoHash string) (*TorrentStatus, error) {
 	s.mu.RLock()
 	t, exists := s.torrents[infoHash]
+	if !exists {
+		t, exists = s.resolving[infoHash]
+	}
 	s.mu.RUnlock()
 
 	if !exists {
@@ -210,6 +529,113 @@ func (s *service) GetStatus(infoHash string) (*TorrentStatus, error) {
 	return &status, nil
 }
 
+// GetFileProgress returns download progress for each file in a torrent.
+// Completed bytes come from the completed pieces within each file's piece range.
+func (s *service) GetFileProgress(infoHash string) ([]FileProgress, error) {
+	s.mu.RLock()
+	t, exists := s.torrents[infoHash]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrTorrentNotFound
+	}
+
+	files := t.Files()
+	result := make([]FileProgress, 0, len(files))
+	for _, f := range files {
+		fp := FileProgress{
+			Path:           f.Path(),
+			Length:         f.Length(),
+			BytesCompleted: f.BytesCompleted(),
+			FirstPiece:     f.BeginPieceIndex(),
+			EndPiece:       f.EndPieceIndex(),
+		}
+		if fp.Length > 0 {
+			fp.Progress = float64(fp.BytesCompleted) / float64(fp.Length)
+		}
+		fp.Complete = fp.BytesCompleted >= fp.Length
+		result = append(result, fp)
+	}
+	return result, nil
+}
+
+// WaitForSeeders waits up to seederWait for minSeeders connected seeders.
+func (s *service) WaitForSeeders(ctx context.Context, infoHash string, minSeeders int) error {
+	if minSeeders <= 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	t, exists := s.torrents[infoHash]
+	s.mu.RUnlock()
+
+	if !exists {
+		return ErrTorrentNotFound
+	}
+	if t.BytesMissing() == 0 {
+		return nil // Nothing left to download
+	}
+
+	timeout := time.NewTimer(s.seederWait)
+	defer timeout.Stop()
+	ticker := time.NewTicker(seederPollInterval)
+	defer ticker.Stop()
+
+	for {
+		seeders := t.Stats().ConnectedSeeders
+		if seeders >= minSeeders {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			s.log.Warn("too few seeders", "hash", infoHash, "seeders", seeders, "min_seeders", minSeeders)
+			return &InsufficientSeedersError{Seeders: seeders, MinSeeders: minSeeders}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GetPieceMap returns which pieces of a torrent are complete.
+func (s *service) GetPieceMap(infoHash string) (*PieceMap, error) {
+	s.mu.RLock()
+	t, exists := s.torrents[infoHash]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrTorrentNotFound
+	}
+
+	// Runs take the client lock once instead of once per piece
+	return pieceMapFromRuns(t.Info().PieceLength, t.PieceStateRuns()), nil
+}
+
+// pieceMapFromRuns packs runs of piece states into a bitfield
+func pieceMapFromRuns(pieceLength int64, runs torrent.PieceStateRuns) *PieceMap {
+	numPieces := 0
+	for _, run := range runs {
+		numPieces += run.Length
+	}
+
+	m := &PieceMap{
+		PieceLength: pieceLength,
+		NumPieces:   numPieces,
+		Bitfield:    make([]byte, (numPieces+7)/8),
+	}
+	piece := 0
+	for _, run := range runs {
+		if run.Complete {
+			for i := piece; i < piece+run.Length; i++ {
+				m.Bitfield[i/8] |= 0x80 >> (i % 8)
+			}
+			m.Completed += run.Length
+		}
+		piece += run.Length
+	}
+	return m
+}
+
 // Pause pauses downloading/uploading for a torrent.
 func (s *service) Pause(infoHash string) error {
 	s.mu.RLock()
@@ -244,6 +670,95 @@ func (s *service) Resume(infoHash string) error {
 	return nil
 }
 
+// AddTrackers announces a loaded or still-resolving torrent to extra trackers.
+func (s *service) AddTrackers(infoHash string, trackers []string) error {
+	s.mu.RLock()
+	t, exists := s.torrents[infoHash]
+	if !exists {
+		t, exists = s.resolving[infoHash]
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return ErrTorrentNotFound
+	}
+
+	trackers = cleanTrackers(trackers)
+	if len(trackers) == 0 {
+		return nil
+	}
+	t.AddTrackers([][]string{trackers})
+
+	s.log.Info("added trackers", "hash", infoHash, "trackers", len(trackers))
+	return nil
+}
+
+// SetMaxEstablishedConns overrides the peer connection limit of a loaded or
+// still-resolving torrent. Dropping connections over a lowered limit starts
+// with the worst peers.
+func (s *service) SetMaxEstablishedConns(infoHash string, maxConns int) (int, error) {
+	s.mu.RLock()
+	t, exists := s.torrents[infoHash]
+	if !exists {
+		t, exists = s.resolving[infoHash]
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return 0, ErrTorrentNotFound
+	}
+
+	previous := t.SetMaxEstablishedConns(maxConns)
+
+	s.log.Info("set peer connection limit", "hash", infoHash, "max_established_conns", maxConns, "previous", previous)
+	return previous, nil
+}
+
+// addMagnetTrackers announces a known torrent to trackers from a magnet added again
+func (s *service) addMagnetTrackers(t *torrent.Torrent, trackers []string) {
+	if trackers = cleanTrackers(trackers); len(trackers) > 0 {
+		t.AddTrackers([][]string{trackers})
+	}
+}
+
+// torrentName returns the name from the torrent's metadata or, until that
+// arrives, the display name (dn) of the magnet it was added with
+func torrentName(t *torrent.Torrent) string {
+	if info := t.Info(); info != nil {
+		return info.Name
+	}
+	// Without a display name anacrolix falls back to "infohash:<hash>"
+	if name := t.Name(); !strings.HasPrefix(name, "infohash:") {
+		return name
+	}
+	return ""
+}
+
+// cleanTrackers trims tracker URLs and drops blanks and duplicates
+func cleanTrackers(trackers []string) []string {
+	seen := make(map[string]bool, len(trackers))
+	cleaned := make([]string, 0, len(trackers))
+	for _, tr := range trackers {
+		tr = strings.TrimSpace(tr)
+		if tr == "" || seen[tr] {
+			continue
+		}
+		seen[tr] = true
+		cleaned = append(cleaned, tr)
+	}
+	return cleaned
+}
+
+// Healthy reports whether the torrent client is still running.
+func (s *service) Healthy() error {
+	select {
+	case <-s.client.Closed():
+		return ErrClientClosed
+	default:
+		return nil
+	}
+}
+
 // Close shuts down the torrent service.
 func (s *service) Close() error {
 	s.mu.Lock()
@@ -256,8 +771,9 @@ func (s *service) Close() error {
 		}
 	}
 
-	// Clear map (client shutdown is handled separately)
+	// Clear maps (client shutdown is handled separately)
 	s.torrents = make(map[string]*torrent.Torrent)
+	s.resolving = make(map[string]*torrent.Torrent)
 
 	s.log.Info("torrent service closed")
 	return nil
@@ -273,15 +789,13 @@ func (s *service) CollectStats() []FullStats {
 		stats := t.Stats()
 
 		var totalSize int64
-		var name string
 		if info := t.Info(); info != nil {
 			totalSize = info.TotalLength()
-			name = info.Name
 		}
 
 		result = append(result, FullStats{
 			InfoHash:          t.InfoHash().HexString(),
-			Name:              name,
+			Name:              torrentName(t),
 			TotalSize:         totalSize,
 			BytesCompleted:    t.BytesCompleted(),
 			ActivePeers:       stats.ActivePeers,
@@ -304,6 +818,7 @@ func (s *service) torrentToInfo(t *torrent.Torrent) *TorrentInfo {
 	if info == nil {
 		return &TorrentInfo{
 			InfoHash: t.InfoHash().HexString(),
+			Name:     torrentName(t), // Placeholder until metadata arrives
 		}
 	}
 
@@ -330,10 +845,9 @@ func (s *service) torrentToStatus(t *torrent.Torrent) TorrentStatus {
 	stats := t.Stats()
 
 	var totalSize int64
-	var name string
-	if info := t.Info(); info != nil {
+	info := t.Info()
+	if info != nil {
 		totalSize = info.TotalLength()
-		name = info.Name
 	}
 
 	// Calculate download progress
@@ -348,17 +862,20 @@ func (s *service) torrentToStatus(t *torrent.Torrent) TorrentStatus {
 		isPaused = s.am.IsPaused(hash)
 	}
 
+	downRate, upRate := s.rates.observe(hash, stats.BytesReadData.Int64(), stats.BytesWrittenData.Int64(), time.Now())
+
 	return TorrentStatus{
 		InfoHash:      hash,
-		Name:          name,
+		Name:          torrentName(t),
 		TotalSize:     totalSize,
 		Downloaded:    t.BytesCompleted(),
 		Progress:      progress,
 		Seeders:       stats.ConnectedSeeders,
 		Leechers:      stats.ActivePeers - stats.ConnectedSeeders,
-		DownloadSpeed: 0, // Would need rate tracking
-		UploadSpeed:   0, // Would need rate tracking
+		DownloadSpeed: downRate,
+		UploadSpeed:   upRate,
 		IsPaused:      isPaused,
+		Resolving:     info == nil,
 	}
 }
 
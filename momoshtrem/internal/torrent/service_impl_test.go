@@ -0,0 +1,245 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"github.com/anacrolix/torrent/storage"
+)
+
+// newOfflineClient returns a client that finds no peers, so magnets never get metadata
+func newOfflineClient(t *testing.T) *torrent.Client {
+	t.Helper()
+	cfg := torrent.NewDefaultClientConfig()
+	cfg.DataDir = t.TempDir()
+	cfg.NoDHT = true
+	cfg.DisableTrackers = true
+	cfg.NoDefaultPortForwarding = true
+	cfg.ListenPort = 0
+	client, err := torrent.NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestAddTorrentConcurrentSameHash(t *testing.T) {
+	client := newOfflineClient(t)
+
+	info := metainfo.Info{
+		Name:        "Movie.2020.1080p.mkv",
+		Length:      1 << 20,
+		PieceLength: 1 << 18,
+		Pieces:      make([]byte, 20*4),
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := &metainfo.MetaInfo{InfoBytes: infoBytes}
+	magnet := mi.Magnet(nil, &info).String()
+
+	// Stand in for the magnet add: metadata is available at once, but the add
+	// is slow enough for every caller to miss the loaded check
+	var adds atomic.Int32
+	s := &service{
+		client:    client,
+		torrents:  make(map[string]*torrent.Torrent),
+		resolving: make(map[string]*torrent.Torrent),
+		addWaits:  make(map[string]*addWait),
+		addMagnet: func(string) (*torrent.Torrent, error) {
+			adds.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return client.AddTorrent(mi)
+		},
+		addTimeout: 5 * time.Second,
+		log:        slog.Default(),
+	}
+
+	const callers = 8
+	infos := make([]*TorrentInfo, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			infos[i], errs[i] = s.AddTorrent(context.Background(), magnet)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := adds.Load(); n != 1 {
+		t.Errorf("underlying adds = %d, want 1", n)
+	}
+	hash := mi.HashInfoBytes().HexString()
+	for i := range infos {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: %v", i, errs[i])
+		}
+		if infos[i].InfoHash != hash || infos[i].Name != info.Name || infos[i].TotalSize != info.Length {
+			t.Errorf("caller %d got %+v", i, infos[i])
+		}
+	}
+	if len(s.torrents) != 1 {
+		t.Errorf("loaded torrents = %d, want 1", len(s.torrents))
+	}
+}
+
+func TestAddTorrentCanceled(t *testing.T) {
+	client := newOfflineClient(t)
+	s := &service{
+		client:     client,
+		torrents:   make(map[string]*torrent.Torrent),
+		resolving:  make(map[string]*torrent.Torrent),
+		addWaits:   make(map[string]*addWait),
+		addMagnet:  client.AddMagnet,
+		addTimeout: time.Minute,
+		log:        slog.Default(),
+	}
+
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := s.AddTorrent(ctx, "magnet:?xt=urn:btih:"+hash)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AddTorrent() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("AddTorrent() returned after %s, want soon after cancel", elapsed)
+	}
+
+	// The abandoned add drops the torrent from the client and forgets the add
+	pending := func() bool {
+		s.addsMu.Lock()
+		defer s.addsMu.Unlock()
+		return len(client.Torrents()) > 0 || len(s.addWaits) > 0
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for pending() {
+		if time.Now().After(deadline) {
+			t.Fatal("torrent add still pending after cancel")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAddTorrentAfterAbandonedAdd(t *testing.T) {
+	client := newOfflineClient(t)
+	info := metainfo.Info{
+		Name:        "Movie.2020.1080p.mkv",
+		Length:      1 << 20,
+		PieceLength: 1 << 18,
+		Pieces:      make([]byte, 20*4),
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mi := &metainfo.MetaInfo{InfoBytes: infoBytes}
+	magnet := mi.Magnet(nil, &info).String()
+
+	// The first add hangs until unblocked, then fails; later adds succeed at once
+	unblock := make(chan struct{})
+	var adds atomic.Int32
+	s := &service{
+		client:    client,
+		torrents:  make(map[string]*torrent.Torrent),
+		resolving: make(map[string]*torrent.Torrent),
+		addWaits:  make(map[string]*addWait),
+		addMagnet: func(string) (*torrent.Torrent, error) {
+			if adds.Add(1) == 1 {
+				<-unblock
+				return nil, errors.New("abandoned add")
+			}
+			return client.AddTorrent(mi)
+		},
+		addTimeout: 5 * time.Second,
+		log:        slog.Default(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := s.AddTorrent(ctx, magnet); !errors.Is(err, context.Canceled) {
+		t.Fatalf("first AddTorrent() error = %v, want context.Canceled", err)
+	}
+
+	// The abandoned add is still running; a new caller must not join it
+	time.AfterFunc(100*time.Millisecond, func() { close(unblock) })
+	got, err := s.AddTorrent(context.Background(), magnet)
+	if err != nil {
+		t.Fatalf("AddTorrent() after an abandoned add = %v, want success", err)
+	}
+	if got.Name != info.Name {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestPieceMapFromRuns(t *testing.T) {
+	complete := torrent.PieceState{Completion: storage.Completion{Complete: true, Ok: true}}
+	runs := torrent.PieceStateRuns{
+		{PieceState: complete, Length: 2},             // Header
+		{PieceState: torrent.PieceState{}, Length: 7}, // Not downloaded
+		{PieceState: complete, Length: 1},             // Footer
+	}
+
+	m := pieceMapFromRuns(1<<18, runs)
+	if m.NumPieces != 10 || m.Completed != 3 || m.PieceLength != 1<<18 {
+		t.Errorf("got %d/%d pieces of %d bytes, want 3/10 of %d", m.Completed, m.NumPieces, m.PieceLength, 1<<18)
+	}
+	if want := []byte{0b11000000, 0b01000000}; !bytes.Equal(m.Bitfield, want) {
+		t.Errorf("Bitfield = %08b, want %08b", m.Bitfield, want)
+	}
+}
+
+func TestWaitForSeeders(t *testing.T) {
+	client := newOfflineClient(t)
+	info := metainfo.Info{
+		Name:        "Movie.2020.1080p.mkv",
+		Length:      1 << 20,
+		PieceLength: 1 << 18,
+		Pieces:      make([]byte, 20*4),
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tor, err := client.AddTorrent(&metainfo.MetaInfo{InfoBytes: infoBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := tor.InfoHash().HexString()
+
+	s := &service{
+		client:     client,
+		torrents:   map[string]*torrent.Torrent{hash: tor},
+		seederWait: 100 * time.Millisecond,
+		log:        slog.Default(),
+	}
+
+	if err := s.WaitForSeeders(context.Background(), hash, 0); err != nil {
+		t.Errorf("WaitForSeeders(0) = %v, want nil when disabled", err)
+	}
+
+	// No peers are reachable, so the wait runs out with none connected
+	err = s.WaitForSeeders(context.Background(), hash, 2)
+	var seedersErr *InsufficientSeedersError
+	if !errors.As(err, &seedersErr) {
+		t.Fatalf("WaitForSeeders(2) = %v, want InsufficientSeedersError", err)
+	}
+	if seedersErr.Seeders != 0 || seedersErr.MinSeeders != 2 {
+		t.Errorf("got %+v, want 0 of 2 seeders", seedersErr)
+	}
+}
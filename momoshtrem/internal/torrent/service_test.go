@@ -0,0 +1,21 @@
+package torrent
+
+import "testing"
+
+func TestMagnetDisplayName(t *testing.T) {
+	const hash = "c9e15763f722f23e98a29decdfae341b98d53056"
+	tests := []struct {
+		magnet string
+		want   string
+	}{
+		{"magnet:?xt=urn:btih:" + hash + "&dn=Show.S01E01.1080p.mkv&tr=udp%3A%2F%2Ftracker.example.org%3A1337", "Show.S01E01.1080p.mkv"},
+		{"magnet:?xt=urn:btih:" + hash + "&dn=Some+Movie+%282020%29", "Some Movie (2020)"},
+		{"magnet:?xt=urn:btih:" + hash, ""},
+		{"not a magnet", ""},
+	}
+	for _, tt := range tests {
+		if got := MagnetDisplayName(tt.magnet); got != tt.want {
+			t.Errorf("MagnetDisplayName(%q) = %q, want %q", tt.magnet, got, tt.want)
+		}
+	}
+}
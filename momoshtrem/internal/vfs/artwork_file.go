@@ -0,0 +1,345 @@
+package vfs
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// Ensure ArtworkFile implements File interface
+var _ File = (*ArtworkFile)(nil)
+
+const (
+	// Filenames Kodi and Jellyfin look for in a movie or show folder
+	posterFileName = "poster.jpg"
+	fanartFileName = "fanart.jpg"
+
+	// maxArtworkSize bounds a downloaded image (TMDB originals are a few MB at most)
+	maxArtworkSize = 20 * 1024 * 1024
+
+	artworkFetchTimeout = 30 * time.Second
+
+	// artworkCacheDir is the subdirectory of the VFS cache directory holding images
+	artworkCacheDir = "artwork"
+
+	// maxArtworkMemory bounds images kept in memory when there is no cache
+	// directory; the oldest are dropped and downloaded again when read
+	maxArtworkMemory = 64 * 1024 * 1024
+
+	// artworkPrefetchQueue bounds images waiting for a background download.
+	// Images that don't fit are downloaded when first read.
+	artworkPrefetchQueue = 1024
+)
+
+// artworkStore downloads TMDB images and keeps them on disk, or in memory when
+// no cache directory is configured. Tree entries queue a background download
+// as they are built, so listings can report sizes without fetching anything.
+type artworkStore struct {
+	client *http.Client
+	flight singleflight.Group // One download per URL, however many readers wait
+
+	mu       sync.Mutex
+	dir      string
+	mem      map[string][]byte
+	memOrder []string // mem keys, oldest first
+	memBytes int64
+
+	prefetchOnce sync.Once
+	prefetchCh   chan string
+}
+
+func newArtworkStore() *artworkStore {
+	return &artworkStore{
+		client:     &http.Client{Timeout: artworkFetchTimeout},
+		mem:        make(map[string][]byte),
+		prefetchCh: make(chan string, artworkPrefetchQueue),
+	}
+}
+
+// setDir configures the on-disk cache directory (empty keeps images in memory)
+func (s *artworkStore) setDir(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dir = dir
+}
+
+// artworkCachePath returns the disk location for an image URL, keeping its extension
+func artworkCachePath(dir, url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+path.Ext(url))
+}
+
+// get returns the image bytes, downloading them if they aren't cached.
+// Failures are not cached so a later read can retry.
+func (s *artworkStore) get(url string) ([]byte, error) {
+	s.mu.Lock()
+	dir := s.dir
+	data, ok := s.mem[url]
+	s.mu.Unlock()
+
+	if ok {
+		return data, nil
+	}
+	if dir != "" {
+		if data, err := os.ReadFile(artworkCachePath(dir, url)); err == nil {
+			return data, nil
+		}
+	}
+
+	v, err, _ := s.flight.Do(url, func() (any, error) {
+		data, err := s.download(url)
+		if err != nil {
+			return nil, err
+		}
+		s.keep(dir, url, data)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// keep caches a downloaded image on disk, or in memory within maxArtworkMemory
+func (s *artworkStore) keep(dir, url string, data []byte) {
+	if dir != "" {
+		if err := writeArtworkFile(artworkCachePath(dir, url), data); err != nil {
+			slog.Warn("Failed to cache artwork", "url", url, "error", err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.mem[url]; ok || int64(len(data)) > maxArtworkMemory {
+		return
+	}
+	for s.memBytes+int64(len(data)) > maxArtworkMemory && len(s.memOrder) > 0 {
+		oldest := s.memOrder[0]
+		s.memOrder = s.memOrder[1:]
+		s.memBytes -= int64(len(s.mem[oldest]))
+		delete(s.mem, oldest)
+	}
+	s.mem[url] = data
+	s.memOrder = append(s.memOrder, url)
+	s.memBytes += int64(len(data))
+}
+
+// cachedSize returns the size of an image that is already cached, without
+// downloading it. Returns false if it isn't.
+func (s *artworkStore) cachedSize(url string) (int64, bool) {
+	s.mu.Lock()
+	dir := s.dir
+	data, ok := s.mem[url]
+	s.mu.Unlock()
+
+	if ok {
+		return int64(len(data)), true
+	}
+	if dir != "" {
+		if info, err := os.Stat(artworkCachePath(dir, url)); err == nil {
+			return info.Size(), true
+		}
+	}
+	return 0, false
+}
+
+// prefetch queues a background download of an image that isn't cached yet.
+// The queue is drained by a single worker so a rebuild doesn't flood the CDN.
+func (s *artworkStore) prefetch(url string) {
+	if _, ok := s.cachedSize(url); ok {
+		return
+	}
+	s.prefetchOnce.Do(func() { go s.prefetchLoop() })
+	select {
+	case s.prefetchCh <- url:
+	default:
+	}
+}
+
+func (s *artworkStore) prefetchLoop() {
+	for url := range s.prefetchCh {
+		if _, ok := s.cachedSize(url); ok {
+			continue
+		}
+		if _, err := s.get(url); err != nil {
+			slog.Debug("Failed to prefetch artwork", "url", url, "error", err)
+		}
+	}
+}
+
+func (s *artworkStore) download(url string) ([]byte, error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artwork: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("artwork download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtworkSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artwork: %w", err)
+	}
+	if len(data) > maxArtworkSize {
+		return nil, fmt.Errorf("artwork exceeds %d bytes", maxArtworkSize)
+	}
+	return data, nil
+}
+
+// writeArtworkFile writes via a temp file so readers never see a partial image
+func writeArtworkFile(dest string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	tmp := dest + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// ArtworkFile is a poster or fanart image proxied from the TMDB CDN.
+// Tree entries only report the size of an image the store already has; open
+// handles download it on first Size/Read.
+type ArtworkFile struct {
+	name    string
+	url     string
+	store   *artworkStore
+	modTime time.Time // When the item was added to the library
+
+	// Set on open handles only
+	handle bool
+	data   []byte
+	pos    int64
+}
+
+func newArtworkFile(name, url string, store *artworkStore, modTime time.Time) *ArtworkFile {
+	return &ArtworkFile{name: name, url: url, store: store, modTime: modTime}
+}
+
+// open returns an independent read handle
+func (f *ArtworkFile) open() *ArtworkFile {
+	return &ArtworkFile{name: f.name, url: f.url, store: f.store, modTime: f.modTime, handle: true}
+}
+
+// bytes loads the image once per handle
+func (f *ArtworkFile) bytes() ([]byte, error) {
+	if f.data != nil {
+		return f.data, nil
+	}
+	data, err := f.store.get(f.url)
+	if err != nil {
+		return nil, err
+	}
+	f.data = data
+	return data, nil
+}
+
+func (f *ArtworkFile) Name() string { return f.name }
+func (f *ArtworkFile) IsDir() bool  { return false }
+
+// Size reports the image size. An open handle downloads the image if needed
+// and reports 0 if that fails; a tree entry reports 0 until the image is
+// cached, queueing a background download.
+func (f *ArtworkFile) Size() int64 {
+	if !f.handle {
+		size, ok := f.cachedSize()
+		if !ok {
+			f.store.prefetch(f.url)
+		}
+		return size
+	}
+	content, err := f.bytes()
+	if err != nil {
+		slog.Warn("Failed to fetch artwork", "name", f.name, "url", f.url, "error", err)
+		return 0
+	}
+	return int64(len(content))
+}
+
+// cachedSize returns the image size if it's known without downloading
+func (f *ArtworkFile) cachedSize() (int64, bool) {
+	if f.data != nil {
+		return int64(len(f.data)), true
+	}
+	return f.store.cachedSize(f.url)
+}
+
+func (f *ArtworkFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *ArtworkFile) ReadAt(p []byte, off int64) (int, error) {
+	content, err := f.bytes()
+	if err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+	if off >= int64(len(content)) {
+		return 0, io.EOF
+	}
+	n := copy(p, content[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *ArtworkFile) Close() error { return nil }
+
+func (f *ArtworkFile) Stat() (os.FileInfo, error) {
+	return common.NewFileInfo(f.name, f.Size(), false, f.modTime), nil
+}
+
+// ETag hashes the source URL; TMDB image URLs change when the image does
+func (f *ArtworkFile) ETag() string { return makeETag(f.url) }
+
+// addArtworkToDir adds poster.jpg and fanart.jpg for the URLs that are set
+func addArtworkToDir(pathMap map[string]Entry, dir *VirtualDir, dirPath string, store *artworkStore, posterURL, fanartURL string, modTime time.Time) {
+	for name, url := range map[string]string{posterFileName: posterURL, fanartFileName: fanartURL} {
+		if url == "" {
+			continue
+		}
+		file := newArtworkFile(name, url, store, modTime)
+		dir.children[name] = file
+		pathMap[dirPath+"/"+name] = file
+		store.prefetch(url)
+	}
+}
+
+// artworkModTime returns the modification time of the artwork in dir, zero if there is none
+func artworkModTime(dir *VirtualDir) time.Time {
+	for _, name := range []string{posterFileName, fanartFileName} {
+		if f, ok := dir.children[name].(*ArtworkFile); ok {
+			return f.modTime
+		}
+	}
+	return time.Time{}
+}
+
+// artworkURL returns the URL behind an artwork entry in dir, or "" if there is none
+func artworkURL(dir *VirtualDir, name string) string {
+	if f, ok := dir.children[name].(*ArtworkFile); ok {
+		return f.url
+	}
+	return ""
+}
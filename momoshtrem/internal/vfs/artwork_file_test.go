@@ -0,0 +1,114 @@
+package vfs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestArtworkFileDownloadsOnceAndCachesToDisk(t *testing.T) {
+	image := []byte("\xff\xd8\xff\xe0 fake jpeg")
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(image)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	store := newArtworkStore()
+	store.setDir(dir)
+
+	tree, moviesDir, _ := newEmptyTree()
+	folderPath := MoviesPath + "/Movie (2020)"
+	movieDir := NewVirtualDir("Movie (2020)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[folderPath] = movieDir
+
+	url := srv.URL + "/poster.jpg"
+	addArtworkToDir(tree.pathMap, movieDir, folderPath, store, url, "", time.Time{})
+	if _, ok := tree.pathMap[folderPath+"/"+fanartFileName]; ok {
+		t.Fatalf("fanart added without a URL")
+	}
+
+	entry, ok := tree.pathMap[folderPath+"/"+posterFileName].(*ArtworkFile)
+	if !ok {
+		t.Fatalf("poster.jpg not added")
+	}
+	// Adding the entry queued a background download; its size is known once that lands
+	deadline := time.Now().Add(5 * time.Second)
+	for entry.Size() != int64(len(image)) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Size() = %d after prefetch, want %d", entry.Size(), len(image))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got, err := io.ReadAll(entry.open())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != string(image) {
+		t.Errorf("read %q, want %q", got, image)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("downloaded %d times, want 1", n)
+	}
+
+	// A fresh store (e.g. after restart) serves the image from disk
+	srv.Close()
+	restarted := newArtworkStore()
+	restarted.setDir(dir)
+	got, err = io.ReadAll(newArtworkFile(posterFileName, url, restarted, time.Time{}))
+	if err != nil {
+		t.Fatalf("read from disk cache: %v", err)
+	}
+	if string(got) != string(image) {
+		t.Errorf("disk cache read %q, want %q", got, image)
+	}
+}
+
+func TestArtworkStoreMemoryCap(t *testing.T) {
+	store := newArtworkStore()
+	chunk := make([]byte, maxArtworkMemory/3)
+
+	for _, url := range []string{"a", "b", "c", "d"} {
+		store.keep("", url, chunk)
+	}
+	if store.memBytes > maxArtworkMemory {
+		t.Errorf("memBytes = %d, over the %d cap", store.memBytes, maxArtworkMemory)
+	}
+	if _, ok := store.cachedSize("a"); ok {
+		t.Error("oldest image kept after the cap was reached")
+	}
+	if size, ok := store.cachedSize("d"); !ok || size != int64(len(chunk)) {
+		t.Errorf("cachedSize(d) = %d, %v; want newest image kept", size, ok)
+	}
+}
+
+func TestArtworkEntrySizeDoesNotDownload(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("jpeg"))
+	}))
+	defer srv.Close()
+
+	// The prefetch worker isn't running, so only Size could download
+	store := newArtworkStore()
+	store.prefetchOnce.Do(func() {})
+	entry := newArtworkFile(posterFileName, srv.URL+"/poster.jpg", store, time.Time{})
+
+	if got := entry.Size(); got != 0 {
+		t.Errorf("Size() of an uncached entry = %d, want 0", got)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Size() downloaded the image %d times", n)
+	}
+	if got := entry.open().Size(); got != 4 {
+		t.Errorf("Size() of an open handle = %d, want 4", got)
+	}
+}
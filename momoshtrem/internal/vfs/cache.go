@@ -6,35 +6,60 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/shapedtime/momoshtrem/internal/library"
 )
 
 const (
-	cacheVersion = 1
+	cacheVersion = 11
 	cacheFile    = "vfs_tree.gob"
 )
 
 // treeCache is the serializable representation of the VFS tree.
 type treeCache struct {
-	Version int
-	Movies  []cachedMovie
-	Shows   []cachedShow
+	Version    int
+	FlatMovies bool
+	ForceExt   string // Forced video extension the file names were built with
+
+	// Library change time the tree reflects; a newer database value makes the cache stale
+	LibraryUpdatedAt time.Time
+
+	Movies    []cachedMovie
+	AllMovies []cachedMovie // Flat /All Movies entries, FolderName unused
+	Shows     []cachedShow
+
+	TagFolderKey string
+	TagAliases   map[string]string // Tag/collection folder link path -> library folder path
 }
 
 type cachedMovie struct {
-	FolderName string
-	FileName   string
-	FileSize   int64
-	InfoHash   string
-	MagnetURI  string
-	FilePath   string
+	AssignmentID int64
+	ItemID       int64
+	FolderName   string
+	FileName     string
+	FileSize     int64
+	InfoHash     string
+	MagnetURI    string
+	FilePath     string
+	PartPaths    []string  // Disc files of a multi-part movie, in play order
+	LocalPath    string    // Set for files on local disk
+	CreatedAt    time.Time // Assignment time, the video's modification time
+	Nfo          *nfoMetadata
+	PosterURL    string
+	FanartURL    string
 }
 
 type cachedShow struct {
 	FolderName string
 	Seasons    []cachedSeason
+	PosterURL  string
+	FanartURL  string
+	CreatedAt  time.Time // Show time, the artwork's modification time
+	Nfo        *nfoMetadata
 }
 
 type cachedSeason struct {
@@ -43,15 +68,54 @@ type cachedSeason struct {
 }
 
 type cachedEpisode struct {
-	FileName  string
-	FileSize  int64
-	InfoHash  string
-	MagnetURI string
-	FilePath  string
+	AssignmentID int64
+	ItemID       int64
+	FileName     string
+	FileSize     int64
+	InfoHash     string
+	MagnetURI    string
+	FilePath     string
+	LocalPath    string    // Set for files on local disk
+	CreatedAt    time.Time // Assignment time, the video's modification time
+	Nfo          *nfoMetadata
+}
+
+// assignment rebuilds the torrent assignment for a cached movie file
+func (cm cachedMovie) assignment() *library.TorrentAssignment {
+	return &library.TorrentAssignment{
+		ID:        cm.AssignmentID,
+		ItemType:  library.ItemTypeMovie,
+		ItemID:    cm.ItemID,
+		InfoHash:  cm.InfoHash,
+		MagnetURI: cm.MagnetURI,
+		FilePath:  cm.FilePath,
+		PartPaths: cm.PartPaths,
+		LocalPath: cm.LocalPath,
+		FileSize:  cm.FileSize,
+		IsActive:  true,
+		CreatedAt: cm.CreatedAt,
+	}
+}
+
+// assignment rebuilds the torrent assignment for a cached episode file
+func (ce cachedEpisode) assignment() *library.TorrentAssignment {
+	return &library.TorrentAssignment{
+		ID:        ce.AssignmentID,
+		ItemType:  library.ItemTypeEpisode,
+		ItemID:    ce.ItemID,
+		InfoHash:  ce.InfoHash,
+		MagnetURI: ce.MagnetURI,
+		FilePath:  ce.FilePath,
+		LocalPath: ce.LocalPath,
+		FileSize:  ce.FileSize,
+		IsActive:  true,
+		CreatedAt: ce.CreatedAt,
+	}
 }
 
 // loadTreeFromCache attempts to load the VFS tree from disk cache.
-// Returns error if cache doesn't exist, is invalid, or version mismatches.
+// Returns error if cache doesn't exist, is invalid, version mismatches,
+// or the library changed in the database after the cache was written.
 func (fs *LibraryFS) loadTreeFromCache() error {
 	if fs.cacheDir == "" {
 		return errors.New("no cache directory configured")
@@ -72,6 +136,29 @@ func (fs *LibraryFS) loadTreeFromCache() error {
 		return errors.New("cache version mismatch")
 	}
 
+	fs.mu.RLock()
+	flatMovies := fs.flatMovies
+	forceExt := fs.forceExt
+	tagFolderKey := fs.tagFolderKey
+	fs.mu.RUnlock()
+	if cache.FlatMovies != flatMovies {
+		return errors.New("cache flat movies setting mismatch")
+	}
+	if cache.ForceExt != forceExt {
+		return errors.New("cache forced extension setting mismatch")
+	}
+	if cache.TagFolderKey != tagFolderKey {
+		return errors.New("cache tag folder setting mismatch")
+	}
+
+	updatedAt, err := fs.currentLibraryUpdatedAt()
+	if err != nil {
+		return err
+	}
+	if updatedAt.After(cache.LibraryUpdatedAt) {
+		return errors.New("cache is older than the library")
+	}
+
 	// Reconstruct tree from cache
 	tree, moviesDir, tvDir := newEmptyTree()
 
@@ -83,16 +170,26 @@ func (fs *LibraryFS) loadTreeFromCache() error {
 		tree.pathMap[folderPath] = movieDir
 
 		// Restore video file
-		assignment := &library.TorrentAssignment{
-			InfoHash:  cm.InfoHash,
-			MagnetURI: cm.MagnetURI,
-			FilePath:  cm.FilePath,
-			FileSize:  cm.FileSize,
-		}
+		assignment := cm.assignment()
 		videoFile := NewPlaceholderFile(cm.FileName, cm.FileSize, assignment)
 		filePath := folderPath + "/" + cm.FileName
 		movieDir.children[cm.FileName] = videoFile
 		tree.pathMap[filePath] = videoFile
+
+		if cm.Nfo != nil {
+			addNfoToDir(tree.pathMap, movieDir, folderPath, movieNfoName, cm.Nfo, cm.CreatedAt)
+		}
+		addArtworkToDir(tree.pathMap, movieDir, folderPath, fs.artwork, cm.PosterURL, cm.FanartURL, cm.CreatedAt)
+	}
+
+	// Restore flat movie listing (names already disambiguated)
+	if flatMovies {
+		flatDir := ensureFlatMoviesDir(tree)
+		for _, cm := range cache.AllMovies {
+			videoFile := NewPlaceholderFile(cm.FileName, cm.FileSize, cm.assignment())
+			flatDir.children[cm.FileName] = videoFile
+			tree.pathMap[AllMoviesPath+"/"+cm.FileName] = videoFile
+		}
 	}
 
 	// Restore shows
@@ -101,6 +198,10 @@ func (fs *LibraryFS) loadTreeFromCache() error {
 		showPath := TVShowsPath + "/" + cs.FolderName
 		tvDir.children[cs.FolderName] = showDir
 		tree.pathMap[showPath] = showDir
+		addArtworkToDir(tree.pathMap, showDir, showPath, fs.artwork, cs.PosterURL, cs.FanartURL, cs.CreatedAt)
+		if cs.Nfo != nil {
+			addNfoToDir(tree.pathMap, showDir, showPath, showNfoName, cs.Nfo, cs.CreatedAt)
+		}
 
 		for _, csn := range cs.Seasons {
 			seasonDir := NewVirtualDir(csn.FolderName)
@@ -109,38 +210,53 @@ func (fs *LibraryFS) loadTreeFromCache() error {
 			tree.pathMap[seasonPath] = seasonDir
 
 			for _, ce := range csn.Episodes {
-				assignment := &library.TorrentAssignment{
-					InfoHash:  ce.InfoHash,
-					MagnetURI: ce.MagnetURI,
-					FilePath:  ce.FilePath,
-					FileSize:  ce.FileSize,
-				}
-				videoFile := NewPlaceholderFile(ce.FileName, ce.FileSize, assignment)
+				videoFile := NewPlaceholderFile(ce.FileName, ce.FileSize, ce.assignment())
 				filePath := seasonPath + "/" + ce.FileName
 				seasonDir.children[ce.FileName] = videoFile
 				tree.pathMap[filePath] = videoFile
+
+				if ce.Nfo != nil {
+					nfoName := makeNfoFileName(strings.TrimSuffix(ce.FileName, fs.videoExt(ce.FilePath)))
+					addNfoToDir(tree.pathMap, seasonDir, seasonPath, nfoName, ce.Nfo, ce.CreatedAt)
+				}
 			}
 		}
 	}
 
-	// Atomic swap
+	// Restore tag folder links (library folders must already be in the tree)
+	for aliasPath, targetPath := range cache.TagAliases {
+		groupPath := path.Dir(aliasPath)
+		linkGroupFolder(tree, path.Dir(groupPath), path.Base(groupPath), targetPath)
+	}
+	tree.root.refreshModTime()
+
+	// Atomic swap, unless a rebuild already produced a tree
 	fs.mu.Lock()
+	if fs.tree != nil {
+		fs.mu.Unlock()
+		return nil
+	}
 	fs.tree = tree
+	fs.treeAsOf = cache.LibraryUpdatedAt
 	fs.mu.Unlock()
 
 	slog.Info("VFS tree loaded from cache", "entries", len(tree.pathMap))
 	return nil
 }
 
-// saveTreeToCache serializes the current VFS tree to disk.
-// This runs synchronously after tree rebuild.
-func (fs *LibraryFS) saveTreeToCache() {
+// saveTreeToCache serializes the current VFS tree to disk, stamped with the
+// library change time the tree reflects.
+// This runs synchronously after tree rebuild and on shutdown.
+func (fs *LibraryFS) saveTreeToCache(libraryUpdatedAt time.Time) {
 	if fs.cacheDir == "" {
 		return
 	}
 
 	fs.mu.RLock()
 	tree := fs.tree
+	flatMovies := fs.flatMovies
+	forceExt := fs.forceExt
+	tagFolderKey := fs.tagFolderKey
 	fs.mu.RUnlock()
 
 	if tree == nil {
@@ -148,7 +264,17 @@ func (fs *LibraryFS) saveTreeToCache() {
 	}
 
 	cache := treeCache{
-		Version: cacheVersion,
+		Version:    cacheVersion,
+		FlatMovies: flatMovies,
+		ForceExt:   forceExt,
+
+		LibraryUpdatedAt: libraryUpdatedAt,
+
+		TagFolderKey: tagFolderKey,
+		TagAliases:   make(map[string]string, len(tree.aliases)),
+	}
+	for aliasPath, targetPath := range tree.aliases {
+		cache.TagAliases[aliasPath] = targetPath
 	}
 
 	// Extract movies
@@ -164,19 +290,52 @@ func (fs *LibraryFS) saveTreeToCache() {
 				if !ok || pf.assignment == nil {
 					continue
 				}
-				cache.Movies = append(cache.Movies, cachedMovie{
-					FolderName: folderName,
-					FileName:   fileName,
-					FileSize:   pf.assignment.FileSize,
-					InfoHash:   pf.assignment.InfoHash,
-					MagnetURI:  pf.assignment.MagnetURI,
-					FilePath:   pf.assignment.FilePath,
-				})
+				cm := cachedMovie{
+					AssignmentID: pf.assignment.ID,
+					ItemID:       pf.assignment.ItemID,
+					FolderName:   folderName,
+					FileName:     fileName,
+					FileSize:     pf.assignment.FileSize,
+					InfoHash:     pf.assignment.InfoHash,
+					MagnetURI:    pf.assignment.MagnetURI,
+					FilePath:     pf.assignment.FilePath,
+					PartPaths:    pf.assignment.PartPaths,
+					LocalPath:    pf.assignment.LocalPath,
+					CreatedAt:    pf.assignment.CreatedAt,
+					PosterURL:    artworkURL(movieDir, posterFileName),
+					FanartURL:    artworkURL(movieDir, fanartFileName),
+				}
+				if nfo, ok := movieDir.children[movieNfoName].(*NfoFile); ok {
+					cm.Nfo = nfo.meta
+				}
+				cache.Movies = append(cache.Movies, cm)
 				break // Only one video file per movie folder
 			}
 		}
 	}
 
+	// Extract flat movie listing
+	if flatDir, ok := tree.pathMap[AllMoviesPath].(*VirtualDir); ok {
+		for fileName, fileEntry := range flatDir.children {
+			pf, ok := fileEntry.(*PlaceholderFile)
+			if !ok || pf.assignment == nil {
+				continue
+			}
+			cache.AllMovies = append(cache.AllMovies, cachedMovie{
+				AssignmentID: pf.assignment.ID,
+				ItemID:       pf.assignment.ItemID,
+				FileName:     fileName,
+				FileSize:     pf.assignment.FileSize,
+				InfoHash:     pf.assignment.InfoHash,
+				MagnetURI:    pf.assignment.MagnetURI,
+				FilePath:     pf.assignment.FilePath,
+				PartPaths:    pf.assignment.PartPaths,
+				LocalPath:    pf.assignment.LocalPath,
+				CreatedAt:    pf.assignment.CreatedAt,
+			})
+		}
+	}
+
 	// Extract shows
 	tvDir, ok := tree.pathMap[TVShowsPath].(*VirtualDir)
 	if ok {
@@ -185,7 +344,16 @@ func (fs *LibraryFS) saveTreeToCache() {
 			if !ok {
 				continue
 			}
-			cs := cachedShow{FolderName: showFolderName}
+			cs := cachedShow{
+				FolderName: showFolderName,
+				PosterURL:  artworkURL(showDir, posterFileName),
+				FanartURL:  artworkURL(showDir, fanartFileName),
+				CreatedAt:  artworkModTime(showDir),
+			}
+			if nfo, ok := showDir.children[showNfoName].(*NfoFile); ok {
+				cs.Nfo = nfo.meta
+				cs.CreatedAt = nfo.modTime
+			}
 
 			for seasonFolderName, seasonEntry := range showDir.children {
 				seasonDir, ok := seasonEntry.(*VirtualDir)
@@ -199,13 +367,22 @@ func (fs *LibraryFS) saveTreeToCache() {
 					if !ok || pf.assignment == nil {
 						continue
 					}
-					csn.Episodes = append(csn.Episodes, cachedEpisode{
-						FileName:  fileName,
-						FileSize:  pf.assignment.FileSize,
-						InfoHash:  pf.assignment.InfoHash,
-						MagnetURI: pf.assignment.MagnetURI,
-						FilePath:  pf.assignment.FilePath,
-					})
+					ce := cachedEpisode{
+						AssignmentID: pf.assignment.ID,
+						ItemID:       pf.assignment.ItemID,
+						FileName:     fileName,
+						FileSize:     pf.assignment.FileSize,
+						InfoHash:     pf.assignment.InfoHash,
+						MagnetURI:    pf.assignment.MagnetURI,
+						FilePath:     pf.assignment.FilePath,
+						LocalPath:    pf.assignment.LocalPath,
+						CreatedAt:    pf.assignment.CreatedAt,
+					}
+					nfoName := makeNfoFileName(strings.TrimSuffix(fileName, fs.videoExt(pf.assignment.FilePath)))
+					if nfo, ok := seasonDir.children[nfoName].(*NfoFile); ok {
+						ce.Nfo = nfo.meta
+					}
+					csn.Episodes = append(csn.Episodes, ce)
 				}
 				if len(csn.Episodes) > 0 {
 					cs.Seasons = append(cs.Seasons, csn)
@@ -262,3 +439,44 @@ func (fs *LibraryFS) DeleteCache() error {
 	}
 	return err
 }
+
+// SaveCache persists the current tree so the next start can skip the database
+// rebuild. Targeted tree updates since the last rebuild are only saved here,
+// so call it on shutdown. Nothing is saved while a rebuild is pending.
+func (fs *LibraryFS) SaveCache() {
+	fs.rebuilding.Lock()
+	defer fs.rebuilding.Unlock()
+
+	fs.invalidateMu.Lock()
+	pending := fs.pendingRebuild != nil
+	fs.invalidateMu.Unlock()
+	if pending {
+		slog.Info("VFS tree rebuild pending, not saving cache")
+		return
+	}
+
+	// Stamp with the time the tree was built from, not the current one, so
+	// changes the tree missed still mark it stale on next load
+	fs.mu.RLock()
+	asOf := fs.treeAsOf
+	fs.mu.RUnlock()
+	fs.saveTreeToCache(asOf)
+}
+
+// currentLibraryUpdatedAt returns the database's library change time,
+// or zero when change tracking isn't configured.
+func (fs *LibraryFS) currentLibraryUpdatedAt() (time.Time, error) {
+	fs.mu.RLock()
+	libraryUpdatedAt := fs.libraryUpdatedAt
+	fs.mu.RUnlock()
+
+	if libraryUpdatedAt == nil {
+		return time.Time{}, nil
+	}
+	t, err := libraryUpdatedAt()
+	if err != nil {
+		slog.Warn("Failed to read library change time", "error", err)
+		return time.Time{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,176 @@
+package vfs
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestTreeCacheRoundTripAndStaleness(t *testing.T) {
+	dir := t.TempDir()
+	savedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tree, _, tvDir := newEmptyTree()
+	showPath := TVShowsPath + "/Show (2020)"
+	showDir := NewVirtualDir("Show (2020)")
+	tvDir.children[showDir.name] = showDir
+	tree.pathMap[showPath] = showDir
+	seasonPath := showPath + "/Season 01"
+	seasonDir := NewVirtualDir("Season 01")
+	showDir.children[seasonDir.name] = seasonDir
+	tree.pathMap[seasonPath] = seasonDir
+
+	assignment := &library.TorrentAssignment{
+		ID:       7,
+		ItemType: library.ItemTypeEpisode,
+		ItemID:   42,
+		InfoHash: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		FilePath: "Show.S01E01.mkv",
+		FileSize: 1 << 30,
+	}
+	fileName := makeEpisodeFileName("Show", 1, 1, "Pilot", ".mkv")
+	video := NewPlaceholderFile(fileName, assignment.FileSize, assignment)
+	seasonDir.children[fileName] = video
+	tree.pathMap[seasonPath+"/"+fileName] = video
+
+	saver := NewLibraryFS(nil, nil, nil, 0)
+	saver.SetCacheDir(dir)
+	saver.tree = tree
+	saver.saveTreeToCache(savedAt)
+
+	loadWithLibraryTime := func(libraryUpdatedAt time.Time) (*LibraryFS, error) {
+		fs := NewLibraryFS(nil, nil, nil, 0)
+		fs.SetCacheDir(dir)
+		fs.libraryUpdatedAt = func() (time.Time, error) { return libraryUpdatedAt, nil }
+		return fs, fs.loadTreeFromCache()
+	}
+
+	fs, err := loadWithLibraryTime(savedAt)
+	if err != nil {
+		t.Fatalf("loadTreeFromCache: %v", err)
+	}
+	entry, ok := fs.tree.lookup(seasonPath + "/" + fileName)
+	if !ok {
+		t.Fatalf("episode not restored from cache")
+	}
+	got := entry.(*PlaceholderFile).assignment
+	if got.ID != 7 || got.ItemID != 42 || got.ItemType != library.ItemTypeEpisode || got.InfoHash != assignment.InfoHash {
+		t.Errorf("restored assignment = %+v, want id 7 for episode 42", got)
+	}
+
+	if _, err := loadWithLibraryTime(savedAt.Add(time.Second)); err == nil {
+		t.Errorf("loadTreeFromCache succeeded although the library changed after the cache was saved")
+	}
+}
+
+func TestTreeCacheKeepsMovieParts(t *testing.T) {
+	dir := t.TempDir()
+	savedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tree, moviesDir, _ := newEmptyTree()
+	folderPath := MoviesPath + "/Movie (1999)"
+	movieDir := NewVirtualDir("Movie (1999)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[folderPath] = movieDir
+
+	parts := []string{"Movie.CD1.avi", "Movie.CD2.avi"}
+	assignment := &library.TorrentAssignment{
+		ID:        3,
+		ItemType:  library.ItemTypeMovie,
+		ItemID:    9,
+		InfoHash:  "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		FilePath:  parts[0],
+		PartPaths: parts,
+		FileSize:  2 << 30,
+	}
+	video := NewPlaceholderFile("Movie (1999).avi", assignment.FileSize, assignment)
+	movieDir.children[video.name] = video
+	tree.pathMap[folderPath+"/"+video.name] = video
+
+	saver := NewLibraryFS(nil, nil, nil, 0)
+	saver.SetCacheDir(dir)
+	saver.tree = tree
+	saver.saveTreeToCache(savedAt)
+
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetCacheDir(dir)
+	if err := fs.loadTreeFromCache(); err != nil {
+		t.Fatalf("loadTreeFromCache: %v", err)
+	}
+	entry, ok := fs.tree.lookup(folderPath + "/" + video.name)
+	if !ok {
+		t.Fatalf("movie not restored from cache")
+	}
+	if got := entry.(*PlaceholderFile).assignment.PartPaths; !slices.Equal(got, parts) {
+		t.Errorf("restored PartPaths = %v, want %v", got, parts)
+	}
+}
+
+func TestEnsureTreeLoadsOnceForConcurrentCallers(t *testing.T) {
+	dir := t.TempDir()
+	savedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	saver := NewLibraryFS(nil, nil, nil, 0)
+	saver.SetCacheDir(dir)
+	saver.tree, _, _ = newEmptyTree()
+	saver.saveTreeToCache(savedAt)
+
+	// The staleness check runs once per cache load
+	var loads atomic.Int32
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetCacheDir(dir)
+	fs.libraryUpdatedAt = func() (time.Time, error) {
+		loads.Add(1)
+		time.Sleep(10 * time.Millisecond) // Keep the load in flight while others arrive
+		return savedAt, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := fs.ReadDir("/"); err != nil {
+				t.Errorf("ReadDir: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("tree loaded %d times, want 1", n)
+	}
+}
+
+func TestSaveCacheKeepsStaleTreeStale(t *testing.T) {
+	dir := t.TempDir()
+	builtAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	changedAt := builtAt.Add(time.Minute)
+
+	saver := NewLibraryFS(nil, nil, nil, 0)
+	saver.SetCacheDir(dir)
+	saver.tree, _, _ = newEmptyTree()
+	saver.saveTreeToCache(builtAt)
+
+	// Load the tree, then let the library change without a rebuild
+	libraryTime := builtAt
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetCacheDir(dir)
+	fs.libraryUpdatedAt = func() (time.Time, error) { return libraryTime, nil }
+	if err := fs.loadTreeFromCache(); err != nil {
+		t.Fatalf("loadTreeFromCache: %v", err)
+	}
+	libraryTime = changedAt
+	fs.SaveCache()
+
+	next := NewLibraryFS(nil, nil, nil, 0)
+	next.SetCacheDir(dir)
+	next.libraryUpdatedAt = func() (time.Time, error) { return changedAt, nil }
+	if err := next.loadTreeFromCache(); err == nil {
+		t.Error("cache saved at shutdown was loaded although the tree missed a library change")
+	}
+}
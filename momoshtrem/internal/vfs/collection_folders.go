@@ -0,0 +1,37 @@
+package vfs
+
+import (
+	"log/slog"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// addCollectionFolders builds /Collections/<name>/ folders from user collections.
+// Members link to the existing movie/show directories, so files are shared with
+// /Movies and /TV Shows rather than duplicated. Collections are refreshed on full
+// rebuilds (collection changes invalidate the tree).
+func (fs *LibraryFS) addCollectionFolders(tree *DirectoryTree, moviePaths, showPaths map[int64]string) {
+	if fs.collectionRepo == nil {
+		return
+	}
+
+	memberships, err := fs.collectionRepo.ListMemberships()
+	if err != nil {
+		slog.Error("Failed to list collections for VFS", "error", err)
+		return
+	}
+
+	for _, m := range memberships {
+		var targetPath string
+		switch m.ItemType {
+		case library.ItemTypeMovie:
+			targetPath = moviePaths[m.ItemID]
+		case library.ItemTypeShow:
+			targetPath = showPaths[m.ItemID]
+		}
+		if targetPath == "" {
+			continue
+		}
+		linkGroupFolder(tree, CollectionsPath, m.CollectionName, targetPath)
+	}
+}
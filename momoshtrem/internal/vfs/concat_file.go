@@ -0,0 +1,154 @@
+package vfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// Ensure ConcatFile implements File interface
+var _ File = (*ConcatFile)(nil)
+
+// Ensure ConcatFile can serve HTTP range requests via http.ServeContent
+var _ io.ReadSeeker = (*ConcatFile)(nil)
+
+// ConcatFile presents the parts of a multi-part movie (CD1, CD2, ...) as one
+// file whose size is the sum of the parts. Reads past the end of one part
+// continue at the start of the next, so a player sees a single stream.
+type ConcatFile struct {
+	name  string
+	parts []File
+
+	// starts[i] is the offset of parts[i] within the whole file; the extra
+	// last entry is the total size
+	starts []int64
+
+	mu  sync.Mutex // Guards pos
+	pos int64
+}
+
+// NewConcatFile joins the parts in order. It takes ownership of the parts and
+// closes them on Close.
+func NewConcatFile(name string, parts []File) *ConcatFile {
+	starts := make([]int64, len(parts)+1)
+	for i, part := range parts {
+		starts[i+1] = starts[i] + part.Size()
+	}
+	return &ConcatFile{
+		name:   name,
+		parts:  parts,
+		starts: starts,
+	}
+}
+
+// Name returns the file name.
+func (f *ConcatFile) Name() string { return f.name }
+
+// IsDir returns false.
+func (f *ConcatFile) IsDir() bool { return false }
+
+// Size returns the combined size of all parts.
+func (f *ConcatFile) Size() int64 { return f.starts[len(f.parts)] }
+
+// Stat returns file info, with the latest modification time of the parts.
+func (f *ConcatFile) Stat() (os.FileInfo, error) {
+	var modTime time.Time
+	for _, part := range f.parts {
+		info, err := part.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return common.NewFileInfo(f.name, f.Size(), false, modTime), nil
+}
+
+// Read reads from the current position, crossing part boundaries as needed.
+func (f *ConcatFile) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n, err := f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil // Report EOF on the next call, like a regular file
+	}
+	return n, err
+}
+
+// ReadAt reads len(p) bytes at offset off of the combined file, splitting the
+// read across the parts it spans.
+func (f *ConcatFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+
+	read := 0
+	for read < len(p) {
+		if off >= f.Size() {
+			return read, io.EOF
+		}
+
+		// First part ending after off; zero-length parts are skipped
+		i := sort.Search(len(f.parts), func(i int) bool { return f.starts[i+1] > off })
+
+		chunk := p[read:]
+		if remaining := f.starts[i+1] - off; int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+
+		n, err := f.parts[i].ReadAt(chunk, off-f.starts[i])
+		read += n
+		off += int64(n)
+		if err == io.EOF && n < len(chunk) {
+			// The part is shorter than its reported size
+			return read, io.ErrUnexpectedEOF
+		}
+		if err != nil && err != io.EOF {
+			return read, err
+		}
+		if n == 0 && err == nil {
+			return read, io.ErrNoProgress
+		}
+	}
+	return read, nil
+}
+
+// Seek sets the offset for the next Read.
+func (f *ConcatFile) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += f.Size()
+	default:
+		return 0, os.ErrInvalid
+	}
+	if offset < 0 {
+		return 0, os.ErrInvalid
+	}
+	f.pos = offset
+	return offset, nil
+}
+
+// Close closes every part.
+func (f *ConcatFile) Close() error {
+	var errs []error
+	for _, part := range f.parts {
+		if err := part.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
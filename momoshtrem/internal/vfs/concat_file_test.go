@@ -0,0 +1,77 @@
+package vfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newConcatTestFile joins local files holding the given contents
+func newConcatTestFile(t *testing.T, contents ...string) *ConcatFile {
+	t.Helper()
+	dir := t.TempDir()
+	parts := make([]File, len(contents))
+	for i, content := range contents {
+		path := filepath.Join(dir, "part"+string(rune('1'+i))+".avi")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		parts[i] = NewSubtitleFile(filepath.Base(path), path, int64(len(content)))
+	}
+	f := NewConcatFile("Movie (1999).avi", parts)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestConcatFileReadAtSpansParts(t *testing.T) {
+	f := newConcatTestFile(t, "abcd", "", "efg", "hij")
+	if f.Size() != 10 {
+		t.Fatalf("Size() = %d, want 10", f.Size())
+	}
+
+	tests := []struct {
+		off  int64
+		n    int
+		want string
+		err  error
+	}{
+		{0, 4, "abcd", nil},        // Exactly the first part
+		{2, 5, "cdefg", nil},       // Across a boundary and the empty part
+		{3, 7, "defghij", nil},     // Across every part to the end
+		{8, 4, "ij", io.EOF},       // Short read at the end
+		{10, 1, "", io.EOF},        // At the end
+		{0, 10, "abcdefghij", nil}, // Whole file
+	}
+	for _, tt := range tests {
+		buf := make([]byte, tt.n)
+		n, err := f.ReadAt(buf, tt.off)
+		if string(buf[:n]) != tt.want || err != tt.err {
+			t.Errorf("ReadAt(%d bytes, %d) = %q, %v; want %q, %v", tt.n, tt.off, buf[:n], err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestConcatFileReadAndSeek(t *testing.T) {
+	f := newConcatTestFile(t, "abcd", "efg", "hij")
+
+	if _, err := f.Seek(-5, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "fghij" {
+		t.Errorf("read after Seek(-5, end) = %q, %v; want \"fghij\"", data, err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err = io.ReadAll(f)
+	if err != nil || string(data) != "abcdefghij" {
+		t.Errorf("read from start = %q, %v; want \"abcdefghij\"", data, err)
+	}
+
+	if _, err := f.Seek(-1, io.SeekStart); err == nil {
+		t.Error("Seek(-1, start) succeeded, want error")
+	}
+}
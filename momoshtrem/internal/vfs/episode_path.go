@@ -0,0 +1,22 @@
+package vfs
+
+import "github.com/shapedtime/momoshtrem/internal/library"
+
+// EpisodePathResolver locates assigned episodes in the library tree.
+// This interface is implemented by LibraryFS and used by the API server.
+type EpisodePathResolver interface {
+	// EpisodeVideoPath returns the VFS path of an episode's video file
+	EpisodeVideoPath(show *library.Show, seasonNumber int, episode *library.Episode) string
+}
+
+// EpisodeVideoPath returns the path an assigned episode's video is listed at:
+// /TV Shows/Title (Year)/Season 01/Title - S01E05 - Name.ext
+func (fs *LibraryFS) EpisodeVideoPath(show *library.Show, seasonNumber int, episode *library.Episode) string {
+	ext := DefaultVideoExt
+	if episode.Assignment != nil {
+		ext = fs.videoExt(episode.Assignment.FilePath)
+	}
+	return TVShowsPath + "/" + makeMediaFolderName(show.Title, show.Year) +
+		"/" + makeSeasonFolderName(seasonNumber) +
+		"/" + makeEpisodeFileName(show.Title, seasonNumber, episode.EpisodeNumber, episode.Name, ext)
+}
@@ -0,0 +1,68 @@
+package vfs
+
+import (
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// flatMoviesDirName is the optional top-level directory listing every movie file directly
+const flatMoviesDirName = "All Movies"
+
+// ensureFlatMoviesDir returns the /All Movies directory, creating it if needed
+func ensureFlatMoviesDir(tree *DirectoryTree) *VirtualDir {
+	if dir, ok := tree.pathMap[AllMoviesPath].(*VirtualDir); ok {
+		return dir
+	}
+	dir := NewVirtualDir(flatMoviesDirName)
+	tree.root.children[flatMoviesDirName] = dir
+	tree.pathMap[AllMoviesPath] = dir
+	return dir
+}
+
+// makeFlatConflictName disambiguates a flat movie filename: "Title (Year) [abc123].ext"
+func makeFlatConflictName(folderName, ext, infoHash string) string {
+	short := infoHash
+	if len(short) > 6 {
+		short = short[:6]
+	}
+	return folderName + " [" + short + "]" + ext
+}
+
+// addFlatMovie lists a movie's video file in /All Movies.
+// A movie already listed (re-assignment) is replaced; a different movie with the
+// same title and year gets a [hash6] suffix so neither file is hidden.
+func addFlatMovie(tree *DirectoryTree, folderName, ext string, assignment *library.TorrentAssignment) {
+	flatDir := ensureFlatMoviesDir(tree)
+	removeFlatMovie(tree, assignment.ItemID)
+
+	fileName := folderName + ext
+	if _, taken := flatDir.children[fileName]; taken {
+		tag := assignment.InfoHash
+		if assignment.IsLocal() {
+			tag = "local"
+		}
+		fileName = makeFlatConflictName(folderName, ext, tag)
+	}
+
+	videoFile := NewPlaceholderFile(fileName, assignment.FileSize, assignment)
+	flatDir.children[fileName] = videoFile
+	tree.pathMap[AllMoviesPath+"/"+fileName] = videoFile
+}
+
+// removeFlatMovie removes a movie's entries from /All Movies by library ID
+func removeFlatMovie(tree *DirectoryTree, movieID int64) {
+	if movieID == 0 {
+		return
+	}
+	flatDir, ok := tree.pathMap[AllMoviesPath].(*VirtualDir)
+	if !ok {
+		return
+	}
+	for name, entry := range flatDir.children {
+		pf, ok := entry.(*PlaceholderFile)
+		if !ok || pf.assignment == nil || pf.assignment.ItemID != movieID {
+			continue
+		}
+		delete(flatDir.children, name)
+		delete(tree.pathMap, AllMoviesPath+"/"+name)
+	}
+}
@@ -0,0 +1,43 @@
+package vfs
+
+import (
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestAddFlatMovieConflicts(t *testing.T) {
+	tree, _, _ := newEmptyTree()
+
+	first := &library.TorrentAssignment{ItemID: 1, InfoHash: "abcdef0123", FilePath: "a/Movie.mkv"}
+	second := &library.TorrentAssignment{ItemID: 2, InfoHash: "123456abcd", FilePath: "b/Movie.mkv"}
+
+	addFlatMovie(tree, "Movie (2020)", getVideoExt(first.FilePath), first)
+	addFlatMovie(tree, "Movie (2020)", getVideoExt(second.FilePath), second)
+
+	for _, path := range []string{
+		AllMoviesPath + "/Movie (2020).mkv",
+		AllMoviesPath + "/Movie (2020) [123456].mkv",
+	} {
+		if _, ok := tree.pathMap[path]; !ok {
+			t.Errorf("%q not found in tree", path)
+		}
+	}
+
+	// Re-assigning the first movie replaces its entry instead of adding a conflict
+	reassigned := &library.TorrentAssignment{ItemID: 1, InfoHash: "fedcba9876", FilePath: "c/Movie.mp4"}
+	addFlatMovie(tree, "Movie (2020)", getVideoExt(reassigned.FilePath), reassigned)
+
+	flatDir := tree.pathMap[AllMoviesPath].(*VirtualDir)
+	if len(flatDir.children) != 2 {
+		t.Errorf("flat dir has %d entries, want 2", len(flatDir.children))
+	}
+	if _, ok := tree.pathMap[AllMoviesPath+"/Movie (2020).mp4"]; !ok {
+		t.Errorf("re-assigned movie not found in tree")
+	}
+
+	removeFlatMovie(tree, 2)
+	if _, ok := tree.pathMap[AllMoviesPath+"/Movie (2020) [123456].mkv"]; ok {
+		t.Errorf("removed movie still in tree")
+	}
+}
@@ -32,3 +32,10 @@ type File interface {
 	// Stat returns file info
 	Stat() (os.FileInfo, error)
 }
+
+// ETagger is implemented by files that can name the version of their content.
+// Files without it are tagged from their modification time and size.
+type ETagger interface {
+	// ETag returns a quoted entity tag, or "" if the version is unknown
+	ETag() string
+}
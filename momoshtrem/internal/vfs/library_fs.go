@@ -2,11 +2,17 @@ package vfs
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"log/slog"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shapedtime/momoshtrem/internal/common"
@@ -15,6 +21,7 @@ import (
 	"github.com/shapedtime/momoshtrem/internal/streaming"
 	"github.com/shapedtime/momoshtrem/internal/subtitle"
 	"github.com/shapedtime/momoshtrem/internal/torrent"
+	"golang.org/x/sync/singleflight"
 )
 
 // VFS path and file constants
@@ -22,8 +29,18 @@ const (
 	DefaultVideoExt = ".mkv"
 	MoviesPath      = "/Movies"
 	TVShowsPath     = "/TV Shows"
+	AllMoviesPath   = "/All Movies"
+	TagsPath        = "/Tags"
+	CollectionsPath = "/Collections"
+
+	// DefaultRebuildDelay is how long InvalidateTree waits to coalesce further invalidations
+	DefaultRebuildDelay = 500 * time.Millisecond
 )
 
+// ErrDraining is returned when opening a torrent-backed file after
+// StartDraining, while the server is shutting down.
+var ErrDraining = errors.New("filesystem is draining for shutdown")
+
 // makeMediaFolderName creates a folder name for movies or shows: "Title (Year)"
 func makeMediaFolderName(title string, year int) string {
 	return library.SanitizeFilename(title) + " (" + common.Itoa(year) + ")"
@@ -59,6 +76,15 @@ func getVideoExt(filePath string) string {
 	return ext
 }
 
+// videoExt returns the extension a video file is listed with: the forced
+// extension when configured, otherwise the torrent file's own
+func (fs *LibraryFS) videoExt(filePath string) string {
+	if fs.forceExt != "" {
+		return fs.forceExt
+	}
+	return getVideoExt(filePath)
+}
+
 // LibraryFS implements Filesystem backed by the library database
 type LibraryFS struct {
 	mu             sync.RWMutex
@@ -72,6 +98,8 @@ type LibraryFS struct {
 	readTimeout       time.Duration
 	onActivity        func(hash string)
 	waitForActivation func(hash string, timeout time.Duration) error
+	onAcquire         func(hash string)
+	onRelease         func(hash string)
 
 	// Streaming optimization config (Stage 3)
 	streamingCfg streaming.Config
@@ -79,16 +107,51 @@ type LibraryFS struct {
 	// Prometheus streaming metrics (nil when metrics disabled)
 	metrics *metrics.Metrics
 
+	// Open video streams, for buffer diagnostics
+	streams *streamRegistry
+
+	// Open TorrentFile handles, waited on at shutdown
+	activeStreams atomic.Int64
+	draining      atomic.Bool
+
 	// Cached tree structure
 	tree       *DirectoryTree
-	rebuilding sync.Mutex // Coordinates rebuild operations to prevent concurrent rebuilds
+	treeAsOf   time.Time          // Library change time the tree was built from; zero if unknown
+	treeLoad   singleflight.Group // Concurrent first accesses share one cache load or rebuild
+	rebuilding sync.Mutex         // Coordinates rebuild operations to prevent concurrent rebuilds
 	cacheDir   string     // Directory for persistent VFS cache (optional)
+
+	// Reports when the library last changed in the database, for cache staleness (optional)
+	libraryUpdatedAt func() (time.Time, error)
+	flatMovies bool       // Also list every movie file directly under /All Movies
+	forceExt   string     // Extension of every video file name when set (e.g. ".mkv")
+
+	// Tag folders (/Tags/<tag>/) built from item metadata (optional)
+	metadataRepo *library.MetadataRepository
+	tagFolderKey string
+
+	// Collection folders (/Collections/<name>/) (optional)
+	collectionRepo *library.CollectionRepository
+
+	// Subtitle language order for marking the default sidecar (optional).
+	// Shows with their own preference override subtitleLangs.
+	showSettingsRepo *library.ShowSettingsRepository
+	subtitleLangs    []string
+
+	// poster.jpg/fanart.jpg downloads, cached under cacheDir when set
+	artwork *artworkStore
+
+	// Coalescing of full rebuilds requested via InvalidateTree
+	invalidateMu   sync.Mutex
+	pendingRebuild *time.Timer   // Non-nil while a rebuild is scheduled
+	rebuildDelay   time.Duration // Window in which invalidations are coalesced
 }
 
 // DirectoryTree represents the virtual directory structure
 type DirectoryTree struct {
 	root    *VirtualDir
-	pathMap map[string]Entry // Fast lookup by path
+	pathMap map[string]Entry  // Fast lookup by path
+	aliases map[string]string // Tag folder link path -> library folder path
 }
 
 // newEmptyTree creates a DirectoryTree with root and standard directories (Movies, TV Shows).
@@ -97,6 +160,7 @@ func newEmptyTree() (*DirectoryTree, *VirtualDir, *VirtualDir) {
 	tree := &DirectoryTree{
 		root:    NewVirtualDir("/"),
 		pathMap: make(map[string]Entry),
+		aliases: make(map[string]string),
 	}
 	tree.pathMap["/"] = tree.root
 
@@ -133,6 +197,9 @@ func NewLibraryFS(
 		movieRepo:      movieRepo,
 		showRepo:       showRepo,
 		assignmentRepo: assignmentRepo,
+		streams:        newStreamRegistry(),
+		artwork:        newArtworkStore(),
+		rebuildDelay:   DefaultRebuildDelay,
 	}
 }
 
@@ -159,6 +226,15 @@ func (fs *LibraryFS) SetTorrentService(
 	)
 }
 
+// SetReaderCallbacks configures the callbacks invoked when a streamed file
+// starts reading and when it is closed.
+func (fs *LibraryFS) SetReaderCallbacks(onAcquire, onRelease func(hash string)) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.onAcquire = onAcquire
+	fs.onRelease = onRelease
+}
+
 // SetSubtitleRepository configures subtitle support for the VFS.
 func (fs *LibraryFS) SetSubtitleRepository(repo *subtitle.Repository) {
 	fs.mu.Lock()
@@ -174,10 +250,86 @@ func (fs *LibraryFS) SetCacheDir(dir string) {
 	defer fs.mu.Unlock()
 	fs.cacheDir = dir
 	if dir != "" {
+		fs.artwork.setDir(filepath.Join(dir, artworkCacheDir))
 		slog.Info("VFS cache directory configured", "path", dir)
 	}
 }
 
+// SetSyncMetadataRepository enables discarding a persisted tree cache that is
+// older than the library's last change in the database.
+func (fs *LibraryFS) SetSyncMetadataRepository(repo *library.SyncMetadataRepository) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.libraryUpdatedAt = repo.GetLibraryUpdatedAt
+}
+
+// SetFlatMovies enables the flattened /All Movies directory.
+// This should be called before the tree is first built.
+func (fs *LibraryFS) SetFlatMovies(enabled bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.flatMovies = enabled
+	if enabled {
+		slog.Info("VFS flat movies directory enabled", "path", AllMoviesPath)
+	}
+}
+
+// SetForceExtension lists every video file with ext (e.g. ".mkv" or "m4v")
+// regardless of the torrent file's extension; the content is served unchanged.
+// An empty ext keeps the torrent file's extension. This should be called
+// before the tree is first built.
+func (fs *LibraryFS) SetForceExtension(ext string) {
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.forceExt = ext
+	if ext != "" {
+		slog.Info("VFS video extension forced", "extension", ext)
+	}
+}
+
+// SetTagFolders enables /Tags/<tag>/ folders built from the given item metadata key.
+// The metadata value is a comma-separated list of tags. An empty key disables tag folders.
+func (fs *LibraryFS) SetTagFolders(repo *library.MetadataRepository, key string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.metadataRepo = repo
+	fs.tagFolderKey = key
+	if key != "" {
+		slog.Info("VFS tag folders enabled", "path", TagsPath, "metadata_key", key)
+	}
+}
+
+// SetCollectionRepository enables /Collections/<name>/ folders built from user collections.
+func (fs *LibraryFS) SetCollectionRepository(repo *library.CollectionRepository) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.collectionRepo = repo
+	slog.Info("VFS collection folders enabled", "path", CollectionsPath)
+}
+
+// SetSubtitlePreferences configures which subtitle sidecar is marked as the
+// default track: the first in a show's preferred languages, or in defaults for
+// movies and shows without a preference.
+func (fs *LibraryFS) SetSubtitlePreferences(repo *library.ShowSettingsRepository, defaults []string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.showSettingsRepo = repo
+	fs.subtitleLangs = defaults
+}
+
+// SetRebuildDelay configures the window in which InvalidateTree calls are coalesced.
+func (fs *LibraryFS) SetRebuildDelay(delay time.Duration) {
+	fs.invalidateMu.Lock()
+	defer fs.invalidateMu.Unlock()
+	if delay < 0 {
+		delay = 0
+	}
+	fs.rebuildDelay = delay
+}
+
 // SetMetrics configures Prometheus streaming metrics for the VFS.
 func (fs *LibraryFS) SetMetrics(m *metrics.Metrics) {
 	fs.mu.Lock()
@@ -188,13 +340,21 @@ func (fs *LibraryFS) SetMetrics(m *metrics.Metrics) {
 
 // Open returns a file handle for reading
 func (fs *LibraryFS) Open(filepath string) (File, error) {
+	return fs.OpenContext(context.Background(), filepath)
+}
+
+// OpenContext is like Open, but gives up loading a torrent once ctx is done,
+// such as when the WebDAV client that asked for the file hangs up
+func (fs *LibraryFS) OpenContext(ctx context.Context, filepath string) (File, error) {
 	fs.ensureTree()
-	fs.mu.RLock()
-	defer fs.mu.RUnlock()
 
-	filepath = common.CleanPath(filepath)
+	// Resolve under the read lock but open outside it: loading a torrent can block
+	// for seconds, which would hold up a tree swap and every reader queued behind it
+	fs.mu.RLock()
+	entry, exists := fs.tree.lookup(common.CleanPath(filepath))
+	torrentService := fs.torrentService
+	fs.mu.RUnlock()
 
-	entry, exists := fs.tree.pathMap[filepath]
 	if !exists {
 		return nil, os.ErrNotExist
 	}
@@ -203,32 +363,89 @@ func (fs *LibraryFS) Open(filepath string) (File, error) {
 	case *VirtualDir:
 		return &DirFile{dir: e}, nil
 	case *PlaceholderFile:
+		// Files on local disk are read directly, with or without a torrent service
+		if e.assignment != nil && e.assignment.IsLocal() {
+			return fs.openLocal(e)
+		}
 		// If torrent service is available and file has assignment, return real torrent file
-		if fs.torrentService != nil && e.assignment != nil {
-			return fs.openTorrentFile(e)
+		if torrentService != nil && e.assignment != nil {
+			if fs.Draining() {
+				return nil, ErrDraining
+			}
+			return fs.openTorrentFile(ctx, e)
 		}
 		// Fallback: return placeholder (Stage 1 behavior)
 		return e, nil
 	case *SubtitleFile:
 		// Subtitle files are backed by local storage
+		if e.offsetMs != 0 {
+			return fs.openSubtitle(ctx, e)
+		}
 		return e, nil
 	case *TorrentSubtitleFile:
 		// Torrent-embedded subtitle: stream from torrent
-		if fs.torrentService != nil {
-			return fs.openTorrentSubtitleFile(e)
+		if torrentService != nil {
+			if fs.Draining() {
+				return nil, ErrDraining
+			}
+			return fs.openSubtitle(ctx, e)
 		}
 		return nil, os.ErrNotExist
+	case *NfoFile:
+		// Metadata rendered from library fields; each open gets its own read position
+		return e.open(), nil
+	case *ArtworkFile:
+		// Image proxied from TMDB, downloaded on first read
+		return e.open(), nil
+	case *VttSubtitleFile:
+		// SRT converted to WebVTT on first read
+		return e.open(fs.vttSourceOpener(ctx, e.source)), nil
+	default:
+		return nil, os.ErrNotExist
+	}
+}
+
+// vttSourceOpener returns a function opening the SRT behind a converted WebVTT entry
+func (fs *LibraryFS) vttSourceOpener(ctx context.Context, source Entry) func() (File, error) {
+	return func() (File, error) {
+		return fs.openSubtitle(ctx, source)
+	}
+}
+
+// openSubtitle opens a subtitle entry for reading with its timing offset applied
+func (fs *LibraryFS) openSubtitle(ctx context.Context, source Entry) (File, error) {
+	var f File
+	var offsetMs int64
+	switch s := source.(type) {
+	case *SubtitleFile:
+		// Fresh handle so reads don't share the tree entry's read position
+		f, offsetMs = s.open(), s.offsetMs
+	case *TorrentSubtitleFile:
+		if fs.torrentService == nil {
+			return nil, os.ErrNotExist
+		}
+		tf, err := fs.openTorrentSubtitleFile(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		f, offsetMs = tf, s.offsetMs
 	default:
 		return nil, os.ErrNotExist
 	}
+
+	if offsetMs != 0 {
+		return newShiftedSubtitleFile(f, offsetMs), nil
+	}
+	return f, nil
 }
 
 // openTorrentFile creates a TorrentFile for streaming from a PlaceholderFile.
-func (fs *LibraryFS) openTorrentFile(pf *PlaceholderFile) (File, error) {
+// A multi-part movie is opened as a ConcatFile over a TorrentFile per part.
+func (fs *LibraryFS) openTorrentFile(ctx context.Context, pf *PlaceholderFile) (File, error) {
 	assignment := pf.assignment
 
 	// Ensure torrent is loaded (lazy loading via GetOrAddTorrent)
-	_, err := fs.torrentService.GetOrAddTorrent(assignment.MagnetURI)
+	_, err := fs.torrentService.GetOrAddTorrent(ctx, assignment.MagnetURI)
 	if err != nil {
 		slog.Error("Failed to load torrent for file",
 			"info_hash", assignment.InfoHash,
@@ -238,31 +455,72 @@ func (fs *LibraryFS) openTorrentFile(pf *PlaceholderFile) (File, error) {
 		return nil, err
 	}
 
+	if len(assignment.PartPaths) < 2 {
+		return fs.openTorrentPart(pf.name, assignment.InfoHash, assignment.FilePath, assignment.CreatedAt)
+	}
+
+	parts := make([]File, 0, len(assignment.PartPaths))
+	for _, partPath := range assignment.PartPaths {
+		part, err := fs.openTorrentPart(pf.name, assignment.InfoHash, partPath, assignment.CreatedAt)
+		if err != nil {
+			for _, opened := range parts {
+				opened.Close()
+			}
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return NewConcatFile(pf.name, parts), nil
+}
+
+// openLocal opens the local disk file behind a placeholder
+func (fs *LibraryFS) openLocal(pf *PlaceholderFile) (File, error) {
+	f, err := openLocalFile(pf.name, pf.assignment.LocalPath)
+	if err != nil {
+		slog.Error("Failed to open local file",
+			"local_path", pf.assignment.LocalPath,
+			"error", err,
+		)
+		return nil, err
+	}
+	return f, nil
+}
+
+// openTorrentPart opens one file of a loaded torrent for streaming, reporting
+// modTime as its modification time.
+func (fs *LibraryFS) openTorrentPart(name, infoHash, filePath string, modTime time.Time) (*TorrentFile, error) {
 	// Get the specific file handle from the torrent
-	handle, err := fs.torrentService.GetFile(assignment.InfoHash, assignment.FilePath)
+	handle, err := fs.torrentService.GetFile(infoHash, filePath)
 	if err != nil {
 		slog.Error("Failed to get file from torrent",
-			"info_hash", assignment.InfoHash,
-			"file_path", assignment.FilePath,
+			"info_hash", infoHash,
+			"file_path", filePath,
 			"error", err,
 		)
 		return nil, err
 	}
 
-	return NewTorrentFile(
+	tf := NewTorrentFile(
 		handle,
-		pf.name,
-		assignment.InfoHash,
+		name,
+		infoHash,
 		fs.readTimeout,
 		fs.onActivity,
 		fs.waitForActivation,
+		fs.onRelease,
 		fs.streamingCfg,
 		fs.metrics,
-	), nil
+	)
+	tf.streams = fs.streams
+	tf.streamPath = filePath
+	tf.modTime = modTime
+	tf.onAcquire = fs.onAcquire
+	fs.trackStream(tf)
+	return tf, nil
 }
 
 // openTorrentSubtitleFile creates a TorrentFile for streaming a subtitle from a torrent.
-func (fs *LibraryFS) openTorrentSubtitleFile(tsf *TorrentSubtitleFile) (File, error) {
+func (fs *LibraryFS) openTorrentSubtitleFile(ctx context.Context, tsf *TorrentSubtitleFile) (File, error) {
 	// Look up magnet_uri from torrent_assignments using the info_hash
 	assignments, err := fs.assignmentRepo.GetByInfoHash(tsf.infoHash)
 	if err != nil || len(assignments) == 0 {
@@ -278,7 +536,7 @@ func (fs *LibraryFS) openTorrentSubtitleFile(tsf *TorrentSubtitleFile) (File, er
 	magnetURI := assignments[0].MagnetURI
 
 	// Ensure torrent is loaded (lazy loading via GetOrAddTorrent)
-	_, err = fs.torrentService.GetOrAddTorrent(magnetURI)
+	_, err = fs.torrentService.GetOrAddTorrent(ctx, magnetURI)
 	if err != nil {
 		slog.Error("Failed to load torrent for subtitle",
 			"info_hash", tsf.infoHash,
@@ -299,16 +557,63 @@ func (fs *LibraryFS) openTorrentSubtitleFile(tsf *TorrentSubtitleFile) (File, er
 		return nil, err
 	}
 
-	return NewTorrentFile(
+	tf := NewTorrentFile(
 		handle,
 		tsf.name,
 		tsf.infoHash,
 		fs.readTimeout,
 		fs.onActivity,
 		fs.waitForActivation,
+		fs.onRelease,
 		fs.streamingCfg,
 		fs.metrics,
-	), nil
+	)
+	tf.modTime = tsf.modTime
+	tf.etag = tsf.ETag()
+	tf.onAcquire = fs.onAcquire
+	fs.trackStream(tf)
+	return tf, nil
+}
+
+// trackStream counts tf as an active stream until it is closed.
+func (fs *LibraryFS) trackStream(tf *TorrentFile) {
+	fs.activeStreams.Add(1)
+	tf.onClose = func() { fs.activeStreams.Add(-1) }
+}
+
+// ActiveStreams returns the number of open torrent-backed files.
+func (fs *LibraryFS) ActiveStreams() int {
+	return int(fs.activeStreams.Load())
+}
+
+// StartDraining makes Open refuse torrent-backed files with ErrDraining.
+// Streams that are already open keep working.
+func (fs *LibraryFS) StartDraining() {
+	fs.draining.Store(true)
+}
+
+// Draining reports whether StartDraining has been called.
+func (fs *LibraryFS) Draining() bool {
+	return fs.draining.Load()
+}
+
+// WaitForDrain blocks until every open stream is closed or ctx is done,
+// returning the number of streams still open.
+func (fs *LibraryFS) WaitForDrain(ctx context.Context) int {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		active := fs.ActiveStreams()
+		if active == 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return active
+		case <-ticker.C:
+		}
+	}
 }
 
 // ReadDir returns directory contents
@@ -328,7 +633,7 @@ func (fs *LibraryFS) ReadDir(dirPath string) (map[string]File, error) {
 		return result, nil
 	}
 
-	entry, exists := fs.tree.pathMap[dirPath]
+	entry, exists := fs.tree.lookup(dirPath)
 	if !exists {
 		return nil, os.ErrNotExist
 	}
@@ -346,25 +651,47 @@ func (fs *LibraryFS) ReadDir(dirPath string) (map[string]File, error) {
 	return result, nil
 }
 
+// LoadTree builds the tree ahead of the first request, from the persistent
+// cache when it is still fresh. Call after configuration is complete.
+func (fs *LibraryFS) LoadTree() {
+	start := time.Now()
+	fs.ensureTree()
+	slog.Info("VFS tree ready", "duration", time.Since(start))
+}
+
 // ensureTree builds tree on first access only.
 // Updates are event-driven via TreeUpdater methods, no periodic rebuilds.
 // On first access, tries to load from persistent cache before rebuilding.
+// A burst of first requests waits on a single load instead of starting one each.
 func (fs *LibraryFS) ensureTree() {
 	fs.mu.RLock()
 	needsBuild := fs.tree == nil
-	cacheDir := fs.cacheDir
 	fs.mu.RUnlock()
+	if !needsBuild {
+		return
+	}
+
+	fs.treeLoad.Do("tree", func() (interface{}, error) {
+		fs.mu.RLock()
+		built := fs.tree != nil
+		cacheDir := fs.cacheDir
+		fs.mu.RUnlock()
+		if built {
+			return nil, nil // Finished by a load that ended before this one started
+		}
 
-	if needsBuild {
 		// Try loading from persistent cache first
 		if cacheDir != "" {
-			if err := fs.loadTreeFromCache(); err == nil {
-				return // Successfully loaded from cache
+			err := fs.loadTreeFromCache()
+			if err == nil {
+				return nil, nil // Successfully loaded from cache
 			}
-			// Cache miss or invalid - fall through to rebuild
+			// Cache miss, invalid or stale - fall through to rebuild
+			slog.Info("VFS cache not used, rebuilding tree", "reason", err)
 		}
 		fs.rebuildTree()
-	}
+		return nil, nil
+	})
 }
 
 // rebuildTree constructs the VFS tree from database.
@@ -383,20 +710,47 @@ func (fs *LibraryFS) rebuildTree() {
 		return
 	}
 
+	fs.buildAndSwapTree()
+}
+
+// refreshTree unconditionally rebuilds the tree from database, replacing the current one.
+// Callers block if another rebuild is in progress.
+func (fs *LibraryFS) refreshTree() {
+	fs.rebuilding.Lock()
+	defer fs.rebuilding.Unlock()
+
+	fs.buildAndSwapTree()
+}
+
+// buildAndSwapTree builds a fresh tree and swaps it in. Caller must hold fs.rebuilding.
+func (fs *LibraryFS) buildAndSwapTree() {
+	// Read before building so changes made during the build leave the cache stale
+	libraryUpdatedAt, _ := fs.currentLibraryUpdatedAt()
+
 	// Build tree completely outside the RWMutex lock
+	start := time.Now()
 	tree := fs.buildTreeFromDB()
+	elapsed := time.Since(start)
 
 	// Atomic swap - only lock for pointer assignment
 	fs.mu.Lock()
 	fs.tree = tree
+	fs.treeAsOf = libraryUpdatedAt
 	cacheDir := fs.cacheDir
+	m := fs.metrics
 	fs.mu.Unlock()
 
-	slog.Debug("VFS tree rebuilt", "entries", len(tree.pathMap))
+	if m != nil {
+		m.VFSTreeRebuilds.Inc()
+		m.VFSTreeRebuildDuration.Observe(elapsed.Seconds())
+		m.VFSTreeEntries.Set(float64(len(tree.pathMap)))
+	}
+
+	slog.Debug("VFS tree rebuilt", "entries", len(tree.pathMap), "duration", elapsed)
 
 	// Save to persistent cache (synchronous to avoid race with DeleteCache)
 	if cacheDir != "" {
-		fs.saveTreeToCache()
+		fs.saveTreeToCache(libraryUpdatedAt)
 	}
 }
 
@@ -404,6 +758,15 @@ func (fs *LibraryFS) rebuildTree() {
 // This allows concurrent reads to continue during tree construction.
 func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 	tree, moviesDir, tvDir := newEmptyTree()
+	if fs.flatMovies {
+		ensureFlatMoviesDir(tree)
+	}
+
+	// Library folder paths by item ID, for tag folders
+	moviePaths := make(map[int64]string)
+	showPaths := make(map[int64]string)
+
+	showSubtitleLangs := fs.showSubtitleLangs()
 
 	// Add movies with active assignments
 	movies, err := fs.movieRepo.ListWithAssignments()
@@ -422,9 +785,10 @@ func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 		movieDir := NewVirtualDir(folderName)
 		moviesDir.children[folderName] = movieDir
 		tree.pathMap[folderPath] = movieDir
+		moviePaths[movie.ID] = folderPath
 
 		// Add video file
-		ext := getVideoExt(movie.Assignment.FilePath)
+		ext := fs.videoExt(movie.Assignment.FilePath)
 		fileName := folderName + ext
 		filePath := folderPath + "/" + fileName
 
@@ -432,8 +796,16 @@ func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 		movieDir.children[fileName] = videoFile
 		tree.pathMap[filePath] = videoFile
 
+		if fs.flatMovies {
+			addFlatMovie(tree, folderName, ext, movie.Assignment)
+		}
+
+		// Add Kodi/Jellyfin metadata
+		addNfoToDir(tree.pathMap, movieDir, folderPath, movieNfoName, newMovieNfo(movie), movie.Assignment.CreatedAt)
+		addArtworkToDir(tree.pathMap, movieDir, folderPath, fs.artwork, movie.PosterURL, movie.BackdropURL, movie.Assignment.CreatedAt)
+
 		// Add subtitle files for this movie
-		fs.addSubtitlesToDir(tree, movieDir, folderPath, folderName, subtitle.ItemTypeMovie, movie.ID)
+		fs.addSubtitlesToDir(tree, movieDir, folderPath, folderName, subtitle.ItemTypeMovie, movie.ID, fs.subtitleLangs)
 	}
 
 	// Add TV shows with assigned episodes
@@ -449,6 +821,16 @@ func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 		showDir := NewVirtualDir(showFolderName)
 		tvDir.children[showFolderName] = showDir
 		tree.pathMap[showPath] = showDir
+		showPaths[show.ID] = showPath
+
+		addArtworkToDir(tree.pathMap, showDir, showPath, fs.artwork, show.PosterURL, show.BackdropURL, show.CreatedAt)
+		addNfoToDir(tree.pathMap, showDir, showPath, showNfoName,
+			newShowNfo(show.Title, show.Year, show.Overview, show.TMDBID), show.CreatedAt)
+
+		subtitleLangs := showSubtitleLangs[show.ID]
+		if len(subtitleLangs) == 0 {
+			subtitleLangs = fs.subtitleLangs
+		}
 
 		for _, season := range show.Seasons {
 			// Create season folder: /TV Shows/Title (Year)/Season 01/
@@ -465,7 +847,7 @@ func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 				}
 
 				// Episode file: Show - S01E05 - Name.ext
-				ext := getVideoExt(episode.Assignment.FilePath)
+				ext := fs.videoExt(episode.Assignment.FilePath)
 				fileName := makeEpisodeFileName(show.Title, season.SeasonNumber, episode.EpisodeNumber, episode.Name, ext)
 				filePath := seasonPath + "/" + fileName
 
@@ -473,23 +855,52 @@ func (fs *LibraryFS) buildTreeFromDB() *DirectoryTree {
 				seasonDir.children[fileName] = videoFile
 				tree.pathMap[filePath] = videoFile
 
-				// Add subtitle files for this episode
 				videoBaseName := strings.TrimSuffix(fileName, ext)
-				fs.addSubtitlesToDir(tree, seasonDir, seasonPath, videoBaseName, subtitle.ItemTypeEpisode, episode.ID)
+
+				// Add Kodi/Jellyfin metadata
+				addNfoToDir(tree.pathMap, seasonDir, seasonPath, makeNfoFileName(videoBaseName),
+					newEpisodeNfo(show.Title, season.SeasonNumber, &episode), episode.Assignment.CreatedAt)
+
+				// Add subtitle files for this episode
+				fs.addSubtitlesToDir(tree, seasonDir, seasonPath, videoBaseName, subtitle.ItemTypeEpisode, episode.ID, subtitleLangs)
 			}
 		}
 	}
 
+	fs.addTagFolders(tree, moviePaths, showPaths)
+	fs.addCollectionFolders(tree, moviePaths, showPaths)
+	tree.root.refreshModTime()
+
 	return tree
 }
 
-// InvalidateTree forces an immediate tree rebuild.
+// InvalidateTree schedules a full tree rebuild.
 // This is used as a fallback when targeted updates aren't possible (e.g., subtitles added).
+// Invalidations arriving within the rebuild delay are coalesced into a single rebuild.
 func (fs *LibraryFS) InvalidateTree() {
-	slog.Debug("VFS tree invalidation requested")
-	// Delete stale cache - rebuildTree will create a new one
+	// Delete stale cache - the rebuild will create a new one
 	fs.DeleteCache()
-	fs.rebuildTree()
+
+	fs.invalidateMu.Lock()
+	defer fs.invalidateMu.Unlock()
+
+	if fs.pendingRebuild != nil {
+		slog.Debug("VFS tree invalidation coalesced into pending rebuild")
+		return
+	}
+
+	slog.Debug("VFS tree invalidation requested", "rebuild_delay", fs.rebuildDelay)
+	fs.pendingRebuild = time.AfterFunc(fs.rebuildDelay, fs.runPendingRebuild)
+}
+
+// runPendingRebuild performs a scheduled rebuild. Invalidations that arrive while
+// it runs schedule a new rebuild, since this one may have read stale data.
+func (fs *LibraryFS) runPendingRebuild() {
+	fs.invalidateMu.Lock()
+	fs.pendingRebuild = nil
+	fs.invalidateMu.Unlock()
+
+	fs.refreshTree()
 }
 
 // AddMovieToTree adds a movie with its assignment to the VFS tree.
@@ -535,7 +946,7 @@ func (fs *LibraryFS) AddMovieToTree(movie *library.Movie, assignment *library.To
 		slog.Error("Movie directory not found after creation", "path", folderPath)
 		return
 	}
-	ext := getVideoExt(assignment.FilePath)
+	ext := fs.videoExt(assignment.FilePath)
 	fileName := folderName + ext
 	filePath := folderPath + "/" + fileName
 
@@ -543,6 +954,14 @@ func (fs *LibraryFS) AddMovieToTree(movie *library.Movie, assignment *library.To
 	movieDir.children[fileName] = videoFile
 	fs.tree.pathMap[filePath] = videoFile
 
+	addNfoToDir(fs.tree.pathMap, movieDir, folderPath, movieNfoName, newMovieNfo(movie), assignment.CreatedAt)
+	addArtworkToDir(fs.tree.pathMap, movieDir, folderPath, fs.artwork, movie.PosterURL, movie.BackdropURL, assignment.CreatedAt)
+
+	if fs.flatMovies {
+		addFlatMovie(fs.tree, folderName, ext, assignment)
+	}
+	fs.tree.root.refreshModTime()
+
 	slog.Debug("Added movie to VFS tree", "path", filePath)
 }
 
@@ -566,18 +985,23 @@ func (fs *LibraryFS) RemoveMovieFromTree(title string, year int) {
 
 	// Remove all children from pathMap
 	if dir, ok := movieDir.(*VirtualDir); ok {
-		for name := range dir.children {
+		for name, child := range dir.children {
+			if pf, ok := child.(*PlaceholderFile); ok && pf.assignment != nil {
+				removeFlatMovie(fs.tree, pf.assignment.ItemID)
+			}
 			delete(fs.tree.pathMap, folderPath+"/"+name)
 		}
 	}
 
 	// Remove folder from pathMap
 	delete(fs.tree.pathMap, folderPath)
+	unlinkTagFolders(fs.tree, folderPath)
 
 	// Remove from parent's children
 	if moviesDir, ok := fs.tree.pathMap[MoviesPath].(*VirtualDir); ok {
 		delete(moviesDir.children, folderName)
 	}
+	fs.tree.root.refreshModTime()
 
 	slog.Debug("Removed movie from VFS tree", "path", folderPath)
 }
@@ -605,9 +1029,17 @@ func (fs *LibraryFS) AddEpisodesToTree(episodes []EpisodeWithContext) {
 
 		showDirEntry, exists := fs.tree.pathMap[showPath]
 		if !exists {
-			showDirEntry = NewVirtualDir(showFolderName)
-			tvDir.children[showFolderName] = showDirEntry
-			fs.tree.pathMap[showPath] = showDirEntry
+			newShowDir := NewVirtualDir(showFolderName)
+			tvDir.children[showFolderName] = newShowDir
+			fs.tree.pathMap[showPath] = newShowDir
+			showTime := ep.ShowCreatedAt
+			if showTime.IsZero() {
+				showTime = ep.Assignment.CreatedAt
+			}
+			addArtworkToDir(fs.tree.pathMap, newShowDir, showPath, fs.artwork, ep.ShowPosterURL, ep.ShowBackdropURL, showTime)
+			addNfoToDir(fs.tree.pathMap, newShowDir, showPath, showNfoName,
+				newShowNfo(ep.ShowTitle, ep.ShowYear, ep.ShowOverview, ep.ShowTMDBID), showTime)
+			showDirEntry = newShowDir
 		}
 		showDir, ok := showDirEntry.(*VirtualDir)
 		if !ok {
@@ -632,7 +1064,7 @@ func (fs *LibraryFS) AddEpisodesToTree(episodes []EpisodeWithContext) {
 		}
 
 		// Add episode file
-		ext := getVideoExt(ep.Assignment.FilePath)
+		ext := fs.videoExt(ep.Assignment.FilePath)
 		fileName := makeEpisodeFileName(ep.ShowTitle, ep.SeasonNumber, ep.Episode.EpisodeNumber, ep.Episode.Name, ext)
 		filePath := seasonPath + "/" + fileName
 
@@ -640,8 +1072,77 @@ func (fs *LibraryFS) AddEpisodesToTree(episodes []EpisodeWithContext) {
 		seasonDir.children[fileName] = videoFile
 		fs.tree.pathMap[filePath] = videoFile
 
+		nfoName := makeNfoFileName(strings.TrimSuffix(fileName, ext))
+		addNfoToDir(fs.tree.pathMap, seasonDir, seasonPath, nfoName,
+			newEpisodeNfo(ep.ShowTitle, ep.SeasonNumber, ep.Episode), ep.Assignment.CreatedAt)
+
 		slog.Debug("Added episode to VFS tree", "path", filePath)
 	}
+	fs.tree.root.refreshModTime()
+}
+
+// RenameEpisodeInTree renames an episode's file and its sidecars (.nfo and
+// subtitles) after the episode name changed, rewriting the .nfo. An episode
+// not yet in the tree is added. If the tree hasn't been built yet, this is a no-op.
+func (fs *LibraryFS) RenameEpisodeInTree(ep EpisodeWithContext) {
+	fs.mu.Lock()
+	if fs.tree == nil {
+		fs.mu.Unlock()
+		return
+	}
+	renamed := fs.renameEpisodeLocked(ep)
+	fs.mu.Unlock()
+
+	if !renamed {
+		fs.AddEpisodesToTree([]EpisodeWithContext{ep})
+	}
+}
+
+// renameEpisodeLocked moves the episode's entries to their new names and
+// reports whether the episode's video was found. Callers hold fs.mu.
+func (fs *LibraryFS) renameEpisodeLocked(ep EpisodeWithContext) bool {
+	seasonPath := TVShowsPath + "/" + makeMediaFolderName(ep.ShowTitle, ep.ShowYear) + "/" + makeSeasonFolderName(ep.SeasonNumber)
+	seasonDir, ok := fs.tree.pathMap[seasonPath].(*VirtualDir)
+	if !ok {
+		return false
+	}
+
+	// Sidecars are named after the video's base name
+	prefix := makeEpisodePrefix(ep.ShowTitle, ep.SeasonNumber, ep.Episode.EpisodeNumber)
+	var oldBase string
+	for name, child := range seasonDir.children {
+		if _, ok := child.(*PlaceholderFile); ok && strings.HasPrefix(name, prefix) {
+			oldBase = strings.TrimSuffix(name, path.Ext(name))
+			break
+		}
+	}
+	if oldBase == "" {
+		return false
+	}
+	ext := fs.videoExt(ep.Assignment.FilePath)
+	newBase := strings.TrimSuffix(makeEpisodeFileName(ep.ShowTitle, ep.SeasonNumber, ep.Episode.EpisodeNumber, ep.Episode.Name, ext), ext)
+
+	moved := make(map[string]Entry)
+	for name, child := range seasonDir.children {
+		if !strings.HasPrefix(name, oldBase+".") {
+			continue
+		}
+		newName := newBase + strings.TrimPrefix(name, oldBase)
+		setEntryName(child, newName)
+		delete(seasonDir.children, name)
+		delete(fs.tree.pathMap, seasonPath+"/"+name)
+		moved[newName] = child
+	}
+	for name, child := range moved {
+		seasonDir.children[name] = child
+		fs.tree.pathMap[seasonPath+"/"+name] = child
+	}
+
+	addNfoToDir(fs.tree.pathMap, seasonDir, seasonPath, makeNfoFileName(newBase),
+		newEpisodeNfo(ep.ShowTitle, ep.SeasonNumber, ep.Episode), ep.Assignment.CreatedAt)
+
+	slog.Debug("Renamed episode in VFS tree", "path", seasonPath+"/"+newBase+ext)
+	return true
 }
 
 // RemoveEpisodeFromTree removes an episode file and cleans up empty parent folders.
@@ -683,38 +1184,95 @@ func (fs *LibraryFS) RemoveEpisodeFromTree(showTitle string, showYear int, seaso
 		return
 	}
 
-	// Find and remove the episode file (match by episode number since extension may vary)
+	// Find and remove the episode file and its sidecars (match by episode number since extension may vary)
 	prefix := makeEpisodePrefix(showTitle, seasonNumber, episodeNumber)
 
-	var fileNameToRemove string
+	var fileNamesToRemove []string
 	for name := range seasonDir.children {
 		if strings.HasPrefix(name, prefix) {
-			fileNameToRemove = name
-			break
+			fileNamesToRemove = append(fileNamesToRemove, name)
 		}
 	}
 
-	if fileNameToRemove == "" {
+	if len(fileNamesToRemove) == 0 {
 		return // File not found
 	}
 
-	filePath := seasonPath + "/" + fileNameToRemove
-	delete(fs.tree.pathMap, filePath)
-	delete(seasonDir.children, fileNameToRemove)
+	for _, name := range fileNamesToRemove {
+		filePath := seasonPath + "/" + name
+		delete(fs.tree.pathMap, filePath)
+		delete(seasonDir.children, name)
 
-	slog.Debug("Removed episode from VFS tree", "path", filePath)
+		slog.Debug("Removed episode from VFS tree", "path", filePath)
+	}
 
 	// Cleanup empty season folder
 	if len(seasonDir.children) == 0 {
 		delete(fs.tree.pathMap, seasonPath)
 		delete(showDir.children, seasonFolderName)
 
-		// Cleanup empty show folder
-		if len(showDir.children) == 0 {
-			delete(fs.tree.pathMap, showPath)
-			delete(tvDir.children, showFolderName)
+		fs.removeShowDirWithoutSeasons(tvDir, showDir, showPath)
+	}
+	fs.tree.root.refreshModTime()
+}
+
+// RemoveSeasonFromTree removes a season folder and its files, and the show
+// folder if no seasons remain. If the tree hasn't been built yet, this is a no-op.
+func (fs *LibraryFS) RemoveSeasonFromTree(showTitle string, showYear int, seasonNumber int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.tree == nil {
+		return
+	}
+
+	tvDir, ok := fs.tree.pathMap[TVShowsPath].(*VirtualDir)
+	if !ok {
+		return
+	}
+
+	showFolderName := makeMediaFolderName(showTitle, showYear)
+	showPath := TVShowsPath + "/" + showFolderName
+
+	showDir, ok := fs.tree.pathMap[showPath].(*VirtualDir)
+	if !ok {
+		return
+	}
+
+	seasonFolderName := makeSeasonFolderName(seasonNumber)
+	seasonPath := showPath + "/" + seasonFolderName
+
+	seasonDir, ok := fs.tree.pathMap[seasonPath].(*VirtualDir)
+	if !ok {
+		return
+	}
+
+	for fileName := range seasonDir.children {
+		delete(fs.tree.pathMap, seasonPath+"/"+fileName)
+	}
+	delete(fs.tree.pathMap, seasonPath)
+	delete(showDir.children, seasonFolderName)
+	defer fs.tree.root.refreshModTime()
+
+	slog.Debug("Removed season from VFS tree", "path", seasonPath)
+
+	fs.removeShowDirWithoutSeasons(tvDir, showDir, showPath)
+}
+
+// removeShowDirWithoutSeasons removes a show folder once no seasons remain
+// (only artwork and tvshow.nfo are left). Callers hold fs.mu.
+func (fs *LibraryFS) removeShowDirWithoutSeasons(tvDir, showDir *VirtualDir, showPath string) {
+	for _, entry := range showDir.children {
+		if _, isDir := entry.(*VirtualDir); isDir {
+			return
 		}
 	}
+	for name := range showDir.children {
+		delete(fs.tree.pathMap, showPath+"/"+name)
+	}
+	delete(fs.tree.pathMap, showPath)
+	delete(tvDir.children, showDir.name)
+	unlinkTagFolders(fs.tree, showPath)
 }
 
 // RemoveShowFromTree removes an entire show subtree from the VFS tree.
@@ -758,6 +1316,8 @@ func (fs *LibraryFS) RemoveShowFromTree(title string, year int) {
 	// Remove show folder
 	delete(fs.tree.pathMap, showPath)
 	delete(tvDir.children, showFolderName)
+	unlinkTagFolders(fs.tree, showPath)
+	fs.tree.root.refreshModTime()
 
 	slog.Debug("Removed show from VFS tree", "path", showPath)
 }
@@ -766,6 +1326,7 @@ func (fs *LibraryFS) RemoveShowFromTree(title string, year int) {
 type VirtualDir struct {
 	name     string
 	children map[string]Entry
+	modTime  time.Time // Latest modification time of its contents
 }
 
 func NewVirtualDir(name string) *VirtualDir {
@@ -775,6 +1336,28 @@ func NewVirtualDir(name string) *VirtualDir {
 	}
 }
 
+// refreshModTime sets the modification time of d and every directory below
+// it to the latest time among their contents, and returns d's time.
+// An emptied directory keeps its previous time.
+func (d *VirtualDir) refreshModTime() time.Time {
+	var latest time.Time
+	for _, child := range d.children {
+		var t time.Time
+		if dir, ok := child.(*VirtualDir); ok {
+			t = dir.refreshModTime()
+		} else {
+			t = entryModTime(child)
+		}
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	if !latest.IsZero() {
+		d.modTime = latest
+	}
+	return d.modTime
+}
+
 func (d *VirtualDir) Name() string { return d.name }
 func (d *VirtualDir) IsDir() bool  { return true }
 func (d *VirtualDir) Size() int64  { return 0 }
@@ -791,7 +1374,7 @@ func (f *DirFile) Read([]byte) (int, error) { return 0, os.ErrInvalid }
 func (f *DirFile) ReadAt([]byte, int64) (int, error) { return 0, os.ErrInvalid }
 func (f *DirFile) Close() error   { return nil }
 func (f *DirFile) Stat() (os.FileInfo, error) {
-	return common.NewFileInfo(f.dir.name, 0, true, time.Now()), nil
+	return common.NewFileInfo(f.dir.name, 0, true, f.dir.modTime), nil
 }
 
 // PlaceholderFile represents a file that will be backed by a torrent (in Stage 2)
@@ -821,7 +1404,15 @@ func (f *PlaceholderFile) ReadAt([]byte, int64) (int, error) {
 }
 func (f *PlaceholderFile) Close() error { return nil }
 func (f *PlaceholderFile) Stat() (os.FileInfo, error) {
-	return common.NewFileInfo(f.name, f.size, false, time.Now()), nil
+	return common.NewFileInfo(f.name, f.size, false, f.modTime()), nil
+}
+
+// modTime is when the torrent was assigned, the last time the content changed
+func (f *PlaceholderFile) modTime() time.Time {
+	if f.assignment == nil {
+		return time.Time{}
+	}
+	return f.assignment.CreatedAt
 }
 
 // GetAssignment returns the torrent assignment for this file
@@ -831,10 +1422,13 @@ func (f *PlaceholderFile) GetAssignment() *library.TorrentAssignment {
 
 // SubtitleFile represents a subtitle file backed by local storage
 type SubtitleFile struct {
-	name      string
-	localPath string
-	size      int64
-	file      *os.File // Opened file handle
+	name        string
+	localPath   string
+	size        int64
+	offsetMs    int64     // Cue timing shift applied when opened
+	modTime     time.Time // When the served content last changed
+	contentHash string    // SHA-256 of the stored content, empty if unknown
+	file        *os.File  // Opened file handle
 }
 
 func NewSubtitleFile(name, localPath string, size int64) *SubtitleFile {
@@ -845,6 +1439,18 @@ func NewSubtitleFile(name, localPath string, size int64) *SubtitleFile {
 	}
 }
 
+// open returns an independent read handle
+func (f *SubtitleFile) open() *SubtitleFile {
+	return &SubtitleFile{
+		name:        f.name,
+		localPath:   f.localPath,
+		size:        f.size,
+		offsetMs:    f.offsetMs,
+		modTime:     f.modTime,
+		contentHash: f.contentHash,
+	}
+}
+
 func (f *SubtitleFile) Name() string { return f.name }
 func (f *SubtitleFile) IsDir() bool  { return false }
 func (f *SubtitleFile) Size() int64  { return f.size }
@@ -893,15 +1499,21 @@ func (f *SubtitleFile) Close() error {
 }
 
 func (f *SubtitleFile) Stat() (os.FileInfo, error) {
-	return common.NewFileInfo(f.name, f.size, false, time.Now()), nil
+	return common.NewFileInfo(f.name, f.size, false, f.modTime), nil
 }
 
+// ETag covers the timing offset, which is applied when the file is opened
+func (f *SubtitleFile) ETag() string { return subtitleETag(f.contentHash, f.offsetMs) }
+
 // TorrentSubtitleFile represents a subtitle file embedded in a torrent
 type TorrentSubtitleFile struct {
 	name        string
 	torrentPath string // Path within the torrent
 	size        int64
 	infoHash    string
+	offsetMs    int64     // Cue timing shift applied when opened
+	modTime     time.Time // When the served content last changed
+	contentHash string    // SHA-256 of the content, empty if unknown
 }
 
 func NewTorrentSubtitleFile(name, torrentPath string, size int64, infoHash string) *TorrentSubtitleFile {
@@ -918,17 +1530,24 @@ func (f *TorrentSubtitleFile) IsDir() bool  { return false }
 func (f *TorrentSubtitleFile) Size() int64  { return f.size }
 
 func (f *TorrentSubtitleFile) Stat() (os.FileInfo, error) {
-	return common.NewFileInfo(f.name, f.size, false, time.Now()), nil
+	return common.NewFileInfo(f.name, f.size, false, f.modTime), nil
 }
 
-// makeSubtitleFileName creates a subtitle filename: "VideoName.lang.format"
-func makeSubtitleFileName(videoBaseName, langCode, format string) string {
+// ETag covers the timing offset, which is applied when the file is opened
+func (f *TorrentSubtitleFile) ETag() string { return subtitleETag(f.contentHash, f.offsetMs) }
+
+// makeSubtitleFileName creates a subtitle filename: "VideoName.lang.format",
+// or "VideoName.lang.variant.format" for forced/SDH tracks (e.g. "Video.en.forced.srt")
+func makeSubtitleFileName(videoBaseName, langCode, variant, format string) string {
+	if variant != "" {
+		return videoBaseName + "." + langCode + "." + variant + "." + format
+	}
 	return videoBaseName + "." + langCode + "." + format
 }
 
 // addSubtitlesToDir adds subtitle files for a media item to the given directory.
 // This is a helper to avoid duplicate code for movies and episodes.
-func (fs *LibraryFS) addSubtitlesToDir(tree *DirectoryTree, dir *VirtualDir, dirPath, videoBaseName string, itemType subtitle.ItemType, itemID int64) {
+func (fs *LibraryFS) addSubtitlesToDir(tree *DirectoryTree, dir *VirtualDir, dirPath, videoBaseName string, itemType subtitle.ItemType, itemID int64, preferredLangs []string) {
 	if fs.subtitleRepo == nil {
 		return
 	}
@@ -939,24 +1558,134 @@ func (fs *LibraryFS) addSubtitlesToDir(tree *DirectoryTree, dir *VirtualDir, dir
 		return
 	}
 
+	addSubtitleEntries(tree.pathMap, dir, dirPath, videoBaseName, subtitles, preferredLangs)
+}
+
+// showSubtitleLangs loads the preferred subtitle languages of every show that has them
+func (fs *LibraryFS) showSubtitleLangs() map[int64][]string {
+	if fs.showSettingsRepo == nil {
+		return nil
+	}
+
+	langs, err := fs.showSettingsRepo.PreferredSubtitleLangs()
+	if err != nil {
+		slog.Error("Failed to load show subtitle preferences", "error", err)
+		return nil
+	}
+	return langs
+}
+
+// defaultSubtitleSortKey leads the default subtitle's language tag; digits
+// sort before every language code
+const defaultSubtitleSortKey = "0"
+
+// defaultSubtitle picks the track players should select by default: a full
+// (non-forced) subtitle in the most preferred language available, favoring
+// regular over SDH. Returns nil if no subtitle is in a preferred language.
+func defaultSubtitle(subtitles []*subtitle.Subtitle, preferredLangs []string) *subtitle.Subtitle {
+	for _, lang := range preferredLangs {
+		var sdh *subtitle.Subtitle
+		for _, sub := range subtitles {
+			if sub.Forced || !strings.EqualFold(sub.LanguageCode, lang) {
+				continue
+			}
+			if !sub.HearingImpaired {
+				return sub
+			}
+			if sdh == nil {
+				sdh = sub
+			}
+		}
+		if sdh != nil {
+			return sdh
+		}
+	}
+	return nil
+}
+
+// addSubtitleEntries places subtitle sidecars next to a video, named after the video's base name.
+// Torrent subtitles keep their own info hash, so a subtitle sourced from a different torrent
+// than the video still sits beside it for player auto-pickup.
+//
+// The subtitle in the most preferred language is named to sort before the
+// others (Video.0.es.default.srt), for players that list sidecars by name, and
+// carries a "default" marker, which Jellyfin, Kodi and Plex select first.
+func addSubtitleEntries(pathMap map[string]Entry, dir *VirtualDir, dirPath, videoBaseName string, subtitles []*subtitle.Subtitle, preferredLangs []string) {
+	def := defaultSubtitle(subtitles, preferredLangs)
+
+	// SRT entries that get a parallel WebVTT entry once every real subtitle is placed
+	type vttSource struct {
+		name    string
+		source  Entry
+		modTime time.Time
+	}
+	var vttSources []vttSource
+
 	for _, sub := range subtitles {
-		subFileName := makeSubtitleFileName(videoBaseName, sub.LanguageCode, sub.Format)
+		baseName := videoBaseName
+		variant := sub.Variant()
+		if sub == def {
+			baseName += "." + defaultSubtitleSortKey
+			variant = strings.TrimPrefix(variant+".default", ".")
+		}
+
+		subFileName := makeSubtitleFileName(baseName, sub.LanguageCode, variant, sub.Format)
 		subFilePath := dirPath + "/" + subFileName
 
+		// Re-downloads and offset changes bump updated_at
+		modTime := sub.UpdatedAt
+		if modTime.IsZero() {
+			modTime = sub.CreatedAt
+		}
+
 		var subFile Entry
 		if sub.Source == subtitle.SourceTorrent {
 			// Torrent-embedded subtitle: will be streamed from torrent
-			subFile = NewTorrentSubtitleFile(subFileName, sub.FilePath, sub.FileSize, sub.InfoHash)
+			tsf := NewTorrentSubtitleFile(subFileName, sub.FilePath, sub.FileSize, sub.InfoHash)
+			tsf.offsetMs = sub.OffsetMs
+			tsf.modTime = modTime
+			tsf.contentHash = sub.ContentHash
+			subFile = tsf
 		} else {
 			// Local subtitle: backed by local storage (OpenSubtitles download)
-			subFile = NewSubtitleFile(subFileName, sub.FilePath, sub.FileSize)
+			sf := NewSubtitleFile(subFileName, sub.FilePath, sub.FileSize)
+			sf.offsetMs = sub.OffsetMs
+			sf.modTime = modTime
+			sf.contentHash = sub.ContentHash
+			subFile = sf
 		}
 
 		dir.children[subFileName] = subFile
-		tree.pathMap[subFilePath] = subFile
+		pathMap[subFilePath] = subFile
+
+		if strings.EqualFold(sub.Format, "srt") {
+			vttName := makeSubtitleFileName(baseName, sub.LanguageCode, variant, "vtt")
+			vttSources = append(vttSources, vttSource{name: vttName, source: subFile, modTime: modTime})
+		}
+	}
+
+	// Parallel WebVTT entries for SRT, for players that only take WebVTT.
+	// A real .vtt subtitle under the same name always takes precedence,
+	// whatever order the subtitles were listed in.
+	for _, v := range vttSources {
+		if existing, exists := dir.children[v.name]; exists {
+			if _, converted := existing.(*VttSubtitleFile); !converted {
+				continue
+			}
+		}
+		vttFile := newVttSubtitleFile(v.name, v.source, v.modTime)
+		dir.children[v.name] = vttFile
+		pathMap[dirPath+"/"+v.name] = vttFile
 	}
 }
 
+// addNfoToDir adds a rendered-on-read .nfo entry to a directory
+func addNfoToDir(pathMap map[string]Entry, dir *VirtualDir, dirPath, name string, meta *nfoMetadata, modTime time.Time) {
+	nfo := newNfoFile(name, meta, modTime)
+	dir.children[name] = nfo
+	pathMap[dirPath+"/"+name] = nfo
+}
+
 // Helper functions
 
 func entryToFile(e Entry) File {
@@ -967,6 +1696,12 @@ func entryToFile(e Entry) File {
 		return v
 	case *SubtitleFile:
 		return v
+	case *NfoFile:
+		return v
+	case *ArtworkFile:
+		return v
+	case *VttSubtitleFile:
+		return v
 	case *TorrentSubtitleFile:
 		// Note: TorrentSubtitleFile needs to be opened via LibraryFS.Open() to get actual torrent file
 		return nil
@@ -974,3 +1709,62 @@ func entryToFile(e Entry) File {
 		return nil
 	}
 }
+
+// setEntryName renames a file entry in place
+func setEntryName(e Entry, name string) {
+	switch v := e.(type) {
+	case *PlaceholderFile:
+		v.name = name
+	case *SubtitleFile:
+		v.name = name
+	case *TorrentSubtitleFile:
+		v.name = name
+	case *NfoFile:
+		v.name = name
+	case *ArtworkFile:
+		v.name = name
+	case *VttSubtitleFile:
+		v.name = name
+	}
+}
+
+// entryModTime returns the modification time a file entry reports
+func entryModTime(e Entry) time.Time {
+	switch v := e.(type) {
+	case *VirtualDir:
+		return v.modTime
+	case *PlaceholderFile:
+		return v.modTime()
+	case *SubtitleFile:
+		return v.modTime
+	case *TorrentSubtitleFile:
+		return v.modTime
+	case *NfoFile:
+		return v.modTime
+	case *ArtworkFile:
+		return v.modTime
+	case *VttSubtitleFile:
+		return v.modTime
+	default:
+		return time.Time{}
+	}
+}
+
+// makeETag returns a strong entity tag for the content version named by parts
+func makeETag(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// subtitleETag tags a subtitle by its content and the timing offset it is
+// served with. Returns "" when the content hash is unknown.
+func subtitleETag(contentHash string, offsetMs int64) string {
+	if contentHash == "" {
+		return ""
+	}
+	return makeETag(contentHash, strconv.FormatInt(offsetMs, 10))
+}
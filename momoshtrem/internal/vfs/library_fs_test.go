@@ -0,0 +1,555 @@
+package vfs
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+	"github.com/shapedtime/momoshtrem/internal/subtitle"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+)
+
+func TestAddSubtitleEntriesCrossTorrent(t *testing.T) {
+	const (
+		videoHash    = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		subtitleHash = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	tree, _, _ := newEmptyTree()
+	seasonPath := TVShowsPath + "/Show (2020)/Season 01"
+	seasonDir := NewVirtualDir("Season 01")
+	tree.pathMap[seasonPath] = seasonDir
+
+	assignment := &library.TorrentAssignment{
+		InfoHash: videoHash,
+		FilePath: "Show.S01E05.1080p.WEB/Show.S01E05.1080p.WEB.mkv",
+		FileSize: 1 << 30,
+	}
+	ext := getVideoExt(assignment.FilePath)
+	fileName := makeEpisodeFileName("Show", 1, 5, "Pilot", ext)
+	videoFile := NewPlaceholderFile(fileName, assignment.FileSize, assignment)
+	seasonDir.children[fileName] = videoFile
+	tree.pathMap[seasonPath+"/"+fileName] = videoFile
+
+	videoBaseName := strings.TrimSuffix(fileName, ext)
+	subs := []*subtitle.Subtitle{
+		{
+			LanguageCode: "en",
+			Format:       "srt",
+			FilePath:     "Show.Subs/Show.S01E05.en.srt",
+			FileSize:     4096,
+			Source:       subtitle.SourceTorrent,
+			InfoHash:     subtitleHash,
+		},
+		{
+			LanguageCode: "ru",
+			Format:       "srt",
+			FilePath:     "/data/subtitles/episode/1/ru.srt",
+			FileSize:     2048,
+			Source:       subtitle.SourceOpenSubtitles,
+		},
+	}
+
+	addSubtitleEntries(tree.pathMap, seasonDir, seasonPath, videoBaseName, subs, nil)
+
+	tests := []struct {
+		name     string
+		wantHash string
+	}{
+		{"Show - S01E05 - Pilot.en.srt", subtitleHash},
+		{"Show - S01E05 - Pilot.ru.srt", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := tree.pathMap[seasonPath+"/"+tt.name]
+			if !ok {
+				t.Fatalf("subtitle %q not found in tree", tt.name)
+			}
+			if seasonDir.children[tt.name] != entry {
+				t.Errorf("subtitle %q not listed in season directory", tt.name)
+			}
+			if !strings.HasPrefix(tt.name, videoBaseName+".") {
+				t.Errorf("subtitle %q does not share video base name %q", tt.name, videoBaseName)
+			}
+
+			if tt.wantHash == "" {
+				if _, ok := entry.(*SubtitleFile); !ok {
+					t.Errorf("entry type = %T, want *SubtitleFile", entry)
+				}
+				return
+			}
+			tsf, ok := entry.(*TorrentSubtitleFile)
+			if !ok {
+				t.Fatalf("entry type = %T, want *TorrentSubtitleFile", entry)
+			}
+			if tsf.infoHash != tt.wantHash {
+				t.Errorf("infoHash = %q, want %q", tsf.infoHash, tt.wantHash)
+			}
+			if tsf.infoHash == videoFile.assignment.InfoHash {
+				t.Errorf("subtitle should stream from its own torrent, got video hash")
+			}
+		})
+	}
+}
+
+func TestAddSubtitleEntriesMarksPreferredDefault(t *testing.T) {
+	tree, _, _ := newEmptyTree()
+	dirPath := MoviesPath + "/Movie (2020)"
+	dir := NewVirtualDir("Movie (2020)")
+	tree.pathMap[dirPath] = dir
+
+	subs := []*subtitle.Subtitle{
+		{LanguageCode: "en", Format: "srt", FilePath: "/subs/en.srt", Source: subtitle.SourceOpenSubtitles},
+		{LanguageCode: "es", Format: "srt", FilePath: "/subs/es.forced.srt", Source: subtitle.SourceOpenSubtitles, Forced: true},
+		{LanguageCode: "es", Format: "srt", FilePath: "/subs/es.sdh.srt", Source: subtitle.SourceOpenSubtitles, HearingImpaired: true},
+	}
+	addSubtitleEntries(tree.pathMap, dir, dirPath, "Movie (2020)", subs, []string{"es", "en"})
+
+	for _, name := range []string{
+		"Movie (2020).en.srt",
+		"Movie (2020).es.forced.srt",
+		"Movie (2020).0.es.sdh.default.srt",
+		"Movie (2020).0.es.sdh.default.vtt",
+	} {
+		if _, ok := dir.children[name]; !ok {
+			t.Errorf("subtitle %q not in directory", name)
+		}
+	}
+	if len(dir.children) != 6 {
+		t.Errorf("got %d entries, want 6 (3 srt + 3 vtt)", len(dir.children))
+	}
+
+	// The preferred subtitle lists first even though "en" sorts before "es"
+	names := slices.Sorted(maps.Keys(dir.children))
+	if names[0] != "Movie (2020).0.es.sdh.default.srt" {
+		t.Errorf("first subtitle by name = %q, want the preferred Spanish one", names[0])
+	}
+}
+
+func TestRemoveSeasonFromTree(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	var episodes []EpisodeWithContext
+	for season := 1; season <= 2; season++ {
+		episodes = append(episodes, EpisodeWithContext{
+			ShowTitle:     "Show",
+			ShowYear:      2020,
+			SeasonNumber:  season,
+			Episode:       &library.Episode{ID: int64(season), EpisodeNumber: 1, Name: "Pilot"},
+			Assignment:    &library.TorrentAssignment{FilePath: "Show.mkv", FileSize: 100},
+			ShowPosterURL: "https://image.example/poster.jpg",
+		})
+	}
+	fs.AddEpisodesToTree(episodes)
+
+	showPath := TVShowsPath + "/Show (2020)"
+	seasonPath := showPath + "/Season 01"
+	if _, ok := tree.pathMap[showPath+"/"+posterFileName]; !ok {
+		t.Fatalf("show artwork not added")
+	}
+
+	fs.RemoveSeasonFromTree("Show", 2020, 1)
+	if _, ok := tree.pathMap[seasonPath]; ok {
+		t.Fatalf("season folder still in tree")
+	}
+	for path := range tree.pathMap {
+		if strings.HasPrefix(path, seasonPath+"/") {
+			t.Errorf("season file %q still in tree", path)
+		}
+	}
+	if _, ok := tree.pathMap[showPath+"/Season 02"]; !ok {
+		t.Fatalf("other season removed")
+	}
+
+	// Last season takes the show folder (and its artwork) with it
+	fs.RemoveSeasonFromTree("Show", 2020, 2)
+	for path := range tree.pathMap {
+		if strings.HasPrefix(path, showPath) {
+			t.Errorf("%q still in tree after last season removed", path)
+		}
+	}
+}
+
+func TestTreeModTimesFollowLibrary(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetCacheDir(t.TempDir())
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	showAdded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstAssigned := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	secondAssigned := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	episode := func(number int, assigned time.Time) EpisodeWithContext {
+		return EpisodeWithContext{
+			ShowTitle:     "Show",
+			ShowYear:      2020,
+			SeasonNumber:  1,
+			Episode:       &library.Episode{ID: int64(number), EpisodeNumber: number, Name: "Episode"},
+			Assignment:    &library.TorrentAssignment{FilePath: "Show.mkv", FileSize: 100, CreatedAt: assigned},
+			ShowPosterURL: "https://image.example/poster.jpg",
+			ShowCreatedAt: showAdded,
+		}
+	}
+
+	showPath := TVShowsPath + "/Show (2020)"
+	seasonPath := showPath + "/Season 01"
+	modTime := func(path string) time.Time {
+		t.Helper()
+		entry, ok := tree.pathMap[path]
+		if !ok {
+			t.Fatalf("%q not in tree", path)
+		}
+		return entryModTime(entry)
+	}
+	nfoPath := seasonPath + "/" + makeNfoFileName(strings.TrimSuffix(makeEpisodeFileName("Show", 1, 1, "Episode", ".mkv"), ".mkv"))
+
+	fs.AddEpisodesToTree([]EpisodeWithContext{episode(1, firstAssigned)})
+	if got := modTime(showPath + "/" + posterFileName); !got.Equal(showAdded) {
+		t.Errorf("poster modTime = %v, want show created_at %v", got, showAdded)
+	}
+	if got := modTime(nfoPath); !got.Equal(firstAssigned) {
+		t.Errorf("nfo modTime = %v, want assignment created_at %v", got, firstAssigned)
+	}
+	for _, path := range []string{seasonPath, showPath, TVShowsPath, "/"} {
+		if got := modTime(path); !got.Equal(firstAssigned) {
+			t.Errorf("%q modTime = %v, want %v", path, got, firstAssigned)
+		}
+	}
+
+	// A targeted add moves every enclosing directory forward
+	fs.AddEpisodesToTree([]EpisodeWithContext{episode(2, secondAssigned)})
+	for _, path := range []string{seasonPath, showPath, TVShowsPath, "/"} {
+		if got := modTime(path); !got.Equal(secondAssigned) {
+			t.Errorf("after add, %q modTime = %v, want %v", path, got, secondAssigned)
+		}
+	}
+
+	// Times survive the cache instead of becoming the load time
+	fs.saveTreeToCache(secondAssigned)
+	loaded := NewLibraryFS(nil, nil, nil, 0)
+	loaded.SetCacheDir(fs.cacheDir)
+	loaded.libraryUpdatedAt = func() (time.Time, error) { return secondAssigned, nil }
+	if err := loaded.loadTreeFromCache(); err != nil {
+		t.Fatalf("loadTreeFromCache: %v", err)
+	}
+	for _, path := range []string{showPath + "/" + posterFileName, nfoPath, seasonPath, "/"} {
+		if got, want := entryModTime(loaded.tree.pathMap[path]), modTime(path); !got.Equal(want) {
+			t.Errorf("cached %q modTime = %v, want %v", path, got, want)
+		}
+	}
+
+	fs.RemoveEpisodeFromTree("Show", 2020, 1, 2)
+	if got := modTime(seasonPath); !got.Equal(firstAssigned) {
+		t.Errorf("after remove, season modTime = %v, want %v", got, firstAssigned)
+	}
+}
+
+func TestRenameEpisodeInTreeKeepsSidecars(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	ep := EpisodeWithContext{
+		ShowTitle:    "Show",
+		ShowYear:     2020,
+		SeasonNumber: 1,
+		Episode:      &library.Episode{ID: 1, EpisodeNumber: 1, Name: "TBA"},
+		Assignment:   &library.TorrentAssignment{FilePath: "Show.mkv", FileSize: 100},
+	}
+	fs.AddEpisodesToTree([]EpisodeWithContext{ep})
+	seasonPath := TVShowsPath + "/Show (2020)/Season 01"
+	seasonDir := tree.pathMap[seasonPath].(*VirtualDir)
+	subs := []*subtitle.Subtitle{
+		{LanguageCode: "en", Format: "srt", FilePath: "/subs/en.srt", Source: subtitle.SourceOpenSubtitles},
+		{LanguageCode: "es", Format: "srt", FilePath: "Subs/es.srt", Source: subtitle.SourceTorrent, InfoHash: "abc"},
+	}
+	addSubtitleEntries(tree.pathMap, seasonDir, seasonPath, "Show - S01E01 - TBA", subs, nil)
+	oldSub := tree.pathMap[seasonPath+"/Show - S01E01 - TBA.en.srt"]
+
+	renamed := *ep.Episode
+	renamed.Name = "Pilot"
+	ep.Episode = &renamed
+	fs.RenameEpisodeInTree(ep)
+
+	for _, name := range []string{".mkv", ".nfo", ".en.srt", ".en.vtt", ".es.srt", ".es.vtt"} {
+		if _, ok := tree.pathMap[seasonPath+"/Show - S01E01 - TBA"+name]; ok {
+			t.Errorf("old entry %q still in tree", name)
+		}
+		entry, ok := tree.pathMap[seasonPath+"/Show - S01E01 - Pilot"+name]
+		if !ok {
+			t.Errorf("renamed entry %q missing", name)
+			continue
+		}
+		if seasonDir.children["Show - S01E01 - Pilot"+name] != entry {
+			t.Errorf("renamed entry %q not listed in the season folder", name)
+		}
+		if entry.Name() != "Show - S01E01 - Pilot"+name {
+			t.Errorf("entry %q reports name %q", name, entry.Name())
+		}
+	}
+	if got := tree.pathMap[seasonPath+"/Show - S01E01 - Pilot.en.srt"]; got != oldSub {
+		t.Errorf("subtitle entry replaced instead of renamed")
+	}
+	if nfo := tree.pathMap[seasonPath+"/Show - S01E01 - Pilot.nfo"].(*NfoFile); nfo.meta.Title != "Pilot" {
+		t.Errorf("nfo title = %q, want the new episode name", nfo.meta.Title)
+	}
+	if len(seasonDir.children) != 6 {
+		t.Errorf("season folder has %d entries, want 6", len(seasonDir.children))
+	}
+
+	// An episode missing from the tree is added
+	ep.Episode = &library.Episode{ID: 2, EpisodeNumber: 2, Name: "Second"}
+	fs.RenameEpisodeInTree(ep)
+	if _, ok := tree.pathMap[seasonPath+"/Show - S01E02 - Second.mkv"]; !ok {
+		t.Errorf("episode missing from the tree was not added")
+	}
+}
+
+func TestWaitForDrain(t *testing.T) {
+	fs := &LibraryFS{}
+	tf := &TorrentFile{}
+	fs.trackStream(tf)
+	fs.StartDraining()
+
+	if !fs.Draining() || fs.ActiveStreams() != 1 {
+		t.Fatalf("draining=%v active=%d, want true and 1", fs.Draining(), fs.ActiveStreams())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if remaining := fs.WaitForDrain(ctx); remaining != 1 {
+		t.Errorf("WaitForDrain with open stream = %d, want 1", remaining)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		tf.Close()
+		tf.Close() // A second Close must not count the stream twice
+	}()
+	if remaining := fs.WaitForDrain(context.Background()); remaining != 0 {
+		t.Errorf("WaitForDrain after close = %d, want 0", remaining)
+	}
+	if fs.ActiveStreams() != 0 {
+		t.Errorf("ActiveStreams() = %d, want 0", fs.ActiveStreams())
+	}
+}
+
+func TestForceExtension(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetForceExtension("m4v")
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	fs.AddEpisodesToTree([]EpisodeWithContext{{
+		ShowTitle:    "Show",
+		ShowYear:     2020,
+		SeasonNumber: 1,
+		Episode:      &library.Episode{ID: 1, EpisodeNumber: 5, Name: "Pilot"},
+		Assignment:   &library.TorrentAssignment{ItemID: 1, FilePath: "Show.S01E05.mkv", FileSize: 1 << 30},
+	}})
+
+	seasonPath := TVShowsPath + "/Show (2020)/Season 01"
+	if _, ok := tree.pathMap[seasonPath+"/Show - S01E05 - Pilot.m4v"]; !ok {
+		t.Error("episode not listed with the forced extension")
+	}
+	if _, ok := tree.pathMap[seasonPath+"/Show - S01E05 - Pilot.mkv"]; ok {
+		t.Error("episode listed with the torrent file's extension")
+	}
+	// Sidecars share the base name without the forced extension
+	if _, ok := tree.pathMap[seasonPath+"/Show - S01E05 - Pilot.nfo"]; !ok {
+		t.Error("episode nfo not found next to the renamed file")
+	}
+
+	show := &library.Show{Title: "Show", Year: 2020}
+	episode := &library.Episode{EpisodeNumber: 5, Name: "Pilot", Assignment: &library.TorrentAssignment{FilePath: "Show.S01E05.mkv"}}
+	if got := fs.EpisodeVideoPath(show, 1, episode); got != seasonPath+"/Show - S01E05 - Pilot.m4v" {
+		t.Errorf("EpisodeVideoPath() = %q, want the listed file", got)
+	}
+}
+
+func TestSubtitleModTimeAndETag(t *testing.T) {
+	localPath := filepath.Join(t.TempDir(), "en.srt")
+	if err := os.WriteFile(localPath, []byte("1\n00:00:01,000 --> 00:00:02,000\nHi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, _, _ := newEmptyTree()
+	dirPath := MoviesPath + "/Movie (2020)"
+	dir := NewVirtualDir("Movie (2020)")
+	tree.pathMap[dirPath] = dir
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	sub := &subtitle.Subtitle{
+		LanguageCode: "en",
+		Format:       "srt",
+		FilePath:     localPath,
+		FileSize:     36,
+		Source:       subtitle.SourceOpenSubtitles,
+		ContentHash:  "abc123",
+		CreatedAt:    createdAt,
+	}
+	addSubtitleEntries(tree.pathMap, dir, dirPath, "Movie (2020)", []*subtitle.Subtitle{sub}, nil)
+	fs := &LibraryFS{tree: tree}
+
+	srtPath := dirPath + "/Movie (2020).en.srt"
+	f, err := fs.Open(srtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := f.Stat()
+	if !info.ModTime().Equal(createdAt) {
+		t.Errorf("ModTime() = %v, want subtitle created_at %v", info.ModTime(), createdAt)
+	}
+	etag := f.(ETagger).ETag()
+	if etag == "" {
+		t.Fatal("ETag() is empty for a subtitle with a content hash")
+	}
+
+	vtt := tree.pathMap[dirPath+"/Movie (2020).en.vtt"].(*VttSubtitleFile)
+	if vtt.ETag() == "" || vtt.ETag() == etag {
+		t.Errorf("vtt ETag() = %q, want one derived from but unlike %q", vtt.ETag(), etag)
+	}
+
+	// A shifted subtitle serves different content under a different tag; the
+	// opened handle must agree with the listed entry
+	tree2, _, _ := newEmptyTree()
+	dir2 := NewVirtualDir("Movie (2020)")
+	tree2.pathMap[dirPath] = dir2
+	shifted := *sub
+	shifted.OffsetMs = 1500
+	addSubtitleEntries(tree2.pathMap, dir2, dirPath, "Movie (2020)", []*subtitle.Subtitle{&shifted}, nil)
+	entry := tree2.pathMap[srtPath].(*SubtitleFile)
+	sf, err := (&LibraryFS{tree: tree2}).Open(srtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sf.(ETagger).ETag(); got != entry.ETag() || got == etag {
+		t.Errorf("shifted ETag() = %q, entry %q, unshifted %q", got, entry.ETag(), etag)
+	}
+
+	// The offset change is dated by updated_at, not by when the tree was built
+	shiftedAt := createdAt.Add(time.Hour)
+	shifted.UpdatedAt = shiftedAt
+	addSubtitleEntries(tree2.pathMap, dir2, dirPath, "Movie (2020)", []*subtitle.Subtitle{&shifted}, nil)
+	if got := tree2.pathMap[srtPath].(*SubtitleFile).modTime; !got.Equal(shiftedAt) {
+		t.Errorf("shifted ModTime() = %v, want subtitle updated_at %v", got, shiftedAt)
+	}
+}
+
+// ctxTorrents records the context torrent adds are made with
+type ctxTorrents struct {
+	torrent.Service
+	ctx context.Context
+}
+
+func (c *ctxTorrents) GetOrAddTorrent(ctx context.Context, _ string) (*torrent.TorrentInfo, error) {
+	c.ctx = ctx
+	return nil, ctx.Err()
+}
+
+func TestOpenContextPassesRequestContext(t *testing.T) {
+	tree, moviesDir, _ := newEmptyTree()
+	movieDir := NewVirtualDir("Movie (2020)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[MoviesPath+"/Movie (2020)"] = movieDir
+	assignment := &library.TorrentAssignment{
+		ItemType:  library.ItemTypeMovie,
+		ItemID:    1,
+		InfoHash:  "aaaa",
+		MagnetURI: "magnet:?xt=urn:btih:aaaa",
+		FilePath:  "Movie.2020.mkv",
+		FileSize:  1 << 30,
+	}
+	filePath := MoviesPath + "/Movie (2020)/Movie (2020).mkv"
+	tree.pathMap[filePath] = NewPlaceholderFile("Movie (2020).mkv", assignment.FileSize, assignment)
+
+	torrents := &ctxTorrents{}
+	fs := &LibraryFS{tree: tree, torrentService: torrents}
+
+	// A client that hung up doesn't keep the torrent add going
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fs.OpenContext(ctx, filePath); !errors.Is(err, context.Canceled) {
+		t.Fatalf("OpenContext() error = %v, want context.Canceled", err)
+	}
+	if torrents.ctx != ctx {
+		t.Error("torrent add did not use the caller's context")
+	}
+}
+
+func TestShowAndEpisodeNfo(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.SetCacheDir(t.TempDir())
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	fs.AddEpisodesToTree([]EpisodeWithContext{{
+		ShowTitle:    "Show",
+		ShowYear:     2020,
+		SeasonNumber: 1,
+		Episode:      &library.Episode{ID: 1, EpisodeNumber: 1, Name: "Pilot", TMDBID: 555, Overview: "Episode plot"},
+		Assignment:   &library.TorrentAssignment{FilePath: "Show.mkv", FileSize: 100},
+		ShowTMDBID:   1399,
+		ShowOverview: "Show plot",
+	}})
+
+	showPath := TVShowsPath + "/Show (2020)"
+	showNfoPath := showPath + "/" + showNfoName
+	episodeNfoPath := showPath + "/Season 01/Show - S01E01 - Pilot.nfo"
+	render := func(fs *LibraryFS, path string) string {
+		t.Helper()
+		nfo, ok := fs.tree.pathMap[path].(*NfoFile)
+		if !ok {
+			t.Fatalf("%q is not an nfo in the tree", path)
+		}
+		return string(nfo.bytes())
+	}
+
+	for path, want := range map[string][]string{
+		showNfoPath: {
+			"<tvshow>", "<title>Show</title>", "<year>2020</year>", "<plot>Show plot</plot>",
+			"<tmdbid>1399</tmdbid>", `<uniqueid type="tmdb" default="true">1399</uniqueid>`,
+		},
+		episodeNfoPath: {
+			"<episodedetails>", "<title>Pilot</title>", "<plot>Episode plot</plot>",
+			`<uniqueid type="tmdb" default="true">555</uniqueid>`,
+		},
+	} {
+		got := render(fs, path)
+		for _, w := range want {
+			if !strings.Contains(got, w) {
+				t.Errorf("%s lacks %s:\n%s", path, w, got)
+			}
+		}
+	}
+
+	// tvshow.nfo survives the tree cache
+	fs.saveTreeToCache(time.Time{})
+	loaded := NewLibraryFS(nil, nil, nil, 0)
+	loaded.SetCacheDir(fs.cacheDir)
+	loaded.libraryUpdatedAt = func() (time.Time, error) { return time.Time{}, nil }
+	if err := loaded.loadTreeFromCache(); err != nil {
+		t.Fatalf("loadTreeFromCache: %v", err)
+	}
+	if got, want := render(loaded, showNfoPath), render(fs, showNfoPath); got != want {
+		t.Errorf("cached tvshow.nfo = %s, want %s", got, want)
+	}
+
+	// Removing the last episode takes the show folder and its tvshow.nfo with it
+	fs.RemoveEpisodeFromTree("Show", 2020, 1, 1)
+	for path := range tree.pathMap {
+		if strings.HasPrefix(path, showPath) {
+			t.Errorf("%q still in tree after last episode removed", path)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package vfs
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// LocalFile is a video file on local disk, read directly instead of
+// streamed through the torrent client
+type LocalFile struct {
+	name    string
+	file    *os.File
+	size    int64
+	modTime time.Time
+}
+
+// openLocalFile opens the file behind a local assignment. Size and modification
+// time come from disk, since the file may have changed after it was assigned.
+func openLocalFile(name, localPath string) (*LocalFile, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &LocalFile{
+		name:    name,
+		file:    file,
+		size:    info.Size(),
+		modTime: info.ModTime(),
+	}, nil
+}
+
+func (f *LocalFile) Name() string { return f.name }
+func (f *LocalFile) IsDir() bool  { return false }
+func (f *LocalFile) Size() int64  { return f.size }
+
+func (f *LocalFile) Read(p []byte) (int, error) {
+	return f.file.Read(p)
+}
+
+func (f *LocalFile) ReadAt(p []byte, off int64) (int, error) {
+	return f.file.ReadAt(p, off)
+}
+
+func (f *LocalFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+func (f *LocalFile) Close() error {
+	return f.file.Close()
+}
+
+func (f *LocalFile) Stat() (os.FileInfo, error) {
+	return common.NewFileInfo(f.name, f.size, false, f.modTime), nil
+}
+
+// ETag changes when the file on disk is replaced or rewritten
+func (f *LocalFile) ETag() string {
+	return makeETag(f.file.Name(), strconv.FormatInt(f.size, 10), strconv.FormatInt(f.modTime.UnixNano(), 10))
+}
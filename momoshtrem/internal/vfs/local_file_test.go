@@ -0,0 +1,68 @@
+package vfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestOpenLocalAssignment(t *testing.T) {
+	content := []byte("local video bytes")
+	localPath := filepath.Join(t.TempDir(), "Movie.2020.1080p.mkv")
+	if err := os.WriteFile(localPath, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, moviesDir, _ := newEmptyTree()
+	movieDir := NewVirtualDir("Movie (2020)")
+	moviesDir.children["Movie (2020)"] = movieDir
+	tree.pathMap[MoviesPath+"/Movie (2020)"] = movieDir
+
+	assignment := &library.TorrentAssignment{
+		ItemType:  library.ItemTypeMovie,
+		ItemID:    1,
+		FilePath:  localPath,
+		FileSize:  int64(len(content)),
+		LocalPath: localPath,
+	}
+	filePath := MoviesPath + "/Movie (2020)/Movie (2020).mkv"
+	tree.pathMap[filePath] = NewPlaceholderFile("Movie (2020).mkv", assignment.FileSize, assignment)
+
+	// No torrent service: local files don't need one
+	fs := &LibraryFS{tree: tree}
+	f, err := fs.Open(filePath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer f.Close()
+
+	if _, ok := f.(*LocalFile); !ok {
+		t.Fatalf("Open returned %T, want *LocalFile", f)
+	}
+	if f.Size() != int64(len(content)) {
+		t.Errorf("Size() = %d, want %d", f.Size(), len(content))
+	}
+
+	buf := make([]byte, 5)
+	if _, err := f.ReadAt(buf, 6); err != nil {
+		t.Fatalf("ReadAt: %v", err)
+	}
+	if string(buf) != "video" {
+		t.Errorf("ReadAt = %q, want %q", buf, "video")
+	}
+
+	all, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(all) != string(content) {
+		t.Errorf("Read = %q, want %q", all, content)
+	}
+
+	if tag := f.(ETagger).ETag(); tag == "" {
+		t.Error("ETag() is empty")
+	}
+}
@@ -0,0 +1,262 @@
+package vfs
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// Ensure NfoFile implements File interface
+var _ File = (*NfoFile)(nil)
+
+const (
+	nfoKindMovie   = "movie"
+	nfoKindShow    = "tvshow"
+	nfoKindEpisode = "episode"
+
+	// movieNfoName is the filename Kodi and Jellyfin look for in a movie folder
+	movieNfoName = "movie.nfo"
+	// showNfoName is the filename Kodi and Jellyfin look for in a show folder
+	showNfoName = "tvshow.nfo"
+)
+
+// nfoMetadata holds the library fields rendered into a Kodi .nfo file.
+// Fields are exported so the metadata survives the gob tree cache.
+type nfoMetadata struct {
+	Kind      string
+	Title     string
+	Year      int
+	Plot      string
+	TMDBID    int
+	ShowTitle string
+	Season    int
+	Episode   int
+	Aired     string
+}
+
+// kodiUniqueID is the <uniqueid> element used by Kodi/Jellyfin scrapers
+type kodiUniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// kodiMovie is the root element of movie.nfo
+type kodiMovie struct {
+	XMLName  xml.Name      `xml:"movie"`
+	Title    string        `xml:"title"`
+	Year     int           `xml:"year,omitempty"`
+	Plot     string        `xml:"plot,omitempty"`
+	TMDBID   int           `xml:"tmdbid,omitempty"`
+	UniqueID *kodiUniqueID `xml:"uniqueid,omitempty"`
+}
+
+// kodiTVShow is the root element of tvshow.nfo
+type kodiTVShow struct {
+	XMLName  xml.Name      `xml:"tvshow"`
+	Title    string        `xml:"title"`
+	Year     int           `xml:"year,omitempty"`
+	Plot     string        `xml:"plot,omitempty"`
+	TMDBID   int           `xml:"tmdbid,omitempty"`
+	UniqueID *kodiUniqueID `xml:"uniqueid,omitempty"`
+}
+
+// kodiEpisode is the root element of an episode .nfo
+type kodiEpisode struct {
+	XMLName   xml.Name      `xml:"episodedetails"`
+	Title     string        `xml:"title"`
+	ShowTitle string        `xml:"showtitle"`
+	Season    int           `xml:"season"`
+	Episode   int           `xml:"episode"`
+	Aired     string        `xml:"aired,omitempty"`
+	Plot      string        `xml:"plot,omitempty"`
+	UniqueID  *kodiUniqueID `xml:"uniqueid,omitempty"`
+}
+
+// newMovieNfo builds nfo metadata from a library movie
+func newMovieNfo(movie *library.Movie) *nfoMetadata {
+	return &nfoMetadata{
+		Kind:   nfoKindMovie,
+		Title:  movie.Title,
+		Year:   movie.Year,
+		Plot:   movie.Overview,
+		TMDBID: movie.TMDBID,
+	}
+}
+
+// newShowNfo builds tvshow.nfo metadata from library show fields
+func newShowNfo(title string, year int, plot string, tmdbID int) *nfoMetadata {
+	return &nfoMetadata{
+		Kind:   nfoKindShow,
+		Title:  title,
+		Year:   year,
+		Plot:   plot,
+		TMDBID: tmdbID,
+	}
+}
+
+// newEpisodeNfo builds nfo metadata from a library episode
+func newEpisodeNfo(showTitle string, seasonNumber int, episode *library.Episode) *nfoMetadata {
+	meta := &nfoMetadata{
+		Kind:      nfoKindEpisode,
+		Title:     episode.Name,
+		Plot:      episode.Overview,
+		TMDBID:    episode.TMDBID,
+		ShowTitle: showTitle,
+		Season:    seasonNumber,
+		Episode:   episode.EpisodeNumber,
+	}
+	if meta.Title == "" {
+		meta.Title = "Episode " + common.Itoa(episode.EpisodeNumber)
+	}
+	if episode.AirDate != nil {
+		meta.Aired = episode.AirDate.Format("2006-01-02")
+	}
+	return meta
+}
+
+// render produces the Kodi XML document for the metadata
+func (m *nfoMetadata) render() ([]byte, error) {
+	var doc interface{}
+	switch m.Kind {
+	case nfoKindEpisode:
+		doc = kodiEpisode{
+			Title:     m.Title,
+			ShowTitle: m.ShowTitle,
+			Season:    m.Season,
+			Episode:   m.Episode,
+			Aired:     m.Aired,
+			Plot:      m.Plot,
+			UniqueID:  m.uniqueID(),
+		}
+	case nfoKindShow:
+		doc = kodiTVShow{
+			Title:    m.Title,
+			Year:     m.Year,
+			Plot:     m.Plot,
+			TMDBID:   m.TMDBID,
+			UniqueID: m.uniqueID(),
+		}
+	default:
+		doc = kodiMovie{
+			Title:    m.Title,
+			Year:     m.Year,
+			Plot:     m.Plot,
+			TMDBID:   m.TMDBID,
+			UniqueID: m.uniqueID(),
+		}
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.Write(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// uniqueID returns the TMDB <uniqueid> element, or nil if the ID is unknown
+func (m *nfoMetadata) uniqueID() *kodiUniqueID {
+	if m.TMDBID <= 0 {
+		return nil
+	}
+	return &kodiUniqueID{Type: "tmdb", Default: true, Value: strconv.Itoa(m.TMDBID)}
+}
+
+// NfoFile is a Kodi/Jellyfin metadata file rendered from library fields.
+// Content is generated lazily on first Size/Read and then reused.
+type NfoFile struct {
+	name string
+	meta *nfoMetadata
+
+	once    *sync.Once
+	content *[]byte
+	modTime time.Time // When the described item was assigned
+	pos     int64
+}
+
+func newNfoFile(name string, meta *nfoMetadata, modTime time.Time) *NfoFile {
+	var content []byte
+	return &NfoFile{
+		name:    name,
+		meta:    meta,
+		once:    &sync.Once{},
+		content: &content,
+		modTime: modTime,
+	}
+}
+
+// open returns an independent read handle sharing the rendered content
+func (f *NfoFile) open() *NfoFile {
+	return &NfoFile{
+		name:    f.name,
+		meta:    f.meta,
+		once:    f.once,
+		content: f.content,
+		modTime: f.modTime,
+	}
+}
+
+// bytes renders the document on first use
+func (f *NfoFile) bytes() []byte {
+	f.once.Do(func() {
+		content, err := f.meta.render()
+		if err != nil {
+			slog.Error("Failed to render nfo", "name", f.name, "error", err)
+			return
+		}
+		*f.content = content
+	})
+	return *f.content
+}
+
+func (f *NfoFile) Name() string { return f.name }
+func (f *NfoFile) IsDir() bool  { return false }
+func (f *NfoFile) Size() int64  { return int64(len(f.bytes())) }
+
+func (f *NfoFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *NfoFile) ReadAt(p []byte, off int64) (int, error) {
+	content := f.bytes()
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+	if off >= int64(len(content)) {
+		return 0, io.EOF
+	}
+	n := copy(p, content[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *NfoFile) Close() error { return nil }
+
+func (f *NfoFile) Stat() (os.FileInfo, error) {
+	return common.NewFileInfo(f.name, f.Size(), false, f.modTime), nil
+}
+
+// ETag hashes the rendered document
+func (f *NfoFile) ETag() string { return makeETag(string(f.bytes())) }
+
+// makeNfoFileName creates the nfo filename for a video: "VideoName.nfo"
+func makeNfoFileName(videoBaseName string) string {
+	return videoBaseName + ".nfo"
+}
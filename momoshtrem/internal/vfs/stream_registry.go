@@ -0,0 +1,150 @@
+package vfs
+
+import (
+	"sort"
+	"sync"
+
+	anacrolix "github.com/anacrolix/torrent"
+
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+)
+
+// StreamBufferStatus is the buffer state of one open stream
+type StreamBufferStatus struct {
+	FilePath string `json:"file_path"`
+	streaming.BufferStatus
+}
+
+// StreamInspector reports the buffer state of open streams for diagnostics.
+// This interface is implemented by LibraryFS and used by the API server.
+type StreamInspector interface {
+	// BufferStatus returns one entry per open stream of the torrent,
+	// limited to filePath when it is non-empty
+	BufferStatus(infoHash, filePath string) []StreamBufferStatus
+}
+
+// streamKey identifies a torrent file being streamed
+type streamKey struct {
+	infoHash string
+	filePath string
+}
+
+// streamRegistry tracks open priority readers so their buffers can be inspected.
+// A file may be open several times (e.g. player plus thumbnailer).
+type streamRegistry struct {
+	mu      sync.Mutex
+	readers map[streamKey]map[*streaming.PriorityReader]struct{}
+	files   map[streamKey]*anacrolix.File // For mapping cache evictions to open streams
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{
+		readers: make(map[streamKey]map[*streaming.PriorityReader]struct{}),
+		files:   make(map[streamKey]*anacrolix.File),
+	}
+}
+
+// add registers an open reader of file. Safe on a nil registry.
+func (r *streamRegistry) add(key streamKey, reader *streaming.PriorityReader, file *anacrolix.File) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	set, ok := r.readers[key]
+	if !ok {
+		set = make(map[*streaming.PriorityReader]struct{})
+		r.readers[key] = set
+	}
+	set[reader] = struct{}{}
+	if file != nil {
+		r.files[key] = file
+	}
+}
+
+// remove unregisters a closed reader. Safe on a nil registry.
+func (r *streamRegistry) remove(key streamKey, reader *streaming.PriorityReader) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.readers[key], reader)
+	if len(r.readers[key]) == 0 {
+		delete(r.readers, key)
+		delete(r.files, key)
+	}
+}
+
+// pieceHashes returns the hex hashes of every piece of the streamed files
+func (r *streamRegistry) pieceHashes() map[string]struct{} {
+	r.mu.Lock()
+	files := make([]*anacrolix.File, 0, len(r.files))
+	for _, f := range r.files {
+		files = append(files, f)
+	}
+	r.mu.Unlock()
+
+	hashes := make(map[string]struct{})
+	for _, f := range files {
+		info := f.Torrent().Info()
+		if info == nil {
+			continue
+		}
+		for i := f.BeginPieceIndex(); i < f.EndPieceIndex(); i++ {
+			if h := info.Piece(i).V1Hash(); h.Ok {
+				hashes[h.Value.HexString()] = struct{}{}
+			}
+		}
+	}
+	return hashes
+}
+
+// status snapshots the readers of a torrent, optionally for a single file
+func (r *streamRegistry) status(infoHash, filePath string) []StreamBufferStatus {
+	r.mu.Lock()
+	var keys []streamKey
+	var readers []*streaming.PriorityReader
+	for key, set := range r.readers {
+		if key.infoHash != infoHash || (filePath != "" && key.filePath != filePath) {
+			continue
+		}
+		for reader := range set {
+			keys = append(keys, key)
+			readers = append(readers, reader)
+		}
+	}
+	r.mu.Unlock()
+
+	// Query outside the registry lock; BufferStatus takes the prioritizer lock
+	result := make([]StreamBufferStatus, len(readers))
+	for i, reader := range readers {
+		result[i] = StreamBufferStatus{
+			FilePath:     keys[i].filePath,
+			BufferStatus: reader.BufferStatus(),
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FilePath != result[j].FilePath {
+			return result[i].FilePath < result[j].FilePath
+		}
+		return result[i].Offset < result[j].Offset
+	})
+	return result
+}
+
+// BufferStatus implements StreamInspector
+func (fs *LibraryFS) BufferStatus(infoHash, filePath string) []StreamBufferStatus {
+	return fs.streams.status(infoHash, filePath)
+}
+
+// StreamingPieceHashes returns the hex hashes of the pieces of every file
+// open for streaming, to tell piece cache evictions that hit playback.
+func (fs *LibraryFS) StreamingPieceHashes() map[string]struct{} {
+	return fs.streams.pieceHashes()
+}
+
+// Compile-time verification
+var _ StreamInspector = (*LibraryFS)(nil)
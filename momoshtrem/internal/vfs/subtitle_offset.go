@@ -0,0 +1,146 @@
+package vfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// Ensure shiftedSubtitleFile implements File interface
+var _ File = (*shiftedSubtitleFile)(nil)
+
+// cueTimestamp matches an SRT (00:01:02,500) or WebVTT (00:01:02.500, 01:02.500) timestamp
+var cueTimestamp = regexp.MustCompile(`(?:(\d{2,}):)?(\d{2}):(\d{2})([,.])(\d{3})`)
+
+// shiftCueTimestamps moves every timestamp on cue timing lines ("start --> end")
+// by offsetMs. Timestamps keep their form and width, so SRT content keeps its
+// length; times that would fall before zero clamp to 00:00:00,000.
+func shiftCueTimestamps(src []byte, offsetMs int64) []byte {
+	if offsetMs == 0 {
+		return src
+	}
+
+	out := make([]byte, 0, len(src))
+	for _, line := range bytes.SplitAfter(src, []byte("\n")) {
+		if bytes.Contains(line, []byte("-->")) {
+			line = cueTimestamp.ReplaceAllFunc(line, func(ts []byte) []byte {
+				return shiftTimestamp(ts, offsetMs)
+			})
+		}
+		out = append(out, line...)
+	}
+	return out
+}
+
+// shiftTimestamp shifts a single timestamp matched by cueTimestamp
+func shiftTimestamp(ts []byte, offsetMs int64) []byte {
+	m := cueTimestamp.FindSubmatch(ts)
+	hours, _ := strconv.ParseInt(string(m[1]), 10, 64) // Absent in short WebVTT form
+	minutes, _ := strconv.ParseInt(string(m[2]), 10, 64)
+	seconds, _ := strconv.ParseInt(string(m[3]), 10, 64)
+	millis, _ := strconv.ParseInt(string(m[5]), 10, 64)
+
+	total := ((hours*60+minutes)*60+seconds)*1000 + millis + offsetMs
+	if total < 0 {
+		total = 0
+	}
+
+	hh := total / 3_600_000
+	mm := total / 60_000 % 60
+	ss := total / 1000 % 60
+	ms := total % 1000
+	sep := m[4]
+
+	if len(m[1]) == 0 && hh == 0 {
+		return fmt.Appendf(nil, "%02d:%02d%s%03d", mm, ss, sep, ms)
+	}
+	return fmt.Appendf(nil, "%0*d:%02d:%02d%s%03d", max(len(m[1]), 2), hh, mm, ss, sep, ms)
+}
+
+// shiftedSubtitleFile serves a subtitle with its cue timing offset applied.
+// The source is read and shifted on first read; each open gets its own handle.
+type shiftedSubtitleFile struct {
+	source   File
+	offsetMs int64
+	content  []byte
+	loaded   bool
+	pos      int64
+}
+
+func newShiftedSubtitleFile(source File, offsetMs int64) *shiftedSubtitleFile {
+	return &shiftedSubtitleFile{source: source, offsetMs: offsetMs}
+}
+
+// bytes returns the shifted content, reading the source if needed
+func (f *shiftedSubtitleFile) bytes() ([]byte, error) {
+	if f.loaded {
+		return f.content, nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f.source, maxVttSourceSize))
+	if err != nil {
+		return nil, err
+	}
+	f.content = shiftCueTimestamps(data, f.offsetMs)
+	f.loaded = true
+	return f.content, nil
+}
+
+func (f *shiftedSubtitleFile) Name() string { return f.source.Name() }
+func (f *shiftedSubtitleFile) IsDir() bool  { return false }
+
+// Size is the source size until read; shifting only changes it for short-form
+// WebVTT timestamps that cross the hour mark.
+func (f *shiftedSubtitleFile) Size() int64 {
+	if f.loaded {
+		return int64(len(f.content))
+	}
+	return f.source.Size()
+}
+
+func (f *shiftedSubtitleFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *shiftedSubtitleFile) ReadAt(p []byte, off int64) (int, error) {
+	content, err := f.bytes()
+	if err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+	if off >= int64(len(content)) {
+		return 0, io.EOF
+	}
+	n := copy(p, content[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *shiftedSubtitleFile) Close() error { return f.source.Close() }
+
+func (f *shiftedSubtitleFile) Stat() (os.FileInfo, error) {
+	info, err := f.source.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return common.NewFileInfo(f.Name(), f.Size(), false, info.ModTime()), nil
+}
+
+// ETag is the source's, which already covers the offset
+func (f *shiftedSubtitleFile) ETag() string {
+	if t, ok := f.source.(ETagger); ok {
+		return t.ETag()
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package vfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShiftCueTimestamps(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		offsetMs int64
+		want     string
+	}{
+		{
+			name:     "srt forward",
+			src:      "1\n00:00:01,000 --> 00:00:03,500\nHello\n",
+			offsetMs: 2500,
+			want:     "1\n00:00:03,500 --> 00:00:06,000\nHello\n",
+		},
+		{
+			name:     "srt backward clamps at zero",
+			src:      "1\r\n00:00:01,000 --> 00:00:03,500\r\n",
+			offsetMs: -2500,
+			want:     "1\r\n00:00:00,000 --> 00:00:01,000\r\n",
+		},
+		{
+			name:     "carries into hours",
+			src:      "00:59:59,500 --> 01:00:00,000",
+			offsetMs: 700,
+			want:     "01:00:00,200 --> 01:00:00,700",
+		},
+		{
+			name:     "vtt short form with cue settings",
+			src:      "WEBVTT\n\n01:02.500 --> 01:04.000 align:start\n12:34.567 is not timing\n",
+			offsetMs: -1000,
+			want:     "WEBVTT\n\n01:01.500 --> 01:03.000 align:start\n12:34.567 is not timing\n",
+		},
+		{
+			name:     "zero offset unchanged",
+			src:      "00:00:01,000 --> 00:00:02,000",
+			offsetMs: 0,
+			want:     "00:00:01,000 --> 00:00:02,000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(shiftCueTimestamps([]byte(tt.src), tt.offsetMs))
+			if got != tt.want {
+				t.Errorf("shiftCueTimestamps() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftedSubtitleFileKeepsSize(t *testing.T) {
+	src := "1\n00:00:01,000 --> 00:00:02,000\nHi\n"
+	path := filepath.Join(t.TempDir(), "a.srt")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f := newShiftedSubtitleFile(NewSubtitleFile("a.srt", path, int64(len(src))), -1500)
+	defer f.Close()
+
+	sizeBefore := f.Size()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if int64(len(data)) != sizeBefore {
+		t.Errorf("read %d bytes, Size() reported %d", len(data), sizeBefore)
+	}
+	if !strings.Contains(string(data), "00:00:00,000 --> 00:00:00,500") {
+		t.Errorf("shifted content = %q", data)
+	}
+}
@@ -0,0 +1,131 @@
+package vfs
+
+import (
+	"log/slog"
+	"path"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+// lookup resolves a path, following tag and collection folder aliases into the library tree.
+// These folders link to the same movie/show directories as /Movies and /TV Shows,
+// so only the linked folder itself is registered in pathMap; deeper paths are
+// resolved against the original location.
+func (t *DirectoryTree) lookup(p string) (Entry, bool) {
+	if entry, ok := t.pathMap[p]; ok {
+		return entry, true
+	}
+	for prefix := path.Dir(p); prefix != "/" && prefix != "."; prefix = path.Dir(prefix) {
+		if target, ok := t.aliases[prefix]; ok {
+			entry, ok := t.pathMap[target+p[len(prefix):]]
+			return entry, ok
+		}
+	}
+	return nil, false
+}
+
+// ensureGroupDir returns the folder for a group (tag or collection) under a top-level
+// root such as /Tags, creating the root and the group folder as needed.
+// Returns nil if the group name can't be used as a folder name.
+func ensureGroupDir(tree *DirectoryTree, rootPath, group string) (*VirtualDir, string) {
+	name := library.SanitizeFilename(group)
+	if name == "" || name == "." || name == ".." {
+		return nil, ""
+	}
+
+	rootDir, ok := tree.pathMap[rootPath].(*VirtualDir)
+	if !ok {
+		rootName := path.Base(rootPath)
+		rootDir = NewVirtualDir(rootName)
+		tree.root.children[rootName] = rootDir
+		tree.pathMap[rootPath] = rootDir
+	}
+
+	groupPath := rootPath + "/" + name
+	if dir, ok := tree.pathMap[groupPath].(*VirtualDir); ok {
+		return dir, groupPath
+	}
+	dir := NewVirtualDir(name)
+	rootDir.children[name] = dir
+	tree.pathMap[groupPath] = dir
+	return dir, groupPath
+}
+
+// linkGroupFolder lists a library folder (e.g. /Movies/Title (Year)) inside a
+// group folder such as /Tags/<tag> or /Collections/<name>
+func linkGroupFolder(tree *DirectoryTree, rootPath, group, targetPath string) {
+	target, ok := tree.pathMap[targetPath].(*VirtualDir)
+	if !ok {
+		return // Item has nothing streamable, so it isn't in the tree
+	}
+
+	groupDir, groupPath := ensureGroupDir(tree, rootPath, group)
+	if groupDir == nil {
+		return
+	}
+	aliasPath := groupPath + "/" + target.name
+	groupDir.children[target.name] = target
+	tree.pathMap[aliasPath] = target
+	tree.aliases[aliasPath] = targetPath
+}
+
+// linkTagFolder lists a library folder inside a tag folder
+func linkTagFolder(tree *DirectoryTree, tag, targetPath string) {
+	linkGroupFolder(tree, TagsPath, tag, targetPath)
+}
+
+// unlinkTagFolders removes every tag and collection folder link pointing at
+// targetPath, dropping group folders that become empty.
+func unlinkTagFolders(tree *DirectoryTree, targetPath string) {
+	for aliasPath, target := range tree.aliases {
+		if target != targetPath {
+			continue
+		}
+		delete(tree.aliases, aliasPath)
+		delete(tree.pathMap, aliasPath)
+
+		groupPath := path.Dir(aliasPath)
+		groupDir, ok := tree.pathMap[groupPath].(*VirtualDir)
+		if !ok {
+			continue
+		}
+		delete(groupDir.children, path.Base(aliasPath))
+		if len(groupDir.children) == 0 {
+			delete(tree.pathMap, groupPath)
+			if rootDir, ok := tree.pathMap[path.Dir(groupPath)].(*VirtualDir); ok {
+				delete(rootDir.children, groupDir.name)
+			}
+		}
+	}
+}
+
+// addTagFolders builds /Tags/<tag>/ folders from item metadata.
+// The metadata value under the configured key is a comma-separated list of tags.
+// Tag folders are refreshed on full rebuilds (metadata changes invalidate the tree).
+func (fs *LibraryFS) addTagFolders(tree *DirectoryTree, moviePaths, showPaths map[int64]string) {
+	if fs.metadataRepo == nil || fs.tagFolderKey == "" {
+		return
+	}
+
+	entries, err := fs.metadataRepo.ListByKey(fs.tagFolderKey, "")
+	if err != nil {
+		slog.Error("Failed to list tags for VFS", "key", fs.tagFolderKey, "error", err)
+		return
+	}
+
+	for _, e := range entries {
+		var targetPath string
+		switch e.ItemType {
+		case library.ItemTypeMovie:
+			targetPath = moviePaths[e.ItemID]
+		case library.ItemTypeShow:
+			targetPath = showPaths[e.ItemID]
+		}
+		if targetPath == "" {
+			continue
+		}
+		for _, tag := range library.SplitTags(e.Value) {
+			linkTagFolder(tree, tag, targetPath)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package vfs
+
+import (
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestTagFolderLookupAndUnlink(t *testing.T) {
+	tree, moviesDir, _ := newEmptyTree()
+
+	folderPath := MoviesPath + "/Movie (2020)"
+	movieDir := NewVirtualDir("Movie (2020)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[folderPath] = movieDir
+
+	video := NewPlaceholderFile("Movie (2020).mkv", 100, &library.TorrentAssignment{ItemID: 1})
+	movieDir.children[video.name] = video
+	tree.pathMap[folderPath+"/"+video.name] = video
+
+	linkTagFolder(tree, "kids", folderPath)
+	linkTagFolder(tree, "rewatch", folderPath)
+	linkTagFolder(tree, "..", folderPath) // Unusable as a folder name
+
+	tests := []struct {
+		path string
+		want Entry
+	}{
+		{TagsPath + "/kids/Movie (2020)", movieDir},
+		{TagsPath + "/kids/Movie (2020)/Movie (2020).mkv", video},
+		{TagsPath + "/rewatch/Movie (2020)/Movie (2020).mkv", video},
+		{TagsPath + "/kids/Movie (2020)/missing.mkv", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := tree.lookup(tt.path)
+			if ok != (tt.want != nil) || got != tt.want {
+				t.Errorf("lookup(%q) = %v, %v; want %v", tt.path, got, ok, tt.want)
+			}
+		})
+	}
+
+	tagsDir := tree.pathMap[TagsPath].(*VirtualDir)
+	if len(tagsDir.children) != 2 {
+		t.Errorf("tags dir has %d entries, want 2", len(tagsDir.children))
+	}
+
+	unlinkTagFolders(tree, folderPath)
+	if len(tagsDir.children) != 0 {
+		t.Errorf("tags dir has %d entries after unlink, want 0", len(tagsDir.children))
+	}
+	if _, ok := tree.lookup(TagsPath + "/kids/Movie (2020)/Movie (2020).mkv"); ok {
+		t.Errorf("unlinked tag path still resolves")
+	}
+}
+
+func TestCollectionFoldersShareEntries(t *testing.T) {
+	tree, moviesDir, _ := newEmptyTree()
+
+	folderPath := MoviesPath + "/Iron Man (2008)"
+	movieDir := NewVirtualDir("Iron Man (2008)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[folderPath] = movieDir
+
+	video := NewPlaceholderFile("Iron Man (2008).mkv", 100, &library.TorrentAssignment{ItemID: 1})
+	movieDir.children[video.name] = video
+	tree.pathMap[folderPath+"/"+video.name] = video
+
+	linkTagFolder(tree, "marvel", folderPath)
+	linkGroupFolder(tree, CollectionsPath, "Marvel", folderPath)
+
+	got, ok := tree.lookup(CollectionsPath + "/Marvel/Iron Man (2008)/Iron Man (2008).mkv")
+	if !ok || got != video {
+		t.Fatalf("collection lookup = %v, %v; want shared placeholder file", got, ok)
+	}
+	if _, ok := tree.root.children["Collections"]; !ok {
+		t.Fatalf("Collections dir not created at root")
+	}
+
+	unlinkTagFolders(tree, folderPath)
+	if _, ok := tree.pathMap[CollectionsPath+"/Marvel"]; ok {
+		t.Errorf("empty collection folder not removed")
+	}
+	if _, ok := tree.pathMap[TagsPath+"/marvel"]; ok {
+		t.Errorf("empty tag folder not removed")
+	}
+}
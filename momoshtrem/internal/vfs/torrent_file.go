@@ -17,6 +17,9 @@ import (
 // Ensure TorrentFile implements File interface
 var _ File = (*TorrentFile)(nil)
 
+// Ensure TorrentFile can serve HTTP range requests via http.ServeContent
+var _ io.ReadSeeker = (*TorrentFile)(nil)
+
 // Buffer size classes for pooling. Each class covers reads up to that size.
 // Reads larger than the largest class fall back to direct allocation.
 const (
@@ -97,6 +100,10 @@ type TorrentFile struct {
 	hash        string
 	readTimeout time.Duration
 
+	// Reported by Stat and the WebDAV layer for conditional requests
+	modTime time.Time // When the torrent was assigned
+	etag    string    // Content ETag, empty to derive one from modTime and size
+
 	// Streaming optimization config
 	streamingCfg streaming.Config
 
@@ -106,6 +113,11 @@ type TorrentFile struct {
 	// Callback to wait for torrent activation (peers connected)
 	waitForActivation func(hash string, timeout time.Duration) error
 
+	// Acquire callback invoked when the reader is created, paired with
+	// onRelease on Close so the torrent goes idle after its last reader closes
+	onAcquire func(hash string)
+	onRelease func(hash string)
+
 	// Track if this is the first read (for activation wait)
 	firstRead bool
 
@@ -116,6 +128,13 @@ type TorrentFile struct {
 
 	// Prometheus streaming metrics (nil when metrics disabled)
 	metrics *metrics.Metrics
+
+	// Registry of open streams for buffer diagnostics (nil = not tracked)
+	streams    *streamRegistry
+	streamPath string // File path within the torrent, as assigned
+
+	// Invoked once on the first Close, for the LibraryFS open-stream count
+	onClose func()
 }
 
 // NewTorrentFile creates a new TorrentFile.
@@ -126,6 +145,7 @@ func NewTorrentFile(
 	readTimeout time.Duration,
 	onActivity func(hash string),
 	waitForActivation func(hash string, timeout time.Duration) error,
+	onRelease func(hash string),
 	streamingCfg streaming.Config,
 	m *metrics.Metrics,
 ) *TorrentFile {
@@ -139,6 +159,7 @@ func NewTorrentFile(
 		readTimeout:       readTimeout,
 		onActivity:        onActivity,
 		waitForActivation: waitForActivation,
+		onRelease:         onRelease,
 		streamingCfg:      streamingCfg,
 		firstRead:         true,
 		metrics:           m,
@@ -170,6 +191,9 @@ func (f *TorrentFile) ensureReader() {
 			OnDowngrade: func(count int) {
 				f.metrics.StreamingPiecesDowngraded.Add(float64(count))
 			},
+			OnIndexWait: func(d time.Duration, result string) {
+				f.metrics.StreamingIndexWait.WithLabelValues(result).Observe(d.Seconds())
+			},
 		}
 	}
 
@@ -181,6 +205,10 @@ func (f *TorrentFile) ensureReader() {
 		onActivity,
 		callbacks,
 	)
+	f.streams.add(streamKey{f.hash, f.streamPath}, f.reader, f.handle.File())
+	if f.onAcquire != nil {
+		f.onAcquire(f.hash)
+	}
 }
 
 // markActivity notifies the activity manager that this torrent is being accessed.
@@ -207,9 +235,12 @@ func (f *TorrentFile) Size() int64 {
 
 // Stat returns file info.
 func (f *TorrentFile) Stat() (os.FileInfo, error) {
-	return common.NewFileInfo(f.name, f.handle.Length(), false, time.Now()), nil
+	return common.NewFileInfo(f.name, f.handle.Length(), false, f.modTime), nil
 }
 
+// ETag returns the content ETag set by the opener, if any.
+func (f *TorrentFile) ETag() string { return f.etag }
+
 // Read reads up to len(p) bytes into p with timeout.
 func (f *TorrentFile) Read(p []byte) (int, error) {
 	f.mu.Lock()
@@ -223,6 +254,9 @@ func (f *TorrentFile) Read(p []byte) (int, error) {
 }
 
 // ReadAt reads len(p) bytes at offset off with timeout.
+// WebDAV serves HTTP Range requests through ReadAt, so repositioning the reader
+// here also hints the prioritizer to fetch the pieces at off first. Concurrent
+// ReadAt calls are serialized by f.mu.
 func (f *TorrentFile) ReadAt(p []byte, off int64) (int, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -231,12 +265,41 @@ func (f *TorrentFile) ReadAt(p []byte, off int64) (int, error) {
 	f.markActivity()
 	f.waitForFirstAccess()
 
-	// Seek to offset
+	// Single context for the whole operation, including waiting out a timed-out read
+	ctx, cancel := context.WithTimeout(context.Background(), f.readTimeout)
+	defer cancel()
+
+	// A timed-out read goroutine still holds the reader's lock; seeking before it
+	// finishes would block without honoring the read timeout.
+	if err := f.drainPendingRead(ctx); err != nil {
+		return 0, err
+	}
+
+	// Seek to offset (updates piece priorities around off, including backward scrubs)
 	if _, err := f.reader.Seek(off, io.SeekStart); err != nil {
 		return 0, err
 	}
 
-	return f.readAtLeast(p, len(p))
+	return f.readAtLeast(ctx, p, len(p))
+}
+
+// Seek sets the offset for the next Read and reprioritizes pieces around it,
+// so a range request starts fetching from its first byte before reading.
+func (f *TorrentFile) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ensureReader()
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.readTimeout)
+	defer cancel()
+
+	// Seeking blocks on the reader's lock while a timed-out read still holds it
+	if err := f.drainPendingRead(ctx); err != nil {
+		return 0, err
+	}
+
+	return f.reader.Seek(offset, whence)
 }
 
 // Close closes the reader.
@@ -247,10 +310,19 @@ func (f *TorrentFile) Close() error {
 	if f.metrics != nil {
 		f.metrics.StreamingOpenFiles.Dec()
 	}
+	if f.onClose != nil {
+		f.onClose()
+		f.onClose = nil
+	}
 
 	if f.reader != nil {
+		// Closing the reader downgrades its prioritized pieces
+		f.streams.remove(streamKey{f.hash, f.streamPath}, f.reader)
 		err := f.reader.Close()
 		f.reader = nil
+		if f.onRelease != nil {
+			f.onRelease(f.hash)
+		}
 		return err
 	}
 	return nil
@@ -292,15 +364,11 @@ func (f *TorrentFile) waitForFirstAccess() {
 // readAtLeast reads at least min bytes with timeout.
 // Uses a single context for the entire operation to avoid overhead of
 // creating new contexts per iteration.
-func (f *TorrentFile) readAtLeast(buf []byte, min int) (n int, err error) {
+func (f *TorrentFile) readAtLeast(ctx context.Context, buf []byte, min int) (n int, err error) {
 	if len(buf) < min {
 		return 0, io.ErrShortBuffer
 	}
 
-	// Create a single context for the entire read operation
-	ctx, cancel := context.WithTimeout(context.Background(), f.readTimeout)
-	defer cancel()
-
 	for n < min && err == nil {
 		var nn int
 		nn, err = f.readContext(ctx, buf[n:])
@@ -316,6 +384,21 @@ func (f *TorrentFile) readAtLeast(buf []byte, min int) (n int, err error) {
 	return
 }
 
+// drainPendingRead waits for a read goroutine left over from a previous timeout.
+func (f *TorrentFile) drainPendingRead(ctx context.Context) error {
+	if f.pendingRead == nil {
+		return nil
+	}
+	select {
+	case r := <-f.pendingRead:
+		returnBuffer(r.pooled)
+		f.pendingRead = nil
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // readContext reads using a context for timeout/cancellation.
 //
 // A private buffer is used instead of the caller's slice to prevent a data
@@ -330,15 +413,8 @@ func (f *TorrentFile) readAtLeast(buf []byte, min int) (n int, err error) {
 // not change the observable concurrency.  This bounds the goroutine leak to at
 // most one per TorrentFile instead of growing without limit.
 func (f *TorrentFile) readContext(ctx context.Context, p []byte) (int, error) {
-	// Drain a pending goroutine from a previous timeout.
-	if f.pendingRead != nil {
-		select {
-		case r := <-f.pendingRead:
-			returnBuffer(r.pooled)
-			f.pendingRead = nil
-		case <-ctx.Done():
-			return 0, ctx.Err()
-		}
+	if err := f.drainPendingRead(ctx); err != nil {
+		return 0, err
 	}
 
 	start := time.Now()
@@ -1,6 +1,10 @@
 package vfs
 
-import "github.com/shapedtime/momoshtrem/internal/library"
+import (
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
 
 // EpisodeWithContext bundles episode data needed for tree operations
 type EpisodeWithContext struct {
@@ -9,6 +13,13 @@ type EpisodeWithContext struct {
 	SeasonNumber int
 	Episode      *library.Episode
 	Assignment   *library.TorrentAssignment
+
+	// Show artwork and tvshow.nfo fields, used when the episode creates the show folder (optional)
+	ShowPosterURL   string
+	ShowBackdropURL string
+	ShowCreatedAt   time.Time // Artwork modification time; the assignment time if zero
+	ShowTMDBID      int
+	ShowOverview    string
 }
 
 // TreeUpdater provides methods to perform partial updates to the VFS tree.
@@ -24,12 +35,18 @@ type TreeUpdater interface {
 	// AddEpisodesToTree adds episodes (with show/season folders as needed)
 	AddEpisodesToTree(episodes []EpisodeWithContext)
 
+	// RenameEpisodeInTree moves an episode's file and sidecars to the episode's current name
+	RenameEpisodeInTree(ep EpisodeWithContext)
+
 	// RemoveEpisodeFromTree removes an episode file (and empty parent folders)
 	RemoveEpisodeFromTree(showTitle string, showYear int, seasonNumber int, episodeNumber int)
 
+	// RemoveSeasonFromTree removes a season folder (and the show folder if it becomes empty)
+	RemoveSeasonFromTree(showTitle string, showYear int, seasonNumber int)
+
 	// RemoveShowFromTree removes an entire show subtree
 	RemoveShowFromTree(title string, year int)
 
-	// InvalidateTree forces a full tree rebuild on next access
+	// InvalidateTree schedules a full tree rebuild; rapid calls are coalesced
 	InvalidateTree()
 }
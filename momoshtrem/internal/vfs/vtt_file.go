@@ -0,0 +1,178 @@
+package vfs
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// Ensure VttSubtitleFile implements File interface
+var _ File = (*VttSubtitleFile)(nil)
+
+const (
+	// vttHeader starts every converted file. Conversion otherwise preserves
+	// length, so the converted size is known without reading the source.
+	vttHeader = "WEBVTT\n\n"
+
+	// maxVttSourceSize bounds how much of a source SRT is buffered for conversion
+	maxVttSourceSize = 10 * 1024 * 1024
+)
+
+var (
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+	// srtTimestamp matches the seconds/milliseconds of an SRT timestamp (00:01,500)
+	srtTimestamp = regexp.MustCompile(`(\d{2}:\d{2}),(\d{3})`)
+)
+
+// srtToVTT converts SRT content to WebVTT: the header is inserted after any
+// byte order mark and timestamp commas become periods. Cue numbers are valid
+// WebVTT cue identifiers and are kept.
+func srtToVTT(src []byte) []byte {
+	bom := 0
+	if bytes.HasPrefix(src, utf8BOM) {
+		bom = len(utf8BOM)
+	}
+
+	out := make([]byte, 0, len(src)+len(vttHeader))
+	out = append(out, src[:bom]...)
+	out = append(out, vttHeader...)
+	return append(out, srtTimestamp.ReplaceAll(src[bom:], []byte("$1.$2"))...)
+}
+
+// vttContent holds the converted bytes, shared by the tree entry and its open handles.
+// content is read without the lock so Stat never waits behind a conversion.
+type vttContent struct {
+	loadMu  sync.Mutex // Serializes conversion
+	content atomic.Pointer[[]byte]
+}
+
+// VttSubtitleFile presents an SRT subtitle as WebVTT, converting on first read.
+// The source is a *SubtitleFile or *TorrentSubtitleFile.
+type VttSubtitleFile struct {
+	name    string
+	source  Entry
+	shared  *vttContent
+	modTime time.Time // The source's modification time
+
+	// Set on open handles only
+	openSource func() (File, error)
+	pos        int64
+}
+
+func newVttSubtitleFile(name string, source Entry, modTime time.Time) *VttSubtitleFile {
+	return &VttSubtitleFile{
+		name:    name,
+		source:  source,
+		shared:  &vttContent{},
+		modTime: modTime,
+	}
+}
+
+// open returns a read handle that converts the source on first read
+func (f *VttSubtitleFile) open(openSource func() (File, error)) *VttSubtitleFile {
+	return &VttSubtitleFile{
+		name:       f.name,
+		source:     f.source,
+		shared:     f.shared,
+		modTime:    f.modTime,
+		openSource: openSource,
+	}
+}
+
+// bytes returns the converted content, reading the source if needed.
+// Failures are not cached so a slow torrent can be retried.
+func (f *VttSubtitleFile) bytes() ([]byte, error) {
+	if content := f.shared.content.Load(); content != nil {
+		return *content, nil
+	}
+
+	f.shared.loadMu.Lock()
+	defer f.shared.loadMu.Unlock()
+
+	if content := f.shared.content.Load(); content != nil {
+		return *content, nil
+	}
+	if f.openSource == nil {
+		return nil, os.ErrInvalid
+	}
+
+	src, err := f.openSource()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxVttSourceSize))
+	if err != nil {
+		return nil, err
+	}
+
+	content := srtToVTT(data)
+	if int64(len(content)) != f.Size() {
+		slog.Warn("Converted subtitle size differs from source size",
+			"name", f.name,
+			"expected", f.Size(),
+			"actual", len(content),
+		)
+	}
+	f.shared.content.Store(&content)
+	return content, nil
+}
+
+func (f *VttSubtitleFile) Name() string { return f.name }
+func (f *VttSubtitleFile) IsDir() bool  { return false }
+
+// Size is exact before conversion since only the header changes the length
+func (f *VttSubtitleFile) Size() int64 {
+	if content := f.shared.content.Load(); content != nil {
+		return int64(len(*content))
+	}
+	return f.source.Size() + int64(len(vttHeader))
+}
+
+func (f *VttSubtitleFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.pos)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *VttSubtitleFile) ReadAt(p []byte, off int64) (int, error) {
+	content, err := f.bytes()
+	if err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, os.ErrInvalid
+	}
+	if off >= int64(len(content)) {
+		return 0, io.EOF
+	}
+	n := copy(p, content[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *VttSubtitleFile) Close() error { return nil }
+
+func (f *VttSubtitleFile) Stat() (os.FileInfo, error) {
+	return common.NewFileInfo(f.name, f.Size(), false, f.modTime), nil
+}
+
+// ETag is derived from the source's, so it changes with the source content
+func (f *VttSubtitleFile) ETag() string {
+	t, ok := f.source.(ETagger)
+	if !ok || t.ETag() == "" {
+		return ""
+	}
+	return makeETag(t.ETag(), "vtt")
+}
@@ -0,0 +1,113 @@
+package vfs
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shapedtime/momoshtrem/internal/subtitle"
+)
+
+func TestSrtToVTT(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "timestamps",
+			src:  "1\n00:00:01,500 --> 00:00:03,000\nHello, world\n",
+			want: "WEBVTT\n\n1\n00:00:01.500 --> 00:00:03.000\nHello, world\n",
+		},
+		{
+			name: "crlf",
+			src:  "1\r\n01:02:03,004 --> 01:02:05,006\r\nLine\r\n",
+			want: "WEBVTT\n\n1\r\n01:02:03.004 --> 01:02:05.006\r\nLine\r\n",
+		},
+		{
+			name: "byte order mark kept first",
+			src:  "\xEF\xBB\xBF1\n00:00:01,000 --> 00:00:02,000\nHi\n",
+			want: "\xEF\xBB\xBFWEBVTT\n\n1\n00:00:01.000 --> 00:00:02.000\nHi\n",
+		},
+		{"empty", "", "WEBVTT\n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(srtToVTT([]byte(tt.src)))
+			if got != tt.want {
+				t.Errorf("srtToVTT() = %q, want %q", got, tt.want)
+			}
+			if len(got) != len(tt.src)+len(vttHeader) {
+				t.Errorf("converted length = %d, want source length + header = %d", len(got), len(tt.src)+len(vttHeader))
+			}
+		})
+	}
+}
+
+func TestVttSubtitleFileReadsLocalSrt(t *testing.T) {
+	const srt = "1\n00:00:01,500 --> 00:00:03,000\nHello\n"
+	localPath := filepath.Join(t.TempDir(), "en.srt")
+	if err := os.WriteFile(localPath, []byte(srt), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, _, _ := newEmptyTree()
+	dirPath := MoviesPath + "/Movie (2020)"
+	dir := NewVirtualDir("Movie (2020)")
+	tree.pathMap[dirPath] = dir
+
+	addSubtitleEntries(tree.pathMap, dir, dirPath, "Movie (2020)", []*subtitle.Subtitle{{
+		LanguageCode: "en",
+		Format:       "srt",
+		FilePath:     localPath,
+		FileSize:     int64(len(srt)),
+		Source:       subtitle.SourceOpenSubtitles,
+	}}, nil)
+
+	fs := &LibraryFS{tree: tree}
+
+	entry, ok := tree.pathMap[dirPath+"/Movie (2020).en.vtt"].(*VttSubtitleFile)
+	if !ok {
+		t.Fatalf("vtt entry missing or wrong type: %T", tree.pathMap[dirPath+"/Movie (2020).en.vtt"])
+	}
+	want := "WEBVTT\n\n1\n00:00:01.500 --> 00:00:03.000\nHello\n"
+	if entry.Size() != int64(len(want)) {
+		t.Errorf("Size() before conversion = %d, want %d", entry.Size(), len(want))
+	}
+
+	f := entry.open(fs.vttSourceOpener(context.Background(), entry.source))
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if entry.Size() != int64(len(want)) {
+		t.Errorf("Size() after conversion = %d, want %d", entry.Size(), len(want))
+	}
+}
+
+func TestRealVttBeatsConvertedInAnyOrder(t *testing.T) {
+	srt := &subtitle.Subtitle{LanguageCode: "en", Format: "srt", FilePath: "/subs/en.srt", Source: subtitle.SourceOpenSubtitles}
+	vtt := &subtitle.Subtitle{LanguageCode: "en", Format: "vtt", FilePath: "/subs/en.vtt", Source: subtitle.SourceOpenSubtitles}
+
+	for _, subs := range [][]*subtitle.Subtitle{{srt, vtt}, {vtt, srt}} {
+		tree, _, _ := newEmptyTree()
+		dirPath := MoviesPath + "/Movie (2020)"
+		dir := NewVirtualDir("Movie (2020)")
+		tree.pathMap[dirPath] = dir
+
+		addSubtitleEntries(tree.pathMap, dir, dirPath, "Movie (2020)", subs, nil)
+
+		name := "Movie (2020).en.vtt"
+		for where, entry := range map[string]Entry{"directory": dir.children[name], "path map": tree.pathMap[dirPath+"/"+name]} {
+			if sf, ok := entry.(*SubtitleFile); !ok || sf.localPath != vtt.FilePath {
+				t.Errorf("%s formats first: %s entry = %T, want the real .vtt", subs[0].Format, where, entry)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package vfs
+
+import (
+	"errors"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+)
+
+// ErrWalkLimit is returned by Walk when a subtree has more entries than allowed
+var ErrWalkLimit = errors.New("too many entries to list")
+
+// WalkEntry is a file or directory listed by Walk
+type WalkEntry struct {
+	Path string // Absolute VFS path
+	Info os.FileInfo
+	ETag string // Content ETag, "" when the entry has none
+
+	// SizeUnknown is set when Info.Size can't be known without a download,
+	// such as artwork that isn't cached yet
+	SizeUnknown bool
+}
+
+// Walk lists root and everything below it, depth first in name order, from the
+// cached tree under a single read lock. Entries are described from tree
+// metadata only, so no torrent is loaded. Returns ErrWalkLimit once more than
+// limit entries are found, and os.ErrNotExist if root is not in the tree.
+func (fs *LibraryFS) Walk(root string, limit int) ([]WalkEntry, error) {
+	fs.ensureTree()
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	root = common.CleanPath(root)
+	var entry Entry = fs.tree.root
+	if root != "/" {
+		var ok bool
+		if entry, ok = fs.tree.lookup(root); !ok {
+			return nil, os.ErrNotExist
+		}
+	}
+
+	var entries []WalkEntry
+	var walk func(p string, e Entry) error
+	walk = func(p string, e Entry) error {
+		if len(entries) >= limit {
+			return ErrWalkLimit
+		}
+		entries = append(entries, newWalkEntry(p, e))
+
+		dir, ok := e.(*VirtualDir)
+		if !ok {
+			return nil
+		}
+		names := make([]string, 0, len(dir.children))
+		for name := range dir.children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := walk(path.Join(p, name), dir.children[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(root, entry); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// newWalkEntry describes a tree entry without opening it
+func newWalkEntry(p string, e Entry) WalkEntry {
+	var info os.FileInfo
+	sizeUnknown := false
+	switch v := e.(type) {
+	case *VirtualDir:
+		info, _ = (&DirFile{dir: v}).Stat()
+	case *ArtworkFile:
+		// Report only what the store already has; never download under the lock
+		size, ok := v.cachedSize()
+		info = common.NewFileInfo(v.name, size, false, v.modTime)
+		sizeUnknown = !ok
+	case interface{ Stat() (os.FileInfo, error) }:
+		info, _ = v.Stat()
+	}
+	if info == nil {
+		info = common.NewFileInfo(e.Name(), e.Size(), e.IsDir(), time.Time{})
+	}
+
+	we := WalkEntry{Path: p, Info: info, SizeUnknown: sizeUnknown}
+	if t, ok := e.(ETagger); ok {
+		we.ETag = t.ETag()
+	}
+	return we
+}
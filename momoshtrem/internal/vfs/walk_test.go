@@ -0,0 +1,104 @@
+package vfs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/library"
+)
+
+func TestWalk(t *testing.T) {
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	tree, _, _ := newEmptyTree()
+	fs.tree = tree
+
+	fs.AddEpisodesToTree([]EpisodeWithContext{{
+		ShowTitle:    "Show",
+		ShowYear:     2020,
+		SeasonNumber: 1,
+		Episode:      &library.Episode{ID: 1, EpisodeNumber: 1, Name: "Pilot"},
+		Assignment:   &library.TorrentAssignment{ItemID: 1, InfoHash: "aaaa", FilePath: "Show.S01E01.mkv", FileSize: 1 << 30},
+	}})
+
+	entries, err := fs.Walk(TVShowsPath, 100)
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	seasonPath := TVShowsPath + "/Show (2020)/Season 01"
+	byPath := make(map[string]WalkEntry)
+	for i, e := range entries {
+		byPath[e.Path] = e
+		if i > 0 && entries[i-1].Path >= e.Path {
+			t.Errorf("entries out of order: %q before %q", entries[i-1].Path, e.Path)
+		}
+	}
+	if entries[0].Path != TVShowsPath || !entries[0].Info.IsDir() {
+		t.Errorf("first entry = %+v, want the %s folder", entries[0], TVShowsPath)
+	}
+	if !byPath[seasonPath].Info.IsDir() {
+		t.Errorf("season folder missing: %v", byPath)
+	}
+	// The video is described from its assignment; a placeholder has no torrent to load
+	video, ok := byPath[seasonPath+"/Show - S01E01 - Pilot.mkv"]
+	if !ok || video.Info.Size() != 1<<30 {
+		t.Errorf("video entry = %+v, want size %d", video, 1<<30)
+	}
+	if nfo := byPath[seasonPath+"/Show - S01E01 - Pilot.nfo"]; nfo.ETag == "" {
+		t.Error("nfo entry has no ETag")
+	}
+
+	if _, err := fs.Walk(TVShowsPath, 2); !errors.Is(err, ErrWalkLimit) {
+		t.Errorf("Walk() past the limit error = %v, want ErrWalkLimit", err)
+	}
+	if _, err := fs.Walk("/Missing", 100); err == nil {
+		t.Error("Walk() of a missing folder succeeded")
+	}
+}
+
+func TestWalkArtworkDoesNotDownload(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("image"))
+	}))
+	defer srv.Close()
+
+	fs := NewLibraryFS(nil, nil, nil, 0)
+	fs.artwork.prefetchOnce.Do(func() {}) // No background worker, so any download comes from Walk
+	tree, moviesDir, _ := newEmptyTree()
+	fs.tree = tree
+
+	folderPath := MoviesPath + "/Movie (2020)"
+	movieDir := NewVirtualDir("Movie (2020)")
+	moviesDir.children[movieDir.name] = movieDir
+	tree.pathMap[folderPath] = movieDir
+	posterURL, fanartURL := srv.URL+"/poster.jpg", srv.URL+"/fanart.jpg"
+	addArtworkToDir(tree.pathMap, movieDir, folderPath, fs.artwork, posterURL, fanartURL, time.Time{})
+	fs.artwork.keep("", fanartURL, []byte("cached fanart"))
+
+	entries, err := fs.Walk(folderPath, 100)
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	byPath := make(map[string]WalkEntry)
+	for _, e := range entries {
+		byPath[e.Path] = e
+	}
+
+	poster, ok := byPath[folderPath+"/"+posterFileName]
+	if !ok || !poster.SizeUnknown || poster.Info.Size() != 0 {
+		t.Errorf("uncached poster entry = %+v, want unknown size", poster)
+	}
+	fanart, ok := byPath[folderPath+"/"+fanartFileName]
+	if !ok || fanart.SizeUnknown || fanart.Info.Size() != int64(len("cached fanart")) {
+		t.Errorf("cached fanart entry = %+v, want size %d", fanart, len("cached fanart"))
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Walk downloaded %d images, want 0", n)
+	}
+}
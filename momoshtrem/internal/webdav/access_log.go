@@ -0,0 +1,70 @@
+package webdav
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// accessLogWriter records the status and body size of a response
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// accessLogLevel is the level a completed request is logged at. Players issue
+// a range read every few seconds during playback and clients send PROPFIND
+// bursts while browsing, so only server errors are logged above debug level.
+func accessLogLevel(status int) slog.Level {
+	if status >= http.StatusInternalServerError {
+		return slog.LevelWarn
+	}
+	return slog.LevelDebug
+}
+
+// accessLog logs every request once it completes, at accessLogLevel.
+func accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		lw := &accessLogWriter{ResponseWriter: w}
+
+		next.ServeHTTP(lw, r)
+
+		status := lw.status
+		if status == 0 {
+			status = http.StatusOK // Nothing written
+		}
+		slog.Log(r.Context(), accessLogLevel(status), "WebDAV access",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"range", r.Header.Get("Range"),
+			"status", status,
+			"bytes", lw.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	})
+}
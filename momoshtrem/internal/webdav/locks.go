@@ -0,0 +1,45 @@
+package webdav
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// readOnlyLS is a webdav.LockSystem that grants every lock without tracking it.
+// macOS Finder and some backup tools refuse to read without a lock token, and
+// since the library is read-only there is nothing for a lock to protect:
+// LOCK returns a synthetic token with the requested timeout, UNLOCK is a no-op
+// and no request is ever rejected for holding (or lacking) a lock.
+type readOnlyLS struct{}
+
+// Ensure readOnlyLS implements webdav.LockSystem
+var _ webdav.LockSystem = readOnlyLS{}
+
+// Confirm always succeeds; nothing is locked.
+func (readOnlyLS) Confirm(now time.Time, name0, name1 string, conditions ...webdav.Condition) (func(), error) {
+	return func() {}, nil
+}
+
+// Create returns a fresh token without recording the lock.
+func (readOnlyLS) Create(now time.Time, details webdav.LockDetails) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	h := hex.EncodeToString(b[:])
+	return fmt.Sprintf("opaquelocktoken:%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32]), nil
+}
+
+// Refresh extends any token by the requested duration.
+func (readOnlyLS) Refresh(now time.Time, token string, duration time.Duration) (webdav.LockDetails, error) {
+	return webdav.LockDetails{Root: "/", Duration: duration}, nil
+}
+
+// Unlock is a no-op.
+func (readOnlyLS) Unlock(now time.Time, token string) error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package webdav
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// maxInfiniteDepthEntries caps Depth: infinity PROPFIND responses. Larger
+// subtrees are refused with DAV:propfind-finite-depth, which tells clients to
+// fall back to Depth: 1 (RFC 4918 section 9.1).
+const maxInfiniteDepthEntries = 20000
+
+// infinitePropfind answers PROPFIND with Depth: infinity (or no Depth, which
+// means the same) from one walk of the cached tree. The webdav handler would
+// open every file to stat it, loading each assigned torrent along the way.
+func (s *Server) infinitePropfind(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		depth := r.Header.Get("Depth")
+		if r.Method != "PROPFIND" || (depth != "" && depth != "infinity") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s.serveInfinitePropfind(w, r)
+	})
+}
+
+func (s *Server) serveInfinitePropfind(w http.ResponseWriter, r *http.Request) {
+	req, err := readPropfind(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reqPath := strings.TrimPrefix(r.URL.Path, s.handler.Prefix)
+	entries, err := s.fs.Walk(reqPath, maxInfiniteDepthEntries)
+	switch {
+	case errors.Is(err, vfs.ErrWalkLimit):
+		slog.Info("Refused Depth: infinity PROPFIND", "path", reqPath, "limit", maxInfiniteDepthEntries)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, xml.Header+`<D:error xmlns:D="DAV:"><D:propfind-finite-depth/></D:error>`)
+		return
+	case errors.Is(err, os.ErrNotExist):
+		http.NotFound(w, r)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	if err := writeMultistatus(w, s.handler.Prefix, entries, req); err != nil {
+		slog.Debug("WebDAV PROPFIND response aborted", "path", reqPath, "error", err)
+	}
+}
+
+// propfindRequest is the body of a PROPFIND request. An empty body asks for
+// all properties.
+type propfindRequest struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	Allprop  *struct{} `xml:"DAV: allprop"`
+	Propname *struct{} `xml:"DAV: propname"`
+	Prop     *struct {
+		Names []struct {
+			XMLName xml.Name
+		} `xml:",any"`
+	} `xml:"DAV: prop"`
+}
+
+// readPropfind parses a PROPFIND body, treating an empty one as allprop
+func readPropfind(body io.Reader) (*propfindRequest, error) {
+	var req propfindRequest
+	if err := xml.NewDecoder(body).Decode(&req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &propfindRequest{}, nil
+		}
+		return nil, fmt.Errorf("invalid PROPFIND body: %w", err)
+	}
+	return &req, nil
+}
+
+// liveProps are the properties reported for every entry, in allprop order
+var liveProps = []string{"resourcetype", "displayname", "getcontentlength", "getlastmodified", "getcontenttype", "getetag"}
+
+// propValue returns the XML value of a live property of e, or false if e
+// doesn't have it (directories have no length, type or ETag, and files whose
+// size isn't known yet have no length)
+func propValue(e vfs.WalkEntry, name string) (string, bool) {
+	dir := e.Info.IsDir()
+	switch name {
+	case "resourcetype":
+		if dir {
+			return "<D:collection/>", true
+		}
+		return "", true
+	case "displayname":
+		if e.Path == "/" {
+			return "", true
+		}
+		return xmlEscape(e.Info.Name()), true
+	case "getcontentlength":
+		return fmt.Sprint(e.Info.Size()), !dir && !e.SizeUnknown
+	case "getlastmodified":
+		return e.Info.ModTime().UTC().Format(http.TimeFormat), true
+	case "getcontenttype":
+		return xmlEscape(contentType(e.Info.Name())), !dir
+	case "getetag":
+		if dir {
+			return "", false
+		}
+		etag := e.ETag
+		if etag == "" {
+			// Same fallback as the webdav handler, so Depth: 1 listings agree
+			etag = fmt.Sprintf(`"%x%x"`, e.Info.ModTime().UnixNano(), e.Info.Size())
+		}
+		return xmlEscape(etag), true
+	}
+	return "", false
+}
+
+// writeMultistatus writes a 207 body describing entries. hrefs are prefixed
+// with the base path, and collections end with a slash.
+func writeMultistatus(w io.Writer, prefix string, entries []vfs.WalkEntry, req *propfindRequest) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header + `<D:multistatus xmlns:D="DAV:">`)
+
+	for _, e := range entries {
+		href := prefix + e.Path
+		if e.Info.IsDir() && !strings.HasSuffix(href, "/") {
+			href += "/"
+		}
+		bw.WriteString("<D:response><D:href>" + xmlEscape((&url.URL{Path: href}).EscapedPath()) + "</D:href>")
+
+		var found, missing strings.Builder
+		switch {
+		case req.Propname != nil:
+			for _, name := range liveProps {
+				if _, ok := propValue(e, name); ok {
+					found.WriteString("<D:" + name + "/>")
+				}
+			}
+		case req.Prop != nil:
+			for _, n := range req.Prop.Names {
+				value, ok := "", false
+				if n.XMLName.Space == "DAV:" {
+					value, ok = propValue(e, n.XMLName.Local)
+				}
+				if !ok {
+					fmt.Fprintf(&missing, `<%s xmlns="%s"/>`, n.XMLName.Local, xmlEscape(n.XMLName.Space))
+					continue
+				}
+				found.WriteString("<D:" + n.XMLName.Local + ">" + value + "</D:" + n.XMLName.Local + ">")
+			}
+		default:
+			for _, name := range liveProps {
+				if value, ok := propValue(e, name); ok {
+					found.WriteString("<D:" + name + ">" + value + "</D:" + name + ">")
+				}
+			}
+		}
+
+		if found.Len() > 0 {
+			bw.WriteString("<D:propstat><D:prop>" + found.String() + "</D:prop><D:status>HTTP/1.1 200 OK</D:status></D:propstat>")
+		}
+		if missing.Len() > 0 {
+			bw.WriteString("<D:propstat><D:prop>" + missing.String() + "</D:prop><D:status>HTTP/1.1 404 Not Found</D:status></D:propstat>")
+		}
+		bw.WriteString("</D:response>")
+	}
+
+	bw.WriteString("</D:multistatus>")
+	return bw.Flush()
+}
+
+// xmlEscape escapes s for use as XML character data or an attribute value
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
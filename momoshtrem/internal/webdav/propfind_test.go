@@ -0,0 +1,81 @@
+package webdav
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shapedtime/momoshtrem/internal/common"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+func TestWriteMultistatus(t *testing.T) {
+	mod := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []vfs.WalkEntry{
+		{Path: "/TV Shows/Show (2020)", Info: common.NewFileInfo("Show (2020)", 0, true, mod)},
+		{Path: "/TV Shows/Show (2020)/Show - S01E01 - Pilot & Co.mkv", Info: common.NewFileInfo("Show - S01E01 - Pilot & Co.mkv", 1<<30, false, mod)},
+		{Path: "/TV Shows/Show (2020)/Show - S01E01 - Pilot & Co.en.srt", Info: common.NewFileInfo("Show - S01E01 - Pilot & Co.en.srt", 2048, false, mod), ETag: `"abc"`},
+		{Path: "/TV Shows/Show (2020)/poster.jpg", Info: common.NewFileInfo("poster.jpg", 0, false, mod), SizeUnknown: true},
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		want, avoid []string
+	}{
+		{
+			name: "allprop",
+			body: "",
+			want: []string{
+				"<D:href>/media/TV%20Shows/Show%20%282020%29/</D:href>",
+				"<D:resourcetype><D:collection/></D:resourcetype>",
+				"/Show%20-%20S01E01%20-%20Pilot%20&amp;%20Co.mkv</D:href>",
+				"<D:getcontentlength>1073741824</D:getcontentlength>",
+				"<D:getcontenttype>video/x-matroska</D:getcontenttype>",
+				"<D:getlastmodified>Wed, 01 May 2024 12:00:00 GMT</D:getlastmodified>",
+				"<D:getetag>&#34;abc&#34;</D:getetag>",
+				"<D:displayname>Show - S01E01 - Pilot &amp; Co.mkv</D:displayname>",
+			},
+		},
+		{
+			name:  "named props",
+			body:  `<?xml version="1.0"?><D:propfind xmlns:D="DAV:"><D:prop><D:getcontentlength/><x:rating xmlns:x="urn:x"/></D:prop></D:propfind>`,
+			want:  []string{"<D:getcontentlength>2048</D:getcontentlength>", `<rating xmlns="urn:x"/>`, "HTTP/1.1 404 Not Found"},
+			avoid: []string{"<D:getcontenttype>", "<D:getcontentlength>0</D:getcontentlength>"},
+		},
+		{
+			name:  "propname",
+			body:  `<propfind xmlns="DAV:"><propname/></propfind>`,
+			want:  []string{"<D:getcontentlength/>"},
+			avoid: []string{"1073741824"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := readPropfind(strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("readPropfind: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := writeMultistatus(&buf, "/media", entries, req); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("response lacks %s\n%s", want, got)
+				}
+			}
+			for _, avoid := range tt.avoid {
+				if strings.Contains(got, avoid) {
+					t.Errorf("response has %s\n%s", avoid, got)
+				}
+			}
+		})
+	}
+
+	if _, err := readPropfind(strings.NewReader("<propfind")); err == nil {
+		t.Error("readPropfind accepted a malformed body")
+	}
+}
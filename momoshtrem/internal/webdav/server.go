@@ -23,6 +23,8 @@ type Server struct {
 	fs      *vfs.LibraryFS
 	handler *webdav.Handler
 	authCfg config.WebDAVAuthConfig
+
+	accessLog bool // Log every request with its range, status, size and duration
 }
 
 // NewServer creates a new WebDAV server
@@ -32,7 +34,7 @@ func NewServer(libraryFS *vfs.LibraryFS, authCfg config.WebDAVAuthConfig) *Serve
 	s.handler = &webdav.Handler{
 		Prefix:     "",
 		FileSystem: &webdavFS{fs: libraryFS},
-		LockSystem: webdav.NewMemLS(),
+		LockSystem: readOnlyLS{}, // Class-2 locking for clients that require it; the library is read-only
 		Logger: func(r *http.Request, err error) {
 			if err != nil {
 				slog.Debug("WebDAV request",
@@ -52,9 +54,73 @@ func NewServer(libraryFS *vfs.LibraryFS, authCfg config.WebDAVAuthConfig) *Serve
 	return s
 }
 
+// SetAccessLog enables per-request access logging
+func (s *Server) SetAccessLog(enabled bool) {
+	s.accessLog = enabled
+}
+
+// SetBasePath serves the tree under a path prefix such as "/media". The prefix
+// is stripped before paths reach the filesystem and prepended to PROPFIND hrefs.
+func (s *Server) SetBasePath(basePath string) {
+	s.handler.Prefix = normalizeBasePath(basePath)
+	if s.handler.Prefix != "" {
+		slog.Info("WebDAV served under base path", "base_path", s.handler.Prefix)
+	}
+}
+
+// normalizeBasePath returns basePath with one leading and no trailing slash,
+// or "" for the root
+func normalizeBasePath(basePath string) string {
+	basePath = strings.TrimSpace(basePath)
+	if basePath == "" {
+		return ""
+	}
+	basePath = common.CleanPath(basePath)
+	if basePath == "/" {
+		return ""
+	}
+	return basePath
+}
+
 // Handler returns the HTTP handler wrapped with authentication middleware
+// and, when enabled, access logging (which also covers rejected requests)
 func (s *Server) Handler() http.Handler {
-	return NewAuthMiddleware(s.handler, s.authCfg)
+	handler := NewAuthMiddleware(s.drainGuard(s.basePathGuard(s.infinitePropfind(s.handler))), s.authCfg)
+	if s.accessLog {
+		handler = accessLog(handler)
+	}
+	return handler
+}
+
+// drainGuard answers 503 to new requests once the filesystem is draining for
+// shutdown, so clients retry against the restarted server
+func (s *Server) drainGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.fs.Draining() {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// basePathGuard answers 404 to paths outside the base path. The WebDAV handler
+// strips the prefix as a plain string, so "/mediafoo" would otherwise be
+// served as "foo".
+func (s *Server) basePathGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !underBasePath(r.URL.Path, s.handler.Prefix) {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// underBasePath reports whether p is the base path itself or below it
+func underBasePath(p, basePath string) bool {
+	return basePath == "" || p == basePath || strings.HasPrefix(p, basePath+"/")
 }
 
 // webdavFS adapts LibraryFS to webdav.FileSystem
@@ -74,7 +140,7 @@ func (wfs *webdavFS) OpenFile(ctx context.Context, name string, flag int, perm o
 
 	name = common.CleanPath(name)
 
-	file, err := wfs.fs.Open(name)
+	file, err := wfs.fs.OpenContext(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -97,13 +163,13 @@ func (wfs *webdavFS) Rename(ctx context.Context, oldName, newName string) error
 func (wfs *webdavFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
 	name = common.CleanPath(name)
 
-	file, err := wfs.fs.Open(name)
+	file, err := wfs.fs.OpenContext(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return file.Stat()
+	return statFile(file)
 }
 
 // webdavFile adapts vfs.File to webdav.File
@@ -133,28 +199,42 @@ func (f *webdavFile) Read(p []byte) (int, error) {
 	return n, err
 }
 
+// Seek moves the read position. http.ServeContent relies on it to serve Range
+// requests: it seeks to the end to learn the size, then to the range start.
+// Moving into the file is forwarded to files that can seek (torrent files),
+// so piece prioritization starts at the range before the first read.
 func (f *webdavFile) Seek(offset int64, whence int) (int64, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
 	size := f.file.Size()
 
+	var pos int64
 	switch whence {
 	case io.SeekStart:
-		f.pos = offset
+		pos = offset
 	case io.SeekCurrent:
-		f.pos += offset
+		pos = f.pos + offset
 	case io.SeekEnd:
-		f.pos = size + offset
+		pos = size + offset
+	default:
+		return f.pos, os.ErrInvalid
 	}
 
-	if f.pos < 0 {
-		f.pos = 0
+	if pos < 0 {
+		return f.pos, os.ErrInvalid
 	}
-	if f.pos > size {
-		f.pos = size
+	if pos > size {
+		pos = size
 	}
 
+	if seeker, ok := f.file.(io.Seeker); ok && pos != f.pos && pos < size {
+		if _, err := seeker.Seek(pos, io.SeekStart); err != nil {
+			return f.pos, err
+		}
+	}
+
+	f.pos = pos
 	return f.pos, nil
 }
 
@@ -174,7 +254,7 @@ func (f *webdavFile) Readdir(count int) ([]os.FileInfo, error) {
 		}
 
 		for _, file := range entries {
-			info, err := file.Stat()
+			info, err := statFile(file)
 			if err != nil {
 				continue
 			}
@@ -208,7 +288,7 @@ func (f *webdavFile) Readdir(count int) ([]os.FileInfo, error) {
 }
 
 func (f *webdavFile) Stat() (os.FileInfo, error) {
-	return f.file.Stat()
+	return statFile(f.file)
 }
 
 func (f *webdavFile) Write(p []byte) (int, error) {
@@ -220,41 +300,66 @@ func (f *webdavFile) ContentType(ctx context.Context) (string, error) {
 	if f.file.IsDir() {
 		return "text/html; charset=utf-8", nil
 	}
+	return contentType(f.file.Name()), nil
+}
 
-	// Determine content type from extension
-	ext := strings.ToLower(path.Ext(f.file.Name()))
+// contentType returns the MIME type for a file name's extension
+func contentType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
 	switch ext {
 	case ".mp4", ".m4v":
-		return "video/mp4", nil
+		return "video/mp4"
 	case ".mkv":
-		return "video/x-matroska", nil
+		return "video/x-matroska"
 	case ".avi":
-		return "video/x-msvideo", nil
+		return "video/x-msvideo"
 	case ".mov":
-		return "video/quicktime", nil
+		return "video/quicktime"
 	case ".webm":
-		return "video/webm", nil
+		return "video/webm"
 	case ".srt":
-		return "text/plain; charset=utf-8", nil
+		return "text/plain; charset=utf-8"
 	case ".ass", ".ssa":
-		return "text/plain; charset=utf-8", nil
+		return "text/plain; charset=utf-8"
 	case ".vtt":
-		return "text/vtt", nil
+		return "text/vtt"
 	case ".edl":
-		return "text/plain", nil
+		return "text/plain"
+	case ".nfo":
+		return "text/xml; charset=utf-8"
 	default:
-		return "application/octet-stream", nil
+		return "application/octet-stream"
 	}
 }
 
-// ETag returns the entity tag for the file
-func (f *webdavFile) ETag(ctx context.Context) (string, error) {
-	// Use size and name as simple ETag
-	info, err := f.Stat()
+// fileInfo carries a file's content ETag. The webdav handler looks for
+// webdav.ETager on the os.FileInfo, both for GET and PROPFIND.
+type fileInfo struct {
+	os.FileInfo
+	etag string
+}
+
+// ETag falls back to the handler's modification time and size tag when the
+// file has no content ETag
+func (fi *fileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.etag == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fi.etag, nil
+}
+
+// statFile returns file info carrying the file's content ETag, if it has one.
+// http.ServeContent answers If-None-Match and If-Modified-Since from the ETag
+// header and the modification time with 304 Not Modified.
+func statFile(file vfs.File) (os.FileInfo, error) {
+	info, err := file.Stat()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if t, ok := file.(vfs.ETagger); ok {
+		return &fileInfo{FileInfo: info, etag: t.ETag()}, nil
 	}
-	return `"` + info.Name() + "-" + common.Itoa64(info.Size()) + `"`, nil
+	return info, nil
 }
 
 // Implement DeadPropsHolder to satisfy webdav requirements
@@ -0,0 +1,267 @@
+package webdav
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	anacrolix "github.com/anacrolix/torrent"
+	"github.com/anacrolix/torrent/bencode"
+	"github.com/anacrolix/torrent/metainfo"
+	"golang.org/x/net/webdav"
+
+	"github.com/shapedtime/momoshtrem/internal/streaming"
+	"github.com/shapedtime/momoshtrem/internal/torrent"
+	"github.com/shapedtime/momoshtrem/internal/vfs"
+)
+
+// localFileHandle exposes a torrent.File as a TorrentFileHandle.
+type localFileHandle struct {
+	file *anacrolix.File
+}
+
+func (h localFileHandle) Path() string                     { return h.file.Path() }
+func (h localFileHandle) Length() int64                    { return h.file.Length() }
+func (h localFileHandle) NewReader() torrent.TorrentReader { return h.file.NewReader() }
+func (h localFileHandle) Torrent() *anacrolix.Torrent      { return h.file.Torrent() }
+func (h localFileHandle) File() *anacrolix.File            { return h.file }
+
+// seedLocalTorrent builds a single-file torrent from data and adds it to an
+// offline client whose storage already holds the complete file.
+func seedLocalTorrent(t *testing.T, data []byte) *anacrolix.File {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "episode.mkv"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	info := metainfo.Info{PieceLength: 16 * 1024}
+	if err := info.BuildFromFilePath(filepath.Join(dir, "episode.mkv")); err != nil {
+		t.Fatal(err)
+	}
+	infoBytes, err := bencode.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := anacrolix.NewDefaultClientConfig()
+	cfg.DataDir = dir
+	cfg.NoDHT = true
+	cfg.DisableTrackers = true
+	cfg.DisablePEX = true
+	cfg.NoDefaultPortForwarding = true
+	cfg.DisableTCP = true
+	cfg.DisableUTP = true
+	cfg.ListenPort = 0
+
+	client, err := anacrolix.NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	tor, err := client.AddTorrent(&metainfo.MetaInfo{InfoBytes: infoBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-tor.GotInfo()
+	if err := tor.VerifyDataContext(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if !tor.Complete().Bool() {
+		t.Fatal("local torrent data did not verify")
+	}
+
+	return tor.Files()[0]
+}
+
+func TestTorrentFileRangeRequest(t *testing.T) {
+	data := make([]byte, 200*1024)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	file := seedLocalTorrent(t, data)
+
+	tf := vfs.NewTorrentFile(
+		localFileHandle{file},
+		"episode.mkv",
+		"",
+		5*time.Second,
+		nil, nil, nil,
+		streaming.DefaultConfig(),
+		nil,
+	)
+	wf := &webdavFile{file: tf, path: "/episode.mkv"}
+	defer wf.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "episode.mkv", time.Time{}, wf)
+	}))
+	defer srv.Close()
+
+	start := int64(70_000) // Mid-file, not on a piece boundary
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusPartialContent)
+	}
+	wantRange := fmt.Sprintf("bytes %d-%d/%d", start, len(data)-1, len(data))
+	if got := resp.Header.Get("Content-Range"); got != wantRange {
+		t.Errorf("Content-Range = %q, want %q", got, wantRange)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, data[start:]) {
+		t.Errorf("body mismatch: got %d bytes, want %d bytes from offset %d", len(body), len(data)-int(start), start)
+	}
+}
+
+// etagFile is a placeholder file with a content ETag
+type etagFile struct {
+	*vfs.PlaceholderFile
+	etag string
+}
+
+func (f etagFile) ETag() string { return f.etag }
+
+func TestStatFileETag(t *testing.T) {
+	ctx := t.Context()
+
+	info, err := statFile(etagFile{vfs.NewPlaceholderFile("a.nfo", 10, nil), `"v1"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etager, ok := info.(webdav.ETager)
+	if !ok {
+		t.Fatalf("statFile() info %T does not carry the ETag", info)
+	}
+	if got, err := etager.ETag(ctx); got != `"v1"` || err != nil {
+		t.Errorf("ETag() = %q, %v; want \"v1\", nil", got, err)
+	}
+
+	// No content ETag: the handler derives one from modification time and size
+	info, err = statFile(etagFile{vfs.NewPlaceholderFile("a.nfo", 10, nil), ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := info.(webdav.ETager).ETag(ctx); err != webdav.ErrNotImplemented {
+		t.Errorf("empty ETag() error = %v, want ErrNotImplemented", err)
+	}
+}
+
+func TestWebdavFileSeek(t *testing.T) {
+	wf := &webdavFile{file: vfs.NewPlaceholderFile("a.txt", 10, nil)}
+
+	tests := []struct {
+		offset  int64
+		whence  int
+		want    int64
+		wantErr bool
+	}{
+		{0, io.SeekEnd, 10, false},
+		{4, io.SeekStart, 4, false},
+		{2, io.SeekCurrent, 6, false},
+		{-3, io.SeekEnd, 7, false},
+		{20, io.SeekStart, 10, false},
+		{-1, io.SeekStart, 10, true},
+		{0, 42, 10, true},
+	}
+
+	for _, tt := range tests {
+		got, err := wf.Seek(tt.offset, tt.whence)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Seek(%d, %d) error = %v, wantErr %v", tt.offset, tt.whence, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("Seek(%d, %d) = %d, want %d", tt.offset, tt.whence, got, tt.want)
+		}
+	}
+}
+
+func TestAccessLogWriterRecordsStatusAndBytes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	lw := &accessLogWriter{ResponseWriter: rec}
+	http.ServeContent(lw, httptest.NewRequest(http.MethodGet, "/a.mkv", nil), "a.mkv", time.Time{}, bytes.NewReader(make([]byte, 100)))
+	if lw.status != http.StatusOK || lw.bytes != 100 {
+		t.Errorf("full read: status %d, bytes %d; want 200, 100", lw.status, lw.bytes)
+	}
+
+	rec = httptest.NewRecorder()
+	lw = &accessLogWriter{ResponseWriter: rec}
+	req := httptest.NewRequest(http.MethodGet, "/a.mkv", nil)
+	req.Header.Set("Range", "bytes=10-19")
+	http.ServeContent(lw, req, "a.mkv", time.Time{}, bytes.NewReader(make([]byte, 100)))
+	if lw.status != http.StatusPartialContent || lw.bytes != 10 {
+		t.Errorf("range read: status %d, bytes %d; want 206, 10", lw.status, lw.bytes)
+	}
+}
+
+func TestAccessLogLevel(t *testing.T) {
+	for status, want := range map[int]slog.Level{
+		http.StatusOK:                  slog.LevelDebug,
+		http.StatusPartialContent:      slog.LevelDebug,
+		http.StatusNotFound:            slog.LevelDebug,
+		http.StatusInternalServerError: slog.LevelWarn,
+		http.StatusServiceUnavailable:  slog.LevelWarn,
+	} {
+		if got := accessLogLevel(status); got != want {
+			t.Errorf("accessLogLevel(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":        "",
+		"/":       "",
+		"media":   "/media",
+		"/media/": "/media",
+		"//a//b/": "/a/b",
+	} {
+		if got := normalizeBasePath(in); got != want {
+			t.Errorf("normalizeBasePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/media", true},
+		{"/media/", true},
+		{"/media/Movies/A (2020)/A (2020).mkv", true},
+		{"/mediafoo", false},
+		{"/", false},
+		{"/Movies", false},
+	}
+	for _, tt := range tests {
+		if got := underBasePath(tt.path, "/media"); got != tt.want {
+			t.Errorf("underBasePath(%q, /media) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if !underBasePath("/Movies", "") {
+		t.Error("every path is under an empty base path")
+	}
+}